@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/ratings"
+	"github.com/rxbenefits/go-hw/internal/util"
+)
+
+// Outcomes of a doctor check.
+const (
+	checkOK      = "ok"
+	checkWarn    = "warn"
+	checkFail    = "FAIL"
+	checkSkipped = "skip"
+)
+
+// doctorCheck is one line of the doctor's report, with what to do about it
+// when it did not pass.
+type doctorCheck struct {
+	name   string
+	status string
+	detail string
+	fix    string
+}
+
+// doctorReport collects the outcomes of the doctor's checks.
+type doctorReport struct {
+	checks []doctorCheck
+}
+
+func (r *doctorReport) add(name, status, detail, fix string) {
+	r.checks = append(r.checks, doctorCheck{name: name, status: status, detail: detail, fix: fix})
+}
+
+// count returns how many checks ended with status.
+func (r *doctorReport) count(status string) int {
+	n := 0
+	for _, check := range r.checks {
+		if check.status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// print writes the report, one check per line followed by its fix.
+func (r *doctorReport) print(out io.Writer) {
+	fmt.Fprintln(out, "Mockbuster doctor")
+	fmt.Fprintln(out)
+	for _, check := range r.checks {
+		fmt.Fprintf(out, "  [%-4s] %-22s %s\n", check.status, check.name, check.detail)
+		if check.fix != "" {
+			fmt.Fprintf(out, "         %-22s fix: %s\n", "", check.fix)
+		}
+	}
+	fmt.Fprintln(out)
+
+	fmt.Fprintf(out, "%d ok, %d warning(s), %d failure(s).\n",
+		r.count(checkOK), r.count(checkWarn), r.count(checkFail))
+	if r.count(checkFail) > 0 {
+		fmt.Fprintln(out, "Fix the failures above before starting the server.")
+		return
+	}
+	fmt.Fprintln(out, "The server is ready to start.")
+}
+
+// runDoctor checks the configuration, database, and cache the server would
+// start with and prints what is wrong and how to fix it. It exits non-zero
+// when the server would fail to start or serve.
+func runDoctor(config util.Config, out io.Writer) int {
+	report := &doctorReport{}
+	checkDoctorConfig(config, report)
+
+	db, err := database.InitDB(
+		database.WithDBHost(config.DBHost),
+		database.WithDBPort(config.DBPort),
+		database.WithDBUser(config.DBUser),
+		database.WithDBPassword(config.DBPassword),
+		database.WithDBName(config.DBName),
+	)
+	if err != nil {
+		report.add("database", checkFail, err.Error(),
+			"check DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, and DB_NAME, and that Postgres is running")
+		for _, name := range []string{"migrations", "schema", "extensions"} {
+			report.add(name, checkSkipped, "needs a database connection", "")
+		}
+	} else {
+		defer db.Close()
+		report.add("database", checkOK,
+			fmt.Sprintf("connected to %s:%s/%s", config.DBHost, config.DBPort, config.DBName), "")
+		checkDoctorDatabase(config, db.DB, report)
+	}
+
+	checkDoctorCache(config, report)
+
+	report.print(out)
+	if report.count(checkFail) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// checkDoctorConfig validates the settings the server checks at startup,
+// and warns about those it accepts but that leave features off.
+func checkDoctorConfig(config util.Config, report *doctorReport) {
+	pageSizes := pagination.Limits{DefaultLimit: config.DefaultPageSize, MaxLimit: config.MaxPageSize}
+	configCheck(report, "config: pagination", pageSizes.Validate(), "set DEFAULT_PAGE_SIZE and MAX_PAGE_SIZE")
+	configCheck(report, "config: compression", newCompressionOptions(config).Validate(),
+		"check the COMPRESSION_* settings")
+
+	_, err := newEmailSender(config)
+	configCheck(report, "config: email", err, "set EMAIL_BACKEND to log, smtp, or sendgrid, with its credentials")
+	_, err = newPaymentProvider(config)
+	configCheck(report, "config: payments", err, "set PAYMENT_PROVIDER to fake or stripe, with its credentials")
+
+	_, err = ratings.Parse(config.RatingMappings)
+	configCheck(report, "config: ratings", err, "set RATING_MAPPINGS to entries like UK:PG-13=12A")
+
+	switch config.SchemaCheck {
+	case "off", "warn", "fail":
+		report.add("config: schema check", checkOK, "SCHEMA_CHECK="+config.SchemaCheck, "")
+	default:
+		report.add("config: schema check", checkFail, fmt.Sprintf("unknown schema check mode %q", config.SchemaCheck),
+			"set SCHEMA_CHECK to off, warn, or fail")
+	}
+
+	if config.PIIEncryptionKey == "" {
+		report.add("config: pii", checkWarn, "PII_ENCRYPTION_KEY not set, personal data is stored unencrypted",
+			"set PII_ENCRYPTION_KEY to a base64-encoded 256-bit key")
+	} else {
+		_, err = newPIICipher(config)
+		configCheck(report, "config: pii", err, "set PII_ENCRYPTION_KEY to a base64-encoded 256-bit key")
+	}
+
+	if config.HandlerTimeout >= writeTimeout {
+		report.add("config: timeouts", checkWarn,
+			fmt.Sprintf("HANDLER_TIMEOUT %s is not below the write timeout %s", config.HandlerTimeout, writeTimeout),
+			fmt.Sprintf("set HANDLER_TIMEOUT below %s so stuck requests get a 504", writeTimeout))
+	}
+
+	if config.AdminAPIToken == "" {
+		report.add("config: admin", checkWarn, "ADMIN_API_TOKEN not set, admin routes are disabled",
+			"set ADMIN_API_TOKEN to enable them")
+	}
+}
+
+// configCheck adds an ok line for a setting that validated, or a failure
+// with fix for one that did not.
+func configCheck(report *doctorReport, name string, err error, fix string) {
+	if err != nil {
+		report.add(name, checkFail, err.Error(), fix)
+		return
+	}
+	report.add(name, checkOK, "valid", "")
+}
+
+// checkDoctorDatabase compares the database with the migrations this build
+// ships and the tables and extensions it uses.
+func checkDoctorDatabase(config util.Config, db *sql.DB, report *doctorReport) {
+	schemaReport, err := database.CheckSchema(db, "migrations", database.RequiredSchema)
+	if err != nil {
+		report.add("migrations", checkFail, err.Error(), "check the migrations directory and database permissions")
+		report.add("schema", checkSkipped, "needs the migration status", "")
+	} else {
+		checkDoctorMigrations(config, db, schemaReport, report)
+		checkDoctorSchema(schemaReport, report)
+	}
+
+	missing, err := database.MissingExtensions(db, database.RecommendedExtensions)
+	switch {
+	case err != nil:
+		report.add("extensions", checkFail, err.Error(), "")
+	case len(missing) > 0:
+		statements := make([]string, 0, len(missing))
+		for _, name := range missing {
+			statements = append(statements, "CREATE EXTENSION "+name+";")
+		}
+		report.add("extensions", checkWarn, "not installed: "+strings.Join(missing, ", "),
+			"run `"+strings.Join(statements, " ")+"` as a database owner")
+	default:
+		report.add("extensions", checkOK, "installed: "+strings.Join(database.RecommendedExtensions, ", "), "")
+	}
+}
+
+// checkDoctorMigrations reports pending migrations, which fail the check
+// unless the server applies them at startup, and a database migrated past
+// this build.
+func checkDoctorMigrations(
+	config util.Config,
+	db *sql.DB,
+	schemaReport *database.SchemaReport,
+	report *doctorReport,
+) {
+	latest, err := database.LatestMigrationVersion("migrations")
+	if err != nil {
+		report.add("migrations", checkFail, err.Error(), "run the server from the directory holding migrations/")
+		return
+	}
+	applied, err := database.AppliedVersion(context.Background(), db)
+	if err != nil {
+		report.add("migrations", checkFail, err.Error(), "")
+		return
+	}
+
+	pending := schemaReport.PendingMigrations
+	switch {
+	case applied > latest:
+		report.add("migrations", checkFail,
+			fmt.Sprintf("database is at version %d, newer than this build's %d", applied, latest),
+			"deploy a build that ships the database's migrations")
+	case len(pending) > 0:
+		names := make([]string, 0, len(pending))
+		for _, migration := range pending {
+			names = append(names, filepath.Base(migration.Source))
+		}
+		detail := fmt.Sprintf("%d pending: %s", len(pending), strings.Join(names, ", "))
+		if config.MigrationsEnabled {
+			report.add("migrations", checkWarn, detail+" (applied at startup)",
+				"preview them with `mockbuster migrate plan`")
+			return
+		}
+		report.add("migrations", checkFail, detail,
+			"run the migration job, or set MIGRATIONS_ENABLED=true; preview with `mockbuster migrate plan`")
+	default:
+		report.add("migrations", checkOK, fmt.Sprintf("up to date at version %d", applied), "")
+	}
+}
+
+// checkDoctorSchema reports tables and columns the API queries that are
+// missing. They are expected while migrations are pending.
+func checkDoctorSchema(schemaReport *database.SchemaReport, report *doctorReport) {
+	missing := append(append([]string{}, schemaReport.MissingTables...), schemaReport.MissingColumns...)
+	switch {
+	case len(missing) == 0:
+		report.add("schema", checkOK, "required tables and columns present", "")
+	case len(schemaReport.PendingMigrations) > 0:
+		report.add("schema", checkWarn, "missing until migrations run: "+strings.Join(missing, ", "), "")
+	default:
+		report.add("schema", checkFail, "missing: "+strings.Join(missing, ", "),
+			"restore them; migrations are up to date, so they were changed by hand")
+	}
+}
+
+// checkDoctorCache describes the film cache. It lives in each replica's
+// memory, so there is no cache server to reach.
+func checkDoctorCache(config util.Config, report *doctorReport) {
+	if !config.CacheEnabled {
+		report.add("cache", checkOK, "disabled (CACHE_ENABLED=false)", "")
+		return
+	}
+	report.add("cache", checkOK, fmt.Sprintf("in-memory, TTL %s; nothing to reach", config.CacheTTL), "")
+}
@@ -2,27 +2,53 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"time"
 
-	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 
 	"github.com/rxbenefits/go-hw/docs"
+	"github.com/rxbenefits/go-hw/internal/attachments"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/cache"
 	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/grpcapi"
 	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/jobs"
+	"github.com/rxbenefits/go-hw/internal/metrics"
+	"github.com/rxbenefits/go-hw/internal/middleware"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/payments"
+	"github.com/rxbenefits/go-hw/internal/pii"
+	"github.com/rxbenefits/go-hw/internal/ratings"
+	"github.com/rxbenefits/go-hw/internal/reporting"
 	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/router"
 	"github.com/rxbenefits/go-hw/internal/service"
 	"github.com/rxbenefits/go-hw/internal/util"
+	"github.com/rxbenefits/go-hw/internal/webhooks"
 )
 
 const (
 	readTimeout  = 15 * time.Second
 	writeTimeout = 15 * time.Second
 	idleTimeout  = 60 * time.Second
+
+	reportFlushTimeout = 2 * time.Second
+
+	// jobTimeout bounds each background job, such as sending one email.
+	jobTimeout = 30 * time.Second
+
+	// scheduledJobTimeout bounds each run of a scheduled job.
+	scheduledJobTimeout = 5 * time.Minute
 )
 
 // @title Mockbuster Movie API.
@@ -42,14 +68,81 @@ const (
 // @schemes http.
 
 func main() {
-	// Initialize database connection.
 	config := util.InitConfig()
+
+	// Initialize logging before anything else logs.
+	logHandler, err := util.NewLogHandler(os.Stdout, config.LogLevel, config.LogFormat)
+	if err != nil {
+		slog.Error("Invalid logging configuration", "error", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(slog.New(logHandler))
+
+	// Run a one-off command instead of serving when one is given.
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(config, os.Args[1:]))
+	}
+
+	// Initialize error reporting.
+	var reporter reporting.Reporter = reporting.NoopReporter{}
+	if config.SentryDSN != "" {
+		sentryReporter, sentryErr := reporting.NewSentryReporter(config.SentryDSN, config.SentryEnvironment)
+		if sentryErr != nil {
+			slog.Error("Failed to initialize error reporting", "error", sentryErr)
+			os.Exit(1)
+		}
+		reporter = sentryReporter
+		slog.Info("Error reporting enabled", "environment", config.SentryEnvironment)
+	}
+
+	// Page size limits shared by the film handler, service, and repository.
+	pageSizes := pagination.Limits{DefaultLimit: config.DefaultPageSize, MaxLimit: config.MaxPageSize}
+	if err = pageSizes.Validate(); err != nil {
+		slog.Error("Invalid pagination configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Response compression, applied to every route.
+	compression := newCompressionOptions(config)
+	if err = compression.Validate(); err != nil {
+		slog.Error("Invalid compression configuration", "error", err)
+		os.Exit(1)
+	}
+
+	piiCipher, err := newPIICipher(config)
+	if err != nil {
+		slog.Error("Invalid PII encryption configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Local rating systems returned alongside each film's MPAA rating.
+	ratingSystems, err := ratings.Parse(config.RatingMappings)
+	if err != nil {
+		slog.Error("Invalid rating mapping configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Networks admin routes may be called from, and the proxies trusted to
+	// report the client address.
+	adminNetworks, err := middleware.ParseNetworks(config.AdminAllowedNetworks)
+	if err != nil {
+		slog.Error("Invalid admin network configuration", "error", err)
+		os.Exit(1)
+	}
+	trustedProxies, err := middleware.ParseNetworks(config.TrustedProxies)
+	if err != nil {
+		slog.Error("Invalid trusted proxy configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Initialize database connection.
 	db, err := database.InitDB(
 		database.WithDBHost(config.DBHost),
 		database.WithDBPort(config.DBPort),
 		database.WithDBUser(config.DBUser),
 		database.WithDBPassword(config.DBPassword),
 		database.WithDBName(config.DBName),
+		database.WithSlowQueryThreshold(config.DBSlowQueryThreshold),
 	)
 	if err != nil {
 		slog.Error("Failed to connect to database", "error", err)
@@ -57,45 +150,623 @@ func main() {
 	}
 	defer db.Close()
 
+	// Initialize the cache and its invalidation bus. Writes publish events on
+	// the bus whether or not caching is enabled.
+	invalidations := cache.NewBus()
+
 	// Initialize repositories.
-	filmRepo := repository.NewFilmRepository(db)
-	commentRepo := repository.NewCommentRepository(db)
+	filmStore := repository.NewFilmRepository(db, pageSizes)
+	var filmRepo repository.FilmRepositoryInterface = filmStore
+	commentRepo := repository.NewCommentRepository(db, piiCipher)
+	staffRepo := repository.NewStaffRepository(db)
+	customerRepo := repository.NewCustomerRepository(db)
+	prefRepo := repository.NewNotificationPreferenceRepository(db)
+	rentalRepo := repository.NewRentalRepository(db)
+	jobRepo := repository.NewBackgroundJobRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	pricingRepo := repository.NewPricingRepository(db)
+	couponRepo := repository.NewCouponRepository(db)
+	cartRepo := repository.NewCartRepository(db)
+	paymentRepo := repository.NewPaymentRepository(db)
+	receiptRepo := repository.NewReceiptRepository(db)
+	loyaltyRepo := repository.NewLoyaltyRepository(db)
+	giftCardRepo := repository.NewGiftCardRepository(db)
+	collectionRepo := repository.NewCollectionRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	customerListRepo := repository.NewCustomerListRepository(db)
+	activityRepo := repository.NewActivityRepository(db)
+	shortLinkRepo := repository.NewShortLinkRepository(db)
+	dashboardRepo := repository.NewDashboardRepository(db)
+	customerExportRepo := repository.NewCustomerExportRepository(db)
+	availabilityRepo := repository.NewAvailabilityRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	savedSearchRepo := repository.NewSavedSearchRepository(db)
+	homeRepo := repository.NewHomeRepository(db)
+	guestRepo := repository.NewGuestRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db, piiCipher)
+	if config.CacheEnabled {
+		filmCache := cache.NewMemoryCache(config.CacheTTL, cache.WithMaxEntries(config.CacheMaxEntries))
+		invalidations.Subscribe(cache.Evict(filmCache))
+		filmRepo = repository.NewCachedFilmRepository(filmRepo, filmCache)
+		slog.Info("Film cache enabled", "ttl", config.CacheTTL, "maxEntries", config.CacheMaxEntries)
+	}
+
+	// Run database migrations, unless a separate job runs them.
+	if config.MigrationsEnabled {
+		if migrationErr := database.RunMigrations(db.DB, "migrations"); migrationErr != nil {
+			slog.Error("Failed to run database migrations", "error", migrationErr)
+			db.Close() //nolint:gosec // Exiting the program anyways
+			os.Exit(1) //nolint:gocritic // Running the db.Close() before os.Exit
+		}
+	} else {
+		slog.Info("Skipping database migrations on startup")
+	}
+
+	// Fail fast, rather than at query time, if the schema is behind.
+	if schemaErr := checkSchema(config, db); schemaErr != nil {
+		slog.Error("Database schema check failed", "error", schemaErr)
+		db.Close() //nolint:gosec // Exiting the program anyways
+		os.Exit(1) //nolint:gocritic // Running the db.Close() before os.Exit
+	}
 
-	// Run database migrations.
-	if migrationErr := database.RunMigrations(db.DB, "migrations"); migrationErr != nil {
-		slog.Error("Failed to run database migrations", "error", migrationErr)
+	// Readiness compares the database's schema version with the newest
+	// migration this build ships.
+	latestMigration, err := database.LatestMigrationVersion("migrations")
+	if err != nil {
+		slog.Error("Failed to read migrations", "error", err)
 		db.Close() //nolint:gosec // Exiting the program anyways
 		os.Exit(1) //nolint:gocritic // Running the db.Close() before os.Exit
 	}
+	readinessService := service.NewReadinessService(repository.NewSchemaRepository(db), latestMigration)
+
+	// Initialize background jobs and email notifications.
+	emailSender, err := newEmailSender(config)
+	if err != nil {
+		slog.Error("Invalid email configuration", "error", err)
+		db.Close() //nolint:gosec // Exiting the program anyways
+		os.Exit(1) //nolint:gocritic // Running the db.Close() before os.Exit
+	}
+	jobQueue := jobs.NewQueue(jobRepo, config.JobWorkers, config.JobPollInterval, jobTimeout)
+	prefService := service.NewNotificationPreferenceService(prefRepo)
+	notifier := notifications.NewNotifier(emailSender, jobQueue, notifications.WithPreferences(prefService))
+	jobQueue.Handle(notifications.EmailJobKind, notifier.HandleEmailJob, jobs.DefaultRetryPolicy)
+	webhookDispatcher := webhooks.NewDispatcher(webhookRepo, jobQueue,
+		webhooks.WithHTTPClient(&http.Client{Timeout: config.WebhookTimeout}))
+	jobQueue.Handle(webhooks.FanoutJobKind, webhookDispatcher.HandleFanoutJob, jobs.DefaultRetryPolicy)
+	jobQueue.Handle(webhooks.DeliverJobKind, webhookDispatcher.HandleDeliveryJob, jobs.DefaultRetryPolicy)
+	// Export downloads and receipts get signed URLs, which work without a
+	// bearer token, such as from email links, when a signing secret is set.
+	var urlSigner *auth.URLSigner
+	if config.SignedURLSecret != "" {
+		urlSigner = auth.NewURLSigner(config.SignedURLSecret, config.SignedURLTTL, config.PublicBaseURL)
+	}
+	customerExportService := service.NewCustomerExportService(customerExportRepo, jobQueue, config.CustomerExportTTL,
+		service.WithSignedExportURLs(urlSigner))
+	jobQueue.Handle(service.CustomerExportJobKind, customerExportService.HandleExportJob, jobs.DefaultRetryPolicy)
+	jobQueue.Start()
 
 	// Initialize services with dependency injection.
-	filmService := service.NewFilmService(filmRepo)
-	commentService := service.NewCommentService(commentRepo, filmRepo)
+	filmService := service.NewFilmService(filmRepo, pageSizes, service.WithRatingSystems(ratingSystems))
+	loyaltyService := service.NewLoyaltyService(loyaltyRepo, config.LoyaltyPointsPerComment, config.LoyaltyPointsPerDollar)
+	activityService := service.NewActivityService(activityRepo)
+	guestTokens := auth.NewTokenIssuer(auth.RoleGuest, config.CustomerAuthSecret, config.GuestTokenTTL)
+	guestService := service.NewGuestService(guestRepo, guestTokens)
+	commentSummaryCache := cache.NewMemoryCache(config.CommentSummaryTTL)
+	invalidations.Subscribe(cache.Evict(commentSummaryCache))
+	commentOpts := []service.CommentServiceOption{
+		service.WithReplyNotifier(notifier), service.WithMentionNotifier(notifier),
+		service.WithEventPublisher(webhookDispatcher),
+		service.WithCommentPoints(loyaltyService), service.WithCommentActivity(activityService),
+		service.WithGuestComments(guestService), service.WithCommentSummaryCache(commentSummaryCache),
+	}
+	// Comments take image attachments only when a directory is set to keep
+	// them in.
+	var attachmentStore *attachments.LocalStore
+	if config.AttachmentDir != "" {
+		attachmentStore, err = attachments.NewLocalStore(config.AttachmentDir, config.PublicBaseURL+"/media")
+		if err != nil {
+			slog.Error("Invalid attachment configuration", "error", err)
+			db.Close() //nolint:gosec // Exiting the program anyways
+			os.Exit(1) //nolint:gocritic // Running the db.Close() before os.Exit
+		}
+		var scanner attachments.Scanner
+		if config.AttachmentScanCommand != "" {
+			scanner = attachments.NewCommandScanner(config.AttachmentScanCommand)
+		}
+		commentOpts = append(commentOpts,
+			service.WithCommentAttachments(attachmentStore, scanner, config.AttachmentMaxBytes))
+	}
+	commentService := service.NewCommentService(commentRepo, filmRepo, commentOpts...)
+	// Staff and customer logins start sessions renewed with refresh tokens.
+	// Their tokens stop working once the session is revoked.
+	revocations := auth.WithRevocationList(sessionRepo)
+	staffTokens := auth.NewTokenIssuer(auth.RoleStaff, config.StaffAuthSecret, config.StaffTokenTTL, revocations)
+	customerTokens := auth.NewTokenIssuer(auth.RoleCustomer, config.CustomerAuthSecret, config.CustomerTokenTTL,
+		revocations)
+	sessionService := service.NewSessionService(sessionRepo, config.RefreshTokenTTL, staffTokens, customerTokens)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepo, config.TwoFactorIssuer)
+	staffService := service.NewStaffService(staffRepo, staffTokens, service.WithStaffSessions(sessionService),
+		service.WithStaffTwoFactor(twoFactorService))
+	customerService := service.NewCustomerService(customerRepo, customerTokens, service.WithGuestMerge(guestService),
+		service.WithCustomerSessions(sessionService))
+	calendarTokens := auth.NewTokenIssuer(auth.RoleCalendar, config.CustomerAuthSecret, config.CalendarTokenTTL)
+	availabilityService := service.NewAvailabilityService(availabilityRepo, notifier)
+	savedSearchService := service.NewSavedSearchService(savedSearchRepo, filmStore, notifier)
+	searchService := service.NewSearchService(searchRepo)
+	rentalService := service.NewRentalService(
+		rentalRepo, notifier, config.RentalReminderWindow, config.TrendingWindow, config.LateFeePerDay,
+		service.WithAvailabilityDispatcher(availabilityService))
+	homeService := service.NewHomeService(homeRepo, rentalService, config.HomeFeedBudget)
+	auditService := service.NewAuditService(auditRepo)
+	webhookService := service.NewWebhookService(webhookRepo, webhookDispatcher,
+		config.WebhookSecretGrace, config.WebhookDeliveryRetention)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	inventoryService := service.NewInventoryService(inventoryRepo)
+	pricingService := service.NewPricingService(pricingRepo)
+	collectionService := service.NewCollectionService(collectionRepo, invalidations)
+	tagService := service.NewTagService(tagRepo, invalidations)
+	filmStatusService := service.NewFilmStatusService(filmStore, invalidations,
+		service.WithFilmEventPublisher(webhookDispatcher))
+	filmUpdateService := service.NewFilmUpdateService(filmStore, invalidations)
+	customerListService := service.NewCustomerListService(customerListRepo, service.WithListActivity(activityService))
+	couponService := service.NewCouponService(couponRepo)
+	cartService := service.NewCartService(cartRepo, pricingRepo, service.WithRentalPoints(config.LoyaltyPointsPerRental))
+	paymentProvider, err := newPaymentProvider(config)
+	if err != nil {
+		slog.Error("Invalid payment configuration", "error", err)
+		db.Close() //nolint:gosec // Exiting the program anyways
+		os.Exit(1) //nolint:gocritic // Running the db.Close() before os.Exit
+	}
+	paymentService := service.NewPaymentService(paymentRepo, cartRepo, paymentProvider, config.PaymentCurrency,
+		service.WithGiftCardPayments(giftCardRepo))
+	giftCardService := service.NewGiftCardService(giftCardRepo, paymentProvider, config.PaymentCurrency)
+	receiptService := service.NewReceiptService(receiptRepo, service.WithSignedReceiptURLs(urlSigner))
+	shortLinkService := service.NewShortLinkService(shortLinkRepo, config.ShortLinkTarget)
+	feedService := service.NewFeedService(filmRepo, filmStore, commentService, config.PublicBaseURL)
+	calendarService := service.NewCalendarService(rentalRepo, calendarTokens, config.PublicBaseURL)
+	sitemapCache := cache.NewMemoryCache(config.SitemapRefreshInterval)
+	invalidations.Subscribe(cache.Evict(sitemapCache))
+	sitemapService := service.NewSitemapService(filmStore, sitemapCache, config.PublicBaseURL, config.SitemapFilmURL)
+	categoryStatsCache := cache.NewMemoryCache(config.CategoryStatsTTL)
+	invalidations.Subscribe(cache.Evict(categoryStatsCache))
+	dashboardService := service.NewDashboardService(dashboardRepo, metrics.HTTPErrorRate,
+		service.WithCategoryStatsCache(categoryStatsCache))
+	backgroundJobService := service.NewBackgroundJobService(jobRepo, config.JobRetention)
+
+	// Run periodic rental jobs; replicas share leases through the database.
+	if config.SchedulerEnabled {
+		scheduler := jobs.NewScheduler(jobs.NewPostgresLeaser(db), scheduledJobTimeout)
+		scheduler.Register(jobs.ScheduledJob{
+			Name: "mark-overdue-rentals", Interval: config.JobMarkOverdueInterval, Run: rentalService.MarkOverdueRentals,
+		})
+		scheduler.Register(jobs.ScheduledJob{
+			Name: "send-due-reminders", Interval: config.JobDueRemindersInterval, Run: rentalService.SendDueReminders,
+		})
+		scheduler.Register(jobs.ScheduledJob{
+			Name: "refresh-trending", Interval: config.JobRefreshTrendingInterval, Run: rentalService.RefreshTrendingFilms,
+		})
+		scheduler.Register(jobs.ScheduledJob{
+			Name:     "purge-webhook-deliveries",
+			Interval: config.JobPurgeWebhookDeliveriesInterval,
+			Run:      webhookService.PurgeDeliveries,
+		})
+		scheduler.Register(jobs.ScheduledJob{
+			Name: "purge-background-jobs", Interval: config.JobPurgeJobsInterval, Run: backgroundJobService.PurgeJobs,
+		})
+		scheduler.Register(jobs.ScheduledJob{
+			Name:     "publish-scheduled-films",
+			Interval: config.JobPublishFilmsInterval,
+			Run:      filmStatusService.PublishScheduledFilms,
+		})
+		scheduler.Register(jobs.ScheduledJob{
+			Name:     "notify-saved-searches",
+			Interval: config.JobSavedSearchAlertsInterval,
+			Run:      savedSearchService.NotifyNewMatches,
+		})
+		scheduler.Register(jobs.ScheduledJob{
+			Name:     "refresh-comment-summaries",
+			Interval: config.JobCommentSummariesInterval,
+			Run:      commentService.RefreshCommentSummaries,
+		})
+		scheduler.Start(context.Background())
+	}
+
+	// Catalog exports read the database directly rather than through the film
+	// cache.
+	filmExportService := service.NewFilmExportService(filmStore)
+
+	// Serve the internal gRPC API, only when a token is configured.
+	if config.GRPCAPIToken != "" {
+		grpcServer := grpcapi.NewServer(config.GRPCAPIToken, filmExportService)
+		go func() {
+			listener, listenErr := net.Listen("tcp", ":"+config.GRPCPort)
+			if listenErr != nil {
+				slog.Error("Failed to listen for gRPC", "port", config.GRPCPort, "error", listenErr)
+				return
+			}
+			slog.Info("Starting gRPC server", "port", config.GRPCPort)
+			if grpcErr := grpcServer.Serve(listener); grpcErr != nil {
+				slog.Error("gRPC server stopped", "error", grpcErr)
+			}
+		}()
+	} else {
+		slog.Warn("GRPC_API_TOKEN not set, gRPC API is disabled")
+	}
+
+	// Warm the cache in the background so startup is not delayed.
+	if config.CacheEnabled && config.CacheWarmPages > 0 {
+		go func() {
+			warmErr := service.WarmFilmCache(context.Background(), filmService, config.CacheWarmPages,
+				pageSizes.DefaultLimit)
+			if warmErr != nil {
+				slog.Warn("Failed to warm film cache", "error", warmErr)
+			}
+		}()
+	}
+
+	// Encrypt names stored before encryption was enabled, in the background.
+	if piiCipher.Enabled() {
+		go func() {
+			encrypted, encryptErr := commentRepo.EncryptCommentNames(context.Background())
+			if encryptErr != nil {
+				slog.Error("Failed to encrypt stored customer names", "error", encryptErr)
+			} else if encrypted > 0 {
+				slog.Info("Encrypted stored customer names", "count", encrypted)
+			}
+		}()
+	}
 
 	// Initialize handlers with services.
-	filmHandler := handlers.NewFilmHandler(filmService, commentService)
+	filmHandler := handlers.NewFilmHandler(filmService, commentService, pageSizes,
+		handlers.WithTitleSuggestions(searchService),
+		handlers.WithMaxAttachmentBytes(int64(config.AttachmentMaxBytes)))
+	searchHandler := handlers.NewSearchHandler(searchService)
+	maintenance := middleware.NewMaintenanceMode(config.MaintenanceMode)
+	if config.MaintenanceMode {
+		slog.Warn("Starting in maintenance mode, write endpoints are disabled")
+	}
+	adminHandler := handlers.NewAdminHandler(invalidations, maintenance)
+	staffHandler := handlers.NewStaffHandler(staffService)
+	customerHandler := handlers.NewCustomerHandler(customerService)
+	prefHandler := handlers.NewNotificationPreferenceHandler(prefService)
+	rentalHandler := handlers.NewRentalHandler(rentalService)
+	availabilityHandler := handlers.NewAvailabilityHandler(availabilityService)
+	savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchService)
+	homeHandler := handlers.NewHomeHandler(homeService)
+	guestHandler := handlers.NewGuestHandler(guestService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+	jobHandler := handlers.NewBackgroundJobHandler(backgroundJobService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+	pricingHandler := handlers.NewPricingHandler(pricingService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	filmStatusHandler := handlers.NewFilmStatusHandler(filmStatusService)
+	filmUpdateHandler := handlers.NewFilmUpdateHandler(filmUpdateService)
+	customerListHandler := handlers.NewCustomerListHandler(customerListService)
+	activityHandler := handlers.NewActivityHandler(activityService)
+	couponHandler := handlers.NewCouponHandler(couponService)
+	cartHandler := handlers.NewCartHandler(cartService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+	loyaltyHandler := handlers.NewLoyaltyHandler(loyaltyService)
+	giftCardHandler := handlers.NewGiftCardHandler(giftCardService)
+	shortLinkHandler := handlers.NewShortLinkHandler(shortLinkService)
+	feedHandler := handlers.NewFeedHandler(feedService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	sitemapHandler := handlers.NewSitemapHandler(sitemapService)
+	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+	customerExportHandler := handlers.NewCustomerExportHandler(customerExportService)
+	filmExportHandler := handlers.NewFilmExportHandler(filmExportService)
+	readinessHandler := handlers.NewReadinessHandler(readinessService)
 
 	// Initialize router.
-	r := mux.NewRouter()
+	// Answer unknown routes and unsupported methods with JSON errors.
+	r := router.New(
+		router.WithNotFound(http.HandlerFunc(handlers.NotFound)),
+		router.WithMethodNotAllowed(http.HandlerFunc(handlers.MethodNotAllowed)),
+	)
+
+	// Recover panics and report server errors for every route.
+	r.Use(middleware.ReportErrors(reporter))
 
 	// API routes.
-	api := r.PathPrefix("/api/v1").Subrouter()
-	api.HandleFunc("", handlers.APIInfoHandler).Methods("GET")
+	api := r.Group("/api/v1")
+	api.HandleFunc("GET", handlers.APIInfoHandler)
 
-	// Film routes.
-	api.HandleFunc("/films", filmHandler.GetFilms).Methods("GET")
-	api.HandleFunc("/films/{id}", filmHandler.GetFilmByID).Methods("GET")
-	api.HandleFunc("/categories", filmHandler.GetCategories).Methods("GET")
+	// Answer 504 when a handler is stuck, before the write timeout drops the
+	// connection without a response.
+	if config.HandlerTimeout >= writeTimeout {
+		slog.Warn("HANDLER_TIMEOUT is not below the server write timeout, stuck requests will be dropped",
+			"handler_timeout", config.HandlerTimeout, "write_timeout", writeTimeout)
+	}
+	api.Use(middleware.Timeout(config.HandlerTimeout))
 
-	// Comment routes.
-	api.HandleFunc("/films/{id}/comments", filmHandler.AddComment).Methods("POST")
-	api.HandleFunc("/films/{id}/comments", filmHandler.GetComments).Methods("GET")
+	// Enforce the rate limit and monthly quota of the caller's API key tier.
+	// Requests without a key are served anonymously.
+	api.Use(middleware.APIKeyAuth(apiKeyService, middleware.NewRateLimiter()))
+	api.HandleFunc("GET /usage", apiKeyHandler.GetUsage)
+
+	// Reject writes during maintenance, except the admin routes that end it.
+	api.Use(middleware.ReadOnly(maintenance, "/api/v1/admin", "/api/v1/staff/login"))
+
+	// Scope requests to a store via X-Store-ID or the /stores/{storeID} prefix.
+	api.Use(middleware.StoreScope)
+	// Customer tokens are optional on film routes and mark comments verified.
+	// Guest tokens are accepted too, within the guest rate limit.
+	customerAuth := router.Middleware(func(next http.Handler) http.Handler { return next })
+	guestLimit := middleware.GuestRateLimit(middleware.NewRateLimiter(), config.GuestRequestsPerMinute)
+	if config.CustomerAuthSecret != "" {
+		optionalToken := middleware.OptionalToken(customerTokens, guestTokens)
+		customerAuth = func(next http.Handler) http.Handler { return optionalToken(guestLimit(next)) }
+	}
+	// Cache-Control by route class: shared caches may keep catalog reads,
+	// must revalidate comments, and never store admin responses.
+	caching := routeCaching{
+		catalog:  middleware.CacheControl(config.CacheControlCatalog),
+		comments: middleware.CacheControl(config.CacheControlComments),
+		admin:    middleware.CacheControl(config.CacheControlAdmin),
+		stale:    func(next http.Handler) http.Handler { return next },
+	}
+	// Public catalog and comment reads fall back to their last good response
+	// when the database is down. Stored responses are keyed by URL, so routes
+	// whose response depends on the caller never get this fallback.
+	if config.StaleFallbackEnabled {
+		staleStore := cache.NewMemoryCache(config.StaleMaxAge, cache.WithMaxEntries(config.StaleMaxEntries))
+		caching.stale = middleware.ServeStale(staleStore)
+	}
+	// Trending films are ranked across all stores, so are not store scoped.
+	// The literal path takes precedence over /films/{id}.
+	api.HandleFunc("GET /films/trending", rentalHandler.GetTrendingFilms, caching.stale, caching.catalog)
+	// Prices apply store rules for the customer_id's store, else the scoped store.
+	api.HandleFunc("GET /films/{id}/price", pricingHandler.GetFilmPrice, caching.stale, caching.catalog)
+	api.HandleFunc("GET /collections/{id}", collectionHandler.GetCollection, caching.stale, caching.catalog)
+	api.HandleFunc("GET /tags", tagHandler.ListTags, caching.stale, caching.catalog)
+	// Private lists are shown to their owner, so a customer token is accepted.
+	api.HandleFunc("GET /lists/{id}", customerListHandler.GetSharedList, customerAuth)
+	registerFilmRoutes(api, filmHandler, customerAuth, caching)
+	registerFilmRoutes(api.Group("/stores/{"+middleware.StoreIDVar+"}"), filmHandler, customerAuth, caching)
+
+	// When staff two-factor authentication is required, staff tokens from
+	// logins without a second factor only reach two-factor enrollment.
+	var twoFactorRoles []string
+	if config.StaffRequireTwoFactor {
+		twoFactorRoles = append(twoFactorRoles, auth.RoleStaff)
+	}
+	requireTwoFactor := middleware.RequireTwoFactor(twoFactorRoles...)
+
+	// Customer routes, only exposed when a customer token secret is configured.
+	if config.CustomerAuthSecret != "" {
+		// Registering with a guest token takes over that guest's session.
+		api.HandleFunc("POST /customers/register", customerHandler.Register, middleware.OptionalToken(guestTokens))
+		api.HandleFunc("POST /guest-sessions", guestHandler.StartSession)
+		guest := api.Group("/guest", middleware.RequireToken(guestTokens), guestLimit)
+		guest.HandleFunc("GET /watchlist", guestHandler.GetWatchlist)
+		guest.HandleFunc("PUT /watchlist/{filmID}", guestHandler.AddToWatchlist)
+		guest.HandleFunc("DELETE /watchlist/{filmID}", guestHandler.RemoveFromWatchlist)
+		r.HandleFunc("POST /auth/customer/login", customerHandler.Login)
+
+		// Calendar apps cannot send headers, so the rental calendar takes a
+		// calendar token in its URL.
+		api.HandleFunc("GET /customers/{id}/rentals.ics", calendarHandler.GetRentalCalendar,
+			middleware.RequireQueryToken("token", calendarTokens), middleware.RequireSubject("id"))
+
+		// Rental history, invoices, and receipts are also open to support
+		// staff, when staff tokens are enabled, unlike the customer routes,
+		// which admit only the customer.
+		rentalIssuers := []*auth.TokenIssuer{customerTokens}
+		if config.StaffAuthSecret != "" {
+			rentalIssuers = append(rentalIssuers, staffTokens)
+		}
+		customerOrStaff := []router.Middleware{
+			middleware.RequireToken(rentalIssuers...), requireTwoFactor, middleware.RequireSubject("id", auth.RoleStaff),
+		}
+		api.HandleFunc("GET /customers/{id}/rentals", rentalHandler.GetCustomerRentals, customerOrStaff...)
+		api.HandleFunc("GET /customers/{id}/invoices", receiptHandler.GetCustomerInvoices, customerOrStaff...)
+		// Support staff also erase data on a customer's behalf.
+		api.HandleFunc("DELETE /customers/{id}/data", customerHandler.EraseData, customerOrStaff...)
+		// Receipts check that a customer's token is for the rental's customer.
+		// Signed receipt URLs need no token.
+		api.HandleFunc("GET /rentals/{id}/receipt", receiptHandler.GetReceipt,
+			middleware.SignedURLOr(urlSigner, middleware.RequireToken(rentalIssuers...), requireTwoFactor))
+		// So do signed export download URLs.
+		api.HandleFunc("GET /customers/{id}/export", customerExportHandler.GetExport,
+			middleware.SignedURLOr(urlSigner, middleware.RequireToken(customerTokens), middleware.RequireSubject("id")))
+
+		// Customer-owned resources, reachable only with that customer's token.
+		customer := api.Group("/customers/{id}")
+		customer.Use(middleware.RequireToken(customerTokens), middleware.RequireSubject("id"))
+		customer.HandleFunc("GET /notification-preferences", prefHandler.GetPreferences)
+		customer.HandleFunc("PUT /notification-preferences", prefHandler.UpdatePreferences)
+		customer.HandleFunc("POST /coupons/{code}/validate", couponHandler.ValidateCoupon)
+		customer.HandleFunc("POST /coupons/{code}/redeem", couponHandler.RedeemCoupon)
+		customer.HandleFunc("GET /cart", cartHandler.GetCart)
+		customer.HandleFunc("POST /cart/items", cartHandler.AddItem)
+		customer.HandleFunc("DELETE /cart/items/{filmID}", cartHandler.RemoveItem)
+		customer.HandleFunc("POST /checkout", cartHandler.Checkout)
+		customer.HandleFunc("POST /checkouts/{checkoutID}/payment", paymentHandler.StartPayment)
+		customer.HandleFunc("GET /loyalty", loyaltyHandler.GetBalance)
+		customer.HandleFunc("GET /loyalty/history", loyaltyHandler.GetHistory)
+		customer.HandleFunc("POST /loyalty/redeem", loyaltyHandler.RedeemPoints)
+		customer.HandleFunc("POST /gift-cards", giftCardHandler.PurchaseGiftCard)
+		customer.HandleFunc("POST /gift-cards/balance", giftCardHandler.CheckBalance)
+		customer.HandleFunc("GET /lists", customerListHandler.ListCustomerLists)
+		customer.HandleFunc("POST /lists", customerListHandler.CreateList)
+		customer.HandleFunc("GET /lists/{listID}", customerListHandler.GetCustomerList)
+		customer.HandleFunc("PUT /lists/{listID}", customerListHandler.UpdateList)
+		customer.HandleFunc("DELETE /lists/{listID}", customerListHandler.DeleteList)
+		customer.HandleFunc("POST /lists/{listID}/entries", customerListHandler.AddListEntry)
+		customer.HandleFunc("PUT /lists/{listID}/entries", customerListHandler.ReorderListEntries)
+		customer.HandleFunc("DELETE /lists/{listID}/entries/{filmID}", customerListHandler.RemoveListEntry)
+		customer.HandleFunc("GET /saved-searches", savedSearchHandler.ListSavedSearches)
+		customer.HandleFunc("POST /saved-searches", savedSearchHandler.CreateSavedSearch)
+		customer.HandleFunc("DELETE /saved-searches/{searchID}", savedSearchHandler.DeleteSavedSearch)
+		customer.HandleFunc("GET /home", homeHandler.GetHome)
+		customer.HandleFunc("GET /sessions", sessionHandler.ListCustomerSessions)
+		customer.HandleFunc("DELETE /sessions/{sessionID}", sessionHandler.RevokeCustomerSession)
+		customer.HandleFunc("POST /calendar-token", calendarHandler.IssueCalendarURL)
+		customer.HandleFunc("GET /following", activityHandler.ListFollowing)
+		customer.HandleFunc("PUT /following/{followedID}", activityHandler.Follow)
+		customer.HandleFunc("DELETE /following/{followedID}", activityHandler.Unfollow)
+		customer.HandleFunc("GET /exports/{exportID}", customerExportHandler.GetExportStatus)
+
+		// The feed and availability alerts are the token's customer's, so
+		// need no customer ID.
+		api.HandleFunc("GET /feed", activityHandler.GetFeed, middleware.RequireToken(customerTokens))
+		api.HandleFunc("POST /films/{id}/notify-me", availabilityHandler.NotifyMe,
+			middleware.RequireToken(customerTokens))
+	} else {
+		slog.Warn("CUSTOMER_AUTH_SECRET not set, customer registration and login are disabled")
+	}
+
+	// Payment provider callbacks, authenticated by the provider's signature.
+	api.HandleFunc("POST /payments/webhook", paymentHandler.PaymentWebhook)
+
+	// Staff routes, only exposed when a staff token secret is configured.
+	// Staff tokens are signed separately from any customer credentials.
+	if config.StaffAuthSecret != "" {
+		api.HandleFunc("POST /staff/login", staffHandler.Login)
+		staff := api.Group("/staff")
+		staff.Use(middleware.RequireToken(staffTokens), requireTwoFactor)
+		staff.HandleFunc("GET", staffHandler.ListStaff)
+		// Staff check returned copies back in at the counter.
+		api.HandleFunc("POST /rentals/{id}/return", rentalHandler.ReturnRental,
+			middleware.RequireToken(staffTokens), requireTwoFactor)
+		// Staff enroll in two-factor authentication for themselves, before
+		// they can pass it.
+		ownStaff := []router.Middleware{middleware.RequireToken(staffTokens), middleware.RequireSubject("id")}
+		api.HandleFunc("POST /staff/{id}/two-factor", twoFactorHandler.Enroll, ownStaff...)
+		api.HandleFunc("POST /staff/{id}/two-factor/confirm", twoFactorHandler.Confirm, ownStaff...)
+	} else {
+		slog.Warn("STAFF_AUTH_SECRET not set, staff routes are disabled")
+	}
+
+	// Session renewal and logout, for staff and customers alike.
+	var sessionIssuers []*auth.TokenIssuer
+	if config.StaffAuthSecret != "" {
+		sessionIssuers = append(sessionIssuers, staffTokens)
+	}
+	if config.CustomerAuthSecret != "" {
+		sessionIssuers = append(sessionIssuers, customerTokens)
+	}
+	if len(sessionIssuers) > 0 {
+		r.HandleFunc("POST /auth/refresh", sessionHandler.Refresh)
+		r.HandleFunc("POST /auth/logout", sessionHandler.Logout, middleware.RequireToken(sessionIssuers...))
+	}
+
+	// Admin routes, only exposed when an admin token is configured, and only
+	// to callers from the allowed networks when those are set.
+	if config.AdminAPIToken != "" {
+		admin := api.Group("/admin")
+		admin.Use(middleware.AllowNetworks(adminNetworks, trustedProxies),
+			middleware.RequireAdminToken(config.AdminAPIToken), caching.admin)
+		admin.HandleFunc("GET /dashboard", dashboardHandler.GetDashboard)
+		admin.HandleFunc("GET /categories/{id}/stats", dashboardHandler.GetCategoryStats)
+		admin.HandleFunc("GET /search/zero-results", searchHandler.ListZeroResults)
+		admin.HandleFunc("POST /cache/purge", adminHandler.PurgeCache)
+		admin.HandleFunc("GET /maintenance", adminHandler.GetMaintenance)
+		admin.HandleFunc("PUT /maintenance", adminHandler.SetMaintenance)
+		admin.HandleFunc("GET /jobs", jobHandler.ListJobs)
+		admin.HandleFunc("GET /jobs/{id}", jobHandler.GetJob)
+		admin.HandleFunc("POST /jobs/{id}/retry", jobHandler.RetryJob)
+		admin.HandleFunc("GET /webhooks", webhookHandler.ListSubscriptions)
+		admin.HandleFunc("POST /webhooks", webhookHandler.CreateSubscription)
+		admin.HandleFunc("DELETE /webhooks/{id}", webhookHandler.DeleteSubscription)
+		admin.HandleFunc("POST /webhooks/{id}/rotate-secret", webhookHandler.RotateSecret)
+		admin.HandleFunc("GET /webhooks/{id}/deliveries", webhookHandler.ListDeliveries)
+		admin.HandleFunc("POST /webhooks/{id}/deliveries/{deliveryID}/replay", webhookHandler.ReplayDelivery)
+		admin.HandleFunc("GET /rentals/overdue", rentalHandler.GetOverdueReport)
+		admin.HandleFunc("GET /films", filmHandler.GetAdminFilms)
+		admin.HandleFunc("GET /films/{id}", filmHandler.GetAdminFilm)
+		admin.HandleFunc("PATCH /films/{id}", filmUpdateHandler.UpdateFilm)
+		admin.HandleFunc("POST /films/bulk-price", filmUpdateHandler.BulkUpdatePrice)
+		admin.HandleFunc("PUT /films/{id}/status", filmStatusHandler.SetFilmStatus)
+		admin.HandleFunc("PUT /films/{id}/schedule", filmStatusHandler.ScheduleFilmPublish)
+		admin.HandleFunc("POST /films/{id}/archive", filmStatusHandler.ArchiveFilm)
+		admin.HandleFunc("POST /films/{id}/restore", filmStatusHandler.RestoreFilm)
+		admin.HandleFunc("PUT /films/{id}/comments:lock", filmHandler.LockComments)
+		admin.HandleFunc("GET /films/{id}/inventory", inventoryHandler.ListFilmInventory)
+		admin.HandleFunc("POST /films/{id}/inventory", inventoryHandler.AddInventory)
+		admin.HandleFunc("POST /films/{id}/tags", tagHandler.TagFilm)
+		admin.HandleFunc("DELETE /films/{id}/tags/{tag}", tagHandler.UntagFilm)
+		admin.HandleFunc("GET /inventory/{id}", inventoryHandler.GetInventoryCopy)
+		admin.HandleFunc("DELETE /inventory/{id}", inventoryHandler.RetireInventory)
+		admin.HandleFunc("POST /inventory/{id}/transfer", inventoryHandler.TransferInventory)
+		admin.HandleFunc("GET /inventory/{id}/transfers", inventoryHandler.ListInventoryTransfers)
+		admin.HandleFunc("GET /pricing-rules", pricingHandler.ListRules)
+		admin.HandleFunc("POST /pricing-rules", pricingHandler.CreateRule)
+		admin.HandleFunc("DELETE /pricing-rules/{id}", pricingHandler.DeleteRule)
+		admin.HandleFunc("GET /collections", collectionHandler.ListCollections)
+		admin.HandleFunc("POST /collections", collectionHandler.CreateCollection)
+		admin.HandleFunc("GET /collections/{id}", collectionHandler.GetCollection)
+		admin.HandleFunc("PUT /collections/{id}", collectionHandler.UpdateCollection)
+		admin.HandleFunc("DELETE /collections/{id}", collectionHandler.DeleteCollection)
+		admin.HandleFunc("GET /coupons", couponHandler.ListCoupons)
+		admin.HandleFunc("POST /coupons", couponHandler.CreateCoupon)
+		admin.HandleFunc("POST /payments/{id}/refund", paymentHandler.RefundPayment)
+		admin.HandleFunc("POST /customers/{id}/merge", customerHandler.MergeCustomers)
+		admin.HandleFunc("PUT /customers/{id}/shadow-ban", filmHandler.ShadowBanCustomer)
+		admin.HandleFunc("GET /staff/{id}/activity", auditHandler.GetStaffActivity)
+		// Any staff token could otherwise mint or lock out staff accounts.
+		admin.HandleFunc("POST /staff", staffHandler.CreateStaff)
+		admin.HandleFunc("POST /staff/{id}/deactivate", staffHandler.DeactivateStaff)
+		admin.HandleFunc("GET /api-keys", apiKeyHandler.ListKeys)
+		admin.HandleFunc("POST /api-keys", apiKeyHandler.CreateKey)
+		admin.HandleFunc("POST /api-keys/{id}/revoke", apiKeyHandler.RevokeKey)
+
+		// Film rental history sits beside the film routes but needs the admin
+		// token. X-Store-ID narrows it to one store.
+		adminOnly := []router.Middleware{
+			middleware.AllowNetworks(adminNetworks, trustedProxies),
+			middleware.RequireAdminToken(config.AdminAPIToken), caching.admin,
+		}
+		api.HandleFunc("GET /films/{id}/rentals", rentalHandler.GetFilmRentals, adminOnly...)
+		// Short links are made by marketing beside the film routes too.
+		api.HandleFunc("POST /films/{id}/shortlink", shortLinkHandler.CreateShortLink, adminOnly...)
+		api.HandleFunc("GET /films/{id}/shortlinks", shortLinkHandler.ListFilmShortLinks, adminOnly...)
+
+		// The catalog export streams outside /api/v1, whose timeout and stale
+		// fallback would hold the whole export in memory.
+		r.HandleFunc("GET /exports/films.ndjson", filmExportHandler.ExportFilms, adminOnly...)
+	} else {
+		slog.Warn("ADMIN_API_TOKEN not set, admin routes are disabled")
+	}
 
 	// Welcome route.
-	r.HandleFunc("/", handlers.WelcomeHandler).Methods("GET")
+	r.HandleFunc("GET /{$}", handlers.WelcomeHandler)
+
+	// Readiness probe: not ready while the schema differs from this build's,
+	// so a rolling deploy drains old replicas once the new schema lands.
+	r.HandleFunc("GET /readyz", readinessHandler.Ready)
+
+	// Short links redirect to their film and count the click.
+	r.HandleFunc("GET /f/{code}", shortLinkHandler.FollowShortLink)
+
+	// Atom feeds of new films and of each film's comments.
+	r.HandleFunc("GET /feeds/films.atom", feedHandler.GetFilmsFeed)
+	r.HandleFunc("GET /feeds/films/{id}/comments.atom", feedHandler.GetFilmCommentsFeed)
+
+	// Images attached to comments, and their thumbnails.
+	if attachmentStore != nil {
+		r.Handle("GET /media/{key}", attachmentStore.Handler())
+	}
+
+	// Sitemaps of the public catalog for search engines.
+	r.HandleFunc("GET /sitemap.xml", sitemapHandler.GetSitemapIndex)
+	r.HandleFunc("GET /sitemaps/{file}", sitemapHandler.GetFilmSitemap)
+
+	// Prometheus metrics.
+	r.Handle("GET /metrics", metrics.Handler())
 
 	// Swagger documentation.
-	r.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
+	r.Handle("GET /swagger/", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/swagger/doc.json"),
 		httpSwagger.DeepLinking(true),
 		httpSwagger.DocExpansion("none"),
@@ -117,8 +788,18 @@ func main() {
 		AllowedHeaders: []string{"*"},
 	})
 
-	// Apply CORS middleware.
-	handler := c.Handler(r)
+	// Treat "/api/v1/films/" like "/api/v1/films", then apply CORS middleware
+	// and compression, shed load beyond the concurrency limit, then response
+	// counting, access logging, and request IDs around it so shed requests are
+	// still counted and logged. The client address trusted proxies report is
+	// resolved first, for the login sessions that record it.
+	handler := c.Handler(middleware.NormalizePath(r)(r))
+	handler = middleware.Compress(compression)(handler)
+	handler = middleware.LimitConcurrency(config.MaxInFlightRequests, config.LoadShedQueueTimeout)(handler)
+	handler = middleware.CountResponses(handler)
+	handler = middleware.AccessLog(config.AccessLogSkipHealthChecks)(handler)
+	handler = middleware.RequestID(handler)
+	handler = middleware.TrustedClient(trustedProxies)(handler)
 
 	// Get port from environment or use default.
 	port := os.Getenv("PORT")
@@ -141,6 +822,7 @@ func main() {
 
 	if serveErr := server.ListenAndServe(); serveErr != nil {
 		slog.Error("Failed to start server", "error", serveErr)
+		reporter.Flush(reportFlushTimeout)
 		err = db.Close()
 		if err != nil {
 			slog.Error("Failed to close database connection", "error", err)
@@ -148,3 +830,122 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// registerFilmRoutes registers the film and comment routes on r, with
+// customerAuth applied to the routes that accept a customer token.
+func registerFilmRoutes(
+	r router.Router,
+	filmHandler *handlers.FilmHandler,
+	customerAuth router.Middleware,
+	caching routeCaching,
+) {
+	// Film routes.
+	r.HandleFunc("GET /films", filmHandler.GetFilms, caching.stale, caching.catalog)
+	r.HandleFunc("GET /films/{id}", filmHandler.GetFilmByID, caching.stale, caching.catalog)
+	r.HandleFunc("GET /categories", filmHandler.GetCategories, caching.stale, caching.catalog)
+
+	// Comment routes.
+	r.HandleFunc("POST /films/{id}/comments", filmHandler.AddComment, caching.comments, customerAuth)
+	r.HandleFunc("GET /films/{id}/comments", filmHandler.GetComments, caching.stale, caching.comments, customerAuth)
+	r.HandleFunc("GET /films/{id}/comments/summary", filmHandler.GetCommentSummary, caching.stale,
+		caching.comments)
+}
+
+// routeCaching holds the Cache-Control middleware of each class of route,
+// and the stale fallback of public reads.
+type routeCaching struct {
+	catalog  router.Middleware
+	comments router.Middleware
+	admin    router.Middleware
+	stale    router.Middleware
+}
+
+// newPaymentProvider returns the checkout payment provider selected by
+// config.
+func newPaymentProvider(config util.Config) (payments.Provider, error) {
+	switch config.PaymentProvider {
+	case "fake":
+		return payments.FakeProvider{}, nil
+	case "stripe":
+		if config.StripeSecretKey == "" || config.StripeWebhookSecret == "" {
+			return nil, errors.New("STRIPE_SECRET_KEY and STRIPE_WEBHOOK_SECRET are required for the stripe provider")
+		}
+		return payments.NewStripeProvider(config.StripeSecretKey, config.StripeWebhookSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", config.PaymentProvider)
+	}
+}
+
+// newCompressionOptions returns the response compression configured by
+// config, offering no encodings when compression is disabled.
+func newCompressionOptions(config util.Config) middleware.CompressionOptions {
+	compression := middleware.CompressionOptions{
+		GzipLevel:   config.CompressionGzipLevel,
+		BrotliLevel: config.CompressionBrotliLevel,
+		MinSize:     config.CompressionMinSize,
+		SkipTypes:   config.CompressionSkipTypes,
+	}
+	if config.CompressionEnabled {
+		compression.Encodings = config.CompressionEncodings
+	}
+	return compression
+}
+
+// newPIICipher returns the cipher personal data is encrypted with at rest,
+// under the key from config.
+func newPIICipher(config util.Config) (*pii.Cipher, error) {
+	if config.PIIEncryptionKey == "" {
+		slog.Warn("PII_ENCRYPTION_KEY not set, personal data is stored unencrypted")
+		return pii.NewCipher(nil), nil
+	}
+	key, err := pii.NewLocalKey(config.PIIEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return pii.NewCipher(key), nil
+}
+
+// checkSchema compares the database with what this build expects, failing
+// or only warning on drift as config.SchemaCheck selects.
+func checkSchema(config util.Config, db *database.DB) error {
+	switch config.SchemaCheck {
+	case "off":
+		return nil
+	case "warn", "fail":
+	default:
+		return fmt.Errorf("unknown schema check mode %q", config.SchemaCheck)
+	}
+
+	report, err := database.CheckSchema(db.DB, "migrations", database.RequiredSchema)
+	if err != nil {
+		return err
+	}
+	if !report.Drifted() {
+		return nil
+	}
+
+	if config.SchemaCheck == "warn" {
+		slog.Warn("Database schema differs from what this build expects", "report", report.String())
+		return nil
+	}
+	return fmt.Errorf("database schema differs from what this build expects:\n%s", report)
+}
+
+// newEmailSender returns the notification email backend selected by config.
+func newEmailSender(config util.Config) (notifications.Sender, error) {
+	switch config.EmailBackend {
+	case "log":
+		return notifications.LogSender{}, nil
+	case "smtp":
+		return notifications.NewSMTPSender(
+			config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.EmailFrom,
+		), nil
+	case "sendgrid":
+		if config.SendGridAPIKey == "" {
+			return nil, errors.New("SENDGRID_API_KEY is required for the sendgrid email backend")
+		}
+		return notifications.NewSendGridSender(config.SendGridAPIKey, config.EmailFrom), nil
+	default:
+		return nil, fmt.Errorf("unknown email backend %q", config.EmailBackend)
+	}
+}
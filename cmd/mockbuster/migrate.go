@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/util"
+)
+
+// migrationCheckLockTimeout bounds how long the migration check waits for
+// a lock held by live traffic before giving up.
+const migrationCheckLockTimeout = 5 * time.Second
+
+// commandUsage describes the commands runCommand accepts.
+const commandUsage = `usage: mockbuster [command]
+
+With no command, mockbuster serves the API.
+
+Commands:
+  doctor        Check configuration, database, migrations, and extensions, and report how to fix problems
+  migrate plan  List pending migrations and check them against the database without applying them
+`
+
+// runCommand runs the command given on the command line and returns the
+// process exit code.
+func runCommand(config util.Config, args []string) int {
+	if len(args) == 1 && args[0] == "doctor" {
+		return runDoctor(config, os.Stdout)
+	}
+	if len(args) == 2 && args[0] == "migrate" && args[1] == "plan" {
+		return planMigrations(config, os.Stdout)
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", strings.Join(args, " "), commandUsage)
+	return 2 //nolint:mnd // Conventional exit code for usage errors
+}
+
+// planMigrations prints the migrations a deploy would apply and checks
+// them in a transaction that is rolled back.
+func planMigrations(config util.Config, out io.Writer) int {
+	db, err := database.InitDB(
+		database.WithDBHost(config.DBHost),
+		database.WithDBPort(config.DBPort),
+		database.WithDBUser(config.DBUser),
+		database.WithDBPassword(config.DBPassword),
+		database.WithDBName(config.DBName),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	pending, err := database.PlanMigrations(db.DB, "migrations")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to plan migrations: %v\n", err)
+		return 1
+	}
+	if len(pending) == 0 {
+		fmt.Fprintln(out, "No pending migrations; the database is up to date.")
+		return 0
+	}
+
+	fmt.Fprintf(out, "%d pending migration(s):\n", len(pending))
+	for _, migration := range pending {
+		fmt.Fprintf(out, "  %d\t%s\n", migration.Version, filepath.Base(migration.Source))
+	}
+
+	if err = database.CheckMigrations(db.DB, pending, migrationCheckLockTimeout); err != nil {
+		fmt.Fprintf(out, "Check failed: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(out, "Check passed: every pending migration ran cleanly and was rolled back.")
+	return 0
+}
@@ -0,0 +1,111 @@
+// Package apperrors classifies errors by kind, so repositories and services
+// can say what went wrong without knowing how callers report it, and
+// handlers can pick a response without knowing every error by name.
+package apperrors
+
+import "errors"
+
+// Kind is the class of an error.
+type Kind int
+
+// Error kinds. Errors without a kind are Internal.
+const (
+	// Internal is an unexpected failure, such as a broken query.
+	Internal Kind = iota
+	// NotFound means a requested record does not exist.
+	NotFound
+	// Invalid means a request is malformed or names records that do not
+	// exist.
+	Invalid
+	// Conflict means a request cannot be carried out in the current state,
+	// such as reusing a taken name or refunding an unpaid payment.
+	Conflict
+	// Unavailable means a dependency could not be reached; retrying later
+	// may succeed.
+	Unavailable
+	// Stale means a request was made against an out-of-date copy of a
+	// record, which has since changed.
+	Stale
+)
+
+// String returns the kind's name.
+func (k Kind) String() string {
+	switch k {
+	case NotFound:
+		return "not found"
+	case Invalid:
+		return "invalid"
+	case Conflict:
+		return "conflict"
+	case Unavailable:
+		return "unavailable"
+	case Stale:
+		return "stale"
+	default:
+		return "internal"
+	}
+}
+
+// Error is an error of a kind, optionally wrapping its cause.
+type Error struct {
+	Kind    Kind
+	Message string
+	Err     error
+}
+
+// Error returns the message, followed by the cause's when there is one.
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+// Unwrap returns the cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error of kind with message, such as a sentinel error
+// compared with errors.Is.
+func New(kind Kind, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap marks err as being of kind, or returns nil if err is nil.
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf returns the kind of the outermost error of a kind in err's chain,
+// or Internal if there is none.
+func KindOf(err error) Kind {
+	var kindErr *Error
+	if errors.As(err, &kindErr) {
+		return kindErr.Kind
+	}
+	return Internal
+}
+
+// Message returns the message of the outermost error of a kind in err's
+// chain that has one, or "" if there is none.
+func Message(err error) string {
+	for err != nil {
+		var kindErr *Error
+		if !errors.As(err, &kindErr) {
+			return ""
+		}
+		if kindErr.Message != "" {
+			return kindErr.Message
+		}
+		err = kindErr.Err
+	}
+	return ""
+}
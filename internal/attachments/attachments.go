@@ -0,0 +1,127 @@
+// Package attachments validates the images attached to comments, makes
+// their thumbnails, and keeps both in a storage backend.
+package attachments
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // Registers the GIF decoder
+	"image/jpeg"
+	_ "image/png" // Registers the PNG decoder
+	"net/http"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+)
+
+// Limits on accepted images. maxPixels rejects images that are small to
+// upload but would take far too much memory to decode.
+const (
+	maxPixels      = 40_000_000
+	thumbnailSize  = 320
+	thumbnailJPEGQ = 80
+)
+
+// ErrUnsupportedType is returned for attachments that are not JPEG, PNG, or
+// GIF images.
+var ErrUnsupportedType = apperrors.New(apperrors.Invalid, "attachment must be a JPEG, PNG, or GIF image")
+
+// ErrTooLarge is returned for attachments over the size limit or with too
+// many pixels.
+var ErrTooLarge = apperrors.New(apperrors.Invalid, "attachment is too large")
+
+// ErrInfected is returned for attachments the virus scanner rejects.
+var ErrInfected = apperrors.New(apperrors.Invalid, "attachment failed the virus scan")
+
+// extensions maps each accepted content type to the extension it is stored
+// under.
+var extensions = map[string]string{ //nolint:gochecknoglobals // Read-only
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Image is a validated image attachment and its thumbnail, a JPEG at most
+// thumbnailSize pixels on its longer side.
+type Image struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+	Thumbnail   []byte
+}
+
+// Prepare checks that data is a JPEG, PNG, or GIF image of at most maxBytes,
+// judged by its content rather than any name or header sent with it, and
+// makes its thumbnail.
+func Prepare(data []byte, maxBytes int) (*Image, error) {
+	if len(data) > maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := extensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	thumbnail, err := makeThumbnail(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{Data: data, ContentType: contentType, Extension: extension, Thumbnail: thumbnail}, nil
+}
+
+// makeThumbnail scales src to fit within thumbnailSize, sampling the nearest
+// source pixel, over white so transparent images stay legible as JPEG.
+func makeThumbnail(src image.Image) ([]byte, error) {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longer := max(width, height); longer > thumbnailSize {
+		width = max(1, width*thumbnailSize/longer)
+		height = max(1, height*thumbnailSize/longer)
+	}
+
+	thumb := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(thumb, thumb.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	for y := range height {
+		srcY := bounds.Min.Y + y*bounds.Dy()/height
+		for x := range width {
+			srcX := bounds.Min.X + x*bounds.Dx()/width
+			thumb.Set(x, y, blendOverWhite(src.At(srcX, srcY)))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailJPEGQ}); err != nil {
+		return nil, fmt.Errorf("error encoding thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// blendOverWhite composites c, which may be translucent, over white.
+func blendOverWhite(c color.Color) color.Color {
+	r, g, b, a := c.RGBA()
+	const opaque = 0xffff
+	background := opaque - a
+	return color.RGBA64{
+		R: uint16(r + background), //nolint:gosec // Premultiplied, so at most opaque
+		G: uint16(g + background), //nolint:gosec // Premultiplied, so at most opaque
+		B: uint16(b + background), //nolint:gosec // Premultiplied, so at most opaque
+		A: opaque,
+	}
+}
@@ -0,0 +1,161 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Store is a storage backend for attachments, addressed by key.
+type Store interface {
+	// Put stores data under key.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Delete removes the data stored under key, if any.
+	Delete(ctx context.Context, key string) error
+
+	// URL returns where the data stored under key is served from.
+	URL(key string) string
+}
+
+// keyPattern matches the keys NewKey generates, with the thumbnail suffix.
+var keyPattern = regexp.MustCompile(`^[0-9a-f]{32}(-thumb)?\.(jpg|png|gif)$`) //nolint:gochecknoglobals // Read-only
+
+// NewKey returns a random key for an attachment stored with extension, and
+// the key of its thumbnail.
+func NewKey(extension string) (key, thumbnailKey string, err error) {
+	raw := make([]byte, 16) //nolint:mnd // 128 bits, unguessable
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("error generating attachment key: %w", err)
+	}
+	name := hex.EncodeToString(raw)
+	return name + extension, name + "-thumb.jpg", nil
+}
+
+// LocalStore keeps attachments as files in a directory, served by Handler.
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore creates a store keeping files in dir, which is created if
+// missing, and serving them under baseURL.
+func NewLocalStore(dir, baseURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil { //nolint:mnd // Owner and group only
+		return nil, fmt.Errorf("error creating attachment directory: %w", err)
+	}
+	return &LocalStore{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+// Put writes data to the file named key, replacing it atomically.
+func (s *LocalStore) Put(_ context.Context, key string, data []byte) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid attachment key %q", key)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error creating attachment file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // Gone once renamed
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing attachment: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("error writing attachment: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), filepath.Join(s.dir, key)); err != nil {
+		return fmt.Errorf("error storing attachment: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the file named key.
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid attachment key %q", key)
+	}
+	if err := os.Remove(filepath.Join(s.dir, key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error deleting attachment: %w", err)
+	}
+	return nil
+}
+
+// URL returns baseURL followed by key.
+func (s *LocalStore) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Handler serves GET /{key} from the store's directory. Only keys the store
+// generates are served, so nothing else in the directory can be listed or
+// read. Attachments never change, so they may be cached for a year.
+func (s *LocalStore) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("key")
+		if !keyPattern.MatchString(key) {
+			http.NotFound(w, r)
+			return
+		}
+
+		file, err := os.Open(filepath.Join(s.dir, key))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer file.Close()
+
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(w, r, key, time.Time{}, file)
+	})
+}
+
+// Scanner is the virus-scan hook run on each attachment before it is stored.
+type Scanner interface {
+	// Scan returns ErrInfected if data must not be stored, or another error
+	// if it could not be scanned.
+	Scan(ctx context.Context, data []byte) error
+}
+
+// CommandScanner scans attachments with an external command, such as
+// "clamdscan --no-summary -", given each attachment on stdin. Following
+// ClamAV, exit status 0 means clean and 1 infected; anything else is a
+// scan failure.
+type CommandScanner struct {
+	args []string
+}
+
+// NewCommandScanner creates a scanner running command, split into
+// arguments on spaces.
+func NewCommandScanner(command string) *CommandScanner {
+	return &CommandScanner{args: strings.Fields(command)}
+}
+
+// Scan runs the command on data.
+func (s *CommandScanner) Scan(ctx context.Context, data []byte) error {
+	cmd := exec.CommandContext(ctx, s.args[0], s.args[1:]...) //nolint:gosec // Command is operator configuration
+	cmd.Stdin = bytes.NewReader(data)
+	output, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		return ErrInfected
+	default:
+		return fmt.Errorf("error scanning attachment: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+}
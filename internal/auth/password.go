@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/sha1" //nolint:gosec // verifies legacy hashes from the sample data only
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// unknownAccountHash is a bcrypt hash, at HashPassword's cost, that no
+// password is checked against successfully in practice.
+const unknownAccountHash = "$2a$10$dJhTrWo2nLMawADe.99cqOqhR.TKfrFc8MBjB7TV8Bio56TAf3yCm"
+
+// HashPassword returns a bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches hash. Besides bcrypt it
+// accepts the unsalted SHA-1 hex digests shipped with the dvdrental data,
+// which callers should replace after a match; see NeedsRehash.
+func CheckPassword(hash, password string) bool {
+	if !NeedsRehash(hash) {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	}
+	if hash == "" {
+		return false
+	}
+
+	digest := sha1.Sum([]byte(password)) //nolint:gosec // legacy hash format
+	return subtle.ConstantTimeCompare([]byte(strings.ToLower(hash)), []byte(hex.EncodeToString(digest[:]))) == 1
+}
+
+// CheckUnknownAccountPassword takes as long as CheckPassword does with a
+// bcrypt hash, for logins to accounts that do not exist: rejecting them
+// sooner would tell callers which accounts do. It always reports false.
+func CheckUnknownAccountPassword(password string) bool {
+	_ = bcrypt.CompareHashAndPassword([]byte(unknownAccountHash), []byte(password))
+	return false
+}
+
+// NeedsRehash reports whether hash is in the legacy SHA-1 format rather
+// than bcrypt, so should be replaced by HashPassword of the password once it
+// has been checked.
+func NeedsRehash(hash string) bool {
+	return !strings.HasPrefix(hash, "$2")
+}
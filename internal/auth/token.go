@@ -0,0 +1,170 @@
+// Package auth provides token and password handling for authenticated API
+// surfaces.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Roles carried in token claims. Each role is issued by its own TokenIssuer,
+// so a token minted for one surface is never accepted by another. Calendar
+// tokens only read a customer's rental due dates, as they travel in URLs.
+// Guest tokens identify an anonymous session rather than an account.
+const (
+	RoleStaff    = "staff"
+	RoleCustomer = "customer"
+	RoleCalendar = "calendar"
+	RoleGuest    = "guest"
+)
+
+// ErrInvalidToken is returned for malformed, forged, expired, or
+// wrong-role tokens.
+var ErrInvalidToken = errors.New("invalid token")
+
+// ErrRevokedToken is returned for tokens of a login session that has been
+// revoked, such as by logging out.
+var ErrRevokedToken = errors.New("token has been revoked")
+
+// Claims identifies the holder of a token. SessionID names the login
+// session a token was issued for; tokens issued outside a session, such as
+// calendar and guest tokens, have none. TwoFactor is set when the session
+// was started with a second factor as well as a password.
+type Claims struct {
+	Subject   int    `json:"sub"`
+	Role      string `json:"role"`
+	ExpiresAt int64  `json:"exp"`
+	SessionID int64  `json:"sid,omitempty"`
+	TwoFactor bool   `json:"tfa,omitempty"`
+}
+
+// RevocationList reports whether a login session has been revoked. It is
+// implemented by repository.SessionRepository.
+type RevocationList interface {
+	IsSessionRevoked(ctx context.Context, sessionID int64) (bool, error)
+}
+
+// TokenIssuer mints and verifies HMAC-signed bearer tokens for one role.
+type TokenIssuer struct {
+	role        string
+	secret      []byte
+	ttl         time.Duration
+	now         func() time.Time
+	revocations RevocationList
+}
+
+// TokenIssuerOption configures optional token issuer behavior.
+type TokenIssuerOption func(*TokenIssuer)
+
+// WithRevocationList rejects tokens whose login session revocations lists
+// as revoked.
+func WithRevocationList(revocations RevocationList) TokenIssuerOption {
+	return func(i *TokenIssuer) {
+		i.revocations = revocations
+	}
+}
+
+// NewTokenIssuer creates an issuer for role signing with secret; tokens are
+// valid for ttl.
+func NewTokenIssuer(role, secret string, ttl time.Duration, opts ...TokenIssuerOption) *TokenIssuer {
+	i := &TokenIssuer{role: role, secret: []byte(secret), ttl: ttl, now: time.Now}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// Role returns the role of the tokens i issues.
+func (i *TokenIssuer) Role() string {
+	return i.role
+}
+
+// Issue returns a token for subject and its expiry time.
+func (i *TokenIssuer) Issue(subject int) (string, time.Time, error) {
+	return i.IssueForSession(subject, 0, false)
+}
+
+// IssueForSession returns a token for subject in login session sessionID,
+// which is rejected once the session is revoked, and its expiry time.
+// twoFactor records that the session was started with a second factor.
+func (i *TokenIssuer) IssueForSession(subject int, sessionID int64, twoFactor bool) (string, time.Time, error) {
+	expiresAt := i.now().Add(i.ttl)
+	payload, err := json.Marshal(Claims{
+		Subject: subject, Role: i.role, ExpiresAt: expiresAt.Unix(), SessionID: sessionID, TwoFactor: twoFactor,
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error encoding token claims: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + i.sign(encoded), expiresAt, nil
+}
+
+// Verify checks token's signature, role, and expiry and returns its claims.
+func (i *TokenIssuer) Verify(token string) (*Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(i.sign(encoded))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Role != i.role || i.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
+// Authenticate is Verify for tokens presented by callers: it also returns
+// ErrRevokedToken when the token's login session has been revoked.
+func (i *TokenIssuer) Authenticate(ctx context.Context, token string) (*Claims, error) {
+	claims, err := i.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if i.revocations == nil || claims.SessionID == 0 {
+		return claims, nil
+	}
+
+	revoked, err := i.revocations.IsSessionRevoked(ctx, claims.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking token revocation: %w", err)
+	}
+	if revoked {
+		return nil, ErrRevokedToken
+	}
+	return claims, nil
+}
+
+func (i *TokenIssuer) sign(encoded string) string {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type claimsKey struct{}
+
+// WithClaims stores the authenticated caller's claims on ctx.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims stored by WithClaims, if any.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
@@ -0,0 +1,179 @@
+// Package binding decodes query parameters into filter structs and validates
+// them by their struct tags, so a handler and the service behind it check a
+// filter by the same rules.
+//
+// Fields are bound from the query parameter named by their query tag, and
+// the fields of embedded structs as if they were the outer struct's:
+//
+//	type Filters struct {
+//		Ratings []string `query:"rating" validate:"dive,oneof=G PG"`
+//		Page    int      `query:"page"   validate:"min=1"`
+//	}
+//
+// String, integer, and boolean fields take the parameter as is; []string
+// fields take a comma-separated list, such as "PG,PG-13".
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+)
+
+var (
+	// ErrInvalidQuery is returned when a query parameter cannot be bound to
+	// its field's type.
+	ErrInvalidQuery = apperrors.New(apperrors.Invalid, "invalid query parameter")
+	// ErrValidation is returned when a filter breaks its validation rules.
+	ErrValidation = apperrors.New(apperrors.Invalid, "validation failed")
+)
+
+// validate checks struct tags. Besides the standard rules it knows
+// notblank, which rejects strings of only whitespace.
+var validate = newValidator() //nolint:gochecknoglobals // Caches struct metadata across requests
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	if err := v.RegisterValidation("notblank", validators.NotBlank); err != nil {
+		panic(err)
+	}
+	v.RegisterTagNameFunc(fieldName)
+	return v
+}
+
+// Query sets the fields of the struct dst points to from values. Fields whose
+// parameter is missing or empty keep their value, so dst can be filled with
+// defaults first.
+func Query(values url.Values, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("binding: destination must be a pointer to a struct, not %T", dst)
+	}
+	return bindStruct(values, v.Elem())
+}
+
+// bindStruct sets the fields of v from values, including those of embedded
+// structs.
+func bindStruct(values url.Values, v reflect.Value) error {
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := bindStruct(values, v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name, ok := field.Tag.Lookup("query")
+		if !ok || name == "-" {
+			continue
+		}
+		raw := values.Get(name)
+		if raw == "" {
+			continue
+		}
+		if err := setField(v.Field(i), name, raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setField parses raw into field.
+func setField(field reflect.Value, name, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || field.OverflowInt(n) {
+			return fmt.Errorf("%w: %s must be a whole number, not %q", ErrInvalidQuery, name, raw)
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%w: %s must be true or false, not %q", ErrInvalidQuery, name, raw)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("binding: unsupported type %s for query parameter %q", field.Type(), name)
+		}
+		field.Set(reflect.ValueOf(splitList(raw)).Convert(field.Type()))
+	default:
+		return fmt.Errorf("binding: unsupported type %s for query parameter %q", field.Type(), name)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated value such as "PG,PG-13" into its
+// trimmed, non-empty parts.
+func splitList(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
+// Validate checks the struct v by its validate tags, returning an
+// ErrValidation error that describes each broken rule.
+func Validate(v any) error {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return fmt.Errorf("error validating %T: %w", v, err)
+	}
+
+	problems := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		problems = append(problems, describe(fieldErr))
+	}
+	return fmt.Errorf("%w: %s", ErrValidation, strings.Join(problems, "; "))
+}
+
+// describe words a broken rule for the client, naming the field as it is
+// sent.
+func describe(fieldErr validator.FieldError) string {
+	field := fieldErr.Field()
+	switch fieldErr.Tag() {
+	case "required", "notblank":
+		return field + " must not be empty"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s, not %q",
+			field, strings.ReplaceAll(fieldErr.Param(), " ", ", "), fmt.Sprint(fieldErr.Value()))
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", field, fieldErr.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", field, fieldErr.Param())
+	default:
+		return fmt.Sprintf("%s is invalid (%s)", field, fieldErr.Tag())
+	}
+}
+
+// fieldName names a field by its query parameter, falling back to its JSON
+// name and then its Go name.
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"query", "json"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,129 @@
+// Package cache provides an in-process response cache for the Mockbuster API
+// along with the invalidation events used to keep it consistent with writes.
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores values by key for a limited time.
+type Cache interface {
+	// Get returns the value stored under key, if present and not expired.
+	Get(key string) (any, bool)
+
+	// Set stores value under key.
+	Set(key string, value any)
+
+	// Delete removes the given keys.
+	Delete(keys ...string)
+
+	// DeletePrefix removes every key starting with prefix.
+	DeletePrefix(prefix string)
+
+	// Purge removes every key.
+	Purge()
+}
+
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// MemoryCache is a Cache backed by a map with a fixed time-to-live per entry.
+// Expired entries are kept until they are deleted or make room for new
+// entries.
+type MemoryCache struct {
+	mu         sync.RWMutex
+	items      map[string]entry
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+}
+
+// MemoryCacheOption configures a MemoryCache.
+type MemoryCacheOption func(*MemoryCache)
+
+// WithMaxEntries bounds the number of entries held. Once full, expired entries
+// are dropped to make room and new keys are refused if none have expired.
+func WithMaxEntries(maxEntries int) MemoryCacheOption {
+	return func(c *MemoryCache) {
+		c.maxEntries = maxEntries
+	}
+}
+
+// NewMemoryCache creates an in-memory cache whose entries expire after ttl.
+func NewMemoryCache(ttl time.Duration, opts ...MemoryCacheOption) *MemoryCache {
+	c := &MemoryCache{
+		items: make(map[string]entry),
+		ttl:   ttl,
+		now:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get returns the value stored under key, if present and not expired.
+func (c *MemoryCache) Get(key string) (any, bool) {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+
+	if !ok || c.now().After(item.expiresAt) {
+		return nil, false
+	}
+	return item.value, true
+}
+
+// Set stores value under key.
+func (c *MemoryCache) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, exists := c.items[key]; !exists && c.maxEntries > 0 && len(c.items) >= c.maxEntries {
+		for existing, item := range c.items {
+			if now.After(item.expiresAt) {
+				delete(c.items, existing)
+			}
+		}
+		if len(c.items) >= c.maxEntries {
+			return
+		}
+	}
+
+	c.items[key] = entry{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// Delete removes the given keys.
+func (c *MemoryCache) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+}
+
+// DeletePrefix removes every key starting with prefix.
+func (c *MemoryCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.items, key)
+		}
+	}
+}
+
+// Purge removes every key.
+func (c *MemoryCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]entry)
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// Event describes cache entries made stale by a write.
+type Event struct {
+	Keys     []string
+	Prefixes []string
+	All      bool
+}
+
+// Publisher announces invalidation events. Services performing writes depend
+// on this rather than on a concrete cache.
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Bus fans invalidation events out to its subscribers synchronously, so the
+// cache is consistent by the time the write's response is sent.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []func(Event)
+}
+
+// NewBus creates an event bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers fn to receive every published event.
+func (b *Bus) Subscribe(fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, fn)
+}
+
+// Publish delivers event to every subscriber.
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	slog.Debug("Publishing cache invalidation", "keys", event.Keys, "prefixes", event.Prefixes, "all", event.All)
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
+
+// Evict returns a subscriber that removes the entries named by each event
+// from c.
+func Evict(c Cache) func(Event) {
+	return func(event Event) {
+		if event.All {
+			c.Purge()
+			return
+		}
+		c.Delete(event.Keys...)
+		for _, prefix := range event.Prefixes {
+			c.DeletePrefix(prefix)
+		}
+	}
+}
+
+// FilmChanged invalidates a film and every listing it may appear in.
+func FilmChanged(filmID int) Event {
+	return Event{Keys: []string{FilmKey(filmID)}, Prefixes: []string{FilmListPrefix}}
+}
+
+// FilmsChanged invalidates several films and every listing they may appear
+// in.
+func FilmsChanged(filmIDs ...int) Event {
+	keys := make([]string, 0, len(filmIDs))
+	for _, filmID := range filmIDs {
+		keys = append(keys, FilmKey(filmID))
+	}
+	return Event{Keys: keys, Prefixes: []string{FilmListPrefix}}
+}
+
+// CategoriesChanged invalidates the category list and every film, since
+// films embed their category names and listings can be filtered by category.
+func CategoriesChanged() Event {
+	return Event{Keys: []string{CategoriesKey}, Prefixes: []string{FilmPrefix, FilmListPrefix}}
+}
+
+// PurgeAll invalidates every cached entry.
+func PurgeAll() Event {
+	return Event{All: true}
+}
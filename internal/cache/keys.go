@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// Key layout shared by the caching repository and invalidation events.
+const (
+	// CategoriesKey holds the full category list.
+	CategoriesKey = "categories"
+
+	// FilmPrefix prefixes every cached single film.
+	FilmPrefix = "film:"
+
+	// FilmListPrefix prefixes every cached film listing page.
+	FilmListPrefix = "films:"
+)
+
+// FilmKey returns the key of a single cached film.
+func FilmKey(filmID int) string {
+	return fmt.Sprintf("%s%d", FilmPrefix, filmID)
+}
+
+// FilmListKey returns the key of a cached film listing for the given filters.
+func FilmListKey(filters models.FilmFilters) string {
+	encoded, err := json.Marshal(filters)
+	if err != nil {
+		// FilmFilters only holds strings, ints, and bools, so this cannot happen.
+		return FilmListPrefix + fmt.Sprintf("%+v", filters)
+	}
+	return FilmListPrefix + string(encoded)
+}
@@ -6,14 +6,27 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"time"
 
 	_ "github.com/lib/pq" //nolint:goimports //Recommended way to use the library
 	"github.com/rxbenefits/go-hw/internal/util"
 )
 
-// DB holds the database connection.
+// DB holds the database connection. Queries issued through it are timed,
+// and those slower than slowQueryThreshold are logged.
 type DB struct {
 	*sql.DB
+
+	slowQueryThreshold time.Duration
+}
+
+// Querier runs queries. *DB satisfies it, as do *sql.Tx and *sql.Conn, so
+// code written against it can run inside a transaction or against a mock
+// driver such as sqlmock.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 }
 
 type dbOpts struct {
@@ -22,10 +35,14 @@ type dbOpts struct {
 	user     string
 	password string
 	dbname   string
+
+	slowQueryThreshold time.Duration
 }
 
 type dbOptsFunc func(dbOpts) dbOpts
 
+const defaultSlowQueryThreshold = 500 * time.Millisecond
+
 func defaultDBOpts() dbOpts {
 	return dbOpts{
 		host:     util.GetEnv("DB_HOST", "localhost"),
@@ -33,6 +50,8 @@ func defaultDBOpts() dbOpts {
 		user:     util.GetEnv("DB_USER", "postgres"),
 		password: util.GetEnv("DB_PASSWORD", "postgres"),
 		dbname:   util.GetEnv("DB_NAME", "dvdrental"),
+
+		slowQueryThreshold: defaultSlowQueryThreshold,
 	}
 }
 
@@ -76,12 +95,21 @@ func WithDBName(dbname string) func(dbOpts) dbOpts {
 	}
 }
 
+// WithSlowQueryThreshold sets the duration above which queries are logged as
+// slow. A zero threshold disables the slow-query log.
+func WithSlowQueryThreshold(threshold time.Duration) func(dbOpts) dbOpts {
+	return func(opts dbOpts) dbOpts {
+		opts.slowQueryThreshold = threshold
+		return opts
+	}
+}
+
 // InitDB initializes a new database connection with the given options.
 func InitDB(opts ...dbOptsFunc) (*DB, error) {
 	dbOptions := defaultDBOpts()
 
 	for _, opt := range opts {
-		opt(dbOptions)
+		dbOptions = opt(dbOptions)
 	}
 
 	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
@@ -97,7 +125,7 @@ func InitDB(opts ...dbOptsFunc) (*DB, error) {
 	}
 
 	slog.Info("Successfully connected to database")
-	return &DB{db}, nil
+	return &DB{DB: db, slowQueryThreshold: dbOptions.slowQueryThreshold}, nil
 }
 
 // Close closes the database connection.
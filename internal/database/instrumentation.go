@@ -0,0 +1,95 @@
+// Package database provides database connection management for the Mockbuster API.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/metrics"
+)
+
+type queryNameKey struct{}
+
+// WithQueryName labels the queries run with ctx so their timings and
+// slow-query logs can be told apart, e.g. "films.list".
+func WithQueryName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+// queryName returns the label attached by WithQueryName, falling back to the
+// statement's leading keyword for unlabeled queries.
+func queryName(ctx context.Context, query string) string {
+	if name, ok := ctx.Value(queryNameKey{}).(string); ok && name != "" {
+		return name
+	}
+	if fields := strings.Fields(query); len(fields) > 0 {
+		return "unnamed." + strings.ToLower(fields[0])
+	}
+	return "unnamed"
+}
+
+// QueryContext runs a query that returns rows, recording its duration.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	defer db.observe(ctx, query, args, time.Now())
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a query that returns at most one row, recording its
+// duration.
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer db.observe(ctx, query, args, time.Now())
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+// ExecContext runs a statement that returns no rows, recording its duration.
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer db.observe(ctx, query, args, time.Now())
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+// observe records the query duration and logs the query when it exceeds the
+// slow-query threshold.
+func (db *DB) observe(ctx context.Context, query string, args []any, start time.Time) {
+	elapsed := time.Since(start)
+	name := queryName(ctx, query)
+	metrics.DBQueryDuration.WithLabelValues(name).Observe(elapsed.Seconds())
+
+	if db.slowQueryThreshold <= 0 || elapsed < db.slowQueryThreshold {
+		return
+	}
+
+	metrics.DBSlowQueries.WithLabelValues(name).Inc()
+	slog.Warn("Slow database query",
+		"query", name,
+		"duration", elapsed,
+		"threshold", db.slowQueryThreshold,
+		"sql", SanitizeSQL(query),
+		"args", SanitizeArgs(args),
+	)
+}
+
+// SanitizeSQL collapses the whitespace of a query onto a single line.
+func SanitizeSQL(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// SanitizeArgs renders query parameters for logging. String values may hold
+// customer data, so only their length is kept.
+func SanitizeArgs(args []any) []string {
+	sanitized := make([]string, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case string:
+			sanitized[i] = fmt.Sprintf("<string len=%d>", len(value))
+		case []byte:
+			sanitized[i] = fmt.Sprintf("<bytes len=%d>", len(value))
+		default:
+			sanitized[i] = fmt.Sprintf("%v", value)
+		}
+	}
+	return sanitized
+}
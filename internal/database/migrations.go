@@ -2,14 +2,29 @@
 package database
 
 import (
+	"bufio"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/pressly/goose/v3"
 )
 
-// RunMigrations runs database migrations using Goose.
+// migrationLockName is hashed into the advisory lock key held while
+// migrations run.
+const migrationLockName = "mockbuster.migrations"
+
+// RunMigrations runs database migrations using Goose. Replicas starting
+// together take turns under a Postgres advisory lock, so only the first
+// applies pending migrations and the rest find nothing left to do.
 func RunMigrations(db *sql.DB, migrationsDir string) error {
 	goose.SetBaseFS(nil)
 
@@ -18,7 +33,28 @@ func RunMigrations(db *sql.DB, migrationsDir string) error {
 		return fmt.Errorf("failed to set dialect: %w", err)
 	}
 
-	if err := goose.Up(db, migrationsDir); err != nil {
+	// The lock belongs to the session, so it is held on one connection
+	// while goose runs on others from the pool.
+	ctx := WithQueryName(context.Background(), "migrations.lock")
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	slog.Info("Waiting for migration lock")
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", migrationLockName); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		unlockCtx := WithQueryName(context.Background(), "migrations.unlock")
+		_, unlockErr := conn.ExecContext(unlockCtx, "SELECT pg_advisory_unlock(hashtext($1))", migrationLockName)
+		if unlockErr != nil {
+			slog.Error("Failed to release migration lock", "error", unlockErr)
+		}
+	}()
+
+	if err = goose.Up(db, migrationsDir); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
@@ -44,3 +80,182 @@ func GetMigrationStatus(db *sql.DB) error {
 	slog.Info("Current database migration version", "version", current)
 	return nil
 }
+
+// PendingMigration is a migration not yet applied to the database.
+type PendingMigration struct {
+	Version int64
+	Source  string
+}
+
+// PlanMigrations returns the migrations in migrationsDir that are not yet
+// applied, in the order goose would apply them. Nothing is written, not
+// even goose's version table.
+func PlanMigrations(db *sql.DB, migrationsDir string) ([]PendingMigration, error) {
+	goose.SetBaseFS(nil)
+
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, math.MaxInt64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect migrations: %w", err)
+	}
+
+	applied, err := appliedVersions(context.Background(), db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []PendingMigration
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, PendingMigration{Version: migration.Version, Source: migration.Source})
+		}
+	}
+	return pending, nil
+}
+
+// LatestMigrationVersion returns the version of the newest migration in
+// migrationsDir, the schema version this build expects.
+func LatestMigrationVersion(migrationsDir string) (int64, error) {
+	goose.SetBaseFS(nil)
+
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, math.MaxInt64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect migrations: %w", err)
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// AppliedVersion returns the newest migration version applied to the
+// database, or 0 if goose has never migrated it. Unlike goose.GetDBVersion
+// it does not create the version table.
+func AppliedVersion(ctx context.Context, db *sql.DB) (int64, error) {
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64
+	for v, isApplied := range applied {
+		if isApplied && v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// CheckMigrations runs the Up SQL of pending migrations in a transaction
+// that is always rolled back, so syntax errors and conflicts with the live
+// schema surface without changing it. Statements give up on locks held
+// longer than lockTimeout rather than stalling traffic.
+func CheckMigrations(db *sql.DB, pending []PendingMigration, lockTimeout time.Duration) error {
+	ctx := WithQueryName(context.Background(), "migrations.check")
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start migration check: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			slog.Error("Failed to roll back migration check", "error", rollbackErr)
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL lock_timeout = %d", lockTimeout.Milliseconds())); err != nil {
+		return fmt.Errorf("failed to set lock timeout: %w", err)
+	}
+
+	for _, migration := range pending {
+		file, openErr := os.Open(migration.Source)
+		if openErr != nil {
+			return fmt.Errorf("failed to open migration: %w", openErr)
+		}
+		upSQL, parseErr := MigrationUpSQL(file)
+		file.Close()
+		if parseErr != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(migration.Source), parseErr)
+		}
+
+		if _, err = tx.ExecContext(ctx, upSQL); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(migration.Source), err)
+		}
+	}
+
+	return nil
+}
+
+// MigrationUpSQL returns the Up section of a goose SQL migration without
+// its goose annotations.
+func MigrationUpSQL(r io.Reader) (string, error) {
+	var upSQL strings.Builder
+	inUp := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		annotation, isAnnotation := strings.CutPrefix(strings.TrimSpace(line), "-- +goose ")
+		if !isAnnotation {
+			if inUp {
+				upSQL.WriteString(line)
+				upSQL.WriteByte('\n')
+			}
+			continue
+		}
+
+		switch strings.TrimSpace(annotation) {
+		case "Up":
+			inUp = true
+		case "Down":
+			inUp = false
+		case "NO TRANSACTION":
+			return "", errors.New("migrations run outside a transaction cannot be checked")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read migration: %w", err)
+	}
+
+	if strings.TrimSpace(upSQL.String()) == "" {
+		return "", errors.New("migration has no Up section")
+	}
+	return upSQL.String(), nil
+}
+
+// appliedVersions returns the migration versions currently applied,
+// according to goose's version table. A database goose has never migrated
+// has none.
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int64]bool, error) {
+	ctx = WithQueryName(ctx, "migrations.applied")
+	var table sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass($1)::text", goose.TableName()).Scan(&table); err != nil {
+		return nil, fmt.Errorf("failed to look up migration version table: %w", err)
+	}
+
+	applied := make(map[int64]bool)
+	if !table.Valid {
+		return applied, nil
+	}
+
+	// Each apply or rollback adds a row; the latest one for a version wins.
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT DISTINCT ON (version_id) version_id, is_applied
+		FROM %s
+		ORDER BY version_id, id DESC`, goose.TableName()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		if err = rows.Scan(&version, &isApplied); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = isApplied
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
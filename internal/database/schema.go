@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// RequiredSchema lists the tables the API queries, with the columns most
+// likely to be missing when the database is behind: those migrations add to
+// existing tables. A table listed without columns only has to exist.
+var RequiredSchema = map[string][]string{
+	"film": {
+		"film_id", "title", "release_year", "rating", "rental_rate", "replacement_cost", "status", "publish_at",
+		"published_at",
+	},
+	"customer":  {"customer_id", "first_name", "last_name", "email"},
+	"rental":    {"rental_id", "inventory_id", "customer_id", "return_date", "overdue", "reminder_sent_at"},
+	"inventory": {"inventory_id", "film_id", "store_id", "retired_at"},
+	"payment":   {"payment_id", "customer_id", "rental_id", "amount"},
+	"category":  {"category_id", "name", "parent_id"},
+
+	"film_comments":            {"customer_name", "customer_id", "parent_id", "attachment_key", "thumbnail_key"},
+	"checkouts":                {"credit_applied", "gift_card_applied"},
+	"checkout_payments":        {"refunded_amount"},
+	"background_jobs":          nil,
+	"scheduled_job_runs":       nil,
+	"webhook_deliveries":       nil,
+	"api_keys":                 nil,
+	"gift_card_ledger":         nil,
+	"customer_exports":         nil,
+	"audit_log":                nil,
+	"film_availability_alerts": nil,
+	"film_search_misses":       nil,
+	"saved_searches":           nil,
+	"guest_sessions":           nil,
+	"guest_session_comments":   nil,
+	"guest_watchlist":          nil,
+	"auth_sessions":            {"user_agent", "ip_address", "two_factor"},
+	"staff_two_factor":         nil,
+	"staff_backup_codes":       nil,
+	"comment_mentions":         nil,
+	"film_comment_summaries":   nil,
+	"customer_shadow_bans":     nil,
+}
+
+// RecommendedExtensions lists the Postgres extensions the API expects:
+// pg_trgm lets the title and actor searches, which match substrings with
+// ILIKE, use trigram indexes instead of scanning, and provides the
+// similarity behind title suggestions.
+var RecommendedExtensions = []string{"pg_trgm"}
+
+// SchemaReport describes how the live database differs from what this
+// build expects.
+type SchemaReport struct {
+	PendingMigrations []PendingMigration
+	// MissingTables and MissingColumns name what is absent, as "table" and
+	// "table.column".
+	MissingTables  []string
+	MissingColumns []string
+}
+
+// Drifted reports whether the database differs from what is expected.
+func (r *SchemaReport) Drifted() bool {
+	return len(r.PendingMigrations) > 0 || len(r.MissingTables) > 0 || len(r.MissingColumns) > 0
+}
+
+// String lists the differences, one per line.
+func (r *SchemaReport) String() string {
+	var b strings.Builder
+	for _, migration := range r.PendingMigrations {
+		fmt.Fprintf(&b, "migration not applied: %s\n", filepath.Base(migration.Source))
+	}
+	for _, table := range r.MissingTables {
+		fmt.Fprintf(&b, "table missing: %s\n", table)
+	}
+	for _, column := range r.MissingColumns {
+		fmt.Fprintf(&b, "column missing: %s\n", column)
+	}
+	return b.String()
+}
+
+// CheckSchema compares the live database with the migrations in
+// migrationsDir and the tables and columns in required.
+func CheckSchema(db *sql.DB, migrationsDir string, required map[string][]string) (*SchemaReport, error) {
+	pending, err := PlanMigrations(db, migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	report := &SchemaReport{PendingMigrations: pending}
+
+	tables := make([]string, 0, len(required))
+	for table := range required {
+		tables = append(tables, table)
+	}
+	slices.Sort(tables)
+
+	existing, err := liveColumns(db, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		columns, ok := existing[table]
+		if !ok {
+			report.MissingTables = append(report.MissingTables, table)
+			continue
+		}
+		for _, column := range required[table] {
+			if !columns[column] {
+				report.MissingColumns = append(report.MissingColumns, table+"."+column)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// liveColumns returns the columns of each of tables that exists in the
+// current schema.
+func liveColumns(db *sql.DB, tables []string) (map[string]map[string]bool, error) {
+	ctx := WithQueryName(context.Background(), "schema.columns")
+	rows, err := db.QueryContext(ctx, `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ANY($1)`, pq.Array(tables))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema columns: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err = rows.Scan(&table, &column); err != nil {
+			return nil, fmt.Errorf("failed to scan schema column: %w", err)
+		}
+		if existing[table] == nil {
+			existing[table] = make(map[string]bool)
+		}
+		existing[table][column] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schema columns: %w", err)
+	}
+
+	return existing, nil
+}
+
+// MissingExtensions returns those of names not installed in the database.
+func MissingExtensions(db *sql.DB, names []string) ([]string, error) {
+	ctx := WithQueryName(context.Background(), "schema.extensions")
+	rows, err := db.QueryContext(ctx, "SELECT extname FROM pg_extension WHERE extname = ANY($1)", pq.Array(names))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query extensions: %w", err)
+	}
+	defer rows.Close()
+
+	installed := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan extension: %w", err)
+		}
+		installed[name] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate extensions: %w", err)
+	}
+
+	var missing []string
+	for _, name := range names {
+		if !installed[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,88 @@
+// Package feeds builds Atom feeds (RFC 4287) for catalog watchers to
+// subscribe to.
+package feeds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// ContentType is the media type Atom feeds are served with.
+const ContentType = "application/atom+xml; charset=utf-8"
+
+// Feed is an Atom feed document.
+type Feed struct {
+	XMLName xml.Name  `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string    `xml:"id"`
+	Title   string    `xml:"title"`
+	Updated time.Time `xml:"updated"`
+	Author  *Person   `xml:"author,omitempty"`
+	Links   []Link    `xml:"link"`
+	Entries []Entry   `xml:"entry"`
+}
+
+// Entry is an entry in an Atom feed.
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    time.Time  `xml:"updated"`
+	Published  *time.Time `xml:"published,omitempty"`
+	Author     *Person    `xml:"author,omitempty"`
+	Links      []Link     `xml:"link"`
+	Categories []Category `xml:"category"`
+	Summary    *Text      `xml:"summary,omitempty"`
+	Content    *Text      `xml:"content,omitempty"`
+}
+
+// Person names the author of a feed or entry.
+type Person struct {
+	Name string `xml:"name"`
+}
+
+// Link is a link from a feed or entry, such as its alternate JSON
+// representation.
+type Link struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+// Category labels an entry, such as with a film's genre.
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+// Text is the summary or content of an entry. Type is "text" when empty,
+// or "html" for escaped HTML.
+type Text struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+// Marshal encodes feed as an XML document. Times are written in UTC to the
+// second, as most feed readers expect.
+func Marshal(feed Feed) ([]byte, error) {
+	feed.Updated = timestamp(feed.Updated)
+	entries := make([]Entry, len(feed.Entries))
+	for i, entry := range feed.Entries {
+		entry.Updated = timestamp(entry.Updated)
+		if entry.Published != nil {
+			published := timestamp(*entry.Published)
+			entry.Published = &published
+		}
+		entries[i] = entry
+	}
+	feed.Entries = entries
+
+	body, err := xml.Marshal(feed)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding feed: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// timestamp returns t in UTC, truncated to the second.
+func timestamp(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
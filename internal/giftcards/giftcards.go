@@ -0,0 +1,67 @@
+// Package giftcards generates gift card codes and the hashes they are
+// stored under.
+package giftcards
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// codeAlphabet holds the characters of a code, leaving out ones easily
+// misread, such as 0, O, 1, and I. Its 32 characters divide 256 evenly, so
+// each random byte picks one without bias.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// codeLength is the number of characters in a code, 80 random bits.
+const codeLength = 16
+
+// groupLength is the number of characters between hyphens in a code.
+const groupLength = 4
+
+// NewCode generates a random gift card code, such as
+// "7KQF-M2XD-9HRT-WB4C", returning the code and the hash to store in its
+// place.
+func NewCode() (code, hash string, err error) {
+	raw := make([]byte, codeLength)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("error generating gift card code: %w", err)
+	}
+
+	var b strings.Builder
+	for i, c := range raw {
+		if i > 0 && i%groupLength == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(codeAlphabet[int(c)%len(codeAlphabet)])
+	}
+	code = b.String()
+	return code, Hash(code), nil
+}
+
+// Normalize returns code in the form it is hashed in: upper case, without
+// the hyphens and spaces people type between groups.
+func Normalize(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
+
+// Hash returns the hex SHA-256 digest under which code is stored. Codes are
+// random, so an unsalted fast hash is enough to make a leaked table useless.
+func Hash(code string) string {
+	sum := sha256.Sum256([]byte(Normalize(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// LastFour returns the last four characters of code, kept in the clear to
+// identify a card.
+func LastFour(code string) string {
+	normalized := Normalize(code)
+	return normalized[max(0, len(normalized)-groupLength):]
+}
@@ -0,0 +1,92 @@
+// Package grpcapi serves the internal gRPC API used by batch consumers.
+package grpcapi
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/rxbenefits/go-hw/internal/grpcapi/filmexportv1"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// NewServer creates a gRPC server exposing the film export service. Every
+// call must carry token as a bearer token in its authorization metadata.
+func NewServer(token string, exportService service.FilmExportService) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(RequireTokenUnary(token)),
+		grpc.StreamInterceptor(RequireTokenStream(token)),
+	)
+	filmexportv1.RegisterFilmExportServiceServer(server, NewFilmExportServer(exportService))
+	return server
+}
+
+// FilmExportServer implements filmexportv1.FilmExportServiceServer.
+type FilmExportServer struct {
+	filmexportv1.UnimplementedFilmExportServiceServer
+
+	exportService service.FilmExportService
+}
+
+// NewFilmExportServer creates a film export server backed by exportService.
+func NewFilmExportServer(exportService service.FilmExportService) *FilmExportServer {
+	return &FilmExportServer{exportService: exportService}
+}
+
+// ListAllFilms streams every film in ID order, one message per film. A
+// consumer that is cut off can resume by passing the last film ID it received
+// as after_film_id.
+func (s *FilmExportServer) ListAllFilms(
+	req *filmexportv1.ListAllFilmsRequest,
+	stream grpc.ServerStreamingServer[filmexportv1.Film],
+) error {
+	err := s.exportService.StreamFilms(stream.Context(), int(req.GetStoreId()), int(req.GetAfterFilmId()),
+		func(film models.Film) error {
+			if sendErr := stream.Send(toProtoFilm(film)); sendErr != nil {
+				return fmt.Errorf("error sending film: %w", sendErr)
+			}
+			return nil
+		})
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, service.ErrInvalidExportRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case stream.Context().Err() != nil:
+		return status.FromContextError(stream.Context().Err()).Err()
+	default:
+		return status.Error(codes.Internal, "failed to export films")
+	}
+}
+
+// toProtoFilm converts a film to its wire representation.
+func toProtoFilm(film models.Film) *filmexportv1.Film {
+	msg := &filmexportv1.Film{
+		FilmId:          int32(film.FilmID), //nolint:gosec // Film IDs are Postgres integers
+		Title:           film.Title,
+		Description:     film.Description,
+		LanguageId:      int32(film.LanguageID),     //nolint:gosec // Postgres smallint
+		RentalDuration:  int32(film.RentalDuration), //nolint:gosec // Postgres smallint
+		RentalRate:      film.RentalRate,
+		ReplacementCost: film.ReplacementCost,
+		Rating:          film.Rating,
+		SpecialFeatures: film.SpecialFeatures,
+		Categories:      film.Categories,
+		Actors:          film.Actors,
+		LastUpdate:      timestamppb.New(film.LastUpdate),
+	}
+	if film.ReleaseYear != nil {
+		year := int32(*film.ReleaseYear) //nolint:gosec // Postgres year domain
+		msg.ReleaseYear = &year
+	}
+	if film.Length != nil {
+		length := int32(*film.Length) //nolint:gosec // Postgres smallint
+		msg.Length = &length
+	}
+	return msg
+}
@@ -0,0 +1,316 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: api/proto/filmexport/v1/film_export.proto
+
+package filmexportv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ListAllFilmsRequest selects the films to export.
+type ListAllFilmsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only export films with inventory at this store; 0 exports every film.
+	StoreId int32 `protobuf:"varint,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	// Resume an interrupted export after this film ID.
+	AfterFilmId   int32 `protobuf:"varint,2,opt,name=after_film_id,json=afterFilmId,proto3" json:"after_film_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAllFilmsRequest) Reset() {
+	*x = ListAllFilmsRequest{}
+	mi := &file_api_proto_filmexport_v1_film_export_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAllFilmsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAllFilmsRequest) ProtoMessage() {}
+
+func (x *ListAllFilmsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_filmexport_v1_film_export_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAllFilmsRequest.ProtoReflect.Descriptor instead.
+func (*ListAllFilmsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_filmexport_v1_film_export_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListAllFilmsRequest) GetStoreId() int32 {
+	if x != nil {
+		return x.StoreId
+	}
+	return 0
+}
+
+func (x *ListAllFilmsRequest) GetAfterFilmId() int32 {
+	if x != nil {
+		return x.AfterFilmId
+	}
+	return 0
+}
+
+// Film is one catalog entry.
+type Film struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	FilmId          int32                  `protobuf:"varint,1,opt,name=film_id,json=filmId,proto3" json:"film_id,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description     *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	ReleaseYear     *int32                 `protobuf:"varint,4,opt,name=release_year,json=releaseYear,proto3,oneof" json:"release_year,omitempty"`
+	LanguageId      int32                  `protobuf:"varint,5,opt,name=language_id,json=languageId,proto3" json:"language_id,omitempty"`
+	RentalDuration  int32                  `protobuf:"varint,6,opt,name=rental_duration,json=rentalDuration,proto3" json:"rental_duration,omitempty"`
+	RentalRate      float64                `protobuf:"fixed64,7,opt,name=rental_rate,json=rentalRate,proto3" json:"rental_rate,omitempty"`
+	Length          *int32                 `protobuf:"varint,8,opt,name=length,proto3,oneof" json:"length,omitempty"`
+	ReplacementCost float64                `protobuf:"fixed64,9,opt,name=replacement_cost,json=replacementCost,proto3" json:"replacement_cost,omitempty"`
+	Rating          string                 `protobuf:"bytes,10,opt,name=rating,proto3" json:"rating,omitempty"`
+	SpecialFeatures []string               `protobuf:"bytes,11,rep,name=special_features,json=specialFeatures,proto3" json:"special_features,omitempty"`
+	Categories      []string               `protobuf:"bytes,12,rep,name=categories,proto3" json:"categories,omitempty"`
+	Actors          []string               `protobuf:"bytes,13,rep,name=actors,proto3" json:"actors,omitempty"`
+	LastUpdate      *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=last_update,json=lastUpdate,proto3" json:"last_update,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Film) Reset() {
+	*x = Film{}
+	mi := &file_api_proto_filmexport_v1_film_export_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Film) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Film) ProtoMessage() {}
+
+func (x *Film) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_filmexport_v1_film_export_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Film.ProtoReflect.Descriptor instead.
+func (*Film) Descriptor() ([]byte, []int) {
+	return file_api_proto_filmexport_v1_film_export_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Film) GetFilmId() int32 {
+	if x != nil {
+		return x.FilmId
+	}
+	return 0
+}
+
+func (x *Film) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Film) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *Film) GetReleaseYear() int32 {
+	if x != nil && x.ReleaseYear != nil {
+		return *x.ReleaseYear
+	}
+	return 0
+}
+
+func (x *Film) GetLanguageId() int32 {
+	if x != nil {
+		return x.LanguageId
+	}
+	return 0
+}
+
+func (x *Film) GetRentalDuration() int32 {
+	if x != nil {
+		return x.RentalDuration
+	}
+	return 0
+}
+
+func (x *Film) GetRentalRate() float64 {
+	if x != nil {
+		return x.RentalRate
+	}
+	return 0
+}
+
+func (x *Film) GetLength() int32 {
+	if x != nil && x.Length != nil {
+		return *x.Length
+	}
+	return 0
+}
+
+func (x *Film) GetReplacementCost() float64 {
+	if x != nil {
+		return x.ReplacementCost
+	}
+	return 0
+}
+
+func (x *Film) GetRating() string {
+	if x != nil {
+		return x.Rating
+	}
+	return ""
+}
+
+func (x *Film) GetSpecialFeatures() []string {
+	if x != nil {
+		return x.SpecialFeatures
+	}
+	return nil
+}
+
+func (x *Film) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *Film) GetActors() []string {
+	if x != nil {
+		return x.Actors
+	}
+	return nil
+}
+
+func (x *Film) GetLastUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdate
+	}
+	return nil
+}
+
+var File_api_proto_filmexport_v1_film_export_proto protoreflect.FileDescriptor
+
+const file_api_proto_filmexport_v1_film_export_proto_rawDesc = "" +
+	"\n" +
+	")api/proto/filmexport/v1/film_export.proto\x12\x18mockbuster.filmexport.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"T\n" +
+	"\x13ListAllFilmsRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\x05R\astoreId\x12\"\n" +
+	"\rafter_film_id\x18\x02 \x01(\x05R\vafterFilmId\"\x9b\x04\n" +
+	"\x04Film\x12\x17\n" +
+	"\afilm_id\x18\x01 \x01(\x05R\x06filmId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x00R\vdescription\x88\x01\x01\x12&\n" +
+	"\frelease_year\x18\x04 \x01(\x05H\x01R\vreleaseYear\x88\x01\x01\x12\x1f\n" +
+	"\vlanguage_id\x18\x05 \x01(\x05R\n" +
+	"languageId\x12'\n" +
+	"\x0frental_duration\x18\x06 \x01(\x05R\x0erentalDuration\x12\x1f\n" +
+	"\vrental_rate\x18\a \x01(\x01R\n" +
+	"rentalRate\x12\x1b\n" +
+	"\x06length\x18\b \x01(\x05H\x02R\x06length\x88\x01\x01\x12)\n" +
+	"\x10replacement_cost\x18\t \x01(\x01R\x0freplacementCost\x12\x16\n" +
+	"\x06rating\x18\n" +
+	" \x01(\tR\x06rating\x12)\n" +
+	"\x10special_features\x18\v \x03(\tR\x0fspecialFeatures\x12\x1e\n" +
+	"\n" +
+	"categories\x18\f \x03(\tR\n" +
+	"categories\x12\x16\n" +
+	"\x06actors\x18\r \x03(\tR\x06actors\x12;\n" +
+	"\vlast_update\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUpdateB\x0e\n" +
+	"\f_descriptionB\x0f\n" +
+	"\r_release_yearB\t\n" +
+	"\a_length2t\n" +
+	"\x11FilmExportService\x12_\n" +
+	"\fListAllFilms\x12-.mockbuster.filmexport.v1.ListAllFilmsRequest\x1a\x1e.mockbuster.filmexport.v1.Film0\x01BHZFgithub.com/rxbenefits/go-hw/internal/grpcapi/filmexportv1;filmexportv1b\x06proto3"
+
+var (
+	file_api_proto_filmexport_v1_film_export_proto_rawDescOnce sync.Once
+	file_api_proto_filmexport_v1_film_export_proto_rawDescData []byte
+)
+
+func file_api_proto_filmexport_v1_film_export_proto_rawDescGZIP() []byte {
+	file_api_proto_filmexport_v1_film_export_proto_rawDescOnce.Do(func() {
+		file_api_proto_filmexport_v1_film_export_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_filmexport_v1_film_export_proto_rawDesc), len(file_api_proto_filmexport_v1_film_export_proto_rawDesc)))
+	})
+	return file_api_proto_filmexport_v1_film_export_proto_rawDescData
+}
+
+var file_api_proto_filmexport_v1_film_export_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_proto_filmexport_v1_film_export_proto_goTypes = []any{
+	(*ListAllFilmsRequest)(nil),   // 0: mockbuster.filmexport.v1.ListAllFilmsRequest
+	(*Film)(nil),                  // 1: mockbuster.filmexport.v1.Film
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_api_proto_filmexport_v1_film_export_proto_depIdxs = []int32{
+	2, // 0: mockbuster.filmexport.v1.Film.last_update:type_name -> google.protobuf.Timestamp
+	0, // 1: mockbuster.filmexport.v1.FilmExportService.ListAllFilms:input_type -> mockbuster.filmexport.v1.ListAllFilmsRequest
+	1, // 2: mockbuster.filmexport.v1.FilmExportService.ListAllFilms:output_type -> mockbuster.filmexport.v1.Film
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_filmexport_v1_film_export_proto_init() }
+func file_api_proto_filmexport_v1_film_export_proto_init() {
+	if File_api_proto_filmexport_v1_film_export_proto != nil {
+		return
+	}
+	file_api_proto_filmexport_v1_film_export_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_filmexport_v1_film_export_proto_rawDesc), len(file_api_proto_filmexport_v1_film_export_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_proto_filmexport_v1_film_export_proto_goTypes,
+		DependencyIndexes: file_api_proto_filmexport_v1_film_export_proto_depIdxs,
+		MessageInfos:      file_api_proto_filmexport_v1_film_export_proto_msgTypes,
+	}.Build()
+	File_api_proto_filmexport_v1_film_export_proto = out.File
+	file_api_proto_filmexport_v1_film_export_proto_goTypes = nil
+	file_api_proto_filmexport_v1_film_export_proto_depIdxs = nil
+}
@@ -0,0 +1,130 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: api/proto/filmexport/v1/film_export.proto
+
+package filmexportv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FilmExportService_ListAllFilms_FullMethodName = "/mockbuster.filmexport.v1.FilmExportService/ListAllFilms"
+)
+
+// FilmExportServiceClient is the client API for FilmExportService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FilmExportService streams the film catalog to internal batch consumers.
+type FilmExportServiceClient interface {
+	// ListAllFilms streams every film in film ID order, one message per film.
+	ListAllFilms(ctx context.Context, in *ListAllFilmsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Film], error)
+}
+
+type filmExportServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFilmExportServiceClient(cc grpc.ClientConnInterface) FilmExportServiceClient {
+	return &filmExportServiceClient{cc}
+}
+
+func (c *filmExportServiceClient) ListAllFilms(ctx context.Context, in *ListAllFilmsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Film], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FilmExportService_ServiceDesc.Streams[0], FilmExportService_ListAllFilms_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListAllFilmsRequest, Film]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FilmExportService_ListAllFilmsClient = grpc.ServerStreamingClient[Film]
+
+// FilmExportServiceServer is the server API for FilmExportService service.
+// All implementations must embed UnimplementedFilmExportServiceServer
+// for forward compatibility.
+//
+// FilmExportService streams the film catalog to internal batch consumers.
+type FilmExportServiceServer interface {
+	// ListAllFilms streams every film in film ID order, one message per film.
+	ListAllFilms(*ListAllFilmsRequest, grpc.ServerStreamingServer[Film]) error
+	mustEmbedUnimplementedFilmExportServiceServer()
+}
+
+// UnimplementedFilmExportServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFilmExportServiceServer struct{}
+
+func (UnimplementedFilmExportServiceServer) ListAllFilms(*ListAllFilmsRequest, grpc.ServerStreamingServer[Film]) error {
+	return status.Errorf(codes.Unimplemented, "method ListAllFilms not implemented")
+}
+func (UnimplementedFilmExportServiceServer) mustEmbedUnimplementedFilmExportServiceServer() {}
+func (UnimplementedFilmExportServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeFilmExportServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FilmExportServiceServer will
+// result in compilation errors.
+type UnsafeFilmExportServiceServer interface {
+	mustEmbedUnimplementedFilmExportServiceServer()
+}
+
+func RegisterFilmExportServiceServer(s grpc.ServiceRegistrar, srv FilmExportServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFilmExportServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FilmExportService_ServiceDesc, srv)
+}
+
+func _FilmExportService_ListAllFilms_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListAllFilmsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FilmExportServiceServer).ListAllFilms(m, &grpc.GenericServerStream[ListAllFilmsRequest, Film]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FilmExportService_ListAllFilmsServer = grpc.ServerStreamingServer[Film]
+
+// FilmExportService_ServiceDesc is the grpc.ServiceDesc for FilmExportService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FilmExportService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mockbuster.filmexport.v1.FilmExportService",
+	HandlerType: (*FilmExportServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListAllFilms",
+			Handler:       _FilmExportService_ListAllFilms_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/proto/filmexport/v1/film_export.proto",
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// defaultFeedLimit is the page size of the activity feed when no limit is
+// given.
+const defaultFeedLimit = 20
+
+// ActivityHandler handles HTTP requests for customer follows and activity
+// feeds.
+type ActivityHandler struct {
+	activityService service.ActivityService
+	validate        *validator.Validate
+}
+
+// NewActivityHandler creates a new activity handler with the given service.
+func NewActivityHandler(activityService service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+		validate:        validator.New(),
+	}
+}
+
+// GetFeed handles GET /feed, the recent public activity of the customers
+// the caller follows, taking page and limit query parameters.
+func (h *ActivityHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	filters := models.ActivityFeedFilters{Page: 1, Limit: defaultFeedLimit}
+	var err error
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		if filters.Page, err = strconv.Atoi(pageStr); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid page", err)
+			return
+		}
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if filters.Limit, err = strconv.Atoi(limitStr); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid limit", err)
+			return
+		}
+	}
+	if err = h.validate.Struct(filters); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	feed, err := h.activityService.GetFeed(r.Context(), filters)
+	if err != nil {
+		if errors.Is(err, service.ErrFeedRequiresCustomer) {
+			respondWithError(w, http.StatusForbidden, "Feeds are for customers", err)
+		} else {
+			respondWithAppError(w, err, "Failed to retrieve feed")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, feed)
+}
+
+// ListFollowing handles GET /customers/{id}/following.
+func (h *ActivityHandler) ListFollowing(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	follows, err := h.activityService.ListFollowing(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve follows")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, follows)
+}
+
+// Follow handles PUT /customers/{id}/following/{followedID}. Following a
+// customer already followed succeeds.
+func (h *ActivityHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	customerID, followedID, ok := followRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.activityService.Follow(r.Context(), customerID, followedID); err != nil {
+		respondWithAppError(w, err, "Failed to follow customer")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Following customer"})
+}
+
+// Unfollow handles DELETE /customers/{id}/following/{followedID}.
+func (h *ActivityHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	customerID, followedID, ok := followRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.activityService.Unfollow(r.Context(), customerID, followedID); err != nil {
+		respondWithAppError(w, err, "Failed to unfollow customer")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Unfollowed customer"})
+}
+
+// followRouteIDs parses the follower and followed customer IDs from the
+// route, responding with 400 and reporting false when either is invalid.
+func followRouteIDs(w http.ResponseWriter, r *http.Request) (customerID, followedID int, ok bool) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return 0, 0, false
+	}
+	followedID, err = strconv.Atoi(r.PathValue("followedID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid followed customer ID", err)
+		return 0, 0, false
+	}
+	return customerID, followedID, true
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/middleware"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// AdminHandler handles HTTP requests for operational admin endpoints.
+type AdminHandler struct {
+	invalidations cache.Publisher
+	maintenance   *middleware.MaintenanceMode
+	validate      *validator.Validate
+}
+
+// NewAdminHandler creates a new admin handler publishing cache invalidations
+// to the given publisher and controlling the given maintenance switch.
+func NewAdminHandler(invalidations cache.Publisher, maintenance *middleware.MaintenanceMode) *AdminHandler {
+	return &AdminHandler{
+		invalidations: invalidations,
+		maintenance:   maintenance,
+		validate:      validator.New(),
+	}
+}
+
+// PurgeCache handles POST /admin/cache/purge.
+func (h *AdminHandler) PurgeCache(w http.ResponseWriter, _ *http.Request) {
+	h.invalidations.Publish(cache.PurgeAll())
+	slog.Warn("Cache purged by admin request")
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Cache purged"})
+}
+
+// GetMaintenance handles GET /admin/maintenance.
+func (h *AdminHandler) GetMaintenance(w http.ResponseWriter, _ *http.Request) {
+	respondWithJSON(w, http.StatusOK, models.MaintenanceStatus{Enabled: h.maintenance.Enabled()})
+}
+
+// SetMaintenance handles PUT /admin/maintenance.
+func (h *AdminHandler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req models.MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	h.maintenance.SetEnabled(*req.Enabled)
+	slog.Warn("Maintenance mode changed by admin request", "enabled", *req.Enabled)
+
+	respondWithJSON(w, http.StatusOK, models.MaintenanceStatus{Enabled: *req.Enabled})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// APIKeyHandler handles HTTP requests for issuing API keys and reporting
+// their usage.
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+	validate      *validator.Validate
+}
+
+// NewAPIKeyHandler creates a new API key handler with the given service.
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validate:      validator.New(),
+	}
+}
+
+// ListKeys handles GET /admin/api-keys.
+func (h *APIKeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeyService.ListKeys(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve API keys")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, keys)
+}
+
+// CreateKey handles POST /admin/api-keys. The response is the only time the
+// key itself is returned.
+func (h *APIKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	var keyReq models.APIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&keyReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(keyReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	key, err := h.apiKeyService.CreateKey(r.Context(), keyReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to create API key")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, key)
+}
+
+// RevokeKey handles POST /admin/api-keys/{id}/revoke.
+func (h *APIKeyHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	keyID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid API key ID", err)
+		return
+	}
+
+	key, err := h.apiKeyService.RevokeKey(r.Context(), keyID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to revoke API key")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, key)
+}
+
+// GetUsage handles GET /usage, reporting the calling key's consumption in
+// the current month.
+func (h *APIKeyHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	key, ok := auth.APIKeyFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized",
+			errors.New("an API key is required in the "+auth.APIKeyHeader+" header"))
+		return
+	}
+
+	usage, err := h.apiKeyService.GetUsage(r.Context(), key)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve API key usage")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, usage)
+}
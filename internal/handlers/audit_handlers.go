@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// AuditHandler handles HTTP requests reading the audit log.
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+// NewAuditHandler creates a new audit log handler with the given service.
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// GetStaffActivity handles GET /admin/staff/{id}/activity, listing the
+// audited actions a staff member has taken. action narrows the entries to
+// a comma-separated list of actions; from and to take an RFC 3339 time or a
+// date, and a date given as to includes that whole day.
+func (h *AuditHandler) GetStaffActivity(w http.ResponseWriter, r *http.Request) {
+	staffID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid staff ID", err)
+		return
+	}
+
+	filters, err := parseAuditFilters(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	if err = binding.Validate(filters); err != nil {
+		respondWithAppError(w, err, "Validation failed")
+		return
+	}
+	if err = models.AuditLimits.Check(filters.Params); err != nil {
+		respondWithAppError(w, err, "Validation failed")
+		return
+	}
+
+	activity, err := h.auditService.GetStaffActivity(r.Context(), staffID, filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve staff activity")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, activity)
+}
+
+// parseAuditFilters reads the actions, page, limit, and date range of an
+// audit log request.
+func parseAuditFilters(r *http.Request) (models.AuditFilters, error) {
+	query := r.URL.Query()
+	filters := models.AuditFilters{Params: models.AuditLimits.First()}
+
+	err := binding.Query(query, &filters)
+	if err != nil {
+		return filters, err
+	}
+	if filters.From, err = parseTimeParam(query.Get("from"), false); err != nil {
+		return filters, fmt.Errorf("invalid from: %w", err)
+	}
+	if filters.To, err = parseTimeParam(query.Get("to"), true); err != nil {
+		return filters, fmt.Errorf("invalid to: %w", err)
+	}
+	if filters.From != nil && filters.To != nil && !filters.From.Before(*filters.To) {
+		return filters, errors.New("from must be before to")
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// AvailabilityHandler handles HTTP requests for film availability alerts.
+type AvailabilityHandler struct {
+	availabilityService service.AvailabilityService
+}
+
+// NewAvailabilityHandler creates a new availability alert handler with the
+// given service.
+func NewAvailabilityHandler(availabilityService service.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{availabilityService: availabilityService}
+}
+
+// NotifyMe handles POST /films/{id}/notify-me, registering the token's
+// customer to be emailed when a copy of the film comes back. It responds 201
+// with a new alert, or 200 with the customer's pending one.
+func (h *AvailabilityHandler) NotifyMe(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	alert, created, err := h.availabilityService.NotifyMe(r.Context(), filmID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to create availability alert")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	respondWithJSON(w, status, alert)
+}
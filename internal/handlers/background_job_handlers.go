@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// defaultJobListLimit is the number of jobs listed when no limit is given.
+const defaultJobListLimit = 50
+
+// BackgroundJobHandler handles HTTP requests for inspecting background jobs.
+type BackgroundJobHandler struct {
+	jobService service.BackgroundJobService
+	validate   *validator.Validate
+}
+
+// NewBackgroundJobHandler creates a new background job handler with the
+// given service.
+func NewBackgroundJobHandler(jobService service.BackgroundJobService) *BackgroundJobHandler {
+	return &BackgroundJobHandler{
+		jobService: jobService,
+		validate:   validator.New(),
+	}
+}
+
+// ListJobs handles GET /admin/jobs.
+func (h *BackgroundJobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	filters := models.BackgroundJobFilters{
+		Status: r.URL.Query().Get("status"),
+		Kind:   r.URL.Query().Get("kind"),
+		Limit:  defaultJobListLimit,
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid limit", err)
+			return
+		}
+		filters.Limit = limit
+	}
+	if err := h.validate.Struct(filters); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	jobs, err := h.jobService.ListJobs(r.Context(), filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve jobs")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, jobs)
+}
+
+// GetJob handles GET /admin/jobs/{id}.
+func (h *BackgroundJobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid job ID", err)
+		return
+	}
+
+	job, err := h.jobService.GetJob(r.Context(), jobID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve job")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
+
+// RetryJob handles POST /admin/jobs/{id}/retry.
+func (h *BackgroundJobHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid job ID", err)
+		return
+	}
+
+	job, err := h.jobService.RetryJob(r.Context(), jobID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retry job")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/ical"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// calendarCacheControl keeps calendars, which are personal and fetched by
+// a tokened URL, out of shared caches.
+const calendarCacheControl = "private, max-age=300"
+
+// CalendarHandler handles HTTP requests for customers' rental calendars.
+type CalendarHandler struct {
+	calendarService service.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler with the given service.
+func NewCalendarHandler(calendarService service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+// IssueCalendarURL handles POST /customers/{id}/calendar-token, returning
+// the URL to subscribe to for the customer's rental due dates.
+func (h *CalendarHandler) IssueCalendarURL(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	subscription, err := h.calendarService.IssueCalendarURL(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to issue calendar URL")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, subscription)
+}
+
+// GetRentalCalendar handles GET and HEAD /customers/{id}/rentals.ics, the
+// customer's rentals not yet returned as events at their due dates.
+func (h *CalendarHandler) GetRentalCalendar(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	calendar, err := h.calendarService.RentalCalendar(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to build rental calendar")
+		return
+	}
+
+	w.Header().Set("Cache-Control", calendarCacheControl)
+	respondWithCacheable(w, r, ical.ContentType, ical.Marshal(*calendar), time.Time{})
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// Error codes for checkouts refused because of the cart's contents.
+const (
+	errorCodeFilmUnavailable = "film_unavailable"
+	errorCodeCartChanged     = "cart_changed"
+)
+
+// CartHandler handles HTTP requests for customer carts and checkout.
+type CartHandler struct {
+	cartService service.CartService
+	validate    *validator.Validate
+}
+
+// NewCartHandler creates a new cart handler with the given service.
+func NewCartHandler(cartService service.CartService) *CartHandler {
+	return &CartHandler{
+		cartService: cartService,
+		validate:    validator.New(),
+	}
+}
+
+// GetCart handles GET /customers/{id}/cart.
+func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	cart, err := h.cartService.GetCart(r.Context(), customerID)
+	if err != nil {
+		respondWithCartError(w, "Failed to retrieve cart", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cart)
+}
+
+// AddItem handles POST /customers/{id}/cart/items, responding with the cart.
+func (h *CartHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	var itemReq models.CartItemRequest
+	if err = json.NewDecoder(r.Body).Decode(&itemReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(itemReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	cart, err := h.cartService.AddItem(r.Context(), customerID, itemReq.FilmID)
+	if err != nil {
+		respondWithCartError(w, "Failed to add film to cart", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cart)
+}
+
+// RemoveItem handles DELETE /customers/{id}/cart/items/{filmID}, responding
+// with the cart.
+func (h *CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+	filmID, err := strconv.Atoi(r.PathValue("filmID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	cart, err := h.cartService.RemoveItem(r.Context(), customerID, filmID)
+	if err != nil {
+		respondWithCartError(w, "Failed to remove film from cart", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cart)
+}
+
+// Checkout handles POST /customers/{id}/checkout. The body is optional and
+// may carry a coupon_code.
+func (h *CartHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	var checkoutReq models.CheckoutRequest
+	if r.ContentLength != 0 {
+		if err = json.NewDecoder(r.Body).Decode(&checkoutReq); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+	if err = h.validate.Struct(checkoutReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	checkout, err := h.cartService.Checkout(r.Context(), customerID, checkoutReq)
+	if err != nil {
+		respondWithCartError(w, "Failed to check out", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, checkout)
+}
+
+// respondWithCartError maps cart service errors to responses, with error
+// codes for the rejections clients are expected to handle.
+func respondWithCartError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, repository.ErrFilmUnavailable):
+		respondWithErrorCode(w, http.StatusConflict, errorCodeFilmUnavailable, "A film in the cart is out of stock", err)
+	case errors.Is(err, repository.ErrCartChanged):
+		respondWithErrorCode(w, http.StatusConflict, errorCodeCartChanged, "Cart changed during checkout", err)
+	case errors.Is(err, repository.ErrGiftCardInactive), errors.Is(err, repository.ErrGiftCardEmpty):
+		respondWithGiftCardError(w, message, err)
+	default:
+		respondWithCouponError(w, message, err)
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// CollectionHandler handles HTTP requests for film collections.
+type CollectionHandler struct {
+	collectionService service.CollectionService
+	validate          *validator.Validate
+}
+
+// NewCollectionHandler creates a new collection handler with the given
+// service.
+func NewCollectionHandler(collectionService service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+		validate:          validator.New(),
+	}
+}
+
+// GetCollection handles GET /collections/{id} and GET
+// /admin/collections/{id}.
+func (h *CollectionHandler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	collectionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid collection ID", err)
+		return
+	}
+
+	collection, err := h.collectionService.GetCollection(r.Context(), collectionID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve collection")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, collection)
+}
+
+// ListCollections handles GET /admin/collections.
+func (h *CollectionHandler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	collections, err := h.collectionService.ListCollections(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve collections")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, collections)
+}
+
+// CreateCollection handles POST /admin/collections.
+func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	var collectionReq models.CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&collectionReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(collectionReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(r.Context(), collectionReq)
+	if err != nil {
+		respondWithCollectionError(w, "Failed to create collection", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, collection)
+}
+
+// UpdateCollection handles PUT /admin/collections/{id}. The request
+// replaces the collection's name, description, and films.
+func (h *CollectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	collectionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid collection ID", err)
+		return
+	}
+
+	var collectionReq models.CollectionRequest
+	if err = json.NewDecoder(r.Body).Decode(&collectionReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(collectionReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	collection, err := h.collectionService.UpdateCollection(r.Context(), collectionID, collectionReq)
+	if err != nil {
+		respondWithCollectionError(w, "Failed to update collection", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, collection)
+}
+
+// DeleteCollection handles DELETE /admin/collections/{id}.
+func (h *CollectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	collectionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid collection ID", err)
+		return
+	}
+
+	if err = h.collectionService.DeleteCollection(r.Context(), collectionID); err != nil {
+		respondWithCollectionError(w, "Failed to delete collection", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Collection deleted"})
+}
+
+// respondWithCollectionError maps collection service errors to responses.
+func respondWithCollectionError(w http.ResponseWriter, message string, err error) {
+	respondWithAppError(w, err, message)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// Error codes for coupons that exist but cannot be used.
+const (
+	errorCodeCouponExpired   = "coupon_expired"
+	errorCodeCouponExhausted = "coupon_exhausted"
+)
+
+// CouponHandler handles HTTP requests for coupons.
+type CouponHandler struct {
+	couponService service.CouponService
+	validate      *validator.Validate
+}
+
+// NewCouponHandler creates a new coupon handler with the given service.
+func NewCouponHandler(couponService service.CouponService) *CouponHandler {
+	return &CouponHandler{
+		couponService: couponService,
+		validate:      validator.New(),
+	}
+}
+
+// ListCoupons handles GET /admin/coupons.
+func (h *CouponHandler) ListCoupons(w http.ResponseWriter, r *http.Request) {
+	coupons, err := h.couponService.ListCoupons(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve coupons")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, coupons)
+}
+
+// CreateCoupon handles POST /admin/coupons.
+func (h *CouponHandler) CreateCoupon(w http.ResponseWriter, r *http.Request) {
+	var couponReq models.CouponRequest
+	if err := json.NewDecoder(r.Body).Decode(&couponReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(couponReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	coupon, err := h.couponService.CreateCoupon(r.Context(), couponReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to create coupon")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, coupon)
+}
+
+// ValidateCoupon handles POST /customers/{id}/coupons/{code}/validate,
+// reporting the discount the coupon would give a checkout of the amount in
+// the body without using it up.
+func (h *CouponHandler) ValidateCoupon(w http.ResponseWriter, r *http.Request) {
+	customerID, checkReq, ok := h.parseCouponCheck(w, r)
+	if !ok {
+		return
+	}
+
+	quote, err := h.couponService.ValidateCoupon(r.Context(), r.PathValue("code"), customerID, checkReq.Amount)
+	if err != nil {
+		respondWithCouponError(w, "Failed to validate coupon", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, quote)
+}
+
+// RedeemCoupon handles POST /customers/{id}/coupons/{code}/redeem.
+func (h *CouponHandler) RedeemCoupon(w http.ResponseWriter, r *http.Request) {
+	customerID, checkReq, ok := h.parseCouponCheck(w, r)
+	if !ok {
+		return
+	}
+
+	redemption, err := h.couponService.RedeemCoupon(r.Context(), r.PathValue("code"), customerID, checkReq.Amount)
+	if err != nil {
+		respondWithCouponError(w, "Failed to redeem coupon", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, redemption)
+}
+
+// parseCouponCheck reads the customer ID and checkout amount of a coupon
+// request, responding with an error and returning false if either is
+// invalid.
+func (h *CouponHandler) parseCouponCheck(
+	w http.ResponseWriter,
+	r *http.Request,
+) (int, models.CouponCheckRequest, bool) {
+	var checkReq models.CouponCheckRequest
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return 0, checkReq, false
+	}
+
+	if err = json.NewDecoder(r.Body).Decode(&checkReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return 0, checkReq, false
+	}
+	if err = h.validate.Struct(checkReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return 0, checkReq, false
+	}
+
+	return customerID, checkReq, true
+}
+
+// respondWithCouponError maps coupon service errors to responses.
+func respondWithCouponError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, repository.ErrCouponExpired):
+		respondWithErrorCode(w, http.StatusConflict, errorCodeCouponExpired, "Coupon has expired", err)
+	case errors.Is(err, repository.ErrCouponExhausted):
+		respondWithErrorCode(w, http.StatusConflict, errorCodeCouponExhausted, "Coupon has been used up", err)
+	default:
+		respondWithAppError(w, err, message)
+	}
+}
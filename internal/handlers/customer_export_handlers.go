@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// exportRetryAfter is how many seconds a client is told to wait before
+// polling a pending export again.
+const exportRetryAfter = "5"
+
+// exportContentTypes maps each export format to the content type it is
+// downloaded as.
+var exportContentTypes = map[string]string{ //nolint:gochecknoglobals // Read-only lookup table
+	models.CustomerExportFormatJSON: "application/json",
+	models.CustomerExportFormatZIP:  "application/zip",
+}
+
+// CustomerExportHandler handles HTTP requests for exports of customers'
+// personal data.
+type CustomerExportHandler struct {
+	exportService service.CustomerExportService
+	validate      *validator.Validate
+}
+
+// NewCustomerExportHandler creates a new customer export handler with the
+// given service.
+func NewCustomerExportHandler(exportService service.CustomerExportService) *CustomerExportHandler {
+	return &CustomerExportHandler{
+		exportService: exportService,
+		validate:      validator.New(),
+	}
+}
+
+// GetExport handles GET /customers/{id}/export, taking a format of json
+// (the default) or zip. A ready export is downloaded; otherwise one is
+// queued if needed and its status returned with 202, to poll at its
+// status_url.
+func (h *CustomerExportHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	filters := models.CustomerExportFilters{Format: r.URL.Query().Get("format")}
+	if filters.Format == "" {
+		filters.Format = models.CustomerExportFormatJSON
+	}
+	if err = h.validate.Struct(filters); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	export, err := h.exportService.RequestExport(r.Context(), customerID, filters.Format)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to export customer data")
+		return
+	}
+
+	if export.Status != models.CustomerExportStatusReady {
+		w.Header().Set("Location", export.StatusURL)
+		w.Header().Set("Retry-After", exportRetryAfter)
+		respondWithJSON(w, http.StatusAccepted, export)
+		return
+	}
+
+	file, err := h.exportService.GetExportFile(r.Context(), export)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve customer export")
+		return
+	}
+	w.Header().Set("Content-Type", exportContentTypes[export.Format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="customer-%d-export-%s.%s"`,
+		customerID, export.CreatedAt.Format(dateLayout), export.Format))
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(file); err != nil {
+		slog.Error("Failed to write customer export response", "error", err)
+	}
+}
+
+// GetExportStatus handles GET /customers/{id}/exports/{exportID}.
+func (h *CustomerExportHandler) GetExportStatus(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+	exportID, err := strconv.Atoi(r.PathValue("exportID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid export ID", err)
+		return
+	}
+
+	export, err := h.exportService.GetExport(r.Context(), customerID, exportID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve customer export")
+		return
+	}
+
+	if export.Status != models.CustomerExportStatusReady {
+		w.Header().Set("Retry-After", exportRetryAfter)
+	}
+	respondWithJSON(w, http.StatusOK, export)
+}
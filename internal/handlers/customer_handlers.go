@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// CustomerHandler handles HTTP requests for customer registration and login.
+type CustomerHandler struct {
+	customerService service.CustomerService
+	validate        *validator.Validate
+}
+
+// NewCustomerHandler creates a new customer handler with the given service.
+func NewCustomerHandler(customerService service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+		validate:        validator.New(),
+	}
+}
+
+// Register handles POST /customers/register.
+func (h *CustomerHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var registerReq models.CustomerRegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&registerReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(registerReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	customer, err := h.customerService.Register(r.Context(), registerReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to register customer")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, customer)
+}
+
+// Login handles POST /auth/customer/login.
+func (h *CustomerHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var loginReq models.CustomerLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&loginReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(loginReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	token, err := h.customerService.Login(sessionContext(r), loginReq)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			respondWithError(w, http.StatusUnauthorized, "Login failed", err)
+		} else {
+			respondWithAppError(w, err, "Failed to log in")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, token)
+}
+
+// EraseData handles DELETE /customers/{id}/data, erasing the customer's
+// personal data and reporting what was erased.
+func (h *CustomerHandler) EraseData(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	erasure, err := h.customerService.EraseData(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to erase customer data")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, erasure)
+}
+
+// MergeCustomers handles POST /admin/customers/{id}/merge?into=, merging a
+// duplicate customer record into the customer named by into and reporting
+// what was moved.
+func (h *CustomerHandler) MergeCustomers(w http.ResponseWriter, r *http.Request) {
+	sourceID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+	targetID, err := strconv.Atoi(r.URL.Query().Get("into"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid into customer ID", err)
+		return
+	}
+
+	merge, err := h.customerService.MergeCustomers(r.Context(), sourceID, targetID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to merge customers")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, merge)
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// CustomerListHandler handles HTTP requests for customer-curated film
+// lists.
+type CustomerListHandler struct {
+	listService service.CustomerListService
+	validate    *validator.Validate
+}
+
+// NewCustomerListHandler creates a new customer list handler with the given
+// service.
+func NewCustomerListHandler(listService service.CustomerListService) *CustomerListHandler {
+	return &CustomerListHandler{
+		listService: listService,
+		validate:    validator.New(),
+	}
+}
+
+// GetSharedList handles GET /lists/{id}. Private lists are only shown to
+// their owner.
+func (h *CustomerListHandler) GetSharedList(w http.ResponseWriter, r *http.Request) {
+	listID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid list ID", err)
+		return
+	}
+
+	list, err := h.listService.GetSharedList(r.Context(), listID)
+	if err != nil {
+		respondWithListError(w, "Failed to retrieve list", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, list)
+}
+
+// ListCustomerLists handles GET /customers/{id}/lists.
+func (h *CustomerListHandler) ListCustomerLists(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	lists, err := h.listService.ListCustomerLists(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve lists")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, lists)
+}
+
+// CreateList handles POST /customers/{id}/lists.
+func (h *CustomerListHandler) CreateList(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	var listReq models.CustomerListRequest
+	if err = json.NewDecoder(r.Body).Decode(&listReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(listReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	list, err := h.listService.CreateList(r.Context(), customerID, listReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to create list")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, list)
+}
+
+// GetCustomerList handles GET /customers/{id}/lists/{listID}.
+func (h *CustomerListHandler) GetCustomerList(w http.ResponseWriter, r *http.Request) {
+	customerID, listID, ok := listRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.listService.GetCustomerList(r.Context(), customerID, listID)
+	if err != nil {
+		respondWithListError(w, "Failed to retrieve list", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, list)
+}
+
+// UpdateList handles PUT /customers/{id}/lists/{listID}.
+func (h *CustomerListHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
+	customerID, listID, ok := listRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var listReq models.CustomerListRequest
+	if err := json.NewDecoder(r.Body).Decode(&listReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(listReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	list, err := h.listService.UpdateList(r.Context(), customerID, listID, listReq)
+	if err != nil {
+		respondWithListError(w, "Failed to update list", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, list)
+}
+
+// DeleteList handles DELETE /customers/{id}/lists/{listID}.
+func (h *CustomerListHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	customerID, listID, ok := listRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.listService.DeleteList(r.Context(), customerID, listID); err != nil {
+		respondWithListError(w, "Failed to delete list", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "List deleted"})
+}
+
+// AddListEntry handles POST /customers/{id}/lists/{listID}/entries, adding
+// the film to the end of the list.
+func (h *CustomerListHandler) AddListEntry(w http.ResponseWriter, r *http.Request) {
+	customerID, listID, ok := listRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var entryReq models.ListEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&entryReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(entryReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	list, err := h.listService.AddListEntry(r.Context(), customerID, listID, entryReq.FilmID)
+	if err != nil {
+		respondWithListError(w, "Failed to add film to list", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, list)
+}
+
+// ReorderListEntries handles PUT /customers/{id}/lists/{listID}/entries.
+func (h *CustomerListHandler) ReorderListEntries(w http.ResponseWriter, r *http.Request) {
+	customerID, listID, ok := listRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var orderReq models.ListOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&orderReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(orderReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	list, err := h.listService.ReorderListEntries(r.Context(), customerID, listID, orderReq.FilmIDs)
+	if err != nil {
+		respondWithListError(w, "Failed to reorder list", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, list)
+}
+
+// RemoveListEntry handles DELETE /customers/{id}/lists/{listID}/entries/{filmID}.
+func (h *CustomerListHandler) RemoveListEntry(w http.ResponseWriter, r *http.Request) {
+	customerID, listID, ok := listRouteIDs(w, r)
+	if !ok {
+		return
+	}
+	filmID, err := strconv.Atoi(r.PathValue("filmID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	list, err := h.listService.RemoveListEntry(r.Context(), customerID, listID, filmID)
+	if err != nil {
+		respondWithListError(w, "Failed to remove film from list", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, list)
+}
+
+// listRouteIDs parses the customer and list IDs from the route, responding
+// with 400 and reporting false when either is invalid.
+func listRouteIDs(w http.ResponseWriter, r *http.Request) (customerID, listID int, ok bool) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return 0, 0, false
+	}
+	listID, err = strconv.Atoi(r.PathValue("listID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid list ID", err)
+		return 0, 0, false
+	}
+	return customerID, listID, true
+}
+
+// respondWithListError maps customer list service errors to responses.
+func respondWithListError(w http.ResponseWriter, message string, err error) {
+	respondWithAppError(w, err, message)
+}
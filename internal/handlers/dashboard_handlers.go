@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// DashboardHandler handles HTTP requests for the admin dashboard.
+type DashboardHandler struct {
+	dashboardService service.DashboardService
+}
+
+// NewDashboardHandler creates a new dashboard handler with the given service.
+func NewDashboardHandler(dashboardService service.DashboardService) *DashboardHandler {
+	return &DashboardHandler{dashboardService: dashboardService}
+}
+
+// GetDashboard handles GET /admin/dashboard.
+func (h *DashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	dashboard, err := h.dashboardService.GetDashboard(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to build dashboard")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dashboard)
+}
+
+// GetCategoryStats handles GET /admin/categories/{id}/stats.
+func (h *DashboardHandler) GetCategoryStats(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid category ID", err)
+		return
+	}
+
+	stats, err := h.dashboardService.GetCategoryStats(r.Context(), categoryID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve category stats")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/feeds"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// feedCacheControl lets readers and proxies reuse a feed for a few minutes
+// before revalidating it with its ETag or Last-Modified.
+const feedCacheControl = "public, max-age=300"
+
+// FeedHandler handles HTTP requests for Atom feeds.
+type FeedHandler struct {
+	feedService service.FeedService
+}
+
+// NewFeedHandler creates a new feed handler with the given service.
+func NewFeedHandler(feedService service.FeedService) *FeedHandler {
+	return &FeedHandler{feedService: feedService}
+}
+
+// GetFilmsFeed handles GET and HEAD /feeds/films.atom.
+func (h *FeedHandler) GetFilmsFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.feedService.FilmsFeed(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to build films feed")
+		return
+	}
+
+	respondWithFeed(w, r, feed)
+}
+
+// GetFilmCommentsFeed handles GET and HEAD /feeds/films/{id}/comments.atom.
+func (h *FeedHandler) GetFilmCommentsFeed(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	feed, err := h.feedService.FilmCommentsFeed(r.Context(), filmID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to build comments feed")
+		return
+	}
+
+	respondWithFeed(w, r, feed)
+}
+
+// respondWithFeed writes feed as a cacheable Atom document, last modified
+// when the feed was updated.
+func respondWithFeed(w http.ResponseWriter, r *http.Request, feed *feeds.Feed) {
+	body, err := feeds.Marshal(*feed)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to encode feed", err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", feedCacheControl)
+	respondWithCacheable(w, r, feeds.ContentType, body, feed.Updated)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+const (
+	// exportFlushEvery is how many films are written between flushes, so a
+	// client receives the export as it is read rather than all at the end.
+	exportFlushEvery = 100
+	// exportWriteTimeout bounds each stretch between flushes. It replaces the
+	// server's write timeout, which would cut off a large export.
+	exportWriteTimeout = 30 * time.Second
+)
+
+// FilmExportHandler handles HTTP exports of the whole film catalog.
+type FilmExportHandler struct {
+	exportService service.FilmExportService
+}
+
+// NewFilmExportHandler creates a new film export handler with the given
+// service.
+func NewFilmExportHandler(exportService service.FilmExportService) *FilmExportHandler {
+	return &FilmExportHandler{exportService: exportService}
+}
+
+// ExportFilms handles GET /exports/films.ndjson, streaming every film in ID
+// order as newline-delimited JSON, one film per line, as the films are read.
+// store_id limits the export to films stocked at that store, and
+// after_film_id resumes an interrupted export after the last film received.
+// An export that fails partway is cut off without its final chunk, so the
+// client sees an error rather than a short catalog.
+func (h *FilmExportHandler) ExportFilms(w http.ResponseWriter, r *http.Request) {
+	var filters models.FilmExportFilters
+	if err := binding.Query(r.URL.Query(), &filters); err != nil {
+		respondWithAppError(w, err, "Invalid export request")
+		return
+	}
+
+	stream := newNDJSONStream(w)
+	err := h.exportService.StreamFilms(r.Context(), filters.StoreID, filters.AfterFilmID,
+		func(film models.Film) error {
+			return stream.write(film)
+		})
+	switch {
+	case err == nil:
+		stream.finish()
+	case !stream.started:
+		respondWithAppError(w, err, "Failed to export films")
+	default:
+		slog.Error("Film export cut off", "exported", stream.count, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// ndjsonStream writes values to a response as newline-delimited JSON,
+// sending the headers with the first value so an error before it can still
+// be answered with a status.
+type ndjsonStream struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	encoder    *json.Encoder
+	started    bool
+	count      int
+}
+
+func newNDJSONStream(w http.ResponseWriter) *ndjsonStream {
+	return &ndjsonStream{w: w, controller: http.NewResponseController(w), encoder: json.NewEncoder(w)}
+}
+
+// write encodes v as the next line, flushing every exportFlushEvery values.
+func (s *ndjsonStream) write(v any) error {
+	if !s.started {
+		s.start()
+	}
+	if err := s.encoder.Encode(v); err != nil {
+		return fmt.Errorf("error writing export: %w", err)
+	}
+	s.count++
+	if s.count%exportFlushEvery == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+// finish sends the headers of an empty stream and flushes what is left.
+func (s *ndjsonStream) finish() {
+	if !s.started {
+		s.start()
+	}
+	if err := s.flush(); err != nil {
+		slog.Warn("Failed to flush export", "error", err)
+	}
+}
+
+func (s *ndjsonStream) start() {
+	s.started = true
+	s.w.Header().Set("Content-Type", "application/x-ndjson")
+	s.w.WriteHeader(http.StatusOK)
+	s.extendDeadline()
+}
+
+// flush sends the lines written so far and extends the write deadline.
+func (s *ndjsonStream) flush() error {
+	if err := s.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("error flushing export: %w", err)
+	}
+	s.extendDeadline()
+	return nil
+}
+
+func (s *ndjsonStream) extendDeadline() {
+	err := s.controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.Warn("Failed to extend export write deadline", "error", err)
+	}
+}
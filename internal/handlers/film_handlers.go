@@ -2,16 +2,24 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/gorilla/mux"
 
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/binding"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
 	"github.com/rxbenefits/go-hw/internal/repository"
 	"github.com/rxbenefits/go-hw/internal/service"
 )
@@ -20,63 +28,91 @@ import (
 type FilmHandler struct {
 	filmService    service.FilmService
 	commentService service.CommentService
+	searchService  service.SearchService
+	pagination     pagination.Limits
 	validate       *validator.Validate
+	// maxAttachmentBytes bounds the image uploaded with a comment.
+	maxAttachmentBytes int64
+}
+
+// defaultMaxAttachmentBytes bounds comment attachments unless
+// WithMaxAttachmentBytes sets another limit.
+const defaultMaxAttachmentBytes = 5 << 20
+
+// commentFormOverhead allows for the fields and multipart framing sent
+// alongside a comment's attachment.
+const commentFormOverhead = 64 << 10
+
+// FilmHandlerOption configures optional film handler behavior.
+type FilmHandlerOption func(*FilmHandler)
+
+// WithTitleSuggestions records title searches that find no films with
+// searchService, and answers them with the titles of films like the search.
+func WithTitleSuggestions(searchService service.SearchService) FilmHandlerOption {
+	return func(h *FilmHandler) {
+		h.searchService = searchService
+	}
+}
+
+// WithMaxAttachmentBytes rejects comments uploaded with an attachment over
+// maxBytes before reading them in full.
+func WithMaxAttachmentBytes(maxBytes int64) FilmHandlerOption {
+	return func(h *FilmHandler) {
+		h.maxAttachmentBytes = maxBytes
+	}
 }
 
 // NewFilmHandler creates a new film handler with the given services.
 // This follows the Constructor Injection pattern from the article.
-func NewFilmHandler(filmService service.FilmService, commentService service.CommentService) *FilmHandler {
-	return &FilmHandler{
+func NewFilmHandler(
+	filmService service.FilmService,
+	commentService service.CommentService,
+	pageSizes pagination.Limits,
+	opts ...FilmHandlerOption,
+) *FilmHandler {
+	h := &FilmHandler{
 		filmService:    filmService,
 		commentService: commentService,
+		pagination:     pageSizes,
 		validate:       validator.New(),
+
+		maxAttachmentBytes: defaultMaxAttachmentBytes,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
-// GetFilms handles GET /films.
+// GetFilms handles GET and HEAD /films. When a title search finds no films,
+// the response suggests titles like it.
 func (h *FilmHandler) GetFilms(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters.
-	filters := models.FilmFilters{
-		Title:    r.URL.Query().Get("title"),
-		Rating:   r.URL.Query().Get("rating"),
-		Category: r.URL.Query().Get("category"),
-	}
-
-	// Parse pagination parameters.
-	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
-		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
-			filters.Page = page
-		} else {
-			filters.Page = 1
-		}
-	} else {
-		filters.Page = 1
-	}
-
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
-			filters.Limit = limit
-		} else {
-			filters.Limit = 10
-		}
-	} else {
-		filters.Limit = 10
+	filters := models.FilmFilters{Params: h.pagination.First()}
+	if err := binding.Query(r.URL.Query(), &filters); err != nil {
+		respondWithAppError(w, err, "Invalid query parameter")
+		return
 	}
+	filters.CountMode = parseCountParam(filters.CountMode)
 
 	// Get films from service.
 	films, err := h.filmService.GetFilms(r.Context(), filters)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve films", err)
+		respondWithAppError(w, err, "Failed to retrieve films")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, films)
+	if h.searchService != nil && filters.Title != "" && filters.Page == 1 && len(films.Items) == 0 {
+		suggestions := h.searchService.SuggestTitles(r.Context(), filters.Title)
+		respondWithCacheableJSON(w, r, models.FilmSearchPage{Paginated: films, Suggestions: suggestions}, time.Time{})
+		return
+	}
+
+	respondWithCacheableJSON(w, r, films, latestFilmUpdate(films.Items))
 }
 
-// GetFilmByID handles GET /films/{id}.
+// GetFilmByID handles GET and HEAD /films/{id}.
 func (h *FilmHandler) GetFilmByID(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	filmID, err := strconv.Atoi(vars["id"])
+	filmID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
 		return
@@ -84,39 +120,85 @@ func (h *FilmHandler) GetFilmByID(w http.ResponseWriter, r *http.Request) {
 
 	film, err := h.filmService.GetFilmByID(r.Context(), filmID)
 	if err != nil {
-		if errors.Is(err, repository.ErrFilmNotFound) {
-			respondWithError(w, http.StatusNotFound, "Film not found", err)
-		} else {
-			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve film", err)
-		}
+		respondWithAppError(w, err, "Failed to retrieve film")
+		return
+	}
+
+	respondWithCacheableJSON(w, r, film, film.LastUpdate)
+}
+
+// GetAdminFilms handles GET /admin/films, listing films of any status, or of
+// those the status parameter names.
+func (h *FilmHandler) GetAdminFilms(w http.ResponseWriter, r *http.Request) {
+	filters := models.FilmFilters{Params: h.pagination.First()}
+	if err := binding.Query(r.URL.Query(), &filters); err != nil {
+		respondWithAppError(w, err, "Invalid query parameter")
+		return
+	}
+	filters.CountMode = parseCountParam(filters.CountMode)
+
+	films, err := h.filmService.GetAdminFilms(r.Context(), filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve films")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, films)
+}
+
+// GetAdminFilm handles GET /admin/films/{id}, whatever the film's status.
+// Its ETag is sent in If-Match to edit the film.
+func (h *FilmHandler) GetAdminFilm(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	film, err := h.filmService.GetAdminFilmByID(r.Context(), filmID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve film")
 		return
 	}
 
+	w.Header().Set("ETag", filmETag(film.LastUpdate))
 	respondWithJSON(w, http.StatusOK, film)
 }
 
-// GetCategories handles GET /categories.
+// GetCategories handles GET and HEAD /categories. Categories carry no
+// modification time, so only the ETag validates them.
 func (h *FilmHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
 	categories, err := h.filmService.GetCategories(r.Context())
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve categories", err)
+		respondWithAppError(w, err, "Failed to retrieve categories")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, categories)
+	respondWithCacheableJSON(w, r, categories, time.Time{})
 }
 
-// AddComment handles POST /films/{id}/comments.
+// AddComment handles POST /films/{id}/comments, taking the comment as
+// JSON, or as multipart form data with an image in the attachment field.
 func (h *FilmHandler) AddComment(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	filmID, err := strconv.Atoi(vars["id"])
+	filmID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
 		return
 	}
 
 	var commentReq models.CommentRequest
-	if decodeErr := json.NewDecoder(r.Body).Decode(&commentReq); decodeErr != nil {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		commentReq, err = h.commentFromForm(w, r)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondWithError(w, http.StatusRequestEntityTooLarge, "Attachment too large", err)
+				return
+			}
+			respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	} else if decodeErr := json.NewDecoder(r.Body).Decode(&commentReq); decodeErr != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid request body", decodeErr)
 		return
 	}
@@ -129,10 +211,11 @@ func (h *FilmHandler) AddComment(w http.ResponseWriter, r *http.Request) {
 
 	comment, err := h.commentService.AddComment(r.Context(), filmID, commentReq)
 	if err != nil {
-		if errors.Is(err, repository.ErrFilmNotFound) {
-			respondWithError(w, http.StatusNotFound, "Film not found", err)
-		} else {
-			respondWithError(w, http.StatusInternalServerError, "Failed to add comment", err)
+		switch {
+		case errors.Is(err, repository.ErrCommentsLocked):
+			respondWithErrorCode(w, http.StatusLocked, errorCodeCommentsLocked, "Comments are locked", err)
+		default:
+			respondWithAppError(w, err, "Failed to add comment")
 		}
 		return
 	}
@@ -140,26 +223,150 @@ func (h *FilmHandler) AddComment(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusCreated, comment)
 }
 
-// GetComments handles GET /films/{id}/comments.
+// commentFromForm reads a comment posted as multipart form data: the
+// customer_name, comment, and parent_id fields, and an optional image in
+// the attachment field.
+func (h *FilmHandler) commentFromForm(w http.ResponseWriter, r *http.Request) (models.CommentRequest, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxAttachmentBytes+commentFormOverhead)
+	if err := r.ParseMultipartForm(h.maxAttachmentBytes + commentFormOverhead); err != nil {
+		return models.CommentRequest{}, err
+	}
+	defer r.MultipartForm.RemoveAll() //nolint:errcheck // Best-effort cleanup of spooled parts
+
+	commentReq := models.CommentRequest{
+		CustomerName: r.FormValue("customer_name"),
+		Comment:      r.FormValue("comment"),
+	}
+	if parent := r.FormValue("parent_id"); parent != "" {
+		parentID, err := strconv.Atoi(parent)
+		if err != nil {
+			return models.CommentRequest{}, errors.New("parent_id must be a number")
+		}
+		commentReq.ParentID = &parentID
+	}
+
+	file, _, err := r.FormFile("attachment")
+	if errors.Is(err, http.ErrMissingFile) {
+		return commentReq, nil
+	}
+	if err != nil {
+		return models.CommentRequest{}, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return models.CommentRequest{}, err
+	}
+	commentReq.Attachment = &models.CommentUpload{Data: data}
+	return commentReq, nil
+}
+
+// GetComments handles GET and HEAD /films/{id}/comments, taking a limit
+// query parameter, and after to continue from a page's next_cursor.
+// Last-Modified is the page's newest comment's creation time, so polling
+// clients can revalidate with If-Modified-Since. A shadow-banned customer
+// sees their own comments when sending their token, so responses to
+// requests with a token are private to them.
 func (h *FilmHandler) GetComments(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	filmID, err := strconv.Atoi(vars["id"])
+	filmID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
 		return
 	}
 
-	comments, err := h.commentService.GetCommentsByFilmID(r.Context(), filmID)
+	filters := models.CommentFilters{Limit: models.CommentLimits.DefaultLimit}
+	if err = binding.Query(r.URL.Query(), &filters); err != nil {
+		respondWithAppError(w, err, "Invalid query parameter")
+		return
+	}
+
+	comments, err := h.commentService.GetCommentsByFilmID(r.Context(), filmID, filters)
 	if err != nil {
-		if errors.Is(err, repository.ErrFilmNotFound) {
-			respondWithError(w, http.StatusNotFound, "Film not found", err)
-		} else {
-			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve comments", err)
-		}
+		respondWithAppError(w, err, "Failed to retrieve comments")
+		return
+	}
+
+	w.Header().Add("Vary", "Authorization")
+	if r.Header.Get("Authorization") != "" {
+		w.Header().Set("Cache-Control", commentsPrivateCacheControl)
+	}
+	respondWithCacheableJSON(w, r, comments, latestCommentCreated(comments.Items))
+}
+
+// GetCommentSummary handles GET and HEAD /films/{id}/comments/summary.
+// Summaries are recomputed nightly, so Last-Modified is when the film's
+// summary was.
+func (h *FilmHandler) GetCommentSummary(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	summary, err := h.commentService.GetCommentSummary(r.Context(), filmID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve comment summary")
+		return
+	}
+
+	respondWithCacheableJSON(w, r, summary, summary.RefreshedAt)
+}
+
+// LockComments handles PUT /admin/films/{id}/comments:lock, locking or
+// unlocking new comments on a film.
+func (h *FilmHandler) LockComments(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	var lockReq models.CommentLockRequest
+	if err = json.NewDecoder(r.Body).Decode(&lockReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(lockReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	status, err := h.commentService.SetCommentsLocked(r.Context(), filmID, *lockReq.Locked)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to change comment lock")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, status)
+}
+
+// ShadowBanCustomer handles PUT /admin/customers/{id}/shadow-ban,
+// shadow-banning a customer or lifting their ban.
+func (h *FilmHandler) ShadowBanCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	var banReq models.ShadowBanRequest
+	if err = json.NewDecoder(r.Body).Decode(&banReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(banReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	status, err := h.commentService.SetShadowBanned(r.Context(), customerID, *banReq.Banned)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to change shadow ban")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, comments)
+	respondWithJSON(w, http.StatusOK, status)
 }
 
 // WelcomeHandler handles GET /.
@@ -187,6 +394,21 @@ func APIInfoHandler(w http.ResponseWriter, _ *http.Request) {
 }
 
 // Helper functions.
+
+// parseCountParam maps the count query parameter to a FilmFilters count mode.
+// "true" and "false" are accepted as aliases; anything else is passed through
+// for the service to validate.
+func parseCountParam(value string) string {
+	switch strings.ToLower(value) {
+	case "", "true":
+		return ""
+	case "false":
+		return models.CountNone
+	default:
+		return strings.ToLower(value)
+	}
+}
+
 func respondWithJSON(w http.ResponseWriter, code int, payload any) {
 	response, err := json.Marshal(payload)
 	if err != nil {
@@ -201,10 +423,182 @@ func respondWithJSON(w http.ResponseWriter, code int, payload any) {
 	}
 }
 
+// respondWithCacheableJSON writes a 200 JSON response with Content-Length,
+// an ETag over the body and, unless lastModified is zero, Last-Modified. It
+// answers 304 when the request's validators still match and leaves out the
+// body for HEAD requests, so clients can probe a resource cheaply.
+func respondWithCacheableJSON(w http.ResponseWriter, r *http.Request, payload any, lastModified time.Time) {
+	response, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("Failed to marshal JSON response", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithCacheable(w, r, "application/json", response, lastModified)
+}
+
+// respondWithCacheable writes body as a 200 response of contentType with
+// the validators and conditional handling of respondWithCacheableJSON.
+func respondWithCacheable(
+	w http.ResponseWriter,
+	r *http.Request,
+	contentType string,
+	response []byte,
+	lastModified time.Time,
+) {
+	sum := sha256.Sum256(response)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(response)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, writeErr := w.Write(response); writeErr != nil {
+		slog.Error("Failed to write response", "error", writeErr)
+	}
+}
+
+// notModified reports whether the client's cached copy is current.
+// If-None-Match takes precedence over If-Modified-Since, as in RFC 9110.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// Last-Modified is sent with second precision.
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// latestFilmUpdate returns the most recent last_update among films, or the
+// zero time for an empty list.
+func latestFilmUpdate(films []models.Film) time.Time {
+	var latest time.Time
+	for _, film := range films {
+		if film.LastUpdate.After(latest) {
+			latest = film.LastUpdate
+		}
+	}
+	return latest
+}
+
+// latestCommentCreated returns the most recent created_at among comments, or
+// the zero time for an empty list.
+func latestCommentCreated(comments []models.Comment) time.Time {
+	var latest time.Time
+	for _, comment := range comments {
+		if comment.CreatedAt.After(latest) {
+			latest = comment.CreatedAt
+		}
+	}
+	return latest
+}
+
+// serverErrorStatus maps an unexpected service error to 503 when the
+// database is unreachable, so callers and ServeStale can tell it apart from a
+// genuine failure, and to 500 otherwise.
+func serverErrorStatus(err error) int {
+	if repository.IsUnavailable(err) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// errorStatus maps err's kind to an HTTP status. Errors without a kind are
+// server errors.
+func errorStatus(err error) int {
+	switch apperrors.KindOf(err) {
+	case apperrors.NotFound:
+		return http.StatusNotFound
+	case apperrors.Invalid:
+		return http.StatusBadRequest
+	case apperrors.Conflict:
+		return http.StatusConflict
+	case apperrors.Stale:
+		return http.StatusPreconditionFailed
+	default:
+		return serverErrorStatus(err)
+	}
+}
+
+// respondWithAppError writes an error response with the status for err's
+// kind. Errors of a kind are described by their own message, and server
+// errors by message.
+func respondWithAppError(w http.ResponseWriter, err error, message string) {
+	status := errorStatus(err)
+	if kindMessage := apperrors.Message(err); kindMessage != "" && status < http.StatusInternalServerError {
+		message = strings.ToUpper(kindMessage[:1]) + kindMessage[1:]
+	}
+	respondWithError(w, status, message, err)
+}
+
+// errorRecorder is implemented by response writers that forward the cause of
+// server errors to error reporting.
+type errorRecorder interface {
+	RecordError(err error)
+}
+
+// recordServerError hands err to the first errorRecorder in w's wrapper chain.
+func recordServerError(w http.ResponseWriter, err error) {
+	for w != nil {
+		if recorder, ok := w.(errorRecorder); ok {
+			recorder.RecordError(err)
+			return
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = unwrapper.Unwrap()
+	}
+}
+
+// errorCodeCommentsLocked marks comments rejected because the film's
+// comments are locked.
+const errorCodeCommentsLocked = "comments_locked"
+
+// commentsPrivateCacheControl keeps a customer's view of a film's comments,
+// which may include their own shadow-banned comments, out of shared caches.
+const commentsPrivateCacheControl = "private, no-cache"
+
 func respondWithError(w http.ResponseWriter, code int, message string, err error) {
+	respondWithErrorCode(w, code, "", message, err)
+}
+
+// respondWithErrorCode writes an error response carrying a machine-readable
+// errorCode for errors clients are expected to handle.
+func respondWithErrorCode(w http.ResponseWriter, code int, errorCode, message string, err error) {
+	if code >= http.StatusInternalServerError {
+		recordServerError(w, err)
+	}
+
 	errorResponse := models.ErrorResponse{
 		Error:   message,
 		Details: err.Error(),
+		Code:    errorCode,
 	}
 	respondWithJSON(w, code, errorResponse)
 }
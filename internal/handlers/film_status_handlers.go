@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// FilmStatusHandler handles HTTP requests publishing and withdrawing films.
+type FilmStatusHandler struct {
+	filmStatusService service.FilmStatusService
+	validate          *validator.Validate
+}
+
+// NewFilmStatusHandler creates a new film status handler with the given
+// service.
+func NewFilmStatusHandler(filmStatusService service.FilmStatusService) *FilmStatusHandler {
+	return &FilmStatusHandler{
+		filmStatusService: filmStatusService,
+		validate:          validator.New(),
+	}
+}
+
+// SetFilmStatus handles PUT /admin/films/{id}/status.
+func (h *FilmStatusHandler) SetFilmStatus(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	var statusReq models.FilmStatusRequest
+	if err = json.NewDecoder(r.Body).Decode(&statusReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(statusReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	filmStatus, err := h.filmStatusService.SetFilmStatus(r.Context(), filmID, statusReq.Status)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to change film status")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, filmStatus)
+}
+
+// ArchiveFilm handles POST /admin/films/{id}/archive.
+func (h *FilmStatusHandler) ArchiveFilm(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	filmStatus, err := h.filmStatusService.ArchiveFilm(r.Context(), filmID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to archive film")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, filmStatus)
+}
+
+// RestoreFilm handles POST /admin/films/{id}/restore.
+func (h *FilmStatusHandler) RestoreFilm(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	filmStatus, err := h.filmStatusService.RestoreFilm(r.Context(), filmID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to restore film")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, filmStatus)
+}
+
+// ScheduleFilmPublish handles PUT /admin/films/{id}/schedule.
+func (h *FilmStatusHandler) ScheduleFilmPublish(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	var scheduleReq models.FilmScheduleRequest
+	if err = json.NewDecoder(r.Body).Decode(&scheduleReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	filmStatus, err := h.filmStatusService.ScheduleFilmPublish(r.Context(), filmID, scheduleReq.PublishAt)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to schedule film")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, filmStatus)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// FilmUpdateHandler handles HTTP requests editing films.
+type FilmUpdateHandler struct {
+	filmUpdateService service.FilmUpdateService
+	validate          *validator.Validate
+}
+
+// NewFilmUpdateHandler creates a new film update handler with the given
+// service.
+func NewFilmUpdateHandler(filmUpdateService service.FilmUpdateService) *FilmUpdateHandler {
+	return &FilmUpdateHandler{
+		filmUpdateService: filmUpdateService,
+		validate:          validator.New(),
+	}
+}
+
+// UpdateFilm handles PATCH /admin/films/{id}. Requests must send the ETag
+// of GET /admin/films/{id} in If-Match; an edit of a film that has changed
+// since gets 412 rather than overwriting the change, and one without
+// If-Match gets 428.
+func (h *FilmUpdateHandler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		respondWithError(w, http.StatusPreconditionRequired, "If-Match header is required",
+			errors.New("send the film's ETag in an If-Match header"))
+		return
+	}
+	version, ok := parseFilmETag(ifMatch)
+	if !ok {
+		respondWithAppError(w, repository.ErrFilmModified, "Failed to update film")
+		return
+	}
+
+	var updateReq models.FilmUpdateRequest
+	if err = json.NewDecoder(r.Body).Decode(&updateReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(updateReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	film, err := h.filmUpdateService.UpdateFilm(r.Context(), filmID, version, updateReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to update film")
+		return
+	}
+
+	w.Header().Set("ETag", filmETag(film.LastUpdate))
+	respondWithJSON(w, http.StatusOK, film)
+}
+
+// BulkUpdatePrice handles POST /admin/films/bulk-price. The films are
+// selected by the query parameters of GET /admin/films, across every page,
+// and the change is given in the body.
+func (h *FilmUpdateHandler) BulkUpdatePrice(w http.ResponseWriter, r *http.Request) {
+	var filters models.FilmFilters
+	if err := binding.Query(r.URL.Query(), &filters); err != nil {
+		respondWithAppError(w, err, "Invalid query parameter")
+		return
+	}
+
+	var change models.BulkPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(change); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	result, err := h.filmUpdateService.BulkUpdatePrice(r.Context(), filters, change)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to update rental rates")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// filmETag returns the ETag naming a version of a film for If-Match: its
+// last_update in microseconds, the precision it is stored with.
+func filmETag(lastUpdate time.Time) string {
+	return `"` + strconv.FormatInt(lastUpdate.UnixMicro(), 10) + `"`
+}
+
+// parseFilmETag returns the last_update named by an ETag from filmETag. The
+// ETag names the film's version rather than the response bytes, so the W/
+// that compression adds to it is ignored.
+func parseFilmETag(etag string) (time.Time, bool) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return time.Time{}, false
+	}
+	micros, err := strconv.ParseInt(etag[1:len(etag)-1], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(micros), true
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// Error codes for gift cards that cannot be spent.
+const (
+	errorCodeGiftCardInactive = "gift_card_inactive"
+	errorCodeGiftCardEmpty    = "gift_card_empty"
+)
+
+// GiftCardHandler handles HTTP requests for gift cards.
+type GiftCardHandler struct {
+	giftCardService service.GiftCardService
+	validate        *validator.Validate
+}
+
+// NewGiftCardHandler creates a new gift card handler with the given service.
+func NewGiftCardHandler(giftCardService service.GiftCardService) *GiftCardHandler {
+	return &GiftCardHandler{
+		giftCardService: giftCardService,
+		validate:        validator.New(),
+	}
+}
+
+// PurchaseGiftCard handles POST /customers/{id}/gift-cards. The response
+// carries the card's code, which is not shown again.
+func (h *GiftCardHandler) PurchaseGiftCard(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	var purchaseReq models.GiftCardPurchaseRequest
+	if err = json.NewDecoder(r.Body).Decode(&purchaseReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(purchaseReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	card, err := h.giftCardService.PurchaseGiftCard(r.Context(), customerID, purchaseReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to purchase gift card")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, card)
+}
+
+// CheckBalance handles POST /customers/{id}/gift-cards/balance. The code is
+// taken in the body rather than the URL to keep it out of access logs.
+func (h *GiftCardHandler) CheckBalance(w http.ResponseWriter, r *http.Request) {
+	var balanceReq models.GiftCardBalanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&balanceReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(balanceReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	balance, err := h.giftCardService.CheckBalance(r.Context(), balanceReq.Code)
+	if err != nil {
+		respondWithGiftCardError(w, "Failed to check gift card balance", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, balance)
+}
+
+// respondWithGiftCardError maps gift card errors to responses.
+func respondWithGiftCardError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, repository.ErrGiftCardInactive):
+		respondWithErrorCode(w, http.StatusConflict, errorCodeGiftCardInactive, "Gift card has not been paid for", err)
+	case errors.Is(err, repository.ErrGiftCardEmpty):
+		respondWithErrorCode(w, http.StatusConflict, errorCodeGiftCardEmpty, "Gift card has no balance left", err)
+	default:
+		respondWithAppError(w, err, message)
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// GuestHandler handles HTTP requests for anonymous guest sessions.
+type GuestHandler struct {
+	guestService service.GuestService
+}
+
+// NewGuestHandler creates a new guest session handler with the given
+// service.
+func NewGuestHandler(guestService service.GuestService) *GuestHandler {
+	return &GuestHandler{guestService: guestService}
+}
+
+// StartSession handles POST /guest-sessions, issuing a guest token that
+// lets a visitor comment and keep a watchlist before registering.
+func (h *GuestHandler) StartSession(w http.ResponseWriter, r *http.Request) {
+	session, err := h.guestService.StartSession(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to start guest session")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, session)
+}
+
+// GetWatchlist handles GET /guest/watchlist.
+func (h *GuestHandler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.guestService.GetWatchlist(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve watchlist")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, entries)
+}
+
+// AddToWatchlist handles PUT /guest/watchlist/{filmID}.
+func (h *GuestHandler) AddToWatchlist(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("filmID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	if err = h.guestService.AddToWatchlist(r.Context(), filmID); err != nil {
+		respondWithAppError(w, err, "Failed to add film to watchlist")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Film added to watchlist"})
+}
+
+// RemoveFromWatchlist handles DELETE /guest/watchlist/{filmID}.
+func (h *GuestHandler) RemoveFromWatchlist(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("filmID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	if err = h.guestService.RemoveFromWatchlist(r.Context(), filmID); err != nil {
+		respondWithAppError(w, err, "Failed to remove film from watchlist")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Film removed from watchlist"})
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// HomeHandler handles HTTP requests for customers' home feeds.
+type HomeHandler struct {
+	homeService service.HomeService
+}
+
+// NewHomeHandler creates a new home feed handler with the given service.
+func NewHomeHandler(homeService service.HomeService) *HomeHandler {
+	return &HomeHandler{homeService: homeService}
+}
+
+// GetHome handles GET /customers/{id}/home. Sections that could not be
+// loaded in time are listed in incomplete rather than failing the request.
+func (h *HomeHandler) GetHome(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	feed, err := h.homeService.GetHome(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve home feed")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, feed)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// InventoryHandler handles HTTP requests for managing inventory copies.
+type InventoryHandler struct {
+	inventoryService service.InventoryService
+	validate         *validator.Validate
+}
+
+// NewInventoryHandler creates a new inventory handler with the given service.
+func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+		validate:         validator.New(),
+	}
+}
+
+// AddInventory handles POST /admin/films/{id}/inventory.
+func (h *InventoryHandler) AddInventory(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	var inventoryReq models.InventoryRequest
+	if err = json.NewDecoder(r.Body).Decode(&inventoryReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(inventoryReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	added, err := h.inventoryService.AddInventory(r.Context(), filmID, inventoryReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to add inventory")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, added)
+}
+
+// ListFilmInventory handles GET /admin/films/{id}/inventory, optionally
+// filtered by store_id and status.
+func (h *InventoryHandler) ListFilmInventory(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	filters := models.InventoryFilters{Status: r.URL.Query().Get("status")}
+	if storeStr := r.URL.Query().Get("store_id"); storeStr != "" {
+		if filters.StoreID, err = strconv.Atoi(storeStr); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid store ID", err)
+			return
+		}
+	}
+	if err = h.validate.Struct(filters); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	copies, err := h.inventoryService.ListFilmInventory(r.Context(), filmID, filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve inventory")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, copies)
+}
+
+// GetInventoryCopy handles GET /admin/inventory/{id}.
+func (h *InventoryHandler) GetInventoryCopy(w http.ResponseWriter, r *http.Request) {
+	inventoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid inventory ID", err)
+		return
+	}
+
+	inventoryCopy, err := h.inventoryService.GetInventoryCopy(r.Context(), inventoryID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve inventory copy")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, inventoryCopy)
+}
+
+// RetireInventory handles DELETE /admin/inventory/{id}. The copy is kept,
+// marked retired, so its rental history survives.
+func (h *InventoryHandler) RetireInventory(w http.ResponseWriter, r *http.Request) {
+	inventoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid inventory ID", err)
+		return
+	}
+
+	retired, err := h.inventoryService.RetireInventory(r.Context(), inventoryID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retire inventory copy")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, retired)
+}
+
+// TransferInventory handles POST /admin/inventory/{id}/transfer.
+func (h *InventoryHandler) TransferInventory(w http.ResponseWriter, r *http.Request) {
+	inventoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid inventory ID", err)
+		return
+	}
+
+	var transferReq models.InventoryTransferRequest
+	if err = json.NewDecoder(r.Body).Decode(&transferReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(transferReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	transfer, err := h.inventoryService.TransferInventory(r.Context(), inventoryID, transferReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to transfer inventory copy")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, transfer)
+}
+
+// ListInventoryTransfers handles GET /admin/inventory/{id}/transfers.
+func (h *InventoryHandler) ListInventoryTransfers(w http.ResponseWriter, r *http.Request) {
+	inventoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid inventory ID", err)
+		return
+	}
+
+	transfers, err := h.inventoryService.ListInventoryTransfers(r.Context(), inventoryID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve inventory transfers")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, transfers)
+}
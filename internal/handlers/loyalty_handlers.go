@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// defaultLoyaltyHistoryLimit is the number of ledger entries listed per page
+// when no limit is given.
+const defaultLoyaltyHistoryLimit = 20
+
+// LoyaltyHandler handles HTTP requests for loyalty points.
+type LoyaltyHandler struct {
+	loyaltyService service.LoyaltyService
+	validate       *validator.Validate
+}
+
+// NewLoyaltyHandler creates a new loyalty handler with the given service.
+func NewLoyaltyHandler(loyaltyService service.LoyaltyService) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyService: loyaltyService,
+		validate:       validator.New(),
+	}
+}
+
+// GetBalance handles GET /customers/{id}/loyalty.
+func (h *LoyaltyHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	balance, err := h.loyaltyService.GetBalance(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve loyalty balance")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, balance)
+}
+
+// GetHistory handles GET /customers/{id}/loyalty/history, taking page and
+// limit query parameters.
+func (h *LoyaltyHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	filters := models.LoyaltyHistoryFilters{Page: 1, Limit: defaultLoyaltyHistoryLimit}
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		if filters.Page, err = strconv.Atoi(pageStr); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid page", err)
+			return
+		}
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if filters.Limit, err = strconv.Atoi(limitStr); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid limit", err)
+			return
+		}
+	}
+	if err = h.validate.Struct(filters); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	history, err := h.loyaltyService.GetHistory(r.Context(), customerID, filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve loyalty history")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, history)
+}
+
+// RedeemPoints handles POST /customers/{id}/loyalty/redeem, responding with
+// the ledger entry for the redemption.
+func (h *LoyaltyHandler) RedeemPoints(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	var redeemReq models.RedeemPointsRequest
+	if err = json.NewDecoder(r.Body).Decode(&redeemReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(redeemReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	entry, err := h.loyaltyService.RedeemPoints(r.Context(), customerID, redeemReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to redeem loyalty points")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, entry)
+}
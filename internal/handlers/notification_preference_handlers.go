@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// NotificationPreferenceHandler handles HTTP requests for customer
+// notification preferences.
+type NotificationPreferenceHandler struct {
+	prefService service.NotificationPreferenceService
+	validate    *validator.Validate
+}
+
+// NewNotificationPreferenceHandler creates a new notification preference
+// handler with the given service.
+func NewNotificationPreferenceHandler(prefService service.NotificationPreferenceService) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{
+		prefService: prefService,
+		validate:    validator.New(),
+	}
+}
+
+// GetPreferences handles GET /customers/{id}/notification-preferences.
+func (h *NotificationPreferenceHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	prefs, err := h.prefService.GetPreferences(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve notification preferences")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, prefs)
+}
+
+// UpdatePreferences handles PUT /customers/{id}/notification-preferences.
+func (h *NotificationPreferenceHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	var prefReq models.NotificationPreferencesRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&prefReq); decodeErr != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", decodeErr)
+		return
+	}
+	if validateErr := h.validate.Struct(prefReq); validateErr != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", validateErr)
+		return
+	}
+
+	prefs, err := h.prefService.UpdatePreferences(r.Context(), customerID, prefReq.Preferences)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to update notification preferences")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, prefs)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// maxPaymentWebhookBytes caps the size of payment provider callbacks.
+const maxPaymentWebhookBytes = 64 << 10
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key header accepted.
+const maxIdempotencyKeyLength = 255
+
+// PaymentHandler handles HTTP requests for checkout payments.
+type PaymentHandler struct {
+	paymentService service.PaymentService
+	validate       *validator.Validate
+}
+
+// NewPaymentHandler creates a new payment handler with the given service.
+func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		validate:       validator.New(),
+	}
+}
+
+// StartPayment handles POST /customers/{id}/checkouts/{checkoutID}/payment,
+// responding with the provider payment and the client secret used to
+// complete it.
+func (h *PaymentHandler) StartPayment(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+	checkoutID, err := strconv.Atoi(r.PathValue("checkoutID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid checkout ID", err)
+		return
+	}
+
+	payment, err := h.paymentService.StartPayment(r.Context(), customerID, checkoutID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to start payment")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, payment)
+}
+
+// PaymentWebhook handles POST /payments/webhook, the payment provider's
+// callback. Callbacks that fail verification get 400; other failures get
+// 500 so the provider retries.
+func (h *PaymentHandler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPaymentWebhookBytes))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err = h.paymentService.HandleWebhook(r.Context(), payload, r.Header); err != nil {
+		respondWithAppError(w, err, "Failed to process webhook")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Webhook received"})
+}
+
+// RefundPayment handles POST /admin/payments/{id}/refund. Requests must send
+// an Idempotency-Key header; repeating a request with the same key returns
+// the refund it made rather than refunding again. Refunds the provider
+// declines get 502, as do repeats of them. So do refunds the provider did
+// not answer, which stay pending until repeated with the same key.
+func (h *PaymentHandler) RefundPayment(w http.ResponseWriter, r *http.Request) {
+	paymentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid payment ID", err)
+		return
+	}
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" || len(idempotencyKey) > maxIdempotencyKeyLength {
+		respondWithError(w, http.StatusBadRequest, "Invalid idempotency key",
+			errors.New("an Idempotency-Key header of 1 to 255 characters is required"))
+		return
+	}
+
+	var refundReq models.RefundRequest
+	if r.ContentLength != 0 {
+		if err = json.NewDecoder(r.Body).Decode(&refundReq); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+	if err = h.validate.Struct(refundReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	refund, err := h.paymentService.RefundPayment(r.Context(), paymentID, idempotencyKey, refundReq)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrIdempotencyKeyReused):
+			respondWithError(w, http.StatusUnprocessableEntity, "Idempotency key used for a different refund", err)
+		case errors.Is(err, service.ErrRefundFailed):
+			respondWithError(w, http.StatusBadGateway, "Payment provider refund failed", err)
+		case errors.Is(err, service.ErrRefundPending):
+			respondWithError(w, http.StatusBadGateway,
+				"Payment provider did not answer; retry with the same Idempotency-Key", err)
+		default:
+			respondWithAppError(w, err, "Failed to refund payment")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, refund)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// PricingHandler handles HTTP requests for pricing rules and film prices.
+type PricingHandler struct {
+	pricingService service.PricingService
+	validate       *validator.Validate
+}
+
+// NewPricingHandler creates a new pricing handler with the given service.
+func NewPricingHandler(pricingService service.PricingService) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+		validate:       validator.New(),
+	}
+}
+
+// ListRules handles GET /admin/pricing-rules.
+func (h *PricingHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.pricingService.ListRules(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve pricing rules")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rules)
+}
+
+// CreateRule handles POST /admin/pricing-rules.
+func (h *PricingHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	var ruleReq models.PricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&ruleReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(ruleReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	rule, err := h.pricingService.CreateRule(r.Context(), ruleReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to create pricing rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, rule)
+}
+
+// DeleteRule handles DELETE /admin/pricing-rules/{id}.
+func (h *PricingHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pricing rule ID", err)
+		return
+	}
+
+	if err = h.pricingService.DeleteRule(r.Context(), ruleID); err != nil {
+		respondWithAppError(w, err, "Failed to delete pricing rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Pricing rule deleted"})
+}
+
+// GetFilmPrice handles GET /films/{id}/price. With customer_id, rules for
+// the customer's store apply; at takes an RFC 3339 time to price the film
+// at, and defaults to now.
+func (h *PricingHandler) GetFilmPrice(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	query := r.URL.Query()
+	var customerID *int
+	if customerStr := query.Get("customer_id"); customerStr != "" {
+		id, parseErr := strconv.Atoi(customerStr)
+		if parseErr != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid customer ID", parseErr)
+			return
+		}
+		customerID = &id
+	}
+	at := time.Now()
+	if atStr := query.Get("at"); atStr != "" {
+		if at, err = time.Parse(time.RFC3339, atStr); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid at time", err)
+			return
+		}
+	}
+
+	price, err := h.pricingService.GetFilmPrice(r.Context(), filmID, customerID, at)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to price film")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, price)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// ReadinessHandler handles the readiness probe of the load balancer or
+// orchestrator.
+type ReadinessHandler struct {
+	readinessService service.ReadinessService
+}
+
+// NewReadinessHandler creates a new readiness handler with the given service.
+func NewReadinessHandler(readinessService service.ReadinessService) *ReadinessHandler {
+	return &ReadinessHandler{readinessService: readinessService}
+}
+
+// Ready handles GET /readyz, answering 200 while the database is at the
+// schema version this build expects and 503, so the replica is taken out of
+// rotation, while it is not or the database cannot be reached.
+func (h *ReadinessHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	readiness, err := h.readinessService.CheckReadiness(r.Context())
+	if err != nil {
+		// A failing probe is expected while the database is down, so it is
+		// logged rather than reported as a server error.
+		slog.WarnContext(r.Context(), "Readiness check failed", "error", err)
+		respondWithJSON(w, http.StatusServiceUnavailable, models.Readiness{Reason: "database unavailable"})
+		return
+	}
+
+	status := http.StatusOK
+	if !readiness.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	respondWithJSON(w, status, readiness)
+}
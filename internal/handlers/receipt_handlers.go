@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/receipts"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// defaultInvoiceLimit is the number of invoices listed per page when no
+// limit is given.
+const defaultInvoiceLimit = 20
+
+// ReceiptHandler handles HTTP requests for rental receipts and invoices.
+type ReceiptHandler struct {
+	receiptService service.ReceiptService
+	validate       *validator.Validate
+}
+
+// NewReceiptHandler creates a new receipt handler with the given service.
+func NewReceiptHandler(receiptService service.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{
+		receiptService: receiptService,
+		validate:       validator.New(),
+	}
+}
+
+// GetReceipt handles GET /rentals/{id}/receipt. The receipt is an HTML
+// document unless format=json, or an Accept header asking for
+// application/json, selects JSON.
+func (h *ReceiptHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	rentalID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid rental ID", err)
+		return
+	}
+
+	receipt, err := h.receiptService.GetReceipt(r.Context(), rentalID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve receipt")
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		respondWithJSON(w, http.StatusOK, receipt)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = receipts.RenderHTML(&buf, receipt); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to render receipt", err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="receipt-%d.html"`, receipt.RentalID))
+	w.WriteHeader(http.StatusOK)
+	if _, err = buf.WriteTo(w); err != nil {
+		slog.Error("Failed to write receipt response", "error", err)
+	}
+}
+
+// GetCustomerInvoices handles GET /customers/{id}/invoices, taking page and
+// limit query parameters.
+func (h *ReceiptHandler) GetCustomerInvoices(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	filters := models.InvoiceFilters{Page: 1, Limit: defaultInvoiceLimit}
+	if err = binding.Query(r.URL.Query(), &filters); err != nil {
+		respondWithAppError(w, err, "Invalid query parameter")
+		return
+	}
+	if err = binding.Validate(filters); err != nil {
+		respondWithAppError(w, err, "Validation failed")
+		return
+	}
+
+	invoices, err := h.receiptService.GetCustomerInvoices(r.Context(), customerID, filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve invoices")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, invoices)
+}
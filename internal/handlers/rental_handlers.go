@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// dateLayout is the layout of date-only query parameters.
+const dateLayout = "2006-01-02"
+
+// RentalHandler handles HTTP requests for rentals.
+type RentalHandler struct {
+	rentalService service.RentalService
+	validate      *validator.Validate
+}
+
+// NewRentalHandler creates a new rental handler with the given service.
+func NewRentalHandler(rentalService service.RentalService) *RentalHandler {
+	return &RentalHandler{
+		rentalService: rentalService,
+		validate:      validator.New(),
+	}
+}
+
+// GetTrendingFilms handles GET /films/trending.
+func (h *RentalHandler) GetTrendingFilms(w http.ResponseWriter, r *http.Request) {
+	films, err := h.rentalService.GetTrendingFilms(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve trending films")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, films)
+}
+
+// GetFilmRentals handles GET /films/{id}/rentals. The status query parameter
+// is open, returned, or overdue; from and to take an RFC 3339 time or a
+// date, and a date given as to includes that whole day.
+func (h *RentalHandler) GetFilmRentals(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	filters, err := parseRentalHistoryFilters(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	if err = validateRentalHistoryFilters(filters); err != nil {
+		respondWithAppError(w, err, "Validation failed")
+		return
+	}
+
+	history, err := h.rentalService.GetFilmRentals(r.Context(), filmID, filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve film rentals")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, history)
+}
+
+// ReturnRental handles POST /rentals/{id}/return, checking a rented copy
+// back in. Customers waiting for the film are notified.
+func (h *RentalHandler) ReturnRental(w http.ResponseWriter, r *http.Request) {
+	rentalID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid rental ID", err)
+		return
+	}
+
+	rental, err := h.rentalService.ReturnRental(r.Context(), rentalID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to return rental")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rental)
+}
+
+// GetCustomerRentals handles GET /customers/{id}/rentals. It takes the same
+// query parameters as GetFilmRentals.
+func (h *RentalHandler) GetCustomerRentals(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	filters, err := parseRentalHistoryFilters(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	if err = validateRentalHistoryFilters(filters); err != nil {
+		respondWithAppError(w, err, "Validation failed")
+		return
+	}
+
+	history, err := h.rentalService.GetCustomerRentals(r.Context(), customerID, filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve customer rentals")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, history)
+}
+
+// GetOverdueReport handles GET /admin/rentals/overdue. Films are sorted by
+// sort (days, fees, rentals, or title; fees by default) in order (asc or
+// desc; descending by default, ascending for title). With format=csv, or an
+// Accept header asking for text/csv, the films are sent as a CSV download.
+func (h *RentalHandler) GetOverdueReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := models.OverdueReportFilters{
+		Sort:  cmp.Or(query.Get("sort"), models.OverdueSortFees),
+		Order: query.Get("order"),
+	}
+	if filters.Order == "" {
+		filters.Order = "desc"
+		if filters.Sort == models.OverdueSortTitle {
+			filters.Order = "asc"
+		}
+	}
+	if err := h.validate.Struct(filters); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	report, err := h.rentalService.GetOverdueReport(r.Context(), filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve overdue rentals")
+		return
+	}
+
+	if query.Get("format") == "csv" || strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		respondWithOverdueCSV(w, report)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// respondWithOverdueCSV writes the films of report as a CSV attachment, one
+// row per film after a header row.
+func respondWithOverdueCSV(w http.ResponseWriter, report *models.OverdueReport) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition",
+		`attachment; filename="overdue-rentals-`+report.GeneratedAt.Format(dateLayout)+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	rows := [][]string{{"film_id", "title", "overdue_rentals", "total_days_overdue", "max_days_overdue", "accrued_fees"}}
+	for _, film := range report.Films {
+		rows = append(rows, []string{
+			strconv.Itoa(film.FilmID),
+			film.Title,
+			strconv.Itoa(film.OverdueRentals),
+			strconv.Itoa(film.TotalDaysOverdue),
+			strconv.Itoa(film.MaxDaysOverdue),
+			strconv.FormatFloat(film.AccruedFees, 'f', 2, 64),
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		slog.Error("Failed to write CSV response", "error", err)
+	}
+}
+
+// parseRentalHistoryFilters reads the status, page, limit, and date range of
+// a rental history request.
+func parseRentalHistoryFilters(r *http.Request) (models.RentalHistoryFilters, error) {
+	query := r.URL.Query()
+	filters := models.RentalHistoryFilters{Params: models.RentalHistoryLimits.First()}
+
+	err := binding.Query(query, &filters)
+	if err != nil {
+		return filters, err
+	}
+	if filters.From, err = parseTimeParam(query.Get("from"), false); err != nil {
+		return filters, fmt.Errorf("invalid from: %w", err)
+	}
+	if filters.To, err = parseTimeParam(query.Get("to"), true); err != nil {
+		return filters, fmt.Errorf("invalid to: %w", err)
+	}
+	if filters.From != nil && filters.To != nil && !filters.From.Before(*filters.To) {
+		return filters, errors.New("from must be before to")
+	}
+
+	return filters, nil
+}
+
+// validateRentalHistoryFilters checks filters by their validate tags and
+// against the rental history page sizes.
+func validateRentalHistoryFilters(filters models.RentalHistoryFilters) error {
+	if err := binding.Validate(filters); err != nil {
+		return err
+	}
+	return models.RentalHistoryLimits.Check(filters.Params)
+}
+
+// parseTimeParam parses an RFC 3339 time or a date, returning nil for an
+// empty value. With endOfDay set, a date is taken as the start of the next
+// day, so an exclusive upper bound still covers the whole date.
+func parseTimeParam(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil //nolint:nilnil // An empty parameter sets no bound
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected an RFC 3339 time or a YYYY-MM-DD date, got %q", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+)
+
+// NotFound handles requests that match no route with a JSON error instead of
+// the router's plain-text default.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	respondWithError(w, http.StatusNotFound, "Not found",
+		errors.New("no route matches "+r.Method+" "+r.URL.Path))
+}
+
+// MethodNotAllowed handles requests whose path matches a route but whose
+// method does not with a JSON error. The router sets the Allow header,
+// listing the methods the path does support, before calling it.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed",
+		errors.New(r.Method+" is not supported for "+r.URL.Path))
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// SavedSearchHandler handles HTTP requests for customers' saved searches.
+type SavedSearchHandler struct {
+	searchService service.SavedSearchService
+}
+
+// NewSavedSearchHandler creates a new saved search handler with the given
+// service.
+func NewSavedSearchHandler(searchService service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{searchService: searchService}
+}
+
+// ListSavedSearches handles GET /customers/{id}/saved-searches.
+func (h *SavedSearchHandler) ListSavedSearches(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	searches, err := h.searchService.ListSavedSearches(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve saved searches")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, searches)
+}
+
+// CreateSavedSearch handles POST /customers/{id}/saved-searches. The filters
+// are those of GET /films; with notify set, the customer is emailed about
+// matching films published afterwards.
+func (h *SavedSearchHandler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	var searchReq models.SavedSearchRequest
+	if err = json.NewDecoder(r.Body).Decode(&searchReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = binding.Validate(searchReq); err != nil {
+		respondWithAppError(w, err, "Validation failed")
+		return
+	}
+
+	search, err := h.searchService.CreateSavedSearch(r.Context(), customerID, searchReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to save search")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, search)
+}
+
+// DeleteSavedSearch handles DELETE /customers/{id}/saved-searches/{searchID}.
+func (h *SavedSearchHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+	searchID, err := strconv.Atoi(r.PathValue("searchID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid saved search ID", err)
+		return
+	}
+
+	if err = h.searchService.DeleteSavedSearch(r.Context(), customerID, searchID); err != nil {
+		respondWithAppError(w, err, "Failed to delete saved search")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Saved search deleted"})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// SearchHandler handles HTTP requests reporting on film searches.
+type SearchHandler struct {
+	searchService service.SearchService
+}
+
+// NewSearchHandler creates a new search handler with the given service.
+func NewSearchHandler(searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// ListZeroResults handles GET /admin/search/zero-results, listing the title
+// searches that found no films, most often made first.
+func (h *SearchHandler) ListZeroResults(w http.ResponseWriter, r *http.Request) {
+	params := models.SearchMissLimits.First()
+	if err := binding.Query(r.URL.Query(), &params); err != nil {
+		respondWithAppError(w, err, "Invalid query parameter")
+		return
+	}
+	if err := models.SearchMissLimits.Check(params); err != nil {
+		respondWithAppError(w, err, "Validation failed")
+		return
+	}
+
+	misses, err := h.searchService.ListMisses(r.Context(), params)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve zero-result searches")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, misses)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/middleware"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// SessionHandler handles HTTP requests renewing and ending login sessions.
+type SessionHandler struct {
+	sessionService service.SessionService
+}
+
+// NewSessionHandler creates a new login session handler with the given
+// service.
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// Refresh handles POST /auth/refresh, exchanging a refresh token for a new
+// access token and a new refresh token. The refresh token given stops
+// working, and using it again ends the session.
+func (h *SessionHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var refreshReq models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&refreshReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := binding.Validate(refreshReq); err != nil {
+		respondWithAppError(w, err, "Validation failed")
+		return
+	}
+
+	tokens, err := h.sessionService.Refresh(sessionContext(r), refreshReq.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			respondWithError(w, http.StatusUnauthorized, "Refresh failed", err)
+		} else {
+			respondWithAppError(w, err, "Failed to refresh token")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tokens)
+}
+
+// Logout handles POST /auth/logout, revoking the session of the caller's
+// token so neither it nor its refresh token work again.
+func (h *SessionHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.sessionService.Logout(r.Context()); err != nil {
+		respondWithAppError(w, err, "Failed to log out")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Logged out"})
+}
+
+// ListCustomerSessions handles GET /customers/{id}/sessions, listing the
+// devices the customer is logged in on.
+func (h *SessionHandler) ListCustomerSessions(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	sessions, err := h.sessionService.ListCustomerSessions(r.Context(), customerID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve sessions")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, sessions)
+}
+
+// RevokeCustomerSession handles DELETE /customers/{id}/sessions/{sessionID},
+// logging the customer out on that device.
+func (h *SessionHandler) RevokeCustomerSession(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+	sessionID, err := strconv.ParseInt(r.PathValue("sessionID"), 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid session ID", err)
+		return
+	}
+
+	if err = h.sessionService.RevokeCustomerSession(r.Context(), customerID, sessionID); err != nil {
+		respondWithAppError(w, err, "Failed to revoke session")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Session revoked"})
+}
+
+// sessionContext returns r's context carrying the device r came from, which
+// login sessions started or refreshed with it record.
+func sessionContext(r *http.Request) context.Context {
+	return auth.WithSessionClient(r.Context(), models.SessionClient{
+		UserAgent: truncate(r.UserAgent(), maxUserAgentLength),
+		IPAddress: truncate(middleware.ClientAddr(r), maxIPAddressLength),
+	})
+}
+
+// maxUserAgentLength and maxIPAddressLength are the sizes of the columns a
+// session's device is stored in.
+const (
+	maxUserAgentLength = 255
+	maxIPAddressLength = 45
+)
+
+// truncate cuts value to at most length bytes, dropping a rune cut in half.
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return strings.ToValidUTF8(value[:length], "")
+}
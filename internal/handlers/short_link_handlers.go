@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// ShortLinkHandler handles HTTP requests for short links to films.
+type ShortLinkHandler struct {
+	shortLinkService service.ShortLinkService
+	validate         *validator.Validate
+}
+
+// NewShortLinkHandler creates a new short link handler with the given service.
+func NewShortLinkHandler(shortLinkService service.ShortLinkService) *ShortLinkHandler {
+	return &ShortLinkHandler{
+		shortLinkService: shortLinkService,
+		validate:         validator.New(),
+	}
+}
+
+// CreateShortLink handles POST /films/{id}/shortlink. The body is optional
+// and may carry the campaign the link is for.
+func (h *ShortLinkHandler) CreateShortLink(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	var linkReq models.ShortLinkRequest
+	if r.ContentLength != 0 {
+		if err = json.NewDecoder(r.Body).Decode(&linkReq); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+	if err = h.validate.Struct(linkReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	link, err := h.shortLinkService.CreateShortLink(r.Context(), filmID, linkReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to create short link")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, link)
+}
+
+// ListFilmShortLinks handles GET /films/{id}/shortlinks.
+func (h *ShortLinkHandler) ListFilmShortLinks(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	links, err := h.shortLinkService.ListFilmShortLinks(r.Context(), filmID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve short links")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, links)
+}
+
+// FollowShortLink handles GET /f/{code}, counting the click and redirecting
+// to the film.
+func (h *ShortLinkHandler) FollowShortLink(w http.ResponseWriter, r *http.Request) {
+	target, err := h.shortLinkService.FollowShortLink(r.Context(), r.PathValue("code"))
+	if err != nil {
+		respondWithAppError(w, err, "Failed to follow short link")
+		return
+	}
+
+	http.Redirect(w, r, target, http.StatusFound)
+}
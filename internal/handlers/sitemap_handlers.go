@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/service"
+	"github.com/rxbenefits/go-hw/internal/sitemaps"
+)
+
+// sitemapCacheControl lets crawlers and proxies reuse a sitemap for an hour.
+// Sitemaps themselves are refreshed daily.
+const sitemapCacheControl = "public, max-age=3600"
+
+// SitemapHandler handles HTTP requests for sitemaps.
+type SitemapHandler struct {
+	sitemapService service.SitemapService
+}
+
+// NewSitemapHandler creates a new sitemap handler with the given service.
+func NewSitemapHandler(sitemapService service.SitemapService) *SitemapHandler {
+	return &SitemapHandler{sitemapService: sitemapService}
+}
+
+// GetSitemapIndex handles GET and HEAD /sitemap.xml, the index of the film
+// sitemaps.
+func (h *SitemapHandler) GetSitemapIndex(w http.ResponseWriter, r *http.Request) {
+	index, err := h.sitemapService.SitemapIndex(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to build sitemap")
+		return
+	}
+
+	respondWithSitemap(w, r, index)
+}
+
+// GetFilmSitemap handles GET and HEAD /sitemaps/{file}, serving the film
+// sitemaps named films-{page}.xml.
+func (h *SitemapHandler) GetFilmSitemap(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	rawPage, ok := strings.CutPrefix(file, "films-")
+	if ok {
+		rawPage, ok = strings.CutSuffix(rawPage, ".xml")
+	}
+	page, err := strconv.Atoi(rawPage)
+	if !ok || err != nil || page < 1 {
+		respondWithError(w, http.StatusNotFound, "Sitemap not found", errors.New("no sitemap named "+file))
+		return
+	}
+
+	urlSet, err := h.sitemapService.FilmSitemap(r.Context(), page)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to build sitemap")
+		return
+	}
+
+	respondWithSitemap(w, r, urlSet)
+}
+
+// respondWithSitemap writes a URLSet or Index as a cacheable XML document.
+// Its ETag changes whenever the refreshed film list does.
+func respondWithSitemap(w http.ResponseWriter, r *http.Request, document any) {
+	body, err := sitemaps.Marshal(document)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to encode sitemap", err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", sitemapCacheControl)
+	respondWithCacheable(w, r, sitemaps.ContentType, body, time.Time{})
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// StaffHandler handles HTTP requests for staff management and staff login.
+type StaffHandler struct {
+	staffService service.StaffService
+	validate     *validator.Validate
+}
+
+// NewStaffHandler creates a new staff handler with the given service.
+func NewStaffHandler(staffService service.StaffService) *StaffHandler {
+	return &StaffHandler{
+		staffService: staffService,
+		validate:     validator.New(),
+	}
+}
+
+// Login handles POST /staff/login.
+func (h *StaffHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var loginReq models.StaffLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&loginReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(loginReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	token, err := h.staffService.Login(sessionContext(r), loginReq)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) || errors.Is(err, service.ErrTwoFactorRequired) ||
+			errors.Is(err, service.ErrInvalidTwoFactorCode) {
+			respondWithError(w, http.StatusUnauthorized, "Login failed", err)
+		} else {
+			respondWithAppError(w, err, "Failed to log in")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, token)
+}
+
+// ListStaff handles GET /staff.
+func (h *StaffHandler) ListStaff(w http.ResponseWriter, r *http.Request) {
+	staff, err := h.staffService.ListStaff(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve staff")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, staff)
+}
+
+// CreateStaff handles POST /staff.
+func (h *StaffHandler) CreateStaff(w http.ResponseWriter, r *http.Request) {
+	var staffReq models.StaffRequest
+	if err := json.NewDecoder(r.Body).Decode(&staffReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(staffReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	member, err := h.staffService.CreateStaff(r.Context(), staffReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to create staff")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, member)
+}
+
+// DeactivateStaff handles POST /staff/{id}/deactivate.
+func (h *StaffHandler) DeactivateStaff(w http.ResponseWriter, r *http.Request) {
+	staffID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid staff ID", err)
+		return
+	}
+
+	member, err := h.staffService.DeactivateStaff(r.Context(), staffID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to deactivate staff")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, member)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// TagHandler handles HTTP requests for free-form film tags.
+type TagHandler struct {
+	tagService service.TagService
+	validate   *validator.Validate
+}
+
+// NewTagHandler creates a new tag handler with the given service.
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+		validate:   validator.New(),
+	}
+}
+
+// ListTags handles GET /tags.
+func (h *TagHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.tagService.ListTags(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve tags")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tags)
+}
+
+// TagFilm handles POST /admin/films/{id}/tags.
+func (h *TagHandler) TagFilm(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	var tagReq models.FilmTagRequest
+	if err = json.NewDecoder(r.Body).Decode(&tagReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(tagReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	filmTags, err := h.tagService.TagFilm(r.Context(), filmID, tagReq.Tag)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to tag film")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, filmTags)
+}
+
+// UntagFilm handles DELETE /admin/films/{id}/tags/{tag}.
+func (h *TagHandler) UntagFilm(w http.ResponseWriter, r *http.Request) {
+	filmID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid film ID", err)
+		return
+	}
+
+	filmTags, err := h.tagService.UntagFilm(r.Context(), filmID, r.PathValue("tag"))
+	if err != nil {
+		respondWithAppError(w, err, "Failed to untag film")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, filmTags)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// TwoFactorHandler handles HTTP requests for staff two-factor enrollment.
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+	validate         *validator.Validate
+}
+
+// NewTwoFactorHandler creates a new two-factor handler with the given service.
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		validate:         validator.New(),
+	}
+}
+
+// Enroll handles POST /staff/{id}/two-factor.
+func (h *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	staffID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid staff ID", err)
+		return
+	}
+
+	enrollment, err := h.twoFactorService.EnrollTwoFactor(r.Context(), staffID)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to enroll in two-factor authentication")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, enrollment)
+}
+
+// Confirm handles POST /staff/{id}/two-factor/confirm.
+func (h *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	staffID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid staff ID", err)
+		return
+	}
+
+	var codeReq models.TwoFactorCodeRequest
+	if err = json.NewDecoder(r.Body).Decode(&codeReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err = h.validate.Struct(codeReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	if err = h.twoFactorService.ConfirmTwoFactor(r.Context(), staffID, codeReq.Code); err != nil {
+		respondWithAppError(w, err, "Failed to confirm two-factor authentication")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Two-factor authentication enabled"})
+}
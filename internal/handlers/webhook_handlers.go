@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// defaultDeliveryListLimit is the number of deliveries listed when no limit
+// is given.
+const defaultDeliveryListLimit = 50
+
+// WebhookHandler handles HTTP requests for managing webhook subscriptions.
+type WebhookHandler struct {
+	webhookService service.WebhookService
+	validate       *validator.Validate
+}
+
+// NewWebhookHandler creates a new webhook handler with the given service.
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		validate:       validator.New(),
+	}
+}
+
+// ListSubscriptions handles GET /admin/webhooks.
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhookService.ListSubscriptions(r.Context())
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve webhook subscriptions")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, subscriptions)
+}
+
+// CreateSubscription handles POST /admin/webhooks.
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var subscriptionReq models.WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&subscriptionReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validate.Struct(subscriptionReq); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(r.Context(), subscriptionReq)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to create webhook subscription")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, subscription)
+}
+
+// DeleteSubscription handles DELETE /admin/webhooks/{id}.
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook ID", err)
+		return
+	}
+
+	if err = h.webhookService.DeleteSubscription(r.Context(), subscriptionID); err != nil {
+		respondWithWebhookError(w, "Failed to delete webhook subscription", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.MessageResponse{Message: "Webhook subscription deleted"})
+}
+
+// RotateSecret handles POST /admin/webhooks/{id}/rotate-secret.
+func (h *WebhookHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook ID", err)
+		return
+	}
+
+	subscription, err := h.webhookService.RotateSecret(r.Context(), subscriptionID)
+	if err != nil {
+		respondWithWebhookError(w, "Failed to rotate webhook secret", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, subscription)
+}
+
+// ListDeliveries handles GET /admin/webhooks/{id}/deliveries.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook ID", err)
+		return
+	}
+
+	filters := models.WebhookDeliveryFilters{
+		Status: r.URL.Query().Get("status"),
+		Limit:  defaultDeliveryListLimit,
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, limitErr := strconv.Atoi(limitStr)
+		if limitErr != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid limit", limitErr)
+			return
+		}
+		filters.Limit = limit
+	}
+	if err = h.validate.Struct(filters); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), subscriptionID, filters)
+	if err != nil {
+		respondWithAppError(w, err, "Failed to retrieve webhook deliveries")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, deliveries)
+}
+
+// ReplayDelivery handles POST /admin/webhooks/{id}/deliveries/{deliveryID}/replay.
+func (h *WebhookHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook ID", err)
+		return
+	}
+	deliveryID, err := strconv.ParseInt(r.PathValue("deliveryID"), 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid delivery ID", err)
+		return
+	}
+
+	delivery, err := h.webhookService.ReplayDelivery(r.Context(), subscriptionID, deliveryID)
+	if err != nil {
+		respondWithWebhookError(w, "Failed to replay webhook delivery", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, delivery)
+}
+
+// respondWithWebhookError maps webhook service errors to responses.
+func respondWithWebhookError(w http.ResponseWriter, message string, err error) {
+	respondWithAppError(w, err, message)
+}
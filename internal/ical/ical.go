@@ -0,0 +1,100 @@
+// Package ical writes iCalendar (RFC 5545) documents for calendar apps to
+// subscribe to.
+package ical
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type calendars are served with.
+const ContentType = "text/calendar; charset=utf-8"
+
+// productID identifies the program that wrote a calendar.
+const productID = "-//Mockbuster//Mockbuster Movie API//EN"
+
+// maxLineLength is the longest a content line may be, in octets, before it
+// is folded onto the next.
+const maxLineLength = 75
+
+// utcFormat is the form of a date-time in UTC.
+const utcFormat = "20060102T150405Z"
+
+// Calendar is an iCalendar document.
+type Calendar struct {
+	// Name is shown by calendar apps for the subscription.
+	Name   string
+	Events []Event
+}
+
+// Event is an event in a calendar. Times are written in UTC; an event
+// without an End lasts no time.
+type Event struct {
+	UID         string
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	URL         string
+}
+
+// Marshal encodes cal as an iCalendar document, with CRLF line endings and
+// long lines folded.
+func Marshal(cal Calendar) []byte {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+productID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if cal.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(cal.Name))
+	}
+	for _, event := range cal.Events {
+		end := event.End
+		if end.IsZero() {
+			end = event.Start
+		}
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+event.UID)
+		writeLine(&b, "DTSTAMP:"+event.Stamp.UTC().Format(utcFormat))
+		writeLine(&b, "DTSTART:"+event.Start.UTC().Format(utcFormat))
+		writeLine(&b, "DTEND:"+end.UTC().Format(utcFormat))
+		writeLine(&b, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		if event.URL != "" {
+			writeLine(&b, "URL:"+event.URL)
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// escapeText escapes the characters with meaning in TEXT values.
+func escapeText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// writeLine writes a content line, folding it so no line exceeds
+// maxLineLength octets. Folds fall between characters, never inside one.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward their length.
+		limit = maxLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+)
+
+// PostgresLeaser coordinates scheduled jobs across replicas sharing a
+// database. A lease holds a transaction-scoped advisory lock for the whole
+// run, and records the start time in scheduled_job_runs so a replica whose
+// ticker fires just after another's run does not repeat it.
+type PostgresLeaser struct {
+	db *database.DB
+}
+
+// NewPostgresLeaser creates a leaser backed by db.
+func NewPostgresLeaser(db *database.DB) *PostgresLeaser {
+	return &PostgresLeaser{db: db}
+}
+
+// TryAcquire claims name unless another replica holds it or started it
+// within interval.
+func (l *PostgresLeaser) TryAcquire(
+	ctx context.Context,
+	name string,
+	interval time.Duration,
+) (func(), bool, error) {
+	ctx = database.WithQueryName(ctx, "scheduler.lease")
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("error starting lease transaction: %w", err)
+	}
+
+	var locked bool
+	if err = tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock(hashtext($1))", name).Scan(&locked); err != nil {
+		_ = tx.Rollback()
+		return nil, false, fmt.Errorf("error acquiring advisory lock: %w", err)
+	}
+	if !locked {
+		_ = tx.Rollback()
+		return nil, false, nil
+	}
+
+	// Leave a little slack so ticker jitter does not skip a whole interval.
+	cutoff := time.Now().Add(-interval * 9 / 10) //nolint:mnd // 90% of the interval
+	var claimed string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO scheduled_job_runs (name, last_started_at) VALUES ($1, NOW())
+		ON CONFLICT (name) DO UPDATE SET last_started_at = NOW()
+		WHERE scheduled_job_runs.last_started_at <= $2
+		RETURNING name`, name, cutoff,
+	).Scan(&claimed)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("error recording scheduled job run: %w", err)
+	}
+
+	release := func() {
+		if commitErr := tx.Commit(); commitErr != nil {
+			slog.Error("Failed to release scheduled job lease", "job", name, "error", commitErr)
+		}
+	}
+	return release, true, nil
+}
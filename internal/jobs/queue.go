@@ -0,0 +1,264 @@
+// Package jobs runs background work off the request path.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/metrics"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// ErrUnknownKind is returned when enqueueing a job of a kind with no handler.
+var ErrUnknownKind = errors.New("no handler registered for job kind")
+
+// ErrQueueClosed is returned when a job is enqueued after Shutdown.
+var ErrQueueClosed = errors.New("job queue is closed")
+
+// Handler runs one job of a kind, given the payload it was enqueued with. It
+// should respect ctx cancellation, and may run more than once for a job if a
+// worker dies mid-run, so it should be idempotent where that matters.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// RetryPolicy controls how often a failing job is attempted. Retries back off
+// exponentially from Backoff, capped at MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy attempts a job five times over roughly eight minutes.
+var DefaultRetryPolicy = RetryPolicy{ //nolint:gochecknoglobals // Read-only default
+	MaxAttempts: 5,
+	Backoff:     30 * time.Second,
+	MaxBackoff:  time.Hour,
+}
+
+// delay returns how long to wait before the attempt after the given one.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for range attempt - 1 {
+		delay *= 2
+		if delay >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// Store persists queued jobs.
+type Store interface {
+	// InsertJob adds a pending job.
+	InsertJob(ctx context.Context, kind string, payload []byte, maxAttempts int) (*models.BackgroundJob, error)
+	// ClaimJob marks the next due job as running, returning nil if none is due.
+	ClaimJob(ctx context.Context, kinds []string, staleAfter time.Duration) (*models.BackgroundJob, error)
+	// CompleteJob marks a running job as succeeded.
+	CompleteJob(ctx context.Context, jobID int64) error
+	// RetryJobLater returns a failed job to pending, to run again at runAt.
+	RetryJobLater(ctx context.Context, jobID int64, lastError string, runAt time.Time) error
+	// BuryJob marks a job that has run out of attempts as dead.
+	BuryJob(ctx context.Context, jobID int64, lastError string) error
+}
+
+type registration struct {
+	handler Handler
+	policy  RetryPolicy
+}
+
+// Queue runs persisted jobs on a fixed pool of workers. Jobs survive restarts
+// and are shared by every replica using the same store; a failing job is
+// retried per its kind's RetryPolicy and then left dead for an admin to
+// inspect and retry.
+type Queue struct {
+	store        Store
+	workers      int
+	pollInterval time.Duration
+	jobTimeout   time.Duration
+
+	handlers map[string]registration
+	kinds    []string
+
+	mu      sync.RWMutex
+	closed  bool
+	wake    chan struct{}
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewQueue creates a queue backed by store. Once started, workers goroutines
+// poll for due jobs every pollInterval and run each with jobTimeout.
+func NewQueue(store Store, workers int, pollInterval, jobTimeout time.Duration) *Queue {
+	return &Queue{
+		store:        store,
+		workers:      workers,
+		pollInterval: pollInterval,
+		jobTimeout:   jobTimeout,
+		handlers:     make(map[string]registration),
+		wake:         make(chan struct{}, workers),
+		stop:         make(chan struct{}),
+	}
+}
+
+// Handle registers handler for jobs of kind. It must be called before Start.
+func (q *Queue) Handle(kind string, handler Handler, policy RetryPolicy) {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	q.handlers[kind] = registration{handler: handler, policy: policy}
+	q.kinds = append(q.kinds, kind)
+}
+
+// Enqueue persists a job of kind with payload encoded as JSON. The job is
+// stored even if the request that queued it has since been cancelled.
+func (q *Queue) Enqueue(kind string, payload any) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	reg, ok := q.handlers[kind]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding job payload: %w", err)
+	}
+	if _, err = q.store.InsertJob(context.Background(), kind, encoded, reg.policy.MaxAttempts); err != nil {
+		return fmt.Errorf("error enqueueing job: %w", err)
+	}
+	metrics.JobsProcessed.WithLabelValues(kind, "enqueued").Inc()
+
+	// Nudge an idle worker so the job does not wait for the next poll.
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start launches the workers.
+func (q *Queue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started || q.closed {
+		return
+	}
+	q.started = true
+	for range q.workers {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+// Shutdown stops accepting and claiming jobs and waits for running ones to
+// finish or for ctx to be done. Pending jobs stay in the store for the next
+// start.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.stop)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	// A job left running for twice the job timeout was abandoned by a worker
+	// that died.
+	staleAfter := 2 * q.jobTimeout //nolint:mnd // Generous margin over jobTimeout
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		default:
+		}
+
+		job, err := q.store.ClaimJob(context.Background(), q.kinds, staleAfter)
+		if err != nil {
+			slog.Error("Failed to claim background job", "error", err)
+		}
+		if job != nil {
+			q.run(job)
+			continue
+		}
+
+		select {
+		case <-q.stop:
+			return
+		case <-q.wake:
+		case <-time.After(q.pollInterval):
+		}
+	}
+}
+
+func (q *Queue) run(job *models.BackgroundJob) {
+	reg := q.handlers[job.Kind]
+	err := q.call(reg.handler, job)
+	if err == nil {
+		metrics.JobsProcessed.WithLabelValues(job.Kind, "success").Inc()
+		if completeErr := q.store.CompleteJob(context.Background(), job.ID); completeErr != nil {
+			slog.Error("Failed to mark background job complete", "job", job.Kind, "id", job.ID, "error", completeErr)
+		}
+		return
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		metrics.JobsProcessed.WithLabelValues(job.Kind, "dead").Inc()
+		slog.Error("Background job failed permanently",
+			"job", job.Kind, "id", job.ID, "attempts", job.Attempts, "error", err)
+		if buryErr := q.store.BuryJob(context.Background(), job.ID, err.Error()); buryErr != nil {
+			slog.Error("Failed to mark background job dead", "job", job.Kind, "id", job.ID, "error", buryErr)
+		}
+		return
+	}
+
+	runAt := time.Now().Add(reg.policy.delay(job.Attempts))
+	metrics.JobsProcessed.WithLabelValues(job.Kind, "retry").Inc()
+	slog.Warn("Background job failed, will retry",
+		"job", job.Kind, "id", job.ID, "attempts", job.Attempts, "retry_at", runAt, "error", err)
+	if retryErr := q.store.RetryJobLater(context.Background(), job.ID, err.Error(), runAt); retryErr != nil {
+		slog.Error("Failed to reschedule background job", "job", job.Kind, "id", job.ID, "error", retryErr)
+	}
+}
+
+// call runs handler with the job timeout, converting a panic into an error.
+func (q *Queue) call(handler Handler, job *models.BackgroundJob) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), q.jobTimeout)
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	if handler == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownKind, job.Kind)
+	}
+	return handler(ctx, job.Payload)
+}
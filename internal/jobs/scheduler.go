@@ -0,0 +1,122 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/metrics"
+)
+
+// ScheduledJob is a job run periodically by a Scheduler.
+type ScheduledJob struct {
+	// Name identifies the job in logs, metrics, and leases.
+	Name string
+	// Interval is the time between runs; zero or negative disables the job.
+	Interval time.Duration
+	// Run does the work; it should respect ctx cancellation.
+	Run func(ctx context.Context) error
+}
+
+// Leaser grants the right to run a scheduled job so that only one replica
+// runs it per interval.
+type Leaser interface {
+	// TryAcquire claims name for a run, returning ok=false if another replica
+	// holds it or ran it within interval. release must be called after the run.
+	TryAcquire(ctx context.Context, name string, interval time.Duration) (release func(), ok bool, err error)
+}
+
+// Scheduler runs registered jobs on their intervals until its context ends.
+type Scheduler struct {
+	leaser  Leaser
+	timeout time.Duration
+	jobs    []ScheduledJob
+	wg      sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler coordinating runs through leaser. Each run
+// is bounded by its job's interval or timeout, whichever is shorter.
+func NewScheduler(leaser Leaser, timeout time.Duration) *Scheduler {
+	return &Scheduler{leaser: leaser, timeout: timeout}
+}
+
+// Register adds job to the schedule. It must be called before Start.
+func (s *Scheduler) Register(job ScheduledJob) {
+	if job.Interval <= 0 {
+		slog.Info("Scheduled job disabled", "job", job.Name)
+		return
+	}
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs every registered job once and then on its interval, until ctx
+// is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Wait blocks until every job loop has stopped after Start's ctx is done.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job ScheduledJob) {
+	defer s.wg.Done()
+	slog.Info("Scheduled job started", "job", job.Name, "interval", job.Interval)
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		s.RunNow(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunNow runs job once if its lease can be acquired, recording the outcome.
+func (s *Scheduler) RunNow(ctx context.Context, job ScheduledJob) {
+	release, ok, err := s.leaser.TryAcquire(ctx, job.Name, job.Interval)
+	if err != nil {
+		metrics.ScheduledJobRuns.WithLabelValues(job.Name, "error").Inc()
+		slog.Error("Failed to acquire scheduled job lease", "job", job.Name, "error", err)
+		return
+	}
+	if !ok {
+		metrics.ScheduledJobRuns.WithLabelValues(job.Name, "skipped").Inc()
+		slog.Debug("Scheduled job skipped, running elsewhere", "job", job.Name)
+		return
+	}
+	defer release()
+
+	runCtx, cancel := context.WithTimeout(ctx, min(job.Interval, s.timeout))
+	defer cancel()
+
+	start := time.Now()
+	err = s.run(runCtx, job)
+	metrics.ScheduledJobDuration.WithLabelValues(job.Name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.ScheduledJobRuns.WithLabelValues(job.Name, "error").Inc()
+		slog.Error("Scheduled job failed", "job", job.Name, "error", err)
+		return
+	}
+	metrics.ScheduledJobRuns.WithLabelValues(job.Name, "success").Inc()
+	slog.Info("Scheduled job finished", "job", job.Name, "duration", time.Since(start))
+}
+
+// run calls job.Run, converting a panic into a failed run.
+func (s *Scheduler) run(ctx context.Context, job ScheduledJob) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return job.Run(ctx)
+}
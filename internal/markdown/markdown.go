@@ -0,0 +1,49 @@
+// Package markdown renders the Markdown subset accepted in comments to
+// sanitized HTML.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"log/slog"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Renderer converts Markdown to HTML and sanitizes the result. Raw HTML in
+// the source is dropped, and only emphasis, inline and block code, links,
+// lists, quotes, and strikethrough survive sanitization; other elements,
+// such as headings and images, are reduced to their text.
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+}
+
+// NewRenderer creates a renderer for the comment Markdown subset.
+func NewRenderer() *Renderer {
+	policy := bluemonday.NewPolicy()
+	policy.AllowElements("p", "br", "em", "strong", "del", "code", "pre", "blockquote", "ul", "ol", "li")
+	policy.AllowAttrs("href").OnElements("a")
+	policy.AllowURLSchemes("http", "https", "mailto")
+	policy.RequireParseableURLs(true)
+	policy.RequireNoFollowOnLinks(true)
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return &Renderer{
+		markdown: goldmark.New(goldmark.WithExtensions(extension.Strikethrough, extension.Linkify)),
+		policy:   policy,
+	}
+}
+
+// Render returns source rendered to sanitized HTML. Should rendering fail,
+// the escaped source is returned instead.
+func (r *Renderer) Render(source string) string {
+	var buf bytes.Buffer
+	if err := r.markdown.Convert([]byte(source), &buf); err != nil {
+		slog.Error("Failed to render Markdown", "error", err)
+		return html.EscapeString(source)
+	}
+	return r.policy.Sanitize(buf.String())
+}
@@ -0,0 +1,210 @@
+// Package metrics defines the Prometheus metrics exported by the Mockbuster API.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Registry holds every metric exported by the API. A dedicated registry keeps
+// tests and the /metrics output free of global state from other packages.
+var Registry = prometheus.NewRegistry() //nolint:gochecknoglobals // Process-wide metric registry
+
+// DBQueryDuration records database query latency by query name.
+var DBQueryDuration = prometheus.NewHistogramVec( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.HistogramOpts{
+		Namespace: "mockbuster",
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of database queries issued by the repositories.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	},
+	[]string{"query"},
+)
+
+// DBSlowQueries counts queries that exceeded the slow-query threshold.
+var DBSlowQueries = prometheus.NewCounterVec( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.CounterOpts{
+		Namespace: "mockbuster",
+		Subsystem: "db",
+		Name:      "slow_queries_total",
+		Help:      "Number of database queries slower than the configured threshold.",
+	},
+	[]string{"query"},
+)
+
+// JobsProcessed counts background jobs by kind and outcome: enqueued,
+// success, retry, or dead.
+var JobsProcessed = prometheus.NewCounterVec( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.CounterOpts{
+		Namespace: "mockbuster",
+		Subsystem: "jobs",
+		Name:      "processed_total",
+		Help:      "Number of background jobs run, by job name and status.",
+	},
+	[]string{"job", "status"},
+)
+
+// ScheduledJobRuns counts scheduled job runs by job name and outcome; runs
+// skipped because another replica holds the job are counted as "skipped".
+var ScheduledJobRuns = prometheus.NewCounterVec( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.CounterOpts{
+		Namespace: "mockbuster",
+		Subsystem: "scheduler",
+		Name:      "runs_total",
+		Help:      "Number of scheduled job runs, by job name and status.",
+	},
+	[]string{"job", "status"},
+)
+
+// ScheduledJobDuration records how long scheduled jobs take.
+var ScheduledJobDuration = prometheus.NewHistogramVec( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.HistogramOpts{
+		Namespace: "mockbuster",
+		Subsystem: "scheduler",
+		Name:      "run_duration_seconds",
+		Help:      "Duration of scheduled job runs.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8), //nolint:mnd // 10ms to ~160s
+	},
+	[]string{"job"},
+)
+
+// HTTPInFlightRequests is the number of HTTP requests being served, excluding
+// those waiting for a slot under the concurrency limit.
+var HTTPInFlightRequests = prometheus.NewGauge( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.GaugeOpts{
+		Namespace: "mockbuster",
+		Subsystem: "http",
+		Name:      "in_flight_requests",
+		Help:      "Number of HTTP requests currently being served.",
+	},
+)
+
+// HTTPShedRequests counts requests rejected because the concurrency limit
+// stayed full for the whole queue timeout.
+var HTTPShedRequests = prometheus.NewCounter( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.CounterOpts{
+		Namespace: "mockbuster",
+		Subsystem: "http",
+		Name:      "shed_requests_total",
+		Help:      "Number of HTTP requests rejected with 503 under load.",
+	},
+)
+
+// HTTPResponses counts HTTP responses by status class, such as "2xx".
+var HTTPResponses = prometheus.NewCounterVec( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.CounterOpts{
+		Namespace: "mockbuster",
+		Subsystem: "http",
+		Name:      "responses_total",
+		Help:      "Number of HTTP responses by status class.",
+	},
+	[]string{"class"},
+)
+
+// CommentsAdded counts comments posted, by the ID of the film commented on.
+// The label is bounded by the size of the catalog.
+var CommentsAdded = prometheus.NewCounterVec( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.CounterOpts{
+		Namespace: "mockbuster",
+		Subsystem: "comments",
+		Name:      "added_total",
+		Help:      "Number of comments added, by film.",
+	},
+	[]string{"film_id"},
+)
+
+// FilmSearches counts film listings that filter the catalog, counted on
+// their first page, by whether anything matched: "found" or "empty".
+var FilmSearches = prometheus.NewCounterVec( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.CounterOpts{
+		Namespace: "mockbuster",
+		Subsystem: "films",
+		Name:      "searches_total",
+		Help:      "Number of film searches, by whether they found any films.",
+	},
+	[]string{"result"},
+)
+
+// FilmLookupsNotFound counts lookups of films that do not exist.
+var FilmLookupsNotFound = prometheus.NewCounter( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.CounterOpts{
+		Namespace: "mockbuster",
+		Subsystem: "films",
+		Name:      "lookups_not_found_total",
+		Help:      "Number of lookups of films that do not exist.",
+	},
+)
+
+// RateLimitRejections counts requests rejected for an API key's limits, by
+// key tier and the limit hit: "rate" or "quota".
+var RateLimitRejections = prometheus.NewCounterVec( //nolint:gochecknoglobals // Process-wide metric
+	prometheus.CounterOpts{
+		Namespace: "mockbuster",
+		Subsystem: "api_keys",
+		Name:      "rejections_total",
+		Help:      "Number of requests rejected for exceeding an API key's rate limit or quota.",
+	},
+	[]string{"tier", "limit"},
+)
+
+// statusClasses lists the HTTP status classes HTTPResponses is labelled with.
+var statusClasses = []string{"1xx", "2xx", "3xx", "4xx", "5xx"} //nolint:gochecknoglobals // Read-only lookup table
+
+// StatusClass returns the class HTTPResponses counts status under, such as
+// "4xx" for 404.
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "5xx"
+	}
+	return statusClasses[status/100-1]
+}
+
+// HTTPErrorRate returns the share of HTTP responses counted by
+// HTTPResponses that were 5xx, or 0 before any response.
+func HTTPErrorRate() float64 {
+	var total, serverErrors float64
+	for _, class := range statusClasses {
+		var m dto.Metric
+		if err := HTTPResponses.WithLabelValues(class).Write(&m); err != nil {
+			continue
+		}
+		count := m.GetCounter().GetValue()
+		total += count
+		if class == "5xx" {
+			serverErrors = count
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return serverErrors / total
+}
+
+func init() { //nolint:gochecknoinits // Registering metrics once at startup
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		DBQueryDuration,
+		DBSlowQueries,
+		JobsProcessed,
+		ScheduledJobRuns,
+		ScheduledJobDuration,
+		HTTPInFlightRequests,
+		HTTPShedRequests,
+		HTTPResponses,
+		CommentsAdded,
+		FilmSearches,
+		FilmLookupsNotFound,
+		RateLimitRejections,
+	)
+}
+
+// Handler serves the registered metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
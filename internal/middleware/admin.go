@@ -0,0 +1,53 @@
+// Package middleware provides HTTP middleware for the Mockbuster API.
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// RequireAdminToken rejects requests whose Authorization header does not carry
+// the given bearer token.
+func RequireAdminToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				writeError(w, http.StatusUnauthorized, "Unauthorized", "a valid admin token is required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeError writes an ErrorResponse, mirroring the handlers package.
+func writeError(w http.ResponseWriter, code int, message, details string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	response := models.ErrorResponse{Error: message, Details: details}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to write error response", "error", err)
+	}
+}
+
+// recordServerError hands err to the first writer in w's wrapper chain that
+// forwards errors to error reporting, mirroring the handlers package.
+func recordServerError(w http.ResponseWriter, err error) {
+	for w != nil {
+		if recorder, ok := w.(interface{ RecordError(err error) }); ok {
+			recorder.RecordError(err)
+			return
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = unwrapper.Unwrap()
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/metrics"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// APIKeyVerifier authenticates API keys and meters their monthly usage. It
+// is implemented by service.APIKeyService.
+type APIKeyVerifier interface {
+	Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error)
+	RecordRequest(ctx context.Context, key *models.APIKey) (*models.APIKeyUsage, bool, error)
+}
+
+// APIKeyAuth enforces the limits of the tier of the key in the X-API-Key
+// header: requests over the per-minute rate limit or the monthly quota are
+// rejected with 429 and a Retry-After header. Requests without a key pass
+// through anonymously; unknown or revoked keys are rejected with 401. The
+// key is stored on the request context.
+func APIKeyAuth(verifier APIKeyVerifier, limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rawKey := r.Header.Get(auth.APIKeyHeader)
+			if rawKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key, err := verifier.Authenticate(r.Context(), rawKey)
+			if errors.Is(err, auth.ErrInvalidAPIKey) {
+				writeError(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+				return
+			}
+			if err != nil {
+				writeVerifierError(w, "Failed to authenticate API key", err)
+				return
+			}
+
+			limits, _ := models.TierLimits(key.Tier)
+			if limits.RequestsPerMinute > 0 {
+				allowed, remaining, resetsAt := limiter.Allow(strconv.Itoa(key.ID), limits.RequestsPerMinute)
+				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limits.RequestsPerMinute))
+				w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+				if !allowed {
+					metrics.RateLimitRejections.WithLabelValues(key.Tier, "rate").Inc()
+					setRetryAfter(w, resetsAt)
+					writeError(w, http.StatusTooManyRequests, "Rate limit exceeded",
+						"the "+key.Tier+" tier allows "+strconv.Itoa(limits.RequestsPerMinute)+" requests per minute")
+					return
+				}
+			}
+
+			usage, allowed, err := verifier.RecordRequest(r.Context(), key)
+			if err != nil {
+				writeVerifierError(w, "Failed to record API key usage", err)
+				return
+			}
+			if !allowed {
+				metrics.RateLimitRejections.WithLabelValues(key.Tier, "quota").Inc()
+				setRetryAfter(w, usage.ResetsAt)
+				writeError(w, http.StatusTooManyRequests, "Monthly quota exceeded",
+					"the "+key.Tier+" tier allows "+strconv.FormatInt(limits.MonthlyQuota, 10)+" requests per month")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithAPIKey(r.Context(), key)))
+		})
+	}
+}
+
+// setRetryAfter sets Retry-After to the whole seconds until resetsAt.
+func setRetryAfter(w http.ResponseWriter, resetsAt time.Time) {
+	seconds := max(int(math.Ceil(time.Until(resetsAt).Seconds())), 1)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
+
+// writeVerifierError answers a failed key lookup with 503 when the database
+// is unavailable and 500 otherwise, forwarding the cause to error reporting.
+func writeVerifierError(w http.ResponseWriter, message string, err error) {
+	slog.Error(message, "error", err)
+
+	code := http.StatusInternalServerError
+	if repository.IsUnavailable(err) {
+		code = http.StatusServiceUnavailable
+	}
+	recordServerError(w, err)
+	writeError(w, code, message, err.Error())
+}
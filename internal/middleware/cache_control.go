@@ -0,0 +1,65 @@
+package middleware
+
+import "net/http"
+
+// cacheControlNoStore keeps a response out of every cache.
+const cacheControlNoStore = "no-store"
+
+// cacheControlWriter sets the Cache-Control header as the response's status
+// is written.
+type cacheControlWriter struct {
+	http.ResponseWriter
+
+	policy      string
+	safe        bool
+	wroteHeader bool
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	if !w.wroteHeader && code >= http.StatusOK {
+		w.wroteHeader = true
+		if w.Header().Get("Cache-Control") == "" {
+			policy := w.policy
+			if !w.safe || code >= http.StatusBadRequest {
+				policy = cacheControlNoStore
+			}
+			w.Header().Set("Cache-Control", policy)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// CacheControl sets the Cache-Control header of responses to policy, such as
+// "public, max-age=60", unless the handler sets its own. Error responses and
+// responses to requests other than GET and HEAD are marked no-store instead,
+// so a shared cache never keeps a failure or the result of a write. An empty
+// policy leaves responses as the handler wrote them.
+func CacheControl(policy string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if policy == "" {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			safe := r.Method == http.MethodGet || r.Method == http.MethodHead
+			cw := &cacheControlWriter{ResponseWriter: w, policy: policy, safe: safe}
+			next.ServeHTTP(cw, r)
+			if !cw.wroteHeader {
+				// The handler wrote nothing, which net/http sends as an empty 200.
+				cw.WriteHeader(http.StatusOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content codings negotiated by Compress.
+const (
+	EncodingBrotli   = "br"
+	EncodingGzip     = "gzip"
+	EncodingIdentity = "identity"
+)
+
+// CompressionOptions configures Compress.
+type CompressionOptions struct {
+	// Encodings lists the codings offered, br or gzip, most preferred first.
+	Encodings []string
+	// GzipLevel (1-9) and BrotliLevel (0-11) trade speed for size.
+	GzipLevel   int
+	BrotliLevel int
+	// MinSize is the smallest body compressed; smaller bodies are sent as is
+	// unless the handler flushes them.
+	MinSize int
+	// SkipTypes lists content types sent as is because they are already
+	// compressed. An entry ending in "/", such as "image/", matches every
+	// subtype.
+	SkipTypes []string
+}
+
+// Validate reports whether the options are usable.
+func (o CompressionOptions) Validate() error {
+	for _, encoding := range o.Encodings {
+		if encoding != EncodingBrotli && encoding != EncodingGzip {
+			return fmt.Errorf("unsupported compression encoding %q, must be br or gzip", encoding)
+		}
+	}
+	if o.GzipLevel < gzip.BestSpeed || o.GzipLevel > gzip.BestCompression {
+		return fmt.Errorf("gzip level must be between %d and %d, not %d",
+			gzip.BestSpeed, gzip.BestCompression, o.GzipLevel)
+	}
+	if o.BrotliLevel < brotli.BestSpeed || o.BrotliLevel > brotli.BestCompression {
+		return fmt.Errorf("brotli level must be between %d and %d, not %d",
+			brotli.BestSpeed, brotli.BestCompression, o.BrotliLevel)
+	}
+	if o.MinSize < 0 {
+		return fmt.Errorf("compression minimum size must not be negative, not %d", o.MinSize)
+	}
+	return nil
+}
+
+// Compress encodes responses with the coding the client prefers among
+// opts.Encodings, by the quality values of its Accept-Encoding header, ties
+// going to the coding listed first. Responses the handler already encoded,
+// of a skipped type, or smaller than opts.MinSize are sent as is. A
+// compressed response loses its Content-Length, and a strong ETag is
+// weakened since the bytes differ from the identity response.
+func Compress(opts CompressionOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(opts.Encodings) == 0 {
+			return next
+		}
+
+		c := newCompressor(opts)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), opts.Encodings)
+			if encoding == EncodingIdentity {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, compressor: c, encoding: encoding}
+			next.ServeHTTP(cw, r)
+			if err := cw.close(); err != nil {
+				slog.WarnContext(r.Context(), "Failed to finish compressed response", "error", err)
+			}
+		})
+	}
+}
+
+// negotiateEncoding picks the coding of a response from the request's
+// Accept-Encoding: the offered coding with the highest quality value, the
+// first offered on a tie. It returns identity when the client accepts none
+// of them or prefers identity.
+func negotiateEncoding(acceptEncoding string, offered []string) string {
+	if acceptEncoding == "" {
+		return EncodingIdentity
+	}
+
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		qualities[name] = quality(params)
+	}
+
+	best, bestQuality := EncodingIdentity, 0.0
+	for _, encoding := range offered {
+		q, ok := qualities[encoding]
+		if !ok {
+			q = qualities["*"]
+		}
+		if q > bestQuality {
+			best, bestQuality = encoding, q
+		}
+	}
+	if q, ok := qualities[EncodingIdentity]; ok && q > bestQuality {
+		return EncodingIdentity
+	}
+	return best
+}
+
+// quality parses the q parameter of an Accept-Encoding entry, which defaults
+// to 1. A malformed value counts as 0, not acceptable.
+func quality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
+
+// compressor holds the options of a Compress middleware and pools its
+// encoders, which are costly to allocate per response.
+type compressor struct {
+	opts       CompressionOptions
+	gzipPool   sync.Pool
+	brotliPool sync.Pool
+}
+
+func newCompressor(opts CompressionOptions) *compressor {
+	c := &compressor{opts: opts}
+	c.gzipPool.New = func() any {
+		// The level was checked by CompressionOptions.Validate.
+		encoder, _ := gzip.NewWriterLevel(io.Discard, opts.GzipLevel)
+		return encoder
+	}
+	c.brotliPool.New = func() any {
+		return brotli.NewWriterLevel(io.Discard, opts.BrotliLevel)
+	}
+	return c
+}
+
+// encoder is a pooled gzip or brotli writer.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// get returns an encoder of encoding writing to w.
+func (c *compressor) get(encoding string, w io.Writer) encoder {
+	var enc encoder
+	if encoding == EncodingBrotli {
+		enc, _ = c.brotliPool.Get().(*brotli.Writer)
+	} else {
+		enc, _ = c.gzipPool.Get().(*gzip.Writer)
+	}
+	enc.Reset(w)
+	return enc
+}
+
+// put returns a closed encoder to its pool.
+func (c *compressor) put(enc encoder) {
+	switch enc := enc.(type) {
+	case *brotli.Writer:
+		c.brotliPool.Put(enc)
+	case *gzip.Writer:
+		c.gzipPool.Put(enc)
+	}
+}
+
+// skipped reports whether contentType is one of the skipped types.
+func (c *compressor) skipped(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, skip := range c.opts.SkipTypes {
+		if mediaType == skip || (strings.HasSuffix(skip, "/") && strings.HasPrefix(mediaType, skip)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter holds the start of a response until it knows whether to
+// compress it: once MinSize bytes are written, or when the handler flushes.
+type compressWriter struct {
+	http.ResponseWriter
+
+	compressor *compressor
+	encoding   string
+	status     int
+	buf        []byte
+	decided    bool
+	// enc is set once the response is being compressed.
+	enc encoder
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if code < http.StatusOK {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status != 0 || w.decided {
+		return
+	}
+	w.status = code
+	if !bodyAllowed(code) {
+		_ = w.decide(false)
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.decided {
+		return w.write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.compressor.opts.MinSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush sends what has been written so far, compressing the response if it
+// has not been decided yet, since a flushing handler is streaming.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+	if w.enc != nil {
+		if err := w.enc.Flush(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide sends the headers, with those of the coding when compress is set
+// and the response can be compressed, then the body held so far.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	header := w.Header()
+	if compress && w.compressible() {
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", http.DetectContentType(w.buf))
+		}
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		weakenETag(header)
+		w.enc = w.compressor.get(w.encoding, w.ResponseWriter)
+	} else if w.status == http.StatusNotModified {
+		// Match the ETag the compressed response was sent with.
+		weakenETag(header)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	held := w.buf
+	w.buf = nil
+	if len(held) == 0 {
+		return nil
+	}
+	_, err := w.write(held)
+	return err
+}
+
+// compressible reports whether the response may be compressed.
+func (w *compressWriter) compressible() bool {
+	header := w.Header()
+	return bodyAllowed(w.status) &&
+		w.status != http.StatusPartialContent &&
+		header.Get("Content-Encoding") == "" &&
+		!w.compressor.skipped(header.Get("Content-Type"))
+}
+
+func (w *compressWriter) write(b []byte) (int, error) {
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// close sends a response still held, uncompressed since it stayed below
+// MinSize, or ends the compressed stream.
+func (w *compressWriter) close() error {
+	if !w.decided {
+		if w.status == 0 && len(w.buf) == 0 {
+			return nil
+		}
+		return w.decide(false)
+	}
+	if w.enc == nil {
+		return nil
+	}
+
+	err := w.enc.Close()
+	w.compressor.put(w.enc)
+	w.enc = nil
+	if err != nil {
+		return fmt.Errorf("error finishing %s response: %w", w.encoding, err)
+	}
+	return nil
+}
+
+// weakenETag marks a strong ETag weak, since the compressed bytes differ
+// from those it was computed over.
+func weakenETag(header http.Header) {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+}
+
+// bodyAllowed reports whether a response with status code may have a body.
+func bodyAllowed(code int) bool {
+	return code >= http.StatusOK && code != http.StatusNoContent && code != http.StatusNotModified
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParseNetworks parses CIDR ranges such as "10.0.0.0/8". A bare address is
+// taken as a range holding only that address.
+func ParseNetworks(values []string) ([]netip.Prefix, error) {
+	networks := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid network %q: %w", value, err)
+			}
+			networks = append(networks, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		network, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w", value, err)
+		}
+		networks = append(networks, network.Masked())
+	}
+	return networks, nil
+}
+
+// AllowNetworks rejects with 403 requests from clients outside the allowed
+// networks. The client is the connection's peer, unless that peer is one of
+// the trusted proxies: then it is the last X-Forwarded-For entry not added
+// by a trusted proxy, since clients can put anything before it. An empty
+// allowlist lets every client through.
+func AllowNetworks(allowed, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(allowed) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, ok := TrustedClientAddr(r, trustedProxies)
+			if !ok || !containsAddr(allowed, client) {
+				writeError(w, http.StatusForbidden, "Forbidden", "requests from this address are not allowed")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TrustedClientAddr returns the client address of r that trusted proxies
+// vouch for, walking X-Forwarded-For back from the connection's peer. It
+// reports false when that address cannot be parsed.
+func TrustedClientAddr(r *http.Request, trustedProxies []netip.Prefix) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	client, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	client = client.Unmap()
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0 && containsAddr(trustedProxies, client); i-- {
+		entry := strings.TrimSpace(forwarded[i])
+		if entry == "" {
+			continue
+		}
+		if client, err = netip.ParseAddr(entry); err != nil {
+			return netip.Addr{}, false
+		}
+		client = client.Unmap()
+	}
+	return client, true
+}
+
+type clientAddrKey struct{}
+
+// TrustedClient stores the client address TrustedClientAddr finds on the
+// request context, for ClientAddr.
+func TrustedClient(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if client, ok := TrustedClientAddr(r, trustedProxies); ok {
+				r = r.WithContext(context.WithValue(r.Context(), clientAddrKey{}, client))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientAddr returns the client address of r stored by TrustedClient.
+// Without it, the client is the connection's peer: X-Forwarded-For is not
+// believed, as any client can send it.
+func ClientAddr(r *http.Request) string {
+	client, ok := r.Context().Value(clientAddrKey{}).(netip.Addr)
+	if !ok {
+		if client, ok = TrustedClientAddr(r, nil); !ok {
+			return ""
+		}
+	}
+	return client.String()
+}
+
+func containsAddr(networks []netip.Prefix, addr netip.Addr) bool {
+	for _, network := range networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/metrics"
+)
+
+// LimitConcurrency serves at most maxInFlight requests at once. A request
+// arriving when every slot is taken waits up to queueTimeout for one to free
+// up, then is shed with 503 and a Retry-After header. Health-check probes
+// bypass the limit so an overloaded instance is not also restarted. A
+// maxInFlight of 0 disables the limit.
+func LimitConcurrency(maxInFlight int, queueTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if maxInFlight <= 0 {
+			return next
+		}
+
+		slots := make(chan struct{}, maxInFlight)
+		retryAfter := strconv.Itoa(max(int(math.Ceil(queueTimeout.Seconds())), 1))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if healthCheckPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !acquireSlot(r, slots, queueTimeout) {
+				metrics.HTTPShedRequests.Inc()
+				w.Header().Set("Retry-After", retryAfter)
+				writeError(w, http.StatusServiceUnavailable, "Server overloaded",
+					"too many requests in flight, retry later")
+				return
+			}
+			metrics.HTTPInFlightRequests.Inc()
+			defer func() {
+				metrics.HTTPInFlightRequests.Dec()
+				<-slots
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// acquireSlot takes a slot, waiting up to queueTimeout or until the client
+// goes away, and reports whether it got one.
+func acquireSlot(r *http.Request, slots chan struct{}, queueTimeout time.Duration) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(queueTimeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
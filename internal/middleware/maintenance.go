@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// MaintenanceMode is a runtime switch that puts the API into read-only mode.
+type MaintenanceMode struct {
+	enabled atomic.Bool
+}
+
+// NewMaintenanceMode creates a maintenance switch in the given initial state.
+func NewMaintenanceMode(enabled bool) *MaintenanceMode {
+	mode := &MaintenanceMode{}
+	mode.enabled.Store(enabled)
+	return mode
+}
+
+// Enabled reports whether maintenance mode is on.
+func (m *MaintenanceMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// SetEnabled turns maintenance mode on or off.
+func (m *MaintenanceMode) SetEnabled(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// ReadOnly rejects write requests with 503 while mode is enabled. Requests
+// under any of the exempt path prefixes, such as the admin routes used to
+// turn maintenance off again, are always let through.
+func ReadOnly(mode *MaintenanceMode, exemptPrefixes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !mode.Enabled() || isReadMethod(r.Method) || hasAnyPrefix(r.URL.Path, exemptPrefixes) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Retry-After", "120")
+			writeError(w, http.StatusServiceUnavailable, "Service is in maintenance mode",
+				"writes are temporarily disabled; reads remain available")
+		})
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/rxbenefits/go-hw/internal/metrics"
+)
+
+// CountResponses counts every response by its status class in
+// metrics.HTTPResponses.
+func CountResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		metrics.HTTPResponses.WithLabelValues(metrics.StatusClass(rec.status)).Inc()
+	})
+}
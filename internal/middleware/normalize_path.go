@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+)
+
+// RouteMatcher reports whether a route matches a request as it is.
+type RouteMatcher interface {
+	Match(r *http.Request) bool
+}
+
+// NormalizePath routes a request whose path is not clean, such as one with a
+// trailing slash, a repeated slash, or dot segments, as if the clean path had
+// been requested, so "/api/v1/films/" behaves like "/api/v1/films". The
+// request is rewritten rather than redirected so that writes keep their method
+// and body. Paths that already match a route on router, such as the "/swagger/"
+// prefix, are left alone.
+func NormalizePath(router RouteMatcher) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cleaned := path.Clean(r.URL.Path)
+			if cleaned == r.URL.Path || r.URL.Path == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if router.Match(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rewritten := r.WithContext(r.Context())
+			rewrittenURL := *r.URL
+			rewrittenURL.Path = cleaned
+			rewrittenURL.RawPath = ""
+			rewritten.URL = &rewrittenURL
+			next.ServeHTTP(w, rewritten)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimitWindow is the length of a RateLimiter window.
+const rateLimitWindow = time.Minute
+
+// RateLimiter counts requests per key in fixed one-minute windows. Counts are
+// kept in memory, so each replica enforces its limits separately. Windows
+// that have ended are dropped, so keys that stop sending requests, such as
+// abandoned guest sessions, do not pile up.
+type RateLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// RateLimiterOption configures a RateLimiter.
+type RateLimiterOption func(*RateLimiter)
+
+// WithRateLimiterClock overrides the clock windows are timed by, e.g. for
+// tests.
+func WithRateLimiterClock(now func() time.Time) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.now = now
+	}
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter creates an empty rate limiter.
+func NewRateLimiter(opts ...RateLimiterOption) *RateLimiter {
+	l := &RateLimiter{windows: make(map[string]*rateWindow), now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Allow counts a request for key against limit requests per minute. It
+// reports whether the request is within the limit, how many requests remain
+// in the window, and when the window resets. Rejected requests are not
+// counted.
+func (l *RateLimiter) Allow(key string, limit int) (bool, int, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	window, ok := l.windows[key]
+	if !ok || !now.Before(window.start.Add(rateLimitWindow)) {
+		window = &rateWindow{start: now.Truncate(rateLimitWindow)}
+		l.windows[key] = window
+	}
+	resetsAt := window.start.Add(rateLimitWindow)
+
+	if window.count >= limit {
+		return false, 0, resetsAt
+	}
+	window.count++
+	return true, limit - window.count, resetsAt
+}
+
+// Len returns how many keys have a window being counted.
+func (l *RateLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.windows)
+}
+
+// sweep drops the windows that have ended by now. It runs at most once a
+// window, so its cost is spread over the requests counted in between.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Before(l.lastSweep.Add(rateLimitWindow)) {
+		return
+	}
+	l.lastSweep = now
+	for key, window := range l.windows {
+		if !now.Before(window.start.Add(rateLimitWindow)) {
+			delete(l.windows, key)
+		}
+	}
+}
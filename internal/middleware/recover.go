@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/rxbenefits/go-hw/internal/reporting"
+)
+
+// errorCapturingWriter remembers the error behind a 5xx response. Handlers
+// record it through RecordError when writing their error response.
+type errorCapturingWriter struct {
+	http.ResponseWriter
+
+	wroteHeader bool
+	status      int
+	err         error
+}
+
+func (w *errorCapturingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *errorCapturingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// RecordError stores the error that caused the response.
+func (w *errorCapturingWriter) RecordError(err error) {
+	w.err = err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *errorCapturingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// ReportErrors recovers panics from the wrapped handler, answering with a 500,
+// and forwards panics and errors behind 5xx responses to reporter.
+func ReportErrors(reporter reporting.Reporter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			capturing := &errorCapturingWriter{ResponseWriter: w}
+			requestID := RequestIDFromContext(r.Context())
+
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					// net/http relies on this panic to abort the response silently.
+					panic(recovered)
+				}
+
+				slog.ErrorContext(r.Context(), "Recovered from panic",
+					"panic", recovered, "request_id", requestID, "stack", string(debug.Stack()))
+				reporter.CapturePanic(r, requestID, recovered)
+
+				if !capturing.wroteHeader {
+					writeError(capturing, http.StatusInternalServerError, "Internal server error", "unexpected error")
+				}
+			}()
+
+			next.ServeHTTP(capturing, r)
+
+			if capturing.status >= http.StatusInternalServerError && capturing.err != nil {
+				reporter.CaptureError(r, requestID, capturing.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+)
+
+// SignedURLOr serves requests to URLs signed by signer without asking for
+// credentials, and sends other requests through authenticate, applied in
+// order. A request carrying a signature that does not verify, or has
+// expired, is rejected with 401 rather than falling back. With a nil signer
+// every request goes through authenticate.
+func SignedURLOr(
+	signer *auth.URLSigner,
+	authenticate ...func(http.Handler) http.Handler,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		authenticated := next
+		for i := len(authenticate) - 1; i >= 0; i-- {
+			authenticated = authenticate[i](authenticated)
+		}
+		if signer == nil {
+			return authenticated
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !r.URL.Query().Has(auth.SignatureParam) {
+				authenticated.ServeHTTP(w, r)
+				return
+			}
+			if err := signer.Verify(r.URL); err != nil {
+				writeError(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
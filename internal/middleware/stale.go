@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/cache"
+)
+
+// StaleHeader marks responses replayed from the last-known copy.
+const StaleHeader = "X-Stale"
+
+// storedResponse is the last successful response for a URL.
+type storedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// bufferedWriter holds the handler's response until ServeStale decides
+// whether to send it or a stored copy.
+type bufferedWriter struct {
+	http.ResponseWriter
+
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// Unwrap exposes the underlying writer so handlers can reach wrappers such as
+// the error reporter's.
+func (w *bufferedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// copyHeader copies a buffered response's header to dst, replacing values
+// already there except Vary, which outer middleware may have set too.
+func copyHeader(dst, src http.Header) {
+	for name, values := range src {
+		if name == "Vary" {
+			dst[name] = append(dst[name], values...)
+			continue
+		}
+		dst[name] = values
+	}
+}
+
+// ServeStale remembers successful GET responses in store and replays the
+// last-known copy, marked with StaleHeader, when the handler answers 503
+// because the database is unavailable. A copy is only replayed until it
+// expires from store, so the store's time-to-live bounds how stale a
+// response can be. Responses are keyed by URL alone, so it must only wrap
+// public routes, and requests that identify their caller, with a token, an
+// API key, or a signed URL, are passed through without being stored or
+// replayed.
+func ServeStale(store cache.Cache) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || identifiesCaller(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			buffered := &bufferedWriter{ResponseWriter: w, header: http.Header{}}
+			next.ServeHTTP(buffered, r)
+			if buffered.status == 0 {
+				buffered.status = http.StatusOK
+			}
+
+			key := r.URL.RequestURI()
+			if storeID := r.Header.Get(StoreIDHeader); storeID != "" {
+				key += " " + StoreIDHeader + "=" + storeID
+			}
+			switch {
+			case buffered.status >= http.StatusOK && buffered.status < http.StatusMultipleChoices:
+				store.Set(key, storedResponse{
+					status: buffered.status,
+					header: buffered.header.Clone(),
+					body:   bytes.Clone(buffered.body.Bytes()),
+				})
+			case buffered.status == http.StatusServiceUnavailable:
+				if cached, ok := store.Get(key); ok {
+					if stored, isResponse := cached.(storedResponse); isResponse {
+						slog.WarnContext(r.Context(), "Serving stale response while database is unavailable",
+							"path", key, "request_id", RequestIDFromContext(r.Context()))
+						buffered.header = stored.header.Clone()
+						buffered.header.Set(StaleHeader, "true")
+						buffered.header.Set("Warning", `110 - "Response is Stale"`)
+						buffered.status = stored.status
+						buffered.body.Reset()
+						buffered.body.Write(stored.body)
+					}
+				}
+			}
+
+			copyHeader(w.Header(), buffered.header)
+			w.WriteHeader(buffered.status)
+			if _, err := w.Write(buffered.body.Bytes()); err != nil {
+				slog.Error("Failed to write response", "error", err)
+			}
+		})
+	}
+}
+
+// identifiesCaller reports whether r carries credentials, so its response
+// may be the caller's own rather than the URL's.
+func identifiesCaller(r *http.Request) bool {
+	return r.Header.Get("Authorization") != "" || r.Header.Get(auth.APIKeyHeader) != "" ||
+		r.URL.Query().Has(auth.SignatureParam)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+// StoreIDHeader scopes a request to a single store.
+const StoreIDHeader = "X-Store-ID"
+
+// StoreIDVar is the route variable used by store-prefixed routes.
+const StoreIDVar = "storeID"
+
+// StoreScope scopes requests to the store named by the storeID route variable
+// or, failing that, the X-Store-ID header. Requests naming neither are left
+// unscoped; malformed store IDs are rejected with 400.
+func StoreScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.PathValue(StoreIDVar)
+		if raw == "" {
+			// Shared caches must key scoped responses by the header.
+			w.Header().Add("Vary", StoreIDHeader)
+			raw = r.Header.Get(StoreIDHeader)
+		}
+		if raw == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		storeID, err := strconv.Atoi(raw)
+		if err != nil || storeID <= 0 {
+			writeError(w, http.StatusBadRequest, "Invalid store ID", "store ID must be a positive integer")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(tenant.WithStoreID(r.Context(), storeID)))
+	})
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timeoutWriter holds the handler's response until Timeout decides whether
+// to send it or a timeout error. Once the request has timed out, later
+// writes from the still-running handler are discarded.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	status   int
+	body     bytes.Buffer
+	err      error
+	timedOut bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 && !w.timedOut {
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// RecordError stores the error behind the response, to be forwarded to error
+// reporting with it. The writer is deliberately not unwrappable: the handler
+// may outlive the request, so it must not reach the writers around it.
+func (w *timeoutWriter) RecordError(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.err = err
+}
+
+// RequestTimeoutHeader and GRPCTimeoutHeader let a caller give a request a
+// shorter deadline than the server's, to enforce its own SLA. The first takes
+// a Go duration such as "1.5s" or "250ms"; the second gRPC's format, up to
+// eight digits and a unit such as "250m" for milliseconds or "2S".
+const (
+	RequestTimeoutHeader = "X-Request-Timeout"
+	GRPCTimeoutHeader    = "Grpc-Timeout"
+)
+
+// maxGRPCTimeoutDigits is the most digits a grpc-timeout value may have.
+const maxGRPCTimeoutDigits = 8
+
+// grpcTimeoutUnits maps the units of grpc-timeout values to durations.
+var grpcTimeoutUnits = map[byte]time.Duration{ //nolint:gochecknoglobals // Read-only lookup table
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+// Timeout answers with 504 when the wrapped handler has not responded within
+// maxTimeout, or within the shorter timeout a request asks for in its
+// RequestTimeoutHeader or GRPCTimeoutHeader; a malformed one is rejected with
+// 400. The handler runs with a context cancelled at the deadline, but work
+// that ignores its context, such as an unresponsive database query, keeps
+// running in the background until it finishes. A maxTimeout of 0 disables
+// the server's limit; requests asking for a timeout still get one.
+func Timeout(maxTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := maxTimeout
+			requested, err := requestedTimeout(r)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "Invalid request timeout", err.Error())
+				return
+			}
+			if requested > 0 && (timeout <= 0 || requested < timeout) {
+				timeout = requested
+			}
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			buffered := &timeoutWriter{header: http.Header{}}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if recovered := recover(); recovered != nil {
+						if recovered != http.ErrAbortHandler {
+							recovered = fmt.Sprintf("%v\n\n%s", recovered, debug.Stack())
+						}
+						panicked <- recovered
+					}
+				}()
+				next.ServeHTTP(buffered, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case recovered := <-panicked:
+				panic(recovered)
+			case <-done:
+				buffered.mu.Lock()
+				defer buffered.mu.Unlock()
+				copyHeader(w.Header(), buffered.header)
+				if buffered.status == 0 {
+					buffered.status = http.StatusOK
+				}
+				if buffered.err != nil {
+					recordServerError(w, buffered.err)
+				}
+				w.WriteHeader(buffered.status)
+				if _, err := w.Write(buffered.body.Bytes()); err != nil {
+					slog.Error("Failed to write response", "error", err)
+				}
+			case <-ctx.Done():
+				buffered.mu.Lock()
+				buffered.timedOut = true
+				buffered.mu.Unlock()
+
+				if r.Context().Err() != nil {
+					// The client went away; there is no one to answer.
+					return
+				}
+				slog.WarnContext(r.Context(), "Request timed out",
+					"path", r.URL.Path, "timeout", timeout, "request_id", RequestIDFromContext(r.Context()))
+				recordServerError(w, fmt.Errorf("handler timed out after %s: %w", timeout, ctx.Err()))
+				writeError(w, http.StatusGatewayTimeout, "Request timed out",
+					"the request took longer than "+timeout.String())
+			}
+		})
+	}
+}
+
+// requestedTimeout returns the timeout r asks for, or 0 when it sets none.
+func requestedTimeout(r *http.Request) (time.Duration, error) {
+	if value := r.Header.Get(RequestTimeoutHeader); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return 0, fmt.Errorf("%s must be a positive duration such as 500ms, not %q", RequestTimeoutHeader, value)
+		}
+		return timeout, nil
+	}
+	if value := r.Header.Get(GRPCTimeoutHeader); value != "" {
+		return parseGRPCTimeout(value)
+	}
+	return 0, nil
+}
+
+// parseGRPCTimeout parses a timeout in gRPC's grpc-timeout format.
+func parseGRPCTimeout(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("%s must be a positive number of up to %d digits and a unit such as 500m, not %q",
+		GRPCTimeoutHeader, maxGRPCTimeoutDigits, value)
+	if len(value) < 2 || len(value) > maxGRPCTimeoutDigits+1 {
+		return 0, invalid
+	}
+	unit, ok := grpcTimeoutUnits[value[len(value)-1]]
+	if !ok {
+		return 0, invalid
+	}
+	digits := value[:len(value)-1]
+	if strings.TrimLeft(digits, "0123456789") != "" {
+		return 0, invalid
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n == 0 {
+		return 0, invalid
+	}
+	if time.Duration(n) > math.MaxInt64/unit {
+		// Longer than any server limit, so as good as none.
+		return math.MaxInt64, nil
+	}
+	return time.Duration(n) * unit, nil
+}
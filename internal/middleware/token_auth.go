@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+)
+
+// RequireToken rejects requests without a bearer token accepted by one of
+// issuers, or whose login session has been revoked, and stores the token's
+// claims on the request context.
+func RequireToken(issuers ...*auth.TokenIssuer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				writeError(w, http.StatusUnauthorized, "Unauthorized", "a bearer token is required")
+				return
+			}
+
+			var claims *auth.Claims
+			err := auth.ErrInvalidToken
+			for _, issuer := range issuers {
+				if claims, err = issuer.Authenticate(r.Context(), token); !errors.Is(err, auth.ErrInvalidToken) {
+					break
+				}
+			}
+			if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrRevokedToken) {
+				writeError(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+				return
+			}
+			if err != nil {
+				writeVerifierError(w, "Failed to authenticate token", err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// RequireQueryToken is RequireToken for clients that cannot send headers,
+// such as calendar apps: the token comes from the query parameter param.
+func RequireQueryToken(param string, issuer *auth.TokenIssuer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get(param)
+			if token == "" {
+				writeError(w, http.StatusUnauthorized, "Unauthorized", "a "+param+" parameter is required")
+				return
+			}
+
+			claims, err := issuer.Verify(token)
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// OptionalToken lets anonymous requests through unchanged but, like
+// RequireToken, rejects a bearer token none of issuers accepts and stores a
+// valid token's claims on the request context.
+func OptionalToken(issuers ...*auth.TokenIssuer) func(http.Handler) http.Handler {
+	required := RequireToken(issuers...)
+	return func(next http.Handler) http.Handler {
+		authenticated := required(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authenticated.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GuestRateLimit limits requests made with guest tokens to perMinute per
+// guest session, counted in limiter. Other requests pass through uncounted.
+// It must run after RequireToken or OptionalToken.
+func GuestRateLimit(limiter *RateLimiter, perMinute int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := auth.ClaimsFromContext(r.Context())
+			if !ok || claims.Role != auth.RoleGuest {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, remaining, resetsAt := limiter.Allow("guest:"+strconv.Itoa(claims.Subject), perMinute)
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(perMinute))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			if !allowed {
+				setRetryAfter(w, resetsAt)
+				writeError(w, http.StatusTooManyRequests, "Rate limit exceeded",
+					"guests may make "+strconv.Itoa(perMinute)+" requests per minute; register for more")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSubject rejects requests whose route variable name does not match
+// the subject of the token claims stored by RequireToken, so callers can only
+// reach their own resources. Tokens for one of exemptRoles, such as support
+// staff, reach anyone's. It must run after RequireToken.
+func RequireSubject(name string, exemptRoles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := auth.ClaimsFromContext(r.Context())
+			if ok && slices.Contains(exemptRoles, claims.Role) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !ok || strconv.Itoa(claims.Subject) != r.PathValue(name) {
+				writeError(w, http.StatusForbidden, "Forbidden", "token does not grant access to this resource")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireTwoFactor rejects requests whose token, for one of roles, comes
+// from a login that did not pass a second factor. Other tokens pass through.
+// It must run after RequireToken.
+func RequireTwoFactor(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(roles) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := auth.ClaimsFromContext(r.Context())
+			if ok && slices.Contains(roles, claims.Role) && !claims.TwoFactor {
+				writeError(w, http.StatusForbidden, "Forbidden", "two-factor authentication is required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Activity kinds recorded in followers' feeds.
+const (
+	ActivityCommentPosted  = "comment_posted"
+	ActivityListPublished  = "list_published"
+	ActivityListEntryAdded = "list_entry_added"
+)
+
+// Activity is something a customer did in public, such as commenting on a
+// film or adding a film to a public list, as shown in their followers'
+// feeds. FilmID, ListID, and CommentID are set as the kind calls for.
+type Activity struct {
+	ID           int64     `json:"id"                   db:"id"`
+	CustomerID   int       `json:"customer_id"          db:"customer_id"`
+	CustomerName string    `json:"customer_name"        db:"customer_name"`
+	Kind         string    `json:"kind"                 db:"kind"`
+	FilmID       *int      `json:"film_id,omitempty"    db:"film_id"`
+	FilmTitle    *string   `json:"film_title,omitempty" db:"film_title"`
+	ListID       *int      `json:"list_id,omitempty"    db:"list_id"`
+	ListName     *string   `json:"list_name,omitempty"  db:"list_name"`
+	CommentID    *int      `json:"comment_id,omitempty" db:"comment_id"`
+	CreatedAt    time.Time `json:"created_at"           db:"created_at"`
+}
+
+// ActivityFeedFilters selects a page of a customer's feed.
+type ActivityFeedFilters struct {
+	Page  int `json:"page"  validate:"min=1"`
+	Limit int `json:"limit" validate:"min=1,max=100"`
+}
+
+// Follow is a customer someone follows.
+type Follow struct {
+	CustomerID int       `json:"customer_id" db:"customer_id"`
+	Name       string    `json:"name"        db:"name"`
+	FollowedAt time.Time `json:"followed_at" db:"followed_at"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// API key tiers. Keep in sync with the oneof validation on APIKeyRequest and
+// the tier check constraint on api_keys.
+const (
+	APIKeyTierFree     = "free"
+	APIKeyTierPartner  = "partner"
+	APIKeyTierInternal = "internal"
+)
+
+// APIKeyTierLimits are the limits of an API key tier. Zero means unlimited.
+type APIKeyTierLimits struct {
+	RequestsPerMinute int   `json:"requests_per_minute"`
+	MonthlyQuota      int64 `json:"monthly_quota"`
+}
+
+// TierLimits returns the limits of tier, and whether the tier exists.
+func TierLimits(tier string) (APIKeyTierLimits, bool) {
+	switch tier {
+	case APIKeyTierFree:
+		return APIKeyTierLimits{RequestsPerMinute: 60, MonthlyQuota: 10_000}, true
+	case APIKeyTierPartner:
+		return APIKeyTierLimits{RequestsPerMinute: 600, MonthlyQuota: 1_000_000}, true
+	case APIKeyTierInternal:
+		return APIKeyTierLimits{}, true
+	default:
+		return APIKeyTierLimits{}, false
+	}
+}
+
+// APIKey represents a key issued to an API consumer. The key itself is only
+// serialized when it is created.
+type APIKey struct {
+	ID        int        `json:"id"                   db:"id"`
+	Name      string     `json:"name"                 db:"name"`
+	Tier      string     `json:"tier"                 db:"tier"`
+	KeyPrefix string     `json:"key_prefix"           db:"key_prefix"`
+	Key       string     `json:"key,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"           db:"created_at"`
+}
+
+// APIKeyRequest represents the request body for issuing an API key.
+type APIKeyRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Tier string `json:"tier" validate:"required,oneof=free partner internal"`
+}
+
+// APIKeyUsage reports an API key's consumption in the current month.
+// Remaining is omitted for keys without a monthly quota.
+type APIKeyUsage struct {
+	Tier   string           `json:"tier"`
+	Limits APIKeyTierLimits `json:"limits"`
+	// Period is the month being counted, as YYYY-MM.
+	Period    string    `json:"period"`
+	Used      int64     `json:"used"`
+	Remaining *int64    `json:"remaining,omitempty"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
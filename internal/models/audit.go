@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// Audited actions.
+const (
+	AuditActionCustomerErased = "customer.erased"
+	AuditActionCustomerMerged = "customer.merged"
+)
+
+// AuditActorSystem is the actor role of actions taken without a caller's
+// token.
+const AuditActorSystem = "system"
+
+// Audited target types.
+const (
+	AuditTargetCustomer = "customer"
+)
+
+// AuditEntry records a sensitive action: the actor who took it, by role and
+// ID, and the target it was taken on, with details of the outcome.
+type AuditEntry struct {
+	ID         int64           `json:"id"                 db:"id"`
+	ActorRole  string          `json:"actor_role"         db:"actor_role"`
+	ActorID    *int            `json:"actor_id,omitempty" db:"actor_id"`
+	Action     string          `json:"action"             db:"action"`
+	TargetType string          `json:"target_type"        db:"target_type"`
+	TargetID   int             `json:"target_id"          db:"target_id"`
+	Details    json.RawMessage `json:"details"            db:"details"`
+	CreatedAt  time.Time       `json:"created_at"         db:"created_at"`
+}
+
+// AuditLimits are the page sizes of audit log listings.
+var AuditLimits = pagination.Limits{DefaultLimit: 20, MaxLimit: 100} //nolint:gochecknoglobals // Read-only
+
+// AuditFilters narrows an audit log listing. Entries are matched on any of
+// Actions, and on creation time from From inclusive up to To exclusive.
+type AuditFilters struct {
+	Actions []string   `json:"actions,omitempty" query:"action" validate:"dive,notblank"`
+	From    *time.Time `json:"from,omitempty"`
+	To      *time.Time `json:"to,omitempty"`
+	pagination.Params
+}
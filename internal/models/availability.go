@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// AvailabilityAlert is a customer's request to hear when a copy of a film
+// comes back.
+type AvailabilityAlert struct {
+	ID         int       `json:"id"          db:"id"`
+	FilmID     int       `json:"film_id"     db:"film_id"`
+	CustomerID int       `json:"customer_id" db:"customer_id"`
+	CreatedAt  time.Time `json:"created_at"  db:"created_at"`
+}
+
+// AvailabilityNotice describes a pending alert whose customer should be told
+// the film is available.
+type AvailabilityNotice struct {
+	AlertID       int    `db:"id"`
+	CustomerID    int    `db:"customer_id"`
+	CustomerEmail string `db:"email"`
+	CustomerName  string `db:"first_name"`
+	FilmID        int    `db:"film_id"`
+	FilmTitle     string `db:"title"`
+}
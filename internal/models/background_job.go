@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Background job statuses. A failed job goes back to pending until it runs
+// out of attempts, and is then dead until an admin retries it.
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusDead      = "dead"
+)
+
+// BackgroundJob represents a job in the persistent job queue.
+type BackgroundJob struct {
+	ID          int64           `json:"id"                   db:"id"`
+	Kind        string          `json:"kind"                 db:"kind"`
+	Payload     json.RawMessage `json:"payload"              db:"payload"`
+	Status      string          `json:"status"               db:"status"`
+	Attempts    int             `json:"attempts"             db:"attempts"`
+	MaxAttempts int             `json:"max_attempts"         db:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"               db:"run_at"`
+	LastError   *string         `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt   time.Time       `json:"created_at"           db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"           db:"updated_at"`
+}
+
+// BackgroundJobFilters represents the query parameters for listing jobs.
+type BackgroundJobFilters struct {
+	Status string `json:"status" validate:"omitempty,oneof=pending running succeeded dead"`
+	Kind   string `json:"kind"`
+	Limit  int    `json:"limit"  validate:"min=1,max=100"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// CartItem is a film in a customer's cart, priced at the customer's store.
+// Available reports whether a copy is in stock there right now.
+type CartItem struct {
+	FilmID      int       `json:"film_id"   db:"film_id"`
+	Title       string    `json:"title"     db:"title"`
+	BaseRate    float64   `json:"base_rate" db:"rental_rate"`
+	Rate        float64   `json:"rate"`
+	Available   bool      `json:"available" db:"available"`
+	AddedAt     time.Time `json:"added_at"  db:"added_at"`
+	CategoryIDs []int     `json:"-"`
+}
+
+// Cart is the films a customer means to rent, with their total.
+type Cart struct {
+	CustomerID int        `json:"customer_id"`
+	StoreID    int        `json:"store_id"`
+	Items      []CartItem `json:"items"`
+	Subtotal   float64    `json:"subtotal"`
+}
+
+// CartItemRequest represents the request to add a film to a cart.
+type CartItemRequest struct {
+	FilmID int `json:"film_id" validate:"required,min=1"`
+}
+
+// CheckoutRequest represents the request to check out a cart, optionally
+// with a coupon and a gift card.
+type CheckoutRequest struct {
+	CouponCode   string `json:"coupon_code"    validate:"omitempty,alphanum,max=32"`
+	GiftCardCode string `json:"gift_card_code" validate:"max=32"`
+}
+
+// CheckoutLine is a cart film to rent at the given rate.
+type CheckoutLine struct {
+	FilmID int
+	Rate   float64
+}
+
+// CheckoutRental is a rental created by a checkout.
+type CheckoutRental struct {
+	RentalID    int       `json:"rental_id"    db:"rental_id"`
+	InventoryID int       `json:"inventory_id" db:"inventory_id"`
+	FilmID      int       `json:"film_id"      db:"film_id"`
+	Title       string    `json:"title"        db:"title"`
+	Rate        float64   `json:"rate"         db:"rate"`
+	DueAt       time.Time `json:"due_at"       db:"due_at"`
+}
+
+// Checkout is a completed checkout: the rentals it created and what was
+// charged for them. The total is the subtotal less the coupon discount, any
+// loyalty rental credit, and any gift card balance applied.
+type Checkout struct {
+	ID              int              `json:"id"                    db:"id"`
+	CustomerID      int              `json:"customer_id"           db:"customer_id"`
+	StoreID         int              `json:"store_id"              db:"store_id"`
+	Rentals         []CheckoutRental `json:"rentals"`
+	Subtotal        float64          `json:"subtotal"              db:"subtotal"`
+	Discount        float64          `json:"discount"              db:"discount"`
+	CreditApplied   float64          `json:"credit_applied"        db:"credit_applied"`
+	GiftCardApplied float64          `json:"gift_card_applied"     db:"gift_card_applied"`
+	Total           float64          `json:"total"                 db:"total"`
+	CouponCode      string           `json:"coupon_code,omitempty" db:"coupon_code"`
+	CreatedAt       time.Time        `json:"created_at"            db:"created_at"`
+}
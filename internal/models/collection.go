@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Collection is a named group of films, such as a franchise or series. Its
+// films are listed in the collection's order, which need not be release
+// order. Listings of collections leave the films out and give their count.
+type Collection struct {
+	ID          int              `json:"id"                    db:"id"`
+	Name        string           `json:"name"                  db:"name"`
+	Description *string          `json:"description,omitempty" db:"description"`
+	FilmCount   int              `json:"film_count"            db:"film_count"`
+	Films       []CollectionFilm `json:"films,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"            db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"            db:"updated_at"`
+}
+
+// CollectionFilm is a film in a collection at its 1-based position.
+type CollectionFilm struct {
+	FilmID      int    `json:"film_id"                db:"film_id"`
+	Title       string `json:"title"                  db:"title"`
+	ReleaseYear *int   `json:"release_year,omitempty" db:"release_year"`
+	Rating      string `json:"rating"                 db:"rating"`
+	Position    int    `json:"position"               db:"position"`
+}
+
+// FilmCollection is a collection a film belongs to, with the film's
+// position in it.
+type FilmCollection struct {
+	ID       int    `json:"id"       db:"id"`
+	Name     string `json:"name"     db:"name"`
+	Position int    `json:"position" db:"position"`
+}
+
+// CollectionRequest represents the request body for creating or replacing a
+// collection. FilmIDs lists the collection's films in order.
+type CollectionRequest struct {
+	Name        string  `json:"name"        validate:"required,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=2000"`
+	FilmIDs     []int   `json:"film_ids"    validate:"max=200,unique,dive,min=1"`
+}
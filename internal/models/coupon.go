@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Coupon is a code that takes PercentOff or AmountOff off a checkout total.
+// MaxRedemptions and MaxPerCustomer limit its uses, and it cannot be used
+// from ExpiresAt on; unset, they do not restrict it.
+type Coupon struct {
+	ID             int        `json:"id"                         db:"id"`
+	Code           string     `json:"code"                       db:"code"`
+	Description    string     `json:"description,omitempty"      db:"description"`
+	PercentOff     *float64   `json:"percent_off,omitempty"      db:"percent_off"`
+	AmountOff      *float64   `json:"amount_off,omitempty"       db:"amount_off"`
+	MaxRedemptions *int       `json:"max_redemptions,omitempty"  db:"max_redemptions"`
+	MaxPerCustomer *int       `json:"max_per_customer,omitempty" db:"max_per_customer"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"       db:"expires_at"`
+	Redemptions    int        `json:"redemptions"                db:"redemptions"`
+	CreatedAt      time.Time  `json:"created_at"                 db:"created_at"`
+}
+
+// CouponRequest represents the request to create a coupon. Exactly one of
+// PercentOff and AmountOff must be set.
+type CouponRequest struct {
+	Code           string     `json:"code"             validate:"required,alphanum,min=3,max=32"`
+	Description    string     `json:"description"      validate:"max=255"`
+	PercentOff     *float64   `json:"percent_off"      validate:"excluded_with=AmountOff,omitempty,gt=0,lte=100"`
+	AmountOff      *float64   `json:"amount_off"       validate:"required_without=PercentOff,omitempty,gt=0,lt=1000"`
+	MaxRedemptions *int       `json:"max_redemptions"  validate:"omitempty,min=1"`
+	MaxPerCustomer *int       `json:"max_per_customer" validate:"omitempty,min=1"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+// CouponCheckRequest names the checkout total a coupon is validated against
+// or redeemed for.
+type CouponCheckRequest struct {
+	Amount float64 `json:"amount" validate:"gt=0,lt=100000"`
+}
+
+// CouponQuote is the discount a coupon would give a customer's checkout.
+type CouponQuote struct {
+	Code     string  `json:"code"`
+	Amount   float64 `json:"amount"`
+	Discount float64 `json:"discount"`
+	Total    float64 `json:"total"`
+}
+
+// CouponRedemption records a coupon used by a customer.
+type CouponRedemption struct {
+	ID         int       `json:"id"          db:"id"`
+	Code       string    `json:"code"        db:"code"`
+	CustomerID int       `json:"customer_id" db:"customer_id"`
+	Amount     float64   `json:"amount"      db:"amount"`
+	Discount   float64   `json:"discount"    db:"discount"`
+	Total      float64   `json:"total"`
+	RedeemedAt time.Time `json:"redeemed_at" db:"redeemed_at"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// Customer represents a registered customer.
+type Customer struct {
+	CustomerID int       `json:"customer_id" db:"customer_id"`
+	StoreID    int       `json:"store_id"    db:"store_id"`
+	FirstName  string    `json:"first_name"  db:"first_name"`
+	LastName   string    `json:"last_name"   db:"last_name"`
+	Email      string    `json:"email"       db:"email"`
+	AddressID  int       `json:"address_id"  db:"address_id"`
+	Active     bool      `json:"active"      db:"activebool"`
+	CreateDate time.Time `json:"create_date" db:"create_date"`
+	// GuestMerge is set on registration with a guest session token.
+	GuestMerge *GuestMerge `json:"guest_merge,omitempty" db:"-"`
+}
+
+// DeletedUserName replaces the name on comments by a customer whose data
+// was erased.
+const DeletedUserName = "deleted user"
+
+// DeletedFirstName and DeletedLastName replace the name on the customer
+// record itself once its data is erased.
+const (
+	DeletedFirstName = "deleted"
+	DeletedLastName  = "user"
+)
+
+// CustomerErasure reports what was erased of a customer's personal data.
+// Their rentals and payments are kept as business records.
+type CustomerErasure struct {
+	CustomerID         int       `json:"customer_id"`
+	CommentsAnonymized int       `json:"comments_anonymized"`
+	ListsDeleted       int       `json:"lists_deleted"`
+	FollowsDeleted     int       `json:"follows_deleted"`
+	ActivityDeleted    int       `json:"activity_deleted"`
+	ExportsDeleted     int       `json:"exports_deleted"`
+	AlertsDeleted      int       `json:"alerts_deleted"`
+	SearchesDeleted    int       `json:"saved_searches_deleted"`
+	CredentialsDeleted bool      `json:"credentials_deleted"`
+	ErasedAt           time.Time `json:"erased_at"`
+}
+
+// CustomerMerge reports what was moved from a duplicate customer record to
+// the one it was merged into. The duplicate is kept, deactivated, so rentals
+// and payments elsewhere that name it still resolve.
+type CustomerMerge struct {
+	SourceID            int       `json:"source_id"`
+	TargetID            int       `json:"target_id"`
+	CommentsMoved       int       `json:"comments_moved"`
+	RentalsMoved        int       `json:"rentals_moved"`
+	PaymentsMoved       int       `json:"payments_moved"`
+	ListsMoved          int       `json:"lists_moved"`
+	CheckoutsMoved      int       `json:"checkouts_moved"`
+	LoyaltyEntriesMoved int       `json:"loyalty_entries_moved"`
+	GiftCardsMoved      int       `json:"gift_cards_moved"`
+	FollowsMoved        int       `json:"follows_moved"`
+	SearchesMoved       int       `json:"saved_searches_moved"`
+	AlertsMoved         int       `json:"alerts_moved"`
+	MergedAt            time.Time `json:"merged_at"`
+}
+
+// CustomerRegisterRequest represents the request body for customer registration.
+type CustomerRegisterRequest struct {
+	FirstName string `json:"first_name" validate:"required,max=45"`
+	LastName  string `json:"last_name"  validate:"required,max=45"`
+	Email     string `json:"email"      validate:"required,email,max=50"`
+	AddressID int    `json:"address_id" validate:"required,min=1"`
+	StoreID   int    `json:"store_id"   validate:"required,min=1"`
+	Password  string `json:"password"   validate:"required,min=8,max=72"`
+}
+
+// CustomerLoginRequest represents the request body for customer login.
+type CustomerLoginRequest struct {
+	Email    string `json:"email"    validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// NotificationPreferences maps each notification event type to whether each
+// delivery channel is enabled, e.g. {"rental_due": {"email": true, "sms": false}}.
+type NotificationPreferences map[string]map[string]bool
+
+// NotificationPreferencesResponse reports a customer's effective preferences.
+type NotificationPreferencesResponse struct {
+	CustomerID  int                     `json:"customer_id"`
+	Preferences NotificationPreferences `json:"preferences"`
+}
+
+// NotificationPreferencesRequest updates some or all of a customer's
+// preferences; omitted events and channels are left unchanged.
+type NotificationPreferencesRequest struct {
+	Preferences NotificationPreferences `json:"preferences" validate:"required"`
+}
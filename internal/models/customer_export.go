@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Customer export statuses. An export is pending until its background job
+// has built the file.
+const (
+	CustomerExportStatusPending = "pending"
+	CustomerExportStatusReady   = "ready"
+)
+
+// Customer export formats: a single JSON document, or a ZIP archive with a
+// JSON file per kind of data.
+const (
+	CustomerExportFormatJSON = "json"
+	CustomerExportFormatZIP  = "zip"
+)
+
+// CustomerExport is a request for a copy of a customer's personal data.
+type CustomerExport struct {
+	ID          int        `json:"id"                     db:"id"`
+	CustomerID  int        `json:"customer_id"            db:"customer_id"`
+	Format      string     `json:"format"                 db:"format"`
+	Status      string     `json:"status"                 db:"status"`
+	CreatedAt   time.Time  `json:"created_at"             db:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	// StatusURL is polled until the export is ready; DownloadURL then
+	// serves the file. SignedDownloadURL, when URL signing is enabled,
+	// serves it for a limited time without a bearer token.
+	StatusURL         string `json:"status_url"`
+	DownloadURL       string `json:"download_url"`
+	SignedDownloadURL string `json:"signed_download_url,omitempty"`
+}
+
+// CustomerExportFilters represents the query parameters for requesting an
+// export.
+type CustomerExportFilters struct {
+	Format string `json:"format" validate:"oneof=json zip"`
+}
+
+// CustomerData is the personal data held about a customer, as exported.
+type CustomerData struct {
+	ExportedAt time.Time             `json:"exported_at"`
+	Profile    CustomerProfile       `json:"profile"`
+	Comments   []CustomerDataComment `json:"comments"`
+	Rentals    []RentalEvent         `json:"rentals"`
+}
+
+// CustomerProfile is a customer's account with their address.
+type CustomerProfile struct {
+	Customer
+
+	Address    string  `json:"address"     db:"address"`
+	Address2   *string `json:"address2"    db:"address2"`
+	District   string  `json:"district"    db:"district"`
+	City       string  `json:"city"        db:"city"`
+	Country    string  `json:"country"     db:"country"`
+	PostalCode *string `json:"postal_code" db:"postal_code"`
+	Phone      string  `json:"phone"       db:"phone"`
+}
+
+// CustomerDataComment is a comment a customer posted, as exported.
+type CustomerDataComment struct {
+	ID        int       `json:"id"                  db:"id"`
+	FilmID    int       `json:"film_id"             db:"film_id"`
+	FilmTitle string    `json:"film_title"          db:"title"`
+	ParentID  *int      `json:"parent_id,omitempty" db:"parent_id"`
+	Comment   string    `json:"comment"             db:"comment"`
+	CreatedAt time.Time `json:"created_at"          db:"created_at"`
+}
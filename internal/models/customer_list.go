@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Customer list visibilities. Public lists can be viewed by anyone with
+// their link; private lists only by the customer who made them.
+const (
+	ListVisibilityPublic  = "public"
+	ListVisibilityPrivate = "private"
+)
+
+// MaxListEntries is the most films a customer list can hold.
+const MaxListEntries = 500
+
+// CustomerList is a named list of films curated by a customer, such as
+// "Best 80s Horror", with its entries in the customer's order. Listings of
+// a customer's lists leave the entries out and give their count.
+type CustomerList struct {
+	ID          int                 `json:"id"                    db:"id"`
+	CustomerID  int                 `json:"customer_id"           db:"customer_id"`
+	Name        string              `json:"name"                  db:"name"`
+	Description *string             `json:"description,omitempty" db:"description"`
+	Visibility  string              `json:"visibility"            db:"visibility"`
+	EntryCount  int                 `json:"entry_count"           db:"entry_count"`
+	Entries     []CustomerListEntry `json:"entries,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"            db:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"            db:"updated_at"`
+}
+
+// CustomerListEntry is a film on a customer list at its 1-based position.
+type CustomerListEntry struct {
+	FilmID      int       `json:"film_id"                db:"film_id"`
+	Title       string    `json:"title"                  db:"title"`
+	ReleaseYear *int      `json:"release_year,omitempty" db:"release_year"`
+	Rating      string    `json:"rating"                 db:"rating"`
+	Position    int       `json:"position"               db:"position"`
+	AddedAt     time.Time `json:"added_at"               db:"added_at"`
+}
+
+// CustomerListRequest represents the request body for creating or
+// replacing a customer list's details. Lists are private unless made public.
+type CustomerListRequest struct {
+	Name        string  `json:"name"                  validate:"required,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Visibility  string  `json:"visibility,omitempty"  validate:"omitempty,oneof=public private"`
+}
+
+// ListEntryRequest represents the request body for adding a film to the end
+// of a customer list.
+type ListEntryRequest struct {
+	FilmID int `json:"film_id" validate:"required,min=1"`
+}
+
+// ListOrderRequest represents the request body for reordering a customer
+// list. It must name every film on the list exactly once, so is no longer
+// than MaxListEntries.
+type ListOrderRequest struct {
+	FilmIDs []int `json:"film_ids" validate:"required,min=1,max=500,unique,dive,min=1"`
+}
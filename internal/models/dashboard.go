@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Dashboard summarizes the state of the business for the admin dashboard.
+// Rentals today and revenue this month are counted from the start of the
+// database's current day and month. Pending comments are top-level comments
+// nobody has replied to yet. The error rate is the share of this replica's
+// HTTP responses since it started that were 5xx.
+type Dashboard struct {
+	FilmsCount       int       `json:"films_count"`
+	RentalsToday     int       `json:"rentals_today"`
+	PendingComments  int       `json:"pending_comments"`
+	RevenueThisMonth float64   `json:"revenue_this_month"`
+	ErrorRate        float64   `json:"error_rate"`
+	GeneratedAt      time.Time `json:"generated_at"`
+}
+
+// CategoryStats summarizes a category's films and their rentals for admins.
+// Revenue sums the payments taken for those rentals. The films carry MPAA
+// ratings rather than scores, so FilmsByRating counts them by rating in
+// place of an average. GeneratedAt is when the statistics were computed,
+// which may be up to the cache's time-to-live ago.
+type CategoryStats struct {
+	CategoryID    int            `json:"category_id"`
+	Name          string         `json:"name"`
+	FilmsCount    int            `json:"films_count"`
+	RentalsCount  int            `json:"rentals_count"`
+	Revenue       float64        `json:"revenue"`
+	FilmsByRating map[string]int `json:"films_by_rating"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+}
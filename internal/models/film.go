@@ -3,62 +3,279 @@ package models
 
 import (
 	"time"
+
+	"github.com/rxbenefits/go-hw/internal/pagination"
 )
 
 // Film represents a movie in the database.
 type Film struct {
-	FilmID          int       `json:"film_id"                    db:"film_id"`
-	Title           string    `json:"title"                      db:"title"            validate:"required"`
-	Description     *string   `json:"description,omitempty"      db:"description"`
-	ReleaseYear     *int      `json:"release_year,omitempty"     db:"release_year"`
-	LanguageID      int       `json:"language_id"                db:"language_id"`
-	RentalDuration  int       `json:"rental_duration"            db:"rental_duration"`
-	RentalRate      float64   `json:"rental_rate"                db:"rental_rate"`
-	Length          *int      `json:"length,omitempty"           db:"length"`
-	ReplacementCost float64   `json:"replacement_cost"           db:"replacement_cost"`
-	Rating          string    `json:"rating"                     db:"rating"`
-	LastUpdate      time.Time `json:"last_update"                db:"last_update"`
-	SpecialFeatures []string  `json:"special_features,omitempty" db:"special_features"`
-	Categories      []string  `json:"categories,omitempty"`
-	Actors          []string  `json:"actors,omitempty"`
-}
-
-// FilmListResponse represents the response for listing films.
-type FilmListResponse struct {
-	Films []Film `json:"films"`
-	Total int    `json:"total"`
-	Page  int    `json:"page"`
-	Limit int    `json:"limit"`
-}
-
-// FilmFilters represents filters for film search.
+	FilmID          int              `json:"film_id"                    db:"film_id"`
+	Title           string           `json:"title"                      db:"title"            validate:"required"`
+	Description     *string          `json:"description,omitempty"      db:"description"`
+	ReleaseYear     *int             `json:"release_year,omitempty"     db:"release_year"`
+	LanguageID      int              `json:"language_id"                db:"language_id"`
+	RentalDuration  int              `json:"rental_duration"            db:"rental_duration"`
+	RentalRate      float64          `json:"rental_rate"                db:"rental_rate"`
+	Length          *int             `json:"length,omitempty"           db:"length"`
+	ReplacementCost float64          `json:"replacement_cost"           db:"replacement_cost"`
+	Rating          string           `json:"rating"                     db:"rating"`
+	LastUpdate      time.Time        `json:"last_update"                db:"last_update"`
+	SpecialFeatures []string         `json:"special_features,omitempty" db:"special_features"`
+	Categories      []string         `json:"categories,omitempty"`
+	Actors          []string         `json:"actors,omitempty"`
+	Tags            []string         `json:"tags,omitempty"`
+	Collections     []FilmCollection `json:"collections,omitempty"`
+	Status          string           `json:"status"                     db:"status"`
+	PublishAt       *time.Time       `json:"publish_at,omitempty"       db:"publish_at"`
+	// LocalRatings holds Rating in each configured local rating system that
+	// covers it, keyed by system, such as {"UK": "12A"}.
+	LocalRatings map[string]string `json:"local_ratings,omitempty"`
+}
+
+// Film statuses. Only published films are shown outside the admin API;
+// drafts are not yet released and archived films are withdrawn.
+const (
+	FilmDraft     = "draft"
+	FilmPublished = "published"
+	FilmArchived  = "archived"
+)
+
+// FilmStatuses lists every film status.
+var FilmStatuses = []string{FilmDraft, FilmPublished, FilmArchived} //nolint:gochecknoglobals // Read-only
+
+// FilmStatusRequest represents a request to change a film's status.
+type FilmStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=draft published archived"`
+}
+
+// FilmStatus reports a film's status after a change, with when a draft is
+// scheduled to be published.
+type FilmStatus struct {
+	FilmID     int        `json:"film_id"`
+	Status     string     `json:"status"`
+	PublishAt  *time.Time `json:"publish_at,omitempty"`
+	LastUpdate time.Time  `json:"last_update"`
+}
+
+// FilmScheduleRequest represents a request to publish a draft film at
+// PublishAt, or to cancel its schedule when PublishAt is null.
+type FilmScheduleRequest struct {
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// FilmUpdateRequest represents an edit of a film's details. Only the fields
+// given are changed; a null or missing field keeps its value.
+type FilmUpdateRequest struct {
+	Title           *string  `json:"title"            validate:"omitnil,min=1,max=255"`
+	Description     *string  `json:"description"`
+	ReleaseYear     *int     `json:"release_year"     validate:"omitnil,min=1901,max=2155"`
+	RentalDuration  *int     `json:"rental_duration"  validate:"omitnil,min=1,max=365"`
+	RentalRate      *float64 `json:"rental_rate"      validate:"omitnil,gte=0,lte=99.99"`
+	Length          *int     `json:"length"           validate:"omitnil,min=1,max=32767"`
+	ReplacementCost *float64 `json:"replacement_cost" validate:"omitnil,gte=0,lte=999.99"`
+	Rating          *string  `json:"rating"           validate:"omitnil,oneof=G PG PG-13 R NC-17"`
+}
+
+// Empty reports whether the request changes nothing.
+func (u FilmUpdateRequest) Empty() bool {
+	return u.Title == nil && u.Description == nil && u.ReleaseYear == nil && u.RentalDuration == nil &&
+		u.RentalRate == nil && u.Length == nil && u.ReplacementCost == nil && u.Rating == nil
+}
+
+// BulkPriceRequest represents a change to the rental rate of many films,
+// either to RentalRate or by Percent, such as 10 to raise rates by a tenth
+// or -10 to lower them. Exactly one must be given. With DryRun the change is
+// worked out and reported but not made.
+type BulkPriceRequest struct {
+	RentalRate *float64 `json:"rental_rate" validate:"omitnil,gte=0,lte=99.99"`
+	Percent    *float64 `json:"percent"     validate:"omitnil,gt=-100,lte=1000"`
+	DryRun     bool     `json:"dry_run"`
+}
+
+// BulkPriceResult reports how many films a bulk price change matched and
+// how many of their rates it changed, or would have changed in a dry run.
+type BulkPriceResult struct {
+	FilmsMatched int  `json:"films_matched"`
+	FilmsChanged int  `json:"films_changed"`
+	DryRun       bool `json:"dry_run"`
+}
+
+// Count modes for FilmFilters.CountMode, reported back as a page's
+// TotalMode. An empty mode means CountExact.
+const (
+	CountExact    = "exact"
+	CountEstimate = "estimate"
+	CountNone     = "none"
+)
+
+// FilmFilters represents filters for film search. Ratings, Categories, and
+// Tags match any of the given values (OR semantics), and Language matches a
+// language's name case-insensitively. With IncludeSubcategories, Categories
+// also match films in any category beneath them. Statuses match any of the
+// given film statuses, and only published films when empty; the public
+// listing always leaves it empty. IncludeArchived adds archived films to an
+// admin listing that names no statuses. The query tags name the parameters GET
+// /films binds, and the validate tags are checked by the film service along
+// with the configured page size limit.
 type FilmFilters struct {
-	Title    string `json:"title,omitempty"`
-	Rating   string `json:"rating,omitempty"`
-	Category string `json:"category,omitempty"`
-	Page     int    `json:"page,omitempty"`
-	Limit    int    `json:"limit,omitempty"`
+	Title      string   `json:"title,omitempty"      query:"title"`
+	Ratings    []string `json:"ratings,omitempty"    query:"rating"   validate:"dive,oneof=G PG PG-13 R NC-17"`
+	Categories []string `json:"categories,omitempty" query:"category" validate:"dive,notblank"`
+	Tags       []string `json:"tags,omitempty"       query:"tags"     validate:"dive,required"`
+	Actor      string   `json:"actor,omitempty"      query:"actor"`
+	Language   string   `json:"language,omitempty"   query:"language"`
+	StoreID    int      `json:"store_id,omitempty"`
+	CountMode  string   `json:"count_mode,omitempty" query:"count"    validate:"omitempty,oneof=exact estimate none"`
+	Statuses   []string `json:"statuses,omitempty"   query:"status"   validate:"dive,oneof=draft published archived"`
+
+	IncludeSubcategories bool `json:"include_subcategories,omitempty" query:"include_subcategories"`
+	IncludeArchived      bool `json:"include_archived,omitempty"      query:"include_archived"`
+	pagination.Params
+}
+
+// FilmExportFilters selects the films of a catalog export: those stocked at
+// StoreID when it is set, after AfterFilmID to resume an interrupted export.
+type FilmExportFilters struct {
+	StoreID     int `query:"store_id"`
+	AfterFilmID int `query:"after_film_id"`
 }
 
 // Comment represents a customer comment on a film.
 type Comment struct {
-	ID           int       `json:"id"            db:"id"`
-	FilmID       int       `json:"film_id"       db:"film_id"       validate:"required"`
-	CustomerName string    `json:"customer_name" db:"customer_name" validate:"required"`
-	Comment      string    `json:"comment"       db:"comment"       validate:"required"`
-	CreatedAt    time.Time `json:"created_at"    db:"created_at"`
+	ID     int `json:"id"      db:"id"`
+	FilmID int `json:"film_id" db:"film_id" validate:"required"`
+	// CustomerName is the name given with a guest comment.
+	CustomerName string `json:"customer_name,omitempty" db:"customer_name"`
+	// Comment is the comment as written, in Markdown; CommentHTML is it
+	// rendered to sanitized HTML, safe to insert into a page.
+	Comment     string    `json:"comment"      db:"comment"    validate:"required"`
+	CommentHTML string    `json:"comment_html"`
+	CreatedAt   time.Time `json:"created_at"   db:"created_at"`
+	// DisplayName is the linked customer's first name and last initial, or
+	// the guest's name, resolved when the comment is read.
+	DisplayName string `json:"display_name"`
+	// Verified is set for comments posted by a logged-in customer.
+	Verified   bool `json:"verified"`
+	CustomerID *int `json:"customer_id,omitempty" db:"customer_id"`
+	// VerifiedRenter is set when that customer has rented the film.
+	VerifiedRenter bool `json:"verified_renter"`
+	// ParentID is set on replies to another comment on the same film.
+	ParentID *int `json:"parent_id,omitempty" db:"parent_id"`
+	// Mentions are the IDs of the customers the comment mentions.
+	Mentions []int `json:"mentions,omitempty"`
+	// AttachmentURL and ThumbnailURL locate the image attached to the
+	// comment, if any, and a small JPEG of it.
+	AttachmentURL string `json:"attachment_url,omitempty"`
+	ThumbnailURL  string `json:"thumbnail_url,omitempty"`
+	// AttachmentKey and ThumbnailKey are where the storage backend keeps them.
+	AttachmentKey string `json:"-"`
+	ThumbnailKey  string `json:"-"`
+}
+
+// CommentAuthor identifies the customer behind a verified comment.
+type CommentAuthor struct {
+	CustomerID int    `json:"customer_id"`
+	FirstName  string `json:"first_name"`
+	Email      string `json:"email"`
+}
+
+// CommentSummary summarizes a film's comments as of RefreshedAt, when it
+// was last recomputed.
+type CommentSummary struct {
+	FilmID          int        `json:"film_id"`
+	CommentCount    int        `json:"comment_count"`
+	LatestCommentAt *time.Time `json:"latest_comment_at"`
+	// TopKeywords are the words used most in the film's comments, most used
+	// first, leaving out common English words.
+	TopKeywords       []string                 `json:"top_keywords"`
+	RatingCorrelation CommentRatingCorrelation `json:"rating_correlation"`
+	RefreshedAt       time.Time                `json:"refreshed_at"`
+}
+
+// CommentRatingCorrelation compares a film's comment count with those of
+// films sharing its rating. Films have MPAA ratings rather than customer
+// scores, so this shows whether the film draws more or less discussion
+// than is usual for its rating. RelativeActivity is the film's count over
+// AverageComments, zero when no film with the rating has comments.
+type CommentRatingCorrelation struct {
+	Rating           string  `json:"rating"`
+	AverageComments  float64 `json:"average_comments"`
+	RelativeActivity float64 `json:"relative_activity"`
+}
+
+// CommentLimits are the page sizes of a film's comments.
+var CommentLimits = pagination.Limits{DefaultLimit: 50, MaxLimit: 100} //nolint:gochecknoglobals // Read-only
+
+// CommentFilters selects a page of a film's comments, newest first: the
+// first page, or with After set to the previous page's NextCursor. Pages
+// are not numbered, as offsets are slow on films with many comments and
+// shift as comments are posted; Page is only bound to reject requests for
+// numbered pages.
+type CommentFilters struct {
+	After string `json:"after,omitempty" query:"after"`
+	Limit int    `json:"limit,omitempty" query:"limit"`
+	Page  int    `json:"-"               query:"page"`
 }
 
 // CommentRequest represents the request to add a comment.
 type CommentRequest struct {
-	CustomerName string `json:"customer_name" validate:"required"`
-	Comment      string `json:"comment"       validate:"required"`
+	// CustomerName is required for guest comments and ignored for comments
+	// posted with a customer token, which are linked to the customer.
+	CustomerName string `json:"customer_name" validate:"max=100"`
+	// Comment may use a limited Markdown subset.
+	Comment string `json:"comment" validate:"required"`
+	// ParentID makes the comment a reply to another comment on the same film.
+	ParentID *int `json:"parent_id,omitempty" validate:"omitempty,min=1"`
+	// CustomerID is set from the caller's customer token, never the body.
+	CustomerID *int `json:"-"`
+	// MentionIDs are the customers mentioned in Comment as @<customer_id>,
+	// parsed from it server-side.
+	MentionIDs []int `json:"-"`
+	// Attachment is an image uploaded with the comment as multipart form
+	// data; AttachmentKey and ThumbnailKey are set once it is stored.
+	Attachment    *CommentUpload `json:"-"`
+	AttachmentKey string         `json:"-"`
+	ThumbnailKey  string         `json:"-"`
+}
+
+// CommentUpload is a file uploaded with a comment.
+type CommentUpload struct {
+	Data []byte
+}
+
+// CommentLockRequest represents the request body for locking or unlocking
+// comments on a film.
+type CommentLockRequest struct {
+	Locked *bool `json:"locked" validate:"required"`
+}
+
+// CommentLockStatus reports whether new comments on a film are blocked.
+type CommentLockStatus struct {
+	FilmID   int        `json:"film_id"`
+	Locked   bool       `json:"locked"`
+	LockedAt *time.Time `json:"locked_at,omitempty"`
+}
+
+// ShadowBanRequest represents the request body for shadow-banning a
+// customer or lifting their ban.
+type ShadowBanRequest struct {
+	Banned *bool `json:"banned" validate:"required"`
 }
 
-// Category represents a film category.
+// ShadowBanStatus reports whether a customer's comments are hidden from
+// everyone but themselves.
+type ShadowBanStatus struct {
+	CustomerID int        `json:"customer_id"`
+	Banned     bool       `json:"banned"`
+	BannedAt   *time.Time `json:"banned_at,omitempty"`
+}
+
+// Category represents a film category. ParentID is set for a subcategory,
+// such as Animation under Family.
 type Category struct {
-	CategoryID int    `json:"category_id" db:"category_id"`
-	Name       string `json:"name"        db:"name"`
+	CategoryID int    `json:"category_id"         db:"category_id"`
+	Name       string `json:"name"                db:"name"`
+	ParentID   *int   `json:"parent_id,omitempty" db:"parent_id"`
 }
 
 // Actor represents a film actor.
@@ -73,10 +290,28 @@ type WelcomeResponse struct {
 	Message string `json:"message" example:"Welcome to Mockbuster Movie API!"`
 }
 
+// MessageResponse represents a plain acknowledgement message.
+type MessageResponse struct {
+	Message string `json:"message" example:"Cache purged"`
+}
+
+// MaintenanceStatus reports whether the API is in read-only maintenance mode.
+type MaintenanceStatus struct {
+	Enabled bool `json:"enabled" example:"false"`
+}
+
+// MaintenanceRequest turns maintenance mode on or off.
+type MaintenanceRequest struct {
+	Enabled *bool `json:"enabled" validate:"required" example:"true"`
+}
+
 // ErrorResponse represents an error response.
 type ErrorResponse struct {
 	Error   string `json:"error"             example:"Failed to retrieve films"`
 	Details string `json:"details,omitempty" example:"database connection failed"`
+	// Code identifies errors clients are expected to handle, such as
+	// "comments_locked".
+	Code string `json:"code,omitempty" example:"comments_locked"`
 }
 
 // APIInfoResponse represents the API information response.
@@ -87,3 +322,9 @@ type APIInfoResponse struct {
 	Endpoints     []string `json:"endpoints"     example:"GET /api/v1/films"`
 	Documentation string   `json:"documentation" example:"http://localhost:8080/swagger/"`
 }
+
+// FilmUpdate is when a film was last changed, as listed in sitemaps.
+type FilmUpdate struct {
+	FilmID     int       `json:"film_id"     db:"film_id"`
+	LastUpdate time.Time `json:"last_update" db:"last_update"`
+}
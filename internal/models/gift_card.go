@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Gift card statuses.
+const (
+	GiftCardStatusPending = "pending"
+	GiftCardStatusActive  = "active"
+	GiftCardStatusFailed  = "failed"
+)
+
+// Gift card ledger entry reasons.
+const (
+	GiftCardReasonPurchase = "purchase"
+	GiftCardReasonCheckout = "checkout"
+)
+
+// GiftCard is a gift card bought through a payment provider. Its code is
+// only known when the card is bought; afterwards it is identified by the
+// code's last four characters. The buyer pays with the provider using
+// ClientSecret, and the card is active once the payment succeeds.
+type GiftCard struct {
+	ID                int       `json:"id"                      db:"id"`
+	Code              string    `json:"code,omitempty"`
+	LastFour          string    `json:"last_four"               db:"last_four"`
+	PurchaserID       int       `json:"purchaser_id"            db:"purchaser_id"`
+	Amount            float64   `json:"amount"                  db:"amount"`
+	Balance           float64   `json:"balance"`
+	Currency          string    `json:"currency"                db:"currency"`
+	Status            string    `json:"status"                  db:"status"`
+	Provider          string    `json:"provider"                db:"provider"`
+	ProviderPaymentID string    `json:"-"                       db:"provider_payment_id"`
+	ClientSecret      string    `json:"client_secret,omitempty" db:"client_secret"`
+	CreatedAt         time.Time `json:"created_at"              db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"              db:"updated_at"`
+}
+
+// GiftCardPurchaseRequest represents the request body for buying a gift
+// card.
+type GiftCardPurchaseRequest struct {
+	Amount float64 `json:"amount" validate:"required,gte=5,lte=500"`
+}
+
+// GiftCardBalanceRequest represents the request body for checking a gift
+// card's balance.
+type GiftCardBalanceRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// GiftCardBalance is what a gift card code is worth, shown to anyone
+// holding the code.
+type GiftCardBalance struct {
+	LastFour string  `json:"last_four"`
+	Balance  float64 `json:"balance"`
+	Currency string  `json:"currency"`
+	Status   string  `json:"status"`
+}
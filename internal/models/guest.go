@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// GuestWatchlistName names the list a guest's watchlist becomes when they
+// register.
+const GuestWatchlistName = "Watchlist"
+
+// GuestSession is an anonymous session issued to a guest, with the token
+// that identifies it.
+type GuestSession struct {
+	GuestID   int       `json:"guest_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GuestWatchlistEntry is a film on a guest's watchlist.
+type GuestWatchlistEntry struct {
+	FilmID  int       `json:"film_id"  db:"film_id"`
+	Title   string    `json:"title"    db:"title"`
+	AddedAt time.Time `json:"added_at" db:"added_at"`
+}
+
+// GuestMerge reports what a guest session brought into the account
+// registered with it: the guest's comments, now linked to the customer, and
+// their watchlist, now the customer's private list ListID.
+type GuestMerge struct {
+	GuestID        int  `json:"guest_id"`
+	CommentsLinked int  `json:"comments_linked"`
+	WatchlistFilms int  `json:"watchlist_films"`
+	ListID         *int `json:"list_id,omitempty"`
+}
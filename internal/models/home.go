@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// Home feed sections, as named in HomeFeed.Incomplete.
+const (
+	HomeSectionOpenRentals    = "open_rentals"
+	HomeSectionRecommended    = "recommended"
+	HomeSectionNewInFavorites = "new_in_favorites"
+)
+
+// HomeFilm is a film shown in a home feed section.
+type HomeFilm struct {
+	FilmID      int    `json:"film_id"                db:"film_id"`
+	Title       string `json:"title"                  db:"title"`
+	ReleaseYear *int   `json:"release_year,omitempty" db:"release_year"`
+	Rating      string `json:"rating,omitempty"       db:"rating"`
+}
+
+// HomeFeed is a customer's personalized home page: the rentals they have
+// out, films recommended from what customers with similar rentals took
+// out, and recent films in the categories they rent most.
+type HomeFeed struct {
+	CustomerID         int           `json:"customer_id"`
+	OpenRentals        []RentalEvent `json:"open_rentals"`
+	Recommended        []HomeFilm    `json:"recommended"`
+	FavoriteCategories []string      `json:"favorite_categories"`
+	NewInFavorites     []HomeFilm    `json:"new_in_favorites"`
+	// Incomplete names the sections left empty because they failed or were
+	// not ready within the feed's latency budget.
+	Incomplete  []string  `json:"incomplete,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Inventory copy statuses reported by InventoryCopy.Status.
+const (
+	CopyStatusInStock = "in_stock"
+	CopyStatusRented  = "rented"
+	CopyStatusLost    = "lost"
+	CopyStatusRetired = "retired"
+)
+
+// InventoryCopy is one physical copy of a film at a store. Rented copies
+// carry their open rental; a copy still out at twice the film's rental
+// duration is reported as lost.
+type InventoryCopy struct {
+	InventoryID int        `json:"inventory_id"          db:"inventory_id"`
+	FilmID      int        `json:"film_id"               db:"film_id"`
+	StoreID     int        `json:"store_id"              db:"store_id"`
+	Status      string     `json:"status"`
+	RentalID    *int       `json:"rental_id,omitempty"   db:"rental_id"`
+	CustomerID  *int       `json:"customer_id,omitempty" db:"customer_id"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	RetiredAt   *time.Time `json:"retired_at,omitempty"  db:"retired_at"`
+	LastUpdate  time.Time  `json:"last_update"           db:"last_update"`
+}
+
+// InventoryRequest represents the request to stock copies of a film at a
+// store.
+type InventoryRequest struct {
+	StoreID int `json:"store_id" validate:"required,min=1"`
+	// Copies is the number of copies to add, one if unset.
+	Copies int `json:"copies" validate:"omitempty,min=1,max=100"`
+}
+
+// InventoryFilters narrows a film's inventory listing.
+type InventoryFilters struct {
+	StoreID int    `json:"store_id,omitempty"`
+	Status  string `json:"status,omitempty"   validate:"omitempty,oneof=in_stock rented lost retired"`
+}
+
+// InventoryTransferRequest represents the request to move a copy to another
+// store.
+type InventoryTransferRequest struct {
+	ToStoreID int    `json:"to_store_id" validate:"required,min=1"`
+	Reason    string `json:"reason"      validate:"max=255"`
+}
+
+// InventoryTransfer records a copy moving between stores.
+type InventoryTransfer struct {
+	ID            int       `json:"id"               db:"id"`
+	InventoryID   int       `json:"inventory_id"     db:"inventory_id"`
+	FromStoreID   int       `json:"from_store_id"    db:"from_store_id"`
+	ToStoreID     int       `json:"to_store_id"      db:"to_store_id"`
+	Reason        string    `json:"reason,omitempty" db:"reason"`
+	TransferredAt time.Time `json:"transferred_at"   db:"transferred_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Loyalty ledger entry reasons.
+const (
+	LoyaltyReasonRental     = "rental"
+	LoyaltyReasonComment    = "comment"
+	LoyaltyReasonRedemption = "redemption"
+	LoyaltyReasonCheckout   = "checkout"
+)
+
+// LoyaltyEntry is one change to a customer's loyalty points or rental
+// credit. Points earned and credit gained are positive; points redeemed and
+// credit spent are negative. ReferenceID is the rental, comment, or
+// checkout the entry is for, if any.
+type LoyaltyEntry struct {
+	ID          int       `json:"id"                     db:"id"`
+	CustomerID  int       `json:"customer_id"            db:"customer_id"`
+	Points      int       `json:"points"                 db:"points"`
+	Credit      float64   `json:"credit"                 db:"credit"`
+	Reason      string    `json:"reason"                 db:"reason"`
+	ReferenceID *int      `json:"reference_id,omitempty" db:"reference_id"`
+	CreatedAt   time.Time `json:"created_at"             db:"created_at"`
+}
+
+// LoyaltyBalance is a customer's points and unspent rental credit.
+type LoyaltyBalance struct {
+	CustomerID int     `json:"customer_id"`
+	Points     int     `json:"points"`
+	Credit     float64 `json:"credit"`
+}
+
+// LoyaltyHistoryFilters selects a page of a customer's ledger.
+type LoyaltyHistoryFilters struct {
+	Page  int `json:"page"  validate:"min=1"`
+	Limit int `json:"limit" validate:"min=1,max=100"`
+}
+
+// RedeemPointsRequest represents the request body for redeeming points for
+// rental credit.
+type RedeemPointsRequest struct {
+	Points int `json:"points" validate:"required,min=1"`
+}
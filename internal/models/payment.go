@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Payment statuses, also used for refunds.
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusSucceeded = "succeeded"
+	PaymentStatusFailed    = "failed"
+)
+
+// Payment is the payment collected for a checkout through a payment
+// provider. The client completes it with the provider using ClientSecret.
+type Payment struct {
+	ID                int       `json:"id"                  db:"id"`
+	CheckoutID        int       `json:"checkout_id"         db:"checkout_id"`
+	Provider          string    `json:"provider"            db:"provider"`
+	ProviderPaymentID string    `json:"provider_payment_id" db:"provider_payment_id"`
+	ClientSecret      string    `json:"client_secret"       db:"client_secret"`
+	Amount            float64   `json:"amount"              db:"amount"`
+	RefundedAmount    float64   `json:"refunded_amount"     db:"refunded_amount"`
+	Currency          string    `json:"currency"            db:"currency"`
+	Status            string    `json:"status"              db:"status"`
+	CreatedAt         time.Time `json:"created_at"          db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"          db:"updated_at"`
+}
+
+// RentalPayment is the share of a checkout payment recorded against one of
+// its rentals.
+type RentalPayment struct {
+	RentalID int
+	Amount   float64
+}
+
+// RefundRequest represents the request body for refunding a payment. An
+// omitted amount refunds all that has not been refunded yet.
+type RefundRequest struct {
+	Amount float64 `json:"amount" validate:"omitempty,gt=0"`
+	Reason string  `json:"reason" validate:"max=255"`
+}
+
+// Refund is a return of part or all of a checkout payment through its
+// provider.
+type Refund struct {
+	ID               int       `json:"id"                 db:"id"`
+	PaymentID        int       `json:"payment_id"         db:"payment_id"`
+	Amount           float64   `json:"amount"             db:"amount"`
+	Reason           string    `json:"reason,omitempty"   db:"reason"`
+	ProviderRefundID string    `json:"provider_refund_id" db:"provider_refund_id"`
+	Status           string    `json:"status"             db:"status"`
+	CreatedAt        time.Time `json:"created_at"         db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"         db:"updated_at"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Pricing rule kinds.
+const (
+	// PricingRulePercentOff takes a percentage off the rental rate.
+	PricingRulePercentOff = "percent_off"
+	// PricingRuleBundle charges for BundlePaid of every BundleQuantity films
+	// rented together.
+	PricingRuleBundle = "bundle"
+)
+
+// PricingRule is an admin-defined discount. CategoryID, StoreID,
+// WeekendsOnly, StartsAt, and EndsAt narrow which rentals it applies to;
+// unset, they do not restrict it.
+type PricingRule struct {
+	ID             int        `json:"id"                        db:"id"`
+	Name           string     `json:"name"                      db:"name"`
+	Kind           string     `json:"kind"                      db:"kind"`
+	PercentOff     *float64   `json:"percent_off,omitempty"     db:"percent_off"`
+	BundleQuantity *int       `json:"bundle_quantity,omitempty" db:"bundle_quantity"`
+	BundlePaid     *int       `json:"bundle_paid,omitempty"     db:"bundle_paid"`
+	CategoryID     *int       `json:"category_id,omitempty"     db:"category_id"`
+	StoreID        *int       `json:"store_id,omitempty"        db:"store_id"`
+	WeekendsOnly   bool       `json:"weekends_only"             db:"weekends_only"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"       db:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"         db:"ends_at"`
+	CreatedAt      time.Time  `json:"created_at"                db:"created_at"`
+}
+
+// PricingRuleRequest represents the request to create a pricing rule.
+type PricingRuleRequest struct {
+	Name           string     `json:"name"            validate:"required,max=100"`
+	Kind           string     `json:"kind"            validate:"required,oneof=percent_off bundle"`
+	PercentOff     *float64   `json:"percent_off"     validate:"required_if=Kind percent_off,omitempty,gt=0,lte=100"`
+	BundleQuantity *int       `json:"bundle_quantity" validate:"required_if=Kind bundle,omitempty,min=2"`
+	BundlePaid     *int       `json:"bundle_paid"     validate:"required_if=Kind bundle,omitempty,min=1"`
+	CategoryID     *int       `json:"category_id"     validate:"omitempty,min=1"`
+	StoreID        *int       `json:"store_id"        validate:"omitempty,min=1"`
+	WeekendsOnly   bool       `json:"weekends_only"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+}
+
+// FilmPricing is what a price quote needs to know about a film.
+type FilmPricing struct {
+	FilmID      int
+	RentalRate  float64
+	CategoryIDs []int
+}
+
+// AppliedDiscount is a percent_off rule applied to a price quote.
+type AppliedDiscount struct {
+	RuleID     int     `json:"rule_id"`
+	Name       string  `json:"name"`
+	PercentOff float64 `json:"percent_off"`
+	AmountOff  float64 `json:"amount_off"`
+}
+
+// BundleOffer is a bundle rule the film qualifies for, with the average
+// rate per film when rented as part of the bundle.
+type BundleOffer struct {
+	RuleID      int     `json:"rule_id"`
+	Name        string  `json:"name"`
+	Quantity    int     `json:"quantity"`
+	Paid        int     `json:"paid"`
+	RatePerFilm float64 `json:"rate_per_film"`
+}
+
+// FilmPrice is the rental rate of a film after discounts at a point in time.
+type FilmPrice struct {
+	FilmID        int               `json:"film_id"`
+	BaseRate      float64           `json:"base_rate"`
+	EffectiveRate float64           `json:"effective_rate"`
+	PricedAt      time.Time         `json:"priced_at"`
+	AppliedRules  []AppliedDiscount `json:"applied_rules"`
+	BundleOffers  []BundleOffer     `json:"bundle_offers"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// Readiness reports whether a replica should receive traffic. A replica is
+// ready only while the database is at the schema version its build was
+// migrated to: during a rolling deploy, old replicas stop being ready once
+// the new schema is applied, and new ones until it is.
+type Readiness struct {
+	Ready           bool   `json:"ready"`
+	SchemaVersion   int64  `json:"schema_version"`
+	ExpectedVersion int64  `json:"expected_version"`
+	Reason          string `json:"reason,omitempty"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Receipt sets out what was charged for one rental: the film, the store it
+// was rented from, and every payment taken for it. Refunds appear as
+// negative payments.
+type Receipt struct {
+	RentalID      int              `json:"rental_id"`
+	CustomerID    int              `json:"customer_id"`
+	CustomerName  string           `json:"customer_name"`
+	CustomerEmail string           `json:"customer_email,omitempty"`
+	StoreID       int              `json:"store_id"`
+	StoreAddress  string           `json:"store_address"`
+	FilmID        int              `json:"film_id"`
+	FilmTitle     string           `json:"film_title"`
+	RentalDate    time.Time        `json:"rental_date"`
+	DueAt         time.Time        `json:"due_at"`
+	ReturnDate    *time.Time       `json:"return_date"`
+	Payments      []ReceiptPayment `json:"payments"`
+	AmountPaid    float64          `json:"amount_paid"`
+	IssuedAt      time.Time        `json:"issued_at"`
+}
+
+// ReceiptPayment is one payment, or refund, on a receipt.
+type ReceiptPayment struct {
+	PaymentID int       `json:"payment_id" db:"payment_id"`
+	Amount    float64   `json:"amount"     db:"amount"`
+	PaidAt    time.Time `json:"paid_at"    db:"payment_date"`
+}
+
+// Invoice summarizes a rental the customer has been charged for.
+// SignedReceiptURL, when URL signing is enabled, serves the receipt for a
+// limited time without a bearer token.
+type Invoice struct {
+	RentalID         int       `json:"rental_id"                    db:"rental_id"`
+	FilmTitle        string    `json:"film_title"                   db:"title"`
+	RentalDate       time.Time `json:"rental_date"                  db:"rental_date"`
+	AmountPaid       float64   `json:"amount_paid"`
+	LastPaymentAt    time.Time `json:"last_payment_at"`
+	ReceiptURL       string    `json:"receipt_url"`
+	SignedReceiptURL string    `json:"signed_receipt_url,omitempty"`
+}
+
+// InvoiceFilters selects a page of a customer's invoices.
+type InvoiceFilters struct {
+	Page  int `json:"page"  query:"page"  validate:"min=1"`
+	Limit int `json:"limit" query:"limit" validate:"min=1,max=100"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// RentalDueReminder describes an open rental whose customer should be
+// reminded to return it.
+type RentalDueReminder struct {
+	RentalID      int       `db:"rental_id"`
+	CustomerID    int       `db:"customer_id"`
+	CustomerEmail string    `db:"email"`
+	CustomerName  string    `db:"first_name"`
+	FilmTitle     string    `db:"title"`
+	DueAt         time.Time `db:"due_at"`
+}
+
+// TrendingFilm represents a film ranked by recent rentals.
+type TrendingFilm struct {
+	Rank        int       `json:"rank"         db:"rank"`
+	FilmID      int       `json:"film_id"      db:"film_id"`
+	Title       string    `json:"title"        db:"title"`
+	RentalCount int       `json:"rental_count" db:"rental_count"`
+	RefreshedAt time.Time `json:"refreshed_at" db:"refreshed_at"`
+}
+
+// Rental statuses reported by RentalEvent.Status and accepted as filters.
+const (
+	RentalStatusOpen     = "open"
+	RentalStatusReturned = "returned"
+	RentalStatusOverdue  = "overdue"
+)
+
+// RentalEvent is one rental of a copy of a film, with the customer who
+// rented it, when it is due back, and whether it has come back.
+type RentalEvent struct {
+	RentalID     int        `json:"rental_id"     db:"rental_id"`
+	InventoryID  int        `json:"inventory_id"  db:"inventory_id"`
+	StoreID      int        `json:"store_id"      db:"store_id"`
+	FilmID       int        `json:"film_id"       db:"film_id"`
+	FilmTitle    string     `json:"film_title"    db:"title"`
+	CustomerID   int        `json:"customer_id"   db:"customer_id"`
+	CustomerName string     `json:"customer_name"`
+	RentalDate   time.Time  `json:"rental_date"   db:"rental_date"`
+	DueAt        time.Time  `json:"due_at"`
+	ReturnDate   *time.Time `json:"return_date"   db:"return_date"`
+	// Status is open, returned, or overdue: an open rental past its due
+	// date is overdue even before the scheduler flags it.
+	Status string `json:"status"`
+}
+
+// RentalHistoryLimits are the page sizes of rental histories.
+var RentalHistoryLimits = pagination.Limits{DefaultLimit: 20, MaxLimit: 100} //nolint:gochecknoglobals // Read-only
+
+// RentalHistoryFilters narrows a rental history. Rentals are matched on
+// rental date, from From inclusive up to To exclusive, and on Status.
+type RentalHistoryFilters struct {
+	From    *time.Time `json:"from,omitempty"`
+	To      *time.Time `json:"to,omitempty"`
+	StoreID int        `json:"store_id,omitempty"`
+	Status  string     `json:"status,omitempty"   query:"status" validate:"omitempty,oneof=open returned overdue"`
+	pagination.Params
+}
+
+// Sort keys for the overdue rentals report.
+const (
+	OverdueSortDays    = "days"
+	OverdueSortFees    = "fees"
+	OverdueSortRentals = "rentals"
+	OverdueSortTitle   = "title"
+)
+
+// OverdueReportFilters selects the order of the overdue rentals report.
+// LateFeePerDay is set by the service from configuration.
+type OverdueReportFilters struct {
+	Sort          string  `json:"sort"               validate:"oneof=days fees rentals title"`
+	Order         string  `json:"order"              validate:"oneof=asc desc"`
+	StoreID       int     `json:"store_id,omitempty"`
+	LateFeePerDay float64 `json:"-"`
+}
+
+// OverdueFilm aggregates the overdue rentals of one film. Days are counted
+// from the due date, a started day counting in full; each rental accrues the
+// late fee for every day overdue, up to the film's replacement cost.
+type OverdueFilm struct {
+	FilmID           int     `json:"film_id"            db:"film_id"`
+	Title            string  `json:"title"              db:"title"`
+	OverdueRentals   int     `json:"overdue_rentals"`
+	TotalDaysOverdue int     `json:"total_days_overdue"`
+	MaxDaysOverdue   int     `json:"max_days_overdue"`
+	AccruedFees      float64 `json:"accrued_fees"`
+}
+
+// OverdueReport lists the films with overdue rentals and their totals.
+type OverdueReport struct {
+	GeneratedAt    time.Time     `json:"generated_at"`
+	LateFeePerDay  float64       `json:"late_fee_per_day"`
+	OverdueRentals int           `json:"overdue_rentals"`
+	AccruedFees    float64       `json:"accrued_fees"`
+	Films          []OverdueFilm `json:"films"`
+}
+
+// CalendarSubscription is the URL of a customer's rental due-date calendar,
+// which carries its own token, and when that token expires.
+type CalendarSubscription struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+)
+
+// MaxSavedSearches is the most searches a customer can save.
+const MaxSavedSearches = 20
+
+// SavedSearchFilters are the film filters a search saves, matching films as
+// the parameters of the same names on GET /films do.
+type SavedSearchFilters struct {
+	Title                string   `json:"title,omitempty"                 validate:"max=255"`
+	Ratings              []string `json:"ratings,omitempty"               validate:"dive,oneof=G PG PG-13 R NC-17"`
+	Categories           []string `json:"categories,omitempty"            validate:"dive,notblank"`
+	Tags                 []string `json:"tags,omitempty"                  validate:"dive,required"`
+	Actor                string   `json:"actor,omitempty"                 validate:"max=255"`
+	Language             string   `json:"language,omitempty"              validate:"max=20"`
+	IncludeSubcategories bool     `json:"include_subcategories,omitempty"`
+}
+
+// FilmFilters returns the filters as a film listing's.
+func (f SavedSearchFilters) FilmFilters() FilmFilters {
+	return FilmFilters{
+		Title:                f.Title,
+		Ratings:              f.Ratings,
+		Categories:           f.Categories,
+		Tags:                 f.Tags,
+		Actor:                f.Actor,
+		Language:             f.Language,
+		IncludeSubcategories: f.IncludeSubcategories,
+	}
+}
+
+// SavedSearchRequest represents a request to save a search. With Notify, the
+// customer is told about films newly published that match it.
+type SavedSearchRequest struct {
+	Name    string             `json:"name"    validate:"required,max=100"`
+	Filters SavedSearchFilters `json:"filters"`
+	Notify  bool               `json:"notify"`
+}
+
+// SavedSearch is a customer's saved search.
+type SavedSearch struct {
+	ID         int                `json:"id"`
+	CustomerID int                `json:"customer_id"`
+	Name       string             `json:"name"`
+	Filters    SavedSearchFilters `json:"filters"`
+	Notify     bool               `json:"notify"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// SavedSearchAlert is a saved search due to be checked for new films, with
+// the customer to tell and the last time it was checked.
+type SavedSearchAlert struct {
+	SavedSearch
+	CustomerEmail string
+	CustomerName  string
+	LastCheckedAt time.Time
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// SearchMiss is a title search that found no films, with how often and
+// when it was made.
+type SearchMiss struct {
+	Term            string    `json:"term"`
+	Searches        int       `json:"searches"`
+	FirstSearchedAt time.Time `json:"first_searched_at"`
+	LastSearchedAt  time.Time `json:"last_searched_at"`
+}
+
+// SearchMissLimits are the page sizes of the search miss report.
+var SearchMissLimits = pagination.Limits{DefaultLimit: 20, MaxLimit: 100} //nolint:gochecknoglobals // Read-only
+
+// FilmSearchPage is a page of films with, when a title search found none,
+// the titles of films like it.
+type FilmSearchPage struct {
+	*pagination.Paginated[Film]
+	Suggestions []string `json:"suggestions,omitempty"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// AuthSession is the login session of a staff member or customer, identified
+// by role and subject ID. It lasts until ExpiresAt unless revoked first.
+// UserAgent and IPAddress describe the device it was last refreshed from;
+// TwoFactor is set when it was started with a second factor. Current marks
+// the session of the token a listing was requested with.
+type AuthSession struct {
+	ID          int64      `json:"id"                   db:"id"`
+	Role        string     `json:"role"                 db:"role"`
+	SubjectID   int        `json:"subject_id"           db:"subject_id"`
+	UserAgent   string     `json:"user_agent"           db:"user_agent"`
+	IPAddress   string     `json:"ip_address"           db:"ip_address"`
+	CreatedAt   time.Time  `json:"created_at"           db:"created_at"`
+	RefreshedAt time.Time  `json:"refreshed_at"         db:"refreshed_at"`
+	ExpiresAt   time.Time  `json:"expires_at"           db:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	TwoFactor   bool       `json:"two_factor"           db:"two_factor"`
+	Current     bool       `json:"current"`
+}
+
+// SessionClient describes the device a login session is used from.
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+// RefreshRequest represents the request body exchanging a refresh token for
+// a new access token.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ShortLink is a short link to a film, such as /f/k3x9qm2a, optionally
+// tagged with the marketing campaign it was made for, with the number of
+// times it was followed.
+type ShortLink struct {
+	ID            int        `json:"id"                        db:"id"`
+	Code          string     `json:"code"                      db:"code"`
+	Path          string     `json:"path"`
+	FilmID        int        `json:"film_id"                   db:"film_id"`
+	Campaign      *string    `json:"campaign,omitempty"        db:"campaign"`
+	Clicks        int64      `json:"clicks"                    db:"clicks"`
+	CreatedAt     time.Time  `json:"created_at"                db:"created_at"`
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty" db:"last_clicked_at"`
+}
+
+// ShortLinkRequest represents the optional request body for creating a
+// short link.
+type ShortLinkRequest struct {
+	Campaign *string `json:"campaign,omitempty" validate:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Staff represents a store employee. The password hash is never serialized.
+type Staff struct {
+	StaffID    int       `json:"staff_id"        db:"staff_id"`
+	FirstName  string    `json:"first_name"      db:"first_name"`
+	LastName   string    `json:"last_name"       db:"last_name"`
+	Email      *string   `json:"email,omitempty" db:"email"`
+	AddressID  int       `json:"address_id"      db:"address_id"`
+	StoreID    int       `json:"store_id"        db:"store_id"`
+	Active     bool      `json:"active"          db:"active"`
+	Username   string    `json:"username"        db:"username"`
+	LastUpdate time.Time `json:"last_update"     db:"last_update"`
+}
+
+// StaffRequest represents the request body for creating a staff member.
+type StaffRequest struct {
+	FirstName string `json:"first_name" validate:"required,max=45"`
+	LastName  string `json:"last_name"  validate:"required,max=45"`
+	Email     string `json:"email"      validate:"omitempty,email,max=50"`
+	AddressID int    `json:"address_id" validate:"required,min=1"`
+	StoreID   int    `json:"store_id"   validate:"required,min=1"`
+	Username  string `json:"username"   validate:"required,max=16"`
+	Password  string `json:"password"   validate:"required,min=8,max=72"`
+}
+
+// StaffLoginRequest represents the request body for staff login.
+// OTPCode is required once the staff member has enabled two-factor
+// authentication: a code from their authenticator app, or a backup code.
+type StaffLoginRequest struct {
+	Username string `json:"username"           validate:"required"`
+	Password string `json:"password"           validate:"required"`
+	OTPCode  string `json:"otp_code,omitempty"`
+}
+
+// TokenResponse carries a bearer token and its expiry. Tokens issued for a
+// login session come with the refresh token that renews them, which is
+// replaced by a new one each time it is used.
+type TokenResponse struct {
+	Token            string     `json:"token"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RefreshToken     string     `json:"refresh_token,omitempty"`
+	RefreshExpiresAt *time.Time `json:"refresh_expires_at,omitempty"`
+}
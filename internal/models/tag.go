@@ -0,0 +1,19 @@
+package models
+
+// FilmTagRequest represents the request body for tagging a film. Tags are
+// free-form and stored lower case with runs of whitespace collapsed.
+type FilmTagRequest struct {
+	Tag string `json:"tag" validate:"required,max=50"`
+}
+
+// FilmTags lists a film's tags in alphabetical order.
+type FilmTags struct {
+	FilmID int      `json:"film_id"`
+	Tags   []string `json:"tags"`
+}
+
+// TagCount is a tag with the number of films carrying it.
+type TagCount struct {
+	Tag       string `json:"tag"        db:"tag"`
+	FilmCount int    `json:"film_count" db:"film_count"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// StaffTwoFactor is a staff member's TOTP second factor. It is pending, and
+// not yet asked for at login, until Enabled. LastUsedStep is the time step
+// of the last code accepted.
+type StaffTwoFactor struct {
+	StaffID      int    `db:"staff_id"`
+	Secret       string `db:"secret"`
+	Enabled      bool   `db:"enabled"`
+	LastUsedStep int64  `db:"last_used_step"`
+}
+
+// TwoFactorEnrollment is the TOTP secret issued to a staff member, with the
+// otpauth:// URI authenticator apps scan as a QR code and the backup codes.
+// It is only ever shown once.
+type TwoFactorEnrollment struct {
+	Secret          string   `json:"secret"`
+	ProvisioningURI string   `json:"provisioning_uri"`
+	BackupCodes     []string `json:"backup_codes"`
+}
+
+// TwoFactorCodeRequest represents the request body confirming two-factor
+// enrollment with a code from the authenticator app.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook delivery statuses. A failed delivery may still be retried by the
+// job queue, or replayed by an admin.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookSubscription represents an endpoint receiving webhook events. The
+// signing secret is only serialized when it is created or rotated.
+type WebhookSubscription struct {
+	ID              int       `json:"id"               db:"id"`
+	URL             string    `json:"url"              db:"url"`
+	Events          []string  `json:"events"           db:"events"`
+	Active          bool      `json:"active"           db:"active"`
+	Secret          string    `json:"secret,omitempty" db:"secret"`
+	SecretRotatedAt time.Time `json:"secret_rotated_at" db:"secret_rotated_at"`
+	CreatedAt       time.Time `json:"created_at"       db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"       db:"updated_at"`
+}
+
+// WebhookSubscriptionRequest represents the request body for creating a
+// webhook subscription.
+type WebhookSubscriptionRequest struct {
+	URL    string   `json:"url"    validate:"required,url,startswith=http,max=2000"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=comment.created film.published"`
+}
+
+// WebhookDeliveryTarget is what a worker needs to send a delivery: the
+// delivery and its subscription's endpoint and signing secrets.
+type WebhookDeliveryTarget struct {
+	Delivery WebhookDelivery
+	URL      string
+	Active   bool
+	Secret   string
+	// PreviousSecret is set during the grace period after a rotation.
+	PreviousSecret *string
+}
+
+// WebhookDelivery represents one event sent, or to be sent, to a subscription.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"                        db:"id"`
+	SubscriptionID int             `json:"subscription_id"           db:"subscription_id"`
+	Event          string          `json:"event"                     db:"event"`
+	Payload        json.RawMessage `json:"payload"                   db:"payload"`
+	Status         string          `json:"status"                    db:"status"`
+	Attempts       int             `json:"attempts"                  db:"attempts"`
+	ResponseStatus *int            `json:"response_status,omitempty" db:"response_status"`
+	LastError      *string         `json:"last_error,omitempty"      db:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"                db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"                db:"updated_at"`
+}
+
+// WebhookDeliveryFilters represents the query parameters for listing a
+// subscription's deliveries.
+type WebhookDeliveryFilters struct {
+	Status string `json:"status" validate:"omitempty,oneof=pending succeeded failed"`
+	Limit  int    `json:"limit"  validate:"min=1,max=100"`
+}
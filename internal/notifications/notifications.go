@@ -0,0 +1,216 @@
+// Package notifications sends customer emails, such as rental-due reminders
+// and comment-reply notices, off the request path.
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Sender delivers a message through an email backend.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogSender logs messages instead of sending them. It is the default when no
+// email backend is configured.
+type LogSender struct{}
+
+// Send logs msg's recipient and subject.
+func (LogSender) Send(ctx context.Context, msg Message) error {
+	slog.InfoContext(ctx, "Email notification (not sent, no backend configured)",
+		"to", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+// PreferenceChecker reports whether a customer accepts an event's
+// notifications on a channel.
+type PreferenceChecker interface {
+	Allowed(ctx context.Context, customerID int, event, channel string) (bool, error)
+}
+
+// EmailJobKind is the background job kind that delivers notification emails.
+const EmailJobKind = "notifications.email"
+
+// Enqueuer persists a background job for asynchronous processing.
+type Enqueuer interface {
+	Enqueue(kind string, payload any) error
+}
+
+// emailJob is the payload of an EmailJobKind job.
+type emailJob struct {
+	CustomerID int     `json:"customer_id"`
+	Event      string  `json:"event"`
+	Message    Message `json:"message"`
+}
+
+// Notifier queues notifications for asynchronous delivery.
+type Notifier struct {
+	sender      Sender
+	queue       Enqueuer
+	preferences PreferenceChecker
+}
+
+// NotifierOption configures a Notifier.
+type NotifierOption func(*Notifier)
+
+// WithPreferences skips notifications the recipient has opted out of.
+func WithPreferences(checker PreferenceChecker) NotifierOption {
+	return func(n *Notifier) {
+		n.preferences = checker
+	}
+}
+
+// NewNotifier creates a notifier delivering through sender on queue. The
+// queue must route EmailJobKind jobs to HandleEmailJob.
+func NewNotifier(sender Sender, queue Enqueuer, opts ...NotifierOption) *Notifier {
+	n := &Notifier{sender: sender, queue: queue}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Notify queues msg for delivery to a customer, unless they have opted out of
+// event emails. Preferences are checked when the job runs, off the request
+// path.
+func (n *Notifier) Notify(customerID int, event string, msg Message) error {
+	err := n.queue.Enqueue(EmailJobKind, emailJob{CustomerID: customerID, Event: event, Message: msg})
+	if err != nil {
+		return fmt.Errorf("error queueing notification: %w", err)
+	}
+	return nil
+}
+
+// HandleEmailJob delivers a notification queued by Notify. It is the job
+// handler for EmailJobKind.
+func (n *Notifier) HandleEmailJob(ctx context.Context, payload json.RawMessage) error {
+	var job emailJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("error decoding email job: %w", err)
+	}
+
+	if n.preferences != nil {
+		allowed, err := n.preferences.Allowed(ctx, job.CustomerID, job.Event, ChannelEmail)
+		if err != nil {
+			return fmt.Errorf("error checking notification preferences: %w", err)
+		}
+		if !allowed {
+			slog.DebugContext(ctx, "Skipping notification, customer opted out",
+				"customerID", job.CustomerID, "event", job.Event)
+			return nil
+		}
+	}
+	return n.sender.Send(ctx, job.Message)
+}
+
+// CommentReply describes a reply to a customer's comment.
+type CommentReply struct {
+	RecipientID    int
+	RecipientEmail string
+	RecipientName  string
+	FilmTitle      string
+	ReplierName    string
+	Reply          string
+}
+
+// NotifyCommentReply tells a comment's author that someone replied.
+func (n *Notifier) NotifyCommentReply(reply CommentReply) error {
+	return n.Notify(reply.RecipientID, EventCommentReply, Message{
+		To:      reply.RecipientEmail,
+		Subject: fmt.Sprintf("New reply to your comment on %s", reply.FilmTitle),
+		Body: fmt.Sprintf("Hi %s,\n\n%s replied to your comment on %s:\n\n%s\n",
+			reply.RecipientName, reply.ReplierName, reply.FilmTitle, reply.Reply),
+	})
+}
+
+// CommentMention describes a comment that mentions a customer.
+type CommentMention struct {
+	RecipientID    int
+	RecipientEmail string
+	RecipientName  string
+	FilmTitle      string
+	MentionerName  string
+	Comment        string
+}
+
+// NotifyCommentMention tells a customer that a comment mentioned them.
+func (n *Notifier) NotifyCommentMention(mention CommentMention) error {
+	return n.Notify(mention.RecipientID, EventCommentMention, Message{
+		To:      mention.RecipientEmail,
+		Subject: fmt.Sprintf("You were mentioned in a comment on %s", mention.FilmTitle),
+		Body: fmt.Sprintf("Hi %s,\n\n%s mentioned you in a comment on %s:\n\n%s\n",
+			mention.RecipientName, mention.MentionerName, mention.FilmTitle, mention.Comment),
+	})
+}
+
+// RentalDue describes a rental that is due back soon.
+type RentalDue struct {
+	RecipientID    int
+	RecipientEmail string
+	RecipientName  string
+	FilmTitle      string
+	DueAt          time.Time
+}
+
+// NotifyRentalDue reminds a customer to return a rental.
+func (n *Notifier) NotifyRentalDue(due RentalDue) error {
+	return n.Notify(due.RecipientID, EventRentalDue, Message{
+		To:      due.RecipientEmail,
+		Subject: fmt.Sprintf("%s is due back %s", due.FilmTitle, due.DueAt.Format("Mon Jan 2")),
+		Body: fmt.Sprintf("Hi %s,\n\nA reminder that your rental of %s is due back by %s.\n",
+			due.RecipientName, due.FilmTitle, due.DueAt.Format("Mon Jan 2, 15:04 MST")),
+	})
+}
+
+// FilmAvailable describes a film a customer asked to hear about that has a
+// copy back in stock.
+type FilmAvailable struct {
+	RecipientID    int
+	RecipientEmail string
+	RecipientName  string
+	FilmTitle      string
+}
+
+// NotifyFilmAvailable tells a customer that a film they are waiting for can
+// be rented.
+func (n *Notifier) NotifyFilmAvailable(available FilmAvailable) error {
+	return n.Notify(available.RecipientID, EventFilmAvailable, Message{
+		To:      available.RecipientEmail,
+		Subject: fmt.Sprintf("%s is available to rent", available.FilmTitle),
+		Body: fmt.Sprintf("Hi %s,\n\nA copy of %s has just come back, so you can rent it now.\n",
+			available.RecipientName, available.FilmTitle),
+	})
+}
+
+// SavedSearchMatch describes films newly published that match a customer's
+// saved search.
+type SavedSearchMatch struct {
+	RecipientID    int
+	RecipientEmail string
+	RecipientName  string
+	SearchName     string
+	FilmTitles     []string
+}
+
+// NotifySavedSearchMatch tells a customer about new films matching one of
+// their saved searches.
+func (n *Notifier) NotifySavedSearchMatch(match SavedSearchMatch) error {
+	return n.Notify(match.RecipientID, EventSavedSearchMatch, Message{
+		To:      match.RecipientEmail,
+		Subject: fmt.Sprintf("New films for your search %q", match.SearchName),
+		Body: fmt.Sprintf("Hi %s,\n\nThese films matching your saved search %q have just been added:\n\n- %s\n",
+			match.RecipientName, match.SearchName, strings.Join(match.FilmTitles, "\n- ")),
+	})
+}
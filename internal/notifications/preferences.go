@@ -0,0 +1,47 @@
+package notifications
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Notification event types.
+const (
+	EventCommentReply     = "comment_reply"
+	EventRentalDue        = "rental_due"
+	EventFilmAvailable    = "film_available"
+	EventSavedSearchMatch = "saved_search_match"
+	EventCommentMention   = "comment_mention"
+)
+
+// Delivery channels. Only email has a sender today; SMS preferences are
+// stored so they take effect once an SMS backend exists.
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
+// Events lists every notification event type.
+var Events = []string{ //nolint:gochecknoglobals // Fixed list
+	EventCommentReply, EventRentalDue, EventFilmAvailable, EventSavedSearchMatch, EventCommentMention,
+}
+
+// Channels lists every delivery channel.
+var Channels = []string{ChannelEmail, ChannelSMS} //nolint:gochecknoglobals // Fixed list
+
+// DefaultEnabled reports whether channel is on for customers who have not
+// set a preference: email is opt-out, SMS is opt-in.
+func DefaultEnabled(channel string) bool {
+	return channel == ChannelEmail
+}
+
+// ValidatePreference returns an error for unknown event types or channels.
+func ValidatePreference(event, channel string) error {
+	if !slices.Contains(Events, event) {
+		return fmt.Errorf("unknown notification event %q", event)
+	}
+	if !slices.Contains(Channels, channel) {
+		return fmt.Errorf("unknown notification channel %q", channel)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// sendGridURL is the SendGrid v3 mail send endpoint.
+const sendGridURL = "https://api.sendgrid.com/v3/mail/send"
+
+const sendGridTimeout = 10 * time.Second
+
+// SendGridSender sends messages through the SendGrid v3 HTTP API.
+type SendGridSender struct {
+	apiKey string
+	from   string
+	url    string
+	client *http.Client
+}
+
+// SendGridOption configures a SendGridSender.
+type SendGridOption func(*SendGridSender)
+
+// WithSendGridURL overrides the API endpoint, e.g. for tests.
+func WithSendGridURL(url string) SendGridOption {
+	return func(s *SendGridSender) {
+		s.url = url
+	}
+}
+
+// NewSendGridSender creates a sender using apiKey, sending as from.
+func NewSendGridSender(apiKey, from string, opts ...SendGridOption) *SendGridSender {
+	sender := &SendGridSender{
+		apiKey: apiKey,
+		from:   from,
+		url:    sendGridURL,
+		client: &http.Client{Timeout: sendGridTimeout},
+	}
+	for _, opt := range opts {
+		opt(sender)
+	}
+	return sender
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+// Send delivers msg.
+func (s *SendGridSender) Send(ctx context.Context, msg Message) error {
+	payload := sendGridRequest{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{Email: msg.To}}}},
+		From:             sendGridAddress{Email: s.from},
+		Subject:          msg.Subject,
+		Content:          []sendGridContent{{Type: "text/plain", Value: msg.Body}},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding SendGrid request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating SendGrid request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending email via SendGrid: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) //nolint:mnd // enough for an error message
+		return fmt.Errorf("error sending email via SendGrid: status %d: %s", resp.StatusCode, detail)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package pagination selects pages of lists and describes them in
+// responses, so every list endpoint pages the same way: by page number, or,
+// on lists that support it, with a cursor from the page before.
+package pagination
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/binding"
+)
+
+// ErrInvalidCursor is returned for a cursor that was not issued by NextCursor.
+var ErrInvalidCursor = apperrors.New(apperrors.Invalid, "invalid cursor")
+
+// Params selects a page of a list. Embed it in a list's filters to bind
+// page and limit from the query string.
+type Params struct {
+	Page  int `json:"page,omitempty"  query:"page"  validate:"min=1"`
+	Limit int `json:"limit,omitempty" query:"limit" validate:"min=1"`
+}
+
+// Offset returns the number of items before the page.
+func (p Params) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// Limits holds a list's page sizes.
+type Limits struct {
+	// DefaultLimit is the page size used when a request does not set one.
+	DefaultLimit int
+	// MaxLimit is the largest page size a request may ask for.
+	MaxLimit int
+}
+
+// DefaultLimits is used when no page sizes are configured.
+var DefaultLimits = Limits{DefaultLimit: 10, MaxLimit: 100} //nolint:gochecknoglobals // Read-only default
+
+// Validate reports whether the limits are usable.
+func (l Limits) Validate() error {
+	if l.DefaultLimit < 1 || l.MaxLimit < l.DefaultLimit {
+		return fmt.Errorf("page sizes must satisfy 1 <= default (%d) <= max (%d)", l.DefaultLimit, l.MaxLimit)
+	}
+	return nil
+}
+
+// First returns the params of the first page at the default size, to bind a
+// request's query over.
+func (l Limits) First() Params {
+	return Params{Page: 1, Limit: l.DefaultLimit}
+}
+
+// Normalize fills in the first page and the default size where params
+// leaves them unset.
+func (l Limits) Normalize(params *Params) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = l.DefaultLimit
+	}
+}
+
+// Check validates params by its tags and against the largest page size.
+func (l Limits) Check(params Params) error {
+	if err := binding.Validate(params); err != nil {
+		return err
+	}
+	if params.Limit > l.MaxLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", binding.ErrValidation, l.MaxLimit)
+	}
+	return nil
+}
+
+// Paginated is a page of a list.
+type Paginated[T any] struct {
+	Items []T `json:"items"`
+	// Total is the number of items in the whole list. TotalMode is set when
+	// it is an estimate or was not computed.
+	Total     int    `json:"total"`
+	TotalMode string `json:"total_mode,omitempty"`
+	// Page is the page number, left out for pages selected by cursor.
+	Page  int `json:"page,omitempty"`
+	Limit int `json:"limit"`
+	// NextCursor selects the page after this one, on lists paged by cursor.
+	// It is left out on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// New returns the page of items selected by params, out of total items in
+// the list.
+func New[T any](items []T, total int, params Params) *Paginated[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Paginated[T]{Items: items, Total: total, Page: params.Page, Limit: params.Limit}
+}
+
+// Cursor marks the last item of a page of a list ordered by time, ties
+// broken by ID. The next page starts after it.
+type Cursor struct {
+	Time time.Time
+	ID   int
+}
+
+// Encode returns the cursor as an opaque string for clients to send back.
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.Time.UnixNano(), 10) + "." + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor returned by Encode.
+func DecodeCursor(encoded string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	cursorID, err := strconv.Atoi(id)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return Cursor{Time: time.Unix(0, unixNano).UTC(), ID: cursorID}, nil
+}
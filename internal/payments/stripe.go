@@ -0,0 +1,196 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stripeAPIURL is the Stripe API base URL.
+const stripeAPIURL = "https://api.stripe.com"
+
+const stripeTimeout = 10 * time.Second
+
+// stripeSignatureTolerance is how old a webhook signature may be, limiting
+// replays of captured callbacks.
+const stripeSignatureTolerance = 5 * time.Minute
+
+// StripeProvider takes payments through the Stripe HTTP API.
+type StripeProvider struct {
+	secretKey     string
+	webhookSecret string
+	url           string
+	client        *http.Client
+	now           func() time.Time
+}
+
+// StripeOption configures a StripeProvider.
+type StripeOption func(*StripeProvider)
+
+// WithStripeURL overrides the API base URL, e.g. for tests.
+func WithStripeURL(url string) StripeOption {
+	return func(p *StripeProvider) {
+		p.url = url
+	}
+}
+
+// WithStripeClock overrides the clock used to check webhook signature
+// timestamps, e.g. for tests.
+func WithStripeClock(now func() time.Time) StripeOption {
+	return func(p *StripeProvider) {
+		p.now = now
+	}
+}
+
+// NewStripeProvider creates a provider using secretKey for API calls and
+// webhookSecret to verify webhook callbacks.
+func NewStripeProvider(secretKey, webhookSecret string, opts ...StripeOption) *StripeProvider {
+	provider := &StripeProvider{
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		url:           stripeAPIURL,
+		client:        &http.Client{Timeout: stripeTimeout},
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(provider)
+	}
+	return provider
+}
+
+// Name returns "stripe".
+func (p *StripeProvider) Name() string {
+	return "stripe"
+}
+
+// stripeIntent is the part of a Stripe PaymentIntent the API uses.
+type stripeIntent struct {
+	ID           string `json:"id"`
+	ClientSecret string `json:"client_secret"`
+	Status       string `json:"status"`
+}
+
+// stripeRefund is the part of a Stripe Refund the API uses.
+type stripeRefund struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// CreateIntent creates a Stripe PaymentIntent with automatic payment
+// methods.
+func (p *StripeProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(req.Amount, 10))
+	form.Set("currency", req.Currency)
+	form.Set("automatic_payment_methods[enabled]", "true")
+	for key, value := range req.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	var intent stripeIntent
+	if err := p.post(ctx, "/v1/payment_intents", form, req.IdempotencyKey, &intent); err != nil {
+		return nil, fmt.Errorf("error creating Stripe payment intent: %w", err)
+	}
+	return &Intent{ID: intent.ID, ClientSecret: intent.ClientSecret, Status: intent.Status}, nil
+}
+
+// CreateRefund creates a Stripe Refund of a PaymentIntent.
+func (p *StripeProvider) CreateRefund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	form := url.Values{}
+	form.Set("payment_intent", req.IntentID)
+	form.Set("amount", strconv.FormatInt(req.Amount, 10))
+
+	var refund stripeRefund
+	if err := p.post(ctx, "/v1/refunds", form, req.IdempotencyKey, &refund); err != nil {
+		return nil, fmt.Errorf("error creating Stripe refund: %w", err)
+	}
+	return &Refund{ID: refund.ID, Status: refund.Status}, nil
+}
+
+// post sends form to a Stripe API path and decodes the response into out.
+func (p *StripeProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+p.secretKey)
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) //nolint:mnd // enough for an error message
+		if declined(resp.StatusCode) {
+			return fmt.Errorf("%w: status %d: %s", ErrDeclined, resp.StatusCode, detail)
+		}
+		return fmt.Errorf("status %d: %s", resp.StatusCode, detail)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
+
+// declined reports whether a Stripe error status means the request was
+// refused without effect: a 4xx, except for a conflicting concurrent request
+// with the same idempotency key and rate limiting, which may be retried.
+func declined(status int) bool {
+	return status >= http.StatusBadRequest && status < http.StatusInternalServerError &&
+		status != http.StatusConflict && status != http.StatusTooManyRequests
+}
+
+// ParseWebhook verifies the Stripe-Signature header of a webhook callback
+// and decodes its event. The header carries a timestamp t and one or more
+// v1 signatures, each an HMAC-SHA256 of "t.payload" under the webhook
+// secret; at least one must match and t must be recent.
+func (p *StripeProvider) ParseWebhook(payload []byte, header http.Header) (*Event, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header.Get("Stripe-Signature"), ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing signature timestamp", ErrInvalidWebhook)
+	}
+	if age := p.now().Sub(time.Unix(seconds, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return nil, fmt.Errorf("%w: signature timestamp outside tolerance", ErrInvalidWebhook)
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, signature := range signatures {
+		decoded, decodeErr := hex.DecodeString(signature)
+		if decodeErr == nil && hmac.Equal(decoded, expected) {
+			return parseStripeEvent(payload)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no matching signature", ErrInvalidWebhook)
+}
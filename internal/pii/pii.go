@@ -0,0 +1,217 @@
+// Package pii encrypts personally identifiable information before it is
+// stored, so database dumps and backups do not leak it.
+//
+// Values are envelope encrypted: each is sealed with its own random data key
+// under AES-256-GCM, and the data key is sealed in turn by a KeyWrapper
+// holding the master key, such as a key from config or a KMS.
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix marks an encrypted value. Values without it were stored before
+// encryption was enabled and are read as they are.
+const Prefix = "pii:v1:"
+
+// dataKeySize is the size of each value's AES-256 data key.
+const dataKeySize = 32
+
+// ErrKeyRequired is returned when reading an encrypted value without a
+// master key configured.
+var ErrKeyRequired = errors.New("PII encryption key is not configured")
+
+// ErrUnknownKey is returned when an encrypted value was sealed under a
+// different master key than the one configured.
+var ErrUnknownKey = errors.New("value was encrypted with an unknown key")
+
+// ErrMalformed is returned when an encrypted value cannot be parsed.
+var ErrMalformed = errors.New("malformed encrypted value")
+
+// ErrReservedPrefix is returned when storing a value that starts with Prefix
+// in the clear, as it would be read back as an encrypted value.
+var ErrReservedPrefix = errors.New("value must not start with " + Prefix)
+
+// KeyWrapper seals and opens data keys with a master key it never reveals.
+type KeyWrapper interface {
+	// KeyID identifies the master key, and is stored with each value.
+	KeyID() string
+	// WrapKey seals a data key.
+	WrapKey(dataKey []byte) ([]byte, error)
+	// UnwrapKey opens a data key sealed by WrapKey.
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// LocalKey is a KeyWrapper holding a 256-bit master key in memory, such as
+// one from config.
+type LocalKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewLocalKey creates a KeyWrapper from a base64-encoded 256-bit key.
+func NewLocalKey(encoded string) (*LocalKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding PII encryption key: %w", err)
+	}
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("PII encryption key must be %d bytes, got %d", dataKeySize, len(key))
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &LocalKey{id: hex.EncodeToString(sum[:4]), aead: aead}, nil
+}
+
+// KeyID identifies the key by a fingerprint of it.
+func (k *LocalKey) KeyID() string {
+	return k.id
+}
+
+// WrapKey seals a data key with the master key.
+func (k *LocalKey) WrapKey(dataKey []byte) ([]byte, error) {
+	return seal(k.aead, dataKey)
+}
+
+// UnwrapKey opens a data key sealed by WrapKey.
+func (k *LocalKey) UnwrapKey(wrapped []byte) ([]byte, error) {
+	return open(k.aead, wrapped)
+}
+
+// Cipher encrypts values for storage and decrypts them on read. A Cipher
+// without a KeyWrapper stores values in the clear.
+type Cipher struct {
+	keys KeyWrapper
+}
+
+// NewCipher creates a cipher sealing data keys with keys, or one storing
+// values in the clear if keys is nil.
+func NewCipher(keys KeyWrapper) *Cipher {
+	return &Cipher{keys: keys}
+}
+
+// Enabled reports whether values are encrypted.
+func (c *Cipher) Enabled() bool {
+	return c != nil && c.keys != nil
+}
+
+// Encrypt returns plaintext in the form to store. Empty values are stored
+// as they are. Without a master key, values are stored in the clear unless
+// they start with Prefix, which returns ErrReservedPrefix.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if !c.Enabled() || plaintext == "" {
+		if strings.HasPrefix(plaintext, Prefix) {
+			return "", ErrReservedPrefix
+		}
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("error generating data key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := c.keys.WrapKey(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("error wrapping data key: %w", err)
+	}
+
+	return Prefix + c.keys.KeyID() + ":" + base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of a stored value. Values stored before
+// encryption was enabled are returned as they are.
+func (c *Cipher) Decrypt(stored string) (string, error) {
+	rest, ok := strings.CutPrefix(stored, Prefix)
+	if !ok {
+		return stored, nil
+	}
+	if !c.Enabled() {
+		return "", ErrKeyRequired
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	if parts[0] != c.keys.KeyID() {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, parts[0])
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dataKey, err := c.keys.UnwrapKey(wrapped)
+	if err != nil {
+		return "", fmt.Errorf("error unwrapping data key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// newAEAD returns AES-GCM under key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// seal encrypts plaintext under a random nonce, which it prepends.
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a value sealed by seal.
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting value: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,83 @@
+// Package pricing applies admin-defined discount rules to film rental rates.
+package pricing
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// Quote prices a film rented at storeID at the given time; a storeID of 0
+// matches only rules for every store. Percent-off rules that apply stack,
+// each taking its share off the rate left by the rules before it, in rule
+// order. Bundle rules leave the single-film rate alone and are returned as
+// offers. Weekends are judged in the location of at.
+func Quote(film models.FilmPricing, storeID int, at time.Time, rules []models.PricingRule) *models.FilmPrice {
+	price := &models.FilmPrice{
+		FilmID:       film.FilmID,
+		BaseRate:     film.RentalRate,
+		PricedAt:     at,
+		AppliedRules: []models.AppliedDiscount{},
+		BundleOffers: []models.BundleOffer{},
+	}
+
+	rate := film.RentalRate
+	for _, rule := range rules {
+		if rule.Kind != models.PricingRulePercentOff || rule.PercentOff == nil || !applies(rule, film, storeID, at) {
+			continue
+		}
+		amountOff := roundCents(rate * *rule.PercentOff / 100)
+		rate = max(rate-amountOff, 0)
+		price.AppliedRules = append(price.AppliedRules, models.AppliedDiscount{
+			RuleID:     rule.ID,
+			Name:       rule.Name,
+			PercentOff: *rule.PercentOff,
+			AmountOff:  amountOff,
+		})
+	}
+	price.EffectiveRate = roundCents(rate)
+
+	for _, rule := range rules {
+		if rule.Kind != models.PricingRuleBundle || rule.BundleQuantity == nil || rule.BundlePaid == nil ||
+			!applies(rule, film, storeID, at) {
+			continue
+		}
+		price.BundleOffers = append(price.BundleOffers, models.BundleOffer{
+			RuleID:      rule.ID,
+			Name:        rule.Name,
+			Quantity:    *rule.BundleQuantity,
+			Paid:        *rule.BundlePaid,
+			RatePerFilm: roundCents(price.EffectiveRate * float64(*rule.BundlePaid) / float64(*rule.BundleQuantity)),
+		})
+	}
+
+	return price
+}
+
+// applies reports whether rule's restrictions admit film rented at storeID
+// at the given time.
+func applies(rule models.PricingRule, film models.FilmPricing, storeID int, at time.Time) bool {
+	if rule.CategoryID != nil && !slices.Contains(film.CategoryIDs, *rule.CategoryID) {
+		return false
+	}
+	if rule.StoreID != nil && *rule.StoreID != storeID {
+		return false
+	}
+	if rule.WeekendsOnly && at.Weekday() != time.Saturday && at.Weekday() != time.Sunday {
+		return false
+	}
+	if rule.StartsAt != nil && at.Before(*rule.StartsAt) {
+		return false
+	}
+	if rule.EndsAt != nil && !at.Before(*rule.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// roundCents rounds amount to the nearest cent.
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,62 @@
+// Package ratings maps the MPAA ratings films are stored with to the rating
+// systems of other countries.
+package ratings
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MPAA lists the ratings films are stored with.
+var MPAA = []string{"G", "PG", "PG-13", "R", "NC-17"} //nolint:gochecknoglobals // Read-only
+
+// Systems maps a rating system's name, such as "UK", to the local rating of
+// each MPAA rating it covers.
+type Systems map[string]map[string]string
+
+// Parse builds Systems from entries of the form "SYSTEM:MPAA=LOCAL", such as
+// "UK:PG-13=12A". A system need not cover every MPAA rating.
+func Parse(entries []string) (Systems, error) {
+	systems := Systems{}
+	for _, entry := range entries {
+		system, mapping, found := strings.Cut(entry, ":")
+		system = strings.TrimSpace(system)
+		if !found || system == "" {
+			return nil, fmt.Errorf("invalid rating mapping %q: want SYSTEM:RATING=LOCAL", entry)
+		}
+
+		rating, local, found := strings.Cut(mapping, "=")
+		rating, local = strings.TrimSpace(rating), strings.TrimSpace(local)
+		if !found || local == "" {
+			return nil, fmt.Errorf("invalid rating mapping %q: want SYSTEM:RATING=LOCAL", entry)
+		}
+		if !slices.Contains(MPAA, rating) {
+			return nil, fmt.Errorf("invalid rating mapping %q: unknown rating %q", entry, rating)
+		}
+
+		if systems[system] == nil {
+			systems[system] = map[string]string{}
+		}
+		if previous, ok := systems[system][rating]; ok && previous != local {
+			return nil, fmt.Errorf("conflicting rating mappings for %s %s: %q and %q", system, rating, previous, local)
+		}
+		systems[system][rating] = local
+	}
+	return systems, nil
+}
+
+// Localize returns the local rating of each system covering rating, keyed by
+// system, or nil when none does.
+func (s Systems) Localize(rating string) map[string]string {
+	var local map[string]string
+	for system, mapping := range s {
+		if value, ok := mapping[rating]; ok {
+			if local == nil {
+				local = map[string]string{}
+			}
+			local[system] = value
+		}
+	}
+	return local
+}
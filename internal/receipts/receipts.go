@@ -0,0 +1,71 @@
+// Package receipts renders rental receipts as standalone, printable HTML
+// documents.
+package receipts
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// receiptTemplate lays out a receipt on one page. Styles are inline so the
+// document prints, or saves to PDF from a browser, without other requests.
+var receiptTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"money": money,
+	"date":  func(t time.Time) string { return t.Format("Jan 2, 2006 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Receipt for rental #{{.RentalID}}</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 2em auto; color: #222; }
+table { width: 100%; border-collapse: collapse; }
+th, td { text-align: left; padding: 0.3em 0; border-bottom: 1px solid #ddd; }
+td.amount, th.amount { text-align: right; }
+tfoot td { font-weight: bold; border-bottom: none; }
+</style>
+</head>
+<body>
+<h1>Mockbuster</h1>
+<p>{{.StoreAddress}}</p>
+<h2>Receipt for rental #{{.RentalID}}</h2>
+<p>Issued {{date .IssuedAt}} to {{.CustomerName}}{{with .CustomerEmail}} ({{.}}){{end}}</p>
+<table>
+<tr><th>Film</th><td>{{.FilmTitle}}</td></tr>
+<tr><th>Rented</th><td>{{date .RentalDate}}</td></tr>
+<tr><th>Due</th><td>{{date .DueAt}}</td></tr>
+<tr><th>Returned</th><td>{{with .ReturnDate}}{{date .}}{{else}}Not yet returned{{end}}</td></tr>
+</table>
+<h3>Payments</h3>
+<table>
+<thead><tr><th>Date</th><th>Payment</th><th class="amount">Amount</th></tr></thead>
+<tbody>
+{{- range .Payments}}
+<tr><td>{{date .PaidAt}}</td><td>#{{.PaymentID}}{{if lt .Amount 0.0}} (refund){{end}}</td>` +
+	`<td class="amount">{{money .Amount}}</td></tr>
+{{- else}}
+<tr><td colspan="3">No payments</td></tr>
+{{- end}}
+</tbody>
+<tfoot><tr><td colspan="2">Total paid</td><td class="amount">{{money .AmountPaid}}</td></tr></tfoot>
+</table>
+</body>
+</html>
+`))
+
+// RenderHTML writes receipt to w as an HTML document.
+func RenderHTML(w io.Writer, receipt *models.Receipt) error {
+	return receiptTemplate.Execute(w, receipt)
+}
+
+// money formats an amount in dollars, with the sign ahead of the symbol.
+func money(amount float64) string {
+	if amount < 0 {
+		return fmt.Sprintf("-$%.2f", -amount)
+	}
+	return fmt.Sprintf("$%.2f", amount)
+}
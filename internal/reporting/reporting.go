@@ -0,0 +1,82 @@
+// Package reporting sends server errors and panics to an external error
+// tracker so they can be triaged with their request context.
+package reporting
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// Reporter captures errors raised while serving requests.
+type Reporter interface {
+	// CaptureError reports an error that produced a 5xx response to r.
+	CaptureError(r *http.Request, requestID string, err error)
+
+	// CapturePanic reports a value recovered from a panic while serving r.
+	// It must be called from the deferred function that recovered, so the
+	// stack trace points at the panic.
+	CapturePanic(r *http.Request, requestID string, recovered any)
+
+	// Flush waits up to timeout for queued reports to be delivered.
+	Flush(timeout time.Duration)
+}
+
+// NoopReporter discards every report. It is used when no DSN is configured.
+type NoopReporter struct{}
+
+// CaptureError discards the error.
+func (NoopReporter) CaptureError(*http.Request, string, error) {}
+
+// CapturePanic discards the panic.
+func (NoopReporter) CapturePanic(*http.Request, string, any) {}
+
+// Flush returns immediately.
+func (NoopReporter) Flush(time.Duration) {}
+
+// SentryReporter reports to Sentry.
+type SentryReporter struct{}
+
+// NewSentryReporter initializes the Sentry client for the given DSN and
+// environment.
+func NewSentryReporter(dsn, environment string) (*SentryReporter, error) {
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:              dsn,
+		Environment:      environment,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing sentry: %w", err)
+	}
+	return &SentryReporter{}, nil
+}
+
+// CaptureError reports err with the request attached.
+func (s *SentryReporter) CaptureError(r *http.Request, requestID string, err error) {
+	hub := requestHub(r, requestID)
+	hub.CaptureException(err)
+}
+
+// CapturePanic reports the recovered value with the request attached.
+func (s *SentryReporter) CapturePanic(r *http.Request, requestID string, recovered any) {
+	hub := requestHub(r, requestID)
+	hub.RecoverWithContext(r.Context(), recovered)
+}
+
+// Flush waits up to timeout for queued events to be sent.
+func (s *SentryReporter) Flush(timeout time.Duration) {
+	sentry.Flush(timeout)
+}
+
+// requestHub returns a hub scoped to a single request, so concurrent requests
+// do not share tags.
+func requestHub(r *http.Request, requestID string) *sentry.Hub {
+	hub := sentry.CurrentHub().Clone()
+	hub.Scope().SetRequest(r)
+	if requestID != "" {
+		hub.Scope().SetTag("request_id", requestID)
+	}
+	return hub
+}
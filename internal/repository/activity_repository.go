@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// ActivityRepository handles database operations for customer follows and
+// the public activity shown in followers' feeds.
+type ActivityRepository struct {
+	db *database.DB
+}
+
+// NewActivityRepository creates a new activity repository.
+func NewActivityRepository(db *database.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// Follow makes followerID follow followedID. Following a customer already
+// followed changes nothing.
+func (r *ActivityRepository) Follow(ctx context.Context, followerID, followedID int) error {
+	ctx = database.WithQueryName(ctx, "follows.add")
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO customer_follows (follower_id, followed_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, followerID, followedID)
+	if err != nil {
+		err = constraintError(err, err)
+		if errors.Is(err, ErrInvalidReference) {
+			return ErrCustomerNotFound
+		}
+		return fmt.Errorf("error following customer: %w", err)
+	}
+
+	return nil
+}
+
+// Unfollow stops followerID following followedID.
+func (r *ActivityRepository) Unfollow(ctx context.Context, followerID, followedID int) error {
+	ctx = database.WithQueryName(ctx, "follows.remove")
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM customer_follows WHERE follower_id = $1 AND followed_id = $2", followerID, followedID)
+	if err != nil {
+		return fmt.Errorf("error unfollowing customer: %w", err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error counting unfollowed customers: %w", err)
+	}
+	if removed == 0 {
+		return ErrNotFollowing
+	}
+
+	return nil
+}
+
+// ListFollowing retrieves the customers a customer follows, most recently
+// followed first.
+func (r *ActivityRepository) ListFollowing(ctx context.Context, customerID int) ([]models.Follow, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "follows.list"), `
+		SELECT c.customer_id, c.first_name || ' ' || c.last_name, f.created_at
+		FROM customer_follows f
+		JOIN customer c ON c.customer_id = f.followed_id
+		WHERE f.follower_id = $1
+		ORDER BY f.created_at DESC, c.customer_id`, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying follows: %w", err)
+	}
+	defer rows.Close()
+
+	follows := []models.Follow{}
+	for rows.Next() {
+		var follow models.Follow
+		if scanErr := rows.Scan(&follow.CustomerID, &follow.Name, &follow.FollowedAt); scanErr != nil {
+			return nil, fmt.Errorf("error scanning follow: %w", scanErr)
+		}
+		follows = append(follows, follow)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating follows: %w", rowsErr)
+	}
+
+	return follows, nil
+}
+
+// RecordActivity adds an entry to a customer's public activity.
+func (r *ActivityRepository) RecordActivity(ctx context.Context, activity models.Activity) error {
+	ctx = database.WithQueryName(ctx, "activity.record")
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO customer_activity (customer_id, kind, film_id, list_id, comment_id)
+		VALUES ($1, $2, $3, $4, $5)`,
+		activity.CustomerID, activity.Kind, activity.FilmID, activity.ListID, activity.CommentID)
+	if err != nil {
+		return fmt.Errorf("error recording activity: %w", err)
+	}
+
+	return nil
+}
+
+// GetFeed retrieves a page of the activity of the customers a customer
+// follows, most recent first, with the total number of entries. Activity
+// on lists since made private is left out.
+func (r *ActivityRepository) GetFeed(
+	ctx context.Context,
+	customerID int,
+	filters models.ActivityFeedFilters,
+) (*pagination.Paginated[models.Activity], error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "activity.feed"), `
+		SELECT a.id, a.customer_id, c.first_name || ' ' || c.last_name, a.kind,
+		       a.film_id, fm.title, a.list_id, l.name, a.comment_id, a.created_at, COUNT(*) OVER()
+		FROM customer_follows f
+		JOIN customer_activity a ON a.customer_id = f.followed_id
+		JOIN customer c ON c.customer_id = a.customer_id
+		LEFT JOIN film fm ON fm.film_id = a.film_id
+		LEFT JOIN customer_lists l ON l.id = a.list_id
+		WHERE f.follower_id = $1
+		  AND (a.list_id IS NULL OR l.visibility = $2)
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $3 OFFSET $4`,
+		customerID, models.ListVisibilityPublic, filters.Limit, (filters.Page-1)*filters.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying feed: %w", err)
+	}
+	defer rows.Close()
+
+	feed := pagination.New([]models.Activity{}, 0, pagination.Params{Page: filters.Page, Limit: filters.Limit})
+	for rows.Next() {
+		var activity models.Activity
+		if scanErr := rows.Scan(
+			&activity.ID, &activity.CustomerID, &activity.CustomerName, &activity.Kind,
+			&activity.FilmID, &activity.FilmTitle, &activity.ListID, &activity.ListName, &activity.CommentID,
+			&activity.CreatedAt, &feed.Total,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning activity: %w", scanErr)
+		}
+		feed.Items = append(feed.Items, activity)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating feed: %w", rowsErr)
+	}
+
+	return feed, nil
+}
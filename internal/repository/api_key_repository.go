@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// apiKeyColumns lists the api_keys columns scanned by scanAPIKey, in order.
+const apiKeyColumns = `id, name, tier, key_prefix, revoked_at, created_at`
+
+// APIKeyRepository handles database operations for API keys and their usage.
+type APIKeyRepository struct {
+	db *database.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository.
+func NewAPIKeyRepository(db *database.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// CreateAPIKey stores a new API key under its hash.
+func (r *APIKeyRepository) CreateAPIKey(
+	ctx context.Context,
+	keyReq models.APIKeyRequest,
+	keyPrefix, keyHash string,
+) (*models.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (name, tier, key_prefix, key_hash)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + apiKeyColumns
+
+	insertCtx := database.WithQueryName(ctx, "api_keys.insert")
+	key, err := scanAPIKey(r.db.QueryRowContext(insertCtx, query, keyReq.Name, keyReq.Tier, keyPrefix, keyHash))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// ListAPIKeys retrieves every API key, including revoked ones.
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id"
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "api_keys.list"), query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, scanErr := scanAPIKey(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning API key: %w", scanErr)
+		}
+		keys = append(keys, *key)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating API keys: %w", rowsErr)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey marks an API key revoked. Revoking a revoked key keeps its
+// original revocation time.
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, keyID int) (*models.APIKey, error) {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+		RETURNING ` + apiKeyColumns
+
+	revokeCtx := database.WithQueryName(ctx, "api_keys.revoke")
+	key, err := scanAPIKey(r.db.QueryRowContext(revokeCtx, query, keyID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("error revoking API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// GetActiveAPIKeyByHash retrieves the unrevoked API key stored under keyHash.
+func (r *APIKeyRepository) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL"
+
+	getCtx := database.WithQueryName(ctx, "api_keys.get_by_hash")
+	key, err := scanAPIKey(r.db.QueryRowContext(getCtx, query, keyHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("error querying API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// IncrementUsage counts a request against a key's usage for the month
+// starting at period, unless that would exceed quota (0 means unlimited). It
+// returns the usage after the attempt and whether the request was counted.
+func (r *APIKeyRepository) IncrementUsage(
+	ctx context.Context,
+	keyID int,
+	period time.Time,
+	quota int64,
+) (int64, bool, error) {
+	// The conditional upsert makes concurrent requests from every replica
+	// agree on the quota without a separate read.
+	query := `
+		INSERT INTO api_key_usage (api_key_id, period, request_count)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (api_key_id, period) DO UPDATE
+		SET request_count = api_key_usage.request_count + 1
+		WHERE $3 = 0 OR api_key_usage.request_count < $3
+		RETURNING request_count`
+
+	incrementCtx := database.WithQueryName(ctx, "api_keys.increment_usage")
+	var used int64
+	err := r.db.QueryRowContext(incrementCtx, query, keyID, period, quota).Scan(&used)
+	if errors.Is(err, sql.ErrNoRows) {
+		used, err = r.GetUsage(ctx, keyID, period)
+		return used, false, err
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("error recording API key usage: %w", err)
+	}
+
+	return used, true, nil
+}
+
+// GetUsage retrieves a key's request count for the month starting at period.
+func (r *APIKeyRepository) GetUsage(ctx context.Context, keyID int, period time.Time) (int64, error) {
+	query := `SELECT request_count FROM api_key_usage WHERE api_key_id = $1 AND period = $2`
+
+	var used int64
+	getCtx := database.WithQueryName(ctx, "api_keys.get_usage")
+	err := r.db.QueryRowContext(getCtx, query, keyID, period).Scan(&used)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("error querying API key usage: %w", err)
+	}
+
+	return used, nil
+}
+
+// scanAPIKey scans a row selected with apiKeyColumns.
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(&key.ID, &key.Name, &key.Tier, &key.KeyPrefix, &key.RevokedAt, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// AuditRepository handles reading the audit log.
+type AuditRepository struct {
+	db *database.DB
+}
+
+// NewAuditRepository creates a new audit log repository.
+func NewAuditRepository(db *database.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// ListStaffActivity retrieves a page of the audit log entries for actions
+// taken with a staff member's token, newest first. It returns
+// ErrStaffNotFound if there is no such staff member.
+func (r *AuditRepository) ListStaffActivity(
+	ctx context.Context,
+	staffID int,
+	filters models.AuditFilters,
+) (*pagination.Paginated[models.AuditEntry], error) {
+	var staffExists bool
+	existsCtx := database.WithQueryName(ctx, "audit.staff_exists")
+	err := r.db.QueryRowContext(existsCtx, "SELECT EXISTS(SELECT 1 FROM staff WHERE staff_id = $1)", staffID).
+		Scan(&staffExists)
+	if err != nil {
+		return nil, fmt.Errorf("error checking staff existence: %w", err)
+	}
+	if !staffExists {
+		return nil, ErrStaffNotFound
+	}
+
+	query := `
+		SELECT id, actor_role, actor_id, action, target_type, target_id, details, created_at, COUNT(*) OVER()
+		FROM audit_log
+		WHERE actor_role = $1 AND actor_id = $2`
+	args := []any{auth.RoleStaff, staffID}
+
+	if len(filters.Actions) > 0 {
+		args = append(args, pq.Array(filters.Actions))
+		query += fmt.Sprintf(" AND action = ANY($%d)", len(args))
+	}
+	if filters.From != nil {
+		args = append(args, *filters.From)
+		query += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if filters.To != nil {
+		args = append(args, *filters.To)
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+
+	args = append(args, filters.Limit, filters.Offset())
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "audit.staff_activity"), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying staff activity: %w", err)
+	}
+	defer rows.Close()
+
+	activity := pagination.New([]models.AuditEntry{}, 0, filters.Params)
+	for rows.Next() {
+		var entry models.AuditEntry
+		var details []byte
+		if scanErr := rows.Scan(
+			&entry.ID, &entry.ActorRole, &entry.ActorID, &entry.Action, &entry.TargetType, &entry.TargetID,
+			&details, &entry.CreatedAt, &activity.Total,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning audit entry: %w", scanErr)
+		}
+		entry.Details = details
+		activity.Items = append(activity.Items, entry)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating staff activity: %w", rowsErr)
+	}
+
+	return activity, nil
+}
+
+// insertAuditEntry records entry in the audit log as part of tx, so the
+// entry is kept only if the action it records is.
+func insertAuditEntry(ctx context.Context, tx *sql.Tx, entry models.AuditEntry) error {
+	details := entry.Details
+	if details == nil {
+		details = []byte("{}")
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_log (actor_role, actor_id, action, target_type, target_id, details)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		entry.ActorRole, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, []byte(details))
+	if err != nil {
+		return fmt.Errorf("error recording audit entry: %w", err)
+	}
+	return nil
+}
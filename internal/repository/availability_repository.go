@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// AvailabilityRepository handles database operations for the alerts
+// customers ask for when a film they want is rented out.
+type AvailabilityRepository struct {
+	db *database.DB
+}
+
+// NewAvailabilityRepository creates a new availability alert repository.
+func NewAvailabilityRepository(db *database.DB) *AvailabilityRepository {
+	return &AvailabilityRepository{db: db}
+}
+
+// CreateAlert records that customerID wants to hear when filmID is
+// available. When the customer already has a pending alert for the film,
+// that alert is returned instead and created is false.
+func (r *AvailabilityRepository) CreateAlert(
+	ctx context.Context,
+	customerID, filmID int,
+) (alert *models.AvailabilityAlert, created bool, err error) {
+	ctx = database.WithQueryName(ctx, "availability_alerts.create")
+	alert = &models.AvailabilityAlert{}
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO film_availability_alerts (film_id, customer_id)
+		VALUES ($1, $2)
+		ON CONFLICT (film_id, customer_id) WHERE notified_at IS NULL DO NOTHING
+		RETURNING id, film_id, customer_id, created_at`, filmID, customerID).
+		Scan(&alert.ID, &alert.FilmID, &alert.CustomerID, &alert.CreatedAt)
+	switch {
+	case err == nil:
+		return alert, true, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		err = constraintError(err, err)
+		if errors.Is(err, ErrInvalidReference) {
+			return nil, false, ErrFilmNotFound
+		}
+		return nil, false, fmt.Errorf("error inserting availability alert: %w", err)
+	}
+
+	pendingCtx := database.WithQueryName(ctx, "availability_alerts.get_pending")
+	err = r.db.QueryRowContext(pendingCtx, `
+		SELECT id, film_id, customer_id, created_at
+		FROM film_availability_alerts
+		WHERE film_id = $1 AND customer_id = $2 AND notified_at IS NULL`, filmID, customerID).
+		Scan(&alert.ID, &alert.FilmID, &alert.CustomerID, &alert.CreatedAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("error querying availability alert: %w", err)
+	}
+
+	return alert, false, nil
+}
+
+// ClaimAlerts marks the pending alerts for filmID as notified and returns
+// them, so each customer is told once. Alerts of customers without an email
+// address stay pending.
+func (r *AvailabilityRepository) ClaimAlerts(ctx context.Context, filmID int) ([]models.AvailabilityNotice, error) {
+	ctx = database.WithQueryName(ctx, "availability_alerts.claim")
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE film_availability_alerts a SET notified_at = NOW()
+		FROM customer c, film f
+		WHERE c.customer_id = a.customer_id
+		AND f.film_id = a.film_id
+		AND a.film_id = $1
+		AND a.notified_at IS NULL
+		AND c.email IS NOT NULL
+		RETURNING a.id, a.customer_id, c.email, c.first_name, f.film_id, f.title`, filmID)
+	if err != nil {
+		return nil, fmt.Errorf("error claiming availability alerts: %w", err)
+	}
+	defer rows.Close()
+
+	notices := []models.AvailabilityNotice{}
+	for rows.Next() {
+		var notice models.AvailabilityNotice
+		if scanErr := rows.Scan(
+			&notice.AlertID, &notice.CustomerID, &notice.CustomerEmail,
+			&notice.CustomerName, &notice.FilmID, &notice.FilmTitle,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning availability alert: %w", scanErr)
+		}
+		notices = append(notices, notice)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating availability alerts: %w", rowsErr)
+	}
+
+	return notices, nil
+}
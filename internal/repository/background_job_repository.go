@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// backgroundJobColumns lists the background_jobs columns scanned by
+// PurgeJobs deletes succeeded and dead jobs last updated before the given
+// time and returns how many were deleted. Pending and running jobs are kept
+// however old they are.
+func (r *BackgroundJobRepository) PurgeJobs(ctx context.Context, before time.Time) (int64, error) {
+	purgeCtx := database.WithQueryName(ctx, "jobs.purge")
+	result, err := r.db.ExecContext(purgeCtx,
+		"DELETE FROM background_jobs WHERE status IN ($1, $2) AND updated_at < $3",
+		models.JobStatusSucceeded, models.JobStatusDead, before)
+	if err != nil {
+		return 0, fmt.Errorf("error purging jobs: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error counting purged jobs: %w", err)
+	}
+
+	return purged, nil
+}
+
+// scanBackgroundJob, in order.
+const backgroundJobColumns = `id, kind, payload, status, attempts, max_attempts,
+		run_at, last_error, created_at, updated_at`
+
+// BackgroundJobRepository handles database operations for the persistent job
+// queue.
+type BackgroundJobRepository struct {
+	db *database.DB
+}
+
+// NewBackgroundJobRepository creates a new background job repository.
+func NewBackgroundJobRepository(db *database.DB) *BackgroundJobRepository {
+	return &BackgroundJobRepository{db: db}
+}
+
+// InsertJob adds a pending job that may be attempted up to maxAttempts times.
+func (r *BackgroundJobRepository) InsertJob(
+	ctx context.Context,
+	kind string,
+	payload []byte,
+	maxAttempts int,
+) (*models.BackgroundJob, error) {
+	query := `
+		INSERT INTO background_jobs (kind, payload, max_attempts)
+		VALUES ($1, $2, $3)
+		RETURNING ` + backgroundJobColumns
+
+	insertCtx := database.WithQueryName(ctx, "jobs.insert")
+	job, err := scanBackgroundJob(r.db.QueryRowContext(insertCtx, query, kind, payload, maxAttempts))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ClaimJob marks the next due job of one of the given kinds as running and
+// returns it, or returns nil when no job is due. Jobs left running for longer
+// than staleAfter are assumed abandoned by a dead worker and claimed again.
+func (r *BackgroundJobRepository) ClaimJob(
+	ctx context.Context,
+	kinds []string,
+	staleAfter time.Duration,
+) (*models.BackgroundJob, error) {
+	query := `
+		UPDATE background_jobs
+		SET status = 'running', attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM background_jobs
+			WHERE kind = ANY($1)
+			AND ((status = 'pending' AND run_at <= NOW())
+				OR (status = 'running' AND locked_at < NOW() - $2 * INTERVAL '1 second'))
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + backgroundJobColumns
+
+	claimCtx := database.WithQueryName(ctx, "jobs.claim")
+	job, err := scanBackgroundJob(r.db.QueryRowContext(claimCtx, query, pq.Array(kinds), staleAfter.Seconds()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil // No job is due
+		}
+		return nil, fmt.Errorf("error claiming job: %w", err)
+	}
+
+	return job, nil
+}
+
+// CompleteJob marks a running job as succeeded.
+func (r *BackgroundJobRepository) CompleteJob(ctx context.Context, jobID int64) error {
+	query := `
+		UPDATE background_jobs
+		SET status = 'succeeded', locked_at = NULL, last_error = NULL, updated_at = NOW()
+		WHERE id = $1`
+
+	completeCtx := database.WithQueryName(ctx, "jobs.complete")
+	if _, err := r.db.ExecContext(completeCtx, query, jobID); err != nil {
+		return fmt.Errorf("error completing job: %w", err)
+	}
+
+	return nil
+}
+
+// RetryJobLater returns a failed job to pending, to run again at runAt.
+func (r *BackgroundJobRepository) RetryJobLater(
+	ctx context.Context,
+	jobID int64,
+	lastError string,
+	runAt time.Time,
+) error {
+	query := `
+		UPDATE background_jobs
+		SET status = 'pending', locked_at = NULL, last_error = $2, run_at = $3, updated_at = NOW()
+		WHERE id = $1`
+
+	retryCtx := database.WithQueryName(ctx, "jobs.retry_later")
+	if _, err := r.db.ExecContext(retryCtx, query, jobID, lastError, runAt); err != nil {
+		return fmt.Errorf("error rescheduling job: %w", err)
+	}
+
+	return nil
+}
+
+// BuryJob marks a job that has run out of attempts as dead.
+func (r *BackgroundJobRepository) BuryJob(ctx context.Context, jobID int64, lastError string) error {
+	query := `
+		UPDATE background_jobs
+		SET status = 'dead', locked_at = NULL, last_error = $2, updated_at = NOW()
+		WHERE id = $1`
+
+	buryCtx := database.WithQueryName(ctx, "jobs.bury")
+	if _, err := r.db.ExecContext(buryCtx, query, jobID, lastError); err != nil {
+		return fmt.Errorf("error burying job: %w", err)
+	}
+
+	return nil
+}
+
+// ListJobs retrieves the most recently updated jobs matching filters.
+func (r *BackgroundJobRepository) ListJobs(
+	ctx context.Context,
+	filters models.BackgroundJobFilters,
+) ([]models.BackgroundJob, error) {
+	query := "SELECT " + backgroundJobColumns + ` FROM background_jobs
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR kind = $2)
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $3`
+
+	listCtx := database.WithQueryName(ctx, "jobs.list")
+	rows, err := r.db.QueryContext(listCtx, query, filters.Status, filters.Kind, filters.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []models.BackgroundJob{}
+	for rows.Next() {
+		job, scanErr := scanBackgroundJob(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning job: %w", scanErr)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating jobs: %w", rowsErr)
+	}
+
+	return jobs, nil
+}
+
+// GetJob retrieves a job by its ID.
+func (r *BackgroundJobRepository) GetJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error) {
+	query := "SELECT " + backgroundJobColumns + " FROM background_jobs WHERE id = $1"
+
+	getCtx := database.WithQueryName(ctx, "jobs.get")
+	job, err := scanBackgroundJob(r.db.QueryRowContext(getCtx, query, jobID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("error querying job: %w", err)
+	}
+
+	return job, nil
+}
+
+// RequeueJob returns a dead job to pending with its attempts reset.
+func (r *BackgroundJobRepository) RequeueJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error) {
+	query := `
+		UPDATE background_jobs
+		SET status = 'pending', attempts = 0, run_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'dead'
+		RETURNING ` + backgroundJobColumns
+
+	requeueCtx := database.WithQueryName(ctx, "jobs.requeue")
+	job, err := scanBackgroundJob(r.db.QueryRowContext(requeueCtx, query, jobID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Distinguish a missing job from one that is not dead.
+			if _, getErr := r.GetJob(ctx, jobID); getErr != nil {
+				return nil, getErr
+			}
+			return nil, ErrJobNotDead
+		}
+		return nil, fmt.Errorf("error requeueing job: %w", err)
+	}
+
+	return job, nil
+}
+
+// scanBackgroundJob scans a row selected with backgroundJobColumns.
+func scanBackgroundJob(row interface{ Scan(dest ...any) error }) (*models.BackgroundJob, error) {
+	var job models.BackgroundJob
+	var payload []byte
+	err := row.Scan(
+		&job.ID, &job.Kind, &payload, &job.Status, &job.Attempts, &job.MaxAttempts,
+		&job.RunAt, &job.LastError, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	return &job, nil
+}
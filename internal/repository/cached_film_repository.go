@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// CachedFilmRepository serves film reads from a cache, falling through to the
+// wrapped repository on a miss. Entries are evicted by cache invalidation
+// events published on writes.
+type CachedFilmRepository struct {
+	next  FilmRepositoryInterface
+	cache cache.Cache
+}
+
+// NewCachedFilmRepository wraps next with the given cache.
+func NewCachedFilmRepository(next FilmRepositoryInterface, c cache.Cache) *CachedFilmRepository {
+	return &CachedFilmRepository{next: next, cache: c}
+}
+
+// GetFilms retrieves films with optional filters.
+func (r *CachedFilmRepository) GetFilms(
+	ctx context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
+	key := cache.FilmListKey(filters)
+	if cached, ok := r.cache.Get(key); ok {
+		if films, isList := cached.(*pagination.Paginated[models.Film]); isList {
+			return films, nil
+		}
+	}
+
+	films, err := r.next.GetFilms(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.Set(key, films)
+	return films, nil
+}
+
+// GetFilmByID retrieves a single film by ID. Missing films are not cached.
+func (r *CachedFilmRepository) GetFilmByID(ctx context.Context, filmID int) (*models.Film, error) {
+	key := cache.FilmKey(filmID)
+	if cached, ok := r.cache.Get(key); ok {
+		if film, isFilm := cached.(*models.Film); isFilm {
+			return film, nil
+		}
+	}
+
+	film, err := r.next.GetFilmByID(ctx, filmID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.Set(key, film)
+	return film, nil
+}
+
+// GetCategories retrieves all categories.
+func (r *CachedFilmRepository) GetCategories(ctx context.Context) ([]models.Category, error) {
+	if cached, ok := r.cache.Get(cache.CategoriesKey); ok {
+		if categories, isList := cached.([]models.Category); isList {
+			return categories, nil
+		}
+	}
+
+	categories, err := r.next.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.Set(cache.CategoriesKey, categories)
+	return categories, nil
+}
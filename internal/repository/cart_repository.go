@@ -0,0 +1,353 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// copyAvailable matches inventory copies i that can be rented: not retired
+// and not out on an open rental.
+const copyAvailable = `i.retired_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM rental r WHERE r.inventory_id = i.inventory_id AND r.return_date IS NULL)`
+
+// CartRepository handles database operations for carts and checkouts.
+type CartRepository struct {
+	db *database.DB
+}
+
+// NewCartRepository creates a new cart repository.
+func NewCartRepository(db *database.DB) *CartRepository {
+	return &CartRepository{db: db}
+}
+
+// GetCart retrieves a customer's cart, oldest films first, with each film's
+// base rate and whether it is in stock at the customer's store. Rates after
+// pricing rules and the subtotal are left to the caller. Films unpublished
+// since they were added are left out.
+func (r *CartRepository) GetCart(ctx context.Context, customerID int) (*models.Cart, error) {
+	ctx = database.WithQueryName(ctx, "carts.get")
+	cart := &models.Cart{CustomerID: customerID, Items: []models.CartItem{}}
+	err := r.db.QueryRowContext(ctx, "SELECT store_id FROM customer WHERE customer_id = $1", customerID).
+		Scan(&cart.StoreID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error querying customer store: %w", err)
+	}
+
+	query := `
+		SELECT f.film_id, f.title, f.rental_rate,
+			EXISTS (
+				SELECT 1 FROM inventory i
+				WHERE i.film_id = f.film_id AND i.store_id = $2 AND ` + copyAvailable + `
+			),
+			ci.added_at,
+			ARRAY(SELECT fc.category_id FROM film_category fc WHERE fc.film_id = f.film_id ORDER BY fc.category_id)
+		FROM cart_items ci
+		JOIN film f ON f.film_id = ci.film_id
+		WHERE ci.customer_id = $1 AND f.status = 'published'
+		ORDER BY ci.added_at, f.film_id`
+
+	rows, err := r.db.QueryContext(ctx, query, customerID, cart.StoreID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying cart items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.CartItem
+		var categoryIDs pq.Int64Array
+		if scanErr := rows.Scan(
+			&item.FilmID, &item.Title, &item.BaseRate, &item.Available, &item.AddedAt, &categoryIDs,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning cart item: %w", scanErr)
+		}
+		for _, categoryID := range categoryIDs {
+			item.CategoryIDs = append(item.CategoryIDs, int(categoryID))
+		}
+		cart.Items = append(cart.Items, item)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating cart items: %w", rowsErr)
+	}
+
+	return cart, nil
+}
+
+// AddCartItem adds a published film to a customer's cart. Adding a film
+// already in the cart changes nothing.
+func (r *CartRepository) AddCartItem(ctx context.Context, customerID, filmID int) error {
+	if err := checkFilmPublished(ctx, r.db, "carts.film_exists", filmID); err != nil {
+		return err
+	}
+
+	addCtx := database.WithQueryName(ctx, "carts.add_item")
+	_, err := r.db.ExecContext(addCtx, `
+		INSERT INTO cart_items (customer_id, film_id)
+		VALUES ($1, $2)
+		ON CONFLICT (customer_id, film_id) DO NOTHING`, customerID, filmID)
+	if err != nil {
+		if errors.Is(constraintError(err, err), ErrInvalidReference) {
+			return ErrCustomerNotFound
+		}
+		return fmt.Errorf("error adding cart item: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveCartItem removes a film from a customer's cart.
+func (r *CartRepository) RemoveCartItem(ctx context.Context, customerID, filmID int) error {
+	removeCtx := database.WithQueryName(ctx, "carts.remove_item")
+	result, err := r.db.ExecContext(removeCtx,
+		"DELETE FROM cart_items WHERE customer_id = $1 AND film_id = $2", customerID, filmID)
+	if err != nil {
+		return fmt.Errorf("error removing cart item: %w", err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error counting removed cart items: %w", err)
+	}
+	if removed == 0 {
+		return ErrCartItemNotFound
+	}
+
+	return nil
+}
+
+// Checkout turns the given cart films into rentals at the customer's store,
+// charged at each line's rate less couponCode's discount, if given, then
+// less the customer's loyalty rental credit, and then less the balance of
+// the gift card stored under giftCardHash, if given. Each rental earns
+// rentalPoints loyalty points. It all happens in one transaction: the films
+// leave the cart, an in-stock copy of each is rented out, the coupon is
+// redeemed, credit and gift card balance are spent and points earned, and
+// the checkout is recorded, or nothing changes. Rentals are booked by the
+// store's manager.
+func (r *CartRepository) Checkout(
+	ctx context.Context,
+	customerID int,
+	lines []models.CheckoutLine,
+	couponCode, giftCardHash string,
+	rentalPoints int,
+) (*models.Checkout, error) {
+	ctx = database.WithQueryName(ctx, "carts.checkout")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "carts.checkout")
+
+	checkout := &models.Checkout{CustomerID: customerID, CouponCode: couponCode}
+	var staffID int
+	err = tx.QueryRowContext(ctx, `
+		SELECT c.store_id, s.manager_staff_id
+		FROM customer c
+		JOIN store s ON s.store_id = c.store_id
+		WHERE c.customer_id = $1`, customerID).Scan(&checkout.StoreID, &staffID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error querying customer store: %w", err)
+	}
+
+	// Taking the films out of the cart first fails the checkout if another
+	// request removed or checked out any of them since they were priced.
+	filmIDs := make(pq.Int64Array, 0, len(lines))
+	for _, line := range lines {
+		filmIDs = append(filmIDs, int64(line.FilmID))
+	}
+	result, err := tx.ExecContext(ctx,
+		"DELETE FROM cart_items WHERE customer_id = $1 AND film_id = ANY($2)", customerID, filmIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error emptying cart: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error counting emptied cart items: %w", err)
+	}
+	if removed != int64(len(lines)) {
+		return nil, ErrCartChanged
+	}
+
+	for _, line := range lines {
+		rental, rentErr := rentCopy(ctx, tx, line, customerID, checkout.StoreID, staffID)
+		if rentErr != nil {
+			return nil, rentErr
+		}
+		checkout.Rentals = append(checkout.Rentals, *rental)
+		checkout.Subtotal += line.Rate
+	}
+	checkout.Subtotal = math.Round(checkout.Subtotal*100) / 100
+	checkout.Total = checkout.Subtotal
+
+	var redemptionID *int
+	if couponCode != "" {
+		redemption, redeemErr := redeemCoupon(ctx, tx, couponCode, customerID, checkout.Subtotal)
+		if redeemErr != nil {
+			return nil, redeemErr
+		}
+		redemptionID = &redemption.ID
+		checkout.Discount = redemption.Discount
+		checkout.Total = redemption.Total
+	}
+
+	if checkout.CreditApplied, err = spendLoyaltyCredit(ctx, tx, customerID, checkout.Total); err != nil {
+		return nil, err
+	}
+	checkout.Total = math.Round((checkout.Total-checkout.CreditApplied)*100) / 100
+
+	var giftCardID int
+	if giftCardHash != "" {
+		if giftCardID, checkout.GiftCardApplied, err = spendGiftCard(ctx, tx, giftCardHash, checkout.Total); err != nil {
+			return nil, err
+		}
+		checkout.Total = math.Round((checkout.Total-checkout.GiftCardApplied)*100) / 100
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO checkouts (customer_id, store_id, subtotal, discount, credit_applied, gift_card_applied, total,
+			coupon_redemption_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`,
+		customerID, checkout.StoreID, checkout.Subtotal, checkout.Discount, checkout.CreditApplied,
+		checkout.GiftCardApplied, checkout.Total, redemptionID,
+	).Scan(&checkout.ID, &checkout.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error recording checkout: %w", err)
+	}
+
+	for _, rental := range checkout.Rentals {
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO checkout_rentals (checkout_id, rental_id, rate) VALUES ($1, $2, $3)",
+			checkout.ID, rental.RentalID, rental.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("error recording checkout rental: %w", err)
+		}
+		if rentalPoints > 0 {
+			err = awardLoyaltyPoints(ctx, tx, customerID, rentalPoints, models.LoyaltyReasonRental, rental.RentalID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if checkout.CreditApplied > 0 {
+		if err = recordLoyaltyCredit(ctx, tx, customerID, checkout.ID, checkout.CreditApplied); err != nil {
+			return nil, err
+		}
+	}
+	if checkout.GiftCardApplied > 0 {
+		if err = recordGiftCardSpend(ctx, tx, giftCardID, checkout.ID, checkout.GiftCardApplied); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing checkout: %w", err)
+	}
+
+	return checkout, nil
+}
+
+// GetCheckout retrieves a checkout with its rentals.
+func (r *CartRepository) GetCheckout(ctx context.Context, checkoutID int) (*models.Checkout, error) {
+	ctx = database.WithQueryName(ctx, "carts.get_checkout")
+	checkout := &models.Checkout{Rentals: []models.CheckoutRental{}}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT ch.id, ch.customer_id, ch.store_id, ch.subtotal::float8, ch.discount::float8,
+			ch.credit_applied::float8, ch.gift_card_applied::float8, ch.total::float8, COALESCE(cp.code, ''), ch.created_at
+		FROM checkouts ch
+		LEFT JOIN coupon_redemptions cr ON cr.id = ch.coupon_redemption_id
+		LEFT JOIN coupons cp ON cp.id = cr.coupon_id
+		WHERE ch.id = $1`, checkoutID,
+	).Scan(&checkout.ID, &checkout.CustomerID, &checkout.StoreID, &checkout.Subtotal, &checkout.Discount,
+		&checkout.CreditApplied, &checkout.GiftCardApplied, &checkout.Total, &checkout.CouponCode, &checkout.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCheckoutNotFound
+		}
+		return nil, fmt.Errorf("error querying checkout: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.rental_id, r.inventory_id, f.film_id, f.title, cr.rate::float8, `+rentalDueAt+`
+		FROM checkout_rentals cr
+		JOIN rental r ON r.rental_id = cr.rental_id
+		JOIN inventory i ON i.inventory_id = r.inventory_id
+		JOIN film f ON f.film_id = i.film_id
+		WHERE cr.checkout_id = $1
+		ORDER BY r.rental_id`, checkoutID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying checkout rentals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rental models.CheckoutRental
+		if scanErr := rows.Scan(
+			&rental.RentalID, &rental.InventoryID, &rental.FilmID, &rental.Title, &rental.Rate, &rental.DueAt,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning checkout rental: %w", scanErr)
+		}
+		checkout.Rentals = append(checkout.Rentals, rental)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating checkout rentals: %w", rowsErr)
+	}
+
+	return checkout, nil
+}
+
+// rentCopy rents out an in-stock copy of line's film at storeID within tx.
+// The copy is locked until tx ends, and copies locked by concurrent
+// checkouts are skipped, so no copy is rented twice.
+func rentCopy(
+	ctx context.Context,
+	tx *sql.Tx,
+	line models.CheckoutLine,
+	customerID, storeID, staffID int,
+) (*models.CheckoutRental, error) {
+	rental := &models.CheckoutRental{FilmID: line.FilmID, Rate: line.Rate}
+	var rentalDuration int
+	err := tx.QueryRowContext(ctx, `
+		SELECT i.inventory_id, f.title, f.rental_duration
+		FROM inventory i
+		JOIN film f ON f.film_id = i.film_id
+		WHERE i.film_id = $1 AND i.store_id = $2 AND f.status = 'published' AND `+copyAvailable+`
+		ORDER BY i.inventory_id
+		LIMIT 1
+		FOR UPDATE OF i SKIP LOCKED`, line.FilmID, storeID).Scan(&rental.InventoryID, &rental.Title, &rentalDuration)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: film %d", ErrFilmUnavailable, line.FilmID)
+		}
+		return nil, fmt.Errorf("error finding an available copy: %w", err)
+	}
+
+	var rentalDate time.Time
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO rental (rental_date, inventory_id, customer_id, staff_id)
+		VALUES (NOW(), $1, $2, $3)
+		RETURNING rental_id, rental_date`, rental.InventoryID, customerID, staffID,
+	).Scan(&rental.RentalID, &rentalDate)
+	if err != nil {
+		return nil, fmt.Errorf("error creating rental: %w", err)
+	}
+	rental.DueAt = rentalDate.AddDate(0, 0, rentalDuration)
+
+	return rental, nil
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// collectionColumns lists the collections columns scanned by scanCollection,
+// in order, with the number of films in the collection.
+const collectionColumns = `c.id, c.name, c.description,
+		(SELECT COUNT(*) FROM collection_films cf WHERE cf.collection_id = c.id), c.created_at, c.updated_at`
+
+// CollectionRepository handles database operations for film collections.
+type CollectionRepository struct {
+	db *database.DB
+}
+
+// NewCollectionRepository creates a new collection repository.
+func NewCollectionRepository(db *database.DB) *CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+// CreateCollection stores a new collection with its films in the given
+// order.
+func (r *CollectionRepository) CreateCollection(
+	ctx context.Context,
+	collectionReq models.CollectionRequest,
+) (*models.Collection, error) {
+	ctx = database.WithQueryName(ctx, "collections.create")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "collections.create")
+
+	var collectionID int
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO collections (name, description) VALUES ($1, $2) RETURNING id",
+		collectionReq.Name, collectionReq.Description,
+	).Scan(&collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting collection: %w", constraintError(err, ErrCollectionNameTaken))
+	}
+
+	if err = insertCollectionFilms(ctx, tx, collectionID, collectionReq.FilmIDs); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing collection: %w", err)
+	}
+
+	return r.GetCollection(ctx, collectionID)
+}
+
+// ListCollections retrieves every collection by name, with the number of
+// films in each but not the films themselves.
+func (r *CollectionRepository) ListCollections(ctx context.Context) ([]models.Collection, error) {
+	query := "SELECT " + collectionColumns + " FROM collections c ORDER BY c.name, c.id"
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "collections.list"), query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying collections: %w", err)
+	}
+	defer rows.Close()
+
+	collections := []models.Collection{}
+	for rows.Next() {
+		collection, scanErr := scanCollection(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning collection: %w", scanErr)
+		}
+		collections = append(collections, *collection)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating collections: %w", rowsErr)
+	}
+
+	return collections, nil
+}
+
+// GetCollection retrieves a collection with its published films in order.
+func (r *CollectionRepository) GetCollection(ctx context.Context, collectionID int) (*models.Collection, error) {
+	ctx = database.WithQueryName(ctx, "collections.get")
+	collection, err := scanCollection(r.db.QueryRowContext(ctx,
+		"SELECT "+collectionColumns+" FROM collections c WHERE c.id = $1", collectionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, fmt.Errorf("error querying collection: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "collections.films"), `
+		SELECT f.film_id, f.title, f.release_year, f.rating, cf.position
+		FROM collection_films cf
+		JOIN film f ON f.film_id = cf.film_id
+		WHERE cf.collection_id = $1 AND f.status = 'published'
+		ORDER BY cf.position`, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying collection films: %w", err)
+	}
+	defer rows.Close()
+
+	collection.Films = []models.CollectionFilm{}
+	for rows.Next() {
+		var film models.CollectionFilm
+		if scanErr := rows.Scan(
+			&film.FilmID, &film.Title, &film.ReleaseYear, &film.Rating, &film.Position,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning collection film: %w", scanErr)
+		}
+		collection.Films = append(collection.Films, film)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating collection films: %w", rowsErr)
+	}
+
+	return collection, nil
+}
+
+// UpdateCollection replaces a collection's name, description, and films,
+// returning the collection and the films it held before.
+func (r *CollectionRepository) UpdateCollection(
+	ctx context.Context,
+	collectionID int,
+	collectionReq models.CollectionRequest,
+) (*models.Collection, []int, error) {
+	ctx = database.WithQueryName(ctx, "collections.update")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "collections.update")
+
+	previousFilmIDs, err := lockCollectionFilms(ctx, tx, collectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE collections SET name = $2, description = $3, updated_at = NOW() WHERE id = $1",
+		collectionID, collectionReq.Name, collectionReq.Description)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating collection: %w", constraintError(err, ErrCollectionNameTaken))
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM collection_films WHERE collection_id = $1", collectionID); err != nil {
+		return nil, nil, fmt.Errorf("error clearing collection films: %w", err)
+	}
+	if err = insertCollectionFilms(ctx, tx, collectionID, collectionReq.FilmIDs); err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("error committing collection: %w", err)
+	}
+
+	collection, err := r.GetCollection(ctx, collectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return collection, previousFilmIDs, nil
+}
+
+// DeleteCollection deletes a collection, returning the films it held. The
+// films themselves are kept.
+func (r *CollectionRepository) DeleteCollection(ctx context.Context, collectionID int) ([]int, error) {
+	ctx = database.WithQueryName(ctx, "collections.delete")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "collections.delete")
+
+	filmIDs, err := lockCollectionFilms(ctx, tx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM collections WHERE id = $1", collectionID); err != nil {
+		return nil, fmt.Errorf("error deleting collection: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing collection deletion: %w", err)
+	}
+
+	return filmIDs, nil
+}
+
+// lockCollectionFilms locks a collection for changes and returns the IDs of
+// its films in order.
+func lockCollectionFilms(ctx context.Context, tx *sql.Tx, collectionID int) ([]int, error) {
+	var locked int
+	err := tx.QueryRowContext(ctx, "SELECT id FROM collections WHERE id = $1 FOR UPDATE", collectionID).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, fmt.Errorf("error locking collection: %w", err)
+	}
+
+	var filmIDs pq.Int64Array
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(array_agg(film_id ORDER BY position), '{}')
+		FROM collection_films
+		WHERE collection_id = $1`, collectionID).Scan(&filmIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error querying collection films: %w", err)
+	}
+
+	ids := make([]int, 0, len(filmIDs))
+	for _, id := range filmIDs {
+		ids = append(ids, int(id))
+	}
+	return ids, nil
+}
+
+// insertCollectionFilms adds films to a collection at positions 1, 2, and
+// so on, in the order given.
+func insertCollectionFilms(ctx context.Context, tx *sql.Tx, collectionID int, filmIDs []int) error {
+	if len(filmIDs) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, 0, len(filmIDs))
+	for _, id := range filmIDs {
+		ids = append(ids, int64(id))
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO collection_films (collection_id, film_id, position)
+		SELECT $1, m.film_id, m.position
+		FROM unnest($2::int[]) WITH ORDINALITY AS m(film_id, position)`, collectionID, ids)
+	if err != nil {
+		return fmt.Errorf("error adding collection films: %w", constraintError(err, err))
+	}
+	return nil
+}
+
+// scanCollection scans collectionColumns from row.
+func scanCollection(row interface{ Scan(dest ...any) error }) (*models.Collection, error) {
+	var collection models.Collection
+	err := row.Scan(
+		&collection.ID, &collection.Name, &collection.Description, &collection.FilmCount,
+		&collection.CreatedAt, &collection.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
@@ -3,74 +3,175 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/rxbenefits/go-hw/internal/database"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/pii"
 )
 
-// CommentRepository handles database operations for comments.
+// commentColumns lists the comment columns scanned by scanComment, in order,
+// selected from commentSource, with a linked customer's first name and last
+// initial, the customers the comment mentions, and the storage keys of its
+// attachment. A linked comment's author is a verified renter if they have
+// ever rented a copy of the film.
+const commentColumns = `fc.id, fc.film_id, COALESCE(fc.customer_name, ''), fc.comment, fc.created_at,
+	fc.customer_id, fc.parent_id, c.first_name || ' ' || LEFT(c.last_name, 1) || '.',
+	fc.customer_id IS NOT NULL AND EXISTS (
+		SELECT 1 FROM rental r JOIN inventory i ON i.inventory_id = r.inventory_id
+		WHERE r.customer_id = fc.customer_id AND i.film_id = fc.film_id
+	),
+	ARRAY(SELECT cm.customer_id FROM comment_mentions cm WHERE cm.comment_id = fc.id ORDER BY cm.customer_id),
+	COALESCE(fc.attachment_key, ''), COALESCE(fc.thumbnail_key, '')`
+
+// commentSource joins comments, aliased fc, to their linked customers.
+const commentSource = "fc LEFT JOIN customer c ON c.customer_id = fc.customer_id"
+
+// commentNotShadowBanned holds for comments, aliased fc, whose customer is
+// not shadow-banned. Guest comments have no customer and always hold.
+const commentNotShadowBanned = `NOT EXISTS (
+	SELECT 1 FROM customer_shadow_bans b WHERE b.customer_id = fc.customer_id)`
+
+// formerCustomerName is shown for comments whose customer was deleted.
+const formerCustomerName = "Former customer"
+
+// encryptBatchSize is the number of comment names EncryptCommentNames
+// encrypts per query.
+const encryptBatchSize = 500
+
+// CommentRepository handles database operations for comments. Guest names
+// are encrypted with cipher when stored and decrypted when read.
 type CommentRepository struct {
-	db *database.DB
+	db     database.Querier
+	cipher *pii.Cipher
 }
 
-// NewCommentRepository creates a new comment repository.
-func NewCommentRepository(db *database.DB) *CommentRepository {
-	return &CommentRepository{db: db}
+// NewCommentRepository creates a new comment repository running its queries
+// on db and encrypting guest names with cipher.
+func NewCommentRepository(db database.Querier, cipher *pii.Cipher) *CommentRepository {
+	return &CommentRepository{db: db, cipher: cipher}
 }
 
-// AddComment adds a new comment to a film.
-func (r *CommentRepository) AddComment(filmID int, commentReq models.CommentRequest) (*models.Comment, error) {
-	var filmExists bool
-	err := r.db.QueryRowContext(context.Background(), "SELECT EXISTS(SELECT 1 FROM film WHERE film_id = $1)", filmID).
-		Scan(&filmExists)
+// AddComment adds a new comment to a film, recording the customers it
+// mentions. It returns ErrCommentsLocked if comments on the film are locked.
+func (r *CommentRepository) AddComment(
+	ctx context.Context,
+	filmID int,
+	commentReq models.CommentRequest,
+) (*models.Comment, error) {
+	var filmExists, locked bool
+	existsCtx := database.WithQueryName(ctx, "comments.film_exists")
+	err := r.db.QueryRowContext(existsCtx, `
+		SELECT EXISTS(SELECT 1 FROM film WHERE film_id = $1),
+			EXISTS(SELECT 1 FROM film_comment_locks WHERE film_id = $1)`, filmID,
+	).Scan(&filmExists, &locked)
 	if err != nil {
 		return nil, fmt.Errorf("error checking film existence: %w", err)
 	}
 	if !filmExists {
 		return nil, ErrFilmNotFound
 	}
+	if locked {
+		return nil, ErrCommentsLocked
+	}
 
+	if commentReq.ParentID != nil {
+		var parentExists bool
+		parentCtx := database.WithQueryName(ctx, "comments.parent_exists")
+		err = r.db.QueryRowContext(parentCtx,
+			"SELECT EXISTS(SELECT 1 FROM film_comments WHERE id = $1 AND film_id = $2)", *commentReq.ParentID, filmID,
+		).Scan(&parentExists)
+		if err != nil {
+			return nil, fmt.Errorf("error checking parent comment existence: %w", err)
+		}
+		if !parentExists {
+			return nil, ErrCommentNotFound
+		}
+	}
+
+	// Guest comments keep their free-text name; linked comments store none.
+	// Mentions are inserted in the same statement, so a comment is never
+	// stored without them.
 	query := `
-		INSERT INTO film_comments (film_id, customer_name, comment, created_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, film_id, customer_name, comment, created_at
-	`
+		WITH fc AS (
+			INSERT INTO film_comments
+				(film_id, customer_name, comment, created_at, customer_id, parent_id, attachment_key, thumbnail_key)
+			VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, NULLIF($8, ''), NULLIF($9, ''))
+			RETURNING *
+		), mentions AS (
+			INSERT INTO comment_mentions (comment_id, customer_id)
+			SELECT fc.id, mentioned FROM fc, unnest($7::int[]) AS mentioned
+		)
+		SELECT ` + commentColumns + " FROM " + commentSource
+
+	customerName, err := r.cipher.Encrypt(commentReq.CustomerName)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting customer name: %w", err)
+	}
 
-	var comment models.Comment
 	now := time.Now()
-	err = r.db.QueryRowContext(context.Background(), query, filmID, commentReq.CustomerName, commentReq.Comment, now).
-		Scan(
-			&comment.ID, &comment.FilmID, &comment.CustomerName, &comment.Comment, &comment.CreatedAt,
-		)
+	insertCtx := database.WithQueryName(ctx, "comments.insert")
+	row := r.db.QueryRowContext(insertCtx, query,
+		filmID, customerName, commentReq.Comment, now, commentReq.CustomerID, commentReq.ParentID,
+		pq.Array(commentReq.MentionIDs), commentReq.AttachmentKey, commentReq.ThumbnailKey,
+	)
+	comment, err := scanComment(row, r.cipher)
 	if err != nil {
 		return nil, fmt.Errorf("error inserting comment: %w", err)
 	}
+	// The select cannot see the mentions its own statement inserted.
+	comment.Mentions = commentReq.MentionIDs
 
-	return &comment, nil
+	return comment, nil
 }
 
-// GetCommentsByFilmID retrieves all comments for a specific film.
-func (r *CommentRepository) GetCommentsByFilmID(filmID int) ([]models.Comment, error) {
-	var filmExists bool
-	err := r.db.QueryRowContext(context.Background(), "SELECT EXISTS(SELECT 1 FROM film WHERE film_id = $1)", filmID).
-		Scan(&filmExists)
-	if err != nil {
-		return nil, fmt.Errorf("error checking film existence: %w", err)
+// GetCommentsByFilmID retrieves limit of a film's comments, newest first,
+// with the film's total number of comments. The page starts after the
+// comment after marks, or at the newest comment when after is nil, and
+// NextCursor marks the page's last comment if more follow. Pages are read
+// by keyset on (created_at, id) rather than by offset, so they stay fast
+// and never skip or repeat comments as new ones are posted. Comments by
+// shadow-banned customers are left out, and from the total, except for the
+// viewer's own; a viewerID of 0 is an anonymous viewer. Films that are not
+// published are reported as not found.
+func (r *CommentRepository) GetCommentsByFilmID(
+	ctx context.Context,
+	filmID int,
+	viewerID int,
+	limit int,
+	after *pagination.Cursor,
+) (*pagination.Paginated[models.Comment], error) {
+	if err := checkFilmPublished(ctx, r.db, "comments.film_exists", filmID); err != nil {
+		return nil, err
 	}
-	if !filmExists {
-		return nil, ErrFilmNotFound
+
+	visible := " WHERE fc.film_id = $1 AND (fc.customer_id = $2 OR " + commentNotShadowBanned + ")"
+
+	var total int
+	countCtx := database.WithQueryName(ctx, "comments.count")
+	err := r.db.QueryRowContext(countCtx, "SELECT COUNT(*) FROM film_comments fc"+visible, filmID, viewerID).
+		Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("error counting comments: %w", err)
 	}
 
-	query := `
-		SELECT id, film_id, customer_name, comment, created_at
-		FROM film_comments
-		WHERE film_id = $1
-		ORDER BY created_at DESC
-	`
+	// One comment past the page tells whether another page follows.
+	query := "SELECT " + commentColumns + " FROM film_comments " + commentSource + visible
+	args := []any{filmID, viewerID}
+	if after != nil {
+		query += " AND (fc.created_at, fc.id) < ($3, $4)"
+		args = append(args, after.Time, after.ID)
+	}
+	query += fmt.Sprintf(" ORDER BY fc.created_at DESC, fc.id DESC LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
 
-	rows, queryErr := r.db.QueryContext(context.Background(), query, filmID)
+	rows, queryErr := r.db.QueryContext(database.WithQueryName(ctx, "comments.list"), query, args...)
 	if queryErr != nil {
 		return nil, fmt.Errorf("error querying comments: %w", queryErr)
 	}
@@ -78,17 +179,364 @@ func (r *CommentRepository) GetCommentsByFilmID(filmID int) ([]models.Comment, e
 
 	var comments []models.Comment
 	for rows.Next() {
-		var comment models.Comment
-		scanErr := rows.Scan(&comment.ID, &comment.FilmID, &comment.CustomerName, &comment.Comment, &comment.CreatedAt)
+		comment, scanErr := scanComment(rows, r.cipher)
 		if scanErr != nil {
 			return nil, fmt.Errorf("error scanning comment: %w", scanErr)
 		}
-		comments = append(comments, comment)
+		comments = append(comments, *comment)
 	}
 
 	if rowsErr := rows.Err(); rowsErr != nil {
 		return nil, fmt.Errorf("error iterating comments: %w", rowsErr)
 	}
 
-	return comments, nil
+	more := len(comments) > limit
+	if more {
+		comments = comments[:limit]
+	}
+	page := pagination.New(comments, total, pagination.Params{Limit: limit})
+	if more {
+		last := comments[len(comments)-1]
+		page.NextCursor = pagination.Cursor{Time: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	return page, nil
+}
+
+// GetCommentAuthor retrieves the customer who posted a verified comment.
+// Anonymous comments have no author and return ErrCommentNotFound.
+func (r *CommentRepository) GetCommentAuthor(ctx context.Context, commentID int) (*models.CommentAuthor, error) {
+	query := `
+		SELECT c.customer_id, c.first_name, COALESCE(cc.email, c.email, '')
+		FROM film_comments fc
+		JOIN customer c ON c.customer_id = fc.customer_id
+		LEFT JOIN customer_credentials cc ON cc.customer_id = c.customer_id
+		WHERE fc.id = $1
+	`
+
+	var author models.CommentAuthor
+	authorCtx := database.WithQueryName(ctx, "comments.author")
+	err := r.db.QueryRowContext(authorCtx, query, commentID).Scan(&author.CustomerID, &author.FirstName, &author.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("error querying comment author: %w", err)
+	}
+
+	return &author, nil
+}
+
+// GetMentionTargets retrieves the active customers among customerIDs, to
+// check and notify the customers a comment mentions.
+func (r *CommentRepository) GetMentionTargets(ctx context.Context, customerIDs []int) ([]models.CommentAuthor, error) {
+	query := `
+		SELECT c.customer_id, c.first_name, COALESCE(cc.email, c.email, '')
+		FROM customer c
+		LEFT JOIN customer_credentials cc ON cc.customer_id = c.customer_id
+		WHERE c.customer_id = ANY($1) AND c.activebool
+		ORDER BY c.customer_id
+	`
+
+	ctx = database.WithQueryName(ctx, "comments.mention_targets")
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(customerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error querying mentioned customers: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []models.CommentAuthor
+	for rows.Next() {
+		var target models.CommentAuthor
+		if scanErr := rows.Scan(&target.CustomerID, &target.FirstName, &target.Email); scanErr != nil {
+			return nil, fmt.Errorf("error scanning mentioned customer: %w", scanErr)
+		}
+		targets = append(targets, target)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating mentioned customers: %w", rowsErr)
+	}
+
+	return targets, nil
+}
+
+// SetCommentsLocked locks or unlocks new comments on a film. Locking a
+// locked film keeps its original lock time.
+func (r *CommentRepository) SetCommentsLocked(
+	ctx context.Context,
+	filmID int,
+	locked bool,
+) (*models.CommentLockStatus, error) {
+	if err := checkFilmExists(ctx, r.db, "comments.film_exists", filmID); err != nil {
+		return nil, err
+	}
+
+	status := &models.CommentLockStatus{FilmID: filmID, Locked: locked}
+	if !locked {
+		unlockCtx := database.WithQueryName(ctx, "comments.unlock")
+		if _, err := r.db.ExecContext(unlockCtx, "DELETE FROM film_comment_locks WHERE film_id = $1", filmID); err != nil {
+			return nil, fmt.Errorf("error unlocking comments: %w", err)
+		}
+		return status, nil
+	}
+
+	query := `
+		INSERT INTO film_comment_locks (film_id) VALUES ($1)
+		ON CONFLICT (film_id) DO UPDATE SET locked_at = film_comment_locks.locked_at
+		RETURNING locked_at`
+
+	var lockedAt time.Time
+	lockCtx := database.WithQueryName(ctx, "comments.lock")
+	if err := r.db.QueryRowContext(lockCtx, query, filmID).Scan(&lockedAt); err != nil {
+		return nil, fmt.Errorf("error locking comments: %w", err)
+	}
+	status.LockedAt = &lockedAt
+
+	return status, nil
+}
+
+// SetShadowBanned shadow-bans a customer or lifts their ban. Banning a
+// banned customer keeps their original ban time. It returns
+// ErrCustomerNotFound if the customer does not exist.
+func (r *CommentRepository) SetShadowBanned(
+	ctx context.Context,
+	customerID int,
+	banned bool,
+) (*models.ShadowBanStatus, error) {
+	var exists bool
+	existsCtx := database.WithQueryName(ctx, "comments.customer_exists")
+	err := r.db.QueryRowContext(existsCtx, "SELECT EXISTS(SELECT 1 FROM customer WHERE customer_id = $1)", customerID).
+		Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("error checking customer existence: %w", err)
+	}
+	if !exists {
+		return nil, ErrCustomerNotFound
+	}
+
+	status := &models.ShadowBanStatus{CustomerID: customerID, Banned: banned}
+	if !banned {
+		unbanCtx := database.WithQueryName(ctx, "comments.shadow_unban")
+		if _, err = r.db.ExecContext(unbanCtx,
+			"DELETE FROM customer_shadow_bans WHERE customer_id = $1", customerID); err != nil {
+			return nil, fmt.Errorf("error lifting shadow ban: %w", err)
+		}
+		return status, nil
+	}
+
+	query := `
+		INSERT INTO customer_shadow_bans (customer_id) VALUES ($1)
+		ON CONFLICT (customer_id) DO UPDATE SET banned_at = customer_shadow_bans.banned_at
+		RETURNING banned_at`
+
+	var bannedAt time.Time
+	banCtx := database.WithQueryName(ctx, "comments.shadow_ban")
+	if err = r.db.QueryRowContext(banCtx, query, customerID).Scan(&bannedAt); err != nil {
+		return nil, fmt.Errorf("error shadow-banning customer: %w", err)
+	}
+	status.BannedAt = &bannedAt
+
+	return status, nil
+}
+
+// IsShadowBanned reports whether a customer is shadow-banned.
+func (r *CommentRepository) IsShadowBanned(ctx context.Context, customerID int) (bool, error) {
+	var banned bool
+	ctx = database.WithQueryName(ctx, "comments.is_shadow_banned")
+	err := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM customer_shadow_bans WHERE customer_id = $1)", customerID).Scan(&banned)
+	if err != nil {
+		return false, fmt.Errorf("error checking shadow ban: %w", err)
+	}
+	return banned, nil
+}
+
+// RefreshCommentSummaries replaces every film's comment summary: its
+// comment count, latest comment time, the keywordLimit words its comments
+// use most, leaving out stopwords and words under three letters, and the
+// average comment count of films with the same rating. Summaries are
+// public, so comments by shadow-banned customers are left out.
+func (r *CommentRepository) RefreshCommentSummaries(ctx context.Context, stopwords []string, keywordLimit int) error {
+	// Summaries are upserted in one statement, so readers never see a
+	// partly refreshed set; deleted films' summaries go with them.
+	query := `
+		INSERT INTO film_comment_summaries
+			(film_id, rating, comment_count, latest_comment_at, top_keywords, rating_average_comments)
+		SELECT f.film_id, COALESCE(f.rating::text, ''), COALESCE(c.comment_count, 0), c.latest_comment_at,
+			COALESCE(k.keywords, '{}'), AVG(COALESCE(c.comment_count, 0)) OVER (PARTITION BY f.rating)
+		FROM film f
+		LEFT JOIN (
+			SELECT fc.film_id, COUNT(*) AS comment_count, MAX(fc.created_at) AS latest_comment_at
+			FROM film_comments fc
+			WHERE ` + commentNotShadowBanned + `
+			GROUP BY fc.film_id
+		) c ON c.film_id = f.film_id
+		LEFT JOIN (
+			SELECT film_id, array_agg(word ORDER BY uses DESC, word) AS keywords
+			FROM (
+				SELECT fc.film_id, word, COUNT(*) AS uses,
+					ROW_NUMBER() OVER (PARTITION BY fc.film_id ORDER BY COUNT(*) DESC, word) AS rank
+				FROM film_comments fc, regexp_split_to_table(lower(fc.comment), '[^a-z]+') AS word
+				WHERE length(word) >= 3 AND NOT word = ANY($1) AND ` + commentNotShadowBanned + `
+				GROUP BY fc.film_id, word
+			) ranked
+			WHERE rank <= $2
+			GROUP BY film_id
+		) k ON k.film_id = f.film_id
+		ON CONFLICT (film_id) DO UPDATE SET
+			rating = EXCLUDED.rating,
+			comment_count = EXCLUDED.comment_count,
+			latest_comment_at = EXCLUDED.latest_comment_at,
+			top_keywords = EXCLUDED.top_keywords,
+			rating_average_comments = EXCLUDED.rating_average_comments,
+			refreshed_at = NOW()`
+
+	ctx = database.WithQueryName(ctx, "comments.refresh_summaries")
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(stopwords), keywordLimit); err != nil {
+		return fmt.Errorf("error summarizing comments: %w", err)
+	}
+
+	return nil
+}
+
+// GetCommentSummary retrieves a film's most recently computed comment
+// summary. It returns ErrCommentSummaryNotFound for films added since the
+// summaries were last refreshed, and ErrFilmNotFound for films that are not
+// published.
+func (r *CommentRepository) GetCommentSummary(ctx context.Context, filmID int) (*models.CommentSummary, error) {
+	if err := checkFilmPublished(ctx, r.db, "comments.film_exists", filmID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT film_id, rating, comment_count, latest_comment_at, top_keywords, rating_average_comments, refreshed_at
+		FROM film_comment_summaries
+		WHERE film_id = $1`
+
+	summary := models.CommentSummary{TopKeywords: []string{}}
+	correlation := &summary.RatingCorrelation
+	ctx = database.WithQueryName(ctx, "comments.summary")
+	err := r.db.QueryRowContext(ctx, query, filmID).Scan(
+		&summary.FilmID, &correlation.Rating, &summary.CommentCount, &summary.LatestCommentAt,
+		(*pq.StringArray)(&summary.TopKeywords), &correlation.AverageComments, &summary.RefreshedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentSummaryNotFound
+		}
+		return nil, fmt.Errorf("error querying comment summary: %w", err)
+	}
+	if correlation.AverageComments > 0 {
+		correlation.RelativeActivity = float64(summary.CommentCount) / correlation.AverageComments
+	}
+
+	return &summary, nil
+}
+
+// EncryptCommentNames encrypts the guest names stored before encryption
+// was enabled, in batches, returning how many it encrypted. A name changed
+// meanwhile, such as by another replica doing the same, is left alone.
+func (r *CommentRepository) EncryptCommentNames(ctx context.Context) (int, error) {
+	if !r.cipher.Enabled() {
+		return 0, nil
+	}
+
+	encrypted := 0
+	for lastID := 0; ; {
+		names, err := r.plaintextCommentNames(ctx, lastID)
+		if err != nil {
+			return encrypted, err
+		}
+		if len(names) == 0 {
+			return encrypted, nil
+		}
+
+		for _, name := range names {
+			lastID = name.id
+			ciphertext, encryptErr := r.cipher.Encrypt(name.value)
+			if encryptErr != nil {
+				return encrypted, fmt.Errorf("error encrypting customer name: %w", encryptErr)
+			}
+			ctx = database.WithQueryName(ctx, "comments.encrypt_name")
+			_, err = r.db.ExecContext(ctx,
+				"UPDATE film_comments SET customer_name = $2 WHERE id = $1 AND customer_name = $3",
+				name.id, ciphertext, name.value)
+			if err != nil {
+				return encrypted, fmt.Errorf("error storing encrypted customer name: %w", err)
+			}
+			encrypted++
+		}
+	}
+}
+
+// commentName is a comment's stored guest name.
+type commentName struct {
+	id    int
+	value string
+}
+
+// plaintextCommentNames retrieves the next batch of unencrypted guest
+// names on comments after afterID, in ID order.
+func (r *CommentRepository) plaintextCommentNames(ctx context.Context, afterID int) ([]commentName, error) {
+	ctx = database.WithQueryName(ctx, "comments.plaintext_names")
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, customer_name FROM film_comments
+		WHERE id > $1 AND customer_name IS NOT NULL AND customer_name <> '' AND customer_name NOT LIKE $2
+		ORDER BY id
+		LIMIT $3`, afterID, pii.Prefix+"%", encryptBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("error querying customer names: %w", err)
+	}
+	defer rows.Close()
+
+	var names []commentName
+	for rows.Next() {
+		var name commentName
+		if scanErr := rows.Scan(&name.id, &name.value); scanErr != nil {
+			return nil, fmt.Errorf("error scanning customer name: %w", scanErr)
+		}
+		names = append(names, name)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating customer names: %w", rowsErr)
+	}
+
+	return names, nil
+}
+
+// scanComment scans commentColumns from row, decrypting the guest name
+// with cipher. Linked comments are verified and shown under the customer's
+// first name and last initial; guest comments under the name given, and
+// comments whose customer was deleted as formerCustomerName.
+func scanComment(row interface{ Scan(dest ...any) error }, cipher *pii.Cipher) (*models.Comment, error) {
+	var comment models.Comment
+	var linkedName *string
+	var mentions pq.Int64Array
+	err := row.Scan(
+		&comment.ID, &comment.FilmID, &comment.CustomerName, &comment.Comment, &comment.CreatedAt,
+		&comment.CustomerID, &comment.ParentID, &linkedName, &comment.VerifiedRenter, &mentions,
+		&comment.AttachmentKey, &comment.ThumbnailKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range mentions {
+		comment.Mentions = append(comment.Mentions, int(id))
+	}
+	if comment.CustomerName, err = cipher.Decrypt(comment.CustomerName); err != nil {
+		return nil, fmt.Errorf("error decrypting customer name: %w", err)
+	}
+
+	comment.Verified = comment.CustomerID != nil
+	switch {
+	case linkedName != nil:
+		comment.DisplayName = *linkedName
+	case comment.CustomerName != "":
+		comment.DisplayName = comment.CustomerName
+	default:
+		comment.DisplayName = formerCustomerName
+	}
+	return &comment, nil
 }
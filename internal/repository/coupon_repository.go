@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// couponColumns lists the columns scanned by scanCoupon, in order, for a
+// coupons table aliased c.
+const couponColumns = `c.id, c.code, COALESCE(c.description, ''), c.percent_off::float8, c.amount_off::float8,
+		c.max_redemptions, c.max_per_customer, c.expires_at,
+		(SELECT COUNT(*) FROM coupon_redemptions cr WHERE cr.coupon_id = c.id), c.created_at`
+
+// rowQuerier runs single-row queries; both *database.DB and *sql.Tx satisfy
+// it.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// couponUsage is a coupon's standing for one customer and checkout amount.
+type couponUsage struct {
+	couponID            int
+	code                string
+	maxRedemptions      *int
+	maxPerCustomer      *int
+	expired             bool
+	redemptions         int
+	customerRedemptions int
+	discount            float64
+	total               float64
+}
+
+// CouponRepository handles database operations for coupons and their
+// redemptions.
+type CouponRepository struct {
+	db *database.DB
+}
+
+// NewCouponRepository creates a new coupon repository.
+func NewCouponRepository(db *database.DB) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+// CreateCoupon stores a new coupon. The code must already be upper-case.
+func (r *CouponRepository) CreateCoupon(ctx context.Context, couponReq models.CouponRequest) (*models.Coupon, error) {
+	query := `
+		WITH c AS (
+			INSERT INTO coupons (
+				code, description, percent_off, amount_off, max_redemptions, max_per_customer, expires_at
+			)
+			VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7)
+			RETURNING *
+		)
+		SELECT ` + couponColumns + ` FROM c`
+
+	row := r.db.QueryRowContext(database.WithQueryName(ctx, "coupons.create"), query,
+		couponReq.Code, couponReq.Description, couponReq.PercentOff, couponReq.AmountOff,
+		couponReq.MaxRedemptions, couponReq.MaxPerCustomer, couponReq.ExpiresAt,
+	)
+	coupon, err := scanCoupon(row)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting coupon: %w", constraintError(err, ErrCouponCodeTaken))
+	}
+
+	return coupon, nil
+}
+
+// ListCoupons retrieves every coupon with its redemption count, most recent
+// first.
+func (r *CouponRepository) ListCoupons(ctx context.Context) ([]models.Coupon, error) {
+	query := "SELECT " + couponColumns + " FROM coupons c ORDER BY c.created_at DESC, c.id DESC"
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "coupons.list"), query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying coupons: %w", err)
+	}
+	defer rows.Close()
+
+	coupons := []models.Coupon{}
+	for rows.Next() {
+		coupon, scanErr := scanCoupon(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning coupon: %w", scanErr)
+		}
+		coupons = append(coupons, *coupon)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating coupons: %w", rowsErr)
+	}
+
+	return coupons, nil
+}
+
+// ValidateCoupon reports the discount a coupon would give a customer's
+// checkout of amount, without redeeming it.
+func (r *CouponRepository) ValidateCoupon(
+	ctx context.Context,
+	code string,
+	customerID int,
+	amount float64,
+) (*models.CouponQuote, error) {
+	ctx = database.WithQueryName(ctx, "coupons.validate")
+	usage, err := getCouponUsage(ctx, r.db, code, customerID, amount, false)
+	if err != nil {
+		return nil, err
+	}
+	if err = usage.check(); err != nil {
+		return nil, err
+	}
+
+	return &models.CouponQuote{
+		Code:     usage.code,
+		Amount:   amount,
+		Discount: usage.discount,
+		Total:    usage.total,
+	}, nil
+}
+
+// RedeemCoupon uses a coupon for a customer's checkout of amount.
+func (r *CouponRepository) RedeemCoupon(
+	ctx context.Context,
+	code string,
+	customerID int,
+	amount float64,
+) (*models.CouponRedemption, error) {
+	ctx = database.WithQueryName(ctx, "coupons.redeem")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "coupons.redeem")
+
+	redemption, err := redeemCoupon(ctx, tx, code, customerID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing coupon redemption: %w", err)
+	}
+
+	return redemption, nil
+}
+
+// redeemCoupon records a redemption of a coupon within tx, so it can be part
+// of a larger checkout. The coupon row stays locked until tx ends, so
+// concurrent redemptions cannot overrun its limits.
+func redeemCoupon(
+	ctx context.Context,
+	tx *sql.Tx,
+	code string,
+	customerID int,
+	amount float64,
+) (*models.CouponRedemption, error) {
+	usage, err := getCouponUsage(ctx, tx, code, customerID, amount, true)
+	if err != nil {
+		return nil, err
+	}
+	if err = usage.check(); err != nil {
+		return nil, err
+	}
+
+	redemption := models.CouponRedemption{
+		Code:       usage.code,
+		CustomerID: customerID,
+		Amount:     amount,
+		Discount:   usage.discount,
+		Total:      usage.total,
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO coupon_redemptions (coupon_id, customer_id, amount, discount)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, redeemed_at`,
+		usage.couponID, customerID, amount, usage.discount,
+	).Scan(&redemption.ID, &redemption.RedeemedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error recording coupon redemption: %w", constraintError(err, err))
+	}
+
+	return &redemption, nil
+}
+
+// getCouponUsage retrieves a coupon's standing for a customer and the
+// discount it gives amount, capped at amount, with the total left to pay.
+// With lock set, the coupon row is locked for update before its redemptions
+// are counted, in a statement of their own: a statement sees only what had
+// committed when it started, so counting in the locking statement would miss
+// redemptions committed by whoever held the lock before.
+func getCouponUsage(
+	ctx context.Context,
+	q rowQuerier,
+	code string,
+	customerID int,
+	amount float64,
+	lock bool,
+) (*couponUsage, error) {
+	query := `
+		SELECT c.id, c.code, c.max_redemptions, c.max_per_customer,
+			c.expires_at IS NOT NULL AND c.expires_at <= NOW(),
+			LEAST(COALESCE(ROUND($2::numeric * c.percent_off / 100, 2), c.amount_off), $2::numeric)::float8
+		FROM coupons c
+		WHERE c.code = $1`
+	if lock {
+		query += " FOR UPDATE OF c"
+	}
+
+	var usage couponUsage
+	err := q.QueryRowContext(ctx, query, code, amount).Scan(
+		&usage.couponID, &usage.code, &usage.maxRedemptions, &usage.maxPerCustomer, &usage.expired,
+		&usage.discount,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCouponNotFound
+		}
+		return nil, fmt.Errorf("error querying coupon: %w", err)
+	}
+
+	err = q.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE customer_id = $2)
+		FROM coupon_redemptions
+		WHERE coupon_id = $1`, usage.couponID, customerID).Scan(&usage.redemptions, &usage.customerRedemptions)
+	if err != nil {
+		return nil, fmt.Errorf("error counting coupon redemptions: %w", err)
+	}
+	usage.total = math.Round((amount-usage.discount)*100) / 100
+
+	return &usage, nil
+}
+
+// check returns why the coupon cannot be used, or nil if it can.
+func (u *couponUsage) check() error {
+	switch {
+	case u.expired:
+		return ErrCouponExpired
+	case u.maxRedemptions != nil && u.redemptions >= *u.maxRedemptions:
+		return ErrCouponExhausted
+	case u.maxPerCustomer != nil && u.customerRedemptions >= *u.maxPerCustomer:
+		return ErrCouponExhausted
+	}
+	return nil
+}
+
+// scanCoupon scans couponColumns from row.
+func scanCoupon(row interface{ Scan(dest ...any) error }) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := row.Scan(
+		&coupon.ID, &coupon.Code, &coupon.Description, &coupon.PercentOff, &coupon.AmountOff,
+		&coupon.MaxRedemptions, &coupon.MaxPerCustomer, &coupon.ExpiresAt, &coupon.Redemptions, &coupon.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// customerExportColumns lists the customer_exports columns scanned by
+// scanCustomerExport, in order.
+const customerExportColumns = "id, customer_id, format, status, created_at, completed_at"
+
+// CustomerExportRepository handles database operations for exports of
+// customers' personal data.
+type CustomerExportRepository struct {
+	db *database.DB
+}
+
+// NewCustomerExportRepository creates a new customer export repository.
+func NewCustomerExportRepository(db *database.DB) *CustomerExportRepository {
+	return &CustomerExportRepository{db: db}
+}
+
+// CreateExport stores a pending export of a customer's data in format,
+// deleting their earlier exports in that format.
+func (r *CustomerExportRepository) CreateExport(
+	ctx context.Context,
+	customerID int,
+	format string,
+) (*models.CustomerExport, error) {
+	ctx = database.WithQueryName(ctx, "customer_exports.create")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "customer_exports.create")
+
+	_, err = tx.ExecContext(ctx,
+		"DELETE FROM customer_exports WHERE customer_id = $1 AND format = $2", customerID, format)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting earlier customer exports: %w", err)
+	}
+
+	export, err := scanCustomerExport(tx.QueryRowContext(ctx, `
+		INSERT INTO customer_exports (customer_id, format) VALUES ($1, $2)
+		RETURNING `+customerExportColumns, customerID, format))
+	if err != nil {
+		err = constraintError(err, err)
+		if errors.Is(err, ErrInvalidReference) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error inserting customer export: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing customer export: %w", err)
+	}
+
+	return export, nil
+}
+
+// GetLatestExport retrieves a customer's newest export in format created
+// after since.
+func (r *CustomerExportRepository) GetLatestExport(
+	ctx context.Context,
+	customerID int,
+	format string,
+	since time.Time,
+) (*models.CustomerExport, error) {
+	ctx = database.WithQueryName(ctx, "customer_exports.get_latest")
+	export, err := scanCustomerExport(r.db.QueryRowContext(ctx, `
+		SELECT `+customerExportColumns+` FROM customer_exports
+		WHERE customer_id = $1 AND format = $2 AND created_at > $3
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1`, customerID, format, since))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerExportNotFound
+		}
+		return nil, fmt.Errorf("error querying customer export: %w", err)
+	}
+
+	return export, nil
+}
+
+// GetExport retrieves one of a customer's exports.
+func (r *CustomerExportRepository) GetExport(
+	ctx context.Context,
+	customerID, exportID int,
+) (*models.CustomerExport, error) {
+	ctx = database.WithQueryName(ctx, "customer_exports.get")
+	export, err := scanCustomerExport(r.db.QueryRowContext(ctx,
+		"SELECT "+customerExportColumns+" FROM customer_exports WHERE id = $1 AND customer_id = $2",
+		exportID, customerID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerExportNotFound
+		}
+		return nil, fmt.Errorf("error querying customer export: %w", err)
+	}
+
+	return export, nil
+}
+
+// GetExportFile retrieves the file built for a ready export.
+func (r *CustomerExportRepository) GetExportFile(ctx context.Context, exportID int) ([]byte, error) {
+	ctx = database.WithQueryName(ctx, "customer_exports.get_file")
+	var data []byte
+	err := r.db.QueryRowContext(ctx,
+		"SELECT data FROM customer_exports WHERE id = $1 AND status = $2",
+		exportID, models.CustomerExportStatusReady).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerExportNotFound
+		}
+		return nil, fmt.Errorf("error querying customer export file: %w", err)
+	}
+
+	return data, nil
+}
+
+// CompleteExport stores the file built for an export and marks it ready.
+func (r *CustomerExportRepository) CompleteExport(ctx context.Context, exportID int, data []byte) error {
+	ctx = database.WithQueryName(ctx, "customer_exports.complete")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE customer_exports SET status = $2, data = $3, completed_at = NOW()
+		WHERE id = $1`, exportID, models.CustomerExportStatusReady, data)
+	if err != nil {
+		return fmt.Errorf("error completing customer export: %w", err)
+	}
+	if affected, affectedErr := result.RowsAffected(); affectedErr == nil && affected == 0 {
+		return ErrCustomerExportNotFound
+	}
+
+	return nil
+}
+
+// GetCustomerData retrieves the personal data held about a customer: their
+// profile, the comments they posted, and their whole rental history.
+func (r *CustomerExportRepository) GetCustomerData(ctx context.Context, customerID int) (*models.CustomerData, error) {
+	profile, err := r.getCustomerProfile(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := r.getCustomerComments(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	rentals, err := r.getCustomerRentals(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CustomerData{Profile: *profile, Comments: comments, Rentals: rentals}, nil
+}
+
+// getCustomerProfile retrieves a customer's account with their address.
+func (r *CustomerExportRepository) getCustomerProfile(
+	ctx context.Context,
+	customerID int,
+) (*models.CustomerProfile, error) {
+	ctx = database.WithQueryName(ctx, "customer_exports.profile")
+	var profile models.CustomerProfile
+	customer, err := scanCustomer(r.db.QueryRowContext(ctx, "SELECT "+customerColumns+`,
+			a.address, a.address2, a.district, ci.city, co.country, a.postal_code, a.phone
+		FROM customer c
+		JOIN address a ON a.address_id = c.address_id
+		JOIN city ci ON ci.city_id = a.city_id
+		JOIN country co ON co.country_id = ci.country_id
+		WHERE c.customer_id = $1`, customerID),
+		&profile.Address, &profile.Address2, &profile.District, &profile.City, &profile.Country,
+		&profile.PostalCode, &profile.Phone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error querying customer profile: %w", err)
+	}
+	profile.Customer = *customer
+
+	return &profile, nil
+}
+
+// getCustomerComments retrieves the comments a customer posted, oldest
+// first.
+func (r *CustomerExportRepository) getCustomerComments(
+	ctx context.Context,
+	customerID int,
+) ([]models.CustomerDataComment, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "customer_exports.comments"), `
+		SELECT fc.id, fc.film_id, f.title, fc.parent_id, fc.comment, fc.created_at
+		FROM film_comments fc
+		JOIN film f ON f.film_id = fc.film_id
+		WHERE fc.customer_id = $1
+		ORDER BY fc.created_at, fc.id`, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying customer comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []models.CustomerDataComment{}
+	for rows.Next() {
+		var comment models.CustomerDataComment
+		if scanErr := rows.Scan(
+			&comment.ID, &comment.FilmID, &comment.FilmTitle, &comment.ParentID, &comment.Comment, &comment.CreatedAt,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning customer comment: %w", scanErr)
+		}
+		comments = append(comments, comment)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating customer comments: %w", rowsErr)
+	}
+
+	return comments, nil
+}
+
+// getCustomerRentals retrieves a customer's whole rental history, oldest
+// first.
+func (r *CustomerExportRepository) getCustomerRentals(
+	ctx context.Context,
+	customerID int,
+) ([]models.RentalEvent, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "customer_exports.rentals"), `
+		SELECT r.rental_id, r.inventory_id, i.store_id, f.film_id, f.title, r.customer_id,
+			c.first_name || ' ' || c.last_name, r.rental_date, `+rentalDueAt+`,
+			r.return_date, `+rentalStatus+`
+		FROM rental r
+		JOIN inventory i ON i.inventory_id = r.inventory_id
+		JOIN film f ON f.film_id = i.film_id
+		JOIN customer c ON c.customer_id = r.customer_id
+		WHERE r.customer_id = $1
+		ORDER BY r.rental_date, r.rental_id`, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying customer rentals: %w", err)
+	}
+	defer rows.Close()
+
+	rentals := []models.RentalEvent{}
+	for rows.Next() {
+		var event models.RentalEvent
+		if scanErr := rows.Scan(
+			&event.RentalID, &event.InventoryID, &event.StoreID, &event.FilmID, &event.FilmTitle,
+			&event.CustomerID, &event.CustomerName, &event.RentalDate, &event.DueAt, &event.ReturnDate,
+			&event.Status,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning customer rental: %w", scanErr)
+		}
+		rentals = append(rentals, event)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating customer rentals: %w", rowsErr)
+	}
+
+	return rentals, nil
+}
+
+// scanCustomerExport scans customerExportColumns from row.
+func scanCustomerExport(row interface{ Scan(dest ...any) error }) (*models.CustomerExport, error) {
+	var export models.CustomerExport
+	err := row.Scan(
+		&export.ID, &export.CustomerID, &export.Format, &export.Status, &export.CreatedAt, &export.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// customerListColumns lists the customer_lists columns scanned by
+// scanCustomerList, in order, with the number of entries on the list.
+const customerListColumns = `l.id, l.customer_id, l.name, l.description, l.visibility,
+		(SELECT COUNT(*) FROM customer_list_entries e WHERE e.list_id = l.id), l.created_at, l.updated_at`
+
+// CustomerListRepository handles database operations for customer-curated
+// film lists. Changes are scoped to the owning customer, so another
+// customer's list is reported as not found.
+type CustomerListRepository struct {
+	db *database.DB
+}
+
+// NewCustomerListRepository creates a new customer list repository.
+func NewCustomerListRepository(db *database.DB) *CustomerListRepository {
+	return &CustomerListRepository{db: db}
+}
+
+// CreateList stores a new, empty list for a customer.
+func (r *CustomerListRepository) CreateList(
+	ctx context.Context,
+	customerID int,
+	listReq models.CustomerListRequest,
+) (*models.CustomerList, error) {
+	ctx = database.WithQueryName(ctx, "customer_lists.create")
+	var listID int
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO customer_lists (customer_id, name, description, visibility)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`, customerID, listReq.Name, listReq.Description, listReq.Visibility,
+	).Scan(&listID)
+	if err != nil {
+		err = constraintError(err, err)
+		if errors.Is(err, ErrInvalidReference) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error inserting list: %w", err)
+	}
+
+	return r.GetList(ctx, listID)
+}
+
+// ListCustomerLists retrieves a customer's lists, most recently changed
+// first, with the number of entries on each but not the entries themselves.
+func (r *CustomerListRepository) ListCustomerLists(ctx context.Context, customerID int) ([]models.CustomerList, error) {
+	query := "SELECT " + customerListColumns +
+		" FROM customer_lists l WHERE l.customer_id = $1 ORDER BY l.updated_at DESC, l.id DESC"
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "customer_lists.list"),
+		query, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying lists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := []models.CustomerList{}
+	for rows.Next() {
+		list, scanErr := scanCustomerList(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning list: %w", scanErr)
+		}
+		lists = append(lists, *list)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating lists: %w", rowsErr)
+	}
+
+	return lists, nil
+}
+
+// GetList retrieves a list with its entries of published films in order,
+// whoever owns it.
+func (r *CustomerListRepository) GetList(ctx context.Context, listID int) (*models.CustomerList, error) {
+	ctx = database.WithQueryName(ctx, "customer_lists.get")
+	list, err := scanCustomerList(r.db.QueryRowContext(ctx,
+		"SELECT "+customerListColumns+" FROM customer_lists l WHERE l.id = $1", listID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListNotFound
+		}
+		return nil, fmt.Errorf("error querying list: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "customer_lists.entries"), `
+		SELECT f.film_id, f.title, f.release_year, f.rating, e.position, e.added_at
+		FROM customer_list_entries e
+		JOIN film f ON f.film_id = e.film_id
+		WHERE e.list_id = $1 AND f.status = 'published'
+		ORDER BY e.position`, listID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying list entries: %w", err)
+	}
+	defer rows.Close()
+
+	list.Entries = []models.CustomerListEntry{}
+	for rows.Next() {
+		var entry models.CustomerListEntry
+		if scanErr := rows.Scan(
+			&entry.FilmID, &entry.Title, &entry.ReleaseYear, &entry.Rating, &entry.Position, &entry.AddedAt,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning list entry: %w", scanErr)
+		}
+		list.Entries = append(list.Entries, entry)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating list entries: %w", rowsErr)
+	}
+
+	return list, nil
+}
+
+// UpdateList replaces the name, description, and visibility of a
+// customer's list.
+func (r *CustomerListRepository) UpdateList(
+	ctx context.Context,
+	customerID, listID int,
+	listReq models.CustomerListRequest,
+) (*models.CustomerList, error) {
+	ctx = database.WithQueryName(ctx, "customer_lists.update")
+	var updated int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE customer_lists SET name = $3, description = $4, visibility = $5, updated_at = NOW()
+		WHERE id = $1 AND customer_id = $2
+		RETURNING id`, listID, customerID, listReq.Name, listReq.Description, listReq.Visibility,
+	).Scan(&updated)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListNotFound
+		}
+		return nil, fmt.Errorf("error updating list: %w", err)
+	}
+
+	return r.GetList(ctx, listID)
+}
+
+// DeleteList deletes a customer's list and its entries.
+func (r *CustomerListRepository) DeleteList(ctx context.Context, customerID, listID int) error {
+	ctx = database.WithQueryName(ctx, "customer_lists.delete")
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM customer_lists WHERE id = $1 AND customer_id = $2", listID, customerID)
+	if err != nil {
+		return fmt.Errorf("error deleting list: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error counting deleted lists: %w", err)
+	}
+	if deleted == 0 {
+		return ErrListNotFound
+	}
+
+	return nil
+}
+
+// AddListEntry adds a published film to the end of a customer's list,
+// unless the list is full.
+func (r *CustomerListRepository) AddListEntry(
+	ctx context.Context,
+	customerID, listID, filmID int,
+) (*models.CustomerList, error) {
+	ctx = database.WithQueryName(ctx, "customer_lists.add_entry")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "customer_lists.add_entry")
+
+	if err = lockCustomerList(ctx, tx, customerID, listID); err != nil {
+		return nil, err
+	}
+	if err = checkFilmPublished(ctx, tx, "customer_lists.film_published", filmID); err != nil {
+		return nil, err
+	}
+
+	var entries int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM customer_list_entries WHERE list_id = $1", listID).
+		Scan(&entries)
+	if err != nil {
+		return nil, fmt.Errorf("error counting list entries: %w", err)
+	}
+	if entries >= models.MaxListEntries {
+		return nil, ErrListFull
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO customer_list_entries (list_id, film_id, position)
+		SELECT $1, $2, COALESCE(MAX(position), 0) + 1 FROM customer_list_entries WHERE list_id = $1`,
+		listID, filmID)
+	if err != nil {
+		err = constraintError(err, ErrListEntryExists)
+		if errors.Is(err, ErrInvalidReference) {
+			return nil, ErrFilmNotFound
+		}
+		return nil, fmt.Errorf("error adding list entry: %w", err)
+	}
+
+	if err = commitListChange(ctx, tx, listID); err != nil {
+		return nil, err
+	}
+
+	return r.GetList(ctx, listID)
+}
+
+// RemoveListEntry removes a film from a customer's list, moving the films
+// after it up a place.
+func (r *CustomerListRepository) RemoveListEntry(
+	ctx context.Context,
+	customerID, listID, filmID int,
+) (*models.CustomerList, error) {
+	ctx = database.WithQueryName(ctx, "customer_lists.remove_entry")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "customer_lists.remove_entry")
+
+	if err = lockCustomerList(ctx, tx, customerID, listID); err != nil {
+		return nil, err
+	}
+
+	var position int
+	err = tx.QueryRowContext(ctx,
+		"DELETE FROM customer_list_entries WHERE list_id = $1 AND film_id = $2 RETURNING position",
+		listID, filmID).Scan(&position)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListEntryNotFound
+		}
+		return nil, fmt.Errorf("error removing list entry: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE customer_list_entries SET position = position - 1 WHERE list_id = $1 AND position > $2",
+		listID, position)
+	if err != nil {
+		return nil, fmt.Errorf("error renumbering list entries: %w", err)
+	}
+
+	if err = commitListChange(ctx, tx, listID); err != nil {
+		return nil, err
+	}
+
+	return r.GetList(ctx, listID)
+}
+
+// ReorderListEntries puts the films on a customer's list in the given
+// order, which must name each of them exactly once.
+func (r *CustomerListRepository) ReorderListEntries(
+	ctx context.Context,
+	customerID, listID int,
+	filmIDs []int,
+) (*models.CustomerList, error) {
+	ctx = database.WithQueryName(ctx, "customer_lists.reorder")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "customer_lists.reorder")
+
+	if err = lockCustomerList(ctx, tx, customerID, listID); err != nil {
+		return nil, err
+	}
+
+	ids := make(pq.Int64Array, 0, len(filmIDs))
+	for _, id := range filmIDs {
+		ids = append(ids, int64(id))
+	}
+
+	// The order's films are unique, so it names each entry exactly once when
+	// it is as long as the list and every entry is in it.
+	var matches bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) = cardinality($2::int[]) AND bool_and(film_id = ANY($2::int[])) IS NOT FALSE
+		FROM customer_list_entries
+		WHERE list_id = $1`, listID, ids).Scan(&matches)
+	if err != nil {
+		return nil, fmt.Errorf("error checking list order: %w", err)
+	}
+	if !matches {
+		return nil, ErrListOrderMismatch
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE customer_list_entries e SET position = m.position
+		FROM unnest($2::int[]) WITH ORDINALITY AS m(film_id, position)
+		WHERE e.list_id = $1 AND e.film_id = m.film_id`, listID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error reordering list entries: %w", err)
+	}
+
+	if err = commitListChange(ctx, tx, listID); err != nil {
+		return nil, err
+	}
+
+	return r.GetList(ctx, listID)
+}
+
+// lockCustomerList locks a customer's list for changes to its entries.
+func lockCustomerList(ctx context.Context, tx *sql.Tx, customerID, listID int) error {
+	var locked int
+	err := tx.QueryRowContext(ctx,
+		"SELECT id FROM customer_lists WHERE id = $1 AND customer_id = $2 FOR UPDATE", listID, customerID,
+	).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrListNotFound
+		}
+		return fmt.Errorf("error locking list: %w", err)
+	}
+	return nil
+}
+
+// commitListChange marks a list changed and commits the transaction that
+// changed its entries.
+func commitListChange(ctx context.Context, tx *sql.Tx, listID int) error {
+	if _, err := tx.ExecContext(ctx, "UPDATE customer_lists SET updated_at = NOW() WHERE id = $1", listID); err != nil {
+		return fmt.Errorf("error updating list: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing list change: %w", err)
+	}
+	return nil
+}
+
+// scanCustomerList scans customerListColumns from row.
+func scanCustomerList(row interface{ Scan(dest ...any) error }) (*models.CustomerList, error) {
+	var list models.CustomerList
+	err := row.Scan(
+		&list.ID, &list.CustomerID, &list.Name, &list.Description, &list.Visibility, &list.EntryCount,
+		&list.CreatedAt, &list.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// customerColumns lists the customer columns scanned by scanCustomer, in order.
+const customerColumns = `c.customer_id, c.store_id, c.first_name, c.last_name, COALESCE(c.email, ''),
+		c.address_id, c.activebool, c.create_date`
+
+// CustomerRepository handles database operations for customer accounts.
+type CustomerRepository struct {
+	db *database.DB
+}
+
+// NewCustomerRepository creates a new customer repository.
+func NewCustomerRepository(db *database.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+// CreateCustomer adds a customer and their credentials in one transaction.
+func (r *CustomerRepository) CreateCustomer(
+	ctx context.Context,
+	registerReq models.CustomerRegisterRequest,
+	passwordHash string,
+) (*models.Customer, error) {
+	ctx = database.WithQueryName(ctx, "customers.register")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "customers.register")
+
+	query := `
+		INSERT INTO customer AS c (store_id, first_name, last_name, email, address_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + customerColumns
+
+	customer, err := scanCustomer(tx.QueryRowContext(ctx, query,
+		registerReq.StoreID, registerReq.FirstName, registerReq.LastName, registerReq.Email, registerReq.AddressID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting customer: %w", constraintError(err, ErrEmailTaken))
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO customer_credentials (customer_id, email, password_hash) VALUES ($1, $2, $3)",
+		customer.CustomerID, registerReq.Email, passwordHash,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting customer credentials: %w", constraintError(err, ErrEmailTaken))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing customer registration: %w", err)
+	}
+
+	return customer, nil
+}
+
+// GetCustomerCredentials retrieves a customer and password hash by email.
+func (r *CustomerRepository) GetCustomerCredentials(
+	ctx context.Context,
+	email string,
+) (*models.Customer, string, error) {
+	query := "SELECT " + customerColumns + `, cc.password_hash
+		FROM customer_credentials cc
+		JOIN customer c ON c.customer_id = cc.customer_id
+		WHERE LOWER(cc.email) = LOWER($1)`
+
+	var passwordHash string
+	row := r.db.QueryRowContext(database.WithQueryName(ctx, "customers.credentials"), query, email)
+	customer, err := scanCustomer(row, &passwordHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", ErrCustomerNotFound
+		}
+		return nil, "", fmt.Errorf("error querying customer credentials: %w", err)
+	}
+
+	return customer, passwordHash, nil
+}
+
+// EraseCustomerData erases a customer's personal data in one transaction,
+// recording the erasure in the audit log under the actor in entry. Their
+// name and email on the customer record are replaced; their comments are
+// kept, anonymized and unlinked; their lists, follows, public activity, data
+// exports, and credentials are deleted, and their login sessions revoked.
+// The customer record, rentals, and payments are kept as business records.
+func (r *CustomerRepository) EraseCustomerData(
+	ctx context.Context,
+	customerID int,
+	entry models.AuditEntry,
+) (*models.CustomerErasure, error) {
+	ctx = database.WithQueryName(ctx, "customers.erase")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "customers.erase")
+
+	var erased int
+	err = tx.QueryRowContext(ctx, `
+		UPDATE customer SET first_name = $2, last_name = $3, email = NULL, last_update = NOW()
+		WHERE customer_id = $1
+		RETURNING customer_id`, customerID, models.DeletedFirstName, models.DeletedLastName).Scan(&erased)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error erasing customer profile: %w", err)
+	}
+
+	erasure := &models.CustomerErasure{CustomerID: customerID}
+	steps := []struct {
+		what  string
+		count *int
+		query string
+		args  []any
+	}{
+		{"activity", &erasure.ActivityDeleted, "DELETE FROM customer_activity WHERE customer_id = $1", nil},
+		{"comments", &erasure.CommentsAnonymized,
+			"UPDATE film_comments SET customer_name = $2, customer_id = NULL WHERE customer_id = $1",
+			[]any{models.DeletedUserName}},
+		{"lists", &erasure.ListsDeleted, "DELETE FROM customer_lists WHERE customer_id = $1", nil},
+		{"follows", &erasure.FollowsDeleted,
+			"DELETE FROM customer_follows WHERE follower_id = $1 OR followed_id = $1", nil},
+		{"exports", &erasure.ExportsDeleted, "DELETE FROM customer_exports WHERE customer_id = $1", nil},
+		{"alerts", &erasure.AlertsDeleted, "DELETE FROM film_availability_alerts WHERE customer_id = $1", nil},
+		{"saved searches", &erasure.SearchesDeleted, "DELETE FROM saved_searches WHERE customer_id = $1", nil},
+	}
+	for _, step := range steps {
+		if *step.count, err = execCount(ctx, tx, step.query, append([]any{customerID}, step.args...)...); err != nil {
+			return nil, fmt.Errorf("error erasing customer %s: %w", step.what, err)
+		}
+	}
+
+	credentials, err := execCount(ctx, tx, "DELETE FROM customer_credentials WHERE customer_id = $1", customerID)
+	if err != nil {
+		return nil, fmt.Errorf("error erasing customer credentials: %w", err)
+	}
+	erasure.CredentialsDeleted = credentials > 0
+	if err = revokeSubjectSessions(ctx, tx, auth.RoleCustomer, customerID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.QueryRowContext(ctx, "SELECT NOW()").Scan(&erasure.ErasedAt); err != nil {
+		return nil, fmt.Errorf("error reading erasure time: %w", err)
+	}
+	if entry.Details, err = json.Marshal(erasure); err != nil {
+		return nil, fmt.Errorf("error encoding erasure: %w", err)
+	}
+	entry.Action = models.AuditActionCustomerErased
+	entry.TargetType = models.AuditTargetCustomer
+	entry.TargetID = customerID
+	if err = insertAuditEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing customer erasure: %w", err)
+	}
+
+	return erasure, nil
+}
+
+// MergeCustomers moves everything customer sourceID owns, from comments,
+// rentals and their payments, and lists to checkouts, loyalty points, gift
+// cards, follows, saved searches, alerts, notification preferences, and any
+// shadow ban, to customer targetID in one transaction, recording the merge
+// in the audit log under the actor in entry. The source customer is
+// deactivated rather than deleted, and its login sessions revoked, so it can
+// no longer log in.
+func (r *CustomerRepository) MergeCustomers(
+	ctx context.Context,
+	sourceID, targetID int,
+	entry models.AuditEntry,
+) (*models.CustomerMerge, error) {
+	ctx = database.WithQueryName(ctx, "customers.merge")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "customers.merge")
+
+	// Both customers are locked in ID order, so concurrent merges of the
+	// same pair cannot deadlock.
+	var locked int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT customer_id FROM customer WHERE customer_id IN ($1, $2)
+			ORDER BY customer_id FOR UPDATE
+		) c`, sourceID, targetID).Scan(&locked)
+	if err != nil {
+		return nil, fmt.Errorf("error locking customers: %w", err)
+	}
+	if locked < 2 {
+		return nil, ErrCustomerNotFound
+	}
+
+	// Rows keyed by customer that the target already has an equivalent of
+	// are left with, or dropped from, the source rather than duplicated.
+	merge := &models.CustomerMerge{SourceID: sourceID, TargetID: targetID}
+	steps := []struct {
+		what  string
+		count *int
+		query string
+	}{
+		{"comments", &merge.CommentsMoved, "UPDATE film_comments SET customer_id = $2 WHERE customer_id = $1"},
+		{"rentals", &merge.RentalsMoved, "UPDATE rental SET customer_id = $2 WHERE customer_id = $1"},
+		{"payments", &merge.PaymentsMoved, "UPDATE payment SET customer_id = $2 WHERE customer_id = $1"},
+		{"lists", &merge.ListsMoved, "UPDATE customer_lists SET customer_id = $2 WHERE customer_id = $1"},
+		{"checkouts", &merge.CheckoutsMoved, "UPDATE checkouts SET customer_id = $2 WHERE customer_id = $1"},
+		{"coupon redemptions", nil, "UPDATE coupon_redemptions SET customer_id = $2 WHERE customer_id = $1"},
+		{"loyalty ledger", &merge.LoyaltyEntriesMoved,
+			"UPDATE loyalty_ledger SET customer_id = $2 WHERE customer_id = $1"},
+		{"gift cards", &merge.GiftCardsMoved, "UPDATE gift_cards SET purchaser_id = $2 WHERE purchaser_id = $1"},
+		{"saved searches", &merge.SearchesMoved, "UPDATE saved_searches SET customer_id = $2 WHERE customer_id = $1"},
+		{"activity", nil, "UPDATE customer_activity SET customer_id = $2 WHERE customer_id = $1"},
+		{"guest sessions", nil,
+			"UPDATE guest_sessions SET merged_customer_id = $2 WHERE merged_customer_id = $1"},
+		{"duplicate follows", nil, `
+			DELETE FROM customer_follows f
+			WHERE (f.follower_id = $1 AND (f.followed_id = $2 OR EXISTS (
+					SELECT 1 FROM customer_follows t WHERE t.follower_id = $2 AND t.followed_id = f.followed_id)))
+				OR (f.followed_id = $1 AND (f.follower_id = $2 OR EXISTS (
+					SELECT 1 FROM customer_follows t WHERE t.followed_id = $2 AND t.follower_id = f.follower_id)))`},
+		{"follows", &merge.FollowsMoved, "UPDATE customer_follows SET follower_id = $2 WHERE follower_id = $1"},
+		{"followers", &merge.FollowsMoved, "UPDATE customer_follows SET followed_id = $2 WHERE followed_id = $1"},
+		{"alerts", &merge.AlertsMoved, `
+			UPDATE film_availability_alerts a SET customer_id = $2
+			WHERE a.customer_id = $1 AND (a.notified_at IS NOT NULL OR NOT EXISTS (
+				SELECT 1 FROM film_availability_alerts t
+				WHERE t.customer_id = $2 AND t.film_id = a.film_id AND t.notified_at IS NULL))`},
+		{"notification preferences", nil, `
+			UPDATE notification_preferences p SET customer_id = $2
+			WHERE p.customer_id = $1 AND NOT EXISTS (
+				SELECT 1 FROM notification_preferences t
+				WHERE t.customer_id = $2 AND t.event_type = p.event_type AND t.channel = p.channel)`},
+		{"cart", nil, `
+			UPDATE cart_items c SET customer_id = $2
+			WHERE c.customer_id = $1 AND NOT EXISTS (
+				SELECT 1 FROM cart_items t WHERE t.customer_id = $2 AND t.film_id = c.film_id)`},
+		{"mentions", nil, `
+			UPDATE comment_mentions m SET customer_id = $2
+			WHERE m.customer_id = $1 AND NOT EXISTS (
+				SELECT 1 FROM comment_mentions t WHERE t.customer_id = $2 AND t.comment_id = m.comment_id)`},
+		{"shadow ban", nil, `
+			INSERT INTO customer_shadow_bans (customer_id, banned_at)
+			SELECT $2, banned_at FROM customer_shadow_bans WHERE customer_id = $1
+			ON CONFLICT (customer_id) DO NOTHING`},
+	}
+	for _, step := range steps {
+		moved, stepErr := execCount(ctx, tx, step.query, sourceID, targetID)
+		if stepErr != nil {
+			return nil, fmt.Errorf("error moving customer %s: %w", step.what, stepErr)
+		}
+		if step.count != nil {
+			*step.count += moved
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE customer SET activebool = FALSE WHERE customer_id = $1", sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("error deactivating merged customer: %w", err)
+	}
+	if err = revokeSubjectSessions(ctx, tx, auth.RoleCustomer, sourceID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.QueryRowContext(ctx, "SELECT NOW()").Scan(&merge.MergedAt); err != nil {
+		return nil, fmt.Errorf("error reading merge time: %w", err)
+	}
+	if entry.Details, err = json.Marshal(merge); err != nil {
+		return nil, fmt.Errorf("error encoding merge: %w", err)
+	}
+	entry.Action = models.AuditActionCustomerMerged
+	entry.TargetType = models.AuditTargetCustomer
+	entry.TargetID = sourceID
+	if err = insertAuditEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing customer merge: %w", err)
+	}
+
+	return merge, nil
+}
+
+// execCount runs a statement in tx, returning the number of rows it
+// affected.
+func execCount(ctx context.Context, tx *sql.Tx, query string, args ...any) (int, error) {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+// scanCustomer scans customerColumns, followed by any extra destinations, from row.
+func scanCustomer(row interface{ Scan(dest ...any) error }, extra ...any) (*models.Customer, error) {
+	var customer models.Customer
+	dest := []any{
+		&customer.CustomerID, &customer.StoreID, &customer.FirstName, &customer.LastName, &customer.Email,
+		&customer.AddressID, &customer.Active, &customer.CreateDate,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
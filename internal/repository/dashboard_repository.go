@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// DashboardRepository handles the database queries behind the admin
+// dashboard. Each statistic is a separate query, so they can run
+// concurrently.
+type DashboardRepository struct {
+	db *database.DB
+}
+
+// NewDashboardRepository creates a new dashboard repository.
+func NewDashboardRepository(db *database.DB) *DashboardRepository {
+	return &DashboardRepository{db: db}
+}
+
+// CountFilms counts the films in the catalog.
+func (r *DashboardRepository) CountFilms(ctx context.Context) (int, error) {
+	return r.count(ctx, "dashboard.films", "SELECT COUNT(*) FROM film")
+}
+
+// CountRentalsToday counts the rentals made since the start of the current
+// day.
+func (r *DashboardRepository) CountRentalsToday(ctx context.Context) (int, error) {
+	return r.count(ctx, "dashboard.rentals_today",
+		"SELECT COUNT(*) FROM rental WHERE rental_date >= date_trunc('day', NOW())")
+}
+
+// CountPendingComments counts the top-level comments nobody has replied to
+// yet.
+func (r *DashboardRepository) CountPendingComments(ctx context.Context) (int, error) {
+	return r.count(ctx, "dashboard.pending_comments", `
+		SELECT COUNT(*) FROM film_comments fc
+		WHERE fc.parent_id IS NULL
+			AND NOT EXISTS (SELECT 1 FROM film_comments r WHERE r.parent_id = fc.id)`)
+}
+
+// GetRevenueThisMonth sums the payments taken since the start of the
+// current month, less refunds.
+func (r *DashboardRepository) GetRevenueThisMonth(ctx context.Context) (float64, error) {
+	ctx = database.WithQueryName(ctx, "dashboard.revenue_this_month")
+	var revenue float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0)::float8 FROM payment
+		WHERE payment_date >= date_trunc('month', NOW())`).Scan(&revenue)
+	if err != nil {
+		return 0, fmt.Errorf("error querying revenue: %w", err)
+	}
+	return revenue, nil
+}
+
+// GetCategoryStats aggregates the films in a category, their rentals, and
+// the payments for those rentals. Films only in its subcategories are not
+// counted.
+func (r *DashboardRepository) GetCategoryStats(ctx context.Context, categoryID int) (*models.CategoryStats, error) {
+	ctx = database.WithQueryName(ctx, "dashboard.category_stats")
+	stats := &models.CategoryStats{FilmsByRating: map[string]int{}}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT c.category_id, c.name,
+			(SELECT COUNT(*) FROM film_category fc WHERE fc.category_id = c.category_id),
+			(SELECT COUNT(*)
+				FROM rental r
+				JOIN inventory i ON r.inventory_id = i.inventory_id
+				JOIN film_category fc ON i.film_id = fc.film_id
+				WHERE fc.category_id = c.category_id),
+			(SELECT COALESCE(SUM(p.amount), 0)::float8
+				FROM payment p
+				JOIN rental r ON p.rental_id = r.rental_id
+				JOIN inventory i ON r.inventory_id = i.inventory_id
+				JOIN film_category fc ON i.film_id = fc.film_id
+				WHERE fc.category_id = c.category_id)
+		FROM category c
+		WHERE c.category_id = $1`, categoryID).
+		Scan(&stats.CategoryID, &stats.Name, &stats.FilmsCount, &stats.RentalsCount, &stats.Revenue)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("error querying category stats: %w", err)
+	}
+
+	ratingsCtx := database.WithQueryName(ctx, "dashboard.category_ratings")
+	rows, err := r.db.QueryContext(ratingsCtx, `
+		SELECT f.rating::text, COUNT(*)
+		FROM film f
+		JOIN film_category fc ON f.film_id = fc.film_id
+		WHERE fc.category_id = $1
+		GROUP BY f.rating`, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying category ratings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rating sql.NullString
+		var count int
+		if scanErr := rows.Scan(&rating, &count); scanErr != nil {
+			return nil, fmt.Errorf("error scanning category rating: %w", scanErr)
+		}
+		if rating.Valid {
+			stats.FilmsByRating[rating.String] = count
+		}
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating category ratings: %w", rowsErr)
+	}
+
+	return stats, nil
+}
+
+// count runs a query returning a single count.
+func (r *DashboardRepository) count(ctx context.Context, queryName, query string) (int, error) {
+	var n int
+	if err := r.db.QueryRowContext(database.WithQueryName(ctx, queryName), query).Scan(&n); err != nil {
+		return 0, fmt.Errorf("error querying %s: %w", queryName, err)
+	}
+	return n, nil
+}
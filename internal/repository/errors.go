@@ -1,6 +1,312 @@
 package repository
 
-import "errors"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+)
 
 // ErrFilmNotFound is returned when a film is not found in the database.
-var ErrFilmNotFound = errors.New("film not found")
+var ErrFilmNotFound = apperrors.New(apperrors.NotFound, "film not found")
+
+// ErrFilmNotDraft is returned when scheduling a film that is not a draft.
+var ErrFilmNotDraft = apperrors.New(apperrors.Conflict, "only draft films can be scheduled")
+
+// ErrFilmNotArchived is returned when restoring a film that is not archived.
+var ErrFilmNotArchived = apperrors.New(apperrors.Conflict, "only archived films can be restored")
+
+// ErrFilmModified is returned when updating a film that has changed since
+// the version the update was based on.
+var ErrFilmModified = apperrors.New(apperrors.Stale, "film has been modified since it was fetched")
+
+// ErrCategoryNotFound is returned when a category does not exist.
+var ErrCategoryNotFound = apperrors.New(apperrors.NotFound, "category not found")
+
+// ErrCommentNotFound is returned when a comment is not found in the database.
+var ErrCommentNotFound = apperrors.New(apperrors.NotFound, "comment not found")
+
+// ErrCommentsLocked is returned when commenting on a film whose comments an
+// admin has locked.
+var ErrCommentsLocked = apperrors.New(apperrors.Conflict, "comments are locked for this film")
+
+// ErrCommentSummaryNotFound is returned when a film's comment summary has
+// not been computed yet.
+var ErrCommentSummaryNotFound = apperrors.New(apperrors.NotFound, "comment summary not computed yet")
+
+// ErrStaffNotFound is returned when a staff member is not found in the database.
+var ErrStaffNotFound = apperrors.New(apperrors.NotFound, "staff member not found")
+
+// ErrUsernameTaken is returned when creating an account with a username that
+// is already in use.
+var ErrUsernameTaken = apperrors.New(apperrors.Conflict, "username already taken")
+
+// ErrCustomerNotFound is returned when a customer is not found in the database.
+var ErrCustomerNotFound = apperrors.New(apperrors.NotFound, "customer not found")
+
+// ErrEmailTaken is returned when registering an email that already has an
+// account.
+var ErrEmailTaken = apperrors.New(apperrors.Conflict, "email already registered")
+
+// ErrJobNotFound is returned when a background job is not found in the database.
+var ErrJobNotFound = apperrors.New(apperrors.NotFound, "job not found")
+
+// ErrJobNotDead is returned when retrying a background job that has not
+// failed permanently.
+var ErrJobNotDead = apperrors.New(apperrors.Conflict, "job is not dead")
+
+// ErrWebhookNotFound is returned when a webhook subscription is not found in
+// the database.
+var ErrWebhookNotFound = apperrors.New(apperrors.NotFound, "webhook subscription not found")
+
+// ErrWebhookDeliveryNotFound is returned when a webhook delivery is not found
+// in the database.
+var ErrWebhookDeliveryNotFound = apperrors.New(apperrors.NotFound, "webhook delivery not found")
+
+// ErrWebhookDeliveryNotFailed is returned when replaying a webhook delivery
+// that has not failed.
+var ErrWebhookDeliveryNotFailed = apperrors.New(apperrors.Conflict, "webhook delivery has not failed")
+
+// ErrAPIKeyNotFound is returned when an API key is not found in the database,
+// or has been revoked.
+var ErrAPIKeyNotFound = apperrors.New(apperrors.NotFound, "API key not found")
+
+// ErrInventoryNotFound is returned when an inventory copy is not found in the
+// database.
+var ErrInventoryNotFound = apperrors.New(apperrors.NotFound, "inventory copy not found")
+
+// ErrInventoryRented is returned when retiring a copy that is rented out and
+// not yet considered lost, or when transferring a copy that is rented out.
+var ErrInventoryRented = apperrors.New(apperrors.Conflict, "inventory copy is rented out")
+
+// ErrInventoryRetired is returned when transferring a retired copy.
+var ErrInventoryRetired = apperrors.New(apperrors.Conflict, "inventory copy is retired")
+
+// ErrInventoryAtStore is returned when transferring a copy to the store that
+// already holds it.
+var ErrInventoryAtStore = apperrors.New(apperrors.Conflict, "inventory copy is already at that store")
+
+// ErrPricingRuleNotFound is returned when a pricing rule is not found in the
+// database.
+var ErrPricingRuleNotFound = apperrors.New(apperrors.NotFound, "pricing rule not found")
+
+// ErrCouponNotFound is returned when a coupon code is not found in the
+// database.
+var ErrCouponNotFound = apperrors.New(apperrors.NotFound, "coupon not found")
+
+// ErrCouponCodeTaken is returned when creating a coupon with a code that is
+// already in use.
+var ErrCouponCodeTaken = apperrors.New(apperrors.Conflict, "coupon code already taken")
+
+// ErrCouponExpired is returned when using a coupon past its expiry.
+var ErrCouponExpired = apperrors.New(apperrors.Conflict, "coupon has expired")
+
+// ErrCouponExhausted is returned when using a coupon that has reached its
+// redemption limit, in total or for the customer.
+var ErrCouponExhausted = apperrors.New(apperrors.Conflict, "coupon redemption limit reached")
+
+// ErrCartItemNotFound is returned when removing a film that is not in the
+// customer's cart.
+var ErrCartItemNotFound = apperrors.New(apperrors.NotFound, "film not in cart")
+
+// ErrCartEmpty is returned when checking out an empty cart.
+var ErrCartEmpty = apperrors.New(apperrors.Invalid, "cart is empty")
+
+// ErrCartChanged is returned when a cart's films change while it is being
+// checked out.
+var ErrCartChanged = apperrors.New(apperrors.Conflict, "cart changed during checkout")
+
+// ErrFilmUnavailable is returned when checking out a film with no copy in
+// stock at the customer's store.
+var ErrFilmUnavailable = apperrors.New(apperrors.Conflict, "film not available")
+
+// ErrRentalNotFound is returned when a rental is not found in the database.
+var ErrRentalNotFound = apperrors.New(apperrors.NotFound, "rental not found")
+
+// ErrRentalReturned is returned when returning a rental that has already
+// come back.
+var ErrRentalReturned = apperrors.New(apperrors.Conflict, "rental already returned")
+
+// ErrCheckoutNotFound is returned when a checkout is not found in the
+// database.
+var ErrCheckoutNotFound = apperrors.New(apperrors.NotFound, "checkout not found")
+
+// ErrPaymentNotFound is returned when a checkout payment is not found in the
+// database.
+var ErrPaymentNotFound = apperrors.New(apperrors.NotFound, "payment not found")
+
+// ErrRefundNotFound is returned when a payment refund is not found in the
+// database.
+var ErrRefundNotFound = apperrors.New(apperrors.NotFound, "refund not found")
+
+// ErrPaymentNotRefundable is returned when refunding a payment that has not
+// succeeded.
+var ErrPaymentNotRefundable = apperrors.New(apperrors.Conflict, "payment has not succeeded")
+
+// ErrRefundExceedsPayment is returned when a refund is larger than what is
+// left of the payment.
+var ErrRefundExceedsPayment = apperrors.New(apperrors.Conflict, "refund exceeds the unrefunded amount")
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is sent again
+// with a different request.
+var ErrIdempotencyKeyReused = apperrors.New(apperrors.Conflict, "idempotency key already used for a different request")
+
+// ErrInsufficientPoints is returned when redeeming more loyalty points than
+// a customer has.
+var ErrInsufficientPoints = apperrors.New(apperrors.Conflict, "not enough loyalty points")
+
+// ErrGiftCardNotFound is returned when a gift card code is not found in the
+// database, or names a card whose purchase failed.
+var ErrGiftCardNotFound = apperrors.New(apperrors.NotFound, "gift card not found")
+
+// ErrGiftCardInactive is returned when spending a gift card whose purchase
+// has not been paid for yet.
+var ErrGiftCardInactive = apperrors.New(apperrors.Conflict, "gift card not active")
+
+// ErrGiftCardEmpty is returned when spending a gift card with no balance
+// left.
+var ErrGiftCardEmpty = apperrors.New(apperrors.Conflict, "gift card has no balance")
+
+// ErrCollectionNotFound is returned when a collection is not found in the
+// database.
+var ErrCollectionNotFound = apperrors.New(apperrors.NotFound, "collection not found")
+
+// ErrCollectionNameTaken is returned when creating or renaming a collection
+// to a name another collection has.
+var ErrCollectionNameTaken = apperrors.New(apperrors.Conflict, "collection name already taken")
+
+// ErrFilmTagNotFound is returned when removing a tag a film does not carry.
+var ErrFilmTagNotFound = apperrors.New(apperrors.NotFound, "film tag not found")
+
+// ErrListNotFound is returned when a customer list is not found, or is not
+// visible to the caller.
+var ErrListNotFound = apperrors.New(apperrors.NotFound, "list not found")
+
+// ErrListEntryExists is returned when adding a film already on a customer
+// list.
+var ErrListEntryExists = apperrors.New(apperrors.Conflict, "film already on list")
+
+// ErrListFull is returned when adding a film to a customer list that
+// already holds models.MaxListEntries films.
+var ErrListFull = apperrors.New(apperrors.Conflict, "list is full")
+
+// ErrListEntryNotFound is returned when removing a film not on a customer
+// list.
+var ErrListEntryNotFound = apperrors.New(apperrors.NotFound, "film not on list")
+
+// ErrListOrderMismatch is returned when reordering a customer list with
+// films other than exactly those on it.
+var ErrListOrderMismatch = apperrors.New(apperrors.Invalid, "order must name every film on the list exactly once")
+
+// ErrGuestSessionNotFound is returned when a guest session is not found in
+// the database.
+var ErrGuestSessionNotFound = apperrors.New(apperrors.NotFound, "guest session not found")
+
+// ErrGuestSessionMerged is returned when using a guest session that has
+// already been merged into a customer account.
+var ErrGuestSessionMerged = apperrors.New(apperrors.Conflict, "guest session already merged into an account")
+
+// ErrSessionNotFound is returned when a login session is not found, or has
+// expired or been revoked.
+var ErrSessionNotFound = apperrors.New(apperrors.NotFound, "session not found")
+
+// ErrRefreshTokenReused is returned when refreshing with a refresh token
+// that has already been exchanged; its session is revoked.
+var ErrRefreshTokenReused = apperrors.New(apperrors.Conflict, "refresh token already used")
+
+// ErrTwoFactorNotFound is returned when a staff member has not enrolled in
+// two-factor authentication.
+var ErrTwoFactorNotFound = apperrors.New(apperrors.NotFound, "two-factor authentication not enrolled")
+
+// ErrTwoFactorEnabled is returned when enrolling a staff member who already
+// has two-factor authentication enabled.
+var ErrTwoFactorEnabled = apperrors.New(apperrors.Conflict, "two-factor authentication already enabled")
+
+// ErrNotFollowing is returned when unfollowing a customer who is not
+// followed.
+var ErrNotFollowing = apperrors.New(apperrors.NotFound, "not following customer")
+
+// ErrSavedSearchNotFound is returned when a customer has no such saved
+// search.
+var ErrSavedSearchNotFound = apperrors.New(apperrors.NotFound, "saved search not found")
+
+// ErrSavedSearchLimit is returned when saving a search for a customer who
+// already has models.MaxSavedSearches.
+var ErrSavedSearchLimit = apperrors.New(apperrors.Conflict, "too many saved searches")
+
+// ErrShortLinkNotFound is returned when no short link has a code.
+var ErrShortLinkNotFound = apperrors.New(apperrors.NotFound, "short link not found")
+
+// ErrShortLinkCodeTaken is returned when a new short link's code is already
+// in use.
+var ErrShortLinkCodeTaken = apperrors.New(apperrors.Conflict, "short link code already taken")
+
+// ErrCustomerExportNotFound is returned when a customer has no such export.
+var ErrCustomerExportNotFound = apperrors.New(apperrors.NotFound, "customer export not found")
+
+// ErrInvalidReference is returned when a write names a related row, such as a
+// store or address, that does not exist.
+var ErrInvalidReference = apperrors.New(apperrors.Invalid, "referenced record does not exist")
+
+// IsUnavailable reports whether err means the database could not be reached,
+// as opposed to a problem with the query itself, or is marked
+// apperrors.Unavailable.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apperrors.KindOf(err) == apperrors.Unavailable {
+		return true
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Class 08 is "connection exception"; 57P01-57P03 cover server shutdown
+	// and startup.
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		code := string(pqErr.Code)
+		return strings.HasPrefix(code, "08") || code == "57P01" || code == "57P02" || code == "57P03"
+	}
+
+	return false
+}
+
+// Postgres error codes mapped to repository errors.
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+)
+
+// constraintError maps unique violations in err to duplicate and foreign key
+// violations to ErrInvalidReference, returning other errors unchanged.
+func constraintError(err, duplicate error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch string(pqErr.Code) {
+	case pqUniqueViolation:
+		return duplicate
+	case pqForeignKeyViolation:
+		return ErrInvalidReference
+	default:
+		return err
+	}
+}
@@ -6,129 +6,321 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/rxbenefits/go-hw/internal/database"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
 )
 
+// filmColumns lists the film columns scanned by scanFilm, in order.
+const filmColumns = `f.film_id, f.title, f.description, f.release_year,
+		f.language_id, f.rental_duration, f.rental_rate, f.length,
+		f.replacement_cost, f.rating, f.last_update, f.special_features,
+		f.status, f.publish_at`
+
+// categoryFilterClause matches films in at least one category satisfying the
+// formatted condition on c.name.
+const categoryFilterClause = `
+		AND EXISTS (
+			SELECT 1
+			FROM film_category fc
+			JOIN category c ON fc.category_id = c.category_id
+			WHERE fc.film_id = f.film_id
+			  AND %s
+		)`
+
+// subcategoryFilterClause matches films in at least one category satisfying
+// the formatted condition on c.name or in any category beneath one. UNION
+// stops the walk should parents ever form a loop.
+const subcategoryFilterClause = `
+		AND f.film_id IN (
+			SELECT fc.film_id
+			FROM film_category fc
+			WHERE fc.category_id IN (
+				WITH RECURSIVE matched AS (
+					SELECT c.category_id FROM category c WHERE %s
+					UNION
+					SELECT sub.category_id
+					FROM category sub
+					JOIN matched m ON sub.parent_id = m.category_id
+				)
+				SELECT category_id FROM matched
+			)
+		)`
+
+// tagFilterClause matches films with at least one tag satisfying the
+// formatted condition on ft.tag.
+const tagFilterClause = `
+		AND EXISTS (
+			SELECT 1
+			FROM film_tags ft
+			WHERE ft.film_id = f.film_id
+			  AND %s
+		)`
+
+// actorFilterClause matches films with at least one actor whose full name
+// contains the bound pattern.
+const actorFilterClause = `
+		AND EXISTS (
+			SELECT 1
+			FROM film_actor fa
+			JOIN actor a ON fa.actor_id = a.actor_id
+			WHERE fa.film_id = f.film_id
+			  AND (a.first_name || ' ' || a.last_name) ILIKE $%d
+		)`
+
+// languageFilterClause matches films in the language whose name equals the
+// bound lower-cased name. Names are blank-padded char(20), so are trimmed.
+const languageFilterClause = `
+		AND EXISTS (
+			SELECT 1
+			FROM language l
+			WHERE l.language_id = f.language_id
+			  AND LOWER(TRIM(l.name)) = $%d
+		)`
+
+// storeFilterClause matches films with at least one stocked inventory copy
+// at the bound store.
+const storeFilterClause = `
+		AND EXISTS (
+			SELECT 1
+			FROM inventory i
+			WHERE i.film_id = f.film_id
+			  AND i.store_id = $%d
+			  AND i.retired_at IS NULL
+		)`
+
 // FilmRepository handles database operations for films.
 type FilmRepository struct {
-	db *database.DB
+	db         database.Querier
+	pagination pagination.Limits
 }
 
-// NewFilmRepository creates a new film repository.
-func NewFilmRepository(db *database.DB) *FilmRepository {
-	return &FilmRepository{db: db}
+// NewFilmRepository creates a new film repository running its queries on
+// db. Listings without a page size use pageSizes' default.
+func NewFilmRepository(db database.Querier, pageSizes pagination.Limits) *FilmRepository {
+	return &FilmRepository{db: db, pagination: pageSizes}
 }
 
-// GetFilms retrieves films with optional filters.
-func (r *FilmRepository) GetFilms(filters models.FilmFilters) (*models.FilmListResponse, error) {
-	r.normalizePagination(&filters)
+// GetFilms retrieves films with optional filters. The page and the total
+// number of matches are fetched in a single round trip using a window count.
+func (r *FilmRepository) GetFilms(
+	ctx context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
+	r.pagination.Normalize(&filters.Params)
+
+	useEstimate := filters.CountMode == models.CountEstimate && !hasFilmFilters(filters)
+	withCount := filters.CountMode != models.CountNone && !useEstimate
 
-	query, args := r.buildFilmsQuery(filters)
-	films, err := r.executeFilmsQuery(query, args)
+	query, args := r.buildFilmsQuery(filters, withCount)
+	films, total, err := r.executeFilmsQuery(ctx, query, args, withCount)
 	if err != nil {
 		return nil, err
 	}
 
-	total, err := r.getFilmsCount(filters)
-	if err != nil {
-		return nil, err
+	response := pagination.New(films, 0, filters.Params)
+
+	switch {
+	case filters.CountMode == models.CountNone:
+		response.TotalMode = models.CountNone
+	case useEstimate:
+		estimate, estimateErr := r.estimateFilmsCount(ctx)
+		if estimateErr != nil {
+			return nil, estimateErr
+		}
+		response.Total = estimate
+		response.TotalMode = models.CountEstimate
+	case len(films) == 0 && filters.Page > 1:
+		// A page past the end returns no rows to carry the window count.
+		total, err = r.getFilmsCount(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+		response.Total = total
+	default:
+		response.Total = total
 	}
 
-	return &models.FilmListResponse{
-		Films: films,
-		Total: total,
-		Page:  filters.Page,
-		Limit: filters.Limit,
-	}, nil
+	return response, nil
+}
+
+// hasFilmFilters reports whether any row-restricting filter is set. Table
+// statistics can only estimate the unfiltered total, which for the public
+// listing includes the few films that are not published.
+func hasFilmFilters(filters models.FilmFilters) bool {
+	return filters.Title != "" || len(filters.Ratings) > 0 ||
+		len(filters.Categories) > 0 || len(filters.Tags) > 0 || filters.Actor != "" ||
+		filters.Language != "" || filters.StoreID != 0 || len(filters.Statuses) > 0
 }
 
-// normalizePagination sets default values for pagination parameters.
-func (r *FilmRepository) normalizePagination(filters *models.FilmFilters) {
-	if filters.Limit <= 0 {
-		filters.Limit = 10
+// estimateFilmsCount returns the planner's row estimate for the film table
+// from pg_class, avoiding a full COUNT scan.
+func (r *FilmRepository) estimateFilmsCount(ctx context.Context) (int, error) {
+	var estimate float64
+	err := r.db.QueryRowContext(database.WithQueryName(ctx, "films.estimate_count"),
+		"SELECT reltuples FROM pg_class WHERE oid = 'film'::regclass").Scan(&estimate)
+	if err != nil {
+		return 0, fmt.Errorf("error estimating film count: %w", err)
 	}
-	if filters.Page <= 0 {
-		filters.Page = 1
+
+	// reltuples is -1 for tables that have never been analyzed.
+	if estimate < 0 {
+		return r.getFilmsCount(ctx, models.FilmFilters{})
 	}
-}
 
-// buildFilmsQuery constructs the SQL query and arguments for fetching films.
-func (r *FilmRepository) buildFilmsQuery(filters models.FilmFilters) (string, []interface{}) {
-	query := `
-		SELECT DISTINCT f.film_id, f.title, f.description, f.release_year, 
-		       f.language_id, f.rental_duration, f.rental_rate, f.length, 
-		       f.replacement_cost, f.rating, f.last_update, f.special_features
-		FROM film f
-		LEFT JOIN film_category fc ON f.film_id = fc.film_id
-		LEFT JOIN category c ON fc.category_id = c.category_id
-		WHERE 1=1
-	`
+	return int(estimate), nil
+}
 
+// buildFilmsWhere builds the WHERE clause shared by the listing and count
+// queries. Filters on related tables use EXISTS so each film appears once.
+func (r *FilmRepository) buildFilmsWhere(filters models.FilmFilters) (string, []interface{}) {
+	where := " WHERE 1=1"
 	args := []interface{}{}
-	argCount := 0
 
 	if filters.Title != "" {
-		argCount++
-		query += fmt.Sprintf(" AND f.title ILIKE $%d", argCount)
 		args = append(args, "%"+filters.Title+"%")
+		where += fmt.Sprintf(" AND f.title ILIKE $%d", len(args))
+	}
+
+	if len(filters.Ratings) > 0 {
+		var clause string
+		clause, args = inClause("f.rating::text", filters.Ratings, args)
+		where += " AND " + clause
+	}
+
+	if len(filters.Categories) > 0 {
+		var clause string
+		clause, args = inClause("LOWER(c.name)", lowerAll(filters.Categories), args)
+		if filters.IncludeSubcategories {
+			where += fmt.Sprintf(subcategoryFilterClause, clause)
+		} else {
+			where += fmt.Sprintf(categoryFilterClause, clause)
+		}
+	}
+
+	if len(filters.Tags) > 0 {
+		var clause string
+		clause, args = inClause("ft.tag", lowerAll(filters.Tags), args)
+		where += fmt.Sprintf(tagFilterClause, clause)
+	}
+
+	if filters.Actor != "" {
+		args = append(args, "%"+filters.Actor+"%")
+		where += fmt.Sprintf(actorFilterClause, len(args))
+	}
+
+	if filters.Language != "" {
+		args = append(args, strings.ToLower(strings.TrimSpace(filters.Language)))
+		where += fmt.Sprintf(languageFilterClause, len(args))
+	}
+
+	if filters.StoreID != 0 {
+		args = append(args, filters.StoreID)
+		where += fmt.Sprintf(storeFilterClause, len(args))
 	}
 
-	if filters.Rating != "" {
-		argCount++
-		query += fmt.Sprintf(" AND f.rating = $%d", argCount)
-		args = append(args, filters.Rating)
+	statuses := filters.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{models.FilmPublished}
 	}
+	var statusClause string
+	statusClause, args = inClause("f.status", statuses, args)
+	where += " AND " + statusClause
+
+	return where, args
+}
 
-	if filters.Category != "" {
-		argCount++
-		query += fmt.Sprintf(" AND c.name ILIKE $%d", argCount)
-		args = append(args, "%"+filters.Category+"%")
+// buildFilmsQuery constructs the SQL query and arguments for fetching a page
+// of films, optionally including the total match count on every row.
+func (r *FilmRepository) buildFilmsQuery(filters models.FilmFilters, withCount bool) (string, []interface{}) {
+	columns := filmColumns
+	if withCount {
+		columns += ", COUNT(*) OVER() AS total_count"
 	}
 
-	offset := (filters.Page - 1) * filters.Limit
-	argCount++
-	query += fmt.Sprintf(" ORDER BY f.title LIMIT $%d OFFSET $%d", argCount, argCount+1)
-	args = append(args, filters.Limit, offset)
+	where, args := r.buildFilmsWhere(filters)
+	query := "SELECT " + columns + " FROM film f" + where
+
+	query += fmt.Sprintf(" ORDER BY f.title LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filters.Limit, filters.Offset())
 
 	return query, args
 }
 
-// executeFilmsQuery executes the query and scans the results into film objects.
-func (r *FilmRepository) executeFilmsQuery(query string, args []interface{}) ([]models.Film, error) {
-	rows, err := r.db.QueryContext(context.Background(), query, args...)
+// inClause builds "column IN ($n, ...)" for the given values, numbering the
+// placeholders after the arguments already collected, and returns the clause
+// together with the extended argument list.
+func inClause(column string, values []string, args []interface{}) (string, []interface{}) {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		args = append(args, value)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")), args
+}
+
+// lowerAll returns a lower-cased copy of values for case-insensitive matching.
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
+
+// executeFilmsQuery executes the query and scans the results into film
+// objects. When withCount is set, the trailing window count column is read
+// into the returned total.
+func (r *FilmRepository) executeFilmsQuery(
+	ctx context.Context,
+	query string,
+	args []interface{},
+	withCount bool,
+) ([]models.Film, int, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "films.list"), query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error querying films: %w", err)
+		return nil, 0, fmt.Errorf("error querying films: %w", err)
 	}
 	defer rows.Close()
 
 	var films []models.Film
+	var total int
 	for rows.Next() {
-		film, scanErr := r.scanFilm(rows)
+		var extra []any
+		if withCount {
+			extra = append(extra, &total)
+		}
+		film, scanErr := r.scanFilm(ctx, rows, extra...)
 		if scanErr != nil {
-			return nil, scanErr
+			return nil, 0, scanErr
 		}
 		films = append(films, film)
 	}
 
 	if rowsErr := rows.Err(); rowsErr != nil {
-		return nil, fmt.Errorf("error iterating films: %w", rowsErr)
+		return nil, 0, fmt.Errorf("error iterating films: %w", rowsErr)
 	}
 
-	return films, nil
+	return films, total, nil
 }
 
-// scanFilm scans a single film row and enriches it with categories and actors.
-func (r *FilmRepository) scanFilm(rows *sql.Rows) (models.Film, error) {
+// scanFilm scans a single film row and enriches it with categories, actors,
+// and collections.
+// Any extra destinations are scanned from the columns following the film's.
+func (r *FilmRepository) scanFilm(ctx context.Context, rows *sql.Rows, extra ...any) (models.Film, error) {
 	var film models.Film
 	var specialFeatures sql.NullString
 
-	scanErr := rows.Scan(
+	dest := []any{
 		&film.FilmID, &film.Title, &film.Description, &film.ReleaseYear,
 		&film.LanguageID, &film.RentalDuration, &film.RentalRate, &film.Length,
 		&film.ReplacementCost, &film.Rating, &film.LastUpdate, &specialFeatures,
-	)
+		&film.Status, &film.PublishAt,
+	}
+	scanErr := rows.Scan(append(dest, extra...)...)
 	if scanErr != nil {
 		return models.Film{}, fmt.Errorf("error scanning film: %w", scanErr)
 	}
@@ -140,54 +332,42 @@ func (r *FilmRepository) scanFilm(rows *sql.Rows) (models.Film, error) {
 		}
 	}
 
-	categories, catErr := r.getFilmCategories(film.FilmID)
+	categories, catErr := r.getFilmCategories(ctx, film.FilmID)
 	if catErr != nil {
 		return models.Film{}, catErr
 	}
 	film.Categories = categories
 
-	actors, actorErr := r.getFilmActors(film.FilmID)
+	actors, actorErr := r.getFilmActors(ctx, film.FilmID)
 	if actorErr != nil {
 		return models.Film{}, actorErr
 	}
 	film.Actors = actors
 
-	return film, nil
-}
-
-// getFilmsCount gets the total count of films matching the filters.
-func (r *FilmRepository) getFilmsCount(filters models.FilmFilters) (int, error) {
-	countQuery := `
-		SELECT COUNT(DISTINCT f.film_id)
-		FROM film f
-		LEFT JOIN film_category fc ON f.film_id = fc.film_id
-		LEFT JOIN category c ON fc.category_id = c.category_id
-		WHERE 1=1
-	`
-
-	countArgs := []interface{}{}
-	argCount := 0
-
-	if filters.Title != "" {
-		argCount++
-		countQuery += fmt.Sprintf(" AND f.title ILIKE $%d", argCount)
-		countArgs = append(countArgs, "%"+filters.Title+"%")
+	collections, collectionErr := r.getFilmCollections(ctx, film.FilmID)
+	if collectionErr != nil {
+		return models.Film{}, collectionErr
 	}
+	film.Collections = collections
 
-	if filters.Rating != "" {
-		argCount++
-		countQuery += fmt.Sprintf(" AND f.rating = $%d", argCount)
-		countArgs = append(countArgs, filters.Rating)
+	tags, tagErr := r.getFilmTags(ctx, film.FilmID)
+	if tagErr != nil {
+		return models.Film{}, tagErr
 	}
+	film.Tags = tags
 
-	if filters.Category != "" {
-		argCount++
-		countQuery += fmt.Sprintf(" AND c.name ILIKE $%d", argCount)
-		countArgs = append(countArgs, "%"+filters.Category+"%")
-	}
+	return film, nil
+}
+
+// getFilmsCount gets the total count of films matching the filters. GetFilms
+// only needs it when the requested page is past the last match.
+func (r *FilmRepository) getFilmsCount(ctx context.Context, filters models.FilmFilters) (int, error) {
+	where, args := r.buildFilmsWhere(filters)
+	countQuery := "SELECT COUNT(*) FROM film f" + where
 
 	var total int
-	err := r.db.QueryRowContext(context.Background(), countQuery, countArgs...).Scan(&total)
+	countCtx := database.WithQueryName(ctx, "films.count")
+	err := r.db.QueryRowContext(countCtx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return 0, fmt.Errorf("error counting films: %w", err)
 	}
@@ -195,12 +375,12 @@ func (r *FilmRepository) getFilmsCount(filters models.FilmFilters) (int, error)
 	return total, nil
 }
 
-// GetFilmByID retrieves a single film by ID.
-func (r *FilmRepository) GetFilmByID(filmID int) (*models.Film, error) {
+// GetFilmByID retrieves a single film by ID, whatever its status.
+func (r *FilmRepository) GetFilmByID(ctx context.Context, filmID int) (*models.Film, error) {
 	query := `
 		SELECT film_id, title, description, release_year, language_id, 
 		       rental_duration, rental_rate, length, replacement_cost, 
-		       rating, last_update, special_features
+		       rating, last_update, special_features, status, publish_at
 		FROM film 
 		WHERE film_id = $1
 	`
@@ -208,10 +388,11 @@ func (r *FilmRepository) GetFilmByID(filmID int) (*models.Film, error) {
 	var film models.Film
 	var specialFeatures sql.NullString
 
-	err := r.db.QueryRowContext(context.Background(), query, filmID).Scan(
+	err := r.db.QueryRowContext(database.WithQueryName(ctx, "films.get"), query, filmID).Scan(
 		&film.FilmID, &film.Title, &film.Description, &film.ReleaseYear,
 		&film.LanguageID, &film.RentalDuration, &film.RentalRate, &film.Length,
 		&film.ReplacementCost, &film.Rating, &film.LastUpdate, &specialFeatures,
+		&film.Status, &film.PublishAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -227,23 +408,236 @@ func (r *FilmRepository) GetFilmByID(filmID int) (*models.Film, error) {
 		}
 	}
 
-	categories, err := r.getFilmCategories(filmID)
+	categories, err := r.getFilmCategories(ctx, filmID)
 	if err != nil {
 		return nil, err
 	}
 	film.Categories = categories
 
-	actors, err := r.getFilmActors(filmID)
+	actors, err := r.getFilmActors(ctx, filmID)
 	if err != nil {
 		return nil, err
 	}
 	film.Actors = actors
 
+	collections, err := r.getFilmCollections(ctx, filmID)
+	if err != nil {
+		return nil, err
+	}
+	film.Collections = collections
+
+	tags, err := r.getFilmTags(ctx, filmID)
+	if err != nil {
+		return nil, err
+	}
+	film.Tags = tags
+
 	return &film, nil
 }
 
+// StreamFilms calls fn for each published film with an ID above
+// afterFilmID, in ID order, limited to films stocked at storeID when it is
+// non-zero. Categories
+// and actors are aggregated in the same query so the whole catalog is read in
+// a single pass. Iteration stops at the first error returned by fn.
+func (r *FilmRepository) StreamFilms(ctx context.Context, storeID, afterFilmID int, fn func(models.Film) error) error {
+	query := `
+		SELECT ` + filmColumns + `,
+		       ARRAY(
+		           SELECT c.name
+		           FROM film_category fc
+		           JOIN category c ON fc.category_id = c.category_id
+		           WHERE fc.film_id = f.film_id
+		           ORDER BY c.name
+		       ),
+		       ARRAY(
+		           SELECT a.first_name || ' ' || a.last_name
+		           FROM film_actor fa
+		           JOIN actor a ON fa.actor_id = a.actor_id
+		           WHERE fa.film_id = f.film_id
+		           ORDER BY a.last_name, a.first_name
+		       )
+		FROM film f
+		WHERE f.film_id > $1
+		  AND f.status = 'published'`
+	args := []interface{}{afterFilmID}
+	if storeID > 0 {
+		args = append(args, storeID)
+		query += fmt.Sprintf(storeFilterClause, len(args))
+	}
+	query += `
+		ORDER BY f.film_id`
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "films.stream"), query, args...)
+	if err != nil {
+		return fmt.Errorf("error querying films: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var film models.Film
+		var specialFeatures sql.NullString
+		var categories, actors pq.StringArray
+
+		scanErr := rows.Scan(
+			&film.FilmID, &film.Title, &film.Description, &film.ReleaseYear,
+			&film.LanguageID, &film.RentalDuration, &film.RentalRate, &film.Length,
+			&film.ReplacementCost, &film.Rating, &film.LastUpdate, &specialFeatures,
+			&film.Status, &film.PublishAt, &categories, &actors,
+		)
+		if scanErr != nil {
+			return fmt.Errorf("error scanning film: %w", scanErr)
+		}
+
+		if specialFeatures.Valid {
+			features := strings.Trim(specialFeatures.String, "{}")
+			if features != "" {
+				film.SpecialFeatures = strings.Split(features, ",")
+			}
+		}
+		if len(categories) > 0 {
+			film.Categories = categories
+		}
+		if len(actors) > 0 {
+			film.Actors = actors
+		}
+
+		if err = fn(film); err != nil {
+			return err
+		}
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return fmt.Errorf("error iterating films: %w", rowsErr)
+	}
+
+	return nil
+}
+
+// GetNewestFilms retrieves the most recently added published films, highest
+// ID first, with their categories.
+func (r *FilmRepository) GetNewestFilms(ctx context.Context, limit int) ([]models.Film, error) {
+	query := `
+		SELECT ` + filmColumns + `,
+		       ARRAY(
+		           SELECT c.name
+		           FROM film_category fc
+		           JOIN category c ON fc.category_id = c.category_id
+		           WHERE fc.film_id = f.film_id
+		           ORDER BY c.name
+		       )
+		FROM film f
+		WHERE f.status = 'published'
+		ORDER BY f.film_id DESC
+		LIMIT $1`
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "films.newest"), query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying newest films: %w", err)
+	}
+	defer rows.Close()
+
+	films := []models.Film{}
+	for rows.Next() {
+		var film models.Film
+		var specialFeatures sql.NullString
+		var categories pq.StringArray
+
+		scanErr := rows.Scan(
+			&film.FilmID, &film.Title, &film.Description, &film.ReleaseYear,
+			&film.LanguageID, &film.RentalDuration, &film.RentalRate, &film.Length,
+			&film.ReplacementCost, &film.Rating, &film.LastUpdate, &specialFeatures,
+			&film.Status, &film.PublishAt, &categories,
+		)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning film: %w", scanErr)
+		}
+
+		if specialFeatures.Valid {
+			features := strings.Trim(specialFeatures.String, "{}")
+			if features != "" {
+				film.SpecialFeatures = strings.Split(features, ",")
+			}
+		}
+		if len(categories) > 0 {
+			film.Categories = categories
+		}
+		films = append(films, film)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating films: %w", rowsErr)
+	}
+
+	return films, nil
+}
+
+// ListPublishedBetween retrieves up to limit films matching filters that
+// were published after after and no later than until, earliest first.
+func (r *FilmRepository) ListPublishedBetween(
+	ctx context.Context,
+	filters models.FilmFilters,
+	after, until time.Time,
+	limit int,
+) ([]models.Film, error) {
+	filters.Statuses = []string{models.FilmPublished}
+	where, args := r.buildFilmsWhere(filters)
+	args = append(args, after, until, limit)
+	query := "SELECT " + filmColumns + " FROM film f" + where +
+		fmt.Sprintf(" AND f.published_at > $%d AND f.published_at <= $%d ORDER BY f.published_at, f.film_id LIMIT $%d",
+			len(args)-2, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "films.published_between"),
+		query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying published films: %w", err)
+	}
+	defer rows.Close()
+
+	films := []models.Film{}
+	for rows.Next() {
+		film, scanErr := r.scanFilm(ctx, rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		films = append(films, film)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating published films: %w", rowsErr)
+	}
+
+	return films, nil
+}
+
+// ListFilmUpdates retrieves when every published film was last changed, in
+// ID order.
+func (r *FilmRepository) ListFilmUpdates(ctx context.Context) ([]models.FilmUpdate, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "films.updates"),
+		"SELECT film_id, last_update FROM film WHERE status = 'published' ORDER BY film_id")
+	if err != nil {
+		return nil, fmt.Errorf("error querying film updates: %w", err)
+	}
+	defer rows.Close()
+
+	updates := []models.FilmUpdate{}
+	for rows.Next() {
+		var update models.FilmUpdate
+		if scanErr := rows.Scan(&update.FilmID, &update.LastUpdate); scanErr != nil {
+			return nil, fmt.Errorf("error scanning film update: %w", scanErr)
+		}
+		updates = append(updates, update)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating film updates: %w", rowsErr)
+	}
+
+	return updates, nil
+}
+
 // getFilmCategories retrieves categories for a film.
-func (r *FilmRepository) getFilmCategories(filmID int) ([]string, error) {
+func (r *FilmRepository) getFilmCategories(ctx context.Context, filmID int) ([]string, error) {
 	query := `
 		SELECT c.name 
 		FROM category c
@@ -252,7 +646,7 @@ func (r *FilmRepository) getFilmCategories(filmID int) ([]string, error) {
 		ORDER BY c.name
 	`
 
-	rows, err := r.db.QueryContext(context.Background(), query, filmID)
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "films.categories"), query, filmID)
 	if err != nil {
 		return nil, fmt.Errorf("error querying film categories: %w", err)
 	}
@@ -276,7 +670,7 @@ func (r *FilmRepository) getFilmCategories(filmID int) ([]string, error) {
 }
 
 // getFilmActors retrieves actors for a film.
-func (r *FilmRepository) getFilmActors(filmID int) ([]string, error) {
+func (r *FilmRepository) getFilmActors(ctx context.Context, filmID int) ([]string, error) {
 	query := `
 		SELECT a.first_name || ' ' || a.last_name as actor_name
 		FROM actor a
@@ -285,7 +679,7 @@ func (r *FilmRepository) getFilmActors(filmID int) ([]string, error) {
 		ORDER BY a.last_name, a.first_name
 	`
 
-	rows, err := r.db.QueryContext(context.Background(), query, filmID)
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "films.actors"), query, filmID)
 	if err != nil {
 		return nil, fmt.Errorf("error querying film actors: %w", err)
 	}
@@ -308,11 +702,51 @@ func (r *FilmRepository) getFilmActors(filmID int) ([]string, error) {
 	return actors, nil
 }
 
-// GetCategories retrieves all categories.
-func (r *FilmRepository) GetCategories() ([]models.Category, error) {
-	query := `SELECT category_id, name FROM category ORDER BY name`
+// getFilmCollections retrieves the collections a film belongs to, with its
+// position in each.
+func (r *FilmRepository) getFilmCollections(ctx context.Context, filmID int) ([]models.FilmCollection, error) {
+	query := `
+		SELECT c.id, c.name, cf.position
+		FROM collections c
+		JOIN collection_films cf ON c.id = cf.collection_id
+		WHERE cf.film_id = $1
+		ORDER BY c.name
+	`
 
-	rows, err := r.db.QueryContext(context.Background(), query)
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "films.collections"), query, filmID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying film collections: %w", err)
+	}
+	defer rows.Close()
+
+	var collections []models.FilmCollection
+	for rows.Next() {
+		var collection models.FilmCollection
+		scanErr := rows.Scan(&collection.ID, &collection.Name, &collection.Position)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning collection: %w", scanErr)
+		}
+		collections = append(collections, collection)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating film collections: %w", rowsErr)
+	}
+
+	return collections, nil
+}
+
+// getFilmTags retrieves a film's tags in alphabetical order.
+func (r *FilmRepository) getFilmTags(ctx context.Context, filmID int) ([]string, error) {
+	return queryFilmTags(database.WithQueryName(ctx, "films.tags"), r.db, filmID)
+}
+
+// GetCategories retrieves all categories, with the parent of each
+// subcategory.
+func (r *FilmRepository) GetCategories(ctx context.Context) ([]models.Category, error) {
+	query := `SELECT category_id, name, parent_id FROM category ORDER BY name`
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "categories.list"), query)
 	if err != nil {
 		return nil, fmt.Errorf("error querying categories: %w", err)
 	}
@@ -321,7 +755,7 @@ func (r *FilmRepository) GetCategories() ([]models.Category, error) {
 	var categories []models.Category
 	for rows.Next() {
 		var category models.Category
-		scanErr := rows.Scan(&category.CategoryID, &category.Name)
+		scanErr := rows.Scan(&category.CategoryID, &category.Name, &category.ParentID)
 		if scanErr != nil {
 			return nil, fmt.Errorf("error scanning category: %w", scanErr)
 		}
@@ -334,3 +768,263 @@ func (r *FilmRepository) GetCategories() ([]models.Category, error) {
 
 	return categories, nil
 }
+
+// filmStatusColumns lists the columns scanned by scanFilmStatus, in order.
+const filmStatusColumns = "film_id, status, publish_at, last_update"
+
+// SetFilmStatus changes a film's status, cancelling any schedule, and
+// returns it with the status it had before. It returns ErrFilmNotFound if
+// the film does not exist.
+func (r *FilmRepository) SetFilmStatus(
+	ctx context.Context,
+	filmID int,
+	status string,
+) (*models.FilmStatus, string, error) {
+	ctx = database.WithQueryName(ctx, "films.set_status")
+	var previous string
+	filmStatus := &models.FilmStatus{}
+	err := r.db.QueryRowContext(ctx, `
+		WITH previous AS (SELECT status FROM film WHERE film_id = $1 FOR UPDATE)
+		UPDATE film f SET status = $2, publish_at = NULL, last_update = NOW(),
+			published_at = CASE WHEN $2 = 'published' AND p.status <> 'published' THEN NOW() ELSE f.published_at END
+		FROM previous p
+		WHERE f.film_id = $1
+		RETURNING f.film_id, f.status, f.publish_at, f.last_update, p.status`, filmID, status).
+		Scan(&filmStatus.FilmID, &filmStatus.Status, &filmStatus.PublishAt, &filmStatus.LastUpdate, &previous)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", ErrFilmNotFound
+		}
+		return nil, "", fmt.Errorf("error updating film status: %w", err)
+	}
+
+	return filmStatus, previous, nil
+}
+
+// ScheduleFilmPublish sets when a draft film is published, or cancels its
+// schedule when publishAt is nil. It returns ErrFilmNotDraft for a film that
+// is not a draft.
+func (r *FilmRepository) ScheduleFilmPublish(
+	ctx context.Context,
+	filmID int,
+	publishAt *time.Time,
+) (*models.FilmStatus, error) {
+	ctx = database.WithQueryName(ctx, "films.schedule_publish")
+	filmStatus, err := scanFilmStatus(r.db.QueryRowContext(ctx, `
+		UPDATE film SET publish_at = $2
+		WHERE film_id = $1 AND status = 'draft'
+		RETURNING `+filmStatusColumns, filmID, publishAt))
+	if err == nil {
+		return filmStatus, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("error scheduling film: %w", err)
+	}
+
+	if existsErr := checkFilmExists(ctx, r.db, "films.schedule_publish_exists", filmID); existsErr != nil {
+		return nil, existsErr
+	}
+	return nil, ErrFilmNotDraft
+}
+
+// PublishScheduledFilms publishes the drafts whose publish_at has passed and
+// returns them, so each is published once.
+func (r *FilmRepository) PublishScheduledFilms(ctx context.Context) ([]models.FilmStatus, error) {
+	ctx = database.WithQueryName(ctx, "films.publish_scheduled")
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE film SET status = 'published', publish_at = NULL, published_at = NOW(), last_update = NOW()
+		WHERE status = 'draft' AND publish_at <= NOW()
+		RETURNING `+filmStatusColumns)
+	if err != nil {
+		return nil, fmt.Errorf("error publishing scheduled films: %w", err)
+	}
+	defer rows.Close()
+
+	published := []models.FilmStatus{}
+	for rows.Next() {
+		filmStatus, scanErr := scanFilmStatus(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning published film: %w", scanErr)
+		}
+		published = append(published, *filmStatus)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating published films: %w", rowsErr)
+	}
+
+	return published, nil
+}
+
+// UpdateFilm changes the details given in update and returns the film. The
+// change is made only if the film's last_update is still version, so an
+// edit of an out-of-date copy returns ErrFilmModified instead of
+// overwriting a newer one.
+func (r *FilmRepository) UpdateFilm(
+	ctx context.Context,
+	filmID int,
+	version time.Time,
+	update models.FilmUpdateRequest,
+) (*models.Film, error) {
+	ctx = database.WithQueryName(ctx, "films.update")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE film SET
+			title = COALESCE($3, title),
+			description = COALESCE($4, description),
+			release_year = COALESCE($5, release_year),
+			rental_duration = COALESCE($6, rental_duration),
+			rental_rate = COALESCE($7, rental_rate),
+			length = COALESCE($8, length),
+			replacement_cost = COALESCE($9, replacement_cost),
+			rating = COALESCE($10, rating),
+			last_update = NOW()
+		WHERE film_id = $1 AND last_update = $2`,
+		filmID, version.UTC(), update.Title, update.Description, update.ReleaseYear, update.RentalDuration,
+		update.RentalRate, update.Length, update.ReplacementCost, update.Rating)
+	if err != nil {
+		return nil, fmt.Errorf("error updating film: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error updating film: %w", err)
+	}
+
+	if updated == 0 {
+		if existsErr := checkFilmExists(ctx, r.db, "films.update_exists", filmID); existsErr != nil {
+			return nil, existsErr
+		}
+		return nil, ErrFilmModified
+	}
+
+	return r.GetFilmByID(ctx, filmID)
+}
+
+// txBeginner is implemented by the Queriers that can start a transaction,
+// such as *database.DB, but not by a transaction itself.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// BulkUpdateRentalRate changes the rental rate of the films filters select,
+// ignoring paging, in one transaction that locks them while they are
+// counted and changed. A dry run rolls the transaction back, so it reports
+// exactly the counts the change would have. The IDs of the films whose rate
+// changed are returned, and none for a dry run.
+func (r *FilmRepository) BulkUpdateRentalRate(
+	ctx context.Context,
+	filters models.FilmFilters,
+	change models.BulkPriceRequest,
+) (*models.BulkPriceResult, []int, error) {
+	ctx = database.WithQueryName(ctx, "films.bulk_price")
+	beginner, ok := r.db.(txBeginner)
+	if !ok {
+		return nil, nil, errors.New("error starting transaction: already running in one")
+	}
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "films.bulk_price")
+
+	where, args := r.buildFilmsWhere(filters)
+	result := &models.BulkPriceResult{DryRun: change.DryRun}
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM (SELECT 1 FROM film f"+where+" FOR UPDATE) matched",
+		args...).Scan(&result.FilmsMatched)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error counting films: %w", err)
+	}
+
+	var rate string
+	if change.Percent != nil {
+		args = append(args, *change.Percent)
+		rate = fmt.Sprintf("LEAST(ROUND(f.rental_rate * (1 + $%d::numeric / 100), 2), 99.99)", len(args))
+	} else {
+		args = append(args, *change.RentalRate)
+		rate = fmt.Sprintf("$%d::numeric", len(args))
+	}
+	rows, err := tx.QueryContext(ctx, "UPDATE film f SET rental_rate = "+rate+", last_update = NOW()"+
+		where+" AND f.rental_rate <> "+rate+" RETURNING f.film_id", args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating rental rates: %w", err)
+	}
+	defer rows.Close()
+
+	var filmIDs []int
+	for rows.Next() {
+		var filmID int
+		if scanErr := rows.Scan(&filmID); scanErr != nil {
+			return nil, nil, fmt.Errorf("error scanning updated film: %w", scanErr)
+		}
+		filmIDs = append(filmIDs, filmID)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, nil, fmt.Errorf("error iterating updated films: %w", rowsErr)
+	}
+	result.FilmsChanged = len(filmIDs)
+
+	if change.DryRun {
+		return result, nil, nil
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("error committing rental rates: %w", err)
+	}
+	return result, filmIDs, nil
+}
+
+// RestoreFilm publishes an archived film again. It returns
+// ErrFilmNotArchived for a film that is not archived.
+func (r *FilmRepository) RestoreFilm(ctx context.Context, filmID int) (*models.FilmStatus, error) {
+	ctx = database.WithQueryName(ctx, "films.restore")
+	filmStatus, err := scanFilmStatus(r.db.QueryRowContext(ctx, `
+		UPDATE film SET status = 'published', published_at = NOW(), last_update = NOW()
+		WHERE film_id = $1 AND status = 'archived'
+		RETURNING `+filmStatusColumns, filmID))
+	if err == nil {
+		return filmStatus, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("error restoring film: %w", err)
+	}
+
+	if existsErr := checkFilmExists(ctx, r.db, "films.restore_exists", filmID); existsErr != nil {
+		return nil, existsErr
+	}
+	return nil, ErrFilmNotArchived
+}
+
+// scanFilmStatus scans a row of filmStatusColumns.
+func scanFilmStatus(row interface{ Scan(dest ...any) error }) (*models.FilmStatus, error) {
+	filmStatus := &models.FilmStatus{}
+	err := row.Scan(&filmStatus.FilmID, &filmStatus.Status, &filmStatus.PublishAt, &filmStatus.LastUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return filmStatus, nil
+}
+
+// checkFilmExists returns ErrFilmNotFound if the film does not exist. The
+// check runs under queryName so it is attributed to the caller.
+func checkFilmExists(ctx context.Context, db database.Querier, queryName string, filmID int) error {
+	return checkFilm(ctx, db, queryName, "SELECT EXISTS(SELECT 1 FROM film WHERE film_id = $1)", filmID)
+}
+
+// checkFilmPublished returns ErrFilmNotFound unless filmID is a published
+// film, for lookups on behalf of customers.
+func checkFilmPublished(ctx context.Context, db database.Querier, queryName string, filmID int) error {
+	return checkFilm(ctx, db, queryName,
+		"SELECT EXISTS(SELECT 1 FROM film WHERE film_id = $1 AND status = 'published')", filmID)
+}
+
+// checkFilm returns ErrFilmNotFound unless the EXISTS query finds filmID.
+func checkFilm(ctx context.Context, db database.Querier, queryName, query string, filmID int) error {
+	var filmExists bool
+	existsCtx := database.WithQueryName(ctx, queryName)
+	err := db.QueryRowContext(existsCtx, query, filmID).Scan(&filmExists)
+	if err != nil {
+		return fmt.Errorf("error checking film existence: %w", err)
+	}
+	if !filmExists {
+		return ErrFilmNotFound
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// giftCardColumns lists the gift_cards columns scanned by scanGiftCard, in
+// order, with the card's balance summed from its ledger.
+const giftCardColumns = `id, last_four, purchaser_id, amount::float8,
+		(SELECT COALESCE(SUM(l.amount), 0) FROM gift_card_ledger l WHERE l.gift_card_id = gift_cards.id)::float8,
+		currency, status, provider, COALESCE(provider_payment_id, ''), COALESCE(client_secret, ''),
+		created_at, updated_at`
+
+// GiftCardRepository handles database operations for gift cards. Cards are
+// looked up by the hash of their code, never the code itself.
+type GiftCardRepository struct {
+	db *database.DB
+}
+
+// NewGiftCardRepository creates a new gift card repository.
+func NewGiftCardRepository(db *database.DB) *GiftCardRepository {
+	return &GiftCardRepository{db: db}
+}
+
+// CreateGiftCard stores a pending gift card under codeHash, to be paid for
+// through card.Provider.
+func (r *GiftCardRepository) CreateGiftCard(
+	ctx context.Context,
+	card models.GiftCard,
+	codeHash string,
+) (*models.GiftCard, error) {
+	ctx = database.WithQueryName(ctx, "gift_cards.create")
+	created, err := scanGiftCard(r.db.QueryRowContext(ctx, `
+		INSERT INTO gift_cards (code_hash, last_four, purchaser_id, amount, currency, provider)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+giftCardColumns,
+		codeHash, card.LastFour, card.PurchaserID, card.Amount, card.Currency, card.Provider,
+	))
+	if err != nil {
+		err = constraintError(err, err)
+		if errors.Is(err, ErrInvalidReference) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error inserting gift card: %w", err)
+	}
+
+	return created, nil
+}
+
+// SetGiftCardPayment records the provider payment a gift card is bought
+// with.
+func (r *GiftCardRepository) SetGiftCardPayment(
+	ctx context.Context,
+	giftCardID int,
+	providerPaymentID, clientSecret string,
+) (*models.GiftCard, error) {
+	ctx = database.WithQueryName(ctx, "gift_cards.set_payment")
+	card, err := scanGiftCard(r.db.QueryRowContext(ctx, `
+		UPDATE gift_cards SET provider_payment_id = $2, client_secret = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+giftCardColumns, giftCardID, providerPaymentID, clientSecret))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGiftCardNotFound
+		}
+		return nil, fmt.Errorf("error recording gift card payment: %w", err)
+	}
+
+	return card, nil
+}
+
+// GetGiftCardByCode retrieves the gift card stored under codeHash, with its
+// balance. Cards whose purchase failed are not found.
+func (r *GiftCardRepository) GetGiftCardByCode(ctx context.Context, codeHash string) (*models.GiftCard, error) {
+	ctx = database.WithQueryName(ctx, "gift_cards.get_by_code")
+	card, err := scanGiftCard(r.db.QueryRowContext(ctx,
+		"SELECT "+giftCardColumns+" FROM gift_cards WHERE code_hash = $1 AND status <> $2",
+		codeHash, models.GiftCardStatusFailed))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGiftCardNotFound
+		}
+		return nil, fmt.Errorf("error querying gift card: %w", err)
+	}
+
+	return card, nil
+}
+
+// GetProviderGiftCard retrieves a gift card by its provider's ID for the
+// payment it is bought with.
+func (r *GiftCardRepository) GetProviderGiftCard(
+	ctx context.Context,
+	provider, providerPaymentID string,
+) (*models.GiftCard, error) {
+	ctx = database.WithQueryName(ctx, "gift_cards.get_for_provider")
+	card, err := scanGiftCard(r.db.QueryRowContext(ctx,
+		"SELECT "+giftCardColumns+" FROM gift_cards WHERE provider = $1 AND provider_payment_id = $2",
+		provider, providerPaymentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGiftCardNotFound
+		}
+		return nil, fmt.Errorf("error querying gift card: %w", err)
+	}
+
+	return card, nil
+}
+
+// ActivateGiftCard marks a gift card paid for and credits it with its
+// amount. Activating a card that is already active changes nothing, so
+// repeated provider callbacks are harmless.
+func (r *GiftCardRepository) ActivateGiftCard(ctx context.Context, giftCardID int) (*models.GiftCard, error) {
+	ctx = database.WithQueryName(ctx, "gift_cards.activate")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "gift_cards.activate")
+
+	card, err := scanGiftCard(tx.QueryRowContext(ctx,
+		"SELECT "+giftCardColumns+" FROM gift_cards WHERE id = $1 FOR UPDATE", giftCardID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGiftCardNotFound
+		}
+		return nil, fmt.Errorf("error locking gift card: %w", err)
+	}
+	if card.Status == models.GiftCardStatusActive {
+		return card, nil
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE gift_cards SET status = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING status, updated_at`, giftCardID, models.GiftCardStatusActive,
+	).Scan(&card.Status, &card.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error activating gift card: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO gift_card_ledger (gift_card_id, amount, reason) VALUES ($1, $2, $3)",
+		giftCardID, card.Amount, models.GiftCardReasonPurchase)
+	if err != nil {
+		return nil, fmt.Errorf("error crediting gift card: %w", err)
+	}
+	card.Balance += card.Amount
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing gift card activation: %w", err)
+	}
+
+	return card, nil
+}
+
+// FailGiftCard marks a pending gift card's purchase failed. Active cards
+// are left alone, as providers may report a failed attempt after a later
+// one succeeded.
+func (r *GiftCardRepository) FailGiftCard(ctx context.Context, giftCardID int) error {
+	ctx = database.WithQueryName(ctx, "gift_cards.fail")
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE gift_cards SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3`, giftCardID, models.GiftCardStatusFailed, models.GiftCardStatusPending)
+	if err != nil {
+		return fmt.Errorf("error failing gift card: %w", err)
+	}
+
+	return nil
+}
+
+// spendGiftCard locks the gift card stored under codeHash and takes as much
+// of its balance as covers amount, returning the card's ID and what was
+// taken. The ledger entry for it is added by recordGiftCardSpend once the
+// checkout has an ID.
+func spendGiftCard(ctx context.Context, tx *sql.Tx, codeHash string, amount float64) (int, float64, error) {
+	card, err := scanGiftCard(tx.QueryRowContext(ctx,
+		"SELECT "+giftCardColumns+" FROM gift_cards WHERE code_hash = $1 AND status <> $2 FOR UPDATE",
+		codeHash, models.GiftCardStatusFailed))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, ErrGiftCardNotFound
+		}
+		return 0, 0, fmt.Errorf("error locking gift card: %w", err)
+	}
+	if card.Status != models.GiftCardStatusActive {
+		return 0, 0, ErrGiftCardInactive
+	}
+	if card.Balance <= 0 {
+		return 0, 0, ErrGiftCardEmpty
+	}
+
+	return card.ID, math.Max(0, math.Min(card.Balance, amount)), nil
+}
+
+// recordGiftCardSpend adds the ledger entry for a gift card's balance spent
+// on checkoutID.
+func recordGiftCardSpend(ctx context.Context, tx *sql.Tx, giftCardID, checkoutID int, amount float64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO gift_card_ledger (gift_card_id, amount, reason, checkout_id)
+		VALUES ($1, $2, $3, $4)`, giftCardID, -amount, models.GiftCardReasonCheckout, checkoutID)
+	if err != nil {
+		return fmt.Errorf("error recording gift card spend: %w", err)
+	}
+	return nil
+}
+
+// scanGiftCard scans giftCardColumns from row.
+func scanGiftCard(row interface{ Scan(dest ...any) error }) (*models.GiftCard, error) {
+	var card models.GiftCard
+	err := row.Scan(
+		&card.ID, &card.LastFour, &card.PurchaserID, &card.Amount, &card.Balance, &card.Currency, &card.Status,
+		&card.Provider, &card.ProviderPaymentID, &card.ClientSecret, &card.CreatedAt, &card.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// GuestRepository handles database operations for anonymous guest sessions
+// and what guests do before they register.
+type GuestRepository struct {
+	db *database.DB
+}
+
+// NewGuestRepository creates a new guest session repository.
+func NewGuestRepository(db *database.DB) *GuestRepository {
+	return &GuestRepository{db: db}
+}
+
+// CreateSession starts a guest session and returns its ID.
+func (r *GuestRepository) CreateSession(ctx context.Context) (int, error) {
+	ctx = database.WithQueryName(ctx, "guest_sessions.create")
+	var guestID int
+	if err := r.db.QueryRowContext(ctx, "INSERT INTO guest_sessions DEFAULT VALUES RETURNING id").
+		Scan(&guestID); err != nil {
+		return 0, fmt.Errorf("error inserting guest session: %w", err)
+	}
+	return guestID, nil
+}
+
+// RecordComment records that a guest posted a comment.
+func (r *GuestRepository) RecordComment(ctx context.Context, guestID, commentID int) error {
+	ctx = database.WithQueryName(ctx, "guest_sessions.record_comment")
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO guest_session_comments (guest_session_id, comment_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, guestID, commentID)
+	if err != nil {
+		return fmt.Errorf("error recording guest comment: %w", err)
+	}
+	return nil
+}
+
+// ListWatchlist retrieves the films on a guest's watchlist, most recently
+// added first.
+func (r *GuestRepository) ListWatchlist(ctx context.Context, guestID int) ([]models.GuestWatchlistEntry, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "guest_watchlist.list"), `
+		SELECT w.film_id, f.title, w.added_at
+		FROM guest_watchlist w
+		JOIN film f ON f.film_id = w.film_id
+		WHERE w.guest_session_id = $1
+		ORDER BY w.added_at DESC, w.film_id`, guestID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying guest watchlist: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.GuestWatchlistEntry{}
+	for rows.Next() {
+		var entry models.GuestWatchlistEntry
+		if scanErr := rows.Scan(&entry.FilmID, &entry.Title, &entry.AddedAt); scanErr != nil {
+			return nil, fmt.Errorf("error scanning guest watchlist entry: %w", scanErr)
+		}
+		entries = append(entries, entry)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating guest watchlist: %w", rowsErr)
+	}
+
+	return entries, nil
+}
+
+// AddToWatchlist adds a film to a guest's watchlist; adding a film already
+// on it changes nothing. It returns ErrGuestSessionMerged once the guest
+// has registered, and ErrListFull when the watchlist holds
+// models.MaxListEntries films, the most the list it becomes can hold.
+func (r *GuestRepository) AddToWatchlist(ctx context.Context, guestID, filmID int) error {
+	ctx = database.WithQueryName(ctx, "guest_watchlist.add")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "guest_watchlist.add")
+
+	// The watchlist is counted in its own statement, after the lock, so that
+	// it sees films added by whoever held the lock before.
+	var merged bool
+	err = tx.QueryRowContext(ctx, "SELECT merged_at IS NOT NULL FROM guest_sessions WHERE id = $1 FOR UPDATE",
+		guestID).Scan(&merged)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrGuestSessionNotFound
+		}
+		return fmt.Errorf("error locking guest session: %w", err)
+	}
+	if merged {
+		return ErrGuestSessionMerged
+	}
+	var entries int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM guest_watchlist WHERE guest_session_id = $1", guestID).
+		Scan(&entries)
+	if err != nil {
+		return fmt.Errorf("error counting guest watchlist: %w", err)
+	}
+	if entries >= models.MaxListEntries {
+		return ErrListFull
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO guest_watchlist (guest_session_id, film_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, guestID, filmID)
+	if err != nil {
+		err = constraintError(err, err)
+		if errors.Is(err, ErrInvalidReference) {
+			return ErrFilmNotFound
+		}
+		return fmt.Errorf("error adding film to guest watchlist: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing guest watchlist: %w", err)
+	}
+	return nil
+}
+
+// RemoveFromWatchlist removes a film from a guest's watchlist. It returns
+// ErrListEntryNotFound if the film is not on it.
+func (r *GuestRepository) RemoveFromWatchlist(ctx context.Context, guestID, filmID int) error {
+	ctx = database.WithQueryName(ctx, "guest_watchlist.remove")
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM guest_watchlist WHERE guest_session_id = $1 AND film_id = $2", guestID, filmID)
+	if err != nil {
+		return fmt.Errorf("error removing film from guest watchlist: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error removing film from guest watchlist: %w", err)
+	}
+	if removed == 0 {
+		return ErrListEntryNotFound
+	}
+	return nil
+}
+
+// MergeSession moves what a guest did into customerID's account: their
+// comments are linked to the customer, like comments posted with a
+// customer token, and their watchlist becomes a private list named
+// models.GuestWatchlistName. A session is merged once; merging it again
+// returns ErrGuestSessionMerged.
+func (r *GuestRepository) MergeSession(ctx context.Context, guestID, customerID int) (*models.GuestMerge, error) {
+	ctx = database.WithQueryName(ctx, "guest_sessions.merge")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "guest_sessions.merge")
+
+	claimed, err := execCount(ctx, tx, `
+		UPDATE guest_sessions SET merged_customer_id = $2, merged_at = NOW()
+		WHERE id = $1 AND merged_at IS NULL`, guestID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("error claiming guest session: %w", err)
+	}
+	if claimed == 0 {
+		var exists bool
+		if err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM guest_sessions WHERE id = $1)", guestID).
+			Scan(&exists); err != nil {
+			return nil, fmt.Errorf("error checking guest session: %w", err)
+		}
+		if !exists {
+			return nil, ErrGuestSessionNotFound
+		}
+		return nil, ErrGuestSessionMerged
+	}
+
+	merge := &models.GuestMerge{GuestID: guestID}
+	// Linked comments store no free-text name, so the guest's is dropped.
+	merge.CommentsLinked, err = execCount(ctx, tx, `
+		UPDATE film_comments c SET customer_id = $2, customer_name = NULL
+		FROM guest_session_comments g
+		WHERE g.comment_id = c.id AND g.guest_session_id = $1 AND c.customer_id IS NULL`, guestID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("error linking guest comments: %w", err)
+	}
+
+	var listID int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO customer_lists (customer_id, name, description, visibility)
+		SELECT $2::int, $3::varchar, '', $4::varchar
+		WHERE EXISTS (SELECT 1 FROM guest_watchlist WHERE guest_session_id = $1)
+		RETURNING id`, guestID, customerID, models.GuestWatchlistName, models.ListVisibilityPrivate).Scan(&listID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return nil, fmt.Errorf("error creating watchlist: %w", err)
+	default:
+		merge.ListID = &listID
+		merge.WatchlistFilms, err = execCount(ctx, tx, `
+			INSERT INTO customer_list_entries (list_id, film_id, position)
+			SELECT $2, film_id, ROW_NUMBER() OVER (ORDER BY added_at, film_id)
+			FROM guest_watchlist WHERE guest_session_id = $1`, guestID, listID)
+		if err != nil {
+			return nil, fmt.Errorf("error copying watchlist: %w", err)
+		}
+		if _, err = tx.ExecContext(ctx, "DELETE FROM guest_watchlist WHERE guest_session_id = $1", guestID); err != nil {
+			return nil, fmt.Errorf("error clearing guest watchlist: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing guest merge: %w", err)
+	}
+	return merge, nil
+}
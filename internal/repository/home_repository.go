@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// customerFilmsCTE selects the films a customer, $1, has ever rented.
+const customerFilmsCTE = `rented AS (
+			SELECT DISTINCT i.film_id
+			FROM rental r
+			JOIN inventory i ON i.inventory_id = r.inventory_id
+			WHERE r.customer_id = $1
+		)`
+
+// HomeRepository handles the database queries behind customers' home
+// feeds.
+type HomeRepository struct {
+	db *database.DB
+}
+
+// NewHomeRepository creates a new home feed repository.
+func NewHomeRepository(db *database.DB) *HomeRepository {
+	return &HomeRepository{db: db}
+}
+
+// GetRecommendedFilms retrieves up to limit published films the customer has
+// not rented, ranked by how often they were rented by the customers who
+// rented the same films as the customer.
+func (r *HomeRepository) GetRecommendedFilms(ctx context.Context, customerID, limit int) ([]models.HomeFilm, error) {
+	query := `
+		WITH ` + customerFilmsCTE + `,
+		peers AS (
+			SELECT DISTINCT r.customer_id
+			FROM rental r
+			JOIN inventory i ON i.inventory_id = r.inventory_id
+			WHERE i.film_id IN (SELECT film_id FROM rented) AND r.customer_id <> $1
+		)
+		SELECT f.film_id, f.title, f.release_year, f.rating
+		FROM rental r
+		JOIN inventory i ON i.inventory_id = r.inventory_id
+		JOIN film f ON f.film_id = i.film_id
+		WHERE r.customer_id IN (SELECT customer_id FROM peers)
+		AND f.status = 'published'
+		AND f.film_id NOT IN (SELECT film_id FROM rented)
+		GROUP BY f.film_id
+		ORDER BY COUNT(*) DESC, f.film_id
+		LIMIT $2`
+
+	return r.queryHomeFilms(ctx, "home.recommended", query, customerID, limit)
+}
+
+// GetFavoriteCategories retrieves the names of up to limit categories the
+// customer has rented the most films in, most rented first.
+func (r *HomeRepository) GetFavoriteCategories(ctx context.Context, customerID, limit int) ([]string, error) {
+	ctx = database.WithQueryName(ctx, "home.favorite_categories")
+	var categories pq.StringArray
+	err := r.db.QueryRowContext(ctx, `
+		SELECT ARRAY(
+			SELECT c.name
+			FROM rental r
+			JOIN inventory i ON i.inventory_id = r.inventory_id
+			JOIN film_category fc ON fc.film_id = i.film_id
+			JOIN category c ON c.category_id = fc.category_id
+			WHERE r.customer_id = $1
+			GROUP BY c.category_id, c.name
+			ORDER BY COUNT(*) DESC, c.name
+			LIMIT $2
+		)`, customerID, limit).Scan(&categories)
+	if err != nil {
+		return nil, fmt.Errorf("error querying favorite categories: %w", err)
+	}
+
+	return []string(categories), nil
+}
+
+// GetNewFilmsInCategories retrieves up to limit published films in any of
+// categories that the customer has not rented, most recently published
+// first.
+func (r *HomeRepository) GetNewFilmsInCategories(
+	ctx context.Context,
+	customerID int,
+	categories []string,
+	limit int,
+) ([]models.HomeFilm, error) {
+	query := `
+		WITH ` + customerFilmsCTE + `
+		SELECT f.film_id, f.title, f.release_year, f.rating
+		FROM film f
+		WHERE f.status = 'published'
+		AND f.film_id NOT IN (SELECT film_id FROM rented)
+		AND EXISTS (
+			SELECT 1 FROM film_category fc
+			JOIN category c ON c.category_id = fc.category_id
+			WHERE fc.film_id = f.film_id AND c.name = ANY($2)
+		)
+		ORDER BY f.published_at DESC NULLS LAST, f.film_id DESC
+		LIMIT $3`
+
+	return r.queryHomeFilms(ctx, "home.new_in_categories", query, customerID, pq.Array(categories), limit)
+}
+
+// queryHomeFilms runs a query selecting the columns of models.HomeFilm.
+func (r *HomeRepository) queryHomeFilms(
+	ctx context.Context,
+	queryName, query string,
+	args ...any,
+) ([]models.HomeFilm, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, queryName), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying home films: %w", err)
+	}
+	defer rows.Close()
+
+	films := []models.HomeFilm{}
+	for rows.Next() {
+		var film models.HomeFilm
+		if scanErr := rows.Scan(&film.FilmID, &film.Title, &film.ReleaseYear, &film.Rating); scanErr != nil {
+			return nil, fmt.Errorf("error scanning home film: %w", scanErr)
+		}
+		films = append(films, film)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating home films: %w", rowsErr)
+	}
+
+	return films, nil
+}
@@ -1,26 +1,777 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
 )
 
 // FilmRepositoryInterface defines the interface for film-related database operations.
 type FilmRepositoryInterface interface {
 	// GetFilms retrieves films with optional filtering and pagination.
-	GetFilms(filters models.FilmFilters) (*models.FilmListResponse, error)
+	GetFilms(ctx context.Context, filters models.FilmFilters) (*pagination.Paginated[models.Film], error)
 
 	// GetFilmByID retrieves a specific film by its ID.
-	GetFilmByID(filmID int) (*models.Film, error)
+	GetFilmByID(ctx context.Context, filmID int) (*models.Film, error)
 
 	// GetCategories retrieves all available film categories.
-	GetCategories() ([]models.Category, error)
+	GetCategories(ctx context.Context) ([]models.Category, error)
+}
+
+// FilmStatusRepositoryInterface defines the interface for publishing and
+// withdrawing films.
+type FilmStatusRepositoryInterface interface {
+	// SetFilmStatus changes a film's status, returning the status it had before.
+	SetFilmStatus(ctx context.Context, filmID int, status string) (*models.FilmStatus, string, error)
+
+	// ScheduleFilmPublish sets or cancels when a draft film is published.
+	ScheduleFilmPublish(ctx context.Context, filmID int, publishAt *time.Time) (*models.FilmStatus, error)
+
+	// PublishScheduledFilms publishes the drafts whose publish_at has passed.
+	PublishScheduledFilms(ctx context.Context) ([]models.FilmStatus, error)
+
+	// RestoreFilm publishes an archived film again.
+	RestoreFilm(ctx context.Context, filmID int) (*models.FilmStatus, error)
+}
+
+// FilmUpdateRepositoryInterface defines the interface for editing films.
+type FilmUpdateRepositoryInterface interface {
+	// UpdateFilm changes a film's details if it is still at version, its
+	// last_update.
+	UpdateFilm(ctx context.Context, filmID int, version time.Time, update models.FilmUpdateRequest) (*models.Film, error)
+
+	// BulkUpdateRentalRate changes the rental rate of the films filters
+	// select, returning the IDs of those changed.
+	BulkUpdateRentalRate(
+		ctx context.Context,
+		filters models.FilmFilters,
+		change models.BulkPriceRequest,
+	) (*models.BulkPriceResult, []int, error)
+}
+
+// FilmExportRepositoryInterface defines the interface for reading the whole
+// film catalog in one pass.
+type FilmExportRepositoryInterface interface {
+	// StreamFilms calls fn for each published film after afterFilmID in ID order,
+	// limited to one store when storeID is non-zero, stopping at fn's first error.
+	StreamFilms(ctx context.Context, storeID, afterFilmID int, fn func(models.Film) error) error
+}
+
+// FilmFeedRepositoryInterface defines the interface for reading the films
+// shown in the new films feed.
+type FilmFeedRepositoryInterface interface {
+	// GetNewestFilms retrieves the most recently added published films, highest ID first.
+	GetNewestFilms(ctx context.Context, limit int) ([]models.Film, error)
+}
+
+// FilmSitemapRepositoryInterface defines the interface for reading the
+// films listed in sitemaps.
+type FilmSitemapRepositoryInterface interface {
+	// ListFilmUpdates retrieves when every published film was last changed, in ID order.
+	ListFilmUpdates(ctx context.Context) ([]models.FilmUpdate, error)
+}
+
+// FilmPublishedRepositoryInterface defines the interface for finding the
+// films published in a period.
+type FilmPublishedRepositoryInterface interface {
+	// ListPublishedBetween retrieves up to limit films matching filters published in (after, until].
+	ListPublishedBetween(
+		ctx context.Context,
+		filters models.FilmFilters,
+		after, until time.Time,
+		limit int,
+	) ([]models.Film, error)
 }
 
 // CommentRepositoryInterface defines the interface for comment-related database operations.
 type CommentRepositoryInterface interface {
 	// AddComment adds a new comment to a film.
-	AddComment(filmID int, commentReq models.CommentRequest) (*models.Comment, error)
+	AddComment(ctx context.Context, filmID int, commentReq models.CommentRequest) (*models.Comment, error)
+
+	// GetCommentsByFilmID retrieves limit of a film's comments, newest
+	// first, starting after the comment after marks if it is set, hiding
+	// shadow-banned customers' comments from all but themselves.
+	GetCommentsByFilmID(
+		ctx context.Context,
+		filmID int,
+		viewerID int,
+		limit int,
+		after *pagination.Cursor,
+	) (*pagination.Paginated[models.Comment], error)
+
+	// GetCommentAuthor retrieves the customer who posted a verified comment.
+	GetCommentAuthor(ctx context.Context, commentID int) (*models.CommentAuthor, error)
+
+	// GetMentionTargets retrieves the active customers among customerIDs.
+	GetMentionTargets(ctx context.Context, customerIDs []int) ([]models.CommentAuthor, error)
+
+	// SetCommentsLocked locks or unlocks new comments on a film.
+	SetCommentsLocked(ctx context.Context, filmID int, locked bool) (*models.CommentLockStatus, error)
+
+	// SetShadowBanned shadow-bans a customer or lifts their ban.
+	SetShadowBanned(ctx context.Context, customerID int, banned bool) (*models.ShadowBanStatus, error)
+
+	// IsShadowBanned reports whether a customer is shadow-banned.
+	IsShadowBanned(ctx context.Context, customerID int) (bool, error)
+
+	// RefreshCommentSummaries recomputes every film's comment summary.
+	RefreshCommentSummaries(ctx context.Context, stopwords []string, keywordLimit int) error
+
+	// GetCommentSummary retrieves a film's most recently computed comment summary.
+	GetCommentSummary(ctx context.Context, filmID int) (*models.CommentSummary, error)
+}
+
+// StaffRepositoryInterface defines the interface for staff-related database operations.
+type StaffRepositoryInterface interface {
+	// ListStaff retrieves staff members, limited to one store when storeID is non-zero.
+	ListStaff(ctx context.Context, storeID int) ([]models.Staff, error)
+
+	// CreateStaff adds a staff member with an already hashed password.
+	CreateStaff(ctx context.Context, staffReq models.StaffRequest, passwordHash string) (*models.Staff, error)
+
+	// DeactivateStaff marks a staff member inactive.
+	DeactivateStaff(ctx context.Context, staffID int) (*models.Staff, error)
+
+	// GetStaffCredentials retrieves a staff member and password hash by username.
+	GetStaffCredentials(ctx context.Context, username string) (*models.Staff, string, error)
+
+	// UpdateStaffPassword replaces a staff member's password hash.
+	UpdateStaffPassword(ctx context.Context, staffID int, passwordHash string) error
+}
+
+// CustomerRepositoryInterface defines the interface for customer account database operations.
+type CustomerRepositoryInterface interface {
+	// CreateCustomer adds a customer and their credentials with an already hashed password.
+	CreateCustomer(
+		ctx context.Context,
+		registerReq models.CustomerRegisterRequest,
+		passwordHash string,
+	) (*models.Customer, error)
+
+	// GetCustomerCredentials retrieves a customer and password hash by email.
+	GetCustomerCredentials(ctx context.Context, email string) (*models.Customer, string, error)
+
+	// EraseCustomerData erases a customer's personal data, recording it in the audit log under entry's actor.
+	EraseCustomerData(ctx context.Context, customerID int, entry models.AuditEntry) (*models.CustomerErasure, error)
+
+	// MergeCustomers moves one customer's records to another, recording it in the audit log under entry's actor.
+	MergeCustomers(ctx context.Context, sourceID, targetID int, entry models.AuditEntry) (*models.CustomerMerge, error)
+}
+
+// NotificationPreferenceRepositoryInterface defines the interface for notification preference database operations.
+type NotificationPreferenceRepositoryInterface interface {
+	// GetPreferences retrieves the preferences a customer has explicitly set.
+	GetPreferences(ctx context.Context, customerID int) (models.NotificationPreferences, error)
+
+	// SetPreferences stores the given preferences, leaving others unchanged.
+	SetPreferences(ctx context.Context, customerID int, prefs models.NotificationPreferences) error
+}
+
+// RentalRepositoryInterface defines the interface for rental-related database operations.
+type RentalRepositoryInterface interface {
+	// MarkOverdueRentals flags open rentals past their due date.
+	MarkOverdueRentals(ctx context.Context) (int64, error)
+
+	// ClaimDueReminders marks and returns open rentals due within window.
+	ClaimDueReminders(ctx context.Context, window time.Duration) ([]models.RentalDueReminder, error)
+
+	// RefreshTrendingFilms recomputes the trending film ranking.
+	RefreshTrendingFilms(ctx context.Context, since time.Time, limit int) error
+
+	// GetTrendingFilms retrieves the current trending film ranking.
+	GetTrendingFilms(ctx context.Context) ([]models.TrendingFilm, error)
+
+	// GetFilmRentals retrieves a page of a film's rentals, most recent first.
+	GetFilmRentals(
+		ctx context.Context,
+		filmID int,
+		filters models.RentalHistoryFilters,
+	) (*pagination.Paginated[models.RentalEvent], error)
+
+	// GetCustomerRentals retrieves a page of a customer's rentals, most
+	// recent first.
+	GetCustomerRentals(
+		ctx context.Context,
+		customerID int,
+		filters models.RentalHistoryFilters,
+	) (*pagination.Paginated[models.RentalEvent], error)
+
+	// GetOverdueFilms aggregates open rentals past their due date by film.
+	GetOverdueFilms(ctx context.Context, filters models.OverdueReportFilters) ([]models.OverdueFilm, error)
+
+	// ReturnRental records that an open rental has come back.
+	ReturnRental(ctx context.Context, rentalID int) (*models.RentalEvent, error)
+}
+
+// AvailabilityRepositoryInterface defines the interface for the alerts
+// customers ask for when a film they want is rented out.
+type AvailabilityRepositoryInterface interface {
+	// CreateAlert records a customer's alert for a film, or returns their
+	// pending one with created false.
+	CreateAlert(ctx context.Context, customerID, filmID int) (alert *models.AvailabilityAlert, created bool, err error)
+
+	// ClaimAlerts marks and returns a film's pending alerts.
+	ClaimAlerts(ctx context.Context, filmID int) ([]models.AvailabilityNotice, error)
+}
+
+// BackgroundJobRepositoryInterface defines the interface for persistent job
+// queue database operations.
+type BackgroundJobRepositoryInterface interface {
+	// InsertJob adds a pending job.
+	InsertJob(ctx context.Context, kind string, payload []byte, maxAttempts int) (*models.BackgroundJob, error)
+
+	// ClaimJob marks the next due job as running, returning nil if none is due.
+	ClaimJob(ctx context.Context, kinds []string, staleAfter time.Duration) (*models.BackgroundJob, error)
+
+	// CompleteJob marks a running job as succeeded.
+	CompleteJob(ctx context.Context, jobID int64) error
+
+	// RetryJobLater returns a failed job to pending, to run again at runAt.
+	RetryJobLater(ctx context.Context, jobID int64, lastError string, runAt time.Time) error
+
+	// BuryJob marks a job that has run out of attempts as dead.
+	BuryJob(ctx context.Context, jobID int64, lastError string) error
+
+	// ListJobs retrieves recently updated jobs matching filters.
+	ListJobs(ctx context.Context, filters models.BackgroundJobFilters) ([]models.BackgroundJob, error)
+
+	// GetJob retrieves a job by its ID.
+	GetJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error)
+
+	// RequeueJob returns a dead job to pending with its attempts reset.
+	RequeueJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error)
+
+	// PurgeJobs deletes succeeded and dead jobs last updated before the given time.
+	PurgeJobs(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookRepositoryInterface defines the interface for webhook subscription
+// and delivery database operations.
+type WebhookRepositoryInterface interface {
+	// ListSubscriptions retrieves every webhook subscription, without secrets.
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+
+	// CreateSubscription adds a webhook subscription signed with secret.
+	CreateSubscription(
+		ctx context.Context,
+		subscriptionReq models.WebhookSubscriptionRequest,
+		secret string,
+	) (*models.WebhookSubscription, error)
+
+	// DeleteSubscription removes a webhook subscription and its delivery history.
+	DeleteSubscription(ctx context.Context, subscriptionID int) error
+
+	// RotateSecret replaces a subscription's signing secret, keeping the old one valid for grace.
+	RotateSecret(
+		ctx context.Context,
+		subscriptionID int,
+		secret string,
+		grace time.Duration,
+	) (*models.WebhookSubscription, error)
+
+	// CreateDeliveries records a pending delivery of event to each subscribed endpoint.
+	CreateDeliveries(ctx context.Context, event string, payload []byte) ([]int64, error)
+
+	// GetDeliveryTarget retrieves a delivery with its endpoint and signing secrets.
+	GetDeliveryTarget(ctx context.Context, deliveryID int64) (*models.WebhookDeliveryTarget, error)
+
+	// RecordDeliveryAttempt stores the outcome of an attempt to send a delivery.
+	RecordDeliveryAttempt(
+		ctx context.Context,
+		deliveryID int64,
+		status string,
+		responseStatus *int,
+		lastError *string,
+	) error
+
+	// ListDeliveries retrieves a subscription's most recent deliveries.
+	ListDeliveries(
+		ctx context.Context,
+		subscriptionID int,
+		filters models.WebhookDeliveryFilters,
+	) ([]models.WebhookDelivery, error)
+
+	// ResetFailedDelivery returns a failed delivery to pending.
+	ResetFailedDelivery(ctx context.Context, subscriptionID int, deliveryID int64) (*models.WebhookDelivery, error)
+
+	// PurgeDeliveries deletes deliveries created before the given time.
+	PurgeDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// APIKeyRepositoryInterface defines the interface for API key and usage
+// database operations.
+type APIKeyRepositoryInterface interface {
+	// CreateAPIKey stores a new API key under its hash.
+	CreateAPIKey(ctx context.Context, keyReq models.APIKeyRequest, keyPrefix, keyHash string) (*models.APIKey, error)
+
+	// ListAPIKeys retrieves every API key, including revoked ones.
+	ListAPIKeys(ctx context.Context) ([]models.APIKey, error)
+
+	// RevokeAPIKey marks an API key revoked.
+	RevokeAPIKey(ctx context.Context, keyID int) (*models.APIKey, error)
+
+	// GetActiveAPIKeyByHash retrieves the unrevoked API key stored under keyHash.
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+
+	// IncrementUsage counts a request against a key's monthly usage unless that would exceed quota.
+	IncrementUsage(ctx context.Context, keyID int, period time.Time, quota int64) (int64, bool, error)
+
+	// GetUsage retrieves a key's request count for the month starting at period.
+	GetUsage(ctx context.Context, keyID int, period time.Time) (int64, error)
+}
+
+// InventoryRepositoryInterface defines the interface for inventory copy
+// database operations.
+type InventoryRepositoryInterface interface {
+	// AddInventory stocks new copies of a film at a store.
+	AddInventory(ctx context.Context, filmID, storeID, copies int) ([]models.InventoryCopy, error)
+
+	// GetInventoryCopy retrieves a copy, including a retired one.
+	GetInventoryCopy(ctx context.Context, inventoryID int) (*models.InventoryCopy, error)
+
+	// ListFilmInventory retrieves a film's copies matching filters.
+	ListFilmInventory(ctx context.Context, filmID int, filters models.InventoryFilters) ([]models.InventoryCopy, error)
+
+	// RetireInventory takes a copy that is not rented out of stock.
+	RetireInventory(ctx context.Context, inventoryID int) (*models.InventoryCopy, error)
+
+	// TransferInventory moves a copy to another store and records the move.
+	TransferInventory(
+		ctx context.Context,
+		inventoryID int,
+		transferReq models.InventoryTransferRequest,
+	) (*models.InventoryTransfer, error)
+
+	// ListInventoryTransfers retrieves a copy's transfers, most recent first.
+	ListInventoryTransfers(ctx context.Context, inventoryID int) ([]models.InventoryTransfer, error)
+}
+
+// PricingRepositoryInterface defines the interface for pricing rule database
+// operations.
+type PricingRepositoryInterface interface {
+	// CreatePricingRule stores a new pricing rule.
+	CreatePricingRule(ctx context.Context, ruleReq models.PricingRuleRequest) (*models.PricingRule, error)
+
+	// ListPricingRules retrieves every pricing rule, in the order they apply.
+	ListPricingRules(ctx context.Context) ([]models.PricingRule, error)
+
+	// DeletePricingRule deletes a pricing rule.
+	DeletePricingRule(ctx context.Context, ruleID int) error
+
+	// GetFilmPricing retrieves a film's rental rate and categories.
+	GetFilmPricing(ctx context.Context, filmID int) (*models.FilmPricing, error)
+
+	// GetCustomerStoreID retrieves the store a customer belongs to.
+	GetCustomerStoreID(ctx context.Context, customerID int) (int, error)
+}
+
+// CouponRepositoryInterface defines the interface for coupon database
+// operations.
+type CouponRepositoryInterface interface {
+	// CreateCoupon stores a new coupon.
+	CreateCoupon(ctx context.Context, couponReq models.CouponRequest) (*models.Coupon, error)
+
+	// ListCoupons retrieves every coupon with its redemption count.
+	ListCoupons(ctx context.Context) ([]models.Coupon, error)
+
+	// ValidateCoupon reports the discount a coupon would give a customer's checkout of amount.
+	ValidateCoupon(ctx context.Context, code string, customerID int, amount float64) (*models.CouponQuote, error)
+
+	// RedeemCoupon uses a coupon for a customer's checkout of amount.
+	RedeemCoupon(ctx context.Context, code string, customerID int, amount float64) (*models.CouponRedemption, error)
+}
+
+// CartRepositoryInterface defines the interface for cart and checkout
+// database operations.
+type CartRepositoryInterface interface {
+	// GetCart retrieves a customer's cart with each film's base rate and availability.
+	GetCart(ctx context.Context, customerID int) (*models.Cart, error)
+
+	// AddCartItem adds a film to a customer's cart.
+	AddCartItem(ctx context.Context, customerID, filmID int) error
+
+	// RemoveCartItem removes a film from a customer's cart.
+	RemoveCartItem(ctx context.Context, customerID, filmID int) error
+
+	// Checkout atomically turns cart films into rentals, redeeming couponCode and spending the gift card if given.
+	Checkout(
+		ctx context.Context,
+		customerID int,
+		lines []models.CheckoutLine,
+		couponCode, giftCardHash string,
+		rentalPoints int,
+	) (*models.Checkout, error)
+
+	// GetCheckout retrieves a checkout with its rentals.
+	GetCheckout(ctx context.Context, checkoutID int) (*models.Checkout, error)
+}
+
+// PaymentRepositoryInterface defines the interface for checkout payment
+// database operations.
+type PaymentRepositoryInterface interface {
+	// CreatePayment stores a pending payment for a checkout, or returns the one it already has.
+	CreatePayment(ctx context.Context, payment models.Payment) (*models.Payment, error)
+
+	// GetPayment retrieves a payment by its ID.
+	GetPayment(ctx context.Context, paymentID int) (*models.Payment, error)
+
+	// GetCheckoutPayment retrieves the payment for a checkout.
+	GetCheckoutPayment(ctx context.Context, checkoutID int) (*models.Payment, error)
+
+	// GetProviderPayment retrieves a payment by its provider's ID for it.
+	GetProviderPayment(ctx context.Context, provider, providerPaymentID string) (*models.Payment, error)
+
+	// CompletePayment marks a payment succeeded and records each rental's share of it.
+	CompletePayment(ctx context.Context, paymentID int, rentalPayments []models.RentalPayment) (*models.Payment, error)
+
+	// FailPayment marks a pending payment failed.
+	FailPayment(ctx context.Context, paymentID int) error
+
+	// CreateRefund stores a pending refund of a succeeded payment, or returns the one made with idempotencyKey.
+	CreateRefund(
+		ctx context.Context,
+		paymentID int,
+		idempotencyKey string,
+		refundReq models.RefundRequest,
+	) (*models.Refund, error)
+
+	// CompleteRefund marks a pending refund succeeded and records each rental's share of it.
+	CompleteRefund(
+		ctx context.Context,
+		refundID int,
+		providerRefundID string,
+		rentalRefunds []models.RentalPayment,
+	) (*models.Refund, error)
+
+	// FailRefund marks a pending refund failed and releases its amount.
+	FailRefund(ctx context.Context, refundID int) error
+}
+
+// ReceiptRepositoryInterface defines the interface for rental receipt and
+// invoice database operations.
+type ReceiptRepositoryInterface interface {
+	// GetRentalReceipt retrieves a rental with its customer, store, and payments.
+	GetRentalReceipt(ctx context.Context, rentalID int) (*models.Receipt, error)
+
+	// GetCustomerInvoices retrieves a page of the rentals a customer has paid for, most recent first.
+	GetCustomerInvoices(
+		ctx context.Context, customerID int, filters models.InvoiceFilters,
+	) (*pagination.Paginated[models.Invoice], error)
+}
+
+// LoyaltyRepositoryInterface defines the interface for loyalty ledger
+// database operations.
+type LoyaltyRepositoryInterface interface {
+	// AwardPoints credits a customer with points earned for a rental or comment, once per reference.
+	AwardPoints(ctx context.Context, customerID, points int, reason string, referenceID int) error
+
+	// GetBalance retrieves a customer's points and rental credit.
+	GetBalance(ctx context.Context, customerID int) (*models.LoyaltyBalance, error)
+
+	// GetHistory retrieves a page of a customer's ledger entries, most recent first.
+	GetHistory(
+		ctx context.Context, customerID int, filters models.LoyaltyHistoryFilters,
+	) (*pagination.Paginated[models.LoyaltyEntry], error)
+
+	// RedeemPoints exchanges points for rental credit, provided the customer has the points.
+	RedeemPoints(ctx context.Context, customerID, points int, credit float64) (*models.LoyaltyEntry, error)
+}
+
+// GiftCardRepositoryInterface defines the interface for gift card database
+// operations.
+type GiftCardRepositoryInterface interface {
+	// CreateGiftCard stores a pending gift card under codeHash.
+	CreateGiftCard(ctx context.Context, card models.GiftCard, codeHash string) (*models.GiftCard, error)
+
+	// SetGiftCardPayment records the provider payment a gift card is bought with.
+	SetGiftCardPayment(
+		ctx context.Context,
+		giftCardID int,
+		providerPaymentID, clientSecret string,
+	) (*models.GiftCard, error)
+
+	// GetGiftCardByCode retrieves the gift card stored under codeHash, with its balance.
+	GetGiftCardByCode(ctx context.Context, codeHash string) (*models.GiftCard, error)
+
+	// GetProviderGiftCard retrieves a gift card by its provider's ID for its payment.
+	GetProviderGiftCard(ctx context.Context, provider, providerPaymentID string) (*models.GiftCard, error)
+
+	// ActivateGiftCard marks a gift card paid for and credits it with its amount.
+	ActivateGiftCard(ctx context.Context, giftCardID int) (*models.GiftCard, error)
+
+	// FailGiftCard marks a pending gift card's purchase failed.
+	FailGiftCard(ctx context.Context, giftCardID int) error
+}
+
+// CollectionRepositoryInterface defines the interface for film collection
+// database operations.
+type CollectionRepositoryInterface interface {
+	// CreateCollection stores a new collection with its films in order.
+	CreateCollection(ctx context.Context, collectionReq models.CollectionRequest) (*models.Collection, error)
+
+	// ListCollections retrieves every collection by name, without their films.
+	ListCollections(ctx context.Context) ([]models.Collection, error)
+
+	// GetCollection retrieves a collection with its films in order.
+	GetCollection(ctx context.Context, collectionID int) (*models.Collection, error)
+
+	// UpdateCollection replaces a collection, returning it and the films it held before.
+	UpdateCollection(
+		ctx context.Context,
+		collectionID int,
+		collectionReq models.CollectionRequest,
+	) (*models.Collection, []int, error)
+
+	// DeleteCollection deletes a collection, returning the films it held.
+	DeleteCollection(ctx context.Context, collectionID int) ([]int, error)
+}
+
+// TagRepositoryInterface defines the interface for film tag database
+// operations.
+type TagRepositoryInterface interface {
+	// TagFilm adds a tag to a film, returning the film's tags.
+	TagFilm(ctx context.Context, filmID int, tag string) ([]string, error)
+
+	// UntagFilm removes a tag from a film, returning the film's tags.
+	UntagFilm(ctx context.Context, filmID int, tag string) ([]string, error)
+
+	// ListTags retrieves every tag in use with the number of films carrying it.
+	ListTags(ctx context.Context) ([]models.TagCount, error)
+}
+
+// CustomerListRepositoryInterface defines the interface for customer list
+// database operations. Changes are scoped to the owning customer.
+type CustomerListRepositoryInterface interface {
+	// CreateList stores a new, empty list for a customer.
+	CreateList(ctx context.Context, customerID int, listReq models.CustomerListRequest) (*models.CustomerList, error)
+
+	// ListCustomerLists retrieves a customer's lists, without their entries.
+	ListCustomerLists(ctx context.Context, customerID int) ([]models.CustomerList, error)
+
+	// GetList retrieves a list with its entries in order, whoever owns it.
+	GetList(ctx context.Context, listID int) (*models.CustomerList, error)
+
+	// UpdateList replaces the name, description, and visibility of a customer's list.
+	UpdateList(
+		ctx context.Context,
+		customerID, listID int,
+		listReq models.CustomerListRequest,
+	) (*models.CustomerList, error)
+
+	// DeleteList deletes a customer's list and its entries.
+	DeleteList(ctx context.Context, customerID, listID int) error
+
+	// AddListEntry adds a film to the end of a customer's list.
+	AddListEntry(ctx context.Context, customerID, listID, filmID int) (*models.CustomerList, error)
+
+	// RemoveListEntry removes a film from a customer's list.
+	RemoveListEntry(ctx context.Context, customerID, listID, filmID int) (*models.CustomerList, error)
+
+	// ReorderListEntries puts the films on a customer's list in the given order.
+	ReorderListEntries(ctx context.Context, customerID, listID int, filmIDs []int) (*models.CustomerList, error)
+}
+
+// ActivityRepositoryInterface defines the interface for customer follows
+// and activity database operations.
+type ActivityRepositoryInterface interface {
+	// Follow makes followerID follow followedID.
+	Follow(ctx context.Context, followerID, followedID int) error
+
+	// Unfollow stops followerID following followedID.
+	Unfollow(ctx context.Context, followerID, followedID int) error
+
+	// ListFollowing retrieves the customers a customer follows.
+	ListFollowing(ctx context.Context, customerID int) ([]models.Follow, error)
+
+	// RecordActivity adds an entry to a customer's public activity.
+	RecordActivity(ctx context.Context, activity models.Activity) error
+
+	// GetFeed retrieves a page of the activity of the customers a customer follows.
+	GetFeed(
+		ctx context.Context, customerID int, filters models.ActivityFeedFilters,
+	) (*pagination.Paginated[models.Activity], error)
+}
+
+// ShortLinkRepositoryInterface defines the interface for short link
+// database operations.
+type ShortLinkRepositoryInterface interface {
+	// CreateShortLink stores a short link to filmID under code.
+	CreateShortLink(ctx context.Context, code string, filmID int, campaign *string) (*models.ShortLink, error)
+
+	// ListFilmShortLinks retrieves the short links to a film, newest first.
+	ListFilmShortLinks(ctx context.Context, filmID int) ([]models.ShortLink, error)
+
+	// RecordClick counts a click on a short link, returning the film it links to.
+	RecordClick(ctx context.Context, code string) (int, error)
+}
+
+// DashboardRepositoryInterface defines the interface for the database
+// queries behind the admin dashboard.
+type DashboardRepositoryInterface interface {
+	// CountFilms counts the films in the catalog.
+	CountFilms(ctx context.Context) (int, error)
+
+	// CountRentalsToday counts the rentals made since the start of the current day.
+	CountRentalsToday(ctx context.Context) (int, error)
+
+	// CountPendingComments counts the top-level comments nobody has replied to yet.
+	CountPendingComments(ctx context.Context) (int, error)
+
+	// GetRevenueThisMonth sums the payments taken since the start of the current month, less refunds.
+	GetRevenueThisMonth(ctx context.Context) (float64, error)
+
+	// GetCategoryStats aggregates the films in a category, their rentals, and their revenue.
+	GetCategoryStats(ctx context.Context, categoryID int) (*models.CategoryStats, error)
+}
+
+// SchemaRepositoryInterface defines the interface for reading the live
+// database's migration version.
+type SchemaRepositoryInterface interface {
+	// GetSchemaVersion returns the newest migration version applied, or 0 if none.
+	GetSchemaVersion(ctx context.Context) (int64, error)
+}
+
+// CustomerExportRepositoryInterface defines the interface for exports of
+// customers' personal data.
+type CustomerExportRepositoryInterface interface {
+	// CreateExport stores a pending export in format, deleting the customer's earlier exports in that format.
+	CreateExport(ctx context.Context, customerID int, format string) (*models.CustomerExport, error)
+
+	// GetLatestExport retrieves a customer's newest export in format created after since.
+	GetLatestExport(ctx context.Context, customerID int, format string, since time.Time) (*models.CustomerExport, error)
+
+	// GetExport retrieves one of a customer's exports.
+	GetExport(ctx context.Context, customerID, exportID int) (*models.CustomerExport, error)
+
+	// GetExportFile retrieves the file built for a ready export.
+	GetExportFile(ctx context.Context, exportID int) ([]byte, error)
+
+	// CompleteExport stores the file built for an export and marks it ready.
+	CompleteExport(ctx context.Context, exportID int, data []byte) error
+
+	// GetCustomerData retrieves the personal data held about a customer.
+	GetCustomerData(ctx context.Context, customerID int) (*models.CustomerData, error)
+}
+
+// SearchRepositoryInterface defines the interface for recording title
+// searches that found no films and suggesting titles for them.
+type SearchRepositoryInterface interface {
+	// RecordMiss counts a search for term that found no films.
+	RecordMiss(ctx context.Context, term string) error
+
+	// ListMisses returns a page of the searches that found no films, most often made first.
+	ListMisses(ctx context.Context, params pagination.Params) (*pagination.Paginated[models.SearchMiss], error)
+
+	// SuggestTitles returns up to limit titles of published films similar to term.
+	SuggestTitles(ctx context.Context, term string, limit int) ([]string, error)
+}
+
+// SavedSearchRepositoryInterface defines the interface for customers' saved
+// searches. Changes are scoped to the owning customer.
+type SavedSearchRepositoryInterface interface {
+	// CreateSavedSearch stores a search for a customer, unless they have models.MaxSavedSearches.
+	CreateSavedSearch(
+		ctx context.Context,
+		customerID int,
+		searchReq models.SavedSearchRequest,
+	) (*models.SavedSearch, error)
+
+	// ListSavedSearches retrieves a customer's saved searches.
+	ListSavedSearches(ctx context.Context, customerID int) ([]models.SavedSearch, error)
+
+	// DeleteSavedSearch deletes one of a customer's saved searches.
+	DeleteSavedSearch(ctx context.Context, customerID, searchID int) error
+
+	// ListAlertingSearches retrieves the searches to check for new films, and the time to check up to.
+	ListAlertingSearches(ctx context.Context) ([]models.SavedSearchAlert, time.Time, error)
+
+	// MarkSearchChecked records that a search has been checked for films published up to checkedAt.
+	MarkSearchChecked(ctx context.Context, searchID int, checkedAt time.Time) error
+}
+
+// HomeRepositoryInterface defines the interface for the database queries
+// behind customers' home feeds.
+type HomeRepositoryInterface interface {
+	// GetRecommendedFilms retrieves films rented by customers who rented the same films as the customer.
+	GetRecommendedFilms(ctx context.Context, customerID, limit int) ([]models.HomeFilm, error)
+
+	// GetFavoriteCategories retrieves the categories the customer has rented the most films in.
+	GetFavoriteCategories(ctx context.Context, customerID, limit int) ([]string, error)
+
+	// GetNewFilmsInCategories retrieves the most recently published films in categories the customer has not rented.
+	GetNewFilmsInCategories(ctx context.Context, customerID int, categories []string, limit int) ([]models.HomeFilm, error)
+}
+
+// GuestRepositoryInterface defines the interface for anonymous guest
+// sessions.
+type GuestRepositoryInterface interface {
+	// CreateSession starts a guest session and returns its ID.
+	CreateSession(ctx context.Context) (int, error)
+
+	// RecordComment records that a guest posted a comment.
+	RecordComment(ctx context.Context, guestID, commentID int) error
+
+	// ListWatchlist retrieves the films on a guest's watchlist.
+	ListWatchlist(ctx context.Context, guestID int) ([]models.GuestWatchlistEntry, error)
+
+	// AddToWatchlist adds a film to a guest's watchlist.
+	AddToWatchlist(ctx context.Context, guestID, filmID int) error
+
+	// RemoveFromWatchlist removes a film from a guest's watchlist.
+	RemoveFromWatchlist(ctx context.Context, guestID, filmID int) error
+
+	// MergeSession moves a guest's comments and watchlist into a customer's account.
+	MergeSession(ctx context.Context, guestID, customerID int) (*models.GuestMerge, error)
+}
+
+// AuditRepositoryInterface defines the interface for reading the audit log.
+type AuditRepositoryInterface interface {
+	// ListStaffActivity retrieves a page of the audit log entries for a staff member's actions, newest first.
+	ListStaffActivity(
+		ctx context.Context,
+		staffID int,
+		filters models.AuditFilters,
+	) (*pagination.Paginated[models.AuditEntry], error)
+}
+
+// SessionRepositoryInterface defines the interface for login session database operations.
+type SessionRepositoryInterface interface {
+	// CreateSession starts a login session whose refresh token is stored as tokenHash.
+	CreateSession(ctx context.Context, session models.AuthSession, tokenHash string) (*models.AuthSession, error)
+
+	// RotateRefreshToken replaces a session's refresh token, revoking the session if the token was already used.
+	RotateRefreshToken(
+		ctx context.Context,
+		tokenHash, newHash string,
+		client models.SessionClient,
+	) (*models.AuthSession, error)
+
+	// RevokeSession revokes a live login session.
+	RevokeSession(ctx context.Context, sessionID int64) error
+
+	// ListActiveSessions retrieves the live login sessions of a staff member or customer.
+	ListActiveSessions(ctx context.Context, role string, subjectID int) ([]models.AuthSession, error)
+
+	// RevokeSubjectSession revokes a live login session of a staff member or customer.
+	RevokeSubjectSession(ctx context.Context, role string, subjectID int, sessionID int64) error
+
+	// IsSessionRevoked reports whether a login session has been revoked or has expired.
+	IsSessionRevoked(ctx context.Context, sessionID int64) (bool, error)
+}
+
+// TwoFactorRepositoryInterface defines the interface for staff two-factor database operations.
+type TwoFactorRepositoryInterface interface {
+	// BeginEnrollment stores a pending TOTP secret and backup codes, returning the staff member's username.
+	BeginEnrollment(ctx context.Context, staffID int, secret string, codeHashes []string) (string, error)
+
+	// GetTwoFactor retrieves a staff member's second factor, pending or enabled.
+	GetTwoFactor(ctx context.Context, staffID int) (*models.StaffTwoFactor, error)
+
+	// UseTOTPStep records an accepted TOTP code's time step, enabling a pending factor.
+	UseTOTPStep(ctx context.Context, staffID int, step int64) (bool, error)
 
-	// GetCommentsByFilmID retrieves all comments for a specific film.
-	GetCommentsByFilmID(filmID int) ([]models.Comment, error)
+	// UseBackupCode spends an unused backup code.
+	UseBackupCode(ctx context.Context, staffID int, codeHash string) (bool, error)
 }
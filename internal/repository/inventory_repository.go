@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// rentalLostAt is the SQL expression for when an open rental's copy is
+// considered lost, given rental r joined to its film f: twice the rental
+// duration after it was rented.
+const rentalLostAt = "r.rental_date + 2 * f.rental_duration * INTERVAL '1 day'"
+
+// inventoryStatus is the SQL expression for an InventoryCopy status, given
+// the columns of inventoryJoins.
+const inventoryStatus = `CASE
+			WHEN i.retired_at IS NOT NULL THEN 'retired'
+			WHEN r.rental_id IS NULL THEN 'in_stock'
+			WHEN ` + rentalLostAt + ` < NOW() THEN 'lost'
+			ELSE 'rented'
+		END`
+
+// inventoryColumns lists the inventory columns scanned by scanInventoryCopy,
+// in order, selected from inventory i and inventoryJoins.
+const inventoryColumns = `i.inventory_id, i.film_id, i.store_id, ` + inventoryStatus + `,
+		r.rental_id, r.customer_id, ` + rentalDueAt + `, i.retired_at, i.last_update`
+
+// inventoryJoins joins inventory i to its film f and its open rental r, if
+// any.
+const inventoryJoins = `
+		JOIN film f ON f.film_id = i.film_id
+		LEFT JOIN LATERAL (
+			SELECT rental_id, customer_id, rental_date
+			FROM rental
+			WHERE inventory_id = i.inventory_id AND return_date IS NULL
+			ORDER BY rental_date DESC
+			LIMIT 1
+		) r ON true`
+
+// InventoryRepository handles database operations for inventory copies.
+type InventoryRepository struct {
+	db *database.DB
+}
+
+// NewInventoryRepository creates a new inventory repository.
+func NewInventoryRepository(db *database.DB) *InventoryRepository {
+	return &InventoryRepository{db: db}
+}
+
+// AddInventory stocks copies new copies of a film at a store.
+func (r *InventoryRepository) AddInventory(
+	ctx context.Context,
+	filmID, storeID, copies int,
+) ([]models.InventoryCopy, error) {
+	if err := checkFilmExists(ctx, r.db, "inventory.film_exists", filmID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH added AS (
+			INSERT INTO inventory (film_id, store_id, last_update)
+			SELECT $1, $2, NOW() FROM generate_series(1, $3)
+			RETURNING *
+		)
+		SELECT ` + inventoryColumns + ` FROM added i` + inventoryJoins + `
+		ORDER BY i.inventory_id`
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "inventory.add"),
+		query, filmID, storeID, copies)
+	if err != nil {
+		return nil, fmt.Errorf("error adding inventory: %w", constraintError(err, err))
+	}
+	defer rows.Close()
+
+	return scanInventoryCopies(rows)
+}
+
+// GetInventoryCopy retrieves a copy, including a retired one.
+func (r *InventoryRepository) GetInventoryCopy(ctx context.Context, inventoryID int) (*models.InventoryCopy, error) {
+	query := "SELECT " + inventoryColumns + " FROM inventory i" + inventoryJoins + " WHERE i.inventory_id = $1"
+
+	row := r.db.QueryRowContext(database.WithQueryName(ctx, "inventory.get"), query, inventoryID)
+	inventoryCopy, err := scanInventoryCopy(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInventoryNotFound
+		}
+		return nil, fmt.Errorf("error querying inventory copy: %w", err)
+	}
+
+	return inventoryCopy, nil
+}
+
+// ListFilmInventory retrieves a film's copies matching filters, by store.
+func (r *InventoryRepository) ListFilmInventory(
+	ctx context.Context,
+	filmID int,
+	filters models.InventoryFilters,
+) ([]models.InventoryCopy, error) {
+	if err := checkFilmExists(ctx, r.db, "inventory.film_exists", filmID); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + inventoryColumns + " FROM inventory i" + inventoryJoins + " WHERE i.film_id = $1"
+	args := []any{filmID}
+	if filters.StoreID != 0 {
+		args = append(args, filters.StoreID)
+		query += fmt.Sprintf(" AND i.store_id = $%d", len(args))
+	}
+	if filters.Status != "" {
+		args = append(args, filters.Status)
+		query += fmt.Sprintf(" AND "+inventoryStatus+" = $%d", len(args))
+	}
+	query += " ORDER BY i.store_id, i.inventory_id"
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "inventory.list"), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying inventory: %w", err)
+	}
+	defer rows.Close()
+
+	return scanInventoryCopies(rows)
+}
+
+// RetireInventory takes a copy out of stock, keeping its rental history.
+// Copies rented out are rejected with ErrInventoryRented unless they are
+// lost; retiring a retired copy changes nothing.
+func (r *InventoryRepository) RetireInventory(ctx context.Context, inventoryID int) (*models.InventoryCopy, error) {
+	ctx = database.WithQueryName(ctx, "inventory.retire")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "inventory.retire")
+
+	_, retired, err := lockInventoryCopy(ctx, tx, inventoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !retired {
+		var rented bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM rental r
+				JOIN inventory i ON i.inventory_id = r.inventory_id
+				JOIN film f ON f.film_id = i.film_id
+				WHERE r.inventory_id = $1 AND r.return_date IS NULL AND `+rentalLostAt+` >= NOW()
+			)`, inventoryID).Scan(&rented)
+		if err != nil {
+			return nil, fmt.Errorf("error checking inventory copy rental: %w", err)
+		}
+		if rented {
+			return nil, ErrInventoryRented
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE inventory SET retired_at = NOW(), last_update = NOW() WHERE inventory_id = $1", inventoryID)
+		if err != nil {
+			return nil, fmt.Errorf("error retiring inventory copy: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing inventory retirement: %w", err)
+	}
+
+	return r.GetInventoryCopy(ctx, inventoryID)
+}
+
+// TransferInventory moves a copy to another store and records the move, in
+// one transaction. Copies that are retired, rented out, or already at the
+// store are rejected.
+func (r *InventoryRepository) TransferInventory(
+	ctx context.Context,
+	inventoryID int,
+	transferReq models.InventoryTransferRequest,
+) (*models.InventoryTransfer, error) {
+	ctx = database.WithQueryName(ctx, "inventory.transfer")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "inventory.transfer")
+
+	fromStoreID, retired, err := lockInventoryCopy(ctx, tx, inventoryID)
+	if err != nil {
+		return nil, err
+	}
+	var rented bool
+	err = tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM rental WHERE inventory_id = $1 AND return_date IS NULL)", inventoryID).
+		Scan(&rented)
+	if err != nil {
+		return nil, fmt.Errorf("error checking inventory copy rental: %w", err)
+	}
+	switch {
+	case retired:
+		return nil, ErrInventoryRetired
+	case rented:
+		return nil, ErrInventoryRented
+	case fromStoreID == transferReq.ToStoreID:
+		return nil, ErrInventoryAtStore
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE inventory SET store_id = $2, last_update = NOW() WHERE inventory_id = $1",
+		inventoryID, transferReq.ToStoreID)
+	if err != nil {
+		return nil, fmt.Errorf("error moving inventory copy: %w", constraintError(err, err))
+	}
+
+	transfer := models.InventoryTransfer{
+		InventoryID: inventoryID,
+		FromStoreID: fromStoreID,
+		ToStoreID:   transferReq.ToStoreID,
+		Reason:      transferReq.Reason,
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO inventory_transfers (inventory_id, from_store_id, to_store_id, reason)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING id, transferred_at`,
+		inventoryID, fromStoreID, transferReq.ToStoreID, transferReq.Reason,
+	).Scan(&transfer.ID, &transfer.TransferredAt)
+	if err != nil {
+		return nil, fmt.Errorf("error recording inventory transfer: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing inventory transfer: %w", err)
+	}
+
+	return &transfer, nil
+}
+
+// ListInventoryTransfers retrieves a copy's transfers, most recent first.
+func (r *InventoryRepository) ListInventoryTransfers(
+	ctx context.Context,
+	inventoryID int,
+) ([]models.InventoryTransfer, error) {
+	if _, err := r.GetInventoryCopy(ctx, inventoryID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, inventory_id, from_store_id, to_store_id, COALESCE(reason, ''), transferred_at
+		FROM inventory_transfers
+		WHERE inventory_id = $1
+		ORDER BY transferred_at DESC, id DESC`
+
+	listCtx := database.WithQueryName(ctx, "inventory.transfers")
+	rows, err := r.db.QueryContext(listCtx, query, inventoryID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying inventory transfers: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := []models.InventoryTransfer{}
+	for rows.Next() {
+		var transfer models.InventoryTransfer
+		if scanErr := rows.Scan(
+			&transfer.ID, &transfer.InventoryID, &transfer.FromStoreID, &transfer.ToStoreID,
+			&transfer.Reason, &transfer.TransferredAt,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning inventory transfer: %w", scanErr)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating inventory transfers: %w", rowsErr)
+	}
+
+	return transfers, nil
+}
+
+// scanInventoryCopy scans inventoryColumns from row.
+func scanInventoryCopy(row interface{ Scan(dest ...any) error }) (*models.InventoryCopy, error) {
+	var inventoryCopy models.InventoryCopy
+	err := row.Scan(
+		&inventoryCopy.InventoryID, &inventoryCopy.FilmID, &inventoryCopy.StoreID, &inventoryCopy.Status,
+		&inventoryCopy.RentalID, &inventoryCopy.CustomerID, &inventoryCopy.DueAt, &inventoryCopy.RetiredAt,
+		&inventoryCopy.LastUpdate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &inventoryCopy, nil
+}
+
+// scanInventoryCopies scans every row of rows with scanInventoryCopy.
+func scanInventoryCopies(rows *sql.Rows) ([]models.InventoryCopy, error) {
+	copies := []models.InventoryCopy{}
+	for rows.Next() {
+		inventoryCopy, err := scanInventoryCopy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning inventory copy: %w", err)
+		}
+		copies = append(copies, *inventoryCopy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inventory copies: %w", err)
+	}
+
+	return copies, nil
+}
+
+// lockInventoryCopy locks a copy within tx, returning the store it is at
+// and whether it is retired. Locking the copy blocks new rentals of it,
+// whose foreign key check needs a share lock on the row, until tx ends.
+// Whether it is rented out must be checked in a later statement: one started
+// before the lock was granted does not see rentals committed while it
+// waited.
+func lockInventoryCopy(ctx context.Context, tx *sql.Tx, inventoryID int) (int, bool, error) {
+	var storeID int
+	var retired bool
+	err := tx.QueryRowContext(ctx,
+		"SELECT store_id, retired_at IS NOT NULL FROM inventory WHERE inventory_id = $1 FOR UPDATE", inventoryID).
+		Scan(&storeID, &retired)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, ErrInventoryNotFound
+		}
+		return 0, false, fmt.Errorf("error locking inventory copy: %w", err)
+	}
+	return storeID, retired, nil
+}
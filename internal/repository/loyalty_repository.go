@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// loyaltyEntryColumns lists the loyalty_ledger columns scanned by
+// scanLoyaltyEntry, in order.
+const loyaltyEntryColumns = "id, customer_id, points, credit::float8, reason, reference_id, created_at"
+
+// LoyaltyRepository handles database operations for loyalty points. Every
+// change to a balance is an entry in the loyalty ledger.
+type LoyaltyRepository struct {
+	db *database.DB
+}
+
+// NewLoyaltyRepository creates a new loyalty repository.
+func NewLoyaltyRepository(db *database.DB) *LoyaltyRepository {
+	return &LoyaltyRepository{db: db}
+}
+
+// AwardPoints credits a customer with points earned for the rental or
+// comment referenceID. Each rental or comment earns points once; awarding
+// it again changes nothing.
+func (r *LoyaltyRepository) AwardPoints(
+	ctx context.Context,
+	customerID, points int,
+	reason string,
+	referenceID int,
+) error {
+	ctx = database.WithQueryName(ctx, "loyalty.award")
+	err := awardLoyaltyPoints(ctx, r.db, customerID, points, reason, referenceID)
+	if errors.Is(err, ErrInvalidReference) {
+		return ErrCustomerNotFound
+	}
+	return err
+}
+
+// GetBalance retrieves a customer's points and rental credit.
+func (r *LoyaltyRepository) GetBalance(ctx context.Context, customerID int) (*models.LoyaltyBalance, error) {
+	ctx = database.WithQueryName(ctx, "loyalty.balance")
+	balance := &models.LoyaltyBalance{CustomerID: customerID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(l.points), 0), COALESCE(SUM(l.credit), 0)::float8
+		FROM customer c
+		LEFT JOIN loyalty_ledger l ON l.customer_id = c.customer_id
+		WHERE c.customer_id = $1
+		GROUP BY c.customer_id`, customerID,
+	).Scan(&balance.Points, &balance.Credit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error querying loyalty balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+// GetHistory retrieves a page of a customer's ledger entries, most recent
+// first, with the total number of entries.
+func (r *LoyaltyRepository) GetHistory(
+	ctx context.Context,
+	customerID int,
+	filters models.LoyaltyHistoryFilters,
+) (*pagination.Paginated[models.LoyaltyEntry], error) {
+	var customerExists bool
+	existsCtx := database.WithQueryName(ctx, "loyalty.customer_exists")
+	err := r.db.QueryRowContext(existsCtx, "SELECT EXISTS(SELECT 1 FROM customer WHERE customer_id = $1)", customerID).
+		Scan(&customerExists)
+	if err != nil {
+		return nil, fmt.Errorf("error checking customer existence: %w", err)
+	}
+	if !customerExists {
+		return nil, ErrCustomerNotFound
+	}
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "loyalty.history"), `
+		SELECT `+loyaltyEntryColumns+`, COUNT(*) OVER()
+		FROM loyalty_ledger
+		WHERE customer_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, customerID, filters.Limit, (filters.Page-1)*filters.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying loyalty history: %w", err)
+	}
+	defer rows.Close()
+
+	history := pagination.New([]models.LoyaltyEntry{}, 0, pagination.Params{Page: filters.Page, Limit: filters.Limit})
+	for rows.Next() {
+		var entry models.LoyaltyEntry
+		if scanErr := rows.Scan(
+			&entry.ID, &entry.CustomerID, &entry.Points, &entry.Credit, &entry.Reason, &entry.ReferenceID,
+			&entry.CreatedAt, &history.Total,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning loyalty entry: %w", scanErr)
+		}
+		history.Items = append(history.Items, entry)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating loyalty history: %w", rowsErr)
+	}
+
+	return history, nil
+}
+
+// RedeemPoints exchanges points for rental credit in one ledger entry,
+// provided the customer has the points.
+func (r *LoyaltyRepository) RedeemPoints(
+	ctx context.Context,
+	customerID, points int,
+	credit float64,
+) (*models.LoyaltyEntry, error) {
+	ctx = database.WithQueryName(ctx, "loyalty.redeem")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "loyalty.redeem")
+
+	balance, err := lockLoyaltyBalance(ctx, tx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Points < points {
+		return nil, ErrInsufficientPoints
+	}
+
+	entry, err := scanLoyaltyEntry(tx.QueryRowContext(ctx, `
+		INSERT INTO loyalty_ledger (customer_id, points, credit, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+loyaltyEntryColumns, customerID, -points, credit, models.LoyaltyReasonRedemption))
+	if err != nil {
+		return nil, fmt.Errorf("error recording redemption: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing redemption: %w", err)
+	}
+
+	return entry, nil
+}
+
+// execer runs statements; both *database.DB and *sql.Tx satisfy it.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// awardLoyaltyPoints adds a ledger entry for points earned for referenceID,
+// unless that rental or comment has already earned them.
+func awardLoyaltyPoints(ctx context.Context, q execer, customerID, points int, reason string, referenceID int) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO loyalty_ledger (customer_id, points, reason, reference_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (reason, reference_id) WHERE reference_id IS NOT NULL DO NOTHING`,
+		customerID, points, reason, referenceID)
+	if err != nil {
+		return fmt.Errorf("error awarding loyalty points: %w", constraintError(err, err))
+	}
+	return nil
+}
+
+// lockLoyaltyBalance locks a customer for changes to their loyalty balance,
+// so concurrent redemptions and checkouts cannot spend it twice, and
+// returns the balance.
+func lockLoyaltyBalance(ctx context.Context, tx *sql.Tx, customerID int) (*models.LoyaltyBalance, error) {
+	var locked int
+	err := tx.QueryRowContext(ctx,
+		"SELECT customer_id FROM customer WHERE customer_id = $1 FOR NO KEY UPDATE", customerID).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error locking customer: %w", err)
+	}
+
+	balance := &models.LoyaltyBalance{CustomerID: customerID}
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(points), 0), COALESCE(SUM(credit), 0)::float8
+		FROM loyalty_ledger
+		WHERE customer_id = $1`, customerID,
+	).Scan(&balance.Points, &balance.Credit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying loyalty balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+// spendLoyaltyCredit takes as much of a customer's rental credit as covers
+// amount, returning what was taken. The ledger entry for it is added by
+// recordLoyaltyCredit once the checkout has an ID.
+func spendLoyaltyCredit(ctx context.Context, tx *sql.Tx, customerID int, amount float64) (float64, error) {
+	balance, err := lockLoyaltyBalance(ctx, tx, customerID)
+	if err != nil {
+		return 0, err
+	}
+	return math.Max(0, math.Min(balance.Credit, amount)), nil
+}
+
+// recordLoyaltyCredit adds the ledger entry for credit spent on checkoutID.
+func recordLoyaltyCredit(ctx context.Context, tx *sql.Tx, customerID, checkoutID int, credit float64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO loyalty_ledger (customer_id, credit, reason, reference_id)
+		VALUES ($1, $2, $3, $4)`, customerID, -credit, models.LoyaltyReasonCheckout, checkoutID)
+	if err != nil {
+		return fmt.Errorf("error recording spent credit: %w", err)
+	}
+	return nil
+}
+
+// scanLoyaltyEntry scans loyaltyEntryColumns from row.
+func scanLoyaltyEntry(row interface{ Scan(dest ...any) error }) (*models.LoyaltyEntry, error) {
+	var entry models.LoyaltyEntry
+	err := row.Scan(
+		&entry.ID, &entry.CustomerID, &entry.Points, &entry.Credit, &entry.Reason, &entry.ReferenceID,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
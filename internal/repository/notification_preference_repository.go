@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// NotificationPreferenceRepository handles database operations for
+// notification preferences.
+type NotificationPreferenceRepository struct {
+	db *database.DB
+}
+
+// NewNotificationPreferenceRepository creates a new notification preference repository.
+func NewNotificationPreferenceRepository(db *database.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// GetPreferences retrieves the preferences a customer has explicitly set.
+func (r *NotificationPreferenceRepository) GetPreferences(
+	ctx context.Context,
+	customerID int,
+) (models.NotificationPreferences, error) {
+	query := "SELECT event_type, channel, enabled FROM notification_preferences WHERE customer_id = $1"
+
+	listCtx := database.WithQueryName(ctx, "notification_preferences.list")
+	rows, err := r.db.QueryContext(listCtx, query, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	prefs := models.NotificationPreferences{}
+	for rows.Next() {
+		var event, channel string
+		var enabled bool
+		if scanErr := rows.Scan(&event, &channel, &enabled); scanErr != nil {
+			return nil, fmt.Errorf("error scanning notification preference: %w", scanErr)
+		}
+		if prefs[event] == nil {
+			prefs[event] = map[string]bool{}
+		}
+		prefs[event][channel] = enabled
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating notification preferences: %w", rowsErr)
+	}
+
+	return prefs, nil
+}
+
+// SetPreferences upserts the given preferences in one transaction.
+func (r *NotificationPreferenceRepository) SetPreferences(
+	ctx context.Context,
+	customerID int,
+	prefs models.NotificationPreferences,
+) error {
+	query := `
+		INSERT INTO notification_preferences (customer_id, event_type, channel, enabled, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (customer_id, event_type, channel)
+		DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at
+	`
+
+	ctx = database.WithQueryName(ctx, "notification_preferences.upsert")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "notification_preferences.upsert")
+
+	for event, channels := range prefs {
+		for channel, enabled := range channels {
+			if _, err = tx.ExecContext(ctx, query, customerID, event, channel, enabled); err != nil {
+				return fmt.Errorf("error storing notification preference: %w", err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing notification preferences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// paymentColumns lists the checkout_payments columns scanned by
+// scanPayment, in order.
+const paymentColumns = `id, checkout_id, provider, provider_payment_id, client_secret, amount::float8,
+		refunded_amount::float8, currency, status, created_at, updated_at`
+
+// refundColumns lists the payment_refunds columns scanned by scanRefund, in
+// order.
+const refundColumns = `id, payment_id, amount::float8, reason, COALESCE(provider_refund_id, ''), status,
+		created_at, updated_at`
+
+// PaymentRepository handles database operations for checkout payments.
+type PaymentRepository struct {
+	db *database.DB
+}
+
+// NewPaymentRepository creates a new payment repository.
+func NewPaymentRepository(db *database.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// CreatePayment stores a pending payment for a checkout. Should the
+// checkout already have one, as when two requests race to start paying, the
+// existing payment is returned instead.
+func (r *PaymentRepository) CreatePayment(ctx context.Context, payment models.Payment) (*models.Payment, error) {
+	ctx = database.WithQueryName(ctx, "payments.create")
+	created, err := scanPayment(r.db.QueryRowContext(ctx, `
+		INSERT INTO checkout_payments (checkout_id, provider, provider_payment_id, client_secret, amount, currency)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (checkout_id) DO NOTHING
+		RETURNING `+paymentColumns,
+		payment.CheckoutID, payment.Provider, payment.ProviderPaymentID, payment.ClientSecret,
+		payment.Amount, payment.Currency,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.GetCheckoutPayment(ctx, payment.CheckoutID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error inserting payment: %w", constraintError(err, err))
+	}
+
+	return created, nil
+}
+
+// GetPayment retrieves a payment by its ID.
+func (r *PaymentRepository) GetPayment(ctx context.Context, paymentID int) (*models.Payment, error) {
+	ctx = database.WithQueryName(ctx, "payments.get")
+	payment, err := scanPayment(r.db.QueryRowContext(ctx,
+		"SELECT "+paymentColumns+" FROM checkout_payments WHERE id = $1", paymentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("error querying payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// GetCheckoutPayment retrieves the payment for a checkout.
+func (r *PaymentRepository) GetCheckoutPayment(ctx context.Context, checkoutID int) (*models.Payment, error) {
+	ctx = database.WithQueryName(ctx, "payments.get_for_checkout")
+	payment, err := scanPayment(r.db.QueryRowContext(ctx,
+		"SELECT "+paymentColumns+" FROM checkout_payments WHERE checkout_id = $1", checkoutID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("error querying payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// GetProviderPayment retrieves a payment by its provider's ID for it.
+func (r *PaymentRepository) GetProviderPayment(
+	ctx context.Context,
+	provider, providerPaymentID string,
+) (*models.Payment, error) {
+	ctx = database.WithQueryName(ctx, "payments.get_for_provider")
+	payment, err := scanPayment(r.db.QueryRowContext(ctx,
+		"SELECT "+paymentColumns+" FROM checkout_payments WHERE provider = $1 AND provider_payment_id = $2",
+		provider, providerPaymentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("error querying payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// CompletePayment marks a payment succeeded and records each rental's share
+// of it in the payment table, taken by the checkout store's manager.
+// Completing a payment that already succeeded changes nothing, so repeated
+// provider callbacks are harmless.
+func (r *PaymentRepository) CompletePayment(
+	ctx context.Context,
+	paymentID int,
+	rentalPayments []models.RentalPayment,
+) (*models.Payment, error) {
+	ctx = database.WithQueryName(ctx, "payments.complete")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "payments.complete")
+
+	payment, err := scanPayment(tx.QueryRowContext(ctx,
+		"SELECT "+paymentColumns+" FROM checkout_payments WHERE id = $1 FOR UPDATE", paymentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("error locking payment: %w", err)
+	}
+	if payment.Status == models.PaymentStatusSucceeded {
+		return payment, nil
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE checkout_payments SET status = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING status, updated_at`, paymentID, models.PaymentStatusSucceeded,
+	).Scan(&payment.Status, &payment.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error completing payment: %w", err)
+	}
+
+	for _, rentalPayment := range rentalPayments {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO payment (customer_id, staff_id, rental_id, amount, payment_date)
+			SELECT ch.customer_id, s.manager_staff_id, $2, $3, NOW()
+			FROM checkouts ch
+			JOIN store s ON s.store_id = ch.store_id
+			WHERE ch.id = $1`, payment.CheckoutID, rentalPayment.RentalID, rentalPayment.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("error recording rental payment: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// FailPayment marks a pending payment failed. Payments that already
+// succeeded are left alone, as providers may report a failed attempt after
+// a later one succeeded.
+func (r *PaymentRepository) FailPayment(ctx context.Context, paymentID int) error {
+	ctx = database.WithQueryName(ctx, "payments.fail")
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE checkout_payments SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3`, paymentID, models.PaymentStatusFailed, models.PaymentStatusPending)
+	if err != nil {
+		return fmt.Errorf("error failing payment: %w", err)
+	}
+
+	return nil
+}
+
+// CreateRefund stores a pending refund of a succeeded payment, setting its
+// amount aside so concurrent refunds cannot return more than was paid. A
+// zero amount refunds whatever is left. Should the payment already have a
+// refund with idempotencyKey, that refund is returned instead, provided the
+// amounts agree.
+func (r *PaymentRepository) CreateRefund(
+	ctx context.Context,
+	paymentID int,
+	idempotencyKey string,
+	refundReq models.RefundRequest,
+) (*models.Refund, error) {
+	ctx = database.WithQueryName(ctx, "payments.create_refund")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "payments.create_refund")
+
+	payment, err := scanPayment(tx.QueryRowContext(ctx,
+		"SELECT "+paymentColumns+" FROM checkout_payments WHERE id = $1 FOR UPDATE", paymentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("error locking payment: %w", err)
+	}
+
+	existing, err := scanRefund(tx.QueryRowContext(ctx,
+		"SELECT "+refundColumns+" FROM payment_refunds WHERE payment_id = $1 AND idempotency_key = $2",
+		paymentID, idempotencyKey))
+	switch {
+	case err == nil:
+		if refundReq.Amount != 0 && toCents(refundReq.Amount) != toCents(existing.Amount) {
+			return nil, ErrIdempotencyKeyReused
+		}
+		return existing, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("error querying refund: %w", err)
+	}
+
+	if payment.Status != models.PaymentStatusSucceeded {
+		return nil, ErrPaymentNotRefundable
+	}
+	remaining := toCents(payment.Amount) - toCents(payment.RefundedAmount)
+	amount := remaining
+	if refundReq.Amount != 0 {
+		amount = toCents(refundReq.Amount)
+	}
+	if amount <= 0 || amount > remaining {
+		return nil, ErrRefundExceedsPayment
+	}
+
+	refund, err := scanRefund(tx.QueryRowContext(ctx, `
+		INSERT INTO payment_refunds (payment_id, idempotency_key, amount, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+refundColumns, paymentID, idempotencyKey, float64(amount)/100, refundReq.Reason))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting refund: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE checkout_payments SET refunded_amount = refunded_amount + $2, updated_at = NOW()
+		WHERE id = $1`, paymentID, refund.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("error reserving refund amount: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing refund: %w", err)
+	}
+
+	return refund, nil
+}
+
+// CompleteRefund marks a pending refund succeeded and records each rental's
+// share of it in the payment table as a negative amount, taken by the
+// checkout store's manager. Refunds no longer pending are returned
+// unchanged.
+func (r *PaymentRepository) CompleteRefund(
+	ctx context.Context,
+	refundID int,
+	providerRefundID string,
+	rentalRefunds []models.RentalPayment,
+) (*models.Refund, error) {
+	ctx = database.WithQueryName(ctx, "payments.complete_refund")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "payments.complete_refund")
+
+	refund, err := scanRefund(tx.QueryRowContext(ctx,
+		"SELECT "+refundColumns+" FROM payment_refunds WHERE id = $1 FOR UPDATE", refundID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRefundNotFound
+		}
+		return nil, fmt.Errorf("error locking refund: %w", err)
+	}
+	if refund.Status != models.PaymentStatusPending {
+		return refund, nil
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE payment_refunds SET status = $2, provider_refund_id = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING status, provider_refund_id, updated_at`, refundID, models.PaymentStatusSucceeded, providerRefundID,
+	).Scan(&refund.Status, &refund.ProviderRefundID, &refund.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error completing refund: %w", err)
+	}
+
+	for _, rentalRefund := range rentalRefunds {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO payment (customer_id, staff_id, rental_id, amount, payment_date)
+			SELECT ch.customer_id, s.manager_staff_id, $2, -$3::numeric, NOW()
+			FROM checkout_payments cp
+			JOIN checkouts ch ON ch.id = cp.checkout_id
+			JOIN store s ON s.store_id = ch.store_id
+			WHERE cp.id = $1`, refund.PaymentID, rentalRefund.RentalID, rentalRefund.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("error recording rental refund: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing refund: %w", err)
+	}
+
+	return refund, nil
+}
+
+// FailRefund marks a pending refund failed and releases its amount, so it
+// can be refunded again under a new idempotency key.
+func (r *PaymentRepository) FailRefund(ctx context.Context, refundID int) error {
+	ctx = database.WithQueryName(ctx, "payments.fail_refund")
+	_, err := r.db.ExecContext(ctx, `
+		WITH failed AS (
+			UPDATE payment_refunds SET status = $2, updated_at = NOW()
+			WHERE id = $1 AND status = $3
+			RETURNING payment_id, amount
+		)
+		UPDATE checkout_payments cp
+		SET refunded_amount = cp.refunded_amount - f.amount, updated_at = NOW()
+		FROM failed f
+		WHERE cp.id = f.payment_id`, refundID, models.PaymentStatusFailed, models.PaymentStatusPending)
+	if err != nil {
+		return fmt.Errorf("error failing refund: %w", err)
+	}
+
+	return nil
+}
+
+// toCents converts an amount of money to whole cents.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// scanRefund scans refundColumns from row.
+func scanRefund(row interface{ Scan(dest ...any) error }) (*models.Refund, error) {
+	var refund models.Refund
+	err := row.Scan(
+		&refund.ID, &refund.PaymentID, &refund.Amount, &refund.Reason, &refund.ProviderRefundID, &refund.Status,
+		&refund.CreatedAt, &refund.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// scanPayment scans paymentColumns from row.
+func scanPayment(row interface{ Scan(dest ...any) error }) (*models.Payment, error) {
+	var payment models.Payment
+	err := row.Scan(
+		&payment.ID, &payment.CheckoutID, &payment.Provider, &payment.ProviderPaymentID, &payment.ClientSecret,
+		&payment.Amount, &payment.RefundedAmount, &payment.Currency, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
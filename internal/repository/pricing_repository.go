@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// pricingRuleColumns lists the pricing_rules columns scanned by
+// scanPricingRule, in order.
+const pricingRuleColumns = `id, name, kind, percent_off::float8, bundle_quantity, bundle_paid,
+		category_id, store_id, weekends_only, starts_at, ends_at, created_at`
+
+// PricingRepository handles database operations for pricing rules and the
+// film and customer details prices depend on.
+type PricingRepository struct {
+	db *database.DB
+}
+
+// NewPricingRepository creates a new pricing repository.
+func NewPricingRepository(db *database.DB) *PricingRepository {
+	return &PricingRepository{db: db}
+}
+
+// CreatePricingRule stores a new pricing rule.
+func (r *PricingRepository) CreatePricingRule(
+	ctx context.Context,
+	ruleReq models.PricingRuleRequest,
+) (*models.PricingRule, error) {
+	query := `
+		INSERT INTO pricing_rules (
+			name, kind, percent_off, bundle_quantity, bundle_paid,
+			category_id, store_id, weekends_only, starts_at, ends_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + pricingRuleColumns
+
+	row := r.db.QueryRowContext(database.WithQueryName(ctx, "pricing.create_rule"), query,
+		ruleReq.Name, ruleReq.Kind, ruleReq.PercentOff, ruleReq.BundleQuantity, ruleReq.BundlePaid,
+		ruleReq.CategoryID, ruleReq.StoreID, ruleReq.WeekendsOnly, ruleReq.StartsAt, ruleReq.EndsAt,
+	)
+	rule, err := scanPricingRule(row)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting pricing rule: %w", constraintError(err, err))
+	}
+
+	return rule, nil
+}
+
+// ListPricingRules retrieves every pricing rule, in the order they apply.
+func (r *PricingRepository) ListPricingRules(ctx context.Context) ([]models.PricingRule, error) {
+	query := "SELECT " + pricingRuleColumns + " FROM pricing_rules ORDER BY id"
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "pricing.list_rules"), query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying pricing rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []models.PricingRule{}
+	for rows.Next() {
+		rule, scanErr := scanPricingRule(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning pricing rule: %w", scanErr)
+		}
+		rules = append(rules, *rule)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating pricing rules: %w", rowsErr)
+	}
+
+	return rules, nil
+}
+
+// DeletePricingRule deletes a pricing rule.
+func (r *PricingRepository) DeletePricingRule(ctx context.Context, ruleID int) error {
+	deleteCtx := database.WithQueryName(ctx, "pricing.delete_rule")
+	result, err := r.db.ExecContext(deleteCtx, "DELETE FROM pricing_rules WHERE id = $1", ruleID)
+	if err != nil {
+		return fmt.Errorf("error deleting pricing rule: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error counting deleted pricing rules: %w", err)
+	}
+	if deleted == 0 {
+		return ErrPricingRuleNotFound
+	}
+
+	return nil
+}
+
+// GetFilmPricing retrieves a published film's rental rate and categories.
+func (r *PricingRepository) GetFilmPricing(ctx context.Context, filmID int) (*models.FilmPricing, error) {
+	query := `
+		SELECT f.film_id, f.rental_rate,
+			ARRAY(SELECT fc.category_id FROM film_category fc WHERE fc.film_id = f.film_id ORDER BY fc.category_id)
+		FROM film f
+		WHERE f.film_id = $1 AND f.status = 'published'`
+
+	film := &models.FilmPricing{}
+	var categoryIDs pq.Int64Array
+	filmCtx := database.WithQueryName(ctx, "pricing.film")
+	err := r.db.QueryRowContext(filmCtx, query, filmID).Scan(&film.FilmID, &film.RentalRate, &categoryIDs)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFilmNotFound
+		}
+		return nil, fmt.Errorf("error querying film pricing: %w", err)
+	}
+
+	for _, categoryID := range categoryIDs {
+		film.CategoryIDs = append(film.CategoryIDs, int(categoryID))
+	}
+
+	return film, nil
+}
+
+// GetCustomerStoreID retrieves the store a customer belongs to.
+func (r *PricingRepository) GetCustomerStoreID(ctx context.Context, customerID int) (int, error) {
+	var storeID int
+	storeCtx := database.WithQueryName(ctx, "pricing.customer_store")
+	err := r.db.QueryRowContext(storeCtx, "SELECT store_id FROM customer WHERE customer_id = $1", customerID).
+		Scan(&storeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrCustomerNotFound
+		}
+		return 0, fmt.Errorf("error querying customer store: %w", err)
+	}
+
+	return storeID, nil
+}
+
+// scanPricingRule scans pricingRuleColumns from row.
+func scanPricingRule(row interface{ Scan(dest ...any) error }) (*models.PricingRule, error) {
+	var rule models.PricingRule
+	err := row.Scan(
+		&rule.ID, &rule.Name, &rule.Kind, &rule.PercentOff, &rule.BundleQuantity, &rule.BundlePaid,
+		&rule.CategoryID, &rule.StoreID, &rule.WeekendsOnly, &rule.StartsAt, &rule.EndsAt, &rule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// ReceiptRepository handles database operations for rental receipts and
+// invoices.
+type ReceiptRepository struct {
+	db *database.DB
+}
+
+// NewReceiptRepository creates a new receipt repository.
+func NewReceiptRepository(db *database.DB) *ReceiptRepository {
+	return &ReceiptRepository{db: db}
+}
+
+// GetRentalReceipt retrieves a rental with its customer, store, and the
+// payments taken for it, oldest first.
+func (r *ReceiptRepository) GetRentalReceipt(ctx context.Context, rentalID int) (*models.Receipt, error) {
+	ctx = database.WithQueryName(ctx, "receipts.get")
+	receipt := &models.Receipt{Payments: []models.ReceiptPayment{}}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT r.rental_id, r.customer_id, c.first_name || ' ' || c.last_name, COALESCE(c.email, ''),
+			i.store_id, a.address || ', ' || ci.city, f.film_id, f.title, r.rental_date, `+rentalDueAt+`,
+			r.return_date
+		FROM rental r
+		JOIN inventory i ON i.inventory_id = r.inventory_id
+		JOIN film f ON f.film_id = i.film_id
+		JOIN customer c ON c.customer_id = r.customer_id
+		JOIN store s ON s.store_id = i.store_id
+		JOIN address a ON a.address_id = s.address_id
+		JOIN city ci ON ci.city_id = a.city_id
+		WHERE r.rental_id = $1`, rentalID,
+	).Scan(
+		&receipt.RentalID, &receipt.CustomerID, &receipt.CustomerName, &receipt.CustomerEmail,
+		&receipt.StoreID, &receipt.StoreAddress, &receipt.FilmID, &receipt.FilmTitle, &receipt.RentalDate,
+		&receipt.DueAt, &receipt.ReturnDate,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRentalNotFound
+		}
+		return nil, fmt.Errorf("error querying rental: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "receipts.payments"), `
+		SELECT payment_id, amount::float8, payment_date
+		FROM payment
+		WHERE rental_id = $1
+		ORDER BY payment_date, payment_id`, rentalID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying rental payments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payment models.ReceiptPayment
+		if scanErr := rows.Scan(&payment.PaymentID, &payment.Amount, &payment.PaidAt); scanErr != nil {
+			return nil, fmt.Errorf("error scanning rental payment: %w", scanErr)
+		}
+		receipt.Payments = append(receipt.Payments, payment)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating rental payments: %w", rowsErr)
+	}
+
+	return receipt, nil
+}
+
+// GetCustomerInvoices retrieves a page of the rentals a customer has paid
+// for, most recent first, with the total number of them.
+func (r *ReceiptRepository) GetCustomerInvoices(
+	ctx context.Context,
+	customerID int,
+	filters models.InvoiceFilters,
+) (*pagination.Paginated[models.Invoice], error) {
+	var customerExists bool
+	existsCtx := database.WithQueryName(ctx, "receipts.customer_exists")
+	err := r.db.QueryRowContext(existsCtx, "SELECT EXISTS(SELECT 1 FROM customer WHERE customer_id = $1)", customerID).
+		Scan(&customerExists)
+	if err != nil {
+		return nil, fmt.Errorf("error checking customer existence: %w", err)
+	}
+	if !customerExists {
+		return nil, ErrCustomerNotFound
+	}
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "receipts.invoices"), `
+		SELECT r.rental_id, f.title, r.rental_date, SUM(p.amount)::float8, MAX(p.payment_date), COUNT(*) OVER()
+		FROM rental r
+		JOIN payment p ON p.rental_id = r.rental_id
+		JOIN inventory i ON i.inventory_id = r.inventory_id
+		JOIN film f ON f.film_id = i.film_id
+		WHERE r.customer_id = $1
+		GROUP BY r.rental_id, f.title, r.rental_date
+		ORDER BY r.rental_date DESC, r.rental_id DESC
+		LIMIT $2 OFFSET $3`, customerID, filters.Limit, (filters.Page-1)*filters.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := pagination.New([]models.Invoice{}, 0, pagination.Params{Page: filters.Page, Limit: filters.Limit})
+	for rows.Next() {
+		var invoice models.Invoice
+		if scanErr := rows.Scan(
+			&invoice.RentalID, &invoice.FilmTitle, &invoice.RentalDate, &invoice.AmountPaid,
+			&invoice.LastPaymentAt, &invoices.Total,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning invoice: %w", scanErr)
+		}
+		invoices.Items = append(invoices.Items, invoice)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating invoices: %w", rowsErr)
+	}
+
+	return invoices, nil
+}
@@ -0,0 +1,391 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// rentalDueAt is the SQL expression for when a rental is due back, given
+// rental r joined to its film f.
+const rentalDueAt = "r.rental_date + f.rental_duration * INTERVAL '1 day'"
+
+// rentalStatus is the SQL expression for a RentalEvent status, given rental r
+// joined to its film f. Open rentals past their due date are overdue even
+// before MarkOverdueRentals flags them.
+const rentalStatus = `CASE
+		WHEN r.return_date IS NOT NULL THEN 'returned'
+		WHEN r.overdue OR ` + rentalDueAt + ` < NOW() THEN 'overdue'
+		ELSE 'open'
+	END`
+
+// overdueSortColumns maps the overdue report sort keys to the columns of the
+// report query they order by.
+var overdueSortColumns = map[string]string{ //nolint:gochecknoglobals // Read-only lookup
+	models.OverdueSortDays:    "max_days_overdue",
+	models.OverdueSortFees:    "accrued_fees",
+	models.OverdueSortRentals: "overdue_rentals",
+	models.OverdueSortTitle:   "title",
+}
+
+// RentalRepository handles database operations for rentals.
+type RentalRepository struct {
+	db *database.DB
+}
+
+// NewRentalRepository creates a new rental repository.
+func NewRentalRepository(db *database.DB) *RentalRepository {
+	return &RentalRepository{db: db}
+}
+
+// MarkOverdueRentals flags open rentals that are past their due date and
+// returns how many were newly flagged.
+func (r *RentalRepository) MarkOverdueRentals(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE rental r SET overdue = true, last_update = NOW()
+		FROM inventory i
+		JOIN film f ON f.film_id = i.film_id
+		WHERE i.inventory_id = r.inventory_id
+		AND r.return_date IS NULL
+		AND NOT r.overdue
+		AND ` + rentalDueAt + ` < NOW()`
+
+	result, err := r.db.ExecContext(database.WithQueryName(ctx, "rentals.mark_overdue"), query)
+	if err != nil {
+		return 0, fmt.Errorf("error marking overdue rentals: %w", err)
+	}
+
+	marked, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error counting overdue rentals: %w", err)
+	}
+
+	return marked, nil
+}
+
+// ClaimDueReminders marks open rentals due within window as reminded and
+// returns them, so each rental is reminded at most once.
+func (r *RentalRepository) ClaimDueReminders(
+	ctx context.Context,
+	window time.Duration,
+) ([]models.RentalDueReminder, error) {
+	query := `
+		UPDATE rental r SET reminder_sent_at = NOW()
+		FROM inventory i, film f, customer c
+		WHERE i.inventory_id = r.inventory_id
+		AND f.film_id = i.film_id
+		AND c.customer_id = r.customer_id
+		AND r.return_date IS NULL
+		AND r.reminder_sent_at IS NULL
+		AND NOT r.overdue
+		AND c.email IS NOT NULL
+		AND ` + rentalDueAt + ` BETWEEN NOW() AND NOW() + $1 * INTERVAL '1 second'
+		RETURNING r.rental_id, r.customer_id, c.email, c.first_name, f.title, ` + rentalDueAt
+
+	claimCtx := database.WithQueryName(ctx, "rentals.claim_reminders")
+	rows, err := r.db.QueryContext(claimCtx, query, window.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("error claiming rental reminders: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := []models.RentalDueReminder{}
+	for rows.Next() {
+		var reminder models.RentalDueReminder
+		scanErr := rows.Scan(
+			&reminder.RentalID, &reminder.CustomerID, &reminder.CustomerEmail,
+			&reminder.CustomerName, &reminder.FilmTitle, &reminder.DueAt,
+		)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning rental reminder: %w", scanErr)
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating rental reminders: %w", rowsErr)
+	}
+
+	return reminders, nil
+}
+
+// RefreshTrendingFilms replaces the trending film ranking with the limit
+// most rented films since the given time.
+func (r *RentalRepository) RefreshTrendingFilms(ctx context.Context, since time.Time, limit int) error {
+	ctx = database.WithQueryName(ctx, "rentals.refresh_trending")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "refresh trending films")
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM trending_films"); err != nil {
+		return fmt.Errorf("error clearing trending films: %w", err)
+	}
+
+	query := `
+		INSERT INTO trending_films (film_id, rank, rental_count)
+		SELECT i.film_id, ROW_NUMBER() OVER (ORDER BY COUNT(*) DESC, i.film_id), COUNT(*)
+		FROM rental r
+		JOIN inventory i ON i.inventory_id = r.inventory_id
+		WHERE r.rental_date >= $1
+		GROUP BY i.film_id
+		ORDER BY COUNT(*) DESC, i.film_id
+		LIMIT $2`
+	if _, err = tx.ExecContext(ctx, query, since, limit); err != nil {
+		return fmt.Errorf("error ranking trending films: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing trending films: %w", err)
+	}
+
+	return nil
+}
+
+// GetTrendingFilms retrieves the current trending film ranking, leaving out
+// films that have since been unpublished.
+func (r *RentalRepository) GetTrendingFilms(ctx context.Context) ([]models.TrendingFilm, error) {
+	query := `
+		SELECT t.rank, t.film_id, f.title, t.rental_count, t.refreshed_at
+		FROM trending_films t
+		JOIN film f ON f.film_id = t.film_id
+		WHERE f.status = 'published'
+		ORDER BY t.rank`
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "rentals.trending"), query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying trending films: %w", err)
+	}
+	defer rows.Close()
+
+	films := []models.TrendingFilm{}
+	for rows.Next() {
+		var film models.TrendingFilm
+		if scanErr := rows.Scan(
+			&film.Rank, &film.FilmID, &film.Title, &film.RentalCount, &film.RefreshedAt,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning trending film: %w", scanErr)
+		}
+		films = append(films, film)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating trending films: %w", rowsErr)
+	}
+
+	return films, nil
+}
+
+// GetFilmRentals retrieves a page of a film's rentals, most recent first,
+// with the total number of matching rentals.
+func (r *RentalRepository) GetFilmRentals(
+	ctx context.Context,
+	filmID int,
+	filters models.RentalHistoryFilters,
+) (*pagination.Paginated[models.RentalEvent], error) {
+	if err := checkFilmExists(ctx, r.db, "rentals.film_exists", filmID); err != nil {
+		return nil, err
+	}
+
+	return r.getRentalHistory(ctx, "rentals.film_history", "i.film_id", filmID, filters)
+}
+
+// GetCustomerRentals retrieves a page of a customer's rentals, most recent
+// first, with the total number of matching rentals.
+func (r *RentalRepository) GetCustomerRentals(
+	ctx context.Context,
+	customerID int,
+	filters models.RentalHistoryFilters,
+) (*pagination.Paginated[models.RentalEvent], error) {
+	var customerExists bool
+	existsCtx := database.WithQueryName(ctx, "rentals.customer_exists")
+	err := r.db.QueryRowContext(existsCtx, "SELECT EXISTS(SELECT 1 FROM customer WHERE customer_id = $1)", customerID).
+		Scan(&customerExists)
+	if err != nil {
+		return nil, fmt.Errorf("error checking customer existence: %w", err)
+	}
+	if !customerExists {
+		return nil, ErrCustomerNotFound
+	}
+
+	return r.getRentalHistory(ctx, "rentals.customer_history", "r.customer_id", customerID, filters)
+}
+
+// ReturnRental records that a rental has come back, returning it with its
+// film and store.
+func (r *RentalRepository) ReturnRental(ctx context.Context, rentalID int) (*models.RentalEvent, error) {
+	query := `
+		WITH returned AS (
+			UPDATE rental SET return_date = NOW(), last_update = NOW()
+			WHERE rental_id = $1 AND return_date IS NULL
+			RETURNING rental_id, inventory_id, customer_id, rental_date, return_date
+		)
+		SELECT r.rental_id, r.inventory_id, i.store_id, f.film_id, f.title, r.customer_id,
+			c.first_name || ' ' || c.last_name, r.rental_date, ` + rentalDueAt + `, r.return_date
+		FROM returned r
+		JOIN inventory i ON i.inventory_id = r.inventory_id
+		JOIN film f ON f.film_id = i.film_id
+		JOIN customer c ON c.customer_id = r.customer_id`
+
+	event := models.RentalEvent{Status: models.RentalStatusReturned}
+	err := r.db.QueryRowContext(database.WithQueryName(ctx, "rentals.return"), query, rentalID).Scan(
+		&event.RentalID, &event.InventoryID, &event.StoreID, &event.FilmID, &event.FilmTitle,
+		&event.CustomerID, &event.CustomerName, &event.RentalDate, &event.DueAt, &event.ReturnDate,
+	)
+	if err == nil {
+		return &event, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("error returning rental: %w", err)
+	}
+
+	// Nothing was updated: the rental is missing or already back.
+	var exists bool
+	existsCtx := database.WithQueryName(ctx, "rentals.exists")
+	err = r.db.QueryRowContext(existsCtx, "SELECT EXISTS(SELECT 1 FROM rental WHERE rental_id = $1)", rentalID).
+		Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("error checking rental existence: %w", err)
+	}
+	if !exists {
+		return nil, ErrRentalNotFound
+	}
+	return nil, ErrRentalReturned
+}
+
+// getRentalHistory retrieves a page of the rentals whose ownerColumn, a
+// column of rental r or inventory i, equals ownerID.
+func (r *RentalRepository) getRentalHistory(
+	ctx context.Context,
+	queryName, ownerColumn string,
+	ownerID int,
+	filters models.RentalHistoryFilters,
+) (*pagination.Paginated[models.RentalEvent], error) {
+	query := `
+		SELECT r.rental_id, r.inventory_id, i.store_id, f.film_id, f.title, r.customer_id,
+			c.first_name || ' ' || c.last_name, r.rental_date, ` + rentalDueAt + `,
+			r.return_date, ` + rentalStatus + `, COUNT(*) OVER()
+		FROM rental r
+		JOIN inventory i ON i.inventory_id = r.inventory_id
+		JOIN film f ON f.film_id = i.film_id
+		JOIN customer c ON c.customer_id = r.customer_id
+		WHERE ` + ownerColumn + ` = $1`
+	args := []any{ownerID}
+
+	if filters.From != nil {
+		args = append(args, *filters.From)
+		query += fmt.Sprintf(" AND r.rental_date >= $%d", len(args))
+	}
+	if filters.To != nil {
+		args = append(args, *filters.To)
+		query += fmt.Sprintf(" AND r.rental_date < $%d", len(args))
+	}
+	if filters.StoreID != 0 {
+		args = append(args, filters.StoreID)
+		query += fmt.Sprintf(" AND i.store_id = $%d", len(args))
+	}
+	if filters.Status != "" {
+		args = append(args, filters.Status)
+		query += fmt.Sprintf(" AND "+rentalStatus+" = $%d", len(args))
+	}
+
+	args = append(args, filters.Limit, filters.Offset())
+	query += fmt.Sprintf(" ORDER BY r.rental_date DESC, r.rental_id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, queryName), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying rental history: %w", err)
+	}
+	defer rows.Close()
+
+	history := pagination.New([]models.RentalEvent{}, 0, filters.Params)
+	for rows.Next() {
+		var event models.RentalEvent
+		if scanErr := rows.Scan(
+			&event.RentalID, &event.InventoryID, &event.StoreID, &event.FilmID, &event.FilmTitle,
+			&event.CustomerID, &event.CustomerName, &event.RentalDate, &event.DueAt, &event.ReturnDate,
+			&event.Status, &history.Total,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning rental: %w", scanErr)
+		}
+		history.Items = append(history.Items, event)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating rental history: %w", rowsErr)
+	}
+
+	return history, nil
+}
+
+// GetOverdueFilms aggregates open rentals past their due date by film, in
+// the order filters asks for, ties broken by film ID.
+func (r *RentalRepository) GetOverdueFilms(
+	ctx context.Context,
+	filters models.OverdueReportFilters,
+) ([]models.OverdueFilm, error) {
+	sortColumn, ok := overdueSortColumns[filters.Sort]
+	if !ok {
+		sortColumn = overdueSortColumns[models.OverdueSortFees]
+	}
+	direction := "DESC"
+	if filters.Order == "asc" {
+		direction = "ASC"
+	}
+
+	args := []any{filters.LateFeePerDay}
+	storeClause := ""
+	if filters.StoreID != 0 {
+		args = append(args, filters.StoreID)
+		storeClause = fmt.Sprintf(" AND i.store_id = $%d", len(args))
+	}
+
+	query := `
+		WITH overdue AS (
+			SELECT f.film_id, f.title, f.replacement_cost,
+				CEIL(EXTRACT(EPOCH FROM NOW() - (` + rentalDueAt + `)) / 86400)::int AS days_overdue
+			FROM rental r
+			JOIN inventory i ON i.inventory_id = r.inventory_id
+			JOIN film f ON f.film_id = i.film_id
+			WHERE r.return_date IS NULL
+			AND ` + rentalDueAt + ` < NOW()` + storeClause + `
+		)
+		SELECT film_id, title, COUNT(*) AS overdue_rentals,
+			SUM(days_overdue) AS total_days_overdue, MAX(days_overdue) AS max_days_overdue,
+			SUM(LEAST(days_overdue * $1::numeric, replacement_cost))::float8 AS accrued_fees
+		FROM overdue
+		GROUP BY film_id, title
+		ORDER BY ` + sortColumn + ` ` + direction + `, film_id`
+
+	overdueCtx := database.WithQueryName(ctx, "rentals.overdue_report")
+	rows, err := r.db.QueryContext(overdueCtx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying overdue rentals: %w", err)
+	}
+	defer rows.Close()
+
+	films := []models.OverdueFilm{}
+	for rows.Next() {
+		var film models.OverdueFilm
+		if scanErr := rows.Scan(
+			&film.FilmID, &film.Title, &film.OverdueRentals,
+			&film.TotalDaysOverdue, &film.MaxDaysOverdue, &film.AccruedFees,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning overdue film: %w", scanErr)
+		}
+		films = append(films, film)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating overdue films: %w", rowsErr)
+	}
+
+	return films, nil
+}
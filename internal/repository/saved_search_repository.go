@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// savedSearchColumns lists the saved_searches columns scanned by
+// scanSavedSearch, in order.
+const savedSearchColumns = "s.id, s.customer_id, s.name, s.filters, s.notify, s.created_at"
+
+// SavedSearchRepository handles database operations for customers' saved
+// searches. Changes are scoped to the owning customer, so another
+// customer's search is reported as not found.
+type SavedSearchRepository struct {
+	db *database.DB
+}
+
+// NewSavedSearchRepository creates a new saved search repository.
+func NewSavedSearchRepository(db *database.DB) *SavedSearchRepository {
+	return &SavedSearchRepository{db: db}
+}
+
+// CreateSavedSearch stores a search for a customer, unless they already
+// have models.MaxSavedSearches. The customer's row is locked while the
+// searches are counted, so concurrent saves cannot pass the limit.
+func (r *SavedSearchRepository) CreateSavedSearch(
+	ctx context.Context,
+	customerID int,
+	searchReq models.SavedSearchRequest,
+) (*models.SavedSearch, error) {
+	filters, err := json.Marshal(searchReq.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding saved search filters: %w", err)
+	}
+
+	ctx = database.WithQueryName(ctx, "saved_searches.create")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "saved_searches.create")
+
+	// The count is its own statement so that it sees searches committed by
+	// whoever held the lock before.
+	var locked int
+	err = tx.QueryRowContext(ctx, "SELECT customer_id FROM customer WHERE customer_id = $1 FOR UPDATE", customerID).
+		Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("error locking customer: %w", err)
+	}
+	var searches int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM saved_searches WHERE customer_id = $1", customerID).
+		Scan(&searches)
+	if err != nil {
+		return nil, fmt.Errorf("error counting saved searches: %w", err)
+	}
+	if searches >= models.MaxSavedSearches {
+		return nil, ErrSavedSearchLimit
+	}
+
+	search, err := scanSavedSearch(tx.QueryRowContext(ctx, `
+		INSERT INTO saved_searches AS s (customer_id, name, filters, notify)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+savedSearchColumns, customerID, searchReq.Name, filters, searchReq.Notify))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting saved search: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing saved search: %w", err)
+	}
+	return search, nil
+}
+
+// ListSavedSearches retrieves a customer's saved searches, newest first.
+func (r *SavedSearchRepository) ListSavedSearches(ctx context.Context, customerID int) ([]models.SavedSearch, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "saved_searches.list"),
+		"SELECT "+savedSearchColumns+" FROM saved_searches s WHERE s.customer_id = $1 ORDER BY s.id DESC",
+		customerID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []models.SavedSearch{}
+	for rows.Next() {
+		search, scanErr := scanSavedSearch(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning saved search: %w", scanErr)
+		}
+		searches = append(searches, *search)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating saved searches: %w", rowsErr)
+	}
+
+	return searches, nil
+}
+
+// DeleteSavedSearch deletes one of a customer's saved searches.
+func (r *SavedSearchRepository) DeleteSavedSearch(ctx context.Context, customerID, searchID int) error {
+	ctx = database.WithQueryName(ctx, "saved_searches.delete")
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM saved_searches WHERE id = $1 AND customer_id = $2", searchID, customerID)
+	if err != nil {
+		return fmt.Errorf("error deleting saved search: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error counting deleted saved searches: %w", err)
+	}
+	if deleted == 0 {
+		return ErrSavedSearchNotFound
+	}
+
+	return nil
+}
+
+// ListAlertingSearches retrieves the searches customers asked to be told
+// about, with the customer and when each was last checked, and the
+// database's current time to check them up to. Searches of customers
+// without an email address are left out.
+func (r *SavedSearchRepository) ListAlertingSearches(
+	ctx context.Context,
+) ([]models.SavedSearchAlert, time.Time, error) {
+	ctx = database.WithQueryName(ctx, "saved_searches.list_alerting")
+	var checkedAt time.Time
+	if err := r.db.QueryRowContext(ctx, "SELECT NOW()::timestamp").Scan(&checkedAt); err != nil {
+		return nil, time.Time{}, fmt.Errorf("error reading database time: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+savedSearchColumns+`, c.email, c.first_name, s.last_checked_at
+		FROM saved_searches s
+		JOIN customer c ON c.customer_id = s.customer_id
+		WHERE s.notify AND c.email IS NOT NULL AND s.last_checked_at < $1
+		ORDER BY s.id`, checkedAt)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error querying saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []models.SavedSearchAlert{}
+	for rows.Next() {
+		var alert models.SavedSearchAlert
+		search, scanErr := scanSavedSearch(rows, &alert.CustomerEmail, &alert.CustomerName, &alert.LastCheckedAt)
+		if scanErr != nil {
+			return nil, time.Time{}, fmt.Errorf("error scanning saved search: %w", scanErr)
+		}
+		alert.SavedSearch = *search
+		alerts = append(alerts, alert)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, time.Time{}, fmt.Errorf("error iterating saved searches: %w", rowsErr)
+	}
+
+	return alerts, checkedAt, nil
+}
+
+// MarkSearchChecked records that a search has been checked for films
+// published up to checkedAt.
+func (r *SavedSearchRepository) MarkSearchChecked(ctx context.Context, searchID int, checkedAt time.Time) error {
+	ctx = database.WithQueryName(ctx, "saved_searches.mark_checked")
+	if _, err := r.db.ExecContext(ctx,
+		"UPDATE saved_searches SET last_checked_at = $2 WHERE id = $1", searchID, checkedAt); err != nil {
+		return fmt.Errorf("error marking saved search checked: %w", err)
+	}
+	return nil
+}
+
+// scanSavedSearch scans a row of savedSearchColumns. Any extra destinations
+// are scanned from the columns following the search's.
+func scanSavedSearch(row interface{ Scan(dest ...any) error }, extra ...any) (*models.SavedSearch, error) {
+	var search models.SavedSearch
+	var filters []byte
+	dest := []any{&search.ID, &search.CustomerID, &search.Name, &filters, &search.Notify, &search.CreatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filters, &search.Filters); err != nil {
+		return nil, fmt.Errorf("error decoding saved search filters: %w", err)
+	}
+	return &search, nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+)
+
+// SchemaRepository reads the migration version of the live database.
+type SchemaRepository struct {
+	db *database.DB
+}
+
+// NewSchemaRepository creates a new schema repository.
+func NewSchemaRepository(db *database.DB) *SchemaRepository {
+	return &SchemaRepository{db: db}
+}
+
+// GetSchemaVersion returns the newest migration version applied to the
+// database, or 0 if it has never been migrated.
+func (r *SchemaRepository) GetSchemaVersion(ctx context.Context) (int64, error) {
+	return database.AppliedVersion(ctx, r.db.DB)
+}
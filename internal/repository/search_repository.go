@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// SearchRepository handles database operations for title searches that
+// found no films.
+type SearchRepository struct {
+	db *database.DB
+}
+
+// NewSearchRepository creates a new search repository.
+func NewSearchRepository(db *database.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// RecordMiss counts a search for term that found no films.
+func (r *SearchRepository) RecordMiss(ctx context.Context, term string) error {
+	ctx = database.WithQueryName(ctx, "search_misses.record")
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO film_search_misses (term) VALUES ($1)
+		ON CONFLICT (term) DO UPDATE
+		SET searches = film_search_misses.searches + 1, last_searched_at = NOW()`, term)
+	if err != nil {
+		return fmt.Errorf("error recording search miss: %w", err)
+	}
+	return nil
+}
+
+// ListMisses returns a page of the searches that found no films, most often
+// made first.
+func (r *SearchRepository) ListMisses(
+	ctx context.Context,
+	params pagination.Params,
+) (*pagination.Paginated[models.SearchMiss], error) {
+	ctx = database.WithQueryName(ctx, "search_misses.list")
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT term, searches, first_searched_at, last_searched_at, COUNT(*) OVER()
+		FROM film_search_misses
+		ORDER BY searches DESC, last_searched_at DESC, term
+		LIMIT $1 OFFSET $2`, params.Limit, params.Offset())
+	if err != nil {
+		return nil, fmt.Errorf("error querying search misses: %w", err)
+	}
+	defer rows.Close()
+
+	misses := pagination.New([]models.SearchMiss{}, 0, params)
+	for rows.Next() {
+		var miss models.SearchMiss
+		if scanErr := rows.Scan(
+			&miss.Term, &miss.Searches, &miss.FirstSearchedAt, &miss.LastSearchedAt, &misses.Total,
+		); scanErr != nil {
+			return nil, fmt.Errorf("error scanning search miss: %w", scanErr)
+		}
+		misses.Items = append(misses.Items, miss)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating search misses: %w", rowsErr)
+	}
+
+	return misses, nil
+}
+
+// SuggestTitles returns up to limit titles of published films similar to
+// term by trigram similarity, most similar first. It needs the pg_trgm
+// extension.
+func (r *SearchRepository) SuggestTitles(ctx context.Context, term string, limit int) ([]string, error) {
+	ctx = database.WithQueryName(ctx, "search_misses.suggest")
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT title FROM film
+		WHERE status = 'published' AND title % $1
+		ORDER BY similarity(title, $1) DESC, title
+		LIMIT $2`, term, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying title suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	titles := []string{}
+	for rows.Next() {
+		var title string
+		if scanErr := rows.Scan(&title); scanErr != nil {
+			return nil, fmt.Errorf("error scanning title suggestion: %w", scanErr)
+		}
+		titles = append(titles, title)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating title suggestions: %w", rowsErr)
+	}
+
+	return titles, nil
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// SessionRepository handles database operations for staff and customer
+// login sessions and their refresh tokens.
+type SessionRepository struct {
+	db *database.DB
+}
+
+// NewSessionRepository creates a new login session repository.
+func NewSessionRepository(db *database.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// sessionColumns are the login session columns scanned by scanSession.
+const sessionColumns = `id, role, subject_id, user_agent, ip_address, created_at, refreshed_at, expires_at,
+		revoked_at, two_factor`
+
+// CreateSession starts the login session described by the role, subject,
+// expiry, device, and second factor of session, whose refresh token is
+// stored as tokenHash.
+func (r *SessionRepository) CreateSession(
+	ctx context.Context,
+	session models.AuthSession,
+	tokenHash string,
+) (*models.AuthSession, error) {
+	ctx = database.WithQueryName(ctx, "auth_sessions.create")
+	created, err := scanSession(r.db.QueryRowContext(ctx, `
+		INSERT INTO auth_sessions (
+			role, subject_id, refresh_token_hash, expires_at, user_agent, ip_address, two_factor
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+sessionColumns, session.Role, session.SubjectID, tokenHash, session.ExpiresAt,
+		session.UserAgent, session.IPAddress, session.TwoFactor))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting session: %w", err)
+	}
+	return created, nil
+}
+
+// RotateRefreshToken replaces the refresh token stored as tokenHash with
+// newHash, records client as the device the session is used from, and
+// returns the session. It returns ErrSessionNotFound if no live
+// session holds tokenHash, or its staff member or customer has since been
+// deactivated. If tokenHash is the token a live session's
+// current one replaced, the token has been used twice, so the session is
+// revoked and ErrRefreshTokenReused returned.
+func (r *SessionRepository) RotateRefreshToken(
+	ctx context.Context,
+	tokenHash, newHash string,
+	client models.SessionClient,
+) (*models.AuthSession, error) {
+	ctx = database.WithQueryName(ctx, "auth_sessions.rotate")
+	session, err := scanSession(r.db.QueryRowContext(ctx, `
+		UPDATE auth_sessions
+		SET previous_token_hash = refresh_token_hash, refresh_token_hash = $2, refreshed_at = NOW(),
+			user_agent = $3, ip_address = $4
+		WHERE refresh_token_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()
+			AND CASE role
+				WHEN $5 THEN EXISTS (SELECT 1 FROM staff WHERE staff_id = subject_id AND active)
+				WHEN $6 THEN EXISTS (SELECT 1 FROM customer WHERE customer_id = subject_id AND activebool)
+				ELSE TRUE
+			END
+		RETURNING `+sessionColumns, tokenHash, newHash, client.UserAgent, client.IPAddress,
+		auth.RoleStaff, auth.RoleCustomer))
+	if err == nil {
+		return session, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("error rotating refresh token: %w", err)
+	}
+
+	ctx = database.WithQueryName(ctx, "auth_sessions.revoke_reused")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE auth_sessions
+		SET revoked_at = NOW()
+		WHERE previous_token_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()`, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("error revoking session of reused refresh token: %w", err)
+	}
+	if revoked, _ := result.RowsAffected(); revoked > 0 {
+		return nil, ErrRefreshTokenReused
+	}
+	return nil, ErrSessionNotFound
+}
+
+// RevokeSession revokes a live login session. It returns ErrSessionNotFound
+// if there is no such session, or it has already ended.
+func (r *SessionRepository) RevokeSession(ctx context.Context, sessionID int64) error {
+	ctx = database.WithQueryName(ctx, "auth_sessions.revoke")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE auth_sessions
+		SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()`, sessionID)
+	if err != nil {
+		return fmt.Errorf("error revoking session: %w", err)
+	}
+	if revoked, _ := result.RowsAffected(); revoked == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// ListActiveSessions retrieves the live login sessions of subjectID in role,
+// most recently refreshed first.
+func (r *SessionRepository) ListActiveSessions(
+	ctx context.Context,
+	role string,
+	subjectID int,
+) ([]models.AuthSession, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "auth_sessions.list_active"), `
+		SELECT `+sessionColumns+`
+		FROM auth_sessions
+		WHERE role = $1 AND subject_id = $2 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY refreshed_at DESC, id DESC`, role, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.AuthSession{}
+	for rows.Next() {
+		session, scanErr := scanSession(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning session: %w", scanErr)
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating sessions: %w", rowsErr)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSubjectSession revokes a live login session of subjectID in role. It
+// returns ErrSessionNotFound if there is no such session, it belongs to
+// someone else, or it has already ended.
+func (r *SessionRepository) RevokeSubjectSession(
+	ctx context.Context,
+	role string,
+	subjectID int,
+	sessionID int64,
+) error {
+	ctx = database.WithQueryName(ctx, "auth_sessions.revoke_subject")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE auth_sessions
+		SET revoked_at = NOW()
+		WHERE id = $1 AND role = $2 AND subject_id = $3 AND revoked_at IS NULL AND expires_at > NOW()`,
+		sessionID, role, subjectID)
+	if err != nil {
+		return fmt.Errorf("error revoking session: %w", err)
+	}
+	if revoked, _ := result.RowsAffected(); revoked == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// revokeSubjectSessions revokes every live login session of subjectID in
+// role within tx, so an account change ends them with it.
+func revokeSubjectSessions(ctx context.Context, tx *sql.Tx, role string, subjectID int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE auth_sessions
+		SET revoked_at = NOW()
+		WHERE role = $1 AND subject_id = $2 AND revoked_at IS NULL AND expires_at > NOW()`, role, subjectID)
+	if err != nil {
+		return fmt.Errorf("error revoking sessions: %w", err)
+	}
+	return nil
+}
+
+// IsSessionRevoked reports whether a login session has ended: it has been
+// revoked or has expired. A session that does not exist counts as revoked.
+func (r *SessionRepository) IsSessionRevoked(ctx context.Context, sessionID int64) (bool, error) {
+	ctx = database.WithQueryName(ctx, "auth_sessions.is_revoked")
+	var revoked bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT revoked_at IS NOT NULL OR expires_at <= NOW()
+		FROM auth_sessions
+		WHERE id = $1`, sessionID).Scan(&revoked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking session revocation: %w", err)
+	}
+	return revoked, nil
+}
+
+// scanSession scans a row selected with sessionColumns.
+func scanSession(row interface{ Scan(dest ...any) error }) (*models.AuthSession, error) {
+	var session models.AuthSession
+	if err := row.Scan(
+		&session.ID, &session.Role, &session.SubjectID, &session.UserAgent, &session.IPAddress,
+		&session.CreatedAt, &session.RefreshedAt, &session.ExpiresAt, &session.RevokedAt, &session.TwoFactor,
+	); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// shortLinkColumns lists the short_links columns scanned by scanShortLink,
+// in order.
+const shortLinkColumns = "id, code, film_id, campaign, clicks, created_at, last_clicked_at"
+
+// ShortLinkRepository handles database operations for short links to films.
+type ShortLinkRepository struct {
+	db *database.DB
+}
+
+// NewShortLinkRepository creates a new short link repository.
+func NewShortLinkRepository(db *database.DB) *ShortLinkRepository {
+	return &ShortLinkRepository{db: db}
+}
+
+// CreateShortLink stores a short link to filmID under code.
+func (r *ShortLinkRepository) CreateShortLink(
+	ctx context.Context,
+	code string,
+	filmID int,
+	campaign *string,
+) (*models.ShortLink, error) {
+	ctx = database.WithQueryName(ctx, "short_links.create")
+	link, err := scanShortLink(r.db.QueryRowContext(ctx, `
+		INSERT INTO short_links (code, film_id, campaign)
+		VALUES ($1, $2, $3)
+		RETURNING `+shortLinkColumns, code, filmID, campaign))
+	if err != nil {
+		err = constraintError(err, ErrShortLinkCodeTaken)
+		if errors.Is(err, ErrInvalidReference) {
+			return nil, ErrFilmNotFound
+		}
+		return nil, fmt.Errorf("error inserting short link: %w", err)
+	}
+
+	return link, nil
+}
+
+// ListFilmShortLinks retrieves the short links to a film, newest first.
+func (r *ShortLinkRepository) ListFilmShortLinks(ctx context.Context, filmID int) ([]models.ShortLink, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "short_links.list"),
+		"SELECT "+shortLinkColumns+" FROM short_links WHERE film_id = $1 ORDER BY created_at DESC, id DESC", filmID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying short links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []models.ShortLink{}
+	for rows.Next() {
+		link, scanErr := scanShortLink(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning short link: %w", scanErr)
+		}
+		links = append(links, *link)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating short links: %w", rowsErr)
+	}
+
+	return links, nil
+}
+
+// RecordClick counts a click on the short link with code, returning the
+// film it links to.
+func (r *ShortLinkRepository) RecordClick(ctx context.Context, code string) (int, error) {
+	ctx = database.WithQueryName(ctx, "short_links.click")
+	var filmID int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE short_links SET clicks = clicks + 1, last_clicked_at = NOW()
+		WHERE code = $1
+		RETURNING film_id`, code).Scan(&filmID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrShortLinkNotFound
+		}
+		return 0, fmt.Errorf("error recording short link click: %w", err)
+	}
+
+	return filmID, nil
+}
+
+// scanShortLink scans shortLinkColumns from row.
+func scanShortLink(row interface{ Scan(dest ...any) error }) (*models.ShortLink, error) {
+	var link models.ShortLink
+	err := row.Scan(
+		&link.ID, &link.Code, &link.FilmID, &link.Campaign, &link.Clicks, &link.CreatedAt, &link.LastClickedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// staffColumns lists the staff columns scanned by scanStaff, in order.
+const staffColumns = `staff_id, first_name, last_name, email, address_id,
+		store_id, active, username, last_update`
+
+// StaffRepository handles database operations for staff.
+type StaffRepository struct {
+	db *database.DB
+}
+
+// NewStaffRepository creates a new staff repository.
+func NewStaffRepository(db *database.DB) *StaffRepository {
+	return &StaffRepository{db: db}
+}
+
+// ListStaff retrieves staff members ordered by name, limited to one store
+// when storeID is non-zero.
+func (r *StaffRepository) ListStaff(ctx context.Context, storeID int) ([]models.Staff, error) {
+	query := "SELECT " + staffColumns + " FROM staff WHERE $1 = 0 OR store_id = $1 ORDER BY last_name, first_name"
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "staff.list"), query, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying staff: %w", err)
+	}
+	defer rows.Close()
+
+	staff := []models.Staff{}
+	for rows.Next() {
+		member, scanErr := scanStaff(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning staff: %w", scanErr)
+		}
+		staff = append(staff, *member)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating staff: %w", rowsErr)
+	}
+
+	return staff, nil
+}
+
+// CreateStaff adds a staff member with an already hashed password.
+func (r *StaffRepository) CreateStaff(
+	ctx context.Context,
+	staffReq models.StaffRequest,
+	passwordHash string,
+) (*models.Staff, error) {
+	query := `
+		INSERT INTO staff (first_name, last_name, email, address_id, store_id, username, password)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
+		RETURNING ` + staffColumns
+
+	insertCtx := database.WithQueryName(ctx, "staff.insert")
+	row := r.db.QueryRowContext(insertCtx, query,
+		staffReq.FirstName, staffReq.LastName, staffReq.Email, staffReq.AddressID, staffReq.StoreID,
+		staffReq.Username, passwordHash,
+	)
+	member, err := scanStaff(row)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting staff: %w", constraintError(err, ErrUsernameTaken))
+	}
+
+	return member, nil
+}
+
+// DeactivateStaff marks a staff member inactive and revokes their login
+// sessions.
+func (r *StaffRepository) DeactivateStaff(ctx context.Context, staffID int) (*models.Staff, error) {
+	ctx = database.WithQueryName(ctx, "staff.deactivate")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "staff.deactivate")
+
+	query := `
+		UPDATE staff SET active = false, last_update = NOW()
+		WHERE staff_id = $1
+		RETURNING ` + staffColumns
+
+	member, err := scanStaff(tx.QueryRowContext(ctx, query, staffID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStaffNotFound
+		}
+		return nil, fmt.Errorf("error deactivating staff: %w", err)
+	}
+	if err = revokeSubjectSessions(ctx, tx, auth.RoleStaff, staffID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing staff deactivation: %w", err)
+	}
+
+	return member, nil
+}
+
+// GetStaffCredentials retrieves a staff member and password hash by username.
+func (r *StaffRepository) GetStaffCredentials(ctx context.Context, username string) (*models.Staff, string, error) {
+	query := "SELECT " + staffColumns + ", COALESCE(password, '') FROM staff WHERE username = $1"
+
+	var passwordHash string
+	row := r.db.QueryRowContext(database.WithQueryName(ctx, "staff.credentials"), query, username)
+	member, err := scanStaff(row, &passwordHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", ErrStaffNotFound
+		}
+		return nil, "", fmt.Errorf("error querying staff credentials: %w", err)
+	}
+
+	return member, passwordHash, nil
+}
+
+// UpdateStaffPassword replaces a staff member's password hash.
+func (r *StaffRepository) UpdateStaffPassword(ctx context.Context, staffID int, passwordHash string) error {
+	ctx = database.WithQueryName(ctx, "staff.update_password")
+	result, err := r.db.ExecContext(ctx, "UPDATE staff SET password = $2, last_update = NOW() WHERE staff_id = $1",
+		staffID, passwordHash)
+	if err != nil {
+		return fmt.Errorf("error updating staff password: %w", err)
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return ErrStaffNotFound
+	}
+	return nil
+}
+
+// scanStaff scans staffColumns, followed by any extra destinations, from row.
+func scanStaff(row interface{ Scan(dest ...any) error }, extra ...any) (*models.Staff, error) {
+	var member models.Staff
+	dest := []any{
+		&member.StaffID, &member.FirstName, &member.LastName, &member.Email, &member.AddressID,
+		&member.StoreID, &member.Active, &member.Username, &member.LastUpdate,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// TagRepository handles database operations for free-form film tags. Tags
+// are expected in their stored form, lower case.
+type TagRepository struct {
+	db *database.DB
+}
+
+// NewTagRepository creates a new tag repository.
+func NewTagRepository(db *database.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// TagFilm adds a tag to a film, returning the film's tags. Adding a tag the
+// film already carries changes nothing.
+func (r *TagRepository) TagFilm(ctx context.Context, filmID int, tag string) ([]string, error) {
+	ctx = database.WithQueryName(ctx, "tags.add")
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO film_tags (film_id, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING", filmID, tag)
+	if err != nil {
+		err = constraintError(err, err)
+		if errors.Is(err, ErrInvalidReference) {
+			return nil, ErrFilmNotFound
+		}
+		return nil, fmt.Errorf("error tagging film: %w", err)
+	}
+
+	return queryFilmTags(ctx, r.db, filmID)
+}
+
+// UntagFilm removes a tag from a film, returning the film's tags.
+func (r *TagRepository) UntagFilm(ctx context.Context, filmID int, tag string) ([]string, error) {
+	ctx = database.WithQueryName(ctx, "tags.remove")
+	result, err := r.db.ExecContext(ctx, "DELETE FROM film_tags WHERE film_id = $1 AND tag = $2", filmID, tag)
+	if err != nil {
+		return nil, fmt.Errorf("error untagging film: %w", err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error checking untagged film: %w", err)
+	}
+	if removed == 0 {
+		var exists bool
+		err = r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM film WHERE film_id = $1)", filmID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("error querying film: %w", err)
+		}
+		if !exists {
+			return nil, ErrFilmNotFound
+		}
+		return nil, ErrFilmTagNotFound
+	}
+
+	return queryFilmTags(ctx, r.db, filmID)
+}
+
+// ListTags retrieves every tag in use with the number of films carrying it,
+// most used first and then by name.
+func (r *TagRepository) ListTags(ctx context.Context) ([]models.TagCount, error) {
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "tags.list"), `
+		SELECT tag, COUNT(*)
+		FROM film_tags
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var tag models.TagCount
+		if scanErr := rows.Scan(&tag.Tag, &tag.FilmCount); scanErr != nil {
+			return nil, fmt.Errorf("error scanning tag: %w", scanErr)
+		}
+		tags = append(tags, tag)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", rowsErr)
+	}
+
+	return tags, nil
+}
+
+// queryFilmTags retrieves a film's tags in alphabetical order.
+func queryFilmTags(ctx context.Context, q rowQuerier, filmID int) ([]string, error) {
+	var tags pq.StringArray
+	err := q.QueryRowContext(ctx,
+		"SELECT COALESCE(array_agg(tag ORDER BY tag), '{}') FROM film_tags WHERE film_id = $1", filmID,
+	).Scan(&tags)
+	if err != nil {
+		return nil, fmt.Errorf("error querying film tags: %w", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pii"
+)
+
+// TwoFactorRepository handles database operations for the TOTP second
+// factors and backup codes of staff accounts. Secrets are encrypted with
+// cipher.
+type TwoFactorRepository struct {
+	db     *database.DB
+	cipher *pii.Cipher
+}
+
+// NewTwoFactorRepository creates a new staff two-factor repository.
+func NewTwoFactorRepository(db *database.DB, cipher *pii.Cipher) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db, cipher: cipher}
+}
+
+// BeginEnrollment stores a pending TOTP secret and backup codes, stored as
+// codeHashes, for a staff member, replacing any earlier pending enrollment,
+// and returns the staff member's username. It returns ErrStaffNotFound if
+// there is no such staff member and ErrTwoFactorEnabled if they already
+// have two-factor authentication enabled.
+func (r *TwoFactorRepository) BeginEnrollment(
+	ctx context.Context,
+	staffID int,
+	secret string,
+	codeHashes []string,
+) (string, error) {
+	storedSecret, err := r.cipher.Encrypt(secret)
+	if err != nil {
+		return "", fmt.Errorf("error encrypting TOTP secret: %w", err)
+	}
+
+	ctx = database.WithQueryName(ctx, "staff_two_factor.enroll")
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer rollback(tx, "staff_two_factor.enroll")
+
+	var username string
+	err = tx.QueryRowContext(ctx, "SELECT username FROM staff WHERE staff_id = $1 FOR UPDATE", staffID).
+		Scan(&username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrStaffNotFound
+		}
+		return "", fmt.Errorf("error locking staff: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO staff_two_factor (staff_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (staff_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+		WHERE staff_two_factor.enabled_at IS NULL`, staffID, storedSecret)
+	if err != nil {
+		return "", fmt.Errorf("error storing TOTP secret: %w", err)
+	}
+	if stored, _ := result.RowsAffected(); stored == 0 {
+		return "", ErrTwoFactorEnabled
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM staff_backup_codes WHERE staff_id = $1", staffID); err != nil {
+		return "", fmt.Errorf("error deleting backup codes: %w", err)
+	}
+	for _, codeHash := range codeHashes {
+		if _, err = tx.ExecContext(ctx,
+			"INSERT INTO staff_backup_codes (staff_id, code_hash) VALUES ($1, $2)", staffID, codeHash,
+		); err != nil {
+			return "", fmt.Errorf("error storing backup code: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", fmt.Errorf("error committing enrollment: %w", err)
+	}
+	return username, nil
+}
+
+// GetTwoFactor retrieves a staff member's second factor, pending or enabled.
+// It returns ErrTwoFactorNotFound if they have not enrolled.
+func (r *TwoFactorRepository) GetTwoFactor(ctx context.Context, staffID int) (*models.StaffTwoFactor, error) {
+	ctx = database.WithQueryName(ctx, "staff_two_factor.get")
+	factor := models.StaffTwoFactor{StaffID: staffID}
+	var storedSecret string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT secret, enabled_at IS NOT NULL, last_used_step
+		FROM staff_two_factor
+		WHERE staff_id = $1`, staffID).Scan(&storedSecret, &factor.Enabled, &factor.LastUsedStep)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTwoFactorNotFound
+		}
+		return nil, fmt.Errorf("error querying two-factor: %w", err)
+	}
+
+	if factor.Secret, err = r.cipher.Decrypt(storedSecret); err != nil {
+		return nil, fmt.Errorf("error decrypting TOTP secret: %w", err)
+	}
+	return &factor, nil
+}
+
+// UseTOTPStep records that a staff member's code for time step was accepted,
+// enabling a pending factor. It reports false, recording nothing, if a code
+// for that step or a later one was already accepted, so codes cannot be
+// replayed.
+func (r *TwoFactorRepository) UseTOTPStep(ctx context.Context, staffID int, step int64) (bool, error) {
+	ctx = database.WithQueryName(ctx, "staff_two_factor.use_step")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE staff_two_factor
+		SET last_used_step = $2, enabled_at = COALESCE(enabled_at, NOW())
+		WHERE staff_id = $1 AND last_used_step < $2`, staffID, step)
+	if err != nil {
+		return false, fmt.Errorf("error recording TOTP step: %w", err)
+	}
+	used, _ := result.RowsAffected()
+	return used > 0, nil
+}
+
+// UseBackupCode spends the unused backup code stored as codeHash of a staff
+// member with two-factor authentication enabled. It reports false if there
+// is no such code.
+func (r *TwoFactorRepository) UseBackupCode(ctx context.Context, staffID int, codeHash string) (bool, error) {
+	ctx = database.WithQueryName(ctx, "staff_backup_codes.use")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE staff_backup_codes c
+		SET used_at = NOW()
+		FROM staff_two_factor f
+		WHERE c.staff_id = $1 AND c.code_hash = $2 AND c.used_at IS NULL
+			AND f.staff_id = c.staff_id AND f.enabled_at IS NOT NULL`, staffID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("error using backup code: %w", err)
+	}
+	used, _ := result.RowsAffected()
+	return used > 0, nil
+}
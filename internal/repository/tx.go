@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+)
+
+// rollback rolls back tx unless it was already committed; deferred by every
+// repository method that opens a transaction.
+func rollback(tx *sql.Tx, operation string) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		slog.Error("Failed to roll back transaction", "operation", operation, "error", err)
+	}
+}
@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// webhookSubscriptionColumns lists the webhook_subscriptions columns scanned
+// by scanWebhookSubscription, in order. The secret is scanned separately.
+const webhookSubscriptionColumns = `id, url, events, active, secret_rotated_at, created_at, updated_at`
+
+// webhookDeliveryColumns lists the webhook_deliveries columns scanned by
+// scanWebhookDelivery, in order.
+const webhookDeliveryColumns = `id, subscription_id, event, payload, status, attempts,
+		response_status, last_error, created_at, updated_at`
+
+// WebhookRepository handles database operations for webhook subscriptions
+// and their delivery history.
+type WebhookRepository struct {
+	db *database.DB
+}
+
+// NewWebhookRepository creates a new webhook repository.
+func NewWebhookRepository(db *database.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// ListSubscriptions retrieves every webhook subscription, without secrets.
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	query := "SELECT " + webhookSubscriptionColumns + " FROM webhook_subscriptions ORDER BY id"
+
+	rows, err := r.db.QueryContext(database.WithQueryName(ctx, "webhooks.list"), query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		subscription, scanErr := scanWebhookSubscription(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning webhook subscription: %w", scanErr)
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating webhook subscriptions: %w", rowsErr)
+	}
+
+	return subscriptions, nil
+}
+
+// CreateSubscription adds a webhook subscription signed with secret.
+func (r *WebhookRepository) CreateSubscription(
+	ctx context.Context,
+	subscriptionReq models.WebhookSubscriptionRequest,
+	secret string,
+) (*models.WebhookSubscription, error) {
+	query := `
+		INSERT INTO webhook_subscriptions (url, events, secret)
+		VALUES ($1, $2, $3)
+		RETURNING ` + webhookSubscriptionColumns + `, secret`
+
+	insertCtx := database.WithQueryName(ctx, "webhooks.insert")
+	var scannedSecret string
+	subscription, err := scanWebhookSubscription(
+		r.db.QueryRowContext(insertCtx, query, subscriptionReq.URL, pq.Array(subscriptionReq.Events), secret),
+		&scannedSecret,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting webhook subscription: %w", err)
+	}
+	subscription.Secret = scannedSecret
+
+	return subscription, nil
+}
+
+// DeleteSubscription removes a webhook subscription and its delivery history.
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID int) error {
+	deleteCtx := database.WithQueryName(ctx, "webhooks.delete")
+	result, err := r.db.ExecContext(deleteCtx, "DELETE FROM webhook_subscriptions WHERE id = $1", subscriptionID)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook subscription: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting webhook subscription: %w", err)
+	}
+	if deleted == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// RotateSecret replaces a subscription's signing secret. Deliveries are also
+// signed with the replaced secret until grace has passed.
+func (r *WebhookRepository) RotateSecret(
+	ctx context.Context,
+	subscriptionID int,
+	secret string,
+	grace time.Duration,
+) (*models.WebhookSubscription, error) {
+	query := `
+		UPDATE webhook_subscriptions
+		SET previous_secret = secret,
+			previous_secret_expires_at = NOW() + $3 * INTERVAL '1 second',
+			secret = $2,
+			secret_rotated_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + webhookSubscriptionColumns + `, secret`
+
+	rotateCtx := database.WithQueryName(ctx, "webhooks.rotate_secret")
+	var scannedSecret string
+	subscription, err := scanWebhookSubscription(
+		r.db.QueryRowContext(rotateCtx, query, subscriptionID, secret, grace.Seconds()),
+		&scannedSecret,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("error rotating webhook secret: %w", err)
+	}
+	subscription.Secret = scannedSecret
+
+	return subscription, nil
+}
+
+// CreateDeliveries records a pending delivery of event to every active
+// subscription to it, returning the new delivery IDs.
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, event string, payload []byte) ([]int64, error) {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event, payload)
+		SELECT id, $1, $2 FROM webhook_subscriptions
+		WHERE active AND $1 = ANY(events)
+		RETURNING id`
+
+	insertCtx := database.WithQueryName(ctx, "webhooks.insert_deliveries")
+	rows, err := r.db.QueryContext(insertCtx, query, event, payload)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveryIDs := []int64{}
+	for rows.Next() {
+		var deliveryID int64
+		if scanErr := rows.Scan(&deliveryID); scanErr != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %w", scanErr)
+		}
+		deliveryIDs = append(deliveryIDs, deliveryID)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", rowsErr)
+	}
+
+	return deliveryIDs, nil
+}
+
+// GetDeliveryTarget retrieves a delivery with its subscription's endpoint and
+// current signing secrets.
+func (r *WebhookRepository) GetDeliveryTarget(
+	ctx context.Context,
+	deliveryID int64,
+) (*models.WebhookDeliveryTarget, error) {
+	query := `
+		SELECT d.id, d.subscription_id, d.event, d.payload, d.status, d.attempts,
+			d.response_status, d.last_error, d.created_at, d.updated_at,
+			s.url, s.active, s.secret,
+			CASE WHEN s.previous_secret_expires_at > NOW() THEN s.previous_secret END
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.id = $1`
+
+	var target models.WebhookDeliveryTarget
+	targetCtx := database.WithQueryName(ctx, "webhooks.delivery_target")
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(targetCtx, query, deliveryID),
+		&target.URL, &target.Active, &target.Secret, &target.PreviousSecret)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("error querying webhook delivery: %w", err)
+	}
+	target.Delivery = *delivery
+
+	return &target, nil
+}
+
+// RecordDeliveryAttempt stores the outcome of an attempt to send a delivery.
+func (r *WebhookRepository) RecordDeliveryAttempt(
+	ctx context.Context,
+	deliveryID int64,
+	status string,
+	responseStatus *int,
+	lastError *string,
+) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, response_status = $3, last_error = $4, updated_at = NOW()
+		WHERE id = $1`
+
+	recordCtx := database.WithQueryName(ctx, "webhooks.record_attempt")
+	if _, err := r.db.ExecContext(recordCtx, query, deliveryID, status, responseStatus, lastError); err != nil {
+		return fmt.Errorf("error recording webhook delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries retrieves a subscription's most recent deliveries.
+func (r *WebhookRepository) ListDeliveries(
+	ctx context.Context,
+	subscriptionID int,
+	filters models.WebhookDeliveryFilters,
+) ([]models.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3`
+
+	listCtx := database.WithQueryName(ctx, "webhooks.list_deliveries")
+	rows, err := r.db.QueryContext(listCtx, query, subscriptionID, filters.Status, filters.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, scanErr := scanWebhookDelivery(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %w", scanErr)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", rowsErr)
+	}
+
+	return deliveries, nil
+}
+
+// ResetFailedDelivery returns a subscription's failed delivery to pending so
+// it can be sent again.
+func (r *WebhookRepository) ResetFailedDelivery(
+	ctx context.Context,
+	subscriptionID int,
+	deliveryID int64,
+) (*models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries SET status = 'pending', updated_at = NOW()
+		WHERE id = $1 AND subscription_id = $2 AND status = 'failed'
+		RETURNING ` + webhookDeliveryColumns
+
+	resetCtx := database.WithQueryName(ctx, "webhooks.reset_delivery")
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(resetCtx, query, deliveryID, subscriptionID))
+	if err == nil {
+		return delivery, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("error resetting webhook delivery: %w", err)
+	}
+
+	// Distinguish a missing delivery from one that has not failed.
+	var exists bool
+	existsCtx := database.WithQueryName(ctx, "webhooks.delivery_exists")
+	err = r.db.QueryRowContext(existsCtx,
+		"SELECT EXISTS (SELECT 1 FROM webhook_deliveries WHERE id = $1 AND subscription_id = $2)",
+		deliveryID, subscriptionID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhook delivery: %w", err)
+	}
+	if !exists {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return nil, ErrWebhookDeliveryNotFailed
+}
+
+// PurgeDeliveries deletes deliveries created before the given time and
+// returns how many were deleted.
+func (r *WebhookRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	purgeCtx := database.WithQueryName(ctx, "webhooks.purge_deliveries")
+	result, err := r.db.ExecContext(purgeCtx, "DELETE FROM webhook_deliveries WHERE created_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("error purging webhook deliveries: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error counting purged webhook deliveries: %w", err)
+	}
+
+	return purged, nil
+}
+
+// scanWebhookSubscription scans a row selected with
+// webhookSubscriptionColumns, followed by any extra destinations.
+func scanWebhookSubscription(
+	row interface{ Scan(dest ...any) error },
+	extra ...any,
+) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	var events pq.StringArray
+	dest := []any{
+		&subscription.ID, &subscription.URL, &events, &subscription.Active,
+		&subscription.SecretRotatedAt, &subscription.CreatedAt, &subscription.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	subscription.Events = events
+	return &subscription, nil
+}
+
+// scanWebhookDelivery scans a row selected with webhookDeliveryColumns,
+// followed by any extra destinations.
+func scanWebhookDelivery(row interface{ Scan(dest ...any) error }, extra ...any) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload []byte
+	dest := []any{
+		&delivery.ID, &delivery.SubscriptionID, &delivery.Event, &payload, &delivery.Status, &delivery.Attempts,
+		&delivery.ResponseStatus, &delivery.LastError, &delivery.CreatedAt, &delivery.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+	return &delivery, nil
+}
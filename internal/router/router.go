@@ -0,0 +1,232 @@
+// Package router registers HTTP routes and the middleware around them on a
+// net/http.ServeMux.
+//
+// Patterns are ServeMux patterns: an optional method, then a path whose
+// {name} segments a handler reads with r.PathValue. A GET route also answers
+// HEAD. Routes are grouped under a path prefix with their own middleware:
+//
+//	r := router.New()
+//	r.Use(middleware.ReportErrors(reporter))
+//	api := r.Group("/api/v1", middleware.StoreScope)
+//	api.HandleFunc("GET /films/{id}", filmHandler.GetFilmByID, caching.catalog)
+//	api.HandleFunc("GET", handlers.APIInfoHandler) // GET /api/v1 itself
+//
+// The most specific pattern wins regardless of registration order, so
+// "/films/trending" is matched before "/films/{id}", and two patterns that
+// match the same requests with neither more specific panic when registered.
+package router
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Middleware wraps a handler, such as to authenticate or time it.
+type Middleware func(http.Handler) http.Handler
+
+// Router registers routes and the middleware around them.
+type Router interface {
+	// Handle registers handler for pattern, wrapped in middleware.
+	Handle(pattern string, handler http.Handler, middleware ...Middleware)
+
+	// HandleFunc registers a handler function for pattern, wrapped in middleware.
+	HandleFunc(pattern string, handler http.HandlerFunc, middleware ...Middleware)
+
+	// Use adds middleware around every route of the router and its groups.
+	Use(middleware ...Middleware)
+
+	// Group returns a router for routes under prefix, wrapped in middleware.
+	Group(prefix string, middleware ...Middleware) Router
+}
+
+// Mux is a Router backed by a net/http.ServeMux. Requests that match no
+// route are answered by its not-found handler, and those whose path matches
+// only routes for other methods by its method-not-allowed handler, with the
+// Allow header already set.
+type Mux struct {
+	*group
+
+	mux              *http.ServeMux
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+}
+
+// Option configures a Mux.
+type Option func(*Mux)
+
+// WithNotFound sets the handler for requests that match no route.
+func WithNotFound(handler http.Handler) Option {
+	return func(m *Mux) {
+		m.notFound = handler
+	}
+}
+
+// WithMethodNotAllowed sets the handler for requests whose path matches
+// only routes for other methods.
+func WithMethodNotAllowed(handler http.Handler) Option {
+	return func(m *Mux) {
+		m.methodNotAllowed = handler
+	}
+}
+
+// New creates a Mux with net/http's plain-text 404 and 405 responses unless
+// options replace them.
+func New(opts ...Option) *Mux {
+	m := &Mux{mux: http.NewServeMux()}
+	m.group = &group{mux: m}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// ServeHTTP dispatches the request to the route its method and path match.
+func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, pattern := m.mux.Handler(r)
+	if pattern != "" {
+		m.mux.ServeHTTP(w, r)
+		return
+	}
+
+	// The ServeMux answers both misses itself, setting Allow on a 405.
+	probe := &headerRecorder{header: make(http.Header)}
+	handler.ServeHTTP(probe, r)
+	switch {
+	case probe.status == http.StatusMethodNotAllowed && m.methodNotAllowed != nil:
+		w.Header().Set("Allow", probe.header.Get("Allow"))
+		m.methodNotAllowed.ServeHTTP(w, r)
+	case probe.status != http.StatusMethodNotAllowed && m.notFound != nil:
+		m.notFound.ServeHTTP(w, r)
+	default:
+		handler.ServeHTTP(w, r)
+	}
+}
+
+// Match reports whether a route matches the request as it is, without the
+// redirect the ServeMux sends for a path that is not clean.
+func (m *Mux) Match(r *http.Request) bool {
+	if cleanPath(r.URL.Path) != r.URL.Path {
+		return false
+	}
+	_, pattern := m.mux.Handler(r)
+	return pattern != ""
+}
+
+// group is the Router for the routes under a prefix. The middleware of a
+// group and of each group enclosing it wraps every route registered on it,
+// outermost first, including middleware added after the route.
+type group struct {
+	mux        *Mux
+	parent     *group
+	prefix     string
+	middleware []Middleware
+}
+
+func (g *group) Handle(pattern string, handler http.Handler, middleware ...Middleware) {
+	method, routePath := splitPattern(pattern)
+	full := g.fullPrefix() + routePath
+	if method != "" {
+		full = method + " " + full
+	}
+	g.mux.mux.Handle(full, &route{group: g, handler: handler, middleware: middleware})
+}
+
+func (g *group) HandleFunc(pattern string, handler http.HandlerFunc, middleware ...Middleware) {
+	g.Handle(pattern, handler, middleware...)
+}
+
+func (g *group) Use(middleware ...Middleware) {
+	g.middleware = append(g.middleware, middleware...)
+}
+
+func (g *group) Group(prefix string, middleware ...Middleware) Router {
+	return &group{mux: g.mux, parent: g, prefix: prefix, middleware: middleware}
+}
+
+func (g *group) fullPrefix() string {
+	if g.parent == nil {
+		return g.prefix
+	}
+	return g.parent.fullPrefix() + g.prefix
+}
+
+// route is a registered handler. Its middleware chain is built on the first
+// request, once every group has all of its middleware.
+type route struct {
+	group      *group
+	handler    http.Handler
+	middleware []Middleware
+
+	once  sync.Once
+	chain http.Handler
+}
+
+func (rt *route) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.once.Do(func() {
+		h := wrap(rt.handler, rt.middleware)
+		for g := rt.group; g != nil; g = g.parent {
+			h = wrap(h, g.middleware)
+		}
+		rt.chain = h
+	})
+	rt.chain.ServeHTTP(w, r)
+}
+
+// wrap applies middleware to h, the first outermost.
+func wrap(h http.Handler, middleware []Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// splitPattern splits a pattern into its method, if any, and path. A
+// pattern of only a method, such as "GET", has an empty path, so matches its
+// group's prefix itself.
+func splitPattern(pattern string) (string, string) {
+	method, routePath, found := strings.Cut(pattern, " ")
+	if !found {
+		if strings.HasPrefix(pattern, "/") {
+			return "", pattern
+		}
+		return pattern, ""
+	}
+	return method, strings.TrimLeft(routePath, " ")
+}
+
+// cleanPath returns the path the ServeMux would redirect p to: p cleaned,
+// keeping a trailing slash.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	cleaned := path.Clean(p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// headerRecorder records the status and headers the ServeMux answers a miss
+// with, discarding the body.
+type headerRecorder struct {
+	header http.Header
+	status int
+}
+
+func (h *headerRecorder) Header() http.Header { return h.header }
+
+func (h *headerRecorder) Write(b []byte) (int, error) {
+	if h.status == 0 {
+		h.status = http.StatusOK
+	}
+	return len(b), nil
+}
+
+func (h *headerRecorder) WriteHeader(code int) {
+	if h.status == 0 {
+		h.status = code
+	}
+}
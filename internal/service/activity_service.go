@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrSelfFollow is returned when a customer tries to follow themselves.
+var ErrSelfFollow = apperrors.New(apperrors.Invalid, "customers cannot follow themselves")
+
+// ErrFeedRequiresCustomer is returned when the feed is requested without a
+// customer token.
+var ErrFeedRequiresCustomer = apperrors.New(apperrors.Invalid, "a customer token is required")
+
+// ActivityRecorder records customers' public activity for their followers'
+// feeds.
+type ActivityRecorder interface {
+	RecordActivity(ctx context.Context, activity models.Activity) error
+}
+
+// activityServiceImpl implements the ActivityService interface.
+type activityServiceImpl struct {
+	activityRepo repository.ActivityRepositoryInterface
+}
+
+// NewActivityService creates a new activity service with the given
+// repository.
+func NewActivityService(activityRepo repository.ActivityRepositoryInterface) ActivityService {
+	return &activityServiceImpl{activityRepo: activityRepo}
+}
+
+// Follow makes followerID follow followedID.
+func (s *activityServiceImpl) Follow(ctx context.Context, followerID, followedID int) error {
+	if followerID == followedID {
+		return ErrSelfFollow
+	}
+
+	if err := s.activityRepo.Follow(ctx, followerID, followedID); err != nil {
+		if !errors.Is(err, repository.ErrCustomerNotFound) {
+			slog.Error("Failed to follow customer", "followerID", followerID, "followedID", followedID, "error", err)
+		}
+		return err
+	}
+
+	slog.Info("Customer followed", "followerID", followerID, "followedID", followedID)
+	return nil
+}
+
+// Unfollow stops followerID following followedID.
+func (s *activityServiceImpl) Unfollow(ctx context.Context, followerID, followedID int) error {
+	if err := s.activityRepo.Unfollow(ctx, followerID, followedID); err != nil {
+		if !errors.Is(err, repository.ErrNotFollowing) {
+			slog.Error("Failed to unfollow customer", "followerID", followerID, "followedID", followedID, "error", err)
+		}
+		return err
+	}
+
+	slog.Info("Customer unfollowed", "followerID", followerID, "followedID", followedID)
+	return nil
+}
+
+// ListFollowing retrieves the customers a customer follows, most recently
+// followed first.
+func (s *activityServiceImpl) ListFollowing(ctx context.Context, customerID int) ([]models.Follow, error) {
+	return s.activityRepo.ListFollowing(ctx, customerID)
+}
+
+// GetFeed retrieves a page of the activity of the customers followed by the
+// customer whose token made the request.
+func (s *activityServiceImpl) GetFeed(
+	ctx context.Context,
+	filters models.ActivityFeedFilters,
+) (*pagination.Paginated[models.Activity], error) {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok || claims.Role != auth.RoleCustomer {
+		return nil, ErrFeedRequiresCustomer
+	}
+
+	return s.activityRepo.GetFeed(ctx, claims.Subject, filters)
+}
+
+// RecordActivity adds an entry to a customer's public activity.
+func (s *activityServiceImpl) RecordActivity(ctx context.Context, activity models.Activity) error {
+	return s.activityRepo.RecordActivity(ctx, activity)
+}
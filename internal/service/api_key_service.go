@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// apiKeyServiceImpl implements the APIKeyService interface.
+type apiKeyServiceImpl struct {
+	apiKeyRepo repository.APIKeyRepositoryInterface
+	now        func() time.Time
+}
+
+// NewAPIKeyService creates a new API key service with the given repository.
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepositoryInterface) APIKeyService {
+	return &apiKeyServiceImpl{apiKeyRepo: apiKeyRepo, now: time.Now}
+}
+
+// CreateKey issues a new API key. The key is only returned here; afterwards
+// only its prefix is shown.
+func (s *apiKeyServiceImpl) CreateKey(ctx context.Context, keyReq models.APIKeyRequest) (*models.APIKey, error) {
+	rawKey, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.apiKeyRepo.CreateAPIKey(ctx, keyReq, prefix, hash)
+	if err != nil {
+		slog.Error("Failed to create API key", "name", keyReq.Name, "error", err)
+		return nil, err
+	}
+	key.Key = rawKey
+
+	slog.Info("API key created", "keyID", key.ID, "tier", key.Tier)
+	return key, nil
+}
+
+// ListKeys retrieves every API key, including revoked ones.
+func (s *apiKeyServiceImpl) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	keys, err := s.apiKeyRepo.ListAPIKeys(ctx)
+	if err != nil {
+		slog.Error("Failed to retrieve API keys from repository", "error", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RevokeKey revokes an API key; requests using it are rejected from then on.
+func (s *apiKeyServiceImpl) RevokeKey(ctx context.Context, keyID int) (*models.APIKey, error) {
+	key, err := s.apiKeyRepo.RevokeAPIKey(ctx, keyID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrAPIKeyNotFound) {
+			slog.Error("Failed to revoke API key", "keyID", keyID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("API key revoked", "keyID", keyID)
+	return key, nil
+}
+
+// Authenticate returns the active API key matching rawKey, or
+// auth.ErrInvalidAPIKey.
+func (s *apiKeyServiceImpl) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	key, err := s.apiKeyRepo.GetActiveAPIKeyByHash(ctx, auth.HashAPIKey(rawKey))
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return nil, auth.ErrInvalidAPIKey
+		}
+		slog.Error("Failed to look up API key", "error", err)
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// RecordRequest counts a request against key's monthly quota, reporting
+// whether it was within the quota and the resulting usage.
+func (s *apiKeyServiceImpl) RecordRequest(ctx context.Context, key *models.APIKey) (*models.APIKeyUsage, bool, error) {
+	limits, err := tierLimits(key.Tier)
+	if err != nil {
+		return nil, false, err
+	}
+
+	period := monthStart(s.now())
+	used, allowed, err := s.apiKeyRepo.IncrementUsage(ctx, key.ID, period, limits.MonthlyQuota)
+	if err != nil {
+		slog.Error("Failed to record API key usage", "keyID", key.ID, "error", err)
+		return nil, false, err
+	}
+
+	return newAPIKeyUsage(key.Tier, limits, period, used), allowed, nil
+}
+
+// GetUsage reports key's consumption in the current month.
+func (s *apiKeyServiceImpl) GetUsage(ctx context.Context, key *models.APIKey) (*models.APIKeyUsage, error) {
+	limits, err := tierLimits(key.Tier)
+	if err != nil {
+		return nil, err
+	}
+
+	period := monthStart(s.now())
+	used, err := s.apiKeyRepo.GetUsage(ctx, key.ID, period)
+	if err != nil {
+		slog.Error("Failed to retrieve API key usage", "keyID", key.ID, "error", err)
+		return nil, err
+	}
+
+	return newAPIKeyUsage(key.Tier, limits, period, used), nil
+}
+
+func tierLimits(tier string) (models.APIKeyTierLimits, error) {
+	limits, ok := models.TierLimits(tier)
+	if !ok {
+		return models.APIKeyTierLimits{}, fmt.Errorf("unknown API key tier %q", tier)
+	}
+	return limits, nil
+}
+
+// monthStart returns the start of the UTC calendar month containing t, which
+// identifies the quota period.
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func newAPIKeyUsage(tier string, limits models.APIKeyTierLimits, period time.Time, used int64) *models.APIKeyUsage {
+	usage := &models.APIKeyUsage{
+		Tier:     tier,
+		Limits:   limits,
+		Period:   period.Format("2006-01"),
+		Used:     used,
+		ResetsAt: period.AddDate(0, 1, 0),
+	}
+	if limits.MonthlyQuota > 0 {
+		remaining := max(limits.MonthlyQuota-used, 0)
+		usage.Remaining = &remaining
+	}
+	return usage
+}
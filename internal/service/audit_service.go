@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// auditServiceImpl implements the AuditService interface.
+type auditServiceImpl struct {
+	auditRepo repository.AuditRepositoryInterface
+}
+
+// NewAuditService creates a new audit log service.
+func NewAuditService(auditRepo repository.AuditRepositoryInterface) AuditService {
+	return &auditServiceImpl{auditRepo: auditRepo}
+}
+
+// GetStaffActivity retrieves a page of the audited actions a staff member
+// has taken, newest first, narrowed by filters.
+func (s *auditServiceImpl) GetStaffActivity(
+	ctx context.Context,
+	staffID int,
+	filters models.AuditFilters,
+) (*pagination.Paginated[models.AuditEntry], error) {
+	models.AuditLimits.Normalize(&filters.Params)
+
+	activity, err := s.auditRepo.ListStaffActivity(ctx, staffID, filters)
+	if err != nil {
+		if !errors.Is(err, repository.ErrStaffNotFound) {
+			slog.Error("Failed to list staff activity", "staffID", staffID, "error", err)
+		}
+		return nil, err
+	}
+	return activity, nil
+}
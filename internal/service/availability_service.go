@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrAlertRequiresCustomer is returned when an availability alert is
+// requested without a customer token.
+var ErrAlertRequiresCustomer = apperrors.New(apperrors.Invalid, "a customer token is required")
+
+// AvailabilityNotifier queues a notice that a film a customer is waiting for
+// can be rented.
+type AvailabilityNotifier interface {
+	NotifyFilmAvailable(available notifications.FilmAvailable) error
+}
+
+// availabilityServiceImpl implements the AvailabilityService interface.
+type availabilityServiceImpl struct {
+	availabilityRepo repository.AvailabilityRepositoryInterface
+	notifier         AvailabilityNotifier
+}
+
+// NewAvailabilityService creates a new availability alert service sending
+// notices through notifier.
+func NewAvailabilityService(
+	availabilityRepo repository.AvailabilityRepositoryInterface,
+	notifier AvailabilityNotifier,
+) AvailabilityService {
+	return &availabilityServiceImpl{availabilityRepo: availabilityRepo, notifier: notifier}
+}
+
+// NotifyMe registers the customer whose token made the request to hear when
+// a copy of filmID is available. Asking again while an alert is pending
+// returns that alert with created false.
+func (s *availabilityServiceImpl) NotifyMe(
+	ctx context.Context,
+	filmID int,
+) (alert *models.AvailabilityAlert, created bool, err error) {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok || claims.Role != auth.RoleCustomer {
+		return nil, false, ErrAlertRequiresCustomer
+	}
+
+	alert, created, err = s.availabilityRepo.CreateAlert(ctx, claims.Subject, filmID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrFilmNotFound) {
+			slog.Error("Failed to create availability alert", "customerID", claims.Subject, "filmID", filmID,
+				"error", err)
+		}
+		return nil, false, err
+	}
+
+	if created {
+		slog.Info("Availability alert created", "customerID", claims.Subject, "filmID", filmID)
+	}
+	return alert, created, nil
+}
+
+// NotifyAvailable queues a notice for each customer waiting for filmID.
+// Alerts are claimed before queueing, so a failed send is logged rather than
+// retried, and every claimed alert is queued even if ctx is canceled.
+func (s *availabilityServiceImpl) NotifyAvailable(ctx context.Context, filmID int) error {
+	notices, err := s.availabilityRepo.ClaimAlerts(ctx, filmID)
+	if err != nil {
+		return err
+	}
+
+	for _, notice := range notices {
+		notifyErr := s.notifier.NotifyFilmAvailable(notifications.FilmAvailable{
+			RecipientID:    notice.CustomerID,
+			RecipientEmail: notice.CustomerEmail,
+			RecipientName:  notice.CustomerName,
+			FilmTitle:      notice.FilmTitle,
+		})
+		if notifyErr != nil {
+			slog.Warn("Failed to queue availability notice", "alert_id", notice.AlertID, "error", notifyErr)
+		}
+	}
+
+	if len(notices) > 0 {
+		slog.Info("Availability notices queued", "filmID", filmID, "count", len(notices))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// backgroundJobServiceImpl implements the BackgroundJobService interface.
+type backgroundJobServiceImpl struct {
+	jobRepo   repository.BackgroundJobRepositoryInterface
+	retention time.Duration
+}
+
+// NewBackgroundJobService creates a new background job service. Succeeded
+// and dead jobs are kept for retention before they are purged.
+func NewBackgroundJobService(
+	jobRepo repository.BackgroundJobRepositoryInterface,
+	retention time.Duration,
+) BackgroundJobService {
+	return &backgroundJobServiceImpl{jobRepo: jobRepo, retention: retention}
+}
+
+// ListJobs retrieves recently updated jobs matching filters.
+func (s *backgroundJobServiceImpl) ListJobs(
+	ctx context.Context,
+	filters models.BackgroundJobFilters,
+) ([]models.BackgroundJob, error) {
+	jobs, err := s.jobRepo.ListJobs(ctx, filters)
+	if err != nil {
+		slog.Error("Failed to retrieve background jobs from repository", "filters", filters, "error", err)
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetJob retrieves a job by its ID.
+func (s *backgroundJobServiceImpl) GetJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error) {
+	job, err := s.jobRepo.GetJob(ctx, jobID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrJobNotFound) {
+			slog.Error("Failed to retrieve background job from repository", "jobID", jobID, "error", err)
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// RetryJob requeues a dead job with a fresh set of attempts.
+func (s *backgroundJobServiceImpl) RetryJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error) {
+	job, err := s.jobRepo.RequeueJob(ctx, jobID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrJobNotFound) && !errors.Is(err, repository.ErrJobNotDead) {
+			slog.Error("Failed to requeue background job", "jobID", jobID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Warn("Background job requeued by admin request", "jobID", jobID, "kind", job.Kind)
+	return job, nil
+}
+
+// PurgeJobs deletes succeeded and dead jobs last updated before the
+// retention period.
+func (s *backgroundJobServiceImpl) PurgeJobs(ctx context.Context) error {
+	purged, err := s.jobRepo.PurgeJobs(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Background jobs purged", "count", purged, "retention", s.retention)
+	return nil
+}
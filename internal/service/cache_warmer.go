@@ -0,0 +1,42 @@
+// Package service provides business logic services for the Mockbuster API.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+// WarmFilmCache loads the category list and the first pages of the default
+// film listing through filmService so that a caching repository behind it is
+// populated before real traffic arrives. It stops at the first failure or
+// when ctx is cancelled.
+func WarmFilmCache(ctx context.Context, filmService FilmService, pages, pageSize int) error {
+	start := time.Now()
+
+	if _, err := filmService.GetCategories(ctx); err != nil {
+		return fmt.Errorf("error warming categories: %w", err)
+	}
+
+	for page := 1; page <= pages; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		filters := models.FilmFilters{Params: pagination.Params{Page: page, Limit: pageSize}}
+		films, err := filmService.GetFilms(ctx, filters)
+		if err != nil {
+			return fmt.Errorf("error warming film page %d: %w", page, err)
+		}
+		if len(films.Items) < pageSize {
+			break
+		}
+	}
+
+	slog.Info("Film cache warmed", "pages", pages, "duration", time.Since(start))
+	return nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/ical"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// calendarRentalLimit bounds each of the open and overdue rentals shown in
+// a calendar.
+const calendarRentalLimit = 100
+
+// calendarServiceImpl implements the CalendarService interface.
+type calendarServiceImpl struct {
+	rentalRepo repository.RentalRepositoryInterface
+	tokens     *auth.TokenIssuer
+	baseURL    string
+}
+
+// NewCalendarService creates a new calendar service. Calendar URLs start
+// with baseURL and carry tokens from tokens, which must issue
+// auth.RoleCalendar tokens.
+func NewCalendarService(
+	rentalRepo repository.RentalRepositoryInterface,
+	tokens *auth.TokenIssuer,
+	baseURL string,
+) CalendarService {
+	return &calendarServiceImpl{rentalRepo: rentalRepo, tokens: tokens, baseURL: baseURL}
+}
+
+// IssueCalendarURL returns the URL a customer subscribes to for their
+// rental due dates. Each call issues a new token; earlier URLs keep working
+// until their tokens expire.
+func (s *calendarServiceImpl) IssueCalendarURL(
+	_ context.Context,
+	customerID int,
+) (*models.CalendarSubscription, error) {
+	token, expiresAt, err := s.tokens.Issue(customerID)
+	if err != nil {
+		slog.Error("Failed to issue calendar token", "customerID", customerID, "error", err)
+		return nil, err
+	}
+
+	calendarURL := fmt.Sprintf("%s/api/v1/customers/%d/rentals.ics?token=%s",
+		s.baseURL, customerID, url.QueryEscape(token))
+	return &models.CalendarSubscription{URL: calendarURL, ExpiresAt: expiresAt}, nil
+}
+
+// RentalCalendar builds the calendar of a customer's rentals not yet
+// returned, with an event at each one's due date, soonest first. Returned
+// rentals drop off the calendar.
+func (s *calendarServiceImpl) RentalCalendar(ctx context.Context, customerID int) (*ical.Calendar, error) {
+	var rentals []models.RentalEvent
+	for _, status := range []string{models.RentalStatusOverdue, models.RentalStatusOpen} {
+		history, err := s.rentalRepo.GetCustomerRentals(ctx, customerID, models.RentalHistoryFilters{
+			Status: status,
+			Params: pagination.Params{Page: 1, Limit: calendarRentalLimit},
+		})
+		if err != nil {
+			if !errors.Is(err, repository.ErrCustomerNotFound) {
+				slog.Error("Failed to retrieve rentals for calendar", "customerID", customerID, "error", err)
+			}
+			return nil, err
+		}
+		rentals = append(rentals, history.Items...)
+	}
+	sort.SliceStable(rentals, func(i, j int) bool { return rentals[i].DueAt.Before(rentals[j].DueAt) })
+
+	calendar := &ical.Calendar{Name: "Mockbuster rentals", Events: make([]ical.Event, 0, len(rentals))}
+	for _, rental := range rentals {
+		summary := "Return " + rental.FilmTitle
+		if rental.Status == models.RentalStatusOverdue {
+			summary = "Overdue: return " + rental.FilmTitle
+		}
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:     "rental-" + strconv.Itoa(rental.RentalID) + "@mockbuster",
+			Stamp:   rental.RentalDate,
+			Start:   rental.DueAt,
+			Summary: summary,
+			Description: fmt.Sprintf("Rented %s from store %d. Rental %d.",
+				rental.RentalDate.UTC().Format("2006-01-02"), rental.StoreID, rental.RentalID),
+			URL: s.baseURL + "/api/v1/films/" + strconv.Itoa(rental.FilmID),
+		})
+	}
+
+	return calendar, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/giftcards"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pricing"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// cartServiceImpl implements the CartService interface.
+type cartServiceImpl struct {
+	cartRepo     repository.CartRepositoryInterface
+	pricingRepo  repository.PricingRepositoryInterface
+	rentalPoints int
+}
+
+// CartServiceOption configures optional cart service behavior.
+type CartServiceOption func(*cartServiceImpl)
+
+// WithRentalPoints awards points loyalty points for each rental checked out.
+func WithRentalPoints(points int) CartServiceOption {
+	return func(s *cartServiceImpl) {
+		s.rentalPoints = points
+	}
+}
+
+// NewCartService creates a new cart service with the given repositories.
+func NewCartService(
+	cartRepo repository.CartRepositoryInterface,
+	pricingRepo repository.PricingRepositoryInterface,
+	opts ...CartServiceOption,
+) CartService {
+	s := &cartServiceImpl{
+		cartRepo:    cartRepo,
+		pricingRepo: pricingRepo,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetCart retrieves a customer's cart, with each film priced by the pricing
+// rules in effect now at the customer's store.
+func (s *cartServiceImpl) GetCart(ctx context.Context, customerID int) (*models.Cart, error) {
+	return s.pricedCart(ctx, customerID)
+}
+
+// AddItem adds a film to a customer's cart, returning the cart.
+func (s *cartServiceImpl) AddItem(ctx context.Context, customerID, filmID int) (*models.Cart, error) {
+	if err := s.cartRepo.AddCartItem(ctx, customerID, filmID); err != nil {
+		return nil, err
+	}
+	return s.pricedCart(ctx, customerID)
+}
+
+// RemoveItem removes a film from a customer's cart, returning the cart.
+func (s *cartServiceImpl) RemoveItem(ctx context.Context, customerID, filmID int) (*models.Cart, error) {
+	if err := s.cartRepo.RemoveCartItem(ctx, customerID, filmID); err != nil {
+		return nil, err
+	}
+	return s.pricedCart(ctx, customerID)
+}
+
+// Checkout rents out every film in a customer's cart at the rates the cart
+// shows, less the coupon's discount if a coupon code is given and the gift
+// card's balance if a gift card code is. Should any film be out of stock,
+// or the coupon or gift card be unusable, nothing is rented.
+func (s *cartServiceImpl) Checkout(
+	ctx context.Context,
+	customerID int,
+	checkoutReq models.CheckoutRequest,
+) (*models.Checkout, error) {
+	cart, err := s.pricedCart(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, repository.ErrCartEmpty
+	}
+
+	lines := make([]models.CheckoutLine, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		lines = append(lines, models.CheckoutLine{FilmID: item.FilmID, Rate: item.Rate})
+	}
+
+	var giftCardHash string
+	if checkoutReq.GiftCardCode != "" {
+		giftCardHash = giftcards.Hash(checkoutReq.GiftCardCode)
+	}
+
+	checkout, err := s.cartRepo.Checkout(ctx, customerID, lines, strings.ToUpper(checkoutReq.CouponCode), giftCardHash,
+		s.rentalPoints)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to check out cart", "customerID", customerID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Cart checked out", "checkoutID", checkout.ID, "customerID", customerID,
+		"rentals", len(checkout.Rentals), "total", checkout.Total)
+	return checkout, nil
+}
+
+// pricedCart retrieves a customer's cart and prices its films.
+func (s *cartServiceImpl) pricedCart(ctx context.Context, customerID int) (*models.Cart, error) {
+	cart, err := s.cartRepo.GetCart(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return cart, nil
+	}
+
+	rules, err := s.pricingRepo.ListPricingRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i, item := range cart.Items {
+		film := models.FilmPricing{FilmID: item.FilmID, RentalRate: item.BaseRate, CategoryIDs: item.CategoryIDs}
+		cart.Items[i].Rate = pricing.Quote(film, cart.StoreID, now, rules).EffectiveRate
+		cart.Subtotal += cart.Items[i].Rate
+	}
+	cart.Subtotal = math.Round(cart.Subtotal*100) / 100
+
+	return cart, nil
+}
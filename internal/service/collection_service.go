@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// collectionServiceImpl implements the CollectionService interface.
+type collectionServiceImpl struct {
+	collectionRepo repository.CollectionRepositoryInterface
+	invalidations  cache.Publisher
+}
+
+// NewCollectionService creates a new collection service. Films show the
+// collections they belong to, so changes to a collection's films are
+// published to invalidations to evict those films from the cache.
+func NewCollectionService(
+	collectionRepo repository.CollectionRepositoryInterface,
+	invalidations cache.Publisher,
+) CollectionService {
+	return &collectionServiceImpl{
+		collectionRepo: collectionRepo,
+		invalidations:  invalidations,
+	}
+}
+
+// CreateCollection stores a new collection with its films in order.
+func (s *collectionServiceImpl) CreateCollection(
+	ctx context.Context,
+	collectionReq models.CollectionRequest,
+) (*models.Collection, error) {
+	collection, err := s.collectionRepo.CreateCollection(ctx, collectionReq)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to create collection", "name", collectionReq.Name, "error", err)
+		}
+		return nil, err
+	}
+
+	s.filmsChanged(collectionReq.FilmIDs)
+	slog.Info("Collection created", "collectionID", collection.ID, "films", collection.FilmCount)
+	return collection, nil
+}
+
+// ListCollections retrieves every collection by name, without their films.
+func (s *collectionServiceImpl) ListCollections(ctx context.Context) ([]models.Collection, error) {
+	return s.collectionRepo.ListCollections(ctx)
+}
+
+// GetCollection retrieves a collection with its films in order.
+func (s *collectionServiceImpl) GetCollection(ctx context.Context, collectionID int) (*models.Collection, error) {
+	return s.collectionRepo.GetCollection(ctx, collectionID)
+}
+
+// UpdateCollection replaces a collection's name, description, and films.
+// Both the films it loses and the films it keeps or gains are evicted, as
+// each shows the collection's name and its position in it.
+func (s *collectionServiceImpl) UpdateCollection(
+	ctx context.Context,
+	collectionID int,
+	collectionReq models.CollectionRequest,
+) (*models.Collection, error) {
+	collection, previousFilmIDs, err := s.collectionRepo.UpdateCollection(ctx, collectionID, collectionReq)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to update collection", "collectionID", collectionID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.filmsChanged(append(previousFilmIDs, collectionReq.FilmIDs...))
+	slog.Info("Collection updated", "collectionID", collectionID, "films", collection.FilmCount)
+	return collection, nil
+}
+
+// DeleteCollection deletes a collection, keeping its films.
+func (s *collectionServiceImpl) DeleteCollection(ctx context.Context, collectionID int) error {
+	filmIDs, err := s.collectionRepo.DeleteCollection(ctx, collectionID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCollectionNotFound) {
+			slog.Error("Failed to delete collection", "collectionID", collectionID, "error", err)
+		}
+		return err
+	}
+
+	s.filmsChanged(filmIDs)
+	slog.Info("Collection deleted", "collectionID", collectionID)
+	return nil
+}
+
+// filmsChanged evicts the given films, and the listings they appear in,
+// from the cache.
+func (s *collectionServiceImpl) filmsChanged(filmIDs []int) {
+	if len(filmIDs) > 0 {
+		s.invalidations.Publish(cache.FilmsChanged(filmIDs...))
+	}
+}
@@ -4,16 +4,188 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/attachments"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/markdown"
+	"github.com/rxbenefits/go-hw/internal/metrics"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/pii"
 	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/webhooks"
 )
 
+// maxMentions is the most customers one comment may mention.
+const maxMentions = 10
+
+// ErrUnknownMention is returned when a comment mentions a customer who does
+// not exist or is inactive.
+var ErrUnknownMention = apperrors.New(apperrors.Invalid, "comment mentions an unknown customer")
+
+// ErrAttachmentsDisabled is returned when a comment has an attachment but
+// no storage backend is configured.
+var ErrAttachmentsDisabled = apperrors.New(apperrors.Invalid, "comment attachments are not enabled")
+
+// ErrTooManyMentions is returned when a comment mentions more than
+// maxMentions customers.
+var ErrTooManyMentions = apperrors.New(apperrors.Invalid, "comment mentions too many customers (max 10)")
+
+// ErrCustomerNameRequired is returned for guest comments without a
+// customer name.
+var ErrCustomerNameRequired = apperrors.New(apperrors.Invalid, "customer name is required for guest comments")
+
+// ErrCustomerNameTooLong is returned for a customer name over 100 characters.
+var ErrCustomerNameTooLong = apperrors.New(apperrors.Invalid, "customer name too long (max 100 characters)")
+
+// ErrCommentRequired is returned for a comment without any text.
+var ErrCommentRequired = apperrors.New(apperrors.Invalid, "comment text is required")
+
+// ErrCommentTooLong is returned for comment text over 1000 characters.
+var ErrCommentTooLong = apperrors.New(apperrors.Invalid, "comment text too long (max 1000 characters)")
+
+// ErrReservedCustomerName is returned for guest names starting with the
+// marker of encrypted values, which would be misread when stored.
+var ErrReservedCustomerName = apperrors.New(apperrors.Invalid,
+	"customer name must not start with \""+pii.Prefix+"\"")
+
+// mentionPattern matches a mention of a customer by ID, such as @42, that
+// is not part of a longer word or an email address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@(\d+)\b`) //nolint:gochecknoglobals // Read-only
+
+// commentSummaryPrefix prefixes each film's comment summary in the summary
+// cache.
+const commentSummaryPrefix = "comment_summary:"
+
+// commentKeywordLimit is how many top keywords a comment summary lists.
+const commentKeywordLimit = 10
+
+// commentStopwords are common English words left out of comment keywords.
+// Words under three letters are left out regardless.
+var commentStopwords = []string{ //nolint:gochecknoglobals // Read-only
+	"about", "after", "all", "also", "and", "any", "are", "because", "been", "but", "can", "could", "did",
+	"does", "don", "even", "film", "for", "from", "had", "has", "have", "her", "his", "how", "into", "its",
+	"just", "like", "more", "most", "movie", "much", "not", "one", "only", "other", "our", "out", "really",
+	"she", "should", "some", "than", "that", "the", "their", "them", "then", "there", "these", "they",
+	"this", "too", "very", "was", "watch", "were", "what", "when", "which", "who", "why", "will", "with",
+	"would", "you", "your",
+}
+
+// ReplyNotifier queues a notice to a comment's author about a reply.
+type ReplyNotifier interface {
+	NotifyCommentReply(reply notifications.CommentReply) error
+}
+
+// MentionNotifier queues a notice to a customer mentioned in a comment.
+type MentionNotifier interface {
+	NotifyCommentMention(mention notifications.CommentMention) error
+}
+
+// EventPublisher publishes domain events, such as to webhook subscribers.
+type EventPublisher interface {
+	Publish(event string, data any) error
+}
+
+// CommentPointsAwarder credits customers with loyalty points for comments.
+type CommentPointsAwarder interface {
+	AwardCommentPoints(ctx context.Context, customerID, commentID int) error
+}
+
+// GuestCommentRecorder records the comments guests post, so they can be
+// linked to the account a guest later registers.
+type GuestCommentRecorder interface {
+	RecordGuestComment(ctx context.Context, guestID, commentID int) error
+}
+
 // commentServiceImpl implements the CommentService interface.
 type commentServiceImpl struct {
-	commentRepo repository.CommentRepositoryInterface
-	filmRepo    repository.FilmRepositoryInterface
+	commentRepo   repository.CommentRepositoryInterface
+	filmRepo      repository.FilmRepositoryInterface
+	replyNotifier ReplyNotifier
+	mentions      MentionNotifier
+	events        EventPublisher
+	pointsAwarder CommentPointsAwarder
+	activity      ActivityRecorder
+	guestComments GuestCommentRecorder
+	summaryCache  cache.Cache
+	attachments   attachments.Store
+	scanner       attachments.Scanner
+	maxAttachment int
+	renderer      *markdown.Renderer
+}
+
+// CommentServiceOption configures optional comment service behavior.
+type CommentServiceOption func(*commentServiceImpl)
+
+// WithReplyNotifier notifies verified authors when their comments get replies.
+func WithReplyNotifier(notifier ReplyNotifier) CommentServiceOption {
+	return func(s *commentServiceImpl) {
+		s.replyNotifier = notifier
+	}
+}
+
+// WithMentionNotifier notifies customers when comments mention them.
+func WithMentionNotifier(notifier MentionNotifier) CommentServiceOption {
+	return func(s *commentServiceImpl) {
+		s.mentions = notifier
+	}
+}
+
+// WithEventPublisher publishes a comment.created event for each new comment.
+func WithEventPublisher(publisher EventPublisher) CommentServiceOption {
+	return func(s *commentServiceImpl) {
+		s.events = publisher
+	}
+}
+
+// WithCommentPoints awards loyalty points to customers for their comments.
+func WithCommentPoints(awarder CommentPointsAwarder) CommentServiceOption {
+	return func(s *commentServiceImpl) {
+		s.pointsAwarder = awarder
+	}
+}
+
+// WithCommentActivity records customers' comments in their followers' feeds.
+func WithCommentActivity(recorder ActivityRecorder) CommentServiceOption {
+	return func(s *commentServiceImpl) {
+		s.activity = recorder
+	}
+}
+
+// WithGuestComments records the comments posted with guest tokens.
+func WithGuestComments(recorder GuestCommentRecorder) CommentServiceOption {
+	return func(s *commentServiceImpl) {
+		s.guestComments = recorder
+	}
+}
+
+// WithCommentSummaryCache keeps films' comment summaries in c until they
+// expire or the summaries are refreshed.
+func WithCommentSummaryCache(c cache.Cache) CommentServiceOption {
+	return func(s *commentServiceImpl) {
+		s.summaryCache = c
+	}
+}
+
+// WithCommentAttachments accepts an image of up to maxBytes with each
+// comment, kept in store along with its thumbnail. Images are first passed
+// to scanner, if not nil, to be checked for viruses.
+func WithCommentAttachments(store attachments.Store, scanner attachments.Scanner, maxBytes int) CommentServiceOption {
+	return func(s *commentServiceImpl) {
+		s.attachments = store
+		s.scanner = scanner
+		s.maxAttachment = maxBytes
+	}
 }
 
 // NewCommentService creates a new comment service with the given repositories.
@@ -21,22 +193,38 @@ type commentServiceImpl struct {
 func NewCommentService(
 	commentRepo repository.CommentRepositoryInterface,
 	filmRepo repository.FilmRepositoryInterface,
+	opts ...CommentServiceOption,
 ) CommentService {
-	return &commentServiceImpl{
+	s := &commentServiceImpl{
 		commentRepo: commentRepo,
 		filmRepo:    filmRepo,
+		renderer:    markdown.NewRenderer(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// AddComment adds a new comment to a film.
+// AddComment adds a new comment to a film. Customers mentioned in it as
+// @<customer_id> must exist and are notified.
 func (s *commentServiceImpl) AddComment(
-	_ context.Context,
+	ctx context.Context,
 	filmID int,
 	commentReq models.CommentRequest,
 ) (*models.Comment, error) {
 	if filmID <= 0 {
 		slog.Warn("Invalid film ID provided", "filmID", filmID)
-		return nil, errors.New("invalid film ID")
+		return nil, ErrInvalidFilmID
+	}
+
+	// Comments from a logged-in customer are linked to them in place of a
+	// free-text name.
+	commentReq.CustomerID = nil
+	claims, hasClaims := auth.ClaimsFromContext(ctx)
+	if hasClaims && claims.Role == auth.RoleCustomer {
+		commentReq.CustomerID = &claims.Subject
+		commentReq.CustomerName = ""
 	}
 
 	if err := s.validateComment(commentReq); err != nil {
@@ -44,7 +232,14 @@ func (s *commentServiceImpl) AddComment(
 		return nil, err
 	}
 
-	if _, err := s.filmRepo.GetFilmByID(filmID); err != nil {
+	commentReq.MentionIDs = parseMentions(commentReq.Comment)
+	mentioned, err := s.mentionTargets(ctx, commentReq.MentionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	film, err := s.publishedFilm(ctx, filmID)
+	if err != nil {
 		if errors.Is(err, repository.ErrFilmNotFound) {
 			slog.Warn("Cannot add comment to non-existent film", "filmID", filmID)
 			return nil, err
@@ -53,24 +248,273 @@ func (s *commentServiceImpl) AddComment(
 		return nil, err
 	}
 
-	comment, err := s.commentRepo.AddComment(filmID, commentReq)
+	// A shadow-banned customer's comment is stored and shown back to them,
+	// but nobody else hears of it.
+	var shadowBanned bool
+	if commentReq.CustomerID != nil {
+		if shadowBanned, err = s.commentRepo.IsShadowBanned(ctx, *commentReq.CustomerID); err != nil {
+			slog.Error("Failed to check shadow ban", "customerID", *commentReq.CustomerID, "error", err)
+			return nil, err
+		}
+	}
+
+	if commentReq.Attachment != nil {
+		if err = s.storeAttachment(ctx, &commentReq); err != nil {
+			return nil, err
+		}
+	}
+
+	comment, err := s.commentRepo.AddComment(ctx, filmID, commentReq)
 	if err != nil {
+		s.deleteAttachment(ctx, commentReq.AttachmentKey, commentReq.ThumbnailKey)
+		if errors.Is(err, repository.ErrCommentNotFound) {
+			slog.Warn("Cannot reply to non-existent comment", "filmID", filmID, "parentID", *commentReq.ParentID)
+			return nil, err
+		}
+		if errors.Is(err, repository.ErrCommentsLocked) {
+			slog.Warn("Cannot comment on film with locked comments", "filmID", filmID)
+			return nil, err
+		}
 		slog.Error("Failed to add comment to repository", "filmID", filmID, "error", err)
 		return nil, err
 	}
 
+	s.present(comment)
+	metrics.CommentsAdded.WithLabelValues(strconv.Itoa(filmID)).Inc()
+	if shadowBanned {
+		slog.Info("Added shadow-banned customer's comment", "filmID", filmID, "commentID", comment.ID)
+		return comment, nil
+	}
+
+	var replyNotified int
+	if comment.ParentID != nil {
+		replyNotified = s.notifyReply(ctx, film, comment)
+	}
+	s.notifyMentions(film, comment, mentioned, replyNotified)
+	if s.events != nil {
+		if publishErr := s.events.Publish(webhooks.EventCommentCreated, comment); publishErr != nil {
+			slog.Warn("Failed to publish comment event", "commentID", comment.ID, "error", publishErr)
+		}
+	}
+	if s.pointsAwarder != nil && comment.CustomerID != nil {
+		if awardErr := s.pointsAwarder.AwardCommentPoints(ctx, *comment.CustomerID, comment.ID); awardErr != nil {
+			slog.Warn("Failed to award comment points", "commentID", comment.ID, "error", awardErr)
+		}
+	}
+	if s.activity != nil && comment.CustomerID != nil {
+		activity := models.Activity{
+			CustomerID: *comment.CustomerID,
+			Kind:       models.ActivityCommentPosted,
+			FilmID:     &filmID,
+			CommentID:  &comment.ID,
+		}
+		if recordErr := s.activity.RecordActivity(ctx, activity); recordErr != nil {
+			slog.Warn("Failed to record comment activity", "commentID", comment.ID, "error", recordErr)
+		}
+	}
+	if s.guestComments != nil && hasClaims && claims.Role == auth.RoleGuest {
+		if recordErr := s.guestComments.RecordGuestComment(ctx, claims.Subject, comment.ID); recordErr != nil {
+			slog.Warn("Failed to record guest comment", "commentID", comment.ID, "error", recordErr)
+		}
+	}
+
 	slog.Info("Successfully added comment", "filmID", filmID, "commentID", comment.ID)
 	return comment, nil
 }
 
-// GetCommentsByFilmID retrieves all comments for a specific film.
-func (s *commentServiceImpl) GetCommentsByFilmID(_ context.Context, filmID int) ([]models.Comment, error) {
+// present fills in what comment shows beyond what is stored: its rendered
+// HTML and the URLs of its attachment.
+func (s *commentServiceImpl) present(comment *models.Comment) {
+	comment.CommentHTML = s.renderer.Render(comment.Comment)
+	if s.attachments != nil && comment.AttachmentKey != "" {
+		comment.AttachmentURL = s.attachments.URL(comment.AttachmentKey)
+		comment.ThumbnailURL = s.attachments.URL(comment.ThumbnailKey)
+	}
+}
+
+// storeAttachment validates and scans commentReq's attachment, then stores
+// it and its thumbnail, setting their keys on commentReq.
+func (s *commentServiceImpl) storeAttachment(ctx context.Context, commentReq *models.CommentRequest) error {
+	if s.attachments == nil {
+		return ErrAttachmentsDisabled
+	}
+
+	image, err := attachments.Prepare(commentReq.Attachment.Data, s.maxAttachment)
+	if err != nil {
+		slog.Warn("Invalid comment attachment", "size", len(commentReq.Attachment.Data), "error", err)
+		return err
+	}
+	if s.scanner != nil {
+		if err = s.scanner.Scan(ctx, image.Data); err != nil {
+			if errors.Is(err, attachments.ErrInfected) {
+				slog.Warn("Comment attachment failed virus scan", "size", len(image.Data))
+			} else {
+				slog.Error("Failed to scan comment attachment", "error", err)
+			}
+			return err
+		}
+	}
+
+	key, thumbnailKey, err := attachments.NewKey(image.Extension)
+	if err != nil {
+		return err
+	}
+	if err = s.attachments.Put(ctx, key, image.Data); err != nil {
+		slog.Error("Failed to store comment attachment", "key", key, "error", err)
+		return err
+	}
+	if err = s.attachments.Put(ctx, thumbnailKey, image.Thumbnail); err != nil {
+		slog.Error("Failed to store comment attachment thumbnail", "key", thumbnailKey, "error", err)
+		s.deleteAttachment(ctx, key)
+		return err
+	}
+
+	commentReq.AttachmentKey = key
+	commentReq.ThumbnailKey = thumbnailKey
+	return nil
+}
+
+// deleteAttachment removes stored attachments by key, such as those of a
+// comment that could not be added. Failures are logged.
+func (s *commentServiceImpl) deleteAttachment(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := s.attachments.Delete(ctx, key); err != nil {
+			slog.Warn("Failed to delete comment attachment", "key", key, "error", err)
+		}
+	}
+}
+
+// notifyReply queues a notice to the parent comment's author, returning
+// their customer ID if one was queued. Failures are logged rather than
+// failing the reply.
+func (s *commentServiceImpl) notifyReply(ctx context.Context, film *models.Film, reply *models.Comment) int {
+	if s.replyNotifier == nil {
+		return 0
+	}
+
+	author, err := s.commentRepo.GetCommentAuthor(ctx, *reply.ParentID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCommentNotFound) {
+			slog.Error("Failed to look up comment author", "commentID", *reply.ParentID, "error", err)
+		}
+		return 0
+	}
+	if author.Email == "" || (reply.CustomerID != nil && *reply.CustomerID == author.CustomerID) {
+		return 0
+	}
+
+	err = s.replyNotifier.NotifyCommentReply(notifications.CommentReply{
+		RecipientID:    author.CustomerID,
+		RecipientEmail: author.Email,
+		RecipientName:  author.FirstName,
+		FilmTitle:      film.Title,
+		ReplierName:    reply.CustomerName,
+		Reply:          reply.Comment,
+	})
+	if err != nil {
+		slog.Warn("Failed to queue comment reply notification", "commentID", reply.ID, "error", err)
+		return 0
+	}
+	return author.CustomerID
+}
+
+// parseMentions returns the distinct customer IDs text mentions as
+// @<customer_id>, in the order first mentioned.
+func parseMentions(text string) []int {
+	var ids []int
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		id, err := strconv.Atoi(match[1])
+		if err != nil || id <= 0 || slices.Contains(ids, id) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// mentionTargets looks up the customers a comment mentions, returning
+// ErrUnknownMention if any is not an active customer.
+func (s *commentServiceImpl) mentionTargets(ctx context.Context, ids []int) ([]models.CommentAuthor, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if len(ids) > maxMentions {
+		slog.Warn("Comment mentions too many customers", "count", len(ids))
+		return nil, ErrTooManyMentions
+	}
+
+	targets, err := s.commentRepo.GetMentionTargets(ctx, ids)
+	if err != nil {
+		slog.Error("Failed to look up mentioned customers", "customerIDs", ids, "error", err)
+		return nil, err
+	}
+	if len(targets) != len(ids) {
+		slog.Warn("Comment mentions unknown customers", "customerIDs", ids)
+		return nil, ErrUnknownMention
+	}
+	return targets, nil
+}
+
+// notifyMentions queues a notice to each customer a comment mentions,
+// except its author and the customer already told of it as a reply.
+// Failures are logged rather than failing the comment.
+func (s *commentServiceImpl) notifyMentions(
+	film *models.Film,
+	comment *models.Comment,
+	mentioned []models.CommentAuthor,
+	replyNotified int,
+) {
+	if s.mentions == nil {
+		return
+	}
+
+	for _, target := range mentioned {
+		if target.Email == "" || target.CustomerID == replyNotified ||
+			(comment.CustomerID != nil && *comment.CustomerID == target.CustomerID) {
+			continue
+		}
+		err := s.mentions.NotifyCommentMention(notifications.CommentMention{
+			RecipientID:    target.CustomerID,
+			RecipientEmail: target.Email,
+			RecipientName:  target.FirstName,
+			FilmTitle:      film.Title,
+			MentionerName:  comment.DisplayName,
+			Comment:        comment.Comment,
+		})
+		if err != nil {
+			slog.Warn("Failed to queue comment mention notification",
+				"commentID", comment.ID, "customerID", target.CustomerID, "error", err)
+		}
+	}
+}
+
+// GetCommentsByFilmID retrieves a page of a film's comments, newest first:
+// the first page, or the one after a cursor from the page before. Comments
+// by shadow-banned customers are only shown to a logged-in customer who
+// posted them.
+func (s *commentServiceImpl) GetCommentsByFilmID(
+	ctx context.Context,
+	filmID int,
+	filters models.CommentFilters,
+) (*pagination.Paginated[models.Comment], error) {
 	if filmID <= 0 {
 		slog.Warn("Invalid film ID provided", "filmID", filmID)
-		return nil, errors.New("invalid film ID")
+		return nil, ErrInvalidFilmID
+	}
+
+	if filters.Limit <= 0 {
+		filters.Limit = models.CommentLimits.DefaultLimit
+	}
+	after, err := commentCursor(filters)
+	if err != nil {
+		slog.Warn("Invalid comment page requested", "filmID", filmID, "error", err)
+		return nil, err
 	}
 
-	if _, err := s.filmRepo.GetFilmByID(filmID); err != nil {
+	if _, err = s.publishedFilm(ctx, filmID); err != nil {
 		if errors.Is(err, repository.ErrFilmNotFound) {
 			slog.Warn("Cannot get comments for non-existent film", "filmID", filmID)
 			return nil, err
@@ -79,16 +523,121 @@ func (s *commentServiceImpl) GetCommentsByFilmID(_ context.Context, filmID int)
 		return nil, err
 	}
 
-	comments, err := s.commentRepo.GetCommentsByFilmID(filmID)
+	var viewerID int
+	if claims, ok := auth.ClaimsFromContext(ctx); ok && claims.Role == auth.RoleCustomer {
+		viewerID = claims.Subject
+	}
+
+	comments, err := s.commentRepo.GetCommentsByFilmID(ctx, filmID, viewerID, filters.Limit, after)
 	if err != nil {
 		slog.Error("Failed to retrieve comments from repository", "filmID", filmID, "error", err)
 		return nil, err
 	}
+	for i := range comments.Items {
+		s.present(&comments.Items[i])
+	}
 
-	slog.Info("Successfully retrieved comments", "filmID", filmID, "count", len(comments))
+	slog.Info("Successfully retrieved comments", "filmID", filmID, "count", len(comments.Items))
 	return comments, nil
 }
 
+// commentCursor validates filters, returning the cursor its page starts
+// after, or nil for the first page.
+func commentCursor(filters models.CommentFilters) (*pagination.Cursor, error) {
+	if err := models.CommentLimits.Check(pagination.Params{Page: 1, Limit: filters.Limit}); err != nil {
+		return nil, err
+	}
+	if filters.Page > 1 {
+		return nil, fmt.Errorf("%w: comments are not paged by number; set after to the previous page's next_cursor",
+			binding.ErrValidation)
+	}
+	if filters.After == "" {
+		return nil, nil //nolint:nilnil // The first page has no cursor
+	}
+
+	after, err := pagination.DecodeCursor(filters.After)
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// GetCommentSummary retrieves a film's comment summary, as of its last
+// nightly refresh, from the summary cache when it holds it.
+func (s *commentServiceImpl) GetCommentSummary(ctx context.Context, filmID int) (*models.CommentSummary, error) {
+	key := commentSummaryPrefix + strconv.Itoa(filmID)
+	if s.summaryCache != nil {
+		if cached, ok := s.summaryCache.Get(key); ok {
+			if summary, isSummary := cached.(*models.CommentSummary); isSummary {
+				return summary, nil
+			}
+		}
+	}
+
+	summary, err := s.commentRepo.GetCommentSummary(ctx, filmID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrFilmNotFound) && !errors.Is(err, repository.ErrCommentSummaryNotFound) {
+			slog.Error("Failed to retrieve comment summary", "filmID", filmID, "error", err)
+		}
+		return nil, err
+	}
+
+	if s.summaryCache != nil {
+		s.summaryCache.Set(key, summary)
+	}
+	return summary, nil
+}
+
+// RefreshCommentSummaries recomputes every film's comment summary and
+// drops the cached ones.
+func (s *commentServiceImpl) RefreshCommentSummaries(ctx context.Context) error {
+	if err := s.commentRepo.RefreshCommentSummaries(ctx, commentStopwords, commentKeywordLimit); err != nil {
+		return err
+	}
+	if s.summaryCache != nil {
+		s.summaryCache.DeletePrefix(commentSummaryPrefix)
+	}
+	return nil
+}
+
+// SetCommentsLocked locks or unlocks new comments on a film. Existing
+// comments stay visible either way.
+func (s *commentServiceImpl) SetCommentsLocked(
+	ctx context.Context,
+	filmID int,
+	locked bool,
+) (*models.CommentLockStatus, error) {
+	status, err := s.commentRepo.SetCommentsLocked(ctx, filmID, locked)
+	if err != nil {
+		if !errors.Is(err, repository.ErrFilmNotFound) {
+			slog.Error("Failed to change comment lock", "filmID", filmID, "locked", locked, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Comment lock changed", "filmID", filmID, "locked", locked)
+	return status, nil
+}
+
+// SetShadowBanned shadow-bans a customer or lifts their ban. Their comments
+// stay stored either way; a ban only hides them from everyone else.
+func (s *commentServiceImpl) SetShadowBanned(
+	ctx context.Context,
+	customerID int,
+	banned bool,
+) (*models.ShadowBanStatus, error) {
+	status, err := s.commentRepo.SetShadowBanned(ctx, customerID, banned)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCustomerNotFound) {
+			slog.Error("Failed to change shadow ban", "customerID", customerID, "banned", banned, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Shadow ban changed", "customerID", customerID, "banned", banned)
+	return status, nil
+}
+
 // validateComment validates the comment request.
 func (s *commentServiceImpl) validateComment(commentReq models.CommentRequest) error {
 	const (
@@ -96,19 +645,35 @@ func (s *commentServiceImpl) validateComment(commentReq models.CommentRequest) e
 		maxCommentLength      = 1000
 	)
 
-	if commentReq.CustomerName == "" {
-		return errors.New("customer name is required")
+	if commentReq.CustomerID == nil && commentReq.CustomerName == "" {
+		return ErrCustomerNameRequired
 	}
 	if len(commentReq.CustomerName) > maxCustomerNameLength {
-		return errors.New("customer name too long (max 100 characters)")
+		return ErrCustomerNameTooLong
+	}
+	if strings.HasPrefix(commentReq.CustomerName, pii.Prefix) {
+		return ErrReservedCustomerName
 	}
 
 	if commentReq.Comment == "" {
-		return errors.New("comment text is required")
+		return ErrCommentRequired
 	}
 	if len(commentReq.Comment) > maxCommentLength {
-		return errors.New("comment text too long (max 1000 characters)")
+		return ErrCommentTooLong
 	}
 
 	return nil
 }
+
+// publishedFilm retrieves a film customers can see. Films that are not
+// published are reported as not found, as the film service does.
+func (s *commentServiceImpl) publishedFilm(ctx context.Context, filmID int) (*models.Film, error) {
+	film, err := s.filmRepo.GetFilmByID(ctx, filmID)
+	if err != nil {
+		return nil, err
+	}
+	if film.Status != models.FilmPublished {
+		return nil, repository.ErrFilmNotFound
+	}
+	return film, nil
+}
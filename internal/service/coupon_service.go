@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// couponServiceImpl implements the CouponService interface.
+type couponServiceImpl struct {
+	couponRepo repository.CouponRepositoryInterface
+}
+
+// NewCouponService creates a new coupon service with the given repository.
+func NewCouponService(couponRepo repository.CouponRepositoryInterface) CouponService {
+	return &couponServiceImpl{couponRepo: couponRepo}
+}
+
+// CreateCoupon creates a coupon. Codes are case-insensitive and stored
+// upper-case.
+func (s *couponServiceImpl) CreateCoupon(
+	ctx context.Context,
+	couponReq models.CouponRequest,
+) (*models.Coupon, error) {
+	couponReq.Code = strings.ToUpper(couponReq.Code)
+
+	coupon, err := s.couponRepo.CreateCoupon(ctx, couponReq)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCouponCodeTaken) {
+			slog.Error("Failed to create coupon", "code", couponReq.Code, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Coupon created", "couponID", coupon.ID, "code", coupon.Code)
+	return coupon, nil
+}
+
+// ListCoupons retrieves every coupon with its redemption count.
+func (s *couponServiceImpl) ListCoupons(ctx context.Context) ([]models.Coupon, error) {
+	return s.couponRepo.ListCoupons(ctx)
+}
+
+// ValidateCoupon reports the discount a coupon would give a customer's
+// checkout of amount, failing as redemption would if the coupon cannot be
+// used.
+func (s *couponServiceImpl) ValidateCoupon(
+	ctx context.Context,
+	code string,
+	customerID int,
+	amount float64,
+) (*models.CouponQuote, error) {
+	return s.couponRepo.ValidateCoupon(ctx, strings.ToUpper(code), customerID, amount)
+}
+
+// RedeemCoupon uses a coupon for a customer's checkout of amount.
+func (s *couponServiceImpl) RedeemCoupon(
+	ctx context.Context,
+	code string,
+	customerID int,
+	amount float64,
+) (*models.CouponRedemption, error) {
+	redemption, err := s.couponRepo.RedeemCoupon(ctx, strings.ToUpper(code), customerID, amount)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to redeem coupon", "code", code, "customerID", customerID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Coupon redeemed", "code", redemption.Code, "customerID", customerID,
+		"discount", redemption.Discount)
+	return redemption, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// CustomerExportJobKind is the background job kind that builds exports of
+// customers' personal data.
+const CustomerExportJobKind = "customers.export"
+
+// JobEnqueuer persists a background job for asynchronous processing.
+type JobEnqueuer interface {
+	Enqueue(kind string, payload any) error
+}
+
+// customerExportJob is the payload of a CustomerExportJobKind job.
+type customerExportJob struct {
+	ExportID   int    `json:"export_id"`
+	CustomerID int    `json:"customer_id"`
+	Format     string `json:"format"`
+}
+
+// customerExportServiceImpl implements the CustomerExportService interface.
+type customerExportServiceImpl struct {
+	exportRepo repository.CustomerExportRepositoryInterface
+	queue      JobEnqueuer
+	ttl        time.Duration
+	signer     *auth.URLSigner
+}
+
+// CustomerExportServiceOption configures optional customer export service
+// behavior.
+type CustomerExportServiceOption func(*customerExportServiceImpl)
+
+// WithSignedExportURLs gives ready exports a signed download URL from
+// signer, which fetches the file without a bearer token.
+func WithSignedExportURLs(signer *auth.URLSigner) CustomerExportServiceOption {
+	return func(s *customerExportServiceImpl) {
+		s.signer = signer
+	}
+}
+
+// NewCustomerExportService creates a new customer export service. Exports
+// are built on queue, which must route CustomerExportJobKind jobs to
+// HandleExportJob. An export is reused for ttl after it was requested,
+// after which a new request builds a fresh one.
+func NewCustomerExportService(
+	exportRepo repository.CustomerExportRepositoryInterface,
+	queue JobEnqueuer,
+	ttl time.Duration,
+	opts ...CustomerExportServiceOption,
+) CustomerExportService {
+	s := &customerExportServiceImpl{exportRepo: exportRepo, queue: queue, ttl: ttl}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RequestExport returns a customer's current export in format, queueing a
+// new one if they have none requested within the time-to-live.
+func (s *customerExportServiceImpl) RequestExport(
+	ctx context.Context,
+	customerID int,
+	format string,
+) (*models.CustomerExport, error) {
+	export, err := s.exportRepo.GetLatestExport(ctx, customerID, format, time.Now().Add(-s.ttl))
+	if err == nil {
+		return s.withExportURLs(export), nil
+	}
+	if !errors.Is(err, repository.ErrCustomerExportNotFound) {
+		return nil, err
+	}
+
+	if export, err = s.exportRepo.CreateExport(ctx, customerID, format); err != nil {
+		return nil, err
+	}
+	err = s.queue.Enqueue(CustomerExportJobKind,
+		customerExportJob{ExportID: export.ID, CustomerID: customerID, Format: format})
+	if err != nil {
+		return nil, fmt.Errorf("error queueing customer export: %w", err)
+	}
+
+	return s.withExportURLs(export), nil
+}
+
+// GetExport retrieves one of a customer's exports, to poll until it is
+// ready.
+func (s *customerExportServiceImpl) GetExport(
+	ctx context.Context,
+	customerID, exportID int,
+) (*models.CustomerExport, error) {
+	export, err := s.exportRepo.GetExport(ctx, customerID, exportID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withExportURLs(export), nil
+}
+
+// GetExportFile retrieves the file built for a ready export.
+func (s *customerExportServiceImpl) GetExportFile(ctx context.Context, export *models.CustomerExport) ([]byte, error) {
+	return s.exportRepo.GetExportFile(ctx, export.ID)
+}
+
+// HandleExportJob builds the file for an export queued by RequestExport. It
+// is the job handler for CustomerExportJobKind. An export replaced by a
+// newer one before its job ran is skipped.
+func (s *customerExportServiceImpl) HandleExportJob(ctx context.Context, payload json.RawMessage) error {
+	var job customerExportJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("error decoding customer export job: %w", err)
+	}
+
+	data, err := s.exportRepo.GetCustomerData(ctx, job.CustomerID)
+	if err != nil {
+		return err
+	}
+	data.ExportedAt = time.Now().UTC()
+
+	file, err := encodeCustomerData(data, job.Format)
+	if err != nil {
+		return err
+	}
+
+	err = s.exportRepo.CompleteExport(ctx, job.ExportID, file)
+	if errors.Is(err, repository.ErrCustomerExportNotFound) {
+		return nil
+	}
+	return err
+}
+
+// encodeCustomerData encodes data in format: one JSON document, or a ZIP
+// archive with a JSON file each for the profile, comments, and rentals.
+func encodeCustomerData(data *models.CustomerData, format string) ([]byte, error) {
+	if format != models.CustomerExportFormatZIP {
+		encoded, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("error encoding customer data: %w", err)
+		}
+		return encoded, nil
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"profile.json", data.Profile},
+		{"comments.json", data.Comments},
+		{"rentals.json", data.Rentals},
+	}
+	for _, f := range files {
+		encoded, err := json.MarshalIndent(f.content, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("error encoding customer data: %w", err)
+		}
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: data.ExportedAt})
+		if err != nil {
+			return nil, fmt.Errorf("error archiving customer data: %w", err)
+		}
+		if _, err = w.Write(encoded); err != nil {
+			return nil, fmt.Errorf("error archiving customer data: %w", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("error archiving customer data: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// withExportURLs sets the URLs an export is polled and downloaded at, and
+// the signed download URL of a ready export when URL signing is enabled.
+func (s *customerExportServiceImpl) withExportURLs(export *models.CustomerExport) *models.CustomerExport {
+	customerPath := fmt.Sprintf("/api/v1/customers/%d", export.CustomerID)
+	export.StatusURL = fmt.Sprintf("%s/exports/%d", customerPath, export.ID)
+	export.DownloadURL = customerPath + "/export?format=" + export.Format
+
+	if s.signer != nil && export.Status == models.CustomerExportStatusReady {
+		signed, _, err := s.signer.Sign(export.DownloadURL)
+		if err != nil {
+			slog.Error("Failed to sign export download URL", "exportID", export.ID, "error", err)
+			return export
+		}
+		export.SignedDownloadURL = signed
+	}
+	return export
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// customerListServiceImpl implements the CustomerListService interface.
+type customerListServiceImpl struct {
+	listRepo repository.CustomerListRepositoryInterface
+	activity ActivityRecorder
+}
+
+// CustomerListServiceOption configures optional customer list service
+// behavior.
+type CustomerListServiceOption func(*customerListServiceImpl)
+
+// WithListActivity records public lists being published and films being
+// added to them in their owners' followers' feeds.
+func WithListActivity(recorder ActivityRecorder) CustomerListServiceOption {
+	return func(s *customerListServiceImpl) {
+		s.activity = recorder
+	}
+}
+
+// NewCustomerListService creates a new customer list service with the given
+// repository.
+func NewCustomerListService(
+	listRepo repository.CustomerListRepositoryInterface,
+	opts ...CustomerListServiceOption,
+) CustomerListService {
+	s := &customerListServiceImpl{listRepo: listRepo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateList stores a new, empty list for a customer. Lists are private
+// unless the request makes them public.
+func (s *customerListServiceImpl) CreateList(
+	ctx context.Context,
+	customerID int,
+	listReq models.CustomerListRequest,
+) (*models.CustomerList, error) {
+	list, err := s.listRepo.CreateList(ctx, customerID, withDefaultVisibility(listReq))
+	if err != nil {
+		if !errors.Is(err, repository.ErrCustomerNotFound) {
+			slog.Error("Failed to create list", "customerID", customerID, "error", err)
+		}
+		return nil, err
+	}
+
+	if list.Visibility == models.ListVisibilityPublic {
+		s.recordActivity(ctx, list, models.ActivityListPublished, nil)
+	}
+	slog.Info("List created", "customerID", customerID, "listID", list.ID, "visibility", list.Visibility)
+	return list, nil
+}
+
+// ListCustomerLists retrieves a customer's lists, public and private,
+// without their entries.
+func (s *customerListServiceImpl) ListCustomerLists(
+	ctx context.Context,
+	customerID int,
+) ([]models.CustomerList, error) {
+	return s.listRepo.ListCustomerLists(ctx, customerID)
+}
+
+// GetCustomerList retrieves one of a customer's own lists with its
+// entries. Other customers' lists are reported as not found.
+func (s *customerListServiceImpl) GetCustomerList(
+	ctx context.Context,
+	customerID, listID int,
+) (*models.CustomerList, error) {
+	list, err := s.listRepo.GetList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.CustomerID != customerID {
+		return nil, repository.ErrListNotFound
+	}
+	return list, nil
+}
+
+// GetSharedList retrieves a list with its entries. Anyone may see a public
+// list; a private one is reported as not found unless the caller's token is
+// for the customer who owns it.
+func (s *customerListServiceImpl) GetSharedList(ctx context.Context, listID int) (*models.CustomerList, error) {
+	list, err := s.listRepo.GetList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.Visibility == models.ListVisibilityPublic {
+		return list, nil
+	}
+	if claims, ok := auth.ClaimsFromContext(ctx); ok && claims.Role == auth.RoleCustomer &&
+		claims.Subject == list.CustomerID {
+		return list, nil
+	}
+	return nil, repository.ErrListNotFound
+}
+
+// UpdateList replaces the name, description, and visibility of a
+// customer's list. Omitting the visibility makes the list private. Making a
+// private list public publishes it to the owner's followers.
+func (s *customerListServiceImpl) UpdateList(
+	ctx context.Context,
+	customerID, listID int,
+	listReq models.CustomerListRequest,
+) (*models.CustomerList, error) {
+	wasPublic := true
+	if s.activity != nil && listReq.Visibility == models.ListVisibilityPublic {
+		if previous, getErr := s.listRepo.GetList(ctx, listID); getErr == nil {
+			wasPublic = previous.Visibility == models.ListVisibilityPublic
+		}
+	}
+
+	list, err := s.listRepo.UpdateList(ctx, customerID, listID, withDefaultVisibility(listReq))
+	if err != nil {
+		if !errors.Is(err, repository.ErrListNotFound) {
+			slog.Error("Failed to update list", "listID", listID, "error", err)
+		}
+		return nil, err
+	}
+
+	if !wasPublic && list.Visibility == models.ListVisibilityPublic {
+		s.recordActivity(ctx, list, models.ActivityListPublished, nil)
+	}
+	slog.Info("List updated", "listID", listID, "visibility", list.Visibility)
+	return list, nil
+}
+
+// DeleteList deletes a customer's list and its entries.
+func (s *customerListServiceImpl) DeleteList(ctx context.Context, customerID, listID int) error {
+	if err := s.listRepo.DeleteList(ctx, customerID, listID); err != nil {
+		if !errors.Is(err, repository.ErrListNotFound) {
+			slog.Error("Failed to delete list", "listID", listID, "error", err)
+		}
+		return err
+	}
+
+	slog.Info("List deleted", "customerID", customerID, "listID", listID)
+	return nil
+}
+
+// AddListEntry adds a film to the end of a customer's list. Films added to
+// a public list appear in the owner's followers' feeds.
+func (s *customerListServiceImpl) AddListEntry(
+	ctx context.Context,
+	customerID, listID, filmID int,
+) (*models.CustomerList, error) {
+	list, err := s.listRepo.AddListEntry(ctx, customerID, listID, filmID)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to add list entry", "listID", listID, "filmID", filmID, "error", err)
+		}
+		return nil, err
+	}
+
+	if list.Visibility == models.ListVisibilityPublic {
+		s.recordActivity(ctx, list, models.ActivityListEntryAdded, &filmID)
+	}
+	return list, nil
+}
+
+// RemoveListEntry removes a film from a customer's list, moving the films
+// after it up a place.
+func (s *customerListServiceImpl) RemoveListEntry(
+	ctx context.Context,
+	customerID, listID, filmID int,
+) (*models.CustomerList, error) {
+	list, err := s.listRepo.RemoveListEntry(ctx, customerID, listID, filmID)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to remove list entry", "listID", listID, "filmID", filmID, "error", err)
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+// ReorderListEntries puts the films on a customer's list in the given
+// order, which must name each of them exactly once.
+func (s *customerListServiceImpl) ReorderListEntries(
+	ctx context.Context,
+	customerID, listID int,
+	filmIDs []int,
+) (*models.CustomerList, error) {
+	list, err := s.listRepo.ReorderListEntries(ctx, customerID, listID, filmIDs)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to reorder list", "listID", listID, "error", err)
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+// recordActivity records activity of kind on list, about filmID if it is
+// not nil. Failures are logged rather than failing the change to the list.
+func (s *customerListServiceImpl) recordActivity(
+	ctx context.Context,
+	list *models.CustomerList,
+	kind string,
+	filmID *int,
+) {
+	if s.activity == nil {
+		return
+	}
+
+	activity := models.Activity{CustomerID: list.CustomerID, Kind: kind, FilmID: filmID, ListID: &list.ID}
+	if err := s.activity.RecordActivity(ctx, activity); err != nil {
+		slog.Warn("Failed to record list activity", "listID", list.ID, "kind", kind, "error", err)
+	}
+}
+
+// withDefaultVisibility returns listReq with its visibility defaulted to
+// private.
+func withDefaultVisibility(listReq models.CustomerListRequest) models.CustomerListRequest {
+	if listReq.Visibility == "" {
+		listReq.Visibility = models.ListVisibilityPrivate
+	}
+	return listReq
+}
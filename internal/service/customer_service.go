@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrMergeSameCustomer is returned when merging a customer into themselves.
+var ErrMergeSameCustomer = apperrors.New(apperrors.Invalid, "a customer cannot be merged into themselves")
+
+// GuestMerger moves what a guest did into the account they register.
+type GuestMerger interface {
+	MergeGuest(ctx context.Context, guestID, customerID int) (*models.GuestMerge, error)
+}
+
+// customerServiceImpl implements the CustomerService interface.
+type customerServiceImpl struct {
+	customerRepo repository.CustomerRepositoryInterface
+	tokens       *auth.TokenIssuer
+	guests       GuestMerger
+	sessions     SessionStarter
+}
+
+// CustomerServiceOption configures optional customer service behavior.
+type CustomerServiceOption func(*customerServiceImpl)
+
+// WithGuestMerge merges the guest session of a registration made with a
+// guest token into the new account.
+func WithGuestMerge(merger GuestMerger) CustomerServiceOption {
+	return func(s *customerServiceImpl) {
+		s.guests = merger
+	}
+}
+
+// WithCustomerSessions starts a refreshable login session on each customer
+// login instead of issuing a lone token.
+func WithCustomerSessions(sessions SessionStarter) CustomerServiceOption {
+	return func(s *customerServiceImpl) {
+		s.sessions = sessions
+	}
+}
+
+// NewCustomerService creates a new customer service issuing customer tokens
+// with the given issuer.
+func NewCustomerService(
+	customerRepo repository.CustomerRepositoryInterface,
+	tokens *auth.TokenIssuer,
+	opts ...CustomerServiceOption,
+) CustomerService {
+	s := &customerServiceImpl{
+		customerRepo: customerRepo,
+		tokens:       tokens,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register creates a customer account with a bcrypt-hashed password. A
+// registration made with a guest token takes over that guest's comments
+// and watchlist; if that fails the account is still created, without them.
+func (s *customerServiceImpl) Register(
+	ctx context.Context,
+	registerReq models.CustomerRegisterRequest,
+) (*models.Customer, error) {
+	passwordHash, err := auth.HashPassword(registerReq.Password)
+	if err != nil {
+		slog.Error("Failed to hash customer password", "error", err)
+		return nil, err
+	}
+
+	customer, err := s.customerRepo.CreateCustomer(ctx, registerReq, passwordHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailTaken) || errors.Is(err, repository.ErrInvalidReference) {
+			slog.Warn("Rejected customer registration", "error", err)
+			return nil, err
+		}
+		slog.Error("Failed to create customer in repository", "error", err)
+		return nil, err
+	}
+
+	if claims, ok := auth.ClaimsFromContext(ctx); ok && claims.Role == auth.RoleGuest && s.guests != nil {
+		merge, mergeErr := s.guests.MergeGuest(ctx, claims.Subject, customer.CustomerID)
+		if mergeErr != nil {
+			slog.Warn("Failed to merge guest session", "guestID", claims.Subject,
+				"customerID", customer.CustomerID, "error", mergeErr)
+		} else {
+			customer.GuestMerge = merge
+		}
+	}
+
+	slog.Info("Successfully registered customer", "customerID", customer.CustomerID)
+	return customer, nil
+}
+
+// Login exchanges customer credentials for a customer bearer token, with a
+// refresh token when login sessions are enabled. Unknown, inactive, and
+// wrong-password logins all return ErrInvalidCredentials.
+func (s *customerServiceImpl) Login(
+	ctx context.Context,
+	loginReq models.CustomerLoginRequest,
+) (*models.TokenResponse, error) {
+	customer, passwordHash, err := s.customerRepo.GetCustomerCredentials(ctx, loginReq.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			slog.Warn("Customer login for unknown email")
+			auth.CheckUnknownAccountPassword(loginReq.Password)
+			return nil, ErrInvalidCredentials
+		}
+		slog.Error("Failed to retrieve customer credentials", "error", err)
+		return nil, err
+	}
+
+	// The password is checked even for inactive customers, so they take as
+	// long to reject as anyone else.
+	passwordMatches := auth.CheckPassword(passwordHash, loginReq.Password)
+	if !customer.Active || !passwordMatches {
+		slog.Warn("Rejected customer login", "customerID", customer.CustomerID, "active", customer.Active)
+		return nil, ErrInvalidCredentials
+	}
+
+	if s.sessions != nil {
+		tokens, sessionErr := s.sessions.StartSession(ctx, auth.RoleCustomer, customer.CustomerID, false)
+		if sessionErr != nil {
+			return nil, sessionErr
+		}
+		slog.Info("Customer logged in", "customerID", customer.CustomerID)
+		return tokens, nil
+	}
+
+	token, expiresAt, err := s.tokens.Issue(customer.CustomerID)
+	if err != nil {
+		slog.Error("Failed to issue customer token", "customerID", customer.CustomerID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Customer logged in", "customerID", customer.CustomerID)
+	return &models.TokenResponse{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// MergeCustomers merges the duplicate customer record sourceID into
+// targetID, recording the merge in the audit log under the caller whose
+// token claims are in ctx.
+func (s *customerServiceImpl) MergeCustomers(
+	ctx context.Context,
+	sourceID, targetID int,
+) (*models.CustomerMerge, error) {
+	if sourceID == targetID {
+		return nil, ErrMergeSameCustomer
+	}
+
+	merge, err := s.customerRepo.MergeCustomers(ctx, sourceID, targetID, auditEntryFor(ctx))
+	if err != nil {
+		if !errors.Is(err, repository.ErrCustomerNotFound) {
+			slog.Error("Failed to merge customers", "sourceID", sourceID, "targetID", targetID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Merged customers", "sourceID", sourceID, "targetID", targetID,
+		"commentsMoved", merge.CommentsMoved, "rentalsMoved", merge.RentalsMoved, "listsMoved", merge.ListsMoved)
+	return merge, nil
+}
+
+// EraseData erases a customer's personal data, recording the erasure in
+// the audit log under the caller whose token claims are in ctx.
+func (s *customerServiceImpl) EraseData(ctx context.Context, customerID int) (*models.CustomerErasure, error) {
+	entry := auditEntryFor(ctx)
+
+	erasure, err := s.customerRepo.EraseCustomerData(ctx, customerID, entry)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCustomerNotFound) {
+			slog.Error("Failed to erase customer data", "customerID", customerID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Erased customer data", "customerID", customerID, "actorRole", entry.ActorRole)
+	return erasure, nil
+}
+
+// auditEntryFor returns an audit entry naming the caller whose token claims
+// are in ctx as its actor, or the system when there are none.
+func auditEntryFor(ctx context.Context) models.AuditEntry {
+	entry := models.AuditEntry{ActorRole: models.AuditActorSystem}
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		entry.ActorRole = claims.Role
+		entry.ActorID = &claims.Subject
+	}
+	return entry
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// categoryStatsPrefix prefixes each category's statistics in the stats cache.
+const categoryStatsPrefix = "category_stats:"
+
+// dashboardServiceImpl implements the DashboardService interface.
+type dashboardServiceImpl struct {
+	dashboardRepo repository.DashboardRepositoryInterface
+	errorRate     func() float64
+	statsCache    cache.Cache
+}
+
+// DashboardServiceOption configures optional dashboard service behavior.
+type DashboardServiceOption func(*dashboardServiceImpl)
+
+// WithCategoryStatsCache keeps category statistics in c until they expire,
+// rather than aggregating them on every request.
+func WithCategoryStatsCache(c cache.Cache) DashboardServiceOption {
+	return func(s *dashboardServiceImpl) {
+		s.statsCache = c
+	}
+}
+
+// NewDashboardService creates a new dashboard service, reading the HTTP
+// error rate from errorRate.
+func NewDashboardService(
+	dashboardRepo repository.DashboardRepositoryInterface,
+	errorRate func() float64,
+	opts ...DashboardServiceOption,
+) DashboardService {
+	s := &dashboardServiceImpl{dashboardRepo: dashboardRepo, errorRate: errorRate}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetDashboard gathers the dashboard's statistics, querying them
+// concurrently. Any failed query fails the whole dashboard.
+func (s *dashboardServiceImpl) GetDashboard(ctx context.Context) (*models.Dashboard, error) {
+	dashboard := &models.Dashboard{ErrorRate: s.errorRate(), GeneratedAt: time.Now().UTC()}
+
+	var g errgroup.Group
+	g.Go(func() (err error) {
+		dashboard.FilmsCount, err = s.dashboardRepo.CountFilms(ctx)
+		return err
+	})
+	g.Go(func() (err error) {
+		dashboard.RentalsToday, err = s.dashboardRepo.CountRentalsToday(ctx)
+		return err
+	})
+	g.Go(func() (err error) {
+		dashboard.PendingComments, err = s.dashboardRepo.CountPendingComments(ctx)
+		return err
+	})
+	g.Go(func() (err error) {
+		dashboard.RevenueThisMonth, err = s.dashboardRepo.GetRevenueThisMonth(ctx)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
+
+// GetCategoryStats summarizes a category's films, rentals, and revenue, from
+// the stats cache when it holds them.
+func (s *dashboardServiceImpl) GetCategoryStats(ctx context.Context, categoryID int) (*models.CategoryStats, error) {
+	key := categoryStatsPrefix + strconv.Itoa(categoryID)
+	if s.statsCache != nil {
+		if cached, ok := s.statsCache.Get(key); ok {
+			if stats, isStats := cached.(*models.CategoryStats); isStats {
+				return stats, nil
+			}
+		}
+	}
+
+	stats, err := s.dashboardRepo.GetCategoryStats(ctx, categoryID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCategoryNotFound) {
+			slog.Error("Failed to aggregate category stats", "categoryID", categoryID, "error", err)
+		}
+		return nil, err
+	}
+	stats.GeneratedAt = time.Now().UTC()
+
+	if s.statsCache != nil {
+		s.statsCache.Set(key, stats)
+	}
+	return stats, nil
+}
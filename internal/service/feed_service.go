@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/feeds"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// feedEntryLimit is the number of entries in each feed. Readers poll, so
+// only recent entries are needed.
+const feedEntryLimit = 50
+
+// feedAuthor is the author of feeds whose entries have none of their own.
+const feedAuthor = "Mockbuster"
+
+// feedServiceImpl implements the FeedService interface.
+type feedServiceImpl struct {
+	filmRepo       repository.FilmRepositoryInterface
+	filmFeedRepo   repository.FilmFeedRepositoryInterface
+	commentService CommentService
+	baseURL        string
+}
+
+// NewFeedService creates a new feed service. Links in feeds are absolute,
+// starting with baseURL.
+func NewFeedService(
+	filmRepo repository.FilmRepositoryInterface,
+	filmFeedRepo repository.FilmFeedRepositoryInterface,
+	commentService CommentService,
+	baseURL string,
+) FeedService {
+	return &feedServiceImpl{
+		filmRepo:       filmRepo,
+		filmFeedRepo:   filmFeedRepo,
+		commentService: commentService,
+		baseURL:        baseURL,
+	}
+}
+
+// FilmsFeed builds the feed of the newest films. It is updated when the
+// most recently changed of them was.
+func (s *feedServiceImpl) FilmsFeed(ctx context.Context) (*feeds.Feed, error) {
+	films, err := s.filmFeedRepo.GetNewestFilms(ctx, feedEntryLimit)
+	if err != nil {
+		slog.Error("Failed to retrieve newest films", "error", err)
+		return nil, err
+	}
+
+	self := s.baseURL + "/feeds/films.atom"
+	feed := &feeds.Feed{
+		ID:      self,
+		Title:   "Mockbuster: New films",
+		Author:  &feeds.Person{Name: feedAuthor},
+		Links:   []feeds.Link{{Rel: "self", Href: self}, {Rel: "alternate", Href: s.baseURL + "/api/v1/films"}},
+		Entries: make([]feeds.Entry, 0, len(films)),
+	}
+	for _, film := range films {
+		feed.Entries = append(feed.Entries, s.filmEntry(film))
+		if film.LastUpdate.After(feed.Updated) {
+			feed.Updated = film.LastUpdate
+		}
+	}
+
+	return feed, nil
+}
+
+// FilmCommentsFeed builds the feed of a film's newest comments. It is
+// updated when the newest comment was posted, or when the film was if it
+// has none.
+func (s *feedServiceImpl) FilmCommentsFeed(ctx context.Context, filmID int) (*feeds.Feed, error) {
+	film, err := s.filmRepo.GetFilmByID(ctx, filmID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrFilmNotFound) {
+			slog.Error("Failed to retrieve film for comments feed", "filmID", filmID, "error", err)
+		}
+		return nil, err
+	}
+	if film.Status != models.FilmPublished {
+		return nil, repository.ErrFilmNotFound
+	}
+
+	// Comments come newest first.
+	page, err := s.commentService.GetCommentsByFilmID(ctx, filmID, models.CommentFilters{Limit: feedEntryLimit})
+	if err != nil {
+		return nil, err
+	}
+	comments := page.Items
+
+	filmURL := s.filmURL(filmID)
+	self := fmt.Sprintf("%s/feeds/films/%d/comments.atom", s.baseURL, filmID)
+	feed := &feeds.Feed{
+		ID:      self,
+		Title:   "Mockbuster: Comments on " + film.Title,
+		Updated: film.LastUpdate,
+		Author:  &feeds.Person{Name: feedAuthor},
+		Links:   []feeds.Link{{Rel: "self", Href: self}, {Rel: "alternate", Href: filmURL + "/comments"}},
+		Entries: make([]feeds.Entry, 0, len(comments)),
+	}
+	if len(comments) > 0 {
+		feed.Updated = comments[0].CreatedAt
+	}
+	for _, comment := range comments {
+		feed.Entries = append(feed.Entries, s.commentEntry(film, comment))
+	}
+
+	return feed, nil
+}
+
+// filmEntry builds the feed entry for a film.
+func (s *feedServiceImpl) filmEntry(film models.Film) feeds.Entry {
+	filmURL := s.filmURL(film.FilmID)
+	entry := feeds.Entry{
+		ID:      filmURL,
+		Title:   film.Title,
+		Updated: film.LastUpdate,
+		Links:   []feeds.Link{{Rel: "alternate", Type: "application/json", Href: filmURL}},
+	}
+	if film.ReleaseYear != nil {
+		entry.Title += " (" + strconv.Itoa(*film.ReleaseYear) + ")"
+	}
+	for _, category := range film.Categories {
+		entry.Categories = append(entry.Categories, feeds.Category{Term: category})
+	}
+	if film.Description != nil {
+		entry.Summary = &feeds.Text{Body: *film.Description}
+	}
+	return entry
+}
+
+// commentEntry builds the feed entry for a comment on film.
+func (s *feedServiceImpl) commentEntry(film *models.Film, comment models.Comment) feeds.Entry {
+	commentsURL := s.filmURL(film.FilmID) + "/comments"
+	published := comment.CreatedAt
+	return feeds.Entry{
+		ID:        commentsURL + "#comment-" + strconv.Itoa(comment.ID),
+		Title:     comment.DisplayName + " on " + film.Title,
+		Updated:   comment.CreatedAt,
+		Published: &published,
+		Author:    &feeds.Person{Name: comment.DisplayName},
+		Links:     []feeds.Link{{Rel: "alternate", Type: "application/json", Href: commentsURL}},
+		Content:   &feeds.Text{Type: "html", Body: comment.CommentHTML},
+	}
+}
+
+// filmURL returns the absolute URL of a film.
+func (s *feedServiceImpl) filmURL(filmID int) string {
+	return s.baseURL + "/api/v1/films/" + strconv.Itoa(filmID)
+}
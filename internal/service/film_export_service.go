@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrInvalidExportRequest is returned for a negative store ID or cursor.
+var ErrInvalidExportRequest = apperrors.New(apperrors.Invalid, "store ID and after film ID must not be negative")
+
+// filmExportServiceImpl implements the FilmExportService interface.
+type filmExportServiceImpl struct {
+	exportRepo repository.FilmExportRepositoryInterface
+}
+
+// NewFilmExportService creates a new film export service with the given
+// repository.
+func NewFilmExportService(exportRepo repository.FilmExportRepositoryInterface) FilmExportService {
+	return &filmExportServiceImpl{exportRepo: exportRepo}
+}
+
+// StreamFilms calls fn for each film after afterFilmID in ID order. It stops
+// early once ctx is done, so an abandoned export releases its connection.
+func (s *filmExportServiceImpl) StreamFilms(
+	ctx context.Context,
+	storeID, afterFilmID int,
+	fn func(models.Film) error,
+) error {
+	if storeID < 0 || afterFilmID < 0 {
+		slog.Warn("Invalid film export request", "storeID", storeID, "afterFilmID", afterFilmID)
+		return ErrInvalidExportRequest
+	}
+
+	count := 0
+	err := s.exportRepo.StreamFilms(ctx, storeID, afterFilmID, func(film models.Film) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if fnErr := fn(film); fnErr != nil {
+			return fnErr
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		slog.Error("Film export stopped",
+			"storeID", storeID, "afterFilmID", afterFilmID, "exported", count, "error", err)
+		return err
+	}
+
+	slog.Info("Successfully exported films", "storeID", storeID, "afterFilmID", afterFilmID, "count", count)
+	return nil
+}
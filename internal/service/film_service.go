@@ -6,51 +6,152 @@ import (
 	"errors"
 	"log/slog"
 
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/metrics"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/ratings"
 	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/tenant"
 )
 
+// ErrInvalidFilmID is returned for a film ID that is not positive.
+var ErrInvalidFilmID = apperrors.New(apperrors.Invalid, "invalid film ID")
+
 // filmServiceImpl implements the FilmService interface.
 type filmServiceImpl struct {
-	filmRepo repository.FilmRepositoryInterface
+	filmRepo      repository.FilmRepositoryInterface
+	pagination    pagination.Limits
+	ratingSystems ratings.Systems
+}
+
+// FilmServiceOption configures optional film service behavior.
+type FilmServiceOption func(*filmServiceImpl)
+
+// WithRatingSystems returns each film's rating in systems alongside its
+// MPAA rating.
+func WithRatingSystems(systems ratings.Systems) FilmServiceOption {
+	return func(s *filmServiceImpl) {
+		s.ratingSystems = systems
+	}
+}
+
+// NewFilmService creates a new film service with the given repository,
+// enforcing pageSizes' limits.
+func NewFilmService(
+	filmRepo repository.FilmRepositoryInterface,
+	pageSizes pagination.Limits,
+	opts ...FilmServiceOption,
+) FilmService {
+	s := &filmServiceImpl{
+		filmRepo:   filmRepo,
+		pagination: pageSizes,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetFilms retrieves published films with optional filtering and
+// pagination.
+func (s *filmServiceImpl) GetFilms(
+	ctx context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
+	filters.Statuses = nil
+	filters.IncludeArchived = false
+	return s.listFilms(ctx, filters)
+}
+
+// GetAdminFilms retrieves films of the given statuses, or drafts and
+// published films when none are given, with optional filtering and
+// pagination.
+func (s *filmServiceImpl) GetAdminFilms(
+	ctx context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
+	filters.Statuses = adminStatuses(filters)
+	return s.listFilms(ctx, filters)
 }
 
-// NewFilmService creates a new film service with the given repository.
-func NewFilmService(filmRepo repository.FilmRepositoryInterface) FilmService {
-	return &filmServiceImpl{
-		filmRepo: filmRepo,
+// adminStatuses returns the statuses of the films an admin listing selects:
+// those filters name, or every status but archived, unless IncludeArchived
+// asks for those too.
+func adminStatuses(filters models.FilmFilters) []string {
+	switch {
+	case len(filters.Statuses) > 0:
+		return filters.Statuses
+	case filters.IncludeArchived:
+		return models.FilmStatuses
+	default:
+		return []string{models.FilmDraft, models.FilmPublished}
 	}
 }
 
-// GetFilms retrieves films with optional filtering and pagination.
-func (s *filmServiceImpl) GetFilms(_ context.Context, filters models.FilmFilters) (*models.FilmListResponse, error) {
+// listFilms retrieves the films filters select, published only unless
+// filters name statuses.
+func (s *filmServiceImpl) listFilms(
+	ctx context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
+	if storeID, ok := tenant.StoreIDFromContext(ctx); ok {
+		filters.StoreID = storeID
+	}
+
+	filters.Tags = normalizeTags(filters.Tags)
 	if err := s.validateFilters(filters); err != nil {
 		slog.Warn("Invalid filters provided", "filters", filters, "error", err)
 		return nil, err
 	}
 
-	s.applyDefaultPagination(&filters)
+	s.pagination.Normalize(&filters.Params)
 
-	films, err := s.filmRepo.GetFilms(filters)
+	films, err := s.filmRepo.GetFilms(ctx, filters)
 	if err != nil {
 		slog.Error("Failed to retrieve films from repository", "filters", filters, "error", err)
 		return nil, err
 	}
 
-	slog.Info("Successfully retrieved films", "count", len(films.Films), "total", films.Total)
-	return films, nil
+	if filters.Page == 1 && isSearch(filters) {
+		result := "found"
+		if len(films.Items) == 0 {
+			result = "empty"
+		}
+		metrics.FilmSearches.WithLabelValues(result).Inc()
+	}
+
+	slog.Info("Successfully retrieved films", "count", len(films.Items), "total", films.Total)
+	return s.localizePage(films), nil
 }
 
-// GetFilmByID retrieves a specific film by its ID.
-func (s *filmServiceImpl) GetFilmByID(_ context.Context, filmID int) (*models.Film, error) {
+// GetFilmByID retrieves a specific published film by its ID. Films that are
+// not published are reported as not found.
+func (s *filmServiceImpl) GetFilmByID(ctx context.Context, filmID int) (*models.Film, error) {
+	film, err := s.GetAdminFilmByID(ctx, filmID)
+	if err != nil {
+		return nil, err
+	}
+	if film.Status != models.FilmPublished {
+		metrics.FilmLookupsNotFound.Inc()
+		slog.Warn("Film not published", "filmID", filmID, "status", film.Status)
+		return nil, repository.ErrFilmNotFound
+	}
+	return film, nil
+}
+
+// GetAdminFilmByID retrieves a specific film by its ID, whatever its status.
+func (s *filmServiceImpl) GetAdminFilmByID(ctx context.Context, filmID int) (*models.Film, error) {
 	if filmID <= 0 {
 		slog.Warn("Invalid film ID provided", "filmID", filmID)
-		return nil, errors.New("invalid film ID")
+		return nil, ErrInvalidFilmID
 	}
 
-	film, err := s.filmRepo.GetFilmByID(filmID)
+	film, err := s.filmRepo.GetFilmByID(ctx, filmID)
 	if err != nil {
 		if errors.Is(err, repository.ErrFilmNotFound) {
+			metrics.FilmLookupsNotFound.Inc()
 			slog.Warn("Film not found", "filmID", filmID)
 			return nil, err
 		}
@@ -59,12 +160,46 @@ func (s *filmServiceImpl) GetFilmByID(_ context.Context, filmID int) (*models.Fi
 	}
 
 	slog.Info("Successfully retrieved film", "filmID", filmID, "title", film.Title)
-	return film, nil
+	return s.localizeFilm(film), nil
+}
+
+// localizePage returns films with each film's local ratings set. The page
+// may be shared through the film cache, so it is copied rather than changed.
+func (s *filmServiceImpl) localizePage(films *pagination.Paginated[models.Film]) *pagination.Paginated[models.Film] {
+	if len(s.ratingSystems) == 0 {
+		return films
+	}
+
+	localized := *films
+	localized.Items = make([]models.Film, len(films.Items))
+	for i, film := range films.Items {
+		film.LocalRatings = s.ratingSystems.Localize(film.Rating)
+		localized.Items[i] = film
+	}
+	return &localized
+}
+
+// localizeFilm returns a copy of film with its local ratings set.
+func (s *filmServiceImpl) localizeFilm(film *models.Film) *models.Film {
+	if len(s.ratingSystems) == 0 {
+		return film
+	}
+
+	localized := *film
+	localized.LocalRatings = s.ratingSystems.Localize(film.Rating)
+	return &localized
+}
+
+// isSearch reports whether filters narrow the catalog, rather than only
+// scoping it to a store.
+func isSearch(filters models.FilmFilters) bool {
+	return filters.Title != "" || filters.Actor != "" || filters.Language != "" ||
+		len(filters.Ratings) > 0 || len(filters.Categories) > 0 || len(filters.Tags) > 0
 }
 
 // GetCategories retrieves all available film categories.
-func (s *filmServiceImpl) GetCategories(_ context.Context) ([]models.Category, error) {
-	categories, err := s.filmRepo.GetCategories()
+func (s *filmServiceImpl) GetCategories(ctx context.Context) ([]models.Category, error) {
+	categories, err := s.filmRepo.GetCategories(ctx)
 	if err != nil {
 		slog.Error("Failed to retrieve categories from repository", "error", err)
 		return nil, err
@@ -74,33 +209,11 @@ func (s *filmServiceImpl) GetCategories(_ context.Context) ([]models.Category, e
 	return categories, nil
 }
 
-// validateFilters validates the provided filters.
+// validateFilters checks filters by their validate tags, which GET /films
+// binds them with, and against the configured page sizes.
 func (s *filmServiceImpl) validateFilters(filters models.FilmFilters) error {
-	if filters.Page < 1 {
-		return errors.New("page must be greater than 0")
-	}
-	if filters.Limit < 1 || filters.Limit > 100 {
-		return errors.New("limit must be between 1 and 100")
-	}
-
-	if filters.Rating != "" {
-		validRatings := map[string]bool{
-			"G": true, "PG": true, "PG-13": true, "R": true, "NC-17": true,
-		}
-		if !validRatings[filters.Rating] {
-			return errors.New("invalid rating provided")
-		}
-	}
-
-	return nil
-}
-
-// applyDefaultPagination applies default pagination values if not provided.
-func (s *filmServiceImpl) applyDefaultPagination(filters *models.FilmFilters) {
-	if filters.Page <= 0 {
-		filters.Page = 1
-	}
-	if filters.Limit <= 0 {
-		filters.Limit = 10
+	if err := binding.Validate(filters); err != nil {
+		return err
 	}
+	return s.pagination.Check(filters.Params)
 }
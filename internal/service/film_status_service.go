@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/webhooks"
+)
+
+var (
+	// ErrInvalidFilmStatus is returned for a status other than draft,
+	// published, or archived.
+	ErrInvalidFilmStatus = apperrors.New(apperrors.Invalid, "status must be draft, published, or archived")
+	// ErrPublishAtPast is returned when scheduling a film to be published at
+	// a time that has already passed.
+	ErrPublishAtPast = apperrors.New(apperrors.Invalid, "publish_at must be in the future")
+)
+
+// filmStatusServiceImpl implements the FilmStatusService interface.
+type filmStatusServiceImpl struct {
+	filmRepo      repository.FilmStatusRepositoryInterface
+	invalidations cache.Publisher
+	events        EventPublisher
+	now           func() time.Time
+}
+
+// FilmStatusServiceOption configures optional film status service behavior.
+type FilmStatusServiceOption func(*filmStatusServiceImpl)
+
+// WithFilmEventPublisher publishes a film.published event each time a film
+// becomes published, by hand or on schedule.
+func WithFilmEventPublisher(publisher EventPublisher) FilmStatusServiceOption {
+	return func(s *filmStatusServiceImpl) {
+		s.events = publisher
+	}
+}
+
+// NewFilmStatusService creates a new film status service. Each change is
+// published to invalidations, so cached films and listings show or hide the
+// film at once.
+func NewFilmStatusService(
+	filmRepo repository.FilmStatusRepositoryInterface,
+	invalidations cache.Publisher,
+	opts ...FilmStatusServiceOption,
+) FilmStatusService {
+	s := &filmStatusServiceImpl{filmRepo: filmRepo, invalidations: invalidations, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// SetFilmStatus publishes a film, returns it to draft, or archives it,
+// cancelling any schedule.
+func (s *filmStatusServiceImpl) SetFilmStatus(
+	ctx context.Context,
+	filmID int,
+	status string,
+) (*models.FilmStatus, error) {
+	if !slices.Contains(models.FilmStatuses, status) {
+		return nil, ErrInvalidFilmStatus
+	}
+
+	filmStatus, previous, err := s.filmRepo.SetFilmStatus(ctx, filmID, status)
+	if err != nil {
+		if !errors.Is(err, repository.ErrFilmNotFound) {
+			slog.Error("Failed to change film status", "filmID", filmID, "status", status, "error", err)
+		}
+		return nil, err
+	}
+
+	s.invalidations.Publish(cache.FilmChanged(filmID))
+	if status == models.FilmPublished && previous != models.FilmPublished {
+		s.publishEvent(*filmStatus)
+	}
+	slog.Info("Film status changed", "filmID", filmID, "status", status, "previous", previous)
+	return filmStatus, nil
+}
+
+// ScheduleFilmPublish sets when a draft film is published, or cancels its
+// schedule when publishAt is nil.
+func (s *filmStatusServiceImpl) ScheduleFilmPublish(
+	ctx context.Context,
+	filmID int,
+	publishAt *time.Time,
+) (*models.FilmStatus, error) {
+	if publishAt != nil && !publishAt.After(s.now()) {
+		return nil, ErrPublishAtPast
+	}
+
+	filmStatus, err := s.filmRepo.ScheduleFilmPublish(ctx, filmID, publishAt)
+	if err != nil {
+		if !errors.Is(err, repository.ErrFilmNotFound) && !errors.Is(err, repository.ErrFilmNotDraft) {
+			slog.Error("Failed to schedule film", "filmID", filmID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.invalidations.Publish(cache.FilmChanged(filmID))
+	slog.Info("Film publish scheduled", "filmID", filmID, "publishAt", publishAt)
+	return filmStatus, nil
+}
+
+// ArchiveFilm withdraws a film from the catalog and admin listings. Its
+// rentals, payments, and comments are kept, and RestoreFilm brings it back.
+func (s *filmStatusServiceImpl) ArchiveFilm(ctx context.Context, filmID int) (*models.FilmStatus, error) {
+	return s.SetFilmStatus(ctx, filmID, models.FilmArchived)
+}
+
+// RestoreFilm publishes an archived film again.
+func (s *filmStatusServiceImpl) RestoreFilm(ctx context.Context, filmID int) (*models.FilmStatus, error) {
+	filmStatus, err := s.filmRepo.RestoreFilm(ctx, filmID)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to restore film", "filmID", filmID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.invalidations.Publish(cache.FilmChanged(filmID))
+	s.publishEvent(*filmStatus)
+	slog.Info("Film restored", "filmID", filmID)
+	return filmStatus, nil
+}
+
+// PublishScheduledFilms publishes the drafts whose publish_at has passed.
+// Films are published before their events are sent, so a failed event is
+// logged rather than retried.
+func (s *filmStatusServiceImpl) PublishScheduledFilms(ctx context.Context) error {
+	published, err := s.filmRepo.PublishScheduledFilms(ctx)
+	if err != nil {
+		return err
+	}
+	if len(published) == 0 {
+		return nil
+	}
+
+	filmIDs := make([]int, 0, len(published))
+	for _, filmStatus := range published {
+		filmIDs = append(filmIDs, filmStatus.FilmID)
+	}
+	s.invalidations.Publish(cache.FilmsChanged(filmIDs...))
+
+	for _, filmStatus := range published {
+		s.publishEvent(filmStatus)
+	}
+
+	slog.Info("Scheduled films published", "count", len(published))
+	return nil
+}
+
+// publishEvent sends a film.published event, logging a failure.
+func (s *filmStatusServiceImpl) publishEvent(filmStatus models.FilmStatus) {
+	if s.events == nil {
+		return
+	}
+	if err := s.events.Publish(webhooks.EventFilmPublished, filmStatus); err != nil {
+		slog.Warn("Failed to publish film event", "filmID", filmStatus.FilmID, "error", err)
+	}
+}
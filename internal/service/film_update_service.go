@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+var (
+	// ErrEmptyFilmUpdate is returned for a film update that changes nothing.
+	ErrEmptyFilmUpdate = apperrors.New(apperrors.Invalid, "at least one field must be given")
+	// ErrBulkPriceChange is returned for a bulk price change that does not
+	// give exactly one of rental_rate and percent.
+	ErrBulkPriceChange = apperrors.New(apperrors.Invalid, "exactly one of rental_rate and percent must be given")
+)
+
+// filmUpdateServiceImpl implements the FilmUpdateService interface.
+type filmUpdateServiceImpl struct {
+	filmRepo      repository.FilmUpdateRepositoryInterface
+	invalidations cache.Publisher
+}
+
+// NewFilmUpdateService creates a new film update service. Each change is
+// published to invalidations, so cached films and listings show it at once.
+func NewFilmUpdateService(
+	filmRepo repository.FilmUpdateRepositoryInterface,
+	invalidations cache.Publisher,
+) FilmUpdateService {
+	return &filmUpdateServiceImpl{filmRepo: filmRepo, invalidations: invalidations}
+}
+
+// UpdateFilm changes a film's details if its last_update is still version.
+// Otherwise someone else has changed it since the caller fetched it, and
+// repository.ErrFilmModified is returned so the caller can fetch it again
+// rather than overwrite that change.
+func (s *filmUpdateServiceImpl) UpdateFilm(
+	ctx context.Context,
+	filmID int,
+	version time.Time,
+	update models.FilmUpdateRequest,
+) (*models.Film, error) {
+	if update.Empty() {
+		return nil, ErrEmptyFilmUpdate
+	}
+
+	film, err := s.filmRepo.UpdateFilm(ctx, filmID, version, update)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to update film", "filmID", filmID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.invalidations.Publish(cache.FilmChanged(filmID))
+	slog.Info("Film updated", "filmID", filmID)
+	return film, nil
+}
+
+// BulkUpdatePrice changes the rental rate of the films filters select across
+// every page, with the statuses and store scope GET /admin/films lists them
+// with.
+func (s *filmUpdateServiceImpl) BulkUpdatePrice(
+	ctx context.Context,
+	filters models.FilmFilters,
+	change models.BulkPriceRequest,
+) (*models.BulkPriceResult, error) {
+	if (change.RentalRate == nil) == (change.Percent == nil) {
+		return nil, ErrBulkPriceChange
+	}
+
+	if storeID, ok := tenant.StoreIDFromContext(ctx); ok {
+		filters.StoreID = storeID
+	}
+	filters.Statuses = adminStatuses(filters)
+	filters.Tags = normalizeTags(filters.Tags)
+	filters.Params = pagination.Params{Page: 1, Limit: 1}
+	if err := binding.Validate(filters); err != nil {
+		return nil, err
+	}
+
+	result, filmIDs, err := s.filmRepo.BulkUpdateRentalRate(ctx, filters, change)
+	if err != nil {
+		slog.Error("Failed to update rental rates", "filters", filters, "error", err)
+		return nil, err
+	}
+
+	if len(filmIDs) > 0 {
+		s.invalidations.Publish(cache.FilmsChanged(filmIDs...))
+	}
+	slog.Info("Rental rates updated", "matched", result.FilmsMatched, "changed", result.FilmsChanged,
+		"dryRun", result.DryRun)
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/giftcards"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/payments"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// giftCardServiceImpl implements the GiftCardService interface.
+type giftCardServiceImpl struct {
+	giftCardRepo repository.GiftCardRepositoryInterface
+	provider     payments.Provider
+	currency     string
+}
+
+// NewGiftCardService creates a new gift card service selling cards in
+// currency through provider.
+func NewGiftCardService(
+	giftCardRepo repository.GiftCardRepositoryInterface,
+	provider payments.Provider,
+	currency string,
+) GiftCardService {
+	return &giftCardServiceImpl{
+		giftCardRepo: giftCardRepo,
+		provider:     provider,
+		currency:     currency,
+	}
+}
+
+// PurchaseGiftCard generates a gift card for a customer and starts
+// collecting its amount with the payment provider. The card's code is
+// returned this once and never stored; the card can be spent once the
+// payment succeeds.
+func (s *giftCardServiceImpl) PurchaseGiftCard(
+	ctx context.Context,
+	customerID int,
+	purchaseReq models.GiftCardPurchaseRequest,
+) (*models.GiftCard, error) {
+	code, codeHash, err := giftcards.NewCode()
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := s.giftCardRepo.CreateGiftCard(ctx, models.GiftCard{
+		LastFour:    giftcards.LastFour(code),
+		PurchaserID: customerID,
+		Amount:      math.Round(purchaseReq.Amount*100) / 100,
+		Currency:    s.currency,
+		Provider:    s.provider.Name(),
+	}, codeHash)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCustomerNotFound) {
+			slog.Error("Failed to create gift card", "customerID", customerID, "error", err)
+		}
+		return nil, err
+	}
+
+	intent, err := s.provider.CreateIntent(ctx, payments.IntentRequest{
+		Amount:         int64(math.Round(card.Amount * 100)),
+		Currency:       s.currency,
+		IdempotencyKey: "gift-card-" + strconv.Itoa(card.ID),
+		Metadata: map[string]string{
+			"gift_card_id": strconv.Itoa(card.ID),
+			"customer_id":  strconv.Itoa(customerID),
+		},
+	})
+	if err != nil {
+		slog.Error("Failed to create gift card payment intent", "giftCardID", card.ID, "error", err)
+		if failErr := s.giftCardRepo.FailGiftCard(ctx, card.ID); failErr != nil {
+			slog.Error("Failed to mark gift card failed", "giftCardID", card.ID, "error", failErr)
+		}
+		return nil, err
+	}
+
+	card, err = s.giftCardRepo.SetGiftCardPayment(ctx, card.ID, intent.ID, intent.ClientSecret)
+	if err != nil {
+		slog.Error("Failed to record gift card payment", "intentID", intent.ID, "error", err)
+		return nil, err
+	}
+	card.Code = code
+
+	slog.Info("Gift card purchase started", "giftCardID", card.ID, "customerID", customerID, "amount", card.Amount)
+	return card, nil
+}
+
+// CheckBalance retrieves what the gift card with code is worth.
+func (s *giftCardServiceImpl) CheckBalance(ctx context.Context, code string) (*models.GiftCardBalance, error) {
+	card, err := s.giftCardRepo.GetGiftCardByCode(ctx, giftcards.Hash(code))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GiftCardBalance{
+		LastFour: card.LastFour,
+		Balance:  card.Balance,
+		Currency: card.Currency,
+		Status:   card.Status,
+	}, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrGuestTokenRequired is returned when a guest watchlist is used without a
+// guest token.
+var ErrGuestTokenRequired = apperrors.New(apperrors.Invalid, "a guest token is required")
+
+// guestServiceImpl implements the GuestService interface.
+type guestServiceImpl struct {
+	guestRepo repository.GuestRepositoryInterface
+	tokens    *auth.TokenIssuer
+}
+
+// NewGuestService creates a new guest session service issuing guest tokens
+// with the given issuer.
+func NewGuestService(guestRepo repository.GuestRepositoryInterface, tokens *auth.TokenIssuer) GuestService {
+	return &guestServiceImpl{guestRepo: guestRepo, tokens: tokens}
+}
+
+// StartSession starts a guest session and issues a token naming it.
+func (s *guestServiceImpl) StartSession(ctx context.Context) (*models.GuestSession, error) {
+	guestID, err := s.guestRepo.CreateSession(ctx)
+	if err != nil {
+		slog.Error("Failed to create guest session", "error", err)
+		return nil, err
+	}
+
+	token, expiresAt, err := s.tokens.Issue(guestID)
+	if err != nil {
+		slog.Error("Failed to issue guest token", "guestID", guestID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Guest session started", "guestID", guestID)
+	return &models.GuestSession{GuestID: guestID, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// GetWatchlist retrieves the watchlist of the guest whose token made the
+// request.
+func (s *guestServiceImpl) GetWatchlist(ctx context.Context) ([]models.GuestWatchlistEntry, error) {
+	guestID, err := guestFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.guestRepo.ListWatchlist(ctx, guestID)
+	if err != nil {
+		slog.Error("Failed to list guest watchlist", "guestID", guestID, "error", err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// AddToWatchlist adds a film to the watchlist of the guest whose token made
+// the request. Adding a film already on it changes nothing.
+func (s *guestServiceImpl) AddToWatchlist(ctx context.Context, filmID int) error {
+	guestID, err := guestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = s.guestRepo.AddToWatchlist(ctx, guestID, filmID); err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to add film to guest watchlist", "guestID", guestID, "filmID", filmID,
+				"error", err)
+		}
+		return err
+	}
+
+	slog.Info("Film added to guest watchlist", "guestID", guestID, "filmID", filmID)
+	return nil
+}
+
+// RemoveFromWatchlist removes a film from the watchlist of the guest whose
+// token made the request.
+func (s *guestServiceImpl) RemoveFromWatchlist(ctx context.Context, filmID int) error {
+	guestID, err := guestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = s.guestRepo.RemoveFromWatchlist(ctx, guestID, filmID); err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to remove film from guest watchlist", "guestID", guestID, "filmID", filmID,
+				"error", err)
+		}
+		return err
+	}
+
+	slog.Info("Film removed from guest watchlist", "guestID", guestID, "filmID", filmID)
+	return nil
+}
+
+// RecordGuestComment records that a guest posted a comment, so it is linked
+// to the account they register.
+func (s *guestServiceImpl) RecordGuestComment(ctx context.Context, guestID, commentID int) error {
+	return s.guestRepo.RecordComment(ctx, guestID, commentID)
+}
+
+// MergeGuest moves a guest's comments and watchlist into a customer's
+// account.
+func (s *guestServiceImpl) MergeGuest(
+	ctx context.Context,
+	guestID, customerID int,
+) (*models.GuestMerge, error) {
+	merge, err := s.guestRepo.MergeSession(ctx, guestID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Guest session merged", "guestID", guestID, "customerID", customerID,
+		"commentsLinked", merge.CommentsLinked, "watchlistFilms", merge.WatchlistFilms)
+	return merge, nil
+}
+
+// guestFromContext returns the guest session named by the token claims in
+// ctx.
+func guestFromContext(ctx context.Context) (int, error) {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok || claims.Role != auth.RoleGuest {
+		return 0, ErrGuestTokenRequired
+	}
+	return claims.Subject, nil
+}
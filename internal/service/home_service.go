@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+const (
+	// homeSectionSize is the most entries a home feed section shows.
+	homeSectionSize = 10
+	// homeFavoriteCategories is how many of a customer's most rented
+	// categories the new films section draws from.
+	homeFavoriteCategories = 3
+)
+
+// homeSection loads one section of a home feed. It returns a function
+// filling the section in, so that only GetHome's goroutine writes the feed.
+type homeSection struct {
+	name string
+	load func(ctx context.Context, customerID int) (func(*models.HomeFeed), error)
+}
+
+// homeSectionResult is a loaded, or failed, home feed section.
+type homeSectionResult struct {
+	name string
+	fill func(*models.HomeFeed)
+	err  error
+}
+
+// homeServiceImpl implements the HomeService interface.
+type homeServiceImpl struct {
+	homeRepo      repository.HomeRepositoryInterface
+	rentalService RentalService
+	budget        time.Duration
+}
+
+// NewHomeService creates a new home feed service. Each feed is assembled
+// within budget; sections not ready by then are left out.
+func NewHomeService(
+	homeRepo repository.HomeRepositoryInterface,
+	rentalService RentalService,
+	budget time.Duration,
+) HomeService {
+	return &homeServiceImpl{homeRepo: homeRepo, rentalService: rentalService, budget: budget}
+}
+
+// GetHome assembles a customer's home feed, loading its sections
+// concurrently. A section that fails, or is not ready when the latency
+// budget runs out, is left empty and named in the feed's Incomplete, so a
+// slow query costs the customer one section rather than the page. Only the
+// request itself ending fails the feed.
+func (s *homeServiceImpl) GetHome(requestCtx context.Context, customerID int) (*models.HomeFeed, error) {
+	ctx, cancel := context.WithTimeout(requestCtx, s.budget)
+	defer cancel()
+
+	sections := []homeSection{
+		{name: models.HomeSectionOpenRentals, load: s.loadOpenRentals},
+		{name: models.HomeSectionRecommended, load: s.loadRecommended},
+		{name: models.HomeSectionNewInFavorites, load: s.loadNewInFavorites},
+	}
+	// Buffered so sections finishing after the budget do not block.
+	results := make(chan homeSectionResult, len(sections))
+	for _, section := range sections {
+		go func() {
+			fill, err := section.load(ctx, customerID)
+			results <- homeSectionResult{name: section.name, fill: fill, err: err}
+		}()
+	}
+
+	feed := &models.HomeFeed{
+		CustomerID:         customerID,
+		OpenRentals:        []models.RentalEvent{},
+		Recommended:        []models.HomeFilm{},
+		FavoriteCategories: []string{},
+		NewInFavorites:     []models.HomeFilm{},
+	}
+	done := make(map[string]bool, len(sections))
+collect:
+	for range sections {
+		select {
+		case result := <-results:
+			done[result.name] = true
+			if result.err != nil {
+				slog.Error("Failed to load home feed section", "customerID", customerID, "section", result.name,
+					"error", result.err)
+				feed.Incomplete = append(feed.Incomplete, result.name)
+				continue
+			}
+			result.fill(feed)
+		case <-ctx.Done():
+			break collect
+		}
+	}
+	if err := requestCtx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, section := range sections {
+		if !done[section.name] {
+			slog.Warn("Home feed section missed latency budget", "customerID", customerID, "section", section.name,
+				"budget", s.budget)
+			feed.Incomplete = append(feed.Incomplete, section.name)
+		}
+	}
+
+	feed.GeneratedAt = time.Now().UTC()
+	return feed, nil
+}
+
+// loadOpenRentals loads the customer's rentals not yet returned, overdue
+// ones first.
+func (s *homeServiceImpl) loadOpenRentals(
+	ctx context.Context,
+	customerID int,
+) (func(*models.HomeFeed), error) {
+	var rentals []models.RentalEvent
+	for _, status := range []string{models.RentalStatusOverdue, models.RentalStatusOpen} {
+		page, err := s.rentalService.GetCustomerRentals(ctx, customerID, models.RentalHistoryFilters{
+			Status: status,
+			Params: pagination.Params{Page: 1, Limit: homeSectionSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		rentals = append(rentals, page.Items...)
+	}
+	if len(rentals) > homeSectionSize {
+		rentals = rentals[:homeSectionSize]
+	}
+
+	return func(feed *models.HomeFeed) {
+		feed.OpenRentals = append(feed.OpenRentals, rentals...)
+	}, nil
+}
+
+// loadRecommended loads the films rented by customers with similar
+// rentals. A customer who has rented nothing yet is shown the trending
+// films instead.
+func (s *homeServiceImpl) loadRecommended(
+	ctx context.Context,
+	customerID int,
+) (func(*models.HomeFeed), error) {
+	films, err := s.homeRepo.GetRecommendedFilms(ctx, customerID, homeSectionSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(films) == 0 {
+		trending, trendingErr := s.rentalService.GetTrendingFilms(ctx)
+		if trendingErr != nil {
+			return nil, trendingErr
+		}
+		for _, film := range trending[:min(len(trending), homeSectionSize)] {
+			films = append(films, models.HomeFilm{FilmID: film.FilmID, Title: film.Title})
+		}
+	}
+
+	return func(feed *models.HomeFeed) {
+		feed.Recommended = films
+	}, nil
+}
+
+// loadNewInFavorites loads the most recently published films in the
+// categories the customer rents most, leaving out those they have rented.
+func (s *homeServiceImpl) loadNewInFavorites(
+	ctx context.Context,
+	customerID int,
+) (func(*models.HomeFeed), error) {
+	categories, err := s.homeRepo.GetFavoriteCategories(ctx, customerID, homeFavoriteCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	films := []models.HomeFilm{}
+	if len(categories) > 0 {
+		films, err = s.homeRepo.GetNewFilmsInCategories(ctx, customerID, categories, homeSectionSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(feed *models.HomeFeed) {
+		feed.FavoriteCategories = categories
+		feed.NewInFavorites = films
+	}, nil
+}
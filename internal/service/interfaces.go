@@ -3,20 +3,78 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"time"
 
+	"github.com/rxbenefits/go-hw/internal/feeds"
+	"github.com/rxbenefits/go-hw/internal/ical"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/sitemaps"
 )
 
 // FilmService defines the interface for film-related business operations.
 type FilmService interface {
-	// GetFilms retrieves films with optional filtering and pagination.
-	GetFilms(ctx context.Context, filters models.FilmFilters) (*models.FilmListResponse, error)
+	// GetFilms retrieves published films with optional filtering and pagination.
+	GetFilms(ctx context.Context, filters models.FilmFilters) (*pagination.Paginated[models.Film], error)
 
-	// GetFilmByID retrieves a specific film by its ID.
+	// GetFilmByID retrieves a specific published film by its ID.
 	GetFilmByID(ctx context.Context, filmID int) (*models.Film, error)
 
 	// GetCategories retrieves all available film categories.
 	GetCategories(ctx context.Context) ([]models.Category, error)
+
+	// GetAdminFilms retrieves films of any status with optional filtering and pagination.
+	GetAdminFilms(ctx context.Context, filters models.FilmFilters) (*pagination.Paginated[models.Film], error)
+
+	// GetAdminFilmByID retrieves a specific film by its ID, whatever its status.
+	GetAdminFilmByID(ctx context.Context, filmID int) (*models.Film, error)
+}
+
+// FilmStatusService defines the interface for publishing and withdrawing
+// films.
+type FilmStatusService interface {
+	// SetFilmStatus changes a film's status, cancelling any schedule.
+	SetFilmStatus(ctx context.Context, filmID int, status string) (*models.FilmStatus, error)
+
+	// ScheduleFilmPublish sets or cancels when a draft film is published.
+	ScheduleFilmPublish(ctx context.Context, filmID int, publishAt *time.Time) (*models.FilmStatus, error)
+
+	// PublishScheduledFilms publishes the drafts whose publish_at has passed.
+	PublishScheduledFilms(ctx context.Context) error
+
+	// ArchiveFilm hides a film from listings, keeping its history.
+	ArchiveFilm(ctx context.Context, filmID int) (*models.FilmStatus, error)
+
+	// RestoreFilm publishes an archived film again.
+	RestoreFilm(ctx context.Context, filmID int) (*models.FilmStatus, error)
+}
+
+// FilmUpdateService defines the interface for editing films.
+type FilmUpdateService interface {
+	// UpdateFilm changes a film's details if it is still at version, its
+	// last_update.
+	UpdateFilm(
+		ctx context.Context,
+		filmID int,
+		version time.Time,
+		update models.FilmUpdateRequest,
+	) (*models.Film, error)
+
+	// BulkUpdatePrice changes the rental rate of the films filters select.
+	BulkUpdatePrice(
+		ctx context.Context,
+		filters models.FilmFilters,
+		change models.BulkPriceRequest,
+	) (*models.BulkPriceResult, error)
+}
+
+// FilmExportService defines the interface for exporting the film catalog.
+type FilmExportService interface {
+	// StreamFilms calls fn for each film after afterFilmID in ID order,
+	// limited to one store when storeID is non-zero, stopping at fn's first error.
+	StreamFilms(ctx context.Context, storeID, afterFilmID int, fn func(models.Film) error) error
 }
 
 // CommentService defines the interface for comment-related business operations.
@@ -24,6 +82,568 @@ type CommentService interface {
 	// AddComment adds a new comment to a film.
 	AddComment(ctx context.Context, filmID int, commentReq models.CommentRequest) (*models.Comment, error)
 
-	// GetCommentsByFilmID retrieves all comments for a specific film.
-	GetCommentsByFilmID(ctx context.Context, filmID int) ([]models.Comment, error)
+	// GetCommentsByFilmID retrieves a page of a film's comments, newest
+	// first, hiding shadow-banned customers' comments from all but themselves.
+	GetCommentsByFilmID(
+		ctx context.Context,
+		filmID int,
+		filters models.CommentFilters,
+	) (*pagination.Paginated[models.Comment], error)
+
+	// SetCommentsLocked locks or unlocks new comments on a film.
+	SetCommentsLocked(ctx context.Context, filmID int, locked bool) (*models.CommentLockStatus, error)
+
+	// SetShadowBanned shadow-bans a customer or lifts their ban.
+	SetShadowBanned(ctx context.Context, customerID int, banned bool) (*models.ShadowBanStatus, error)
+
+	// GetCommentSummary retrieves a film's most recently computed comment summary.
+	GetCommentSummary(ctx context.Context, filmID int) (*models.CommentSummary, error)
+
+	// RefreshCommentSummaries recomputes every film's comment summary.
+	RefreshCommentSummaries(ctx context.Context) error
+}
+
+// StaffService defines the interface for staff management and staff
+// authentication.
+type StaffService interface {
+	// ListStaff retrieves staff members, limited to the request's store if scoped.
+	ListStaff(ctx context.Context) ([]models.Staff, error)
+
+	// CreateStaff adds a new staff member.
+	CreateStaff(ctx context.Context, staffReq models.StaffRequest) (*models.Staff, error)
+
+	// DeactivateStaff marks a staff member inactive.
+	DeactivateStaff(ctx context.Context, staffID int) (*models.Staff, error)
+
+	// Login exchanges staff credentials for a staff bearer token.
+	Login(ctx context.Context, loginReq models.StaffLoginRequest) (*models.TokenResponse, error)
+}
+
+// CustomerService defines the interface for customer registration and
+// customer authentication.
+type CustomerService interface {
+	// Register creates a customer account.
+	Register(ctx context.Context, registerReq models.CustomerRegisterRequest) (*models.Customer, error)
+
+	// Login exchanges customer credentials for a customer bearer token.
+	Login(ctx context.Context, loginReq models.CustomerLoginRequest) (*models.TokenResponse, error)
+
+	// EraseData erases a customer's personal data, on behalf of the caller in ctx.
+	EraseData(ctx context.Context, customerID int) (*models.CustomerErasure, error)
+
+	// MergeCustomers merges a duplicate customer record into another, on behalf of the caller in ctx.
+	MergeCustomers(ctx context.Context, sourceID, targetID int) (*models.CustomerMerge, error)
+}
+
+// NotificationPreferenceService defines the interface for customer
+// notification preferences.
+type NotificationPreferenceService interface {
+	// GetPreferences returns a customer's effective preferences, including defaults.
+	GetPreferences(ctx context.Context, customerID int) (*models.NotificationPreferencesResponse, error)
+
+	// UpdatePreferences stores some or all of a customer's preferences.
+	UpdatePreferences(
+		ctx context.Context,
+		customerID int,
+		prefs models.NotificationPreferences,
+	) (*models.NotificationPreferencesResponse, error)
+
+	// Allowed reports whether a customer accepts event notifications on channel.
+	Allowed(ctx context.Context, customerID int, event, channel string) (bool, error)
+}
+
+// RentalService defines the interface for rental operations, including the
+// periodic jobs run by the scheduler.
+type RentalService interface {
+	// GetTrendingFilms retrieves the most recently computed trending films.
+	GetTrendingFilms(ctx context.Context) ([]models.TrendingFilm, error)
+
+	// GetFilmRentals retrieves a page of a film's rental history.
+	GetFilmRentals(
+		ctx context.Context, filmID int, filters models.RentalHistoryFilters,
+	) (*pagination.Paginated[models.RentalEvent], error)
+
+	// GetCustomerRentals retrieves a page of a customer's rental history.
+	GetCustomerRentals(
+		ctx context.Context, customerID int, filters models.RentalHistoryFilters,
+	) (*pagination.Paginated[models.RentalEvent], error)
+
+	// GetOverdueReport aggregates overdue rentals by film with accrued fees.
+	GetOverdueReport(ctx context.Context, filters models.OverdueReportFilters) (*models.OverdueReport, error)
+
+	// ReturnRental records that a rental has come back.
+	ReturnRental(ctx context.Context, rentalID int) (*models.RentalEvent, error)
+
+	// MarkOverdueRentals flags open rentals that are past their due date.
+	MarkOverdueRentals(ctx context.Context) error
+
+	// SendDueReminders queues reminders for rentals due soon.
+	SendDueReminders(ctx context.Context) error
+
+	// RefreshTrendingFilms recomputes the trending film ranking.
+	RefreshTrendingFilms(ctx context.Context) error
+}
+
+// AvailabilityService defines the interface for the alerts customers ask
+// for when a film they want is rented out.
+type AvailabilityService interface {
+	// NotifyMe registers the calling customer to hear when a film is
+	// available, or returns their pending alert with created false.
+	NotifyMe(ctx context.Context, filmID int) (alert *models.AvailabilityAlert, created bool, err error)
+
+	// NotifyAvailable notifies the customers waiting for a film.
+	NotifyAvailable(ctx context.Context, filmID int) error
+}
+
+// BackgroundJobService defines the interface for inspecting and retrying
+// persistent background jobs.
+type BackgroundJobService interface {
+	// ListJobs retrieves recently updated jobs matching filters.
+	ListJobs(ctx context.Context, filters models.BackgroundJobFilters) ([]models.BackgroundJob, error)
+
+	// GetJob retrieves a job by its ID.
+	GetJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error)
+
+	// RetryJob requeues a dead job with a fresh set of attempts.
+	RetryJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error)
+
+	// PurgeJobs deletes finished jobs older than the retention period.
+	PurgeJobs(ctx context.Context) error
+}
+
+// WebhookService defines the interface for managing webhook subscriptions and
+// their delivery history.
+type WebhookService interface {
+	// ListSubscriptions retrieves every webhook subscription, without secrets.
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+
+	// CreateSubscription adds a webhook subscription with a new signing secret.
+	CreateSubscription(
+		ctx context.Context,
+		subscriptionReq models.WebhookSubscriptionRequest,
+	) (*models.WebhookSubscription, error)
+
+	// DeleteSubscription removes a webhook subscription and its delivery history.
+	DeleteSubscription(ctx context.Context, subscriptionID int) error
+
+	// RotateSecret issues a new signing secret for a subscription.
+	RotateSecret(ctx context.Context, subscriptionID int) (*models.WebhookSubscription, error)
+
+	// ListDeliveries retrieves a subscription's most recent deliveries.
+	ListDeliveries(
+		ctx context.Context,
+		subscriptionID int,
+		filters models.WebhookDeliveryFilters,
+	) ([]models.WebhookDelivery, error)
+
+	// ReplayDelivery queues a failed delivery to be sent again.
+	ReplayDelivery(ctx context.Context, subscriptionID int, deliveryID int64) (*models.WebhookDelivery, error)
+
+	// PurgeDeliveries deletes delivery history older than the retention period.
+	PurgeDeliveries(ctx context.Context) error
+}
+
+// APIKeyService defines the interface for issuing API keys and metering
+// their usage.
+type APIKeyService interface {
+	// CreateKey issues a new API key, returning the key only this once.
+	CreateKey(ctx context.Context, keyReq models.APIKeyRequest) (*models.APIKey, error)
+
+	// ListKeys retrieves every API key, including revoked ones.
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+
+	// RevokeKey revokes an API key.
+	RevokeKey(ctx context.Context, keyID int) (*models.APIKey, error)
+
+	// Authenticate returns the active API key matching rawKey.
+	Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error)
+
+	// RecordRequest counts a request against a key's monthly quota, reporting whether it was within it.
+	RecordRequest(ctx context.Context, key *models.APIKey) (*models.APIKeyUsage, bool, error)
+
+	// GetUsage reports a key's consumption in the current month.
+	GetUsage(ctx context.Context, key *models.APIKey) (*models.APIKeyUsage, error)
+}
+
+// InventoryService defines the interface for managing inventory copies.
+type InventoryService interface {
+	// AddInventory stocks new copies of a film at a store.
+	AddInventory(ctx context.Context, filmID int, inventoryReq models.InventoryRequest) ([]models.InventoryCopy, error)
+
+	// GetInventoryCopy retrieves a copy with its status.
+	GetInventoryCopy(ctx context.Context, inventoryID int) (*models.InventoryCopy, error)
+
+	// ListFilmInventory retrieves a film's copies with their status.
+	ListFilmInventory(ctx context.Context, filmID int, filters models.InventoryFilters) ([]models.InventoryCopy, error)
+
+	// RetireInventory takes a copy out of stock.
+	RetireInventory(ctx context.Context, inventoryID int) (*models.InventoryCopy, error)
+
+	// TransferInventory moves a copy to another store.
+	TransferInventory(
+		ctx context.Context, inventoryID int, transferReq models.InventoryTransferRequest,
+	) (*models.InventoryTransfer, error)
+
+	// ListInventoryTransfers retrieves a copy's transfer history.
+	ListInventoryTransfers(ctx context.Context, inventoryID int) ([]models.InventoryTransfer, error)
+}
+
+// PricingService defines the interface for pricing rules and film prices.
+type PricingService interface {
+	// CreateRule stores a new pricing rule.
+	CreateRule(ctx context.Context, ruleReq models.PricingRuleRequest) (*models.PricingRule, error)
+
+	// ListRules retrieves every pricing rule.
+	ListRules(ctx context.Context) ([]models.PricingRule, error)
+
+	// DeleteRule deletes a pricing rule.
+	DeleteRule(ctx context.Context, ruleID int) error
+
+	// GetFilmPrice prices a film at the given time, for a customer's store when customerID is set.
+	GetFilmPrice(ctx context.Context, filmID int, customerID *int, at time.Time) (*models.FilmPrice, error)
+}
+
+// CouponService defines the interface for creating and using coupons.
+type CouponService interface {
+	// CreateCoupon creates a coupon.
+	CreateCoupon(ctx context.Context, couponReq models.CouponRequest) (*models.Coupon, error)
+
+	// ListCoupons retrieves every coupon with its redemption count.
+	ListCoupons(ctx context.Context) ([]models.Coupon, error)
+
+	// ValidateCoupon reports the discount a coupon would give a customer's checkout.
+	ValidateCoupon(ctx context.Context, code string, customerID int, amount float64) (*models.CouponQuote, error)
+
+	// RedeemCoupon uses a coupon for a customer's checkout.
+	RedeemCoupon(ctx context.Context, code string, customerID int, amount float64) (*models.CouponRedemption, error)
+}
+
+// CartService defines the interface for customer carts and checkout.
+type CartService interface {
+	// GetCart retrieves a customer's cart, priced at their store.
+	GetCart(ctx context.Context, customerID int) (*models.Cart, error)
+
+	// AddItem adds a film to a customer's cart, returning the cart.
+	AddItem(ctx context.Context, customerID, filmID int) (*models.Cart, error)
+
+	// RemoveItem removes a film from a customer's cart, returning the cart.
+	RemoveItem(ctx context.Context, customerID, filmID int) (*models.Cart, error)
+
+	// Checkout rents out every film in a customer's cart.
+	Checkout(ctx context.Context, customerID int, checkoutReq models.CheckoutRequest) (*models.Checkout, error)
+}
+
+// PaymentService defines the interface for paying for checkouts.
+type PaymentService interface {
+	// StartPayment starts collecting a customer's checkout total with the payment provider.
+	StartPayment(ctx context.Context, customerID, checkoutID int) (*models.Payment, error)
+
+	// HandleWebhook verifies a payment provider callback and applies it.
+	HandleWebhook(ctx context.Context, payload []byte, header http.Header) error
+
+	// RefundPayment returns part or all of a succeeded payment, once per idempotency key.
+	RefundPayment(
+		ctx context.Context,
+		paymentID int,
+		idempotencyKey string,
+		refundReq models.RefundRequest,
+	) (*models.Refund, error)
+}
+
+// ReceiptService defines the interface for rental receipts and invoices.
+type ReceiptService interface {
+	// GetReceipt retrieves the receipt for a rental, if the caller may see it.
+	GetReceipt(ctx context.Context, rentalID int) (*models.Receipt, error)
+
+	// GetCustomerInvoices retrieves a page of a customer's invoices, most recent first.
+	GetCustomerInvoices(
+		ctx context.Context, customerID int, filters models.InvoiceFilters,
+	) (*pagination.Paginated[models.Invoice], error)
+}
+
+// LoyaltyService defines the interface for loyalty points and rental credit.
+type LoyaltyService interface {
+	// GetBalance retrieves a customer's points and rental credit.
+	GetBalance(ctx context.Context, customerID int) (*models.LoyaltyBalance, error)
+
+	// GetHistory retrieves a page of a customer's loyalty ledger, most recent first.
+	GetHistory(
+		ctx context.Context, customerID int, filters models.LoyaltyHistoryFilters,
+	) (*pagination.Paginated[models.LoyaltyEntry], error)
+
+	// RedeemPoints exchanges a customer's points for rental credit.
+	RedeemPoints(
+		ctx context.Context, customerID int, redeemReq models.RedeemPointsRequest,
+	) (*models.LoyaltyEntry, error)
+
+	// AwardCommentPoints credits a customer with the points for a comment they posted.
+	AwardCommentPoints(ctx context.Context, customerID, commentID int) error
+}
+
+// GiftCardService defines the interface for buying and checking gift cards.
+type GiftCardService interface {
+	// PurchaseGiftCard generates a gift card for a customer and starts collecting its amount.
+	PurchaseGiftCard(
+		ctx context.Context, customerID int, purchaseReq models.GiftCardPurchaseRequest,
+	) (*models.GiftCard, error)
+
+	// CheckBalance retrieves what the gift card with code is worth.
+	CheckBalance(ctx context.Context, code string) (*models.GiftCardBalance, error)
+}
+
+// CollectionService defines the interface for film collections.
+type CollectionService interface {
+	// CreateCollection stores a new collection with its films in order.
+	CreateCollection(ctx context.Context, collectionReq models.CollectionRequest) (*models.Collection, error)
+
+	// ListCollections retrieves every collection, without their films.
+	ListCollections(ctx context.Context) ([]models.Collection, error)
+
+	// GetCollection retrieves a collection with its films in order.
+	GetCollection(ctx context.Context, collectionID int) (*models.Collection, error)
+
+	// UpdateCollection replaces a collection's name, description, and films.
+	UpdateCollection(
+		ctx context.Context, collectionID int, collectionReq models.CollectionRequest,
+	) (*models.Collection, error)
+
+	// DeleteCollection deletes a collection, keeping its films.
+	DeleteCollection(ctx context.Context, collectionID int) error
+}
+
+// TagService defines the interface for free-form film tags.
+type TagService interface {
+	// TagFilm adds a tag to a film, returning the film's tags.
+	TagFilm(ctx context.Context, filmID int, tag string) (*models.FilmTags, error)
+
+	// UntagFilm removes a tag from a film, returning the film's tags.
+	UntagFilm(ctx context.Context, filmID int, tag string) (*models.FilmTags, error)
+
+	// ListTags retrieves every tag in use with the number of films carrying it.
+	ListTags(ctx context.Context) ([]models.TagCount, error)
+}
+
+// CustomerListService defines the interface for customer-curated film lists.
+type CustomerListService interface {
+	// CreateList stores a new, empty list for a customer.
+	CreateList(ctx context.Context, customerID int, listReq models.CustomerListRequest) (*models.CustomerList, error)
+
+	// ListCustomerLists retrieves a customer's lists, without their entries.
+	ListCustomerLists(ctx context.Context, customerID int) ([]models.CustomerList, error)
+
+	// GetCustomerList retrieves one of a customer's own lists with its entries.
+	GetCustomerList(ctx context.Context, customerID, listID int) (*models.CustomerList, error)
+
+	// GetSharedList retrieves a list with its entries if the caller may see it.
+	GetSharedList(ctx context.Context, listID int) (*models.CustomerList, error)
+
+	// UpdateList replaces the name, description, and visibility of a customer's list.
+	UpdateList(
+		ctx context.Context, customerID, listID int, listReq models.CustomerListRequest,
+	) (*models.CustomerList, error)
+
+	// DeleteList deletes a customer's list and its entries.
+	DeleteList(ctx context.Context, customerID, listID int) error
+
+	// AddListEntry adds a film to the end of a customer's list.
+	AddListEntry(ctx context.Context, customerID, listID, filmID int) (*models.CustomerList, error)
+
+	// RemoveListEntry removes a film from a customer's list.
+	RemoveListEntry(ctx context.Context, customerID, listID, filmID int) (*models.CustomerList, error)
+
+	// ReorderListEntries puts the films on a customer's list in the given order.
+	ReorderListEntries(ctx context.Context, customerID, listID int, filmIDs []int) (*models.CustomerList, error)
+}
+
+// ActivityService defines the interface for customer follows and activity
+// feeds.
+type ActivityService interface {
+	// Follow makes followerID follow followedID.
+	Follow(ctx context.Context, followerID, followedID int) error
+
+	// Unfollow stops followerID following followedID.
+	Unfollow(ctx context.Context, followerID, followedID int) error
+
+	// ListFollowing retrieves the customers a customer follows.
+	ListFollowing(ctx context.Context, customerID int) ([]models.Follow, error)
+
+	// GetFeed retrieves a page of the activity of the customers the caller follows.
+	GetFeed(ctx context.Context, filters models.ActivityFeedFilters) (*pagination.Paginated[models.Activity], error)
+
+	// RecordActivity adds an entry to a customer's public activity.
+	RecordActivity(ctx context.Context, activity models.Activity) error
+}
+
+// ShortLinkService defines the interface for short links to films.
+type ShortLinkService interface {
+	// CreateShortLink creates a short link to a film.
+	CreateShortLink(ctx context.Context, filmID int, linkReq models.ShortLinkRequest) (*models.ShortLink, error)
+
+	// ListFilmShortLinks retrieves the short links to a film with their clicks.
+	ListFilmShortLinks(ctx context.Context, filmID int) ([]models.ShortLink, error)
+
+	// FollowShortLink counts a click on a short link and returns the URL it redirects to.
+	FollowShortLink(ctx context.Context, code string) (string, error)
+}
+
+// FeedService defines the interface for the Atom feeds of the catalog.
+type FeedService interface {
+	// FilmsFeed builds the feed of the newest films.
+	FilmsFeed(ctx context.Context) (*feeds.Feed, error)
+
+	// FilmCommentsFeed builds the feed of a film's newest comments.
+	FilmCommentsFeed(ctx context.Context, filmID int) (*feeds.Feed, error)
+}
+
+// CalendarService defines the interface for customers' rental due-date
+// calendars.
+type CalendarService interface {
+	// IssueCalendarURL returns the tokened URL a customer subscribes to for their due dates.
+	IssueCalendarURL(ctx context.Context, customerID int) (*models.CalendarSubscription, error)
+
+	// RentalCalendar builds the calendar of a customer's rentals not yet returned.
+	RentalCalendar(ctx context.Context, customerID int) (*ical.Calendar, error)
+}
+
+// SitemapService defines the interface for the sitemaps of the public
+// catalog.
+type SitemapService interface {
+	// SitemapIndex builds the sitemap index listing the film sitemaps.
+	SitemapIndex(ctx context.Context) (*sitemaps.Index, error)
+
+	// FilmSitemap builds a page of the film sitemap.
+	FilmSitemap(ctx context.Context, page int) (*sitemaps.URLSet, error)
+}
+
+// DashboardService defines the interface for the admin dashboard.
+type DashboardService interface {
+	// GetDashboard gathers the dashboard's statistics.
+	GetDashboard(ctx context.Context) (*models.Dashboard, error)
+
+	// GetCategoryStats summarizes a category's films, rentals, and revenue.
+	GetCategoryStats(ctx context.Context, categoryID int) (*models.CategoryStats, error)
+}
+
+// ReadinessService defines the interface for deciding whether this replica
+// should receive traffic.
+type ReadinessService interface {
+	// CheckReadiness compares the database's schema version with the one this build expects.
+	CheckReadiness(ctx context.Context) (*models.Readiness, error)
+}
+
+// CustomerExportService defines the interface for exports of customers'
+// personal data.
+type CustomerExportService interface {
+	// RequestExport returns a customer's current export in format, queueing a new one if needed.
+	RequestExport(ctx context.Context, customerID int, format string) (*models.CustomerExport, error)
+
+	// GetExport retrieves one of a customer's exports.
+	GetExport(ctx context.Context, customerID, exportID int) (*models.CustomerExport, error)
+
+	// GetExportFile retrieves the file built for a ready export.
+	GetExportFile(ctx context.Context, export *models.CustomerExport) ([]byte, error)
+
+	// HandleExportJob builds the file for a queued export.
+	HandleExportJob(ctx context.Context, payload json.RawMessage) error
+}
+
+// SearchService defines the interface for title searches that found no
+// films.
+type SearchService interface {
+	// SuggestTitles records a title search that found no films and returns
+	// titles like it.
+	SuggestTitles(ctx context.Context, title string) []string
+
+	// ListMisses returns a page of the searches that found no films, most
+	// often made first.
+	ListMisses(ctx context.Context, params pagination.Params) (*pagination.Paginated[models.SearchMiss], error)
+}
+
+// SavedSearchService defines the interface for customers' saved searches
+// and the alerts sent when new films match them.
+type SavedSearchService interface {
+	// CreateSavedSearch saves a search for a customer.
+	CreateSavedSearch(
+		ctx context.Context, customerID int, searchReq models.SavedSearchRequest,
+	) (*models.SavedSearch, error)
+
+	// ListSavedSearches retrieves a customer's saved searches.
+	ListSavedSearches(ctx context.Context, customerID int) ([]models.SavedSearch, error)
+
+	// DeleteSavedSearch deletes one of a customer's saved searches.
+	DeleteSavedSearch(ctx context.Context, customerID, searchID int) error
+
+	// NotifyNewMatches tells customers about films published since their
+	// alerting searches were last checked.
+	NotifyNewMatches(ctx context.Context) error
+}
+
+// HomeService defines the interface for customers' personalized home
+// feeds.
+type HomeService interface {
+	// GetHome assembles a customer's home feed within the service's latency
+	// budget.
+	GetHome(ctx context.Context, customerID int) (*models.HomeFeed, error)
+}
+
+// GuestService defines the interface for anonymous guest sessions.
+type GuestService interface {
+	// StartSession starts a guest session and issues its token.
+	StartSession(ctx context.Context) (*models.GuestSession, error)
+
+	// GetWatchlist retrieves the watchlist of the guest whose token made the request.
+	GetWatchlist(ctx context.Context) ([]models.GuestWatchlistEntry, error)
+
+	// AddToWatchlist adds a film to the watchlist of the guest whose token made the request.
+	AddToWatchlist(ctx context.Context, filmID int) error
+
+	// RemoveFromWatchlist removes a film from the watchlist of the guest whose token made the request.
+	RemoveFromWatchlist(ctx context.Context, filmID int) error
+
+	// RecordGuestComment records that a guest posted a comment.
+	RecordGuestComment(ctx context.Context, guestID, commentID int) error
+
+	// MergeGuest moves a guest's comments and watchlist into a customer's account.
+	MergeGuest(ctx context.Context, guestID, customerID int) (*models.GuestMerge, error)
+}
+
+// AuditService defines the interface for reading the audit log.
+type AuditService interface {
+	// GetStaffActivity retrieves a page of the audited actions a staff member
+	// has taken, newest first.
+	GetStaffActivity(
+		ctx context.Context,
+		staffID int,
+		filters models.AuditFilters,
+	) (*pagination.Paginated[models.AuditEntry], error)
+}
+
+// SessionService defines the interface for staff and customer login sessions.
+type SessionService interface {
+	// StartSession starts a login session, returning an access token and a refresh token.
+	StartSession(ctx context.Context, role string, subjectID int, twoFactor bool) (*models.TokenResponse, error)
+
+	// Refresh exchanges a refresh token for new access and refresh tokens.
+	Refresh(ctx context.Context, refreshToken string) (*models.TokenResponse, error)
+
+	// Logout revokes the caller's login session.
+	Logout(ctx context.Context) error
+
+	// ListCustomerSessions retrieves a customer's live login sessions, most recently used first.
+	ListCustomerSessions(ctx context.Context, customerID int) ([]models.AuthSession, error)
+
+	// RevokeCustomerSession revokes one of a customer's login sessions.
+	RevokeCustomerSession(ctx context.Context, customerID int, sessionID int64) error
+}
+
+// TwoFactorService defines the interface for staff two-factor authentication.
+type TwoFactorService interface {
+	// EnrollTwoFactor issues a staff member a pending TOTP secret and backup codes.
+	EnrollTwoFactor(ctx context.Context, staffID int) (*models.TwoFactorEnrollment, error)
+
+	// ConfirmTwoFactor enables a pending enrollment with a code from the authenticator app.
+	ConfirmTwoFactor(ctx context.Context, staffID int, code string) error
+
+	// VerifyLogin checks a login's TOTP or backup code, reporting whether two-factor is enabled.
+	VerifyLogin(ctx context.Context, staffID int, code string) (bool, error)
 }
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+// inventoryServiceImpl implements the InventoryService interface.
+type inventoryServiceImpl struct {
+	inventoryRepo repository.InventoryRepositoryInterface
+}
+
+// NewInventoryService creates a new inventory service with the given
+// repository.
+func NewInventoryService(inventoryRepo repository.InventoryRepositoryInterface) InventoryService {
+	return &inventoryServiceImpl{inventoryRepo: inventoryRepo}
+}
+
+// AddInventory stocks new copies of a film at a store, one if the request
+// does not say how many.
+func (s *inventoryServiceImpl) AddInventory(
+	ctx context.Context,
+	filmID int,
+	inventoryReq models.InventoryRequest,
+) ([]models.InventoryCopy, error) {
+	copies := max(inventoryReq.Copies, 1)
+
+	added, err := s.inventoryRepo.AddInventory(ctx, filmID, inventoryReq.StoreID, copies)
+	if err != nil {
+		slog.Error("Failed to add inventory", "filmID", filmID, "storeID", inventoryReq.StoreID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Inventory added", "filmID", filmID, "storeID", inventoryReq.StoreID, "copies", len(added))
+	return added, nil
+}
+
+// GetInventoryCopy retrieves a copy with its status.
+func (s *inventoryServiceImpl) GetInventoryCopy(ctx context.Context, inventoryID int) (*models.InventoryCopy, error) {
+	return s.inventoryRepo.GetInventoryCopy(ctx, inventoryID)
+}
+
+// ListFilmInventory retrieves a film's copies with their status. Requests
+// scoped to a store only see that store's copies.
+func (s *inventoryServiceImpl) ListFilmInventory(
+	ctx context.Context,
+	filmID int,
+	filters models.InventoryFilters,
+) ([]models.InventoryCopy, error) {
+	if storeID, ok := tenant.StoreIDFromContext(ctx); ok {
+		filters.StoreID = storeID
+	}
+
+	return s.inventoryRepo.ListFilmInventory(ctx, filmID, filters)
+}
+
+// RetireInventory takes a copy out of stock. Copies rented out cannot be
+// retired until they are returned or considered lost.
+func (s *inventoryServiceImpl) RetireInventory(ctx context.Context, inventoryID int) (*models.InventoryCopy, error) {
+	retired, err := s.inventoryRepo.RetireInventory(ctx, inventoryID)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to retire inventory copy", "inventoryID", inventoryID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Inventory copy retired", "inventoryID", inventoryID)
+	return retired, nil
+}
+
+// TransferInventory moves a copy to another store. Copies that are retired,
+// rented out, or already at the store cannot be transferred.
+func (s *inventoryServiceImpl) TransferInventory(
+	ctx context.Context,
+	inventoryID int,
+	transferReq models.InventoryTransferRequest,
+) (*models.InventoryTransfer, error) {
+	transfer, err := s.inventoryRepo.TransferInventory(ctx, inventoryID, transferReq)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.Internal {
+			slog.Error("Failed to transfer inventory copy", "inventoryID", inventoryID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Inventory copy transferred", "inventoryID", inventoryID,
+		"fromStoreID", transfer.FromStoreID, "toStoreID", transfer.ToStoreID)
+	return transfer, nil
+}
+
+// ListInventoryTransfers retrieves a copy's transfer history.
+func (s *inventoryServiceImpl) ListInventoryTransfers(
+	ctx context.Context,
+	inventoryID int,
+) ([]models.InventoryTransfer, error) {
+	return s.inventoryRepo.ListInventoryTransfers(ctx, inventoryID)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrRedemptionTooSmall is returned when redeeming too few points to be
+// worth a cent of rental credit.
+var ErrRedemptionTooSmall = apperrors.New(apperrors.Invalid, "too few points to redeem for credit")
+
+// loyaltyServiceImpl implements the LoyaltyService interface.
+type loyaltyServiceImpl struct {
+	loyaltyRepo      repository.LoyaltyRepositoryInterface
+	pointsPerComment int
+	pointsPerDollar  int
+}
+
+// NewLoyaltyService creates a new loyalty service awarding pointsPerComment
+// points for each comment and redeeming pointsPerDollar points for each
+// dollar of rental credit.
+func NewLoyaltyService(
+	loyaltyRepo repository.LoyaltyRepositoryInterface,
+	pointsPerComment, pointsPerDollar int,
+) LoyaltyService {
+	return &loyaltyServiceImpl{
+		loyaltyRepo:      loyaltyRepo,
+		pointsPerComment: pointsPerComment,
+		pointsPerDollar:  pointsPerDollar,
+	}
+}
+
+// GetBalance retrieves a customer's points and rental credit.
+func (s *loyaltyServiceImpl) GetBalance(ctx context.Context, customerID int) (*models.LoyaltyBalance, error) {
+	return s.loyaltyRepo.GetBalance(ctx, customerID)
+}
+
+// GetHistory retrieves a page of a customer's ledger entries.
+func (s *loyaltyServiceImpl) GetHistory(
+	ctx context.Context,
+	customerID int,
+	filters models.LoyaltyHistoryFilters,
+) (*pagination.Paginated[models.LoyaltyEntry], error) {
+	return s.loyaltyRepo.GetHistory(ctx, customerID, filters)
+}
+
+// RedeemPoints exchanges a customer's points for rental credit, which is
+// taken off their next checkouts. Credit is rounded down to the cent.
+func (s *loyaltyServiceImpl) RedeemPoints(
+	ctx context.Context,
+	customerID int,
+	redeemReq models.RedeemPointsRequest,
+) (*models.LoyaltyEntry, error) {
+	if s.pointsPerDollar <= 0 {
+		return nil, ErrRedemptionTooSmall
+	}
+	creditCents := redeemReq.Points * 100 / s.pointsPerDollar
+	if creditCents == 0 {
+		return nil, ErrRedemptionTooSmall
+	}
+
+	entry, err := s.loyaltyRepo.RedeemPoints(ctx, customerID, redeemReq.Points, float64(creditCents)/100)
+	if err != nil {
+		if !errors.Is(err, repository.ErrInsufficientPoints) && !errors.Is(err, repository.ErrCustomerNotFound) {
+			slog.Error("Failed to redeem loyalty points", "customerID", customerID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Loyalty points redeemed", "customerID", customerID, "points", redeemReq.Points, "credit", entry.Credit)
+	return entry, nil
+}
+
+// AwardCommentPoints credits a customer with the points for a comment they
+// posted.
+func (s *loyaltyServiceImpl) AwardCommentPoints(ctx context.Context, customerID, commentID int) error {
+	if s.pointsPerComment <= 0 {
+		return nil
+	}
+	return s.loyaltyRepo.AwardPoints(ctx, customerID, s.pointsPerComment, models.LoyaltyReasonComment, commentID)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrInvalidPreference is returned for updates naming unknown event types or
+// channels.
+var ErrInvalidPreference = apperrors.New(apperrors.Invalid, "invalid notification preference")
+
+// notificationPreferenceServiceImpl implements the NotificationPreferenceService interface.
+type notificationPreferenceServiceImpl struct {
+	prefRepo repository.NotificationPreferenceRepositoryInterface
+}
+
+// NewNotificationPreferenceService creates a new notification preference
+// service with the given repository.
+func NewNotificationPreferenceService(
+	prefRepo repository.NotificationPreferenceRepositoryInterface,
+) NotificationPreferenceService {
+	return &notificationPreferenceServiceImpl{prefRepo: prefRepo}
+}
+
+// GetPreferences returns a customer's effective preferences, including defaults.
+func (s *notificationPreferenceServiceImpl) GetPreferences(
+	ctx context.Context,
+	customerID int,
+) (*models.NotificationPreferencesResponse, error) {
+	stored, err := s.prefRepo.GetPreferences(ctx, customerID)
+	if err != nil {
+		slog.Error("Failed to retrieve notification preferences", "customerID", customerID, "error", err)
+		return nil, err
+	}
+
+	return &models.NotificationPreferencesResponse{
+		CustomerID:  customerID,
+		Preferences: effectivePreferences(stored),
+	}, nil
+}
+
+// UpdatePreferences stores some or all of a customer's preferences.
+func (s *notificationPreferenceServiceImpl) UpdatePreferences(
+	ctx context.Context,
+	customerID int,
+	prefs models.NotificationPreferences,
+) (*models.NotificationPreferencesResponse, error) {
+	for event, channels := range prefs {
+		for channel := range channels {
+			if err := notifications.ValidatePreference(event, channel); err != nil {
+				slog.Warn("Invalid notification preference provided", "customerID", customerID, "error", err)
+				return nil, fmt.Errorf("%w: %w", ErrInvalidPreference, err)
+			}
+		}
+	}
+
+	if err := s.prefRepo.SetPreferences(ctx, customerID, prefs); err != nil {
+		slog.Error("Failed to store notification preferences", "customerID", customerID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Successfully updated notification preferences", "customerID", customerID)
+	return s.GetPreferences(ctx, customerID)
+}
+
+// Allowed reports whether a customer accepts event notifications on channel.
+func (s *notificationPreferenceServiceImpl) Allowed(
+	ctx context.Context,
+	customerID int,
+	event, channel string,
+) (bool, error) {
+	stored, err := s.prefRepo.GetPreferences(ctx, customerID)
+	if err != nil {
+		return false, err
+	}
+	if enabled, ok := stored[event][channel]; ok {
+		return enabled, nil
+	}
+	return notifications.DefaultEnabled(channel), nil
+}
+
+// effectivePreferences fills in defaults for every event and channel the
+// customer has not set.
+func effectivePreferences(stored models.NotificationPreferences) models.NotificationPreferences {
+	prefs := models.NotificationPreferences{}
+	for _, event := range notifications.Events {
+		prefs[event] = map[string]bool{}
+		for _, channel := range notifications.Channels {
+			enabled, ok := stored[event][channel]
+			if !ok {
+				enabled = notifications.DefaultEnabled(channel)
+			}
+			prefs[event][channel] = enabled
+		}
+	}
+	return prefs
+}
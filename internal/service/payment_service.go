@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/payments"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrNothingToPay is returned when starting payment for a checkout whose
+// total is zero, such as one fully covered by a coupon.
+var ErrNothingToPay = apperrors.New(apperrors.Conflict, "checkout has nothing to pay")
+
+// ErrCheckoutPaid is returned when starting payment for a checkout that has
+// already been paid.
+var ErrCheckoutPaid = apperrors.New(apperrors.Conflict, "checkout already paid")
+
+// ErrRefundFailed is returned when the payment provider declines or fails a
+// refund, including when replaying a request whose refund failed.
+var ErrRefundFailed = errors.New("payment provider refund failed")
+
+// ErrRefundPending is returned when the payment provider's answer to a
+// refund is unknown, such as after a timeout. The refund stays pending, and
+// repeating the request with the same idempotency key finishes it.
+var ErrRefundPending = errors.New("payment provider refund outcome unknown")
+
+// paymentServiceImpl implements the PaymentService interface.
+type paymentServiceImpl struct {
+	paymentRepo  repository.PaymentRepositoryInterface
+	cartRepo     repository.CartRepositoryInterface
+	giftCardRepo repository.GiftCardRepositoryInterface
+	provider     payments.Provider
+	currency     string
+}
+
+// PaymentServiceOption configures optional payment service behavior.
+type PaymentServiceOption func(*paymentServiceImpl)
+
+// WithGiftCardPayments applies provider callbacks for gift card purchases,
+// activating each card once its payment succeeds.
+func WithGiftCardPayments(giftCardRepo repository.GiftCardRepositoryInterface) PaymentServiceOption {
+	return func(s *paymentServiceImpl) {
+		s.giftCardRepo = giftCardRepo
+	}
+}
+
+// NewPaymentService creates a new payment service taking payments in
+// currency through provider.
+func NewPaymentService(
+	paymentRepo repository.PaymentRepositoryInterface,
+	cartRepo repository.CartRepositoryInterface,
+	provider payments.Provider,
+	currency string,
+	opts ...PaymentServiceOption,
+) PaymentService {
+	s := &paymentServiceImpl{
+		paymentRepo: paymentRepo,
+		cartRepo:    cartRepo,
+		provider:    provider,
+		currency:    currency,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// StartPayment starts collecting a customer's checkout total with the
+// payment provider. A checkout is paid through a single provider payment,
+// so starting again returns the payment already started.
+func (s *paymentServiceImpl) StartPayment(
+	ctx context.Context,
+	customerID, checkoutID int,
+) (*models.Payment, error) {
+	checkout, err := s.cartRepo.GetCheckout(ctx, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	if checkout.CustomerID != customerID {
+		return nil, repository.ErrCheckoutNotFound
+	}
+
+	payment, err := s.paymentRepo.GetCheckoutPayment(ctx, checkoutID)
+	switch {
+	case err == nil && payment.Status == models.PaymentStatusSucceeded:
+		return nil, ErrCheckoutPaid
+	case err == nil:
+		return payment, nil
+	case !errors.Is(err, repository.ErrPaymentNotFound):
+		return nil, err
+	}
+	if checkout.Total <= 0 {
+		return nil, ErrNothingToPay
+	}
+
+	intent, err := s.provider.CreateIntent(ctx, payments.IntentRequest{
+		Amount:         int64(math.Round(checkout.Total * 100)),
+		Currency:       s.currency,
+		IdempotencyKey: "checkout-" + strconv.Itoa(checkoutID),
+		Metadata: map[string]string{
+			"checkout_id": strconv.Itoa(checkoutID),
+			"customer_id": strconv.Itoa(customerID),
+		},
+	})
+	if err != nil {
+		slog.Error("Failed to create payment intent", "checkoutID", checkoutID, "error", err)
+		return nil, err
+	}
+
+	payment, err = s.paymentRepo.CreatePayment(ctx, models.Payment{
+		CheckoutID:        checkoutID,
+		Provider:          s.provider.Name(),
+		ProviderPaymentID: intent.ID,
+		ClientSecret:      intent.ClientSecret,
+		Amount:            checkout.Total,
+		Currency:          s.currency,
+	})
+	if err != nil {
+		slog.Error("Failed to record payment", "checkoutID", checkoutID, "intentID", intent.ID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Payment started", "checkoutID", checkoutID, "provider", payment.Provider, "amount", payment.Amount)
+	return payment, nil
+}
+
+// HandleWebhook verifies a payment provider callback and applies it. A
+// successful payment records each rental's share of the checkout total, or
+// activates the gift card it bought; callbacks for other events, or for
+// payments this API did not start, are acknowledged and ignored.
+func (s *paymentServiceImpl) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	event, err := s.provider.ParseWebhook(payload, header)
+	if err != nil {
+		slog.Warn("Rejected payment webhook", "provider", s.provider.Name(), "error", err)
+		return err
+	}
+	if event.Type != payments.EventPaymentSucceeded && event.Type != payments.EventPaymentFailed {
+		return nil
+	}
+
+	payment, err := s.paymentRepo.GetProviderPayment(ctx, s.provider.Name(), event.IntentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentNotFound) && s.giftCardRepo != nil {
+			return s.applyGiftCardEvent(ctx, event)
+		}
+		if errors.Is(err, repository.ErrPaymentNotFound) {
+			slog.Warn("Payment webhook for unknown payment", "eventID", event.ID, "intentID", event.IntentID)
+			return nil
+		}
+		return err
+	}
+
+	if event.Type == payments.EventPaymentFailed {
+		if err = s.paymentRepo.FailPayment(ctx, payment.ID); err != nil {
+			slog.Error("Failed to mark payment failed", "paymentID", payment.ID, "error", err)
+			return err
+		}
+		slog.Info("Payment failed", "paymentID", payment.ID, "checkoutID", payment.CheckoutID)
+		return nil
+	}
+
+	checkout, err := s.cartRepo.GetCheckout(ctx, payment.CheckoutID)
+	if err != nil {
+		return err
+	}
+	if _, err = s.paymentRepo.CompletePayment(ctx, payment.ID, splitAmount(checkout.Total, checkout.Rentals)); err != nil {
+		slog.Error("Failed to complete payment", "paymentID", payment.ID, "error", err)
+		return err
+	}
+
+	slog.Info("Payment succeeded", "paymentID", payment.ID, "checkoutID", payment.CheckoutID)
+	return nil
+}
+
+// applyGiftCardEvent applies a provider callback about the payment for a
+// gift card purchase.
+func (s *paymentServiceImpl) applyGiftCardEvent(ctx context.Context, event *payments.Event) error {
+	card, err := s.giftCardRepo.GetProviderGiftCard(ctx, s.provider.Name(), event.IntentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrGiftCardNotFound) {
+			slog.Warn("Payment webhook for unknown payment", "eventID", event.ID, "intentID", event.IntentID)
+			return nil
+		}
+		return err
+	}
+
+	if event.Type == payments.EventPaymentFailed {
+		if err = s.giftCardRepo.FailGiftCard(ctx, card.ID); err != nil {
+			slog.Error("Failed to mark gift card failed", "giftCardID", card.ID, "error", err)
+			return err
+		}
+		slog.Info("Gift card payment failed", "giftCardID", card.ID)
+		return nil
+	}
+
+	if _, err = s.giftCardRepo.ActivateGiftCard(ctx, card.ID); err != nil {
+		slog.Error("Failed to activate gift card", "giftCardID", card.ID, "error", err)
+		return err
+	}
+
+	slog.Info("Gift card activated", "giftCardID", card.ID, "amount", card.Amount)
+	return nil
+}
+
+// RefundPayment returns part or all of a succeeded payment through its
+// provider and records each rental's share of the refund. Requests are made
+// idempotent by idempotencyKey: repeating one returns the same refund, and
+// finishes it should an earlier attempt have stopped before the provider
+// answered. A refund is only marked failed, releasing its amount for another
+// refund, when the provider declines it; if the provider's answer is
+// unknown it stays pending, as the provider may have made it.
+func (s *paymentServiceImpl) RefundPayment(
+	ctx context.Context,
+	paymentID int,
+	idempotencyKey string,
+	refundReq models.RefundRequest,
+) (*models.Refund, error) {
+	payment, err := s.paymentRepo.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := s.paymentRepo.CreateRefund(ctx, paymentID, idempotencyKey, refundReq)
+	if err != nil {
+		return nil, err
+	}
+	switch refund.Status {
+	case models.PaymentStatusPending:
+	case models.PaymentStatusFailed:
+		return nil, fmt.Errorf("%w: refund %d failed", ErrRefundFailed, refund.ID)
+	default:
+		return refund, nil
+	}
+
+	providerRefund, err := s.provider.CreateRefund(ctx, payments.RefundRequest{
+		IntentID:       payment.ProviderPaymentID,
+		Amount:         int64(math.Round(refund.Amount * 100)),
+		IdempotencyKey: "refund-" + strconv.Itoa(refund.ID),
+	})
+	if err == nil && (providerRefund.Status == "failed" || providerRefund.Status == "canceled") {
+		err = fmt.Errorf("%w: refund %s is %s", payments.ErrDeclined, providerRefund.ID, providerRefund.Status)
+	}
+	if err != nil {
+		if !errors.Is(err, payments.ErrDeclined) {
+			slog.Error("Payment provider refund outcome unknown", "paymentID", paymentID, "refundID", refund.ID,
+				"error", err)
+			return nil, fmt.Errorf("%w: %w", ErrRefundPending, err)
+		}
+		slog.Error("Payment provider refund failed", "paymentID", paymentID, "refundID", refund.ID, "error", err)
+		if failErr := s.paymentRepo.FailRefund(ctx, refund.ID); failErr != nil {
+			slog.Error("Failed to mark refund failed", "refundID", refund.ID, "error", failErr)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrRefundFailed, err)
+	}
+
+	checkout, err := s.cartRepo.GetCheckout(ctx, payment.CheckoutID)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := s.paymentRepo.CompleteRefund(ctx, refund.ID, providerRefund.ID,
+		splitAmount(refund.Amount, checkout.Rentals))
+	if err != nil {
+		slog.Error("Failed to complete refund", "refundID", refund.ID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Payment refunded", "paymentID", paymentID, "refundID", completed.ID, "amount", completed.Amount)
+	return completed, nil
+}
+
+// splitAmount divides an amount of a checkout across its rentals in
+// proportion to their rates, in whole cents, leaving any rounding remainder
+// on the last rental so the shares add up to the amount.
+func splitAmount(amount float64, rentals []models.CheckoutRental) []models.RentalPayment {
+	totalCents := int64(math.Round(amount * 100))
+	var rateCents int64
+	for _, rental := range rentals {
+		rateCents += int64(math.Round(rental.Rate * 100))
+	}
+
+	shares := make([]models.RentalPayment, 0, len(rentals))
+	var allocated int64
+	for i, rental := range rentals {
+		share := totalCents - allocated
+		if i < len(rentals)-1 && rateCents > 0 {
+			share = totalCents * int64(math.Round(rental.Rate*100)) / rateCents
+		}
+		allocated += share
+		shares = append(shares, models.RentalPayment{RentalID: rental.RentalID, Amount: float64(share) / 100})
+	}
+	return shares
+}
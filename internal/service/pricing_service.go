@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pricing"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+// ErrInvalidPricingRule is returned for a pricing rule whose fields
+// contradict each other.
+var ErrInvalidPricingRule = apperrors.New(apperrors.Invalid, "invalid pricing rule")
+
+// pricingServiceImpl implements the PricingService interface.
+type pricingServiceImpl struct {
+	pricingRepo repository.PricingRepositoryInterface
+}
+
+// NewPricingService creates a new pricing service with the given repository.
+func NewPricingService(pricingRepo repository.PricingRepositoryInterface) PricingService {
+	return &pricingServiceImpl{pricingRepo: pricingRepo}
+}
+
+// CreateRule stores a new pricing rule. Bundles must charge for fewer films
+// than they include, and a rule must end after it starts.
+func (s *pricingServiceImpl) CreateRule(
+	ctx context.Context,
+	ruleReq models.PricingRuleRequest,
+) (*models.PricingRule, error) {
+	if ruleReq.Kind == models.PricingRuleBundle && ruleReq.BundlePaid != nil && ruleReq.BundleQuantity != nil &&
+		*ruleReq.BundlePaid >= *ruleReq.BundleQuantity {
+		return nil, fmt.Errorf("%w: bundle_paid must be less than bundle_quantity", ErrInvalidPricingRule)
+	}
+	if ruleReq.StartsAt != nil && ruleReq.EndsAt != nil && !ruleReq.StartsAt.Before(*ruleReq.EndsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPricingRule)
+	}
+	if ruleReq.Kind == models.PricingRuleBundle {
+		ruleReq.PercentOff = nil
+	} else {
+		ruleReq.BundleQuantity, ruleReq.BundlePaid = nil, nil
+	}
+
+	rule, err := s.pricingRepo.CreatePricingRule(ctx, ruleReq)
+	if err != nil {
+		if !errors.Is(err, repository.ErrInvalidReference) {
+			slog.Error("Failed to create pricing rule", "name", ruleReq.Name, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Pricing rule created", "ruleID", rule.ID, "kind", rule.Kind)
+	return rule, nil
+}
+
+// ListRules retrieves every pricing rule, in the order they apply.
+func (s *pricingServiceImpl) ListRules(ctx context.Context) ([]models.PricingRule, error) {
+	return s.pricingRepo.ListPricingRules(ctx)
+}
+
+// DeleteRule deletes a pricing rule.
+func (s *pricingServiceImpl) DeleteRule(ctx context.Context, ruleID int) error {
+	if err := s.pricingRepo.DeletePricingRule(ctx, ruleID); err != nil {
+		if !errors.Is(err, repository.ErrPricingRuleNotFound) {
+			slog.Error("Failed to delete pricing rule", "ruleID", ruleID, "error", err)
+		}
+		return err
+	}
+
+	slog.Info("Pricing rule deleted", "ruleID", ruleID)
+	return nil
+}
+
+// GetFilmPrice prices a film at the given time. Store-specific rules apply
+// for the customer's store when a customer is given, otherwise for the store
+// the request is scoped to; with neither, only rules for every store apply.
+func (s *pricingServiceImpl) GetFilmPrice(
+	ctx context.Context,
+	filmID int,
+	customerID *int,
+	at time.Time,
+) (*models.FilmPrice, error) {
+	film, err := s.pricingRepo.GetFilmPricing(ctx, filmID)
+	if err != nil {
+		return nil, err
+	}
+
+	storeID, _ := tenant.StoreIDFromContext(ctx)
+	if customerID != nil {
+		if storeID, err = s.pricingRepo.GetCustomerStoreID(ctx, *customerID); err != nil {
+			return nil, err
+		}
+	}
+
+	rules, err := s.pricingRepo.ListPricingRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pricing.Quote(*film, storeID, at, rules), nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// readinessServiceImpl implements the ReadinessService interface.
+type readinessServiceImpl struct {
+	schemaRepo      repository.SchemaRepositoryInterface
+	expectedVersion int64
+}
+
+// NewReadinessService creates a new readiness service for a build whose
+// newest migration is expectedVersion.
+func NewReadinessService(schemaRepo repository.SchemaRepositoryInterface, expectedVersion int64) ReadinessService {
+	return &readinessServiceImpl{schemaRepo: schemaRepo, expectedVersion: expectedVersion}
+}
+
+// CheckReadiness reports the replica ready when the database's schema
+// version is the expected one. A newer schema means this build is being
+// replaced, an older one that migrations have not run yet.
+func (s *readinessServiceImpl) CheckReadiness(ctx context.Context) (*models.Readiness, error) {
+	version, err := s.schemaRepo.GetSchemaVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema version: %w", err)
+	}
+
+	readiness := &models.Readiness{SchemaVersion: version, ExpectedVersion: s.expectedVersion}
+	switch {
+	case version > s.expectedVersion:
+		readiness.Reason = "database schema is newer than this build"
+	case version < s.expectedVersion:
+		readiness.Reason = "database schema is behind this build"
+	default:
+		readiness.Ready = true
+	}
+	return readiness, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// receiptServiceImpl implements the ReceiptService interface.
+type receiptServiceImpl struct {
+	receiptRepo repository.ReceiptRepositoryInterface
+	signer      *auth.URLSigner
+}
+
+// ReceiptServiceOption configures optional receipt service behavior.
+type ReceiptServiceOption func(*receiptServiceImpl)
+
+// WithSignedReceiptURLs gives invoices a signed receipt URL from signer,
+// which fetches the receipt without a bearer token.
+func WithSignedReceiptURLs(signer *auth.URLSigner) ReceiptServiceOption {
+	return func(s *receiptServiceImpl) {
+		s.signer = signer
+	}
+}
+
+// NewReceiptService creates a new receipt service with the given repository.
+func NewReceiptService(receiptRepo repository.ReceiptRepositoryInterface, opts ...ReceiptServiceOption) ReceiptService {
+	s := &receiptServiceImpl{receiptRepo: receiptRepo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetReceipt retrieves the receipt for a rental, totalling its payments. A
+// customer may only see receipts for their own rentals; the rentals of other
+// customers are reported as not found.
+func (s *receiptServiceImpl) GetReceipt(ctx context.Context, rentalID int) (*models.Receipt, error) {
+	receipt, err := s.receiptRepo.GetRentalReceipt(ctx, rentalID)
+	if err != nil {
+		return nil, err
+	}
+	if claims, ok := auth.ClaimsFromContext(ctx); ok && claims.Role == auth.RoleCustomer &&
+		claims.Subject != receipt.CustomerID {
+		return nil, repository.ErrRentalNotFound
+	}
+
+	var paidCents int64
+	for _, payment := range receipt.Payments {
+		paidCents += int64(math.Round(payment.Amount * 100))
+	}
+	receipt.AmountPaid = float64(paidCents) / 100
+	receipt.IssuedAt = time.Now().UTC()
+	return receipt, nil
+}
+
+// GetCustomerInvoices retrieves a page of a customer's invoices, each
+// linking to its rental's receipt, and to a signed receipt URL when URL
+// signing is enabled.
+func (s *receiptServiceImpl) GetCustomerInvoices(
+	ctx context.Context,
+	customerID int,
+	filters models.InvoiceFilters,
+) (*pagination.Paginated[models.Invoice], error) {
+	invoices, err := s.receiptRepo.GetCustomerInvoices(ctx, customerID, filters)
+	if err != nil {
+		return nil, err
+	}
+	for i := range invoices.Items {
+		invoice := &invoices.Items[i]
+		invoice.ReceiptURL = "/api/v1/rentals/" + strconv.Itoa(invoice.RentalID) + "/receipt"
+		if s.signer == nil {
+			continue
+		}
+		if invoice.SignedReceiptURL, _, err = s.signer.Sign(invoice.ReceiptURL); err != nil {
+			slog.Error("Failed to sign receipt URL", "rentalID", invoice.RentalID, "error", err)
+		}
+	}
+	return invoices, nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+// trendingFilmCount is the number of films kept in the trending ranking.
+const trendingFilmCount = 10
+
+// DueNotifier queues a reminder that a rental is due back.
+type DueNotifier interface {
+	NotifyRentalDue(due notifications.RentalDue) error
+}
+
+// AvailabilityDispatcher tells the customers waiting for a film that a copy
+// has come back.
+type AvailabilityDispatcher interface {
+	NotifyAvailable(ctx context.Context, filmID int) error
+}
+
+// rentalServiceImpl implements the RentalService interface.
+type rentalServiceImpl struct {
+	rentalRepo     repository.RentalRepositoryInterface
+	notifier       DueNotifier
+	availability   AvailabilityDispatcher
+	reminderWindow time.Duration
+	trendingWindow time.Duration
+	lateFeePerDay  float64
+}
+
+// RentalServiceOption configures optional rental service behavior.
+type RentalServiceOption func(*rentalServiceImpl)
+
+// WithAvailabilityDispatcher notifies the customers waiting for a film when
+// a rental of it is returned.
+func WithAvailabilityDispatcher(dispatcher AvailabilityDispatcher) RentalServiceOption {
+	return func(s *rentalServiceImpl) {
+		s.availability = dispatcher
+	}
+}
+
+// NewRentalService creates a new rental service. Reminders go out for
+// rentals due within reminderWindow, trending films are ranked by rentals
+// within the last trendingWindow, and overdue rentals accrue lateFeePerDay.
+func NewRentalService(
+	rentalRepo repository.RentalRepositoryInterface,
+	notifier DueNotifier,
+	reminderWindow, trendingWindow time.Duration,
+	lateFeePerDay float64,
+	opts ...RentalServiceOption,
+) RentalService {
+	s := &rentalServiceImpl{
+		rentalRepo:     rentalRepo,
+		notifier:       notifier,
+		reminderWindow: reminderWindow,
+		trendingWindow: trendingWindow,
+		lateFeePerDay:  lateFeePerDay,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetTrendingFilms retrieves the most recently computed trending films.
+func (s *rentalServiceImpl) GetTrendingFilms(ctx context.Context) ([]models.TrendingFilm, error) {
+	return s.rentalRepo.GetTrendingFilms(ctx)
+}
+
+// GetFilmRentals retrieves a page of a film's rental history. Requests
+// scoped to a store only see that store's rentals.
+func (s *rentalServiceImpl) GetFilmRentals(
+	ctx context.Context,
+	filmID int,
+	filters models.RentalHistoryFilters,
+) (*pagination.Paginated[models.RentalEvent], error) {
+	if storeID, ok := tenant.StoreIDFromContext(ctx); ok {
+		filters.StoreID = storeID
+	}
+
+	return s.rentalRepo.GetFilmRentals(ctx, filmID, filters)
+}
+
+// GetCustomerRentals retrieves a page of a customer's rental history.
+// Requests scoped to a store only see rentals from that store.
+func (s *rentalServiceImpl) GetCustomerRentals(
+	ctx context.Context,
+	customerID int,
+	filters models.RentalHistoryFilters,
+) (*pagination.Paginated[models.RentalEvent], error) {
+	if storeID, ok := tenant.StoreIDFromContext(ctx); ok {
+		filters.StoreID = storeID
+	}
+
+	return s.rentalRepo.GetCustomerRentals(ctx, customerID, filters)
+}
+
+// GetOverdueReport aggregates overdue rentals by film with the fees they
+// have accrued. Requests scoped to a store only see that store's rentals.
+func (s *rentalServiceImpl) GetOverdueReport(
+	ctx context.Context,
+	filters models.OverdueReportFilters,
+) (*models.OverdueReport, error) {
+	if storeID, ok := tenant.StoreIDFromContext(ctx); ok {
+		filters.StoreID = storeID
+	}
+	filters.LateFeePerDay = s.lateFeePerDay
+
+	films, err := s.rentalRepo.GetOverdueFilms(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.OverdueReport{
+		GeneratedAt:   time.Now().UTC(),
+		LateFeePerDay: s.lateFeePerDay,
+		Films:         films,
+	}
+	for _, film := range films {
+		report.OverdueRentals += film.OverdueRentals
+		report.AccruedFees += film.AccruedFees
+	}
+	report.AccruedFees = math.Round(report.AccruedFees*100) / 100
+
+	return report, nil
+}
+
+// ReturnRental records that a rental has come back and notifies the
+// customers waiting for its film. The return stands even if queueing their
+// notices fails, which is logged.
+func (s *rentalServiceImpl) ReturnRental(ctx context.Context, rentalID int) (*models.RentalEvent, error) {
+	rental, err := s.rentalRepo.ReturnRental(ctx, rentalID)
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Rental returned", "rental_id", rentalID, "film_id", rental.FilmID)
+
+	if s.availability != nil {
+		if notifyErr := s.availability.NotifyAvailable(ctx, rental.FilmID); notifyErr != nil {
+			slog.WarnContext(ctx, "Failed to notify customers waiting for film",
+				"film_id", rental.FilmID, "error", notifyErr)
+		}
+	}
+
+	return rental, nil
+}
+
+// MarkOverdueRentals flags open rentals that are past their due date.
+func (s *rentalServiceImpl) MarkOverdueRentals(ctx context.Context) error {
+	marked, err := s.rentalRepo.MarkOverdueRentals(ctx)
+	if err != nil {
+		return err
+	}
+	slog.Info("Overdue rentals marked", "count", marked)
+	return nil
+}
+
+// SendDueReminders queues a reminder for each rental due soon. Rentals are
+// claimed before queueing, so a failed send is logged rather than retried.
+func (s *rentalServiceImpl) SendDueReminders(ctx context.Context) error {
+	reminders, err := s.rentalRepo.ClaimDueReminders(ctx, s.reminderWindow)
+	if err != nil {
+		return err
+	}
+
+	for _, reminder := range reminders {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("error queueing rental reminders: %w", ctxErr)
+		}
+		notifyErr := s.notifier.NotifyRentalDue(notifications.RentalDue{
+			RecipientID:    reminder.CustomerID,
+			RecipientEmail: reminder.CustomerEmail,
+			RecipientName:  reminder.CustomerName,
+			FilmTitle:      reminder.FilmTitle,
+			DueAt:          reminder.DueAt,
+		})
+		if notifyErr != nil {
+			slog.Warn("Failed to queue rental reminder", "rental_id", reminder.RentalID, "error", notifyErr)
+		}
+	}
+
+	slog.Info("Rental reminders queued", "count", len(reminders))
+	return nil
+}
+
+// RefreshTrendingFilms recomputes the trending film ranking.
+func (s *rentalServiceImpl) RefreshTrendingFilms(ctx context.Context) error {
+	return s.rentalRepo.RefreshTrendingFilms(ctx, time.Now().Add(-s.trendingWindow), trendingFilmCount)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// savedSearchAlertFilms is the most films one saved search alert names.
+const savedSearchAlertFilms = 10
+
+// SavedSearchNotifier queues a notice of new films matching a customer's
+// saved search.
+type SavedSearchNotifier interface {
+	NotifySavedSearchMatch(match notifications.SavedSearchMatch) error
+}
+
+// savedSearchServiceImpl implements the SavedSearchService interface.
+type savedSearchServiceImpl struct {
+	searchRepo repository.SavedSearchRepositoryInterface
+	filmRepo   repository.FilmPublishedRepositoryInterface
+	notifier   SavedSearchNotifier
+}
+
+// NewSavedSearchService creates a new saved search service finding new
+// films in filmRepo and sending alerts through notifier.
+func NewSavedSearchService(
+	searchRepo repository.SavedSearchRepositoryInterface,
+	filmRepo repository.FilmPublishedRepositoryInterface,
+	notifier SavedSearchNotifier,
+) SavedSearchService {
+	return &savedSearchServiceImpl{searchRepo: searchRepo, filmRepo: filmRepo, notifier: notifier}
+}
+
+// CreateSavedSearch saves a search for a customer. Only films published
+// afterwards are alerted on.
+func (s *savedSearchServiceImpl) CreateSavedSearch(
+	ctx context.Context,
+	customerID int,
+	searchReq models.SavedSearchRequest,
+) (*models.SavedSearch, error) {
+	searchReq.Filters.Tags = normalizeTags(searchReq.Filters.Tags)
+
+	search, err := s.searchRepo.CreateSavedSearch(ctx, customerID, searchReq)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCustomerNotFound) && !errors.Is(err, repository.ErrSavedSearchLimit) {
+			slog.Error("Failed to save search", "customerID", customerID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Info("Search saved", "customerID", customerID, "searchID", search.ID, "notify", search.Notify)
+	return search, nil
+}
+
+// ListSavedSearches retrieves a customer's saved searches, newest first.
+func (s *savedSearchServiceImpl) ListSavedSearches(ctx context.Context, customerID int) ([]models.SavedSearch, error) {
+	return s.searchRepo.ListSavedSearches(ctx, customerID)
+}
+
+// DeleteSavedSearch deletes one of a customer's saved searches.
+func (s *savedSearchServiceImpl) DeleteSavedSearch(ctx context.Context, customerID, searchID int) error {
+	if err := s.searchRepo.DeleteSavedSearch(ctx, customerID, searchID); err != nil {
+		if !errors.Is(err, repository.ErrSavedSearchNotFound) {
+			slog.Error("Failed to delete saved search", "searchID", searchID, "error", err)
+		}
+		return err
+	}
+
+	slog.Info("Saved search deleted", "customerID", customerID, "searchID", searchID)
+	return nil
+}
+
+// NotifyNewMatches tells customers about the films published since each of
+// their alerting searches was last checked. A search is marked checked
+// before its alert is queued, so a failed send is logged rather than
+// repeated; a search that cannot be checked is tried again on the next run.
+func (s *savedSearchServiceImpl) NotifyNewMatches(ctx context.Context) error {
+	alerts, checkedAt, err := s.searchRepo.ListAlertingSearches(ctx)
+	if err != nil {
+		return err
+	}
+
+	queued := 0
+	for _, alert := range alerts {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		films, filmErr := s.filmRepo.ListPublishedBetween(ctx, alert.Filters.FilmFilters(), alert.LastCheckedAt, checkedAt,
+			savedSearchAlertFilms)
+		if filmErr != nil {
+			slog.Error("Failed to check saved search", "searchID", alert.ID, "error", filmErr)
+			continue
+		}
+		if markErr := s.searchRepo.MarkSearchChecked(ctx, alert.ID, checkedAt); markErr != nil {
+			slog.Error("Failed to mark saved search checked", "searchID", alert.ID, "error", markErr)
+			continue
+		}
+		if len(films) == 0 {
+			continue
+		}
+
+		titles := make([]string, len(films))
+		for i, film := range films {
+			titles[i] = film.Title
+		}
+		notifyErr := s.notifier.NotifySavedSearchMatch(notifications.SavedSearchMatch{
+			RecipientID:    alert.CustomerID,
+			RecipientEmail: alert.CustomerEmail,
+			RecipientName:  alert.CustomerName,
+			SearchName:     alert.Name,
+			FilmTitles:     titles,
+		})
+		if notifyErr != nil {
+			slog.Warn("Failed to queue saved search alert", "searchID", alert.ID, "error", notifyErr)
+			continue
+		}
+		queued++
+	}
+
+	if queued > 0 {
+		slog.Info("Saved search alerts queued", "count", queued)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+const (
+	// maxTitleSuggestions is the most titles suggested for a search.
+	maxTitleSuggestions = 5
+	// maxSearchTermLength is the longest search term recorded; longer terms
+	// are cut short.
+	maxSearchTermLength = 100
+)
+
+// searchServiceImpl implements the SearchService interface.
+type searchServiceImpl struct {
+	searchRepo repository.SearchRepositoryInterface
+}
+
+// NewSearchService creates a new search service.
+func NewSearchService(searchRepo repository.SearchRepositoryInterface) SearchService {
+	return &searchServiceImpl{searchRepo: searchRepo}
+}
+
+// SuggestTitles records a title search that found no films, counting terms
+// case-insensitively, and returns the titles of published films most like
+// it. Suggestions help but are not needed to answer the search, so failures
+// are logged and leave them out.
+func (s *searchServiceImpl) SuggestTitles(ctx context.Context, title string) []string {
+	term := normalizeSearchTerm(title)
+	if term == "" {
+		return nil
+	}
+
+	if err := s.searchRepo.RecordMiss(ctx, term); err != nil {
+		slog.Warn("Failed to record search miss", "term", term, "error", err)
+	}
+
+	suggestions, err := s.searchRepo.SuggestTitles(ctx, term, maxTitleSuggestions)
+	if err != nil {
+		slog.Warn("Failed to suggest titles", "term", term, "error", err)
+		return nil
+	}
+	return suggestions
+}
+
+// ListMisses returns a page of the searches that found no films, most often
+// made first.
+func (s *searchServiceImpl) ListMisses(
+	ctx context.Context,
+	params pagination.Params,
+) (*pagination.Paginated[models.SearchMiss], error) {
+	misses, err := s.searchRepo.ListMisses(ctx, params)
+	if err != nil {
+		slog.Error("Failed to list search misses", "error", err)
+		return nil, err
+	}
+	return misses, nil
+}
+
+// normalizeSearchTerm lower-cases and trims a search term, cutting it to
+// maxSearchTermLength characters.
+func normalizeSearchTerm(title string) string {
+	term := strings.ToLower(strings.TrimSpace(title))
+	if utf8.RuneCountInString(term) > maxSearchTermLength {
+		term = strings.TrimSpace(string([]rune(term)[:maxSearchTermLength]))
+	}
+	return term
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrInvalidRefreshToken is returned when refreshing with a refresh token
+// that is unknown, expired, revoked, or already used.
+var ErrInvalidRefreshToken = apperrors.New(apperrors.Invalid, "invalid refresh token")
+
+// ErrSessionTokenRequired is returned when logging out with a token that was
+// not issued for a login session.
+var ErrSessionTokenRequired = apperrors.New(apperrors.Invalid, "token does not belong to a login session")
+
+// SessionStarter starts a login session for an authenticated subject.
+type SessionStarter interface {
+	StartSession(ctx context.Context, role string, subjectID int, twoFactor bool) (*models.TokenResponse, error)
+}
+
+// sessionServiceImpl implements the SessionService interface.
+type sessionServiceImpl struct {
+	sessionRepo repository.SessionRepositoryInterface
+	issuers     map[string]*auth.TokenIssuer
+	refreshTTL  time.Duration
+}
+
+// NewSessionService creates a new login session service. Sessions last
+// refreshTTL and issue access tokens with the issuer of their role.
+func NewSessionService(
+	sessionRepo repository.SessionRepositoryInterface,
+	refreshTTL time.Duration,
+	issuers ...*auth.TokenIssuer,
+) SessionService {
+	s := &sessionServiceImpl{
+		sessionRepo: sessionRepo,
+		issuers:     make(map[string]*auth.TokenIssuer, len(issuers)),
+		refreshTTL:  refreshTTL,
+	}
+	for _, issuer := range issuers {
+		s.issuers[issuer.Role()] = issuer
+	}
+	return s
+}
+
+// StartSession starts a login session for subjectID in role from the device
+// in ctx, returning an access token for it and the refresh token that renews
+// it. twoFactor records that the login passed a second factor, which the
+// session's access tokens then carry.
+func (s *sessionServiceImpl) StartSession(
+	ctx context.Context,
+	role string,
+	subjectID int,
+	twoFactor bool,
+) (*models.TokenResponse, error) {
+	issuer, ok := s.issuers[role]
+	if !ok {
+		return nil, errors.New("no token issuer for role " + role)
+	}
+
+	refreshToken, tokenHash, err := auth.NewRefreshToken()
+	if err != nil {
+		slog.Error("Failed to generate refresh token", "role", role, "subjectID", subjectID, "error", err)
+		return nil, err
+	}
+
+	client := auth.SessionClientFromContext(ctx)
+	session, err := s.sessionRepo.CreateSession(ctx, models.AuthSession{
+		Role:      role,
+		SubjectID: subjectID,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+		TwoFactor: twoFactor,
+	}, tokenHash)
+	if err != nil {
+		slog.Error("Failed to create session", "role", role, "subjectID", subjectID, "error", err)
+		return nil, err
+	}
+
+	return s.issueTokens(issuer, session, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token, retiring the one given, and records the device in ctx as the one
+// the session is used from. Reusing a retired refresh token revokes its
+// session, as it means the token has leaked.
+func (s *sessionServiceImpl) Refresh(ctx context.Context, refreshToken string) (*models.TokenResponse, error) {
+	newToken, newHash, err := auth.NewRefreshToken()
+	if err != nil {
+		slog.Error("Failed to generate refresh token", "error", err)
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.RotateRefreshToken(ctx, auth.HashRefreshToken(refreshToken), newHash,
+		auth.SessionClientFromContext(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRefreshTokenReused):
+			slog.Warn("Refresh token reused, session revoked")
+			return nil, ErrInvalidRefreshToken
+		case errors.Is(err, repository.ErrSessionNotFound):
+			return nil, ErrInvalidRefreshToken
+		}
+		slog.Error("Failed to rotate refresh token", "error", err)
+		return nil, err
+	}
+
+	issuer, ok := s.issuers[session.Role]
+	if !ok {
+		slog.Warn("Refresh for a role without a token issuer", "sessionID", session.ID, "role", session.Role)
+		return nil, ErrInvalidRefreshToken
+	}
+	return s.issueTokens(issuer, session, newToken)
+}
+
+// Logout revokes the login session of the token whose claims are in ctx, so
+// its access and refresh tokens stop working.
+func (s *sessionServiceImpl) Logout(ctx context.Context) error {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok || claims.SessionID == 0 {
+		return ErrSessionTokenRequired
+	}
+
+	if err := s.sessionRepo.RevokeSession(ctx, claims.SessionID); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil
+		}
+		slog.Error("Failed to revoke session", "sessionID", claims.SessionID, "error", err)
+		return err
+	}
+
+	slog.Info("Logged out", "sessionID", claims.SessionID, "role", claims.Role, "subjectID", claims.Subject)
+	return nil
+}
+
+// ListCustomerSessions retrieves a customer's live login sessions, most
+// recently used first, marking the one of the token that made the request.
+func (s *sessionServiceImpl) ListCustomerSessions(
+	ctx context.Context,
+	customerID int,
+) ([]models.AuthSession, error) {
+	sessions, err := s.sessionRepo.ListActiveSessions(ctx, auth.RoleCustomer, customerID)
+	if err != nil {
+		slog.Error("Failed to list customer sessions", "customerID", customerID, "error", err)
+		return nil, err
+	}
+
+	if claims, ok := auth.ClaimsFromContext(ctx); ok && claims.Role == auth.RoleCustomer {
+		for i := range sessions {
+			sessions[i].Current = sessions[i].ID == claims.SessionID
+		}
+	}
+	return sessions, nil
+}
+
+// RevokeCustomerSession revokes one of a customer's login sessions, logging
+// out the device it belongs to.
+func (s *sessionServiceImpl) RevokeCustomerSession(ctx context.Context, customerID int, sessionID int64) error {
+	if err := s.sessionRepo.RevokeSubjectSession(ctx, auth.RoleCustomer, customerID, sessionID); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			slog.Warn("Customer session not found", "customerID", customerID, "sessionID", sessionID)
+			return err
+		}
+		slog.Error("Failed to revoke customer session", "customerID", customerID, "sessionID", sessionID,
+			"error", err)
+		return err
+	}
+
+	slog.Info("Customer session revoked", "customerID", customerID, "sessionID", sessionID)
+	return nil
+}
+
+// issueTokens returns an access token for session from issuer alongside its
+// refresh token.
+func (s *sessionServiceImpl) issueTokens(
+	issuer *auth.TokenIssuer,
+	session *models.AuthSession,
+	refreshToken string,
+) (*models.TokenResponse, error) {
+	token, expiresAt, err := issuer.IssueForSession(session.SubjectID, session.ID, session.TwoFactor)
+	if err != nil {
+		slog.Error("Failed to issue session token", "sessionID", session.ID, "error", err)
+		return nil, err
+	}
+
+	// An access token never outlives its session.
+	if expiresAt.After(session.ExpiresAt) {
+		expiresAt = session.ExpiresAt
+	}
+	return &models.TokenResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: &session.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/shortlinks"
+)
+
+// shortLinkCodeAttempts is how many random codes are tried before giving up
+// on creating a short link. With 40-bit codes a second attempt is already
+// rare.
+const shortLinkCodeAttempts = 3
+
+// shortLinkServiceImpl implements the ShortLinkService interface.
+type shortLinkServiceImpl struct {
+	shortLinkRepo repository.ShortLinkRepositoryInterface
+	target        string
+}
+
+// NewShortLinkService creates a new short link service. Short links
+// redirect to target, with "{id}" replaced by the film's ID.
+func NewShortLinkService(shortLinkRepo repository.ShortLinkRepositoryInterface, target string) ShortLinkService {
+	return &shortLinkServiceImpl{
+		shortLinkRepo: shortLinkRepo,
+		target:        target,
+	}
+}
+
+// CreateShortLink creates a short link to a film under a new random code.
+func (s *shortLinkServiceImpl) CreateShortLink(
+	ctx context.Context,
+	filmID int,
+	linkReq models.ShortLinkRequest,
+) (*models.ShortLink, error) {
+	var link *models.ShortLink
+	err := repository.ErrShortLinkCodeTaken
+	for attempt := 0; attempt < shortLinkCodeAttempts && errors.Is(err, repository.ErrShortLinkCodeTaken); attempt++ {
+		var code string
+		if code, err = shortlinks.NewCode(); err != nil {
+			return nil, err
+		}
+		link, err = s.shortLinkRepo.CreateShortLink(ctx, code, filmID, linkReq.Campaign)
+	}
+	if err != nil {
+		if !errors.Is(err, repository.ErrFilmNotFound) {
+			slog.Error("Failed to create short link", "filmID", filmID, "error", err)
+		}
+		return nil, err
+	}
+
+	link.Path = shortlinks.Path(link.Code)
+	slog.Info("Short link created", "filmID", filmID, "code", link.Code)
+	return link, nil
+}
+
+// ListFilmShortLinks retrieves the short links to a film with their
+// clicks, newest first.
+func (s *shortLinkServiceImpl) ListFilmShortLinks(ctx context.Context, filmID int) ([]models.ShortLink, error) {
+	links, err := s.shortLinkRepo.ListFilmShortLinks(ctx, filmID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		links[i].Path = shortlinks.Path(links[i].Code)
+	}
+	return links, nil
+}
+
+// FollowShortLink counts a click on the short link with code and returns
+// the URL it redirects to.
+func (s *shortLinkServiceImpl) FollowShortLink(ctx context.Context, code string) (string, error) {
+	filmID, err := s.shortLinkRepo.RecordClick(ctx, shortlinks.Normalize(code))
+	if err != nil {
+		if !errors.Is(err, repository.ErrShortLinkNotFound) {
+			slog.Error("Failed to follow short link", "code", code, "error", err)
+		}
+		return "", err
+	}
+
+	return shortlinks.Target(s.target, filmID), nil
+}
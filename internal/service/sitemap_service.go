@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/sitemaps"
+)
+
+// ErrSitemapNotFound is returned for a film sitemap page past the last.
+var ErrSitemapNotFound = apperrors.New(apperrors.NotFound, "sitemap not found")
+
+// SitemapPageSize is the number of films in each film sitemap.
+const SitemapPageSize = 1000
+
+// sitemapFilmsKey holds the film list behind sitemaps in the sitemap cache.
+const sitemapFilmsKey = "sitemap:films"
+
+// sitemapServiceImpl implements the SitemapService interface.
+type sitemapServiceImpl struct {
+	sitemapRepo repository.FilmSitemapRepositoryInterface
+	cache       cache.Cache
+	baseURL     string
+	filmURL     string
+}
+
+// NewSitemapService creates a new sitemap service. The film list is kept in
+// c until it expires, so sitemaps are refreshed at c's time-to-live. Film
+// pages are at filmURL, with "{id}" replaced by the film's ID; sitemaps are
+// under baseURL.
+func NewSitemapService(
+	sitemapRepo repository.FilmSitemapRepositoryInterface,
+	c cache.Cache,
+	baseURL, filmURL string,
+) SitemapService {
+	return &sitemapServiceImpl{sitemapRepo: sitemapRepo, cache: c, baseURL: baseURL, filmURL: filmURL}
+}
+
+// SitemapIndex builds the sitemap index listing the film sitemaps, each
+// last modified when its most recently changed film was.
+func (s *sitemapServiceImpl) SitemapIndex(ctx context.Context) (*sitemaps.Index, error) {
+	films, err := s.films(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index := sitemaps.NewIndex()
+	for page := 1; page == 1 || (page-1)*SitemapPageSize < len(films); page++ {
+		index.Sitemaps = append(index.Sitemaps, sitemaps.Sitemap{
+			Loc:     s.filmSitemapURL(page),
+			LastMod: sitemaps.LastMod(latestFilmUpdate(filmPage(films, page))),
+		})
+	}
+
+	return index, nil
+}
+
+// FilmSitemap builds a page of the film sitemap, listing each film's page.
+// Page 1 always exists, even for an empty catalog.
+func (s *sitemapServiceImpl) FilmSitemap(ctx context.Context, page int) (*sitemaps.URLSet, error) {
+	films, err := s.films(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if page < 1 || (page > 1 && (page-1)*SitemapPageSize >= len(films)) {
+		return nil, ErrSitemapNotFound
+	}
+
+	urlSet := sitemaps.NewURLSet()
+	for _, film := range filmPage(films, page) {
+		urlSet.URLs = append(urlSet.URLs, sitemaps.URL{
+			Loc:     strings.ReplaceAll(s.filmURL, "{id}", strconv.Itoa(film.FilmID)),
+			LastMod: sitemaps.LastMod(film.LastUpdate),
+		})
+	}
+
+	return urlSet, nil
+}
+
+// filmSitemapURL returns the URL of a page of the film sitemap.
+func (s *sitemapServiceImpl) filmSitemapURL(page int) string {
+	return fmt.Sprintf("%s/sitemaps/films-%d.xml", s.baseURL, page)
+}
+
+// films returns the film list behind sitemaps, from the cache when it has
+// not expired.
+func (s *sitemapServiceImpl) films(ctx context.Context) ([]models.FilmUpdate, error) {
+	if cached, ok := s.cache.Get(sitemapFilmsKey); ok {
+		if films, isFilms := cached.([]models.FilmUpdate); isFilms {
+			return films, nil
+		}
+	}
+
+	films, err := s.sitemapRepo.ListFilmUpdates(ctx)
+	if err != nil {
+		slog.Error("Failed to retrieve films for sitemap", "error", err)
+		return nil, err
+	}
+	s.cache.Set(sitemapFilmsKey, films)
+
+	slog.Info("Sitemap film list refreshed", "films", len(films))
+	return films, nil
+}
+
+// filmPage returns the films on a page of the film sitemap.
+func filmPage(films []models.FilmUpdate, page int) []models.FilmUpdate {
+	start := min((page-1)*SitemapPageSize, len(films))
+	return films[start:min(start+SitemapPageSize, len(films))]
+}
+
+// latestFilmUpdate returns the most recent last update among films, or the
+// zero time for none.
+func latestFilmUpdate(films []models.FilmUpdate) time.Time {
+	var latest time.Time
+	for _, film := range films {
+		if film.LastUpdate.After(latest) {
+			latest = film.LastUpdate
+		}
+	}
+	return latest
+}
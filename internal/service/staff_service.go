@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+// ErrInvalidCredentials is returned when a login does not match an active
+// account. It deliberately does not say which part was wrong.
+var ErrInvalidCredentials = apperrors.New(apperrors.Invalid, "invalid username or password")
+
+// ErrInvalidStaffID is returned for a staff ID that is not positive.
+var ErrInvalidStaffID = apperrors.New(apperrors.Invalid, "invalid staff ID")
+
+// staffServiceImpl implements the StaffService interface.
+type staffServiceImpl struct {
+	staffRepo repository.StaffRepositoryInterface
+	tokens    *auth.TokenIssuer
+	sessions  SessionStarter
+	twoFactor TwoFactorVerifier
+}
+
+// StaffServiceOption configures optional staff service behavior.
+type StaffServiceOption func(*staffServiceImpl)
+
+// WithStaffSessions starts a refreshable login session on each staff login
+// instead of issuing a lone token.
+func WithStaffSessions(sessions SessionStarter) StaffServiceOption {
+	return func(s *staffServiceImpl) {
+		s.sessions = sessions
+	}
+}
+
+// WithStaffTwoFactor asks staff who have enabled two-factor authentication
+// for a TOTP or backup code at login.
+func WithStaffTwoFactor(verifier TwoFactorVerifier) StaffServiceOption {
+	return func(s *staffServiceImpl) {
+		s.twoFactor = verifier
+	}
+}
+
+// NewStaffService creates a new staff service issuing staff tokens with the
+// given issuer.
+func NewStaffService(
+	staffRepo repository.StaffRepositoryInterface,
+	tokens *auth.TokenIssuer,
+	opts ...StaffServiceOption,
+) StaffService {
+	s := &staffServiceImpl{
+		staffRepo: staffRepo,
+		tokens:    tokens,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ListStaff retrieves staff members, limited to the request's store if scoped.
+func (s *staffServiceImpl) ListStaff(ctx context.Context) ([]models.Staff, error) {
+	storeID, _ := tenant.StoreIDFromContext(ctx)
+
+	staff, err := s.staffRepo.ListStaff(ctx, storeID)
+	if err != nil {
+		slog.Error("Failed to retrieve staff from repository", "storeID", storeID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Successfully retrieved staff", "count", len(staff))
+	return staff, nil
+}
+
+// CreateStaff adds a new staff member.
+func (s *staffServiceImpl) CreateStaff(ctx context.Context, staffReq models.StaffRequest) (*models.Staff, error) {
+	passwordHash, err := auth.HashPassword(staffReq.Password)
+	if err != nil {
+		slog.Error("Failed to hash staff password", "error", err)
+		return nil, err
+	}
+
+	member, err := s.staffRepo.CreateStaff(ctx, staffReq, passwordHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrUsernameTaken) || errors.Is(err, repository.ErrInvalidReference) {
+			slog.Warn("Rejected staff creation", "username", staffReq.Username, "error", err)
+			return nil, err
+		}
+		slog.Error("Failed to create staff in repository", "username", staffReq.Username, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Successfully created staff", "staffID", member.StaffID, "username", member.Username)
+	return member, nil
+}
+
+// DeactivateStaff marks a staff member inactive.
+func (s *staffServiceImpl) DeactivateStaff(ctx context.Context, staffID int) (*models.Staff, error) {
+	if staffID <= 0 {
+		slog.Warn("Invalid staff ID provided", "staffID", staffID)
+		return nil, ErrInvalidStaffID
+	}
+
+	member, err := s.staffRepo.DeactivateStaff(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, repository.ErrStaffNotFound) {
+			slog.Warn("Staff not found", "staffID", staffID)
+			return nil, err
+		}
+		slog.Error("Failed to deactivate staff in repository", "staffID", staffID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Successfully deactivated staff", "staffID", staffID)
+	return member, nil
+}
+
+// Login exchanges staff credentials for a staff bearer token, with a
+// refresh token when login sessions are enabled. Unknown, inactive, and
+// wrong-password logins all return ErrInvalidCredentials. Staff with
+// two-factor authentication enabled must also give a code, and their tokens
+// record that they did.
+func (s *staffServiceImpl) Login(
+	ctx context.Context,
+	loginReq models.StaffLoginRequest,
+) (*models.TokenResponse, error) {
+	member, passwordHash, err := s.staffRepo.GetStaffCredentials(ctx, loginReq.Username)
+	if err != nil {
+		if errors.Is(err, repository.ErrStaffNotFound) {
+			slog.Warn("Staff login for unknown username", "username", loginReq.Username)
+			auth.CheckUnknownAccountPassword(loginReq.Password)
+			return nil, ErrInvalidCredentials
+		}
+		slog.Error("Failed to retrieve staff credentials", "username", loginReq.Username, "error", err)
+		return nil, err
+	}
+
+	// The password is checked even for inactive staff, so they take as long
+	// to reject as anyone else.
+	passwordMatches := auth.CheckPassword(passwordHash, loginReq.Password)
+	if !member.Active || !passwordMatches {
+		slog.Warn("Rejected staff login", "staffID", member.StaffID, "active", member.Active)
+		return nil, ErrInvalidCredentials
+	}
+	if auth.NeedsRehash(passwordHash) {
+		s.rehashPassword(ctx, member.StaffID, loginReq.Password)
+	}
+
+	twoFactor := false
+	if s.twoFactor != nil {
+		if twoFactor, err = s.twoFactor.VerifyLogin(ctx, member.StaffID, loginReq.OTPCode); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.sessions != nil {
+		tokens, sessionErr := s.sessions.StartSession(ctx, auth.RoleStaff, member.StaffID, twoFactor)
+		if sessionErr != nil {
+			return nil, sessionErr
+		}
+		slog.Info("Staff logged in", "staffID", member.StaffID)
+		return tokens, nil
+	}
+
+	token, expiresAt, err := s.tokens.IssueForSession(member.StaffID, 0, twoFactor)
+	if err != nil {
+		slog.Error("Failed to issue staff token", "staffID", member.StaffID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Staff logged in", "staffID", member.StaffID)
+	return &models.TokenResponse{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// rehashPassword replaces a staff member's legacy SHA-1 password hash with a
+// bcrypt one, once their login has proven the password. Failing to is
+// logged rather than failing the login; the next login tries again.
+func (s *staffServiceImpl) rehashPassword(ctx context.Context, staffID int, password string) {
+	passwordHash, err := auth.HashPassword(password)
+	if err == nil {
+		err = s.staffRepo.UpdateStaffPassword(ctx, staffID, passwordHash)
+	}
+	if err != nil {
+		slog.Warn("Failed to rehash legacy staff password", "staffID", staffID, "error", err)
+		return
+	}
+	slog.Info("Rehashed legacy staff password", "staffID", staffID)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrInvalidTag is returned when a tag is blank once normalized.
+var ErrInvalidTag = apperrors.New(apperrors.Invalid, "tag must not be blank")
+
+// tagServiceImpl implements the TagService interface.
+type tagServiceImpl struct {
+	tagRepo       repository.TagRepositoryInterface
+	invalidations cache.Publisher
+}
+
+// NewTagService creates a new tag service. Films show their tags, so each
+// change is published to invalidations to evict the film from the cache.
+func NewTagService(tagRepo repository.TagRepositoryInterface, invalidations cache.Publisher) TagService {
+	return &tagServiceImpl{
+		tagRepo:       tagRepo,
+		invalidations: invalidations,
+	}
+}
+
+// TagFilm adds a tag to a film, returning the film's tags.
+func (s *tagServiceImpl) TagFilm(ctx context.Context, filmID int, tag string) (*models.FilmTags, error) {
+	tag = normalizeTag(tag)
+	if tag == "" {
+		return nil, ErrInvalidTag
+	}
+
+	tags, err := s.tagRepo.TagFilm(ctx, filmID, tag)
+	if err != nil {
+		if !errors.Is(err, repository.ErrFilmNotFound) {
+			slog.Error("Failed to tag film", "filmID", filmID, "tag", tag, "error", err)
+		}
+		return nil, err
+	}
+
+	s.invalidations.Publish(cache.FilmChanged(filmID))
+	slog.Info("Film tagged", "filmID", filmID, "tag", tag)
+	return &models.FilmTags{FilmID: filmID, Tags: tags}, nil
+}
+
+// UntagFilm removes a tag from a film, returning the film's tags.
+func (s *tagServiceImpl) UntagFilm(ctx context.Context, filmID int, tag string) (*models.FilmTags, error) {
+	tag = normalizeTag(tag)
+
+	tags, err := s.tagRepo.UntagFilm(ctx, filmID, tag)
+	if err != nil {
+		if !errors.Is(err, repository.ErrFilmNotFound) && !errors.Is(err, repository.ErrFilmTagNotFound) {
+			slog.Error("Failed to untag film", "filmID", filmID, "tag", tag, "error", err)
+		}
+		return nil, err
+	}
+
+	s.invalidations.Publish(cache.FilmChanged(filmID))
+	slog.Info("Film untagged", "filmID", filmID, "tag", tag)
+	return &models.FilmTags{FilmID: filmID, Tags: tags}, nil
+}
+
+// ListTags retrieves every tag in use with the number of films carrying it,
+// most used first.
+func (s *tagServiceImpl) ListTags(ctx context.Context) ([]models.TagCount, error) {
+	return s.tagRepo.ListTags(ctx)
+}
+
+// normalizeTag returns tag in its stored form: lower case, with runs of
+// whitespace collapsed to single spaces and none at either end.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// normalizeTags returns tags in their stored form, or nil when there are
+// none.
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	normalized := make([]string, len(tags))
+	for i, tag := range tags {
+		normalized[i] = normalizeTag(tag)
+	}
+	return normalized
+}
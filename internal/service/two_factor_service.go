@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// ErrTwoFactorRequired is returned when a staff member with two-factor
+// authentication enabled logs in without a code.
+var ErrTwoFactorRequired = apperrors.New(apperrors.Invalid, "a two-factor code is required")
+
+// ErrInvalidTwoFactorCode is returned for a wrong, expired, or already used
+// TOTP or backup code.
+var ErrInvalidTwoFactorCode = apperrors.New(apperrors.Invalid, "invalid two-factor code")
+
+// TwoFactorVerifier checks the second factor of a staff login.
+type TwoFactorVerifier interface {
+	VerifyLogin(ctx context.Context, staffID int, code string) (bool, error)
+}
+
+// twoFactorServiceImpl implements the TwoFactorService interface.
+type twoFactorServiceImpl struct {
+	twoFactorRepo repository.TwoFactorRepositoryInterface
+	issuer        string
+	now           func() time.Time
+}
+
+// NewTwoFactorService creates a new staff two-factor service. issuer names
+// the service in authenticator apps.
+func NewTwoFactorService(twoFactorRepo repository.TwoFactorRepositoryInterface, issuer string) TwoFactorService {
+	return &twoFactorServiceImpl{
+		twoFactorRepo: twoFactorRepo,
+		issuer:        issuer,
+		now:           time.Now,
+	}
+}
+
+// EnrollTwoFactor issues a staff member a new TOTP secret and backup codes,
+// replacing any enrollment not yet confirmed. Login does not ask for a code
+// until ConfirmTwoFactor proves the authenticator app has the secret.
+func (s *twoFactorServiceImpl) EnrollTwoFactor(ctx context.Context, staffID int) (*models.TwoFactorEnrollment, error) {
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		slog.Error("Failed to generate TOTP secret", "staffID", staffID, "error", err)
+		return nil, err
+	}
+	codes, hashes, err := auth.NewBackupCodes()
+	if err != nil {
+		slog.Error("Failed to generate backup codes", "staffID", staffID, "error", err)
+		return nil, err
+	}
+
+	username, err := s.twoFactorRepo.BeginEnrollment(ctx, staffID, secret, hashes)
+	if err != nil {
+		if errors.Is(err, repository.ErrStaffNotFound) || errors.Is(err, repository.ErrTwoFactorEnabled) {
+			slog.Warn("Rejected two-factor enrollment", "staffID", staffID, "error", err)
+			return nil, err
+		}
+		slog.Error("Failed to begin two-factor enrollment", "staffID", staffID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Began two-factor enrollment", "staffID", staffID)
+	return &models.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(s.issuer, username, secret),
+		BackupCodes:     codes,
+	}, nil
+}
+
+// ConfirmTwoFactor enables a staff member's pending two-factor enrollment
+// once code shows their authenticator app generates the right codes.
+func (s *twoFactorServiceImpl) ConfirmTwoFactor(ctx context.Context, staffID int, code string) error {
+	factor, err := s.twoFactorRepo.GetTwoFactor(ctx, staffID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrTwoFactorNotFound) {
+			slog.Error("Failed to retrieve two-factor", "staffID", staffID, "error", err)
+		}
+		return err
+	}
+	if factor.Enabled {
+		return repository.ErrTwoFactorEnabled
+	}
+
+	if err = s.useTOTPCode(ctx, factor, code); err != nil {
+		return err
+	}
+
+	slog.Info("Enabled two-factor authentication", "staffID", staffID)
+	return nil
+}
+
+// VerifyLogin checks code, a TOTP code or an unused backup code, for a
+// staff member logging in, reporting whether they have two-factor
+// authentication enabled. Staff without it pass with any code.
+func (s *twoFactorServiceImpl) VerifyLogin(ctx context.Context, staffID int, code string) (bool, error) {
+	factor, err := s.twoFactorRepo.GetTwoFactor(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTwoFactorNotFound) {
+			return false, nil
+		}
+		slog.Error("Failed to retrieve two-factor", "staffID", staffID, "error", err)
+		return false, err
+	}
+	if !factor.Enabled {
+		return false, nil
+	}
+	if code == "" {
+		return true, ErrTwoFactorRequired
+	}
+
+	if _, isTOTP := auth.VerifyTOTP(factor.Secret, code, s.now()); isTOTP {
+		return true, s.useTOTPCode(ctx, factor, code)
+	}
+
+	used, err := s.twoFactorRepo.UseBackupCode(ctx, staffID, auth.HashBackupCode(code))
+	if err != nil {
+		slog.Error("Failed to use backup code", "staffID", staffID, "error", err)
+		return true, err
+	}
+	if !used {
+		slog.Warn("Rejected two-factor code", "staffID", staffID)
+		return true, ErrInvalidTwoFactorCode
+	}
+	slog.Info("Staff used a backup code", "staffID", staffID)
+	return true, nil
+}
+
+// useTOTPCode accepts code for factor unless it is wrong or its time step
+// was already used.
+func (s *twoFactorServiceImpl) useTOTPCode(ctx context.Context, factor *models.StaffTwoFactor, code string) error {
+	step, ok := auth.VerifyTOTP(factor.Secret, code, s.now())
+	if !ok || step <= factor.LastUsedStep {
+		slog.Warn("Rejected two-factor code", "staffID", factor.StaffID)
+		return ErrInvalidTwoFactorCode
+	}
+
+	used, err := s.twoFactorRepo.UseTOTPStep(ctx, factor.StaffID, step)
+	if err != nil {
+		slog.Error("Failed to record TOTP step", "staffID", factor.StaffID, "error", err)
+		return err
+	}
+	if !used {
+		slog.Warn("Rejected replayed two-factor code", "staffID", factor.StaffID)
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
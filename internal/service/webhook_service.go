@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/webhooks"
+)
+
+// DeliveryReplayer queues another attempt at a webhook delivery.
+type DeliveryReplayer interface {
+	Replay(deliveryID int64) error
+}
+
+// webhookServiceImpl implements the WebhookService interface.
+type webhookServiceImpl struct {
+	webhookRepo repository.WebhookRepositoryInterface
+	replayer    DeliveryReplayer
+	secretGrace time.Duration
+	retention   time.Duration
+}
+
+// NewWebhookService creates a new webhook service. Rotated secrets stay valid
+// for secretGrace, and delivery history is kept for retention.
+func NewWebhookService(
+	webhookRepo repository.WebhookRepositoryInterface,
+	replayer DeliveryReplayer,
+	secretGrace, retention time.Duration,
+) WebhookService {
+	return &webhookServiceImpl{
+		webhookRepo: webhookRepo,
+		replayer:    replayer,
+		secretGrace: secretGrace,
+		retention:   retention,
+	}
+}
+
+// ListSubscriptions retrieves every webhook subscription, without secrets.
+func (s *webhookServiceImpl) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	subscriptions, err := s.webhookRepo.ListSubscriptions(ctx)
+	if err != nil {
+		slog.Error("Failed to retrieve webhook subscriptions from repository", "error", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// CreateSubscription adds a webhook subscription with a new signing secret,
+// which is only returned here and on rotation.
+func (s *webhookServiceImpl) CreateSubscription(
+	ctx context.Context,
+	subscriptionReq models.WebhookSubscriptionRequest,
+) (*models.WebhookSubscription, error) {
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.webhookRepo.CreateSubscription(ctx, subscriptionReq, secret)
+	if err != nil {
+		slog.Error("Failed to create webhook subscription", "url", subscriptionReq.URL, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Webhook subscription created", "subscriptionID", subscription.ID, "events", subscription.Events)
+	return subscription, nil
+}
+
+// DeleteSubscription removes a webhook subscription and its delivery history.
+func (s *webhookServiceImpl) DeleteSubscription(ctx context.Context, subscriptionID int) error {
+	if err := s.webhookRepo.DeleteSubscription(ctx, subscriptionID); err != nil {
+		if !errors.Is(err, repository.ErrWebhookNotFound) {
+			slog.Error("Failed to delete webhook subscription", "subscriptionID", subscriptionID, "error", err)
+		}
+		return err
+	}
+
+	slog.Info("Webhook subscription deleted", "subscriptionID", subscriptionID)
+	return nil
+}
+
+// RotateSecret issues a new signing secret for a subscription.
+func (s *webhookServiceImpl) RotateSecret(
+	ctx context.Context,
+	subscriptionID int,
+) (*models.WebhookSubscription, error) {
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.webhookRepo.RotateSecret(ctx, subscriptionID, secret, s.secretGrace)
+	if err != nil {
+		if !errors.Is(err, repository.ErrWebhookNotFound) {
+			slog.Error("Failed to rotate webhook secret", "subscriptionID", subscriptionID, "error", err)
+		}
+		return nil, err
+	}
+
+	slog.Warn("Webhook secret rotated", "subscriptionID", subscriptionID, "previousValidFor", s.secretGrace)
+	return subscription, nil
+}
+
+// ListDeliveries retrieves a subscription's most recent deliveries.
+func (s *webhookServiceImpl) ListDeliveries(
+	ctx context.Context,
+	subscriptionID int,
+	filters models.WebhookDeliveryFilters,
+) ([]models.WebhookDelivery, error) {
+	deliveries, err := s.webhookRepo.ListDeliveries(ctx, subscriptionID, filters)
+	if err != nil {
+		slog.Error("Failed to retrieve webhook deliveries", "subscriptionID", subscriptionID, "error", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ReplayDelivery queues a failed delivery to be sent again.
+func (s *webhookServiceImpl) ReplayDelivery(
+	ctx context.Context,
+	subscriptionID int,
+	deliveryID int64,
+) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.ResetFailedDelivery(ctx, subscriptionID, deliveryID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrWebhookDeliveryNotFound) &&
+			!errors.Is(err, repository.ErrWebhookDeliveryNotFailed) {
+			slog.Error("Failed to reset webhook delivery", "deliveryID", deliveryID, "error", err)
+		}
+		return nil, err
+	}
+
+	if err = s.replayer.Replay(deliveryID); err != nil {
+		slog.Error("Failed to queue webhook replay", "deliveryID", deliveryID, "error", err)
+		return nil, err
+	}
+
+	slog.Info("Webhook delivery replayed", "subscriptionID", subscriptionID, "deliveryID", deliveryID)
+	return delivery, nil
+}
+
+// PurgeDeliveries deletes delivery history older than the retention period.
+func (s *webhookServiceImpl) PurgeDeliveries(ctx context.Context) error {
+	purged, err := s.webhookRepo.PurgeDeliveries(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Webhook deliveries purged", "count", purged, "retention", s.retention)
+	return nil
+}
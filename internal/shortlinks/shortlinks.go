@@ -0,0 +1,52 @@
+// Package shortlinks generates the codes of short links to films and the
+// URLs they redirect to.
+package shortlinks
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// codeAlphabet holds the characters of a code: digits and lower-case
+// letters without i, l, o, and u, which are easily misread. Its 32
+// characters divide 256 evenly, so each random byte picks one without bias.
+const codeAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// codeLength is the number of characters in a code, 40 random bits.
+const codeLength = 8
+
+// FilmIDPlaceholder is replaced with a film's ID in redirect targets.
+const FilmIDPlaceholder = "{id}"
+
+// NewCode generates a random short link code, such as "k3x9qm2a".
+func NewCode() (string, error) {
+	raw := make([]byte, codeLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("error generating short link code: %w", err)
+	}
+
+	code := make([]byte, codeLength)
+	for i, c := range raw {
+		code[i] = codeAlphabet[int(c)%len(codeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// Normalize returns code as it is stored, lower case, so codes typed in
+// upper case still resolve.
+func Normalize(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// Path returns the path of the short link with code.
+func Path(code string) string {
+	return "/f/" + code
+}
+
+// Target returns the URL a short link to filmID redirects to, replacing
+// FilmIDPlaceholder in target.
+func Target(target string, filmID int) string {
+	return strings.ReplaceAll(target, FilmIDPlaceholder, strconv.Itoa(filmID))
+}
@@ -0,0 +1,69 @@
+// Package sitemaps builds XML sitemaps (sitemaps.org protocol 0.9) so
+// search engines can find every page of the public catalog.
+package sitemaps
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// ContentType is the media type sitemaps are served with.
+const ContentType = "application/xml; charset=utf-8"
+
+// namespace is the XML namespace of sitemaps and sitemap indexes.
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URLSet is a sitemap listing pages.
+type URLSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	URLs    []URL    `xml:"url"`
+}
+
+// URL is a page in a sitemap.
+type URL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Index is a sitemap index listing sitemaps, for sites with more pages than
+// one sitemap should hold.
+type Index struct {
+	XMLName  xml.Name  `xml:"sitemapindex"`
+	Xmlns    string    `xml:"xmlns,attr"`
+	Sitemaps []Sitemap `xml:"sitemap"`
+}
+
+// Sitemap is a sitemap in a sitemap index.
+type Sitemap struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// NewURLSet returns an empty sitemap.
+func NewURLSet() *URLSet {
+	return &URLSet{Xmlns: namespace, URLs: []URL{}}
+}
+
+// NewIndex returns an empty sitemap index.
+func NewIndex() *Index {
+	return &Index{Xmlns: namespace, Sitemaps: []Sitemap{}}
+}
+
+// LastMod formats t as a lastmod value, or returns "" for the zero time.
+func LastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Marshal encodes a URLSet or Index as an XML document.
+func Marshal(document any) ([]byte, error) {
+	body, err := xml.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding sitemap: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,17 @@
+// Package tenant carries the store an API request is scoped to.
+package tenant
+
+import "context"
+
+type storeIDKey struct{}
+
+// WithStoreID scopes ctx to the given store.
+func WithStoreID(ctx context.Context, storeID int) context.Context {
+	return context.WithValue(ctx, storeIDKey{}, storeID)
+}
+
+// StoreIDFromContext returns the store ctx is scoped to, if any.
+func StoreIDFromContext(ctx context.Context) (int, bool) {
+	storeID, ok := ctx.Value(storeIDKey{}).(int)
+	return storeID, ok
+}
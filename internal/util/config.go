@@ -1,7 +1,12 @@
 // Package util provides utility functions for configuration management.
 package util //nolint:revive //Package name is fine IMO
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Config holds application configuration. Can be extended to include more
 // and work with helm charts.
@@ -11,16 +16,355 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+	// DBSlowQueryThreshold is the query duration above which queries are
+	// logged as slow; 0 disables the slow-query log.
+	DBSlowQueryThreshold time.Duration
+	// MigrationsEnabled runs pending migrations on startup. Disable it where
+	// a separate job runs them.
+	MigrationsEnabled bool
+	// SchemaCheck sets what happens on startup when the database is missing
+	// migrations, tables, or columns the API needs: fail, warn, or off.
+	SchemaCheck string
+
+	// RatingMappings map MPAA ratings to local rating systems, each entry of
+	// the form "SYSTEM:RATING=LOCAL", such as "UK:PG-13=12A".
+	RatingMappings []string
+
+	// DefaultPageSize is the film listing page size when a request sets no
+	// limit; MaxPageSize is the largest limit a request may set.
+	DefaultPageSize int
+	MaxPageSize     int
+
+	CacheEnabled bool
+	CacheTTL     time.Duration
+	// CacheMaxEntries bounds the number of cached reads.
+	CacheMaxEntries int
+	// CacheWarmPages is the number of default film listing pages loaded into
+	// the cache on startup; 0 disables warming.
+	CacheWarmPages int
+
+	// StaleFallbackEnabled replays the last successful GET response when the
+	// database is unavailable.
+	StaleFallbackEnabled bool
+	// StaleMaxAge is how long a stored response may be replayed.
+	StaleMaxAge time.Duration
+	// StaleMaxEntries bounds the number of stored responses.
+	StaleMaxEntries int
+
+	// CacheControlCatalog, CacheControlComments, and CacheControlAdmin are
+	// the Cache-Control headers of catalog reads, comments, and admin routes.
+	CacheControlCatalog  string
+	CacheControlComments string
+	CacheControlAdmin    string
+
+	AdminAPIToken string
+	// AdminAllowedNetworks lists the CIDR ranges admin routes may be called
+	// from; any address may call them when empty. TrustedProxies lists the
+	// ranges of proxies whose X-Forwarded-For entries are believed.
+	AdminAllowedNetworks []string
+	TrustedProxies       []string
+	// PIIEncryptionKey is the base64-encoded 256-bit master key personal
+	// data is encrypted under at rest; it is stored in the clear when unset.
+	PIIEncryptionKey string
+	// StaffAuthSecret signs staff bearer tokens; staff routes are disabled
+	// when unset.
+	StaffAuthSecret string
+	// StaffTokenTTL is how long a staff login token stays valid.
+	StaffTokenTTL time.Duration
+	// StaffRequireTwoFactor rejects staff tokens from logins that did not
+	// pass a TOTP second factor everywhere but two-factor enrollment.
+	StaffRequireTwoFactor bool
+	// TwoFactorIssuer names the service in authenticator apps.
+	TwoFactorIssuer string
+
+	// CustomerAuthSecret signs customer bearer tokens; customer registration
+	// and login are disabled when unset.
+	CustomerAuthSecret string
+	// CustomerTokenTTL is how long a customer login token stays valid.
+	CustomerTokenTTL time.Duration
+	// RefreshTokenTTL is how long a staff or customer login session can be
+	// renewed with refresh tokens before logging in again.
+	RefreshTokenTTL time.Duration
+	// CalendarTokenTTL is how long the token in a customer's rental calendar
+	// URL stays valid. Calendar tokens are signed with CustomerAuthSecret.
+	CalendarTokenTTL time.Duration
+	// GuestTokenTTL is how long an anonymous guest session token stays valid.
+	// Guest tokens are signed with CustomerAuthSecret.
+	GuestTokenTTL time.Duration
+	// GuestRequestsPerMinute limits the comment and watchlist requests made
+	// with one guest session token.
+	GuestRequestsPerMinute int
+
+	// EmailBackend selects how notifications are sent: log, smtp, or sendgrid.
+	EmailBackend string
+	// EmailFrom is the sender address for notification emails.
+	EmailFrom      string
+	SMTPHost       string
+	SMTPPort       string
+	SMTPUsername   string
+	SMTPPassword   string
+	SendGridAPIKey string
+	// JobWorkers is the number of background job workers in this process.
+	JobWorkers int
+	// JobPollInterval is how often idle workers check the database for due
+	// jobs, such as retries or jobs enqueued by other replicas.
+	JobPollInterval time.Duration
+	// JobRetention is how long succeeded and dead background jobs are kept.
+	JobRetention time.Duration
+
+	// SchedulerEnabled runs the periodic rental jobs in this process. Replicas
+	// coordinate through the database so each job runs once per interval.
+	SchedulerEnabled bool
+	// The Job*Interval settings set how often each scheduled job runs; 0
+	// disables that job.
+	JobMarkOverdueInterval            time.Duration
+	JobDueRemindersInterval           time.Duration
+	JobRefreshTrendingInterval        time.Duration
+	JobPurgeWebhookDeliveriesInterval time.Duration
+	JobPurgeJobsInterval              time.Duration
+	JobPublishFilmsInterval           time.Duration
+	JobSavedSearchAlertsInterval      time.Duration
+	JobCommentSummariesInterval       time.Duration
+	// RentalReminderWindow is how far ahead of the due date reminders are sent.
+	RentalReminderWindow time.Duration
+	// TrendingWindow is the rental history used to rank trending films.
+	TrendingWindow time.Duration
+	// LateFeePerDay is the fee charged for each day a rental is overdue, up to
+	// the film's replacement cost.
+	LateFeePerDay float64
+	// HomeFeedBudget bounds how long a customer's home feed waits for its
+	// sections; those not ready by then are left out.
+	HomeFeedBudget time.Duration
+
+	// PaymentProvider selects how checkouts are paid: fake (local
+	// development) or stripe.
+	PaymentProvider string
+	// PaymentCurrency is the ISO currency code payments are taken in.
+	PaymentCurrency     string
+	StripeSecretKey     string
+	StripeWebhookSecret string
+
+	// LoyaltyPointsPerRental and LoyaltyPointsPerComment are the loyalty
+	// points a customer earns for each rental and each comment they post.
+	LoyaltyPointsPerRental  int
+	LoyaltyPointsPerComment int
+	// LoyaltyPointsPerDollar is how many points redeem for a dollar of
+	// rental credit.
+	LoyaltyPointsPerDollar int
+
+	// ShortLinkTarget is the URL short links to films redirect to, with
+	// "{id}" standing for the film's ID.
+	ShortLinkTarget string
+	// PublicBaseURL is the scheme and host the API is reached at publicly,
+	// used for the absolute links in feeds and sitemaps.
+	PublicBaseURL string
+	// SitemapFilmURL is the URL of a film's page listed in sitemaps, with
+	// "{id}" standing for the film's ID. SitemapRefreshInterval is how long
+	// the film list behind sitemaps is cached.
+	SitemapFilmURL         string
+	SitemapRefreshInterval time.Duration
+	// CategoryStatsTTL is how long a category's admin statistics are cached.
+	CategoryStatsTTL time.Duration
+	// CommentSummaryTTL is how long a film's comment summary is cached.
+	CommentSummaryTTL time.Duration
+	// CustomerExportTTL is how long a customer's data export is reused
+	// before a new request builds a fresh one.
+	CustomerExportTTL time.Duration
+	// SignedURLSecret signs the URLs that download exports and receipts
+	// without a bearer token; no signed URLs are issued when unset.
+	// SignedURLTTL is how long a signed URL stays valid.
+	SignedURLSecret string
+	SignedURLTTL    time.Duration
+	// AttachmentDir is where images attached to comments are stored,
+	// served under /media; attachments are refused when unset.
+	// AttachmentMaxBytes bounds each image, and AttachmentScanCommand, if
+	// set, is run to scan each for viruses before it is stored.
+	AttachmentDir         string
+	AttachmentMaxBytes    int
+	AttachmentScanCommand string
+
+	// WebhookTimeout bounds each webhook delivery request.
+	WebhookTimeout time.Duration
+	// WebhookSecretGrace is how long a rotated-out signing secret keeps
+	// signing deliveries alongside its replacement.
+	WebhookSecretGrace time.Duration
+	// WebhookDeliveryRetention is how long webhook delivery history is kept.
+	WebhookDeliveryRetention time.Duration
+
+	// GRPCPort is the port of the internal gRPC API.
+	GRPCPort string
+	// GRPCAPIToken is the bearer token gRPC callers must present; the gRPC
+	// API is disabled when unset.
+	GRPCAPIToken string
+
+	// MaintenanceMode starts the API in read-only mode; it can be toggled at
+	// runtime through the admin API.
+	MaintenanceMode bool
+
+	// LogLevel is one of debug, info, warn, or error.
+	LogLevel string
+	// LogFormat is text or json.
+	LogFormat string
+
+	// SentryDSN enables error reporting to Sentry when set.
+	SentryDSN string
+	// SentryEnvironment tags reported errors with the deployment environment.
+	SentryEnvironment string
+
+	// AccessLogSkipHealthChecks omits health-check probes from the access log.
+	AccessLogSkipHealthChecks bool
+
+	// MaxInFlightRequests caps concurrently served requests; 0 disables the
+	// limit.
+	MaxInFlightRequests int
+	// LoadShedQueueTimeout is how long a request waits for a free slot before
+	// it is shed with 503.
+	LoadShedQueueTimeout time.Duration
+	// HandlerTimeout bounds /api/v1 handlers, which answer 504 when it passes;
+	// 0 disables the limit. Keep it below the server's write timeout.
+	HandlerTimeout time.Duration
+
+	// CompressionEnabled compresses responses for clients that accept it.
+	CompressionEnabled bool
+	// CompressionEncodings lists the codings offered, br or gzip, most
+	// preferred first.
+	CompressionEncodings []string
+	// CompressionGzipLevel (1-9) and CompressionBrotliLevel (0-11) trade
+	// speed for size.
+	CompressionGzipLevel   int
+	CompressionBrotliLevel int
+	// CompressionMinSize is the smallest response body compressed, in bytes.
+	CompressionMinSize int
+	// CompressionSkipTypes lists content types that are already compressed;
+	// an entry ending in "/" matches every subtype.
+	CompressionSkipTypes []string
 }
 
 // InitConfig initializes configuration from environment variables.
 func InitConfig() Config {
+	publicBaseURL := strings.TrimSuffix(GetEnv("PUBLIC_BASE_URL", "http://localhost:8080"), "/")
+
 	return Config{
-		DBHost:     GetEnv("DB_HOST", "localhost"),
-		DBPort:     GetEnv("DB_PORT", "5432"),
-		DBUser:     GetEnv("DB_USER", "postgres"),
-		DBPassword: GetEnv("DB_PASSWORD", "postgres"),
-		DBName:     GetEnv("DB_NAME", "dvdrental"),
+		DBHost:               GetEnv("DB_HOST", "localhost"),
+		DBPort:               GetEnv("DB_PORT", "5432"),
+		DBUser:               GetEnv("DB_USER", "postgres"),
+		DBPassword:           GetEnv("DB_PASSWORD", "postgres"),
+		DBName:               GetEnv("DB_NAME", "dvdrental"),
+		DBSlowQueryThreshold: GetEnvDuration("DB_SLOW_QUERY_THRESHOLD", 500*time.Millisecond),
+		MigrationsEnabled:    GetEnvBool("MIGRATIONS_ENABLED", true),
+		SchemaCheck:          GetEnv("SCHEMA_CHECK", "fail"),
+
+		RatingMappings: GetEnvList("RATING_MAPPINGS", nil),
+
+		DefaultPageSize: GetEnvInt("DEFAULT_PAGE_SIZE", 10),
+		MaxPageSize:     GetEnvInt("MAX_PAGE_SIZE", 100),
+
+		CacheEnabled:    GetEnvBool("CACHE_ENABLED", true),
+		CacheTTL:        GetEnvDuration("CACHE_TTL", 5*time.Minute),
+		CacheMaxEntries: GetEnvInt("CACHE_MAX_ENTRIES", 10000),
+		CacheWarmPages:  GetEnvInt("CACHE_WARM_PAGES", 0),
+
+		StaleFallbackEnabled: GetEnvBool("SERVE_STALE_ON_DB_ERROR", true),
+		StaleMaxAge:          GetEnvDuration("STALE_MAX_AGE", 24*time.Hour),
+		StaleMaxEntries:      GetEnvInt("STALE_MAX_ENTRIES", 1000),
+
+		CacheControlCatalog:  GetEnv("CACHE_CONTROL_CATALOG", "public, max-age=60"),
+		CacheControlComments: GetEnv("CACHE_CONTROL_COMMENTS", "no-cache"),
+		CacheControlAdmin:    GetEnv("CACHE_CONTROL_ADMIN", "no-store"),
+
+		AdminAPIToken:         GetEnv("ADMIN_API_TOKEN", ""),
+		AdminAllowedNetworks:  GetEnvList("ADMIN_ALLOWED_NETWORKS", nil),
+		TrustedProxies:        GetEnvList("TRUSTED_PROXIES", nil),
+		PIIEncryptionKey:      GetEnv("PII_ENCRYPTION_KEY", ""),
+		StaffAuthSecret:       GetEnv("STAFF_AUTH_SECRET", ""),
+		StaffTokenTTL:         GetEnvDuration("STAFF_TOKEN_TTL", 8*time.Hour),
+		StaffRequireTwoFactor: GetEnvBool("STAFF_REQUIRE_TWO_FACTOR", false),
+		TwoFactorIssuer:       GetEnv("TWO_FACTOR_ISSUER", "Mockbuster"),
+
+		CustomerAuthSecret:     GetEnv("CUSTOMER_AUTH_SECRET", ""),
+		CustomerTokenTTL:       GetEnvDuration("CUSTOMER_TOKEN_TTL", 24*time.Hour),
+		RefreshTokenTTL:        GetEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		CalendarTokenTTL:       GetEnvDuration("CALENDAR_TOKEN_TTL", 365*24*time.Hour),
+		GuestTokenTTL:          GetEnvDuration("GUEST_TOKEN_TTL", 30*24*time.Hour),
+		GuestRequestsPerMinute: GetEnvInt("GUEST_REQUESTS_PER_MINUTE", 30),
+
+		EmailBackend:    GetEnv("EMAIL_BACKEND", "log"),
+		EmailFrom:       GetEnv("EMAIL_FROM", "no-reply@mockbuster.local"),
+		SMTPHost:        GetEnv("SMTP_HOST", "localhost"),
+		SMTPPort:        GetEnv("SMTP_PORT", "587"),
+		SMTPUsername:    GetEnv("SMTP_USERNAME", ""),
+		SMTPPassword:    GetEnv("SMTP_PASSWORD", ""),
+		SendGridAPIKey:  GetEnv("SENDGRID_API_KEY", ""),
+		JobWorkers:      GetEnvInt("JOB_WORKERS", 2),
+		JobPollInterval: GetEnvDuration("JOB_POLL_INTERVAL", 2*time.Second),
+		JobRetention:    GetEnvDuration("JOB_RETENTION", 7*24*time.Hour),
+
+		SchedulerEnabled:                  GetEnvBool("SCHEDULER_ENABLED", true),
+		JobMarkOverdueInterval:            GetEnvDuration("JOB_MARK_OVERDUE_INTERVAL", 15*time.Minute),
+		JobDueRemindersInterval:           GetEnvDuration("JOB_DUE_REMINDERS_INTERVAL", time.Hour),
+		JobRefreshTrendingInterval:        GetEnvDuration("JOB_REFRESH_TRENDING_INTERVAL", 10*time.Minute),
+		JobPurgeWebhookDeliveriesInterval: GetEnvDuration("JOB_PURGE_WEBHOOK_DELIVERIES_INTERVAL", 24*time.Hour),
+		JobPurgeJobsInterval:              GetEnvDuration("JOB_PURGE_JOBS_INTERVAL", 24*time.Hour),
+		JobPublishFilmsInterval:           GetEnvDuration("JOB_PUBLISH_FILMS_INTERVAL", time.Minute),
+		JobSavedSearchAlertsInterval:      GetEnvDuration("JOB_SAVED_SEARCH_ALERTS_INTERVAL", time.Hour),
+		JobCommentSummariesInterval:       GetEnvDuration("JOB_COMMENT_SUMMARIES_INTERVAL", 24*time.Hour),
+		RentalReminderWindow:              GetEnvDuration("RENTAL_REMINDER_WINDOW", 24*time.Hour),
+		TrendingWindow:                    GetEnvDuration("TRENDING_WINDOW", 30*24*time.Hour),
+		LateFeePerDay:                     GetEnvFloat("LATE_FEE_PER_DAY", 1.00),
+		HomeFeedBudget:                    GetEnvDuration("HOME_FEED_BUDGET", 500*time.Millisecond),
+
+		PaymentProvider:     GetEnv("PAYMENT_PROVIDER", "fake"),
+		PaymentCurrency:     GetEnv("PAYMENT_CURRENCY", "usd"),
+		StripeSecretKey:     GetEnv("STRIPE_SECRET_KEY", ""),
+		StripeWebhookSecret: GetEnv("STRIPE_WEBHOOK_SECRET", ""),
+
+		LoyaltyPointsPerRental:  GetEnvInt("LOYALTY_POINTS_PER_RENTAL", 10),
+		LoyaltyPointsPerComment: GetEnvInt("LOYALTY_POINTS_PER_COMMENT", 5),
+		LoyaltyPointsPerDollar:  GetEnvInt("LOYALTY_POINTS_PER_DOLLAR", 100),
+
+		ShortLinkTarget: GetEnv("SHORT_LINK_TARGET", "/api/v1/films/{id}"),
+		PublicBaseURL:   publicBaseURL,
+
+		SitemapFilmURL:         GetEnv("SITEMAP_FILM_URL", publicBaseURL+"/api/v1/films/{id}"),
+		SitemapRefreshInterval: GetEnvDuration("SITEMAP_REFRESH_INTERVAL", 24*time.Hour),
+		CategoryStatsTTL:       GetEnvDuration("CATEGORY_STATS_TTL", 5*time.Minute),
+		CommentSummaryTTL:      GetEnvDuration("COMMENT_SUMMARY_TTL", time.Hour),
+		CustomerExportTTL:      GetEnvDuration("CUSTOMER_EXPORT_TTL", 24*time.Hour),
+		SignedURLSecret:        GetEnv("SIGNED_URL_SECRET", ""),
+		SignedURLTTL:           GetEnvDuration("SIGNED_URL_TTL", 24*time.Hour),
+		AttachmentDir:          GetEnv("ATTACHMENT_DIR", ""),
+		AttachmentMaxBytes:     GetEnvInt("ATTACHMENT_MAX_BYTES", 5<<20),
+		AttachmentScanCommand:  GetEnv("ATTACHMENT_SCAN_COMMAND", ""),
+
+		WebhookTimeout:           GetEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookSecretGrace:       GetEnvDuration("WEBHOOK_SECRET_GRACE", 24*time.Hour),
+		WebhookDeliveryRetention: GetEnvDuration("WEBHOOK_DELIVERY_RETENTION", 30*24*time.Hour),
+
+		GRPCPort:     GetEnv("GRPC_PORT", "9090"),
+		GRPCAPIToken: GetEnv("GRPC_API_TOKEN", ""),
+
+		MaintenanceMode: GetEnvBool("MAINTENANCE_MODE", false),
+
+		LogLevel:  GetEnv("LOG_LEVEL", "info"),
+		LogFormat: GetEnv("LOG_FORMAT", "text"),
+
+		SentryDSN:         GetEnv("SENTRY_DSN", ""),
+		SentryEnvironment: GetEnv("SENTRY_ENVIRONMENT", "development"),
+
+		AccessLogSkipHealthChecks: GetEnvBool("ACCESS_LOG_SKIP_HEALTH_CHECKS", true),
+
+		MaxInFlightRequests:  GetEnvInt("MAX_IN_FLIGHT_REQUESTS", 200),
+		LoadShedQueueTimeout: GetEnvDuration("LOAD_SHED_QUEUE_TIMEOUT", 250*time.Millisecond),
+		HandlerTimeout:       GetEnvDuration("HANDLER_TIMEOUT", 10*time.Second),
+
+		CompressionEnabled:     GetEnvBool("COMPRESSION_ENABLED", true),
+		CompressionEncodings:   GetEnvList("COMPRESSION_ENCODINGS", []string{"br", "gzip"}),
+		CompressionGzipLevel:   GetEnvInt("COMPRESSION_GZIP_LEVEL", 5),
+		CompressionBrotliLevel: GetEnvInt("COMPRESSION_BROTLI_LEVEL", 4),
+		CompressionMinSize:     GetEnvInt("COMPRESSION_MIN_SIZE", 1024),
+		CompressionSkipTypes: GetEnvList("COMPRESSION_SKIP_TYPES", []string{
+			"image/", "video/", "audio/", "font/woff2", "application/zip", "application/gzip",
+		}),
 	}
 }
 
@@ -31,3 +375,54 @@ func GetEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// GetEnvBool gets a boolean environment variable or returns a default value
+// when it is unset or cannot be parsed.
+func GetEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetEnvInt gets an integer environment variable or returns a default value
+// when it is unset or cannot be parsed.
+func GetEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetEnvFloat gets a decimal environment variable or returns a default value
+// when it is unset or cannot be parsed.
+func GetEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetEnvList gets a comma-separated environment variable as its trimmed,
+// non-empty entries, or returns a default value when it is unset or empty.
+func GetEnvList(key string, defaultValue []string) []string {
+	var values []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
+// GetEnvDuration gets a duration environment variable (e.g. "30s") or returns
+// a default value when it is unset or cannot be parsed.
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
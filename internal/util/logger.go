@@ -0,0 +1,27 @@
+package util //nolint:revive //Package name is fine IMO
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// NewLogHandler builds the slog handler selected by the LOG_LEVEL
+// (debug|info|warn|error) and LOG_FORMAT (text|json) settings.
+func NewLogHandler(w io.Writer, level, format string) (slog.Handler, error) {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	opts := &slog.HandlerOptions{Level: logLevel}
+	switch strings.ToLower(format) {
+	case "text":
+		return slog.NewTextHandler(w, opts), nil
+	case "json":
+		return slog.NewJSONHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be text or json", format)
+	}
+}
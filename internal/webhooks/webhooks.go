@@ -0,0 +1,284 @@
+// Package webhooks delivers signed event notifications to subscribed HTTP
+// endpoints through the background job queue.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+// Events that can be subscribed to. Keep in sync with the oneof validation on
+// models.WebhookSubscriptionRequest.
+const (
+	EventCommentCreated = "comment.created"
+	EventFilmPublished  = "film.published"
+)
+
+// Background job kinds; the queue must route them to the Dispatcher's
+// handlers.
+const (
+	FanoutJobKind  = "webhooks.fanout"
+	DeliverJobKind = "webhooks.deliver"
+)
+
+// Headers sent with every delivery. The signature header holds one
+// "v1=<hex HMAC-SHA256>" entry per valid secret, comma separated, computed
+// over "<timestamp>.<body>".
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// defaultTimeout bounds each delivery request when no client is configured.
+const defaultTimeout = 10 * time.Second
+
+// maxErrorBody is how much of a failed response body is kept for debugging.
+const maxErrorBody = 512
+
+// Enqueuer persists a background job for asynchronous processing.
+type Enqueuer interface {
+	Enqueue(kind string, payload any) error
+}
+
+// Store records deliveries and their outcomes.
+type Store interface {
+	CreateDeliveries(ctx context.Context, event string, payload []byte) ([]int64, error)
+	GetDeliveryTarget(ctx context.Context, deliveryID int64) (*models.WebhookDeliveryTarget, error)
+	RecordDeliveryAttempt(
+		ctx context.Context,
+		deliveryID int64,
+		status string,
+		responseStatus *int,
+		lastError *string,
+	) error
+}
+
+// fanoutJob is the payload of a FanoutJobKind job.
+type fanoutJob struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// deliverJob is the payload of a DeliverJobKind job.
+type deliverJob struct {
+	DeliveryID int64 `json:"delivery_id"`
+}
+
+// envelope is the JSON body posted to subscribers.
+type envelope struct {
+	ID        int64           `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Dispatcher publishes events to webhook subscribers.
+type Dispatcher struct {
+	store  Store
+	queue  Enqueuer
+	client *http.Client
+}
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithHTTPClient sends deliveries with client instead of a default client
+// with a 10 second timeout.
+func WithHTTPClient(client *http.Client) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// NewDispatcher creates a dispatcher recording deliveries in store and
+// sending them through queue.
+func NewDispatcher(store Store, queue Enqueuer, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		store:  store,
+		queue:  queue,
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Publish queues event with data for every subscribed endpoint. Subscribers
+// are looked up when the job runs, off the request path.
+func (d *Dispatcher) Publish(event string, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error encoding webhook data: %w", err)
+	}
+	if err = d.queue.Enqueue(FanoutJobKind, fanoutJob{Event: event, Data: encoded}); err != nil {
+		return fmt.Errorf("error queueing webhook event: %w", err)
+	}
+	return nil
+}
+
+// Replay queues another attempt at a delivery.
+func (d *Dispatcher) Replay(deliveryID int64) error {
+	if err := d.queue.Enqueue(DeliverJobKind, deliverJob{DeliveryID: deliveryID}); err != nil {
+		return fmt.Errorf("error queueing webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// HandleFanoutJob records a delivery per subscriber and queues each one. It
+// is the job handler for FanoutJobKind.
+func (d *Dispatcher) HandleFanoutJob(ctx context.Context, payload json.RawMessage) error {
+	var job fanoutJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("error decoding webhook fanout job: %w", err)
+	}
+
+	deliveryIDs, err := d.store.CreateDeliveries(ctx, job.Event, job.Data)
+	if err != nil {
+		return err
+	}
+
+	// Deliveries already exist, so a failure to queue one is recorded on it
+	// for replay rather than retrying the whole fanout.
+	for _, deliveryID := range deliveryIDs {
+		if queueErr := d.Replay(deliveryID); queueErr != nil {
+			slog.Error("Failed to queue webhook delivery", "deliveryID", deliveryID, "error", queueErr)
+			d.record(ctx, deliveryID, models.WebhookDeliveryFailed, nil, queueErr.Error())
+		}
+	}
+	return nil
+}
+
+// HandleDeliveryJob sends one delivery and records the outcome. It is the job
+// handler for DeliverJobKind; a failed request returns an error so the queue
+// retries it.
+func (d *Dispatcher) HandleDeliveryJob(ctx context.Context, payload json.RawMessage) error {
+	var job deliverJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("error decoding webhook delivery job: %w", err)
+	}
+
+	target, err := d.store.GetDeliveryTarget(ctx, job.DeliveryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookDeliveryNotFound) {
+			// Purged, or its subscription was deleted.
+			return nil
+		}
+		return err
+	}
+	if target.Delivery.Status == models.WebhookDeliverySucceeded {
+		return nil
+	}
+	if !target.Active {
+		d.record(ctx, job.DeliveryID, models.WebhookDeliveryFailed, nil, "subscription is inactive")
+		return nil
+	}
+
+	responseStatus, sendErr := d.send(ctx, target)
+	if sendErr != nil {
+		d.record(ctx, job.DeliveryID, models.WebhookDeliveryFailed, responseStatus, sendErr.Error())
+		return sendErr
+	}
+	d.record(ctx, job.DeliveryID, models.WebhookDeliverySucceeded, responseStatus, "")
+	return nil
+}
+
+// send posts a delivery to its endpoint, returning the response status if
+// one was received.
+func (d *Dispatcher) send(ctx context.Context, target *models.WebhookDeliveryTarget) (*int, error) {
+	delivery := target.Delivery
+	body, err := json.Marshal(envelope{
+		ID:        delivery.ID,
+		Event:     delivery.Event,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding webhook body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building webhook request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	signatures := []string{Sign(target.Secret, timestamp, body)}
+	if target.PreviousSecret != nil {
+		signatures = append(signatures, Sign(*target.PreviousSecret, timestamp, body))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, strings.Join(signatures, ","))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	if status < 200 || status >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &status, fmt.Errorf("webhook endpoint returned %d: %s", status, strings.TrimSpace(string(detail)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return &status, nil
+}
+
+// record stores a delivery attempt, logging rather than returning failures
+// so the attempt's own outcome decides whether the job is retried. It is
+// stored even when the attempt used up the job's time.
+func (d *Dispatcher) record(
+	ctx context.Context,
+	deliveryID int64,
+	status string,
+	responseStatus *int,
+	lastError string,
+) {
+	var errPtr *string
+	if lastError != "" {
+		errPtr = &lastError
+	}
+	err := d.store.RecordDeliveryAttempt(context.WithoutCancel(ctx), deliveryID, status, responseStatus, errPtr)
+	if err != nil {
+		slog.Error("Failed to record webhook delivery attempt", "deliveryID", deliveryID, "error", err)
+	}
+}
+
+// Sign returns the signature header entry for body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret generates a random signing secret.
+func NewSecret() (string, error) {
+	raw := make([]byte, 32) //nolint:mnd // 256-bit secret
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("error generating webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(raw), nil
+}
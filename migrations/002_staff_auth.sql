@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- bcrypt hashes are 60 characters, longer than the original SHA-1 column.
+ALTER TABLE staff ALTER COLUMN password TYPE VARCHAR(255);
+CREATE UNIQUE INDEX IF NOT EXISTS staff_username_key ON staff (username);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS staff_username_key;
+ALTER TABLE staff ALTER COLUMN password TYPE VARCHAR(40) USING LEFT(password, 40);
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS customer_credentials (
+    customer_id INTEGER PRIMARY KEY,
+    email VARCHAR(50) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_customer_credentials_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS customer_credentials_email_key ON customer_credentials (LOWER(email));
+
+-- Comments posted by a logged-in customer are linked to them and shown as verified.
+ALTER TABLE film_comments ADD COLUMN IF NOT EXISTS customer_id INTEGER
+    REFERENCES customer(customer_id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE film_comments DROP COLUMN IF EXISTS customer_id;
+DROP TABLE IF EXISTS customer_credentials;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE film_comments ADD COLUMN IF NOT EXISTS parent_id INTEGER
+    REFERENCES film_comments(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_film_comments_parent_id ON film_comments (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_film_comments_parent_id;
+ALTER TABLE film_comments DROP COLUMN IF EXISTS parent_id;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rental ADD COLUMN IF NOT EXISTS overdue BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE rental ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_rental_open ON rental (rental_date) WHERE return_date IS NULL;
+
+-- One row per scheduled job so replicas can tell when it last ran.
+CREATE TABLE IF NOT EXISTS scheduled_job_runs (
+    name VARCHAR(100) PRIMARY KEY,
+    last_started_at TIMESTAMP NOT NULL
+);
+
+-- Most rented films, recomputed periodically by the refresh-trending job.
+CREATE TABLE IF NOT EXISTS trending_films (
+    film_id INTEGER PRIMARY KEY REFERENCES film(film_id) ON DELETE CASCADE,
+    rank INTEGER NOT NULL,
+    rental_count INTEGER NOT NULL,
+    refreshed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS trending_films;
+DROP TABLE IF EXISTS scheduled_job_runs;
+DROP INDEX IF EXISTS idx_rental_open;
+ALTER TABLE rental DROP COLUMN IF EXISTS reminder_sent_at;
+ALTER TABLE rental DROP COLUMN IF EXISTS overdue;
+-- +goose StatementEnd
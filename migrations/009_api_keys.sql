@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    tier VARCHAR(20) NOT NULL,
+    -- Only a SHA-256 hash of the key is stored; the prefix identifies the key
+    -- in listings.
+    key_hash CHAR(64) NOT NULL,
+    key_prefix VARCHAR(20) NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash),
+    CONSTRAINT chk_api_keys_tier CHECK (tier IN ('free', 'partner', 'internal'))
+);
+
+-- One row per key per calendar month (UTC), counting accepted requests.
+CREATE TABLE IF NOT EXISTS api_key_usage (
+    api_key_id INTEGER NOT NULL,
+    period DATE NOT NULL,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (api_key_id, period),
+    CONSTRAINT fk_api_key_usage_api_key_id FOREIGN KEY (api_key_id)
+        REFERENCES api_keys(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_key_usage;
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
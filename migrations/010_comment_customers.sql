@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Comments from logged-in customers are linked through customer_id and no
+-- longer store a free-text name; only guest comments carry customer_name.
+ALTER TABLE film_comments ALTER COLUMN customer_name DROP NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_film_comments_customer_id ON film_comments (customer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_film_comments_customer_id;
+UPDATE film_comments fc
+SET customer_name = COALESCE(
+    (SELECT c.first_name || ' ' || c.last_name FROM customer c WHERE c.customer_id = fc.customer_id), '')
+WHERE customer_name IS NULL;
+ALTER TABLE film_comments ALTER COLUMN customer_name SET NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A row here blocks new comments on the film.
+CREATE TABLE IF NOT EXISTS film_comment_locks (
+    film_id INTEGER PRIMARY KEY,
+    locked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_film_comment_locks_film_id FOREIGN KEY (film_id)
+        REFERENCES film(film_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS film_comment_locks;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Retired copies are kept for their rental history but are no longer stocked.
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS retired_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE inventory DROP COLUMN IF EXISTS retired_at;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Audit trail of copies moved between stores.
+CREATE TABLE IF NOT EXISTS inventory_transfers (
+    id SERIAL PRIMARY KEY,
+    inventory_id INTEGER NOT NULL,
+    from_store_id INTEGER NOT NULL,
+    to_store_id INTEGER NOT NULL,
+    reason VARCHAR(255),
+    transferred_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_inventory_transfers_inventory_id FOREIGN KEY (inventory_id)
+        REFERENCES inventory(inventory_id) ON DELETE CASCADE,
+    CONSTRAINT fk_inventory_transfers_from_store_id FOREIGN KEY (from_store_id)
+        REFERENCES store(store_id),
+    CONSTRAINT fk_inventory_transfers_to_store_id FOREIGN KEY (to_store_id)
+        REFERENCES store(store_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_transfers_inventory_id
+    ON inventory_transfers (inventory_id, transferred_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS inventory_transfers;
+-- +goose StatementEnd
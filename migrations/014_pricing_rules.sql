@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Discount rules applied to film rental rates. A percent_off rule takes
+-- percent_off off the rate; a bundle rule charges for bundle_paid of every
+-- bundle_quantity films rented together. The remaining columns narrow where
+-- and when a rule applies; NULL means no restriction.
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    percent_off NUMERIC(5,2),
+    bundle_quantity INTEGER,
+    bundle_paid INTEGER,
+    category_id INTEGER,
+    store_id INTEGER,
+    weekends_only BOOLEAN NOT NULL DEFAULT false,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_pricing_rules_kind CHECK (
+        (kind = 'percent_off' AND percent_off > 0 AND percent_off <= 100)
+        OR (kind = 'bundle' AND bundle_paid >= 1 AND bundle_quantity > bundle_paid)
+    ),
+    CONSTRAINT fk_pricing_rules_category_id FOREIGN KEY (category_id)
+        REFERENCES category(category_id) ON DELETE CASCADE,
+    CONSTRAINT fk_pricing_rules_store_id FOREIGN KEY (store_id)
+        REFERENCES store(store_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pricing_rules;
+-- +goose StatementEnd
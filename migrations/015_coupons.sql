@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Coupon codes, stored upper-case. A coupon takes either percent_off or a
+-- fixed amount_off off a checkout total. max_redemptions caps its uses in
+-- total and max_per_customer its uses by one customer; NULL means no limit.
+CREATE TABLE IF NOT EXISTS coupons (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    percent_off NUMERIC(5,2),
+    amount_off NUMERIC(5,2),
+    max_redemptions INTEGER CHECK (max_redemptions > 0),
+    max_per_customer INTEGER CHECK (max_per_customer > 0),
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_coupons_discount CHECK (
+        (percent_off > 0 AND percent_off <= 100 AND amount_off IS NULL)
+        OR (amount_off > 0 AND percent_off IS NULL)
+    )
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id SERIAL PRIMARY KEY,
+    coupon_id INTEGER NOT NULL,
+    customer_id INTEGER NOT NULL,
+    amount NUMERIC(7,2) NOT NULL,
+    discount NUMERIC(7,2) NOT NULL,
+    redeemed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_coupon_redemptions_coupon_id FOREIGN KEY (coupon_id)
+        REFERENCES coupons(id) ON DELETE CASCADE,
+    CONSTRAINT fk_coupon_redemptions_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon_customer
+    ON coupon_redemptions(coupon_id, customer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupons;
+-- +goose StatementEnd
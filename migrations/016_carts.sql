@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Films a customer means to rent, until checkout turns them into rentals.
+CREATE TABLE IF NOT EXISTS cart_items (
+    customer_id INTEGER NOT NULL,
+    film_id INTEGER NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (customer_id, film_id),
+    CONSTRAINT fk_cart_items_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_cart_items_film_id FOREIGN KEY (film_id)
+        REFERENCES film(film_id) ON DELETE CASCADE
+);
+
+-- A completed checkout, with what was charged for it.
+CREATE TABLE IF NOT EXISTS checkouts (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL,
+    store_id INTEGER NOT NULL,
+    subtotal NUMERIC(7,2) NOT NULL,
+    discount NUMERIC(7,2) NOT NULL DEFAULT 0,
+    total NUMERIC(7,2) NOT NULL,
+    coupon_redemption_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_checkouts_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_checkouts_store_id FOREIGN KEY (store_id)
+        REFERENCES store(store_id),
+    CONSTRAINT fk_checkouts_coupon_redemption_id FOREIGN KEY (coupon_redemption_id)
+        REFERENCES coupon_redemptions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkouts_customer_id ON checkouts(customer_id);
+
+-- The rentals a checkout created, at the rate charged for each.
+CREATE TABLE IF NOT EXISTS checkout_rentals (
+    checkout_id INTEGER NOT NULL,
+    rental_id INTEGER NOT NULL,
+    rate NUMERIC(5,2) NOT NULL,
+    PRIMARY KEY (checkout_id, rental_id),
+    CONSTRAINT fk_checkout_rentals_checkout_id FOREIGN KEY (checkout_id)
+        REFERENCES checkouts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_checkout_rentals_rental_id FOREIGN KEY (rental_id)
+        REFERENCES rental(rental_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS checkout_rentals;
+DROP TABLE IF EXISTS checkouts;
+DROP TABLE IF EXISTS cart_items;
+-- +goose StatementEnd
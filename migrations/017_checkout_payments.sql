@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Payment collected for a checkout through a payment provider. Each checkout
+-- has at most one, and payment attempts retry the same provider payment.
+-- Once it succeeds, a row per rental is also added to payment.
+CREATE TABLE IF NOT EXISTS checkout_payments (
+    id SERIAL PRIMARY KEY,
+    checkout_id INTEGER NOT NULL UNIQUE,
+    provider VARCHAR(20) NOT NULL,
+    provider_payment_id VARCHAR(255) NOT NULL,
+    client_secret VARCHAR(255) NOT NULL,
+    amount NUMERIC(7,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'succeeded', 'failed')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_checkout_payments_provider_payment UNIQUE (provider, provider_payment_id),
+    CONSTRAINT fk_checkout_payments_checkout_id FOREIGN KEY (checkout_id)
+        REFERENCES checkouts(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS checkout_payments;
+-- +goose StatementEnd
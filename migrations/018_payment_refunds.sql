@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Amount of each checkout payment returned so far, including refunds still
+-- pending with the provider, so concurrent refunds cannot exceed it.
+ALTER TABLE checkout_payments ADD COLUMN refunded_amount NUMERIC(7,2) NOT NULL DEFAULT 0;
+
+-- Refunds of checkout payments. Each is requested with an idempotency key,
+-- and a repeated request with the same key returns the same refund. Once it
+-- succeeds, a negative row per rental is also added to payment.
+CREATE TABLE IF NOT EXISTS payment_refunds (
+    id SERIAL PRIMARY KEY,
+    payment_id INTEGER NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    amount NUMERIC(7,2) NOT NULL CHECK (amount > 0),
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    provider_refund_id VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'succeeded', 'failed')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_payment_refunds_idempotency_key UNIQUE (payment_id, idempotency_key),
+    CONSTRAINT fk_payment_refunds_payment_id FOREIGN KEY (payment_id)
+        REFERENCES checkout_payments(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payment_refunds;
+ALTER TABLE checkout_payments DROP COLUMN IF EXISTS refunded_amount;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every change to a customer's loyalty points and rental credit. Balances
+-- are the sums of points and credit over a customer's entries. Awards name
+-- the rental or comment they were earned for, which can earn points once.
+CREATE TABLE IF NOT EXISTS loyalty_ledger (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL,
+    points INTEGER NOT NULL DEFAULT 0,
+    credit NUMERIC(7,2) NOT NULL DEFAULT 0,
+    reason VARCHAR(20) NOT NULL
+        CHECK (reason IN ('rental', 'comment', 'redemption', 'checkout')),
+    reference_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_loyalty_ledger_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_customer ON loyalty_ledger (customer_id, created_at DESC, id DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_loyalty_ledger_reference ON loyalty_ledger (reason, reference_id)
+    WHERE reference_id IS NOT NULL;
+
+-- Rental credit spent on each checkout, taken off its total after any
+-- coupon.
+ALTER TABLE checkouts ADD COLUMN credit_applied NUMERIC(7,2) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE checkouts DROP COLUMN IF EXISTS credit_applied;
+DROP TABLE IF EXISTS loyalty_ledger;
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Gift cards bought by customers through a payment provider. Codes are
+-- stored only as SHA-256 hashes, with their last four characters kept to
+-- identify a card. A card becomes active, and can be spent, once its
+-- purchase payment succeeds.
+CREATE TABLE IF NOT EXISTS gift_cards (
+    id SERIAL PRIMARY KEY,
+    code_hash CHAR(64) NOT NULL UNIQUE,
+    last_four CHAR(4) NOT NULL,
+    purchaser_id INTEGER NOT NULL,
+    amount NUMERIC(7,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'active', 'failed')),
+    provider VARCHAR(20) NOT NULL,
+    provider_payment_id VARCHAR(255),
+    client_secret VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_gift_cards_provider_payment UNIQUE (provider, provider_payment_id),
+    CONSTRAINT fk_gift_cards_purchaser_id FOREIGN KEY (purchaser_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+
+-- Every change to a gift card's balance: the purchased amount when the card
+-- is activated, and the amount spent on each checkout. A card's balance is
+-- the sum of its entries.
+CREATE TABLE IF NOT EXISTS gift_card_ledger (
+    id SERIAL PRIMARY KEY,
+    gift_card_id INTEGER NOT NULL,
+    amount NUMERIC(7,2) NOT NULL,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('purchase', 'checkout')),
+    checkout_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_gift_card_ledger_gift_card_id FOREIGN KEY (gift_card_id)
+        REFERENCES gift_cards(id) ON DELETE CASCADE,
+    CONSTRAINT fk_gift_card_ledger_checkout_id FOREIGN KEY (checkout_id)
+        REFERENCES checkouts(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_card_ledger_gift_card ON gift_card_ledger (gift_card_id);
+
+-- Gift card balance spent on each checkout, taken off its total after any
+-- coupon and loyalty credit.
+ALTER TABLE checkouts ADD COLUMN gift_card_applied NUMERIC(7,2) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE checkouts DROP COLUMN IF EXISTS gift_card_applied;
+DROP TABLE IF EXISTS gift_card_ledger;
+DROP TABLE IF EXISTS gift_cards;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Named groups of films, such as a franchise or series, with their films
+-- kept in order by position.
+CREATE TABLE IF NOT EXISTS collections (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_collections_name ON collections (LOWER(name));
+
+CREATE TABLE IF NOT EXISTS collection_films (
+    collection_id INTEGER NOT NULL,
+    film_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (collection_id, film_id),
+    CONSTRAINT uq_collection_films_position UNIQUE (collection_id, position),
+    CONSTRAINT fk_collection_films_collection_id FOREIGN KEY (collection_id)
+        REFERENCES collections(id) ON DELETE CASCADE,
+    CONSTRAINT fk_collection_films_film_id FOREIGN KEY (film_id)
+        REFERENCES film(film_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_films_film ON collection_films (film_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS collection_films;
+DROP TABLE IF EXISTS collections;
+-- +goose StatementEnd
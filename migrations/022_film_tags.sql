@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Free-form tags on films, kept apart from the fixed category table. Tags
+-- are stored lower case, so "Cult Classic" and "cult classic" are one tag.
+CREATE TABLE IF NOT EXISTS film_tags (
+    film_id INTEGER NOT NULL,
+    tag VARCHAR(50) NOT NULL CHECK (tag = LOWER(tag) AND tag <> ''),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (film_id, tag),
+    CONSTRAINT fk_film_tags_film_id FOREIGN KEY (film_id)
+        REFERENCES film(film_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_film_tags_tag ON film_tags (tag);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS film_tags;
+-- +goose StatementEnd
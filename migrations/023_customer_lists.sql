@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Named lists of films curated by customers, such as "Best 80s Horror".
+-- Public lists can be viewed by anyone; private ones only by their owner.
+CREATE TABLE IF NOT EXISTS customer_lists (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    visibility VARCHAR(10) NOT NULL DEFAULT 'private' CHECK (visibility IN ('public', 'private')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_customer_lists_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_lists_customer ON customer_lists (customer_id);
+
+-- Positions are checked at commit, so entries can be reordered or shifted
+-- down after a removal in one statement.
+CREATE TABLE IF NOT EXISTS customer_list_entries (
+    list_id INTEGER NOT NULL,
+    film_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (list_id, film_id),
+    CONSTRAINT uq_customer_list_entries_position UNIQUE (list_id, position) DEFERRABLE INITIALLY DEFERRED,
+    CONSTRAINT fk_customer_list_entries_list_id FOREIGN KEY (list_id)
+        REFERENCES customer_lists(id) ON DELETE CASCADE,
+    CONSTRAINT fk_customer_list_entries_film_id FOREIGN KEY (film_id)
+        REFERENCES film(film_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS customer_list_entries;
+DROP TABLE IF EXISTS customer_lists;
+-- +goose StatementEnd
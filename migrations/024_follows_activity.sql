@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Customers following other customers, whose public activity appears in
+-- their feed.
+CREATE TABLE IF NOT EXISTS customer_follows (
+    follower_id INTEGER NOT NULL,
+    followed_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followed_id),
+    CHECK (follower_id <> followed_id),
+    CONSTRAINT fk_customer_follows_follower_id FOREIGN KEY (follower_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_customer_follows_followed_id FOREIGN KEY (followed_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_follows_followed ON customer_follows (followed_id);
+
+-- Public activity by customers, written by the service layer as it happens.
+-- Entries go with the comment, film, or list they are about.
+CREATE TABLE IF NOT EXISTS customer_activity (
+    id BIGSERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL,
+    kind VARCHAR(30) NOT NULL CHECK (kind IN ('comment_posted', 'list_published', 'list_entry_added')),
+    film_id INTEGER,
+    list_id INTEGER,
+    comment_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_customer_activity_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_customer_activity_film_id FOREIGN KEY (film_id)
+        REFERENCES film(film_id) ON DELETE CASCADE,
+    CONSTRAINT fk_customer_activity_list_id FOREIGN KEY (list_id)
+        REFERENCES customer_lists(id) ON DELETE CASCADE,
+    CONSTRAINT fk_customer_activity_comment_id FOREIGN KEY (comment_id)
+        REFERENCES film_comments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_activity_customer ON customer_activity (customer_id, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS customer_activity;
+DROP TABLE IF EXISTS customer_follows;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Short links to films for marketing campaigns, counting the clicks on
+-- each.
+CREATE TABLE IF NOT EXISTS short_links (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(16) NOT NULL UNIQUE,
+    film_id INTEGER NOT NULL,
+    campaign VARCHAR(100),
+    clicks BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_clicked_at TIMESTAMP,
+    CONSTRAINT fk_short_links_film_id FOREIGN KEY (film_id)
+        REFERENCES film(film_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_short_links_film ON short_links (film_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS short_links;
+-- +goose StatementEnd
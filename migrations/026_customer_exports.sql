@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Exports of a customer's personal data, built by a background job. The
+-- file is kept here until a newer export of the same format replaces it.
+CREATE TABLE IF NOT EXISTS customer_exports (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('json', 'zip')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'ready')),
+    data BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    CONSTRAINT fk_customer_exports_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_exports_customer ON customer_exports (customer_id, format, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS customer_exports;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Guest names on comments are stored encrypted, which is longer than the
+-- names themselves.
+ALTER TABLE film_comments ALTER COLUMN customer_name TYPE TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE film_comments ALTER COLUMN customer_name TYPE VARCHAR(255);
+-- +goose StatementEnd
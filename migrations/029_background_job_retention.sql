@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Finished jobs are purged once they are older than the retention period.
+CREATE INDEX IF NOT EXISTS idx_background_jobs_finished
+    ON background_jobs (updated_at) WHERE status IN ('succeeded', 'dead');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_background_jobs_finished;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A film's comments are paged newest first, by offset or after a cursor.
+CREATE INDEX IF NOT EXISTS idx_film_comments_film_created
+    ON film_comments (film_id, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_film_comments_film_created;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Customers waiting to hear when a copy of a film comes back. An alert is
+-- pending until notified_at is set, and a customer has at most one pending
+-- alert per film.
+CREATE TABLE IF NOT EXISTS film_availability_alerts (
+    id SERIAL PRIMARY KEY,
+    film_id INTEGER NOT NULL,
+    customer_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    notified_at TIMESTAMP,
+    CONSTRAINT fk_film_availability_alerts_film_id FOREIGN KEY (film_id)
+        REFERENCES film(film_id) ON DELETE CASCADE,
+    CONSTRAINT fk_film_availability_alerts_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_film_availability_alerts_pending
+    ON film_availability_alerts (film_id, customer_id) WHERE notified_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS film_availability_alerts;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A category may sit under a parent, such as Animation under Family. Films
+-- filtered by a category can include those in its subcategories.
+ALTER TABLE category ADD COLUMN IF NOT EXISTS parent_id INTEGER
+    REFERENCES category(category_id) ON DELETE SET NULL;
+ALTER TABLE category ADD CONSTRAINT category_parent_not_self CHECK (parent_id <> category_id);
+CREATE INDEX IF NOT EXISTS idx_category_parent_id ON category (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_category_parent_id;
+ALTER TABLE category DROP CONSTRAINT IF EXISTS category_parent_not_self;
+ALTER TABLE category DROP COLUMN IF EXISTS parent_id;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Draft and archived films are hidden from the public catalog; existing
+-- films stay published.
+ALTER TABLE film ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CONSTRAINT chk_film_status CHECK (status IN ('draft', 'published', 'archived'));
+CREATE INDEX IF NOT EXISTS idx_film_status ON film (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_film_status;
+ALTER TABLE film DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
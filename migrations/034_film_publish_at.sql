@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A draft film may be scheduled to publish itself at publish_at.
+ALTER TABLE film ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_film_publish_at ON film (publish_at)
+    WHERE status = 'draft' AND publish_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_film_publish_at;
+ALTER TABLE film DROP COLUMN IF EXISTS publish_at;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Title searches that found no films, one row per lower-cased term, so
+-- admins can see what customers look for and the catalog lacks.
+CREATE TABLE IF NOT EXISTS film_search_misses (
+    term VARCHAR(100) PRIMARY KEY,
+    searches INTEGER NOT NULL DEFAULT 1,
+    first_searched_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_searched_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_film_search_misses_searches
+    ON film_search_misses (searches DESC, last_searched_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS film_search_misses;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- published_at is when a film last entered the catalog, so saved searches
+-- can tell new films from old ones that were edited. Films published before
+-- it was tracked have none.
+ALTER TABLE film ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+ALTER TABLE film ALTER COLUMN published_at SET DEFAULT NOW();
+CREATE INDEX IF NOT EXISTS idx_film_published_at ON film (published_at) WHERE status = 'published';
+
+-- A customer's saved film filters. Searches with notify set are checked by a
+-- scheduled job for films published since last_checked_at.
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    filters JSONB NOT NULL,
+    notify BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_checked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_saved_searches_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_customer_id ON saved_searches (customer_id);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_notify ON saved_searches (last_checked_at) WHERE notify;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS saved_searches;
+DROP INDEX IF EXISTS idx_film_published_at;
+ALTER TABLE film DROP COLUMN IF EXISTS published_at;
+-- +goose StatementEnd
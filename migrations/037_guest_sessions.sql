@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Anonymous sessions issued to guests, so what a guest does before signing
+-- up can be correlated and merged into the account they register.
+CREATE TABLE IF NOT EXISTS guest_sessions (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    merged_customer_id INTEGER,
+    merged_at TIMESTAMP,
+    CONSTRAINT fk_guest_sessions_merged_customer_id FOREIGN KEY (merged_customer_id)
+        REFERENCES customer(customer_id) ON DELETE SET NULL
+);
+
+-- The comments a guest posted with their session token.
+CREATE TABLE IF NOT EXISTS guest_session_comments (
+    guest_session_id INTEGER NOT NULL,
+    comment_id INTEGER NOT NULL,
+    PRIMARY KEY (guest_session_id, comment_id),
+    CONSTRAINT fk_guest_session_comments_session_id FOREIGN KEY (guest_session_id)
+        REFERENCES guest_sessions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_guest_session_comments_comment_id FOREIGN KEY (comment_id)
+        REFERENCES film_comments(id) ON DELETE CASCADE
+);
+
+-- The films a guest wants to watch, moved to a customer list on sign-up.
+CREATE TABLE IF NOT EXISTS guest_watchlist (
+    guest_session_id INTEGER NOT NULL,
+    film_id INTEGER NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (guest_session_id, film_id),
+    CONSTRAINT fk_guest_watchlist_session_id FOREIGN KEY (guest_session_id)
+        REFERENCES guest_sessions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_guest_watchlist_film_id FOREIGN KEY (film_id)
+        REFERENCES film(film_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS guest_watchlist;
+DROP TABLE IF EXISTS guest_session_comments;
+DROP TABLE IF EXISTS guest_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Login sessions of staff and customers. A session is kept alive by its
+-- refresh token, rotated on every use; the token it replaced is kept so its
+-- reuse, a sign it was stolen, can revoke the session. Access tokens name
+-- their session and stop working once it is revoked.
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    role VARCHAR(20) NOT NULL,
+    subject_id INTEGER NOT NULL,
+    refresh_token_hash CHAR(64) NOT NULL UNIQUE,
+    previous_token_hash CHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    refreshed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_previous_token ON auth_sessions (previous_token_hash);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_subject ON auth_sessions (role, subject_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS auth_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The device a login session was last refreshed from, so customers can tell
+-- their sessions apart when revoking them.
+ALTER TABLE auth_sessions ADD COLUMN IF NOT EXISTS user_agent VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE auth_sessions ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE auth_sessions DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE auth_sessions DROP COLUMN IF EXISTS user_agent;
+-- +goose StatementEnd
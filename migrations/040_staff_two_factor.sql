@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- TOTP second factors of staff accounts. The secret is encrypted with the
+-- PII key when one is configured. A factor is pending until its first code
+-- is confirmed; last_used_step refuses a code from being used twice.
+CREATE TABLE IF NOT EXISTS staff_two_factor (
+    staff_id INTEGER PRIMARY KEY,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_staff_two_factor_staff_id FOREIGN KEY (staff_id)
+        REFERENCES staff(staff_id) ON DELETE CASCADE
+);
+
+-- Single-use codes standing in for the authenticator when it is lost.
+CREATE TABLE IF NOT EXISTS staff_backup_codes (
+    id SERIAL PRIMARY KEY,
+    staff_id INTEGER NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    CONSTRAINT fk_staff_backup_codes_staff_id FOREIGN KEY (staff_id)
+        REFERENCES staff(staff_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_staff_backup_codes_staff ON staff_backup_codes (staff_id);
+
+-- Whether a login session was started with a second factor, so refreshed
+-- tokens keep saying so.
+ALTER TABLE auth_sessions ADD COLUMN IF NOT EXISTS two_factor BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE auth_sessions DROP COLUMN IF EXISTS two_factor;
+DROP TABLE IF EXISTS staff_backup_codes;
+DROP TABLE IF EXISTS staff_two_factor;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The customers a comment mentions as @<customer_id>, recorded when it is
+-- posted so they can be notified and shown with the comment.
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    comment_id INTEGER NOT NULL,
+    customer_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (comment_id, customer_id),
+    CONSTRAINT fk_comment_mentions_comment_id FOREIGN KEY (comment_id)
+        REFERENCES film_comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_mentions_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_mentions_customer_id ON comment_mentions (customer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS comment_mentions;
+-- +goose StatementEnd
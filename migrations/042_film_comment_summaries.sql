@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each film's comment summary, recomputed nightly by the
+-- refresh-comment-summaries job. rating_average_comments is the average
+-- comment count of films with the same rating when it was computed.
+CREATE TABLE IF NOT EXISTS film_comment_summaries (
+    film_id INTEGER PRIMARY KEY REFERENCES film(film_id) ON DELETE CASCADE,
+    rating VARCHAR(10) NOT NULL DEFAULT '',
+    comment_count INTEGER NOT NULL,
+    latest_comment_at TIMESTAMP,
+    top_keywords TEXT[] NOT NULL DEFAULT '{}',
+    rating_average_comments NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS film_comment_summaries;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The storage keys of the image attached to a comment and its thumbnail.
+ALTER TABLE film_comments ADD COLUMN IF NOT EXISTS attachment_key VARCHAR(100);
+ALTER TABLE film_comments ADD COLUMN IF NOT EXISTS thumbnail_key VARCHAR(100);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE film_comments DROP COLUMN IF EXISTS thumbnail_key;
+ALTER TABLE film_comments DROP COLUMN IF EXISTS attachment_key;
+-- +goose StatementEnd
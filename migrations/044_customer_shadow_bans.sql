@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Customers whose comments are hidden from everyone but themselves.
+CREATE TABLE IF NOT EXISTS customer_shadow_bans (
+    customer_id INTEGER PRIMARY KEY,
+    banned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_customer_shadow_bans_customer_id FOREIGN KEY (customer_id)
+        REFERENCES customer(customer_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS customer_shadow_bans;
+-- +goose StatementEnd
@@ -20,6 +20,5 @@ import (
 	_ "github.com/DataDog/orchestrion" // integration
 
 	// HTTP-only instrumentation
-	_ "github.com/DataDog/dd-trace-go/contrib/gorilla/mux/v2" // integration
-	_ "github.com/DataDog/dd-trace-go/contrib/net/http/v2"    // integration
+	_ "github.com/DataDog/dd-trace-go/contrib/net/http/v2" // integration
 )
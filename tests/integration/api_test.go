@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -9,13 +10,15 @@ import (
 	"testing"
 	"time"
 
-	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/middleware"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
 	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/router"
 	"github.com/rxbenefits/go-hw/internal/service"
 )
 
@@ -23,15 +26,18 @@ type MockFilmRepository struct {
 	mock.Mock
 }
 
-func (m *MockFilmRepository) GetFilms(filters models.FilmFilters) (*models.FilmListResponse, error) {
+func (m *MockFilmRepository) GetFilms(
+	_ context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
 	args := m.Called(filters)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.FilmListResponse), args.Error(1)
+	return args.Get(0).(*pagination.Paginated[models.Film]), args.Error(1)
 }
 
-func (m *MockFilmRepository) GetFilmByID(filmID int) (*models.Film, error) {
+func (m *MockFilmRepository) GetFilmByID(_ context.Context, filmID int) (*models.Film, error) {
 	args := m.Called(filmID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -39,7 +45,7 @@ func (m *MockFilmRepository) GetFilmByID(filmID int) (*models.Film, error) {
 	return args.Get(0).(*models.Film), args.Error(1)
 }
 
-func (m *MockFilmRepository) GetCategories() ([]models.Category, error) {
+func (m *MockFilmRepository) GetCategories(_ context.Context) ([]models.Category, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Category), args.Error(1)
 }
@@ -48,7 +54,11 @@ type MockCommentRepository struct {
 	mock.Mock
 }
 
-func (m *MockCommentRepository) AddComment(filmID int, commentReq models.CommentRequest) (*models.Comment, error) {
+func (m *MockCommentRepository) AddComment(
+	_ context.Context,
+	filmID int,
+	commentReq models.CommentRequest,
+) (*models.Comment, error) {
 	args := m.Called(filmID, commentReq)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -56,9 +66,79 @@ func (m *MockCommentRepository) AddComment(filmID int, commentReq models.Comment
 	return args.Get(0).(*models.Comment), args.Error(1)
 }
 
-func (m *MockCommentRepository) GetCommentsByFilmID(filmID int) ([]models.Comment, error) {
+func (m *MockCommentRepository) SetCommentsLocked(
+	_ context.Context,
+	filmID int,
+	locked bool,
+) (*models.CommentLockStatus, error) {
+	args := m.Called(filmID, locked)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentLockStatus), args.Error(1)
+}
+
+func (m *MockCommentRepository) SetShadowBanned(
+	_ context.Context,
+	customerID int,
+	banned bool,
+) (*models.ShadowBanStatus, error) {
+	args := m.Called(customerID, banned)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShadowBanStatus), args.Error(1)
+}
+
+func (m *MockCommentRepository) IsShadowBanned(_ context.Context, customerID int) (bool, error) {
+	args := m.Called(customerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetCommentsByFilmID(
+	_ context.Context,
+	filmID int,
+	viewerID int,
+	limit int,
+	after *pagination.Cursor,
+) (*pagination.Paginated[models.Comment], error) {
+	args := m.Called(filmID, viewerID, limit, after)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.Comment]), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetCommentAuthor(_ context.Context, commentID int) (*models.CommentAuthor, error) {
+	args := m.Called(commentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentAuthor), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetMentionTargets(
+	_ context.Context,
+	customerIDs []int,
+) ([]models.CommentAuthor, error) {
+	args := m.Called(customerIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CommentAuthor), args.Error(1)
+}
+
+func (m *MockCommentRepository) RefreshCommentSummaries(_ context.Context, stopwords []string, keywordLimit int) error {
+	args := m.Called(stopwords, keywordLimit)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetCommentSummary(_ context.Context, filmID int) (*models.CommentSummary, error) {
 	args := m.Called(filmID)
-	return args.Get(0).([]models.Comment), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentSummary), args.Error(1)
 }
 
 type IntegrationTestSuite struct {
@@ -66,7 +146,7 @@ type IntegrationTestSuite struct {
 
 	mockFilmRepo    *MockFilmRepository
 	mockCommentRepo *MockCommentRepository
-	router          *mux.Router
+	router          *router.Mux
 	filmHandler     *handlers.FilmHandler
 }
 
@@ -76,27 +156,32 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 	suite.mockCommentRepo = new(MockCommentRepository)
 
 	// Initialize services with mock repositories
-	filmService := service.NewFilmService(suite.mockFilmRepo)
+	filmService := service.NewFilmService(suite.mockFilmRepo, pagination.DefaultLimits)
 	commentService := service.NewCommentService(suite.mockCommentRepo, suite.mockFilmRepo)
 
 	// Initialize handlers
-	suite.filmHandler = handlers.NewFilmHandler(filmService, commentService)
+	suite.filmHandler = handlers.NewFilmHandler(filmService, commentService, pagination.DefaultLimits)
 
 	// Setup router
-	suite.router = mux.NewRouter()
-	api := suite.router.PathPrefix("/api/v1").Subrouter()
+	suite.router = router.New()
+	api := suite.router.Group("/api/v1")
+	api.Use(middleware.StoreScope)
 
 	// Film routes
-	api.HandleFunc("/films", suite.filmHandler.GetFilms).Methods("GET")
-	api.HandleFunc("/films/{id}", suite.filmHandler.GetFilmByID).Methods("GET")
-	api.HandleFunc("/categories", suite.filmHandler.GetCategories).Methods("GET")
+	api.HandleFunc("GET /films", suite.filmHandler.GetFilms)
+	api.HandleFunc("GET /films/{id}", suite.filmHandler.GetFilmByID)
+	api.HandleFunc("GET /categories", suite.filmHandler.GetCategories)
 
 	// Comment routes
-	api.HandleFunc("/films/{id}/comments", suite.filmHandler.AddComment).Methods("POST")
-	api.HandleFunc("/films/{id}/comments", suite.filmHandler.GetComments).Methods("GET")
+	api.HandleFunc("POST /films/{id}/comments", suite.filmHandler.AddComment)
+	api.HandleFunc("GET /films/{id}/comments", suite.filmHandler.GetComments)
+
+	// Store-scoped routes
+	stores := api.Group("/stores/{storeID}")
+	stores.HandleFunc("GET /films", suite.filmHandler.GetFilms)
 
 	// Welcome route
-	suite.router.HandleFunc("/", handlers.WelcomeHandler).Methods("GET")
+	suite.router.HandleFunc("GET /{$}", handlers.WelcomeHandler)
 }
 
 func (suite *IntegrationTestSuite) TearDownSuite() {
@@ -128,11 +213,10 @@ func (suite *IntegrationTestSuite) TestWelcomeEndpoint() {
 func (suite *IntegrationTestSuite) TestGetFilms() {
 	// Setup mock expectations
 	expectedFilters := models.FilmFilters{
-		Page:  1,
-		Limit: 5,
+		Params: pagination.Params{Page: 1, Limit: 5},
 	}
-	mockResponse := &models.FilmListResponse{
-		Films: []models.Film{
+	mockResponse := &pagination.Paginated[models.Film]{
+		Items: []models.Film{
 			{FilmID: 1, Title: "Test Film 1", Rating: "PG"},
 			{FilmID: 2, Title: "Test Film 2", Rating: "G"},
 		},
@@ -149,10 +233,10 @@ func (suite *IntegrationTestSuite) TestGetFilms() {
 
 	suite.Equal(http.StatusOK, w.Code)
 
-	var response models.FilmListResponse
+	var response pagination.Paginated[models.Film]
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	suite.Require().NoError(err)
-	suite.Len(response.Films, 2)
+	suite.Len(response.Items, 2)
 	suite.Equal(1, response.Page)
 	suite.Equal(5, response.Limit)
 	suite.Equal(2, response.Total)
@@ -161,13 +245,12 @@ func (suite *IntegrationTestSuite) TestGetFilms() {
 func (suite *IntegrationTestSuite) TestGetFilmsWithFilters() {
 	// Setup mock expectations
 	expectedFilters := models.FilmFilters{
-		Title:  "Academy",
-		Rating: "PG",
-		Page:   1,
-		Limit:  10,
+		Title:   "Academy",
+		Ratings: []string{"PG"},
+		Params:  pagination.Params{Page: 1, Limit: 10},
 	}
-	mockResponse := &models.FilmListResponse{
-		Films: []models.Film{
+	mockResponse := &pagination.Paginated[models.Film]{
+		Items: []models.Film{
 			{FilmID: 1, Title: "Academy Dinosaur", Rating: "PG"},
 		},
 		Total: 1,
@@ -183,14 +266,138 @@ func (suite *IntegrationTestSuite) TestGetFilmsWithFilters() {
 
 	suite.Equal(http.StatusOK, w.Code)
 
-	var response models.FilmListResponse
+	var response pagination.Paginated[models.Film]
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	suite.Require().NoError(err)
 
 	// Verify filtering works
-	suite.Len(response.Films, 1)
-	suite.Contains(response.Films[0].Title, "Academy")
-	suite.Equal("PG", response.Films[0].Rating)
+	suite.Len(response.Items, 1)
+	suite.Contains(response.Items[0].Title, "Academy")
+	suite.Equal("PG", response.Items[0].Rating)
+}
+
+func (suite *IntegrationTestSuite) TestGetFilmsWithMultiValueFilters() {
+	// Setup mock expectations
+	expectedFilters := models.FilmFilters{
+		Ratings:    []string{"PG", "PG-13"},
+		Categories: []string{"Action", "Comedy"},
+		Params:     pagination.Params{Page: 1, Limit: 10},
+	}
+	mockResponse := &pagination.Paginated[models.Film]{
+		Items: []models.Film{
+			{FilmID: 1, Title: "Academy Dinosaur", Rating: "PG"},
+			{FilmID: 2, Title: "Ace Goldfinger", Rating: "PG-13"},
+		},
+		Total: 2,
+		Page:  1,
+		Limit: 10,
+	}
+	suite.mockFilmRepo.On("GetFilms", expectedFilters).Return(mockResponse, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/films?rating=PG,PG-13&category=Action,%20Comedy", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var response pagination.Paginated[models.Film]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	suite.Require().NoError(err)
+	suite.Len(response.Items, 2)
+}
+
+func (suite *IntegrationTestSuite) TestGetFilmsByActor() {
+	// Setup mock expectations
+	expectedFilters := models.FilmFilters{
+		Actor:  "penelope",
+		Params: pagination.Params{Page: 1, Limit: 10},
+	}
+	mockResponse := &pagination.Paginated[models.Film]{
+		Items: []models.Film{
+			{FilmID: 1, Title: "Academy Dinosaur", Rating: "PG", Actors: []string{"Penelope Guiness"}},
+		},
+		Total: 1,
+		Page:  1,
+		Limit: 10,
+	}
+	suite.mockFilmRepo.On("GetFilms", expectedFilters).Return(mockResponse, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/films?actor=penelope", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var response pagination.Paginated[models.Film]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	suite.Require().NoError(err)
+	suite.Len(response.Items, 1)
+	suite.Equal(1, response.Total)
+}
+
+func (suite *IntegrationTestSuite) TestGetFilmsWithoutCount() {
+	// Setup mock expectations
+	expectedFilters := models.FilmFilters{
+		CountMode: models.CountNone,
+		Params:    pagination.Params{Page: 1, Limit: 10},
+	}
+	mockResponse := &pagination.Paginated[models.Film]{
+		Items: []models.Film{
+			{FilmID: 1, Title: "Academy Dinosaur", Rating: "PG"},
+		},
+		TotalMode: models.CountNone,
+		Page:      1,
+		Limit:     10,
+	}
+	suite.mockFilmRepo.On("GetFilms", expectedFilters).Return(mockResponse, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/films?count=false", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	var response pagination.Paginated[models.Film]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	suite.Require().NoError(err)
+	suite.Equal(models.CountNone, response.TotalMode)
+}
+
+func (suite *IntegrationTestSuite) TestGetFilmsScopedToStore() {
+	// Setup mock expectations
+	expectedFilters := models.FilmFilters{
+		StoreID: 2,
+		Params:  pagination.Params{Page: 1, Limit: 10},
+	}
+	mockResponse := &pagination.Paginated[models.Film]{
+		Items: []models.Film{
+			{FilmID: 1, Title: "Academy Dinosaur", Rating: "PG"},
+		},
+		Total: 1,
+		Page:  1,
+		Limit: 10,
+	}
+	suite.mockFilmRepo.On("GetFilms", expectedFilters).Return(mockResponse, nil).Twice()
+
+	headerReq := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+	headerReq.Header.Set(middleware.StoreIDHeader, "2")
+	prefixReq := httptest.NewRequest(http.MethodGet, "/api/v1/stores/2/films", nil)
+
+	for _, req := range []*http.Request{headerReq, prefixReq} {
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		suite.Equal(http.StatusOK, w.Code)
+	}
+	suite.mockFilmRepo.AssertExpectations(suite.T())
+
+	badReq := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+	badReq.Header.Set(middleware.StoreIDHeader, "abc")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, badReq)
+	suite.Equal(http.StatusBadRequest, w.Code)
 }
 
 func (suite *IntegrationTestSuite) TestGetFilmByID() {
@@ -213,11 +420,12 @@ func (suite *IntegrationTestSuite) TestGetFilmByID() {
 		Rating:          "PG",
 		SpecialFeatures: []string{"Trailers", "Commentaries"},
 		LastUpdate:      lastUpdate,
+		Status:          models.FilmPublished,
 	}
 	suite.mockFilmRepo.On("GetFilmByID", filmID).Return(mockFilm, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/films/1", nil)
-	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	req.SetPathValue("id", "1")
 	w := httptest.NewRecorder()
 
 	suite.router.ServeHTTP(w, req)
@@ -237,7 +445,7 @@ func (suite *IntegrationTestSuite) TestGetFilmByIDNotFound() {
 	suite.mockFilmRepo.On("GetFilmByID", filmID).Return(nil, repository.ErrFilmNotFound)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/films/99999", nil)
-	req = mux.SetURLVars(req, map[string]string{"id": "99999"})
+	req.SetPathValue("id", "99999")
 	w := httptest.NewRecorder()
 
 	suite.router.ServeHTTP(w, req)
@@ -250,6 +458,22 @@ func (suite *IntegrationTestSuite) TestGetFilmByIDNotFound() {
 	suite.Equal("Film not found", response.Error)
 }
 
+func (suite *IntegrationTestSuite) TestHeadCategories() {
+	mockCategories := []models.Category{{CategoryID: 1, Name: "Action"}}
+	suite.mockFilmRepo.On("GetCategories").Return(mockCategories, nil)
+
+	req := httptest.NewRequest(http.MethodHead, "/api/v1/categories", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Empty(w.Body.Bytes())
+	suite.NotEmpty(w.Header().Get("ETag"))
+	suite.NotEmpty(w.Header().Get("Content-Length"))
+	suite.Empty(w.Header().Get("Last-Modified"))
+}
+
 func (suite *IntegrationTestSuite) TestGetCategories() {
 	// Setup mock expectations
 	mockCategories := []models.Category{
@@ -282,7 +506,7 @@ func (suite *IntegrationTestSuite) TestAddAndGetComments() {
 	filmID := 1
 
 	// Setup mock expectations for film existence check
-	mockFilm := &models.Film{FilmID: 1, Title: "Test Film"}
+	mockFilm := &models.Film{FilmID: 1, Title: "Test Film", Status: models.FilmPublished}
 	suite.mockFilmRepo.On("GetFilmByID", filmID).Return(mockFilm, nil)
 
 	// Setup mock expectations for adding comment
@@ -308,7 +532,7 @@ func (suite *IntegrationTestSuite) TestAddAndGetComments() {
 		bytes.NewBuffer(requestBody),
 	)
 	req.Header.Set("Content-Type", "application/json")
-	req = mux.SetURLVars(req, map[string]string{"id": strconv.Itoa(filmID)})
+	req.SetPathValue("id", strconv.Itoa(filmID))
 	w := httptest.NewRecorder()
 
 	suite.router.ServeHTTP(w, req)
@@ -325,26 +549,61 @@ func (suite *IntegrationTestSuite) TestAddAndGetComments() {
 
 	// Setup mock expectations for getting comments
 	mockComments := []models.Comment{*mockComment}
-	suite.mockCommentRepo.On("GetCommentsByFilmID", filmID).Return(mockComments, nil)
+	firstPage := pagination.Params{Limit: models.CommentLimits.DefaultLimit}
+	suite.mockCommentRepo.On("GetCommentsByFilmID", filmID, 0, firstPage.Limit, (*pagination.Cursor)(nil)).
+		Return(pagination.New(mockComments, 1, firstPage), nil)
 
 	// Now, get comments for the film
 	req = httptest.NewRequest(http.MethodGet, "/api/v1/films/"+strconv.Itoa(filmID)+"/comments", nil)
-	req = mux.SetURLVars(req, map[string]string{"id": strconv.Itoa(filmID)})
+	req.SetPathValue("id", strconv.Itoa(filmID))
 	w = httptest.NewRecorder()
 
 	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusOK, w.Code)
 
-	var getResponse []models.Comment
+	var getResponse pagination.Paginated[models.Comment]
 	err = json.Unmarshal(w.Body.Bytes(), &getResponse)
 	suite.Require().NoError(err)
-	suite.Len(getResponse, 1)
+	suite.Require().Len(getResponse.Items, 1)
+	suite.Equal(1, getResponse.Total)
 
 	// Verify our comment is in the list
-	suite.Equal(addResponse.ID, getResponse[0].ID)
-	suite.Equal(commentReq.CustomerName, getResponse[0].CustomerName)
-	suite.Equal(commentReq.Comment, getResponse[0].Comment)
+	suite.Equal(addResponse.ID, getResponse.Items[0].ID)
+	suite.Equal(commentReq.CustomerName, getResponse.Items[0].CustomerName)
+	suite.Equal(commentReq.Comment, getResponse.Items[0].Comment)
+}
+
+func (suite *IntegrationTestSuite) TestAddGuestCommentWithoutName() {
+	requestBody, _ := json.Marshal(models.CommentRequest{Comment: "Who wrote this?"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/films/1/comments", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	var response models.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	suite.Contains(response.Details, "customer name is required")
+}
+
+func (suite *IntegrationTestSuite) TestCommentsOnDraftFilm() {
+	filmID := 7
+	suite.mockFilmRepo.On("GetFilmByID", filmID).
+		Return(&models.Film{FilmID: filmID, Title: "Unreleased", Status: models.FilmDraft}, nil)
+
+	requestBody, _ := json.Marshal(models.CommentRequest{CustomerName: "Test User", Comment: "Too early"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/films/7/comments", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/films/7/comments", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)
 }
 
 func (suite *IntegrationTestSuite) TestAddCommentToNonExistentFilm() {
@@ -365,7 +624,7 @@ func (suite *IntegrationTestSuite) TestAddCommentToNonExistentFilm() {
 		bytes.NewBuffer(requestBody),
 	)
 	req.Header.Set("Content-Type", "application/json")
-	req = mux.SetURLVars(req, map[string]string{"id": strconv.Itoa(filmID)})
+	req.SetPathValue("id", strconv.Itoa(filmID))
 	w := httptest.NewRecorder()
 
 	suite.router.ServeHTTP(w, req)
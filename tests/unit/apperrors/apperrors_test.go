@@ -0,0 +1,57 @@
+package apperrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+)
+
+var errThingNotFound = apperrors.New(apperrors.NotFound, "thing not found")
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected apperrors.Kind
+	}{
+		{name: "nil", err: nil, expected: apperrors.Internal},
+		{name: "plain error", err: errors.New("boom"), expected: apperrors.Internal},
+		{name: "sentinel", err: errThingNotFound, expected: apperrors.NotFound},
+		{name: "wrapped sentinel", err: fmt.Errorf("error loading: %w", errThingNotFound), expected: apperrors.NotFound},
+		{name: "marked cause", err: apperrors.Wrap(apperrors.Unavailable, errors.New("timeout")),
+			expected: apperrors.Unavailable},
+		{name: "outermost kind wins", err: apperrors.Wrap(apperrors.Conflict, errThingNotFound),
+			expected: apperrors.Conflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, apperrors.KindOf(tt.err))
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert.NoError(t, apperrors.Wrap(apperrors.Invalid, nil))
+
+	cause := errors.New("bad input")
+	err := apperrors.Wrap(apperrors.Invalid, cause)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "bad input", err.Error())
+}
+
+func TestMessage(t *testing.T) {
+	assert.Equal(t, "thing not found", apperrors.Message(fmt.Errorf("error loading: %w", errThingNotFound)))
+	assert.Equal(t, "thing not found", apperrors.Message(apperrors.Wrap(apperrors.Conflict, errThingNotFound)))
+	assert.Empty(t, apperrors.Message(apperrors.Wrap(apperrors.Conflict, errors.New("boom"))))
+	assert.Empty(t, apperrors.Message(errors.New("boom")))
+}
+
+func TestErrorsIs(t *testing.T) {
+	assert.ErrorIs(t, fmt.Errorf("error loading: %w", errThingNotFound), errThingNotFound)
+	assert.NotErrorIs(t, apperrors.New(apperrors.NotFound, "thing not found"), errThingNotFound)
+}
@@ -0,0 +1,115 @@
+package attachments_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/attachments"
+)
+
+// pngImage encodes a width by height PNG.
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		img.Set(x, 0, color.NRGBA{R: 255, A: 128})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestPrepare_MakesThumbnail(t *testing.T) {
+	data := pngImage(t, 800, 400)
+
+	img, err := attachments.Prepare(data, 1<<20)
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", img.ContentType)
+	assert.Equal(t, ".png", img.Extension)
+	thumb, err := jpeg.Decode(bytes.NewReader(img.Thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 320, 160), thumb.Bounds())
+}
+
+func TestPrepare_Rejects(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          []byte
+		maxBytes      int
+		expectedError error
+	}{
+		{name: "not an image", data: []byte("<html>hello</html>"), maxBytes: 1 << 20,
+			expectedError: attachments.ErrUnsupportedType},
+		{name: "truncated image", data: pngImage(t, 10, 10)[:40], maxBytes: 1 << 20,
+			expectedError: attachments.ErrUnsupportedType},
+		{name: "over the size limit", data: pngImage(t, 10, 10), maxBytes: 10,
+			expectedError: attachments.ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := attachments.Prepare(tt.data, tt.maxBytes)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestLocalStore_PutServeDelete(t *testing.T) {
+	store, err := attachments.NewLocalStore(t.TempDir(), "https://mockbuster.example/media/")
+	require.NoError(t, err)
+	key, thumbnailKey, err := attachments.NewKey(".png")
+	require.NoError(t, err)
+	assert.Equal(t, key[:32]+"-thumb.jpg", thumbnailKey)
+	data := pngImage(t, 4, 4)
+
+	require.NoError(t, store.Put(context.Background(), key, data))
+	assert.Equal(t, "https://mockbuster.example/media/"+key, store.URL(key))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /media/{key}", store.Handler())
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/media/"+key, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, data, w.Body.Bytes())
+
+	require.NoError(t, store.Delete(context.Background(), key))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/media/"+key, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLocalStore_RejectsOtherKeys(t *testing.T) {
+	store, err := attachments.NewLocalStore(t.TempDir(), "/media")
+	require.NoError(t, err)
+
+	require.Error(t, store.Put(context.Background(), "../escape.png", []byte("x")))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /media/{key}", store.Handler())
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/media/.upload-123", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCommandScanner(t *testing.T) {
+	ctx := context.Background()
+
+	require.NoError(t, attachments.NewCommandScanner("true").Scan(ctx, []byte("clean")))
+	require.ErrorIs(t, attachments.NewCommandScanner("false").Scan(ctx, []byte("infected")), attachments.ErrInfected)
+
+	err := attachments.NewCommandScanner("/nonexistent/scanner").Scan(ctx, []byte("data"))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, attachments.ErrInfected)
+}
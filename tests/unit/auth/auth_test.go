@@ -0,0 +1,127 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+)
+
+func TestTokenIssuer_RoundTrip(t *testing.T) {
+	issuer := auth.NewTokenIssuer(auth.RoleStaff, "secret", time.Hour)
+
+	token, expiresAt, err := issuer.Issue(7)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	claims, err := issuer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, 7, claims.Subject)
+	assert.Equal(t, auth.RoleStaff, claims.Role)
+}
+
+func TestTokenIssuer_RejectsInvalidTokens(t *testing.T) {
+	issuer := auth.NewTokenIssuer(auth.RoleStaff, "secret", time.Hour)
+	token, _, err := issuer.Issue(7)
+	require.NoError(t, err)
+
+	otherSecret := auth.NewTokenIssuer(auth.RoleStaff, "other", time.Hour)
+	otherRole := auth.NewTokenIssuer("customer", "secret", time.Hour)
+	expired := auth.NewTokenIssuer(auth.RoleStaff, "secret", -time.Second)
+	expiredToken, _, err := expired.Issue(7)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		issuer *auth.TokenIssuer
+		token  string
+	}{
+		{name: "malformed", issuer: issuer, token: "not-a-token"},
+		{name: "tampered", issuer: issuer, token: "e30." + token[len(token)-10:]},
+		{name: "wrong secret", issuer: otherSecret, token: token},
+		{name: "wrong role", issuer: otherRole, token: token},
+		{name: "expired", issuer: issuer, token: expiredToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, verifyErr := tt.issuer.Verify(tt.token)
+			assert.ErrorIs(t, verifyErr, auth.ErrInvalidToken)
+		})
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+
+	assert.True(t, auth.CheckPassword(hash, "correct horse"))
+	assert.False(t, auth.CheckPassword(hash, "wrong"))
+	assert.False(t, auth.NeedsRehash(hash))
+
+	// SHA-1 of "12345", as stored for the sample dvdrental staff.
+	legacy := "8cb2237d0679ca88db6464eac60da96345513964"
+	assert.True(t, auth.CheckPassword(legacy, "12345"))
+	assert.False(t, auth.CheckPassword(legacy, "wrong"))
+	assert.True(t, auth.NeedsRehash(legacy))
+	assert.False(t, auth.CheckPassword("", ""))
+}
+
+func TestCheckUnknownAccountPassword(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+
+	start := time.Now()
+	auth.CheckPassword(hash, "wrong")
+	known := time.Since(start)
+
+	start = time.Now()
+	matched := auth.CheckUnknownAccountPassword("correct horse")
+	unknown := time.Since(start)
+
+	assert.False(t, matched)
+	assert.Greater(t, unknown, known/2, "unknown accounts must not be rejected faster than known ones")
+}
+
+type stubRevocationList map[int64]bool
+
+func (l stubRevocationList) IsSessionRevoked(_ context.Context, sessionID int64) (bool, error) {
+	return l[sessionID], nil
+}
+
+func TestTokenIssuer_AuthenticateChecksSession(t *testing.T) {
+	issuer := auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour,
+		auth.WithRevocationList(stubRevocationList{2: true}))
+
+	live, _, err := issuer.IssueForSession(7, 1, false)
+	require.NoError(t, err)
+	claims, err := issuer.Authenticate(context.Background(), live)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), claims.SessionID)
+
+	revoked, _, err := issuer.IssueForSession(7, 2, false)
+	require.NoError(t, err)
+	_, err = issuer.Authenticate(context.Background(), revoked)
+	require.ErrorIs(t, err, auth.ErrRevokedToken)
+
+	// Tokens issued outside a session are not looked up.
+	sessionless, _, err := issuer.Issue(7)
+	require.NoError(t, err)
+	_, err = issuer.Authenticate(context.Background(), sessionless)
+	assert.NoError(t, err)
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	token, hash, err := auth.NewRefreshToken()
+	require.NoError(t, err)
+
+	assert.Equal(t, auth.HashRefreshToken(token), hash)
+	assert.NotContains(t, hash, token)
+	other, _, err := auth.NewRefreshToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
@@ -0,0 +1,78 @@
+package auth_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+)
+
+func TestURLSigner_RoundTrip(t *testing.T) {
+	signer := auth.NewURLSigner("secret", time.Hour, "https://api.example.com")
+
+	signed, expiresAt, err := signer.Sign("/api/v1/customers/600/export?format=zip")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(signed, "https://api.example.com/api/v1/customers/600/export?"))
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, 2*time.Second)
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "zip", u.Query().Get("format"))
+	assert.NoError(t, signer.Verify(u))
+}
+
+func TestURLSigner_RejectsTamperedURLs(t *testing.T) {
+	signer := auth.NewURLSigner("secret", time.Hour, "")
+	signed, _, err := signer.Sign("/api/v1/rentals/42/receipt")
+	require.NoError(t, err)
+	valid, err := url.Parse(signed)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		mutate func(u *url.URL)
+	}{
+		{name: "other path", mutate: func(u *url.URL) { u.Path = "/api/v1/rentals/43/receipt" }},
+		{name: "added parameter", mutate: func(u *url.URL) {
+			query := u.Query()
+			query.Set("format", "json")
+			u.RawQuery = query.Encode()
+		}},
+		{name: "extended expiry", mutate: func(u *url.URL) {
+			query := u.Query()
+			query.Set(auth.ExpiresParam, "99999999999")
+			u.RawQuery = query.Encode()
+		}},
+		{name: "no signature", mutate: func(u *url.URL) {
+			query := u.Query()
+			query.Del(auth.SignatureParam)
+			u.RawQuery = query.Encode()
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := *valid
+			tt.mutate(&u)
+			assert.ErrorIs(t, signer.Verify(&u), auth.ErrInvalidSignature)
+		})
+	}
+
+	other := auth.NewURLSigner("other-secret", time.Hour, "")
+	assert.ErrorIs(t, other.Verify(valid), auth.ErrInvalidSignature)
+}
+
+func TestURLSigner_RejectsExpiredURLs(t *testing.T) {
+	signer := auth.NewURLSigner("secret", -time.Minute, "")
+	signed, _, err := signer.Sign("/api/v1/rentals/42/receipt")
+	require.NoError(t, err)
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, signer.Verify(u), auth.ErrInvalidSignature)
+}
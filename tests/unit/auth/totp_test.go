@@ -0,0 +1,71 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+)
+
+// rfcSecret is the RFC 6238 SHA-1 test key, "12345678901234567890", in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 2000000000, expected: "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := auth.TOTPCode(rfcSecret, auth.TOTPStep(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, code, "at %d", tt.unix)
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := auth.NewTOTPSecret()
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+	step := auth.TOTPStep(now)
+
+	previous, err := auth.TOTPCode(secret, step-1)
+	require.NoError(t, err)
+	matched, ok := auth.VerifyTOTP(secret, previous, now)
+	assert.True(t, ok, "a code from the previous step is still accepted")
+	assert.Equal(t, step-1, matched)
+
+	stale, err := auth.TOTPCode(secret, step-2)
+	require.NoError(t, err)
+	_, ok = auth.VerifyTOTP(secret, stale, now)
+	assert.False(t, ok)
+
+	_, ok = auth.VerifyTOTP(secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := auth.TOTPProvisioningURI("Mockbuster", "mike", "ABCDEF")
+
+	assert.Equal(t, "otpauth://totp/Mockbuster:mike?issuer=Mockbuster&secret=ABCDEF", uri)
+}
+
+func TestNewBackupCodes(t *testing.T) {
+	codes, hashes, err := auth.NewBackupCodes()
+	require.NoError(t, err)
+
+	require.Len(t, hashes, len(codes))
+	assert.Len(t, codes, 10)
+	assert.Regexp(t, `^[0-9a-f]{5}-[0-9a-f]{5}$`, codes[0])
+	assert.Equal(t, hashes[0], auth.HashBackupCode(codes[0]))
+	// Backup codes are accepted however they are typed.
+	assert.Equal(t, hashes[0], auth.HashBackupCode(" "+strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))))
+}
@@ -0,0 +1,70 @@
+package binding_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/binding"
+)
+
+type filters struct {
+	Name    string   `query:"name"`
+	Kinds   []string `query:"kind"   validate:"dive,oneof=a b"`
+	Labels  []string `query:"label"  validate:"dive,notblank"`
+	Page    int      `query:"page"   validate:"min=1"`
+	Active  bool     `query:"active"`
+	Skipped string
+}
+
+func TestQuery(t *testing.T) {
+	values, err := url.ParseQuery("name=x&kind=a,%20b,,&page=3&active=true&Skipped=y")
+	require.NoError(t, err)
+
+	got := filters{Page: 1}
+	require.NoError(t, binding.Query(values, &got))
+
+	assert.Equal(t, filters{Name: "x", Kinds: []string{"a", "b"}, Page: 3, Active: true}, got)
+}
+
+func TestQuery_MissingKeepsDefaults(t *testing.T) {
+	got := filters{Page: 1, Name: "default"}
+	require.NoError(t, binding.Query(url.Values{"page": {""}}, &got))
+
+	assert.Equal(t, filters{Page: 1, Name: "default"}, got)
+}
+
+func TestQuery_Invalid(t *testing.T) {
+	for _, query := range []string{"page=two", "page=99999999999999999999", "active=maybe"} {
+		t.Run(query, func(t *testing.T) {
+			values, err := url.ParseQuery(query)
+			require.NoError(t, err)
+
+			err = binding.Query(values, &filters{})
+
+			require.ErrorIs(t, err, binding.ErrInvalidQuery)
+			assert.Equal(t, apperrors.Invalid, apperrors.KindOf(err))
+		})
+	}
+}
+
+func TestQuery_RequiresStructPointer(t *testing.T) {
+	err := binding.Query(url.Values{}, filters{})
+
+	require.Error(t, err)
+	assert.Equal(t, apperrors.Internal, apperrors.KindOf(err))
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, binding.Validate(filters{Kinds: []string{"a"}, Labels: []string{"x"}, Page: 1}))
+
+	err := binding.Validate(filters{Kinds: []string{"a", "c"}, Labels: []string{" "}, Page: 0})
+
+	require.ErrorIs(t, err, binding.ErrValidation)
+	assert.Equal(t, apperrors.Invalid, apperrors.KindOf(err))
+	assert.EqualError(t, err, `validation failed: kind[1] must be one of a, b, not "c"; `+
+		"label[0] must not be empty; page must be at least 1")
+}
@@ -0,0 +1,126 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+func TestMemoryCache_SetAndGet(t *testing.T) {
+	c := cache.NewMemoryCache(time.Minute)
+
+	c.Set("key", "value")
+
+	value, ok := c.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	c := cache.NewMemoryCache(time.Nanosecond)
+
+	c.Set("key", "value")
+	time.Sleep(time.Millisecond)
+
+	_, ok := c.Get("key")
+	assert.False(t, ok)
+}
+
+func TestMemoryCache_MaxEntries(t *testing.T) {
+	c := cache.NewMemoryCache(time.Minute, cache.WithMaxEntries(1))
+
+	c.Set("first", 1)
+	c.Set("second", 2)
+	c.Set("first", 3)
+
+	value, ok := c.Get("first")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+	_, ok = c.Get("second")
+	assert.False(t, ok)
+}
+
+func TestMemoryCache_DeletePrefix(t *testing.T) {
+	c := cache.NewMemoryCache(time.Minute)
+	c.Set(cache.FilmKey(1), "film")
+	c.Set(cache.FilmListKey(models.FilmFilters{Params: pagination.Params{Page: 1, Limit: 10}}), "page")
+
+	c.DeletePrefix(cache.FilmListPrefix)
+
+	_, ok := c.Get(cache.FilmKey(1))
+	assert.True(t, ok)
+	_, ok = c.Get(cache.FilmListKey(models.FilmFilters{Params: pagination.Params{Page: 1, Limit: 10}}))
+	assert.False(t, ok)
+}
+
+func TestFilmListKey_DistinguishesFilters(t *testing.T) {
+	first := cache.FilmListKey(models.FilmFilters{Params: pagination.Params{Page: 1, Limit: 10}})
+	second := cache.FilmListKey(models.FilmFilters{Params: pagination.Params{Page: 2, Limit: 10}})
+
+	assert.NotEqual(t, first, second)
+	assert.Contains(t, first, cache.FilmListPrefix)
+}
+
+func TestBus_EvictsOnEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		event         cache.Event
+		expectFilm1   bool
+		expectFilm2   bool
+		expectListing bool
+		expectCats    bool
+	}{
+		{
+			name:        "film changed",
+			event:       cache.FilmChanged(1),
+			expectFilm2: true,
+			expectCats:  true,
+		},
+		{
+			name:       "films changed",
+			event:      cache.FilmsChanged(1, 2),
+			expectCats: true,
+		},
+		{
+			name:  "categories changed",
+			event: cache.CategoriesChanged(),
+		},
+		{
+			name:  "purge all",
+			event: cache.PurgeAll(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cache.NewMemoryCache(time.Minute)
+			bus := cache.NewBus()
+			bus.Subscribe(cache.Evict(c))
+
+			listingKey := cache.FilmListKey(models.FilmFilters{Params: pagination.Params{Page: 1, Limit: 10}})
+			c.Set(cache.FilmKey(1), "film 1")
+			c.Set(cache.FilmKey(2), "film 2")
+			c.Set(listingKey, "listing")
+			c.Set(cache.CategoriesKey, "categories")
+
+			bus.Publish(tt.event)
+
+			_, ok := c.Get(cache.FilmKey(1))
+			assert.Equal(t, tt.expectFilm1, ok)
+			_, ok = c.Get(cache.FilmKey(2))
+			assert.Equal(t, tt.expectFilm2, ok)
+			_, ok = c.Get(listingKey)
+			assert.Equal(t, tt.expectListing, ok)
+			_, ok = c.Get(cache.CategoriesKey)
+			assert.Equal(t, tt.expectCats, ok)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package database_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,6 +41,31 @@ func TestWithDBName(t *testing.T) {
 	assert.NotNil(t, withDBName)
 }
 
+func TestWithSlowQueryThreshold(t *testing.T) {
+	// Test that WithSlowQueryThreshold function exists and can be called
+	withThreshold := database.WithSlowQueryThreshold(250 * time.Millisecond)
+	assert.NotNil(t, withThreshold)
+}
+
+func TestWithQueryName(t *testing.T) {
+	ctx := database.WithQueryName(context.Background(), "films.list")
+	assert.NotNil(t, ctx)
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	query := `
+		SELECT film_id, title
+		FROM film
+		WHERE film_id = $1
+	`
+	assert.Equal(t, "SELECT film_id, title FROM film WHERE film_id = $1", database.SanitizeSQL(query))
+}
+
+func TestSanitizeArgs(t *testing.T) {
+	args := []any{42, "John Doe", true, []byte("abc")}
+	assert.Equal(t, []string{"42", "<string len=8>", "true", "<bytes len=3>"}, database.SanitizeArgs(args))
+}
+
 func TestInitDB_WithOptions(t *testing.T) {
 	// Test with custom options
 	db, err := database.InitDB(
@@ -0,0 +1,71 @@
+package database_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+)
+
+func TestMigrationUpSQL(t *testing.T) {
+	upSQL, err := database.MigrationUpSQL(strings.NewReader(`-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE things (id SERIAL PRIMARY KEY);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE things;
+-- +goose StatementEnd
+`))
+
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE things (id SERIAL PRIMARY KEY);\n\n", upSQL)
+}
+
+func TestMigrationUpSQL_Invalid(t *testing.T) {
+	_, err := database.MigrationUpSQL(strings.NewReader("-- +goose Down\nDROP TABLE things;\n"))
+	assert.Error(t, err)
+
+	_, err = database.MigrationUpSQL(strings.NewReader(
+		"-- +goose NO TRANSACTION\n-- +goose Up\nCREATE INDEX CONCURRENTLY i ON t (c);\n"))
+	assert.Error(t, err)
+}
+
+func TestMigrationUpSQL_RepositoryMigrations(t *testing.T) {
+	paths, err := filepath.Glob("../../../migrations/*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		file, openErr := os.Open(path)
+		require.NoError(t, openErr)
+
+		_, err = database.MigrationUpSQL(file)
+		file.Close()
+		assert.NoError(t, err, filepath.Base(path))
+	}
+}
+
+func TestLatestMigrationVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"001_init.sql", "012_later.sql", "003_middle.sql"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("-- +goose Up\nSELECT 1;\n"), 0o600))
+	}
+
+	version, err := database.LatestMigrationVersion(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), version)
+}
+
+func TestLatestMigrationVersion_NoMigrations(t *testing.T) {
+	_, err := database.LatestMigrationVersion(t.TempDir())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/database"
+)
+
+func TestSchemaReport(t *testing.T) {
+	report := &database.SchemaReport{}
+	assert.False(t, report.Drifted())
+	assert.Empty(t, report.String())
+
+	report = &database.SchemaReport{
+		PendingMigrations: []database.PendingMigration{{Version: 28, Source: "migrations/028_encrypted_comment_names.sql"}},
+		MissingTables:     []string{"audit_log"},
+		MissingColumns:    []string{"rental.overdue"},
+	}
+	assert.True(t, report.Drifted())
+	assert.Equal(t, "migration not applied: 028_encrypted_comment_names.sql\n"+
+		"table missing: audit_log\n"+
+		"column missing: rental.overdue\n", report.String())
+}
+
+func TestMissingExtensions(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("SELECT extname FROM pg_extension").
+		WillReturnRows(sqlmock.NewRows([]string{"extname"}).AddRow("pg_trgm"))
+
+	missing, err := database.MissingExtensions(db, []string{"pg_trgm", "citext"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"citext"}, missing)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
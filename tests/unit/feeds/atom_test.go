@@ -0,0 +1,43 @@
+package feeds_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/feeds"
+)
+
+func TestMarshal(t *testing.T) {
+	published := time.Date(2024, 5, 1, 14, 30, 15, 500, time.FixedZone("EDT", -4*60*60))
+	feed := feeds.Feed{
+		ID:      "http://localhost:8080/feeds/films.atom",
+		Title:   "New films",
+		Updated: published,
+		Author:  &feeds.Person{Name: "Mockbuster"},
+		Links:   []feeds.Link{{Rel: "self", Href: "http://localhost:8080/feeds/films.atom"}},
+		Entries: []feeds.Entry{{
+			ID:         "http://localhost:8080/api/v1/films/1",
+			Title:      "Academy Dinosaur",
+			Updated:    published,
+			Published:  &published,
+			Categories: []feeds.Category{{Term: "Documentary"}},
+			Content:    &feeds.Text{Type: "html", Body: "<p>Epic & drama</p>"},
+		}},
+	}
+
+	body, err := feeds.Marshal(feed)
+
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><id>http://localhost:8080/feeds/films.atom</id>`+
+		`<title>New films</title><updated>2024-05-01T18:30:15Z</updated><author><name>Mockbuster</name></author>`+
+		`<link rel="self" href="http://localhost:8080/feeds/films.atom"></link>`+
+		`<entry><id>http://localhost:8080/api/v1/films/1</id><title>Academy Dinosaur</title>`+
+		`<updated>2024-05-01T18:30:15Z</updated><published>2024-05-01T18:30:15Z</published>`+
+		`<category term="Documentary"></category>`+
+		`<content type="html">&lt;p&gt;Epic &amp; drama&lt;/p&gt;</content></entry></feed>`, string(body))
+	assert.Equal(t, 500, published.Nanosecond(), "the caller's times are left alone")
+}
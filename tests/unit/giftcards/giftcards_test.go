@@ -0,0 +1,39 @@
+package giftcards_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/giftcards"
+)
+
+func TestNewCode(t *testing.T) {
+	format := regexp.MustCompile(`^[A-HJ-NP-Z2-9]{4}(-[A-HJ-NP-Z2-9]{4}){3}$`)
+	seen := make(map[string]bool)
+	for range 100 {
+		code, hash, err := giftcards.NewCode()
+
+		require.NoError(t, err)
+		assert.Regexp(t, format, code)
+		assert.Equal(t, giftcards.Hash(code), hash)
+		assert.False(t, seen[code], "code %s generated twice", code)
+		seen[code] = true
+	}
+}
+
+func TestHash_IgnoresCaseAndSeparators(t *testing.T) {
+	hash := giftcards.Hash("7KQF-M2XD-9HRT-WB4C")
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, giftcards.Hash("7kqf m2xd 9hrt wb4c"))
+	assert.Equal(t, hash, giftcards.Hash("7KQFM2XD9HRTWB4C"))
+	assert.NotEqual(t, hash, giftcards.Hash("7KQF-M2XD-9HRT-WB4D"))
+}
+
+func TestLastFour(t *testing.T) {
+	assert.Equal(t, "WB4C", giftcards.LastFour("7kqf-m2xd-9hrt-wb4c"))
+	assert.Equal(t, "AB", giftcards.LastFour("ab"))
+}
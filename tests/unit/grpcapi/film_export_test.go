@@ -0,0 +1,178 @@
+package grpcapi_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/rxbenefits/go-hw/internal/grpcapi"
+	"github.com/rxbenefits/go-hw/internal/grpcapi/filmexportv1"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+const testToken = "export-token"
+
+// fakeExportRepo serves a fixed catalog, recording the arguments it was
+// called with.
+type fakeExportRepo struct {
+	films       []models.Film
+	err         error
+	storeID     int
+	afterFilmID int
+}
+
+func (r *fakeExportRepo) StreamFilms(_ context.Context, storeID, afterFilmID int, fn func(models.Film) error) error {
+	r.storeID = storeID
+	r.afterFilmID = afterFilmID
+	if r.err != nil {
+		return r.err
+	}
+	for _, film := range r.films {
+		if film.FilmID <= afterFilmID {
+			continue
+		}
+		if err := fn(film); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newClient starts the gRPC API over an in-memory listener.
+func newClient(t *testing.T, repo *fakeExportRepo) filmexportv1.FilmExportServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpcapi.NewServer(testToken, service.NewFilmExportService(repo))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return filmexportv1.NewFilmExportServiceClient(conn)
+}
+
+func authorized(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+testToken)
+}
+
+// receiveAll drains a stream, returning the films and the terminal error.
+func receiveAll(stream grpc.ServerStreamingClient[filmexportv1.Film]) ([]*filmexportv1.Film, error) {
+	var films []*filmexportv1.Film
+	for {
+		film, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return films, nil
+		}
+		if err != nil {
+			return films, err
+		}
+		films = append(films, film)
+	}
+}
+
+func TestListAllFilms_StreamsCatalog(t *testing.T) {
+	description := "A thrilling drama"
+	year := 2006
+	lastUpdate := time.Date(2013, 5, 26, 14, 50, 58, 0, time.UTC)
+	repo := &fakeExportRepo{films: []models.Film{
+		{
+			FilmID: 1, Title: "ACADEMY DINOSAUR", Description: &description, ReleaseYear: &year,
+			LanguageID: 1, RentalDuration: 6, RentalRate: 0.99, ReplacementCost: 20.99, Rating: "PG",
+			LastUpdate: lastUpdate, SpecialFeatures: []string{"Deleted Scenes"},
+			Categories: []string{"Documentary"}, Actors: []string{"PENELOPE GUINESS"},
+		},
+		{FilmID: 2, Title: "ACE GOLDFINGER", Rating: "G", LastUpdate: lastUpdate},
+	}}
+	client := newClient(t, repo)
+
+	stream, err := client.ListAllFilms(authorized(t), &filmexportv1.ListAllFilmsRequest{StoreId: 1})
+	require.NoError(t, err)
+	films, err := receiveAll(stream)
+	require.NoError(t, err)
+
+	require.Len(t, films, 2)
+	assert.Equal(t, 1, repo.storeID)
+	first := films[0]
+	assert.Equal(t, int32(1), first.GetFilmId())
+	assert.Equal(t, "ACADEMY DINOSAUR", first.GetTitle())
+	assert.Equal(t, description, first.GetDescription())
+	assert.Equal(t, int32(2006), first.GetReleaseYear())
+	assert.InDelta(t, 0.99, first.GetRentalRate(), 0.001)
+	assert.Equal(t, []string{"Documentary"}, first.GetCategories())
+	assert.Equal(t, []string{"PENELOPE GUINESS"}, first.GetActors())
+	assert.Equal(t, lastUpdate, first.GetLastUpdate().AsTime())
+	assert.Equal(t, int32(2), films[1].GetFilmId())
+	assert.Nil(t, films[1].Description)
+	assert.Nil(t, films[1].Length)
+}
+
+func TestListAllFilms_ResumesAfterFilmID(t *testing.T) {
+	repo := &fakeExportRepo{films: []models.Film{{FilmID: 1}, {FilmID: 2}, {FilmID: 3}}}
+	client := newClient(t, repo)
+
+	stream, err := client.ListAllFilms(authorized(t), &filmexportv1.ListAllFilmsRequest{AfterFilmId: 2})
+	require.NoError(t, err)
+	films, err := receiveAll(stream)
+	require.NoError(t, err)
+
+	require.Len(t, films, 1)
+	assert.Equal(t, int32(3), films[0].GetFilmId())
+	assert.Equal(t, 2, repo.afterFilmID)
+}
+
+func TestListAllFilms_RequiresToken(t *testing.T) {
+	client := newClient(t, &fakeExportRepo{films: []models.Film{{FilmID: 1}}})
+
+	for name, ctx := range map[string]context.Context{
+		"missing": context.Background(),
+		"wrong":   metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer nope"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			stream, err := client.ListAllFilms(ctx, &filmexportv1.ListAllFilmsRequest{})
+			require.NoError(t, err)
+			_, err = receiveAll(stream)
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	}
+}
+
+func TestListAllFilms_InvalidRequest(t *testing.T) {
+	client := newClient(t, &fakeExportRepo{})
+
+	stream, err := client.ListAllFilms(authorized(t), &filmexportv1.ListAllFilmsRequest{StoreId: -1})
+	require.NoError(t, err)
+	_, err = receiveAll(stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListAllFilms_RepositoryError(t *testing.T) {
+	client := newClient(t, &fakeExportRepo{err: errors.New("connection refused")})
+
+	stream, err := client.ListAllFilms(authorized(t), &filmexportv1.ListAllFilmsRequest{})
+	require.NoError(t, err)
+	_, err = receiveAll(stream)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, err.Error(), "connection refused")
+}
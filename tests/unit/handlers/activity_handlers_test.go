@@ -0,0 +1,132 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockActivityService struct {
+	mock.Mock
+}
+
+func (m *MockActivityService) Follow(ctx context.Context, followerID, followedID int) error {
+	return m.Called(ctx, followerID, followedID).Error(0)
+}
+
+func (m *MockActivityService) Unfollow(ctx context.Context, followerID, followedID int) error {
+	return m.Called(ctx, followerID, followedID).Error(0)
+}
+
+func (m *MockActivityService) ListFollowing(ctx context.Context, customerID int) ([]models.Follow, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
+
+func (m *MockActivityService) GetFeed(
+	ctx context.Context,
+	filters models.ActivityFeedFilters,
+) (*pagination.Paginated[models.Activity], error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.Activity]), args.Error(1)
+}
+
+func (m *MockActivityService) RecordActivity(ctx context.Context, activity models.Activity) error {
+	return m.Called(ctx, activity).Error(0)
+}
+
+func TestActivityHandler_Follow(t *testing.T) {
+	tests := []struct {
+		name               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "followed", expectedStatusCode: http.StatusOK},
+		{name: "unknown customer", mockError: repository.ErrCustomerNotFound, expectedStatusCode: http.StatusNotFound},
+		{name: "self", mockError: service.ErrSelfFollow, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockActivityService)
+			handler := handlers.NewActivityHandler(mockService)
+			mockService.On("Follow", mock.Anything, 600, 601).Return(tt.mockError)
+
+			req := httptest.NewRequest(http.MethodPut, "/customers/600/following/601", nil)
+			req.SetPathValue("id", "600")
+			req.SetPathValue("followedID", "601")
+			w := httptest.NewRecorder()
+			handler.Follow(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActivityHandler_GetFeed(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedFilters    *models.ActivityFeedFilters
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "defaults",
+			expectedFilters:    &models.ActivityFeedFilters{Page: 1, Limit: 20},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "paged",
+			query:              "?page=3&limit=5",
+			expectedFilters:    &models.ActivityFeedFilters{Page: 3, Limit: 5},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not a customer",
+			expectedFilters:    &models.ActivityFeedFilters{Page: 1, Limit: 20},
+			mockError:          service.ErrFeedRequiresCustomer,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{name: "limit too large", query: "?limit=500", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockActivityService)
+			handler := handlers.NewActivityHandler(mockService)
+			if tt.expectedFilters != nil {
+				if tt.mockError != nil {
+					mockService.On("GetFeed", mock.Anything, *tt.expectedFilters).Return(nil, tt.mockError)
+				} else {
+					mockService.On("GetFeed", mock.Anything, *tt.expectedFilters).
+						Return(&pagination.Paginated[models.Activity]{Items: []models.Activity{}}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/feed"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.GetFeed(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
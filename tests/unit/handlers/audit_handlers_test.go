@@ -0,0 +1,97 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) GetStaffActivity(
+	ctx context.Context,
+	staffID int,
+	filters models.AuditFilters,
+) (*pagination.Paginated[models.AuditEntry], error) {
+	args := m.Called(ctx, staffID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.AuditEntry]), args.Error(1)
+}
+
+func TestAuditHandler_GetStaffActivity(t *testing.T) {
+	tests := []struct {
+		name               string
+		staffID            string
+		query              string
+		expectedFilters    *models.AuditFilters
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "defaults",
+			staffID:            "1",
+			expectedFilters:    &models.AuditFilters{Params: pagination.Params{Page: 1, Limit: 20}},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:    "actions and date range",
+			staffID: "1",
+			query:   "?action=customer.erased,customer.merged&from=2026-10-01&to=2026-10-15&limit=5",
+			expectedFilters: &models.AuditFilters{
+				Actions: []string{models.AuditActionCustomerErased, models.AuditActionCustomerMerged},
+				From:    timePtr(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)),
+				To:      timePtr(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)),
+				Params:  pagination.Params{Page: 1, Limit: 5},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "staff not found",
+			staffID:            "1",
+			expectedFilters:    &models.AuditFilters{Params: pagination.Params{Page: 1, Limit: 20}},
+			mockError:          repository.ErrStaffNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "invalid staff ID", staffID: "abc", expectedStatusCode: http.StatusBadRequest},
+		{name: "reversed range", staffID: "1", query: "?from=2026-10-15&to=2026-10-01",
+			expectedStatusCode: http.StatusBadRequest},
+		{name: "limit too large", staffID: "1", query: "?limit=1000", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuditService)
+			handler := handlers.NewAuditHandler(mockService)
+			if tt.expectedFilters != nil {
+				if tt.mockError != nil {
+					mockService.On("GetStaffActivity", mock.Anything, 1, *tt.expectedFilters).Return(nil, tt.mockError)
+				} else {
+					mockService.On("GetStaffActivity", mock.Anything, 1, *tt.expectedFilters).
+						Return(pagination.New([]models.AuditEntry{}, 0, tt.expectedFilters.Params), nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/staff/"+tt.staffID+"/activity"+tt.query, nil)
+			req.SetPathValue("id", tt.staffID)
+			w := httptest.NewRecorder()
+			handler.GetStaffActivity(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
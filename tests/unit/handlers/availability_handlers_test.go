@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockAvailabilityService struct {
+	mock.Mock
+}
+
+func (m *MockAvailabilityService) NotifyMe(ctx context.Context, filmID int) (*models.AvailabilityAlert, bool, error) {
+	args := m.Called(ctx, filmID)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*models.AvailabilityAlert), args.Bool(1), args.Error(2)
+}
+
+func (m *MockAvailabilityService) NotifyAvailable(ctx context.Context, filmID int) error {
+	return m.Called(ctx, filmID).Error(0)
+}
+
+func TestAvailabilityHandler_NotifyMe(t *testing.T) {
+	alert := &models.AvailabilityAlert{ID: 1, FilmID: 8, CustomerID: 600}
+	tests := []struct {
+		name               string
+		filmID             string
+		created            bool
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "new alert", filmID: "8", created: true, expectedStatusCode: http.StatusCreated},
+		{name: "pending alert", filmID: "8", expectedStatusCode: http.StatusOK},
+		{
+			name:               "film not found",
+			filmID:             "8",
+			mockError:          repository.ErrFilmNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "not a customer",
+			filmID:             "8",
+			mockError:          service.ErrAlertRequiresCustomer,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "database error",
+			filmID:             "8",
+			mockError:          errors.New("boom"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "invalid film ID", filmID: "abc", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAvailabilityService)
+			handler := handlers.NewAvailabilityHandler(mockService)
+			if tt.filmID == "8" {
+				if tt.mockError != nil {
+					mockService.On("NotifyMe", mock.Anything, 8).Return(nil, false, tt.mockError)
+				} else {
+					mockService.On("NotifyMe", mock.Anything, 8).Return(alert, tt.created, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/films/"+tt.filmID+"/notify-me", nil)
+			req.SetPathValue("id", tt.filmID)
+			w := httptest.NewRecorder()
+			handler.NotifyMe(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode < http.StatusBadRequest {
+				var body models.AvailabilityAlert
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, *alert, body)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockBackgroundJobService struct {
+	mock.Mock
+}
+
+func (m *MockBackgroundJobService) ListJobs(
+	ctx context.Context,
+	filters models.BackgroundJobFilters,
+) ([]models.BackgroundJob, error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BackgroundJob), args.Error(1)
+}
+
+func (m *MockBackgroundJobService) GetJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BackgroundJob), args.Error(1)
+}
+
+func (m *MockBackgroundJobService) RetryJob(ctx context.Context, jobID int64) (*models.BackgroundJob, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BackgroundJob), args.Error(1)
+}
+
+func (m *MockBackgroundJobService) PurgeJobs(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func TestBackgroundJobHandler_ListJobs(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedFilters    *models.BackgroundJobFilters
+		expectedStatusCode int
+	}{
+		{
+			name:               "defaults",
+			expectedFilters:    &models.BackgroundJobFilters{Limit: 50},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "dead jobs of a kind",
+			query: "?status=dead&kind=notifications.email&limit=5",
+			expectedFilters: &models.BackgroundJobFilters{
+				Status: models.JobStatusDead, Kind: "notifications.email", Limit: 5,
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "unknown status", query: "?status=stuck", expectedStatusCode: http.StatusBadRequest},
+		{name: "limit too large", query: "?limit=1000", expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid limit", query: "?limit=abc", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBackgroundJobService)
+			handler := handlers.NewBackgroundJobHandler(mockService)
+			if tt.expectedFilters != nil {
+				mockService.On("ListJobs", mock.Anything, *tt.expectedFilters).
+					Return([]models.BackgroundJob{{ID: 1, Status: models.JobStatusDead}}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/jobs"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.ListJobs(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBackgroundJobHandler_RetryJob(t *testing.T) {
+	tests := []struct {
+		name               string
+		mockResponse       *models.BackgroundJob
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "requeued",
+			mockResponse:       &models.BackgroundJob{ID: 7, Status: models.JobStatusPending},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "not found", mockError: repository.ErrJobNotFound, expectedStatusCode: http.StatusNotFound},
+		{name: "not dead", mockError: repository.ErrJobNotDead, expectedStatusCode: http.StatusConflict},
+		{name: "database error", mockError: errors.New("boom"), expectedStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBackgroundJobService)
+			handler := handlers.NewBackgroundJobHandler(mockService)
+			if tt.mockResponse != nil {
+				mockService.On("RetryJob", mock.Anything, int64(7)).Return(tt.mockResponse, nil)
+			} else {
+				mockService.On("RetryJob", mock.Anything, int64(7)).Return(nil, tt.mockError)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/jobs/7/retry", nil)
+			req.SetPathValue("id", "7")
+			w := httptest.NewRecorder()
+			handler.RetryJob(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/ical"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockCalendarService struct {
+	mock.Mock
+}
+
+func (m *MockCalendarService) IssueCalendarURL(
+	ctx context.Context,
+	customerID int,
+) (*models.CalendarSubscription, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CalendarSubscription), args.Error(1)
+}
+
+func (m *MockCalendarService) RentalCalendar(ctx context.Context, customerID int) (*ical.Calendar, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ical.Calendar), args.Error(1)
+}
+
+func TestCalendarHandler_IssueCalendarURL(t *testing.T) {
+	mockService := new(MockCalendarService)
+	handler := handlers.NewCalendarHandler(mockService)
+	mockService.On("IssueCalendarURL", mock.Anything, 600).Return(&models.CalendarSubscription{
+		URL:       "http://localhost:8080/api/v1/customers/600/rentals.ics?token=abc",
+		ExpiresAt: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/customers/600/calendar-token", nil)
+	req.SetPathValue("id", "600")
+	w := httptest.NewRecorder()
+	handler.IssueCalendarURL(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"url":"http://localhost:8080/api/v1/customers/600/rentals.ics?token=abc",`+
+		`"expires_at":"2025-05-01T00:00:00Z"}`, w.Body.String())
+}
+
+func TestCalendarHandler_GetRentalCalendar(t *testing.T) {
+	mockService := new(MockCalendarService)
+	handler := handlers.NewCalendarHandler(mockService)
+	mockService.On("RentalCalendar", mock.Anything, 600).
+		Return(&ical.Calendar{Events: []ical.Event{{UID: "rental-7@mockbuster", Summary: "Return Academy Dinosaur"}}}, nil)
+	mockService.On("RentalCalendar", mock.Anything, 999).Return(nil, repository.ErrCustomerNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/600/rentals.ics?token=abc", nil)
+	req.SetPathValue("id", "600")
+	w := httptest.NewRecorder()
+	handler.GetRentalCalendar(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "private, max-age=300", w.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), "SUMMARY:Return Academy Dinosaur\r\n")
+
+	req = httptest.NewRequest(http.MethodGet, "/customers/999/rentals.ics?token=abc", nil)
+	req.SetPathValue("id", "999")
+	w = httptest.NewRecorder()
+	handler.GetRentalCalendar(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,197 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockCartService struct {
+	mock.Mock
+}
+
+func (m *MockCartService) GetCart(ctx context.Context, customerID int) (*models.Cart, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Cart), args.Error(1)
+}
+
+func (m *MockCartService) AddItem(ctx context.Context, customerID, filmID int) (*models.Cart, error) {
+	args := m.Called(ctx, customerID, filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Cart), args.Error(1)
+}
+
+func (m *MockCartService) RemoveItem(ctx context.Context, customerID, filmID int) (*models.Cart, error) {
+	args := m.Called(ctx, customerID, filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Cart), args.Error(1)
+}
+
+func (m *MockCartService) Checkout(
+	ctx context.Context,
+	customerID int,
+	checkoutReq models.CheckoutRequest,
+) (*models.Checkout, error) {
+	args := m.Called(ctx, customerID, checkoutReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Checkout), args.Error(1)
+}
+
+func TestCartHandler_AddItem(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectMock         bool
+		expectedStatusCode int
+	}{
+		{name: "added", body: `{"film_id": 1}`, expectMock: true, expectedStatusCode: http.StatusOK},
+		{
+			name:               "film not found",
+			body:               `{"film_id": 1}`,
+			mockError:          repository.ErrFilmNotFound,
+			expectMock:         true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "missing film", body: `{}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCartService)
+			handler := handlers.NewCartHandler(mockService)
+			if tt.expectMock {
+				call := mockService.On("AddItem", mock.Anything, 600, 1)
+				if tt.mockError != nil {
+					call.Return(nil, tt.mockError)
+				} else {
+					call.Return(&models.Cart{CustomerID: 600, Items: []models.CartItem{{FilmID: 1}}}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/customers/600/cart/items", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "600")
+			w := httptest.NewRecorder()
+			handler.AddItem(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartHandler_Checkout(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		expectedRequest    *models.CheckoutRequest
+		mockError          error
+		expectedStatusCode int
+		expectedErrorCode  string
+	}{
+		{
+			name:               "without a body",
+			expectedRequest:    &models.CheckoutRequest{},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "with a coupon",
+			body:               `{"coupon_code": "FIVEOFF"}`,
+			expectedRequest:    &models.CheckoutRequest{CouponCode: "FIVEOFF"},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "empty cart",
+			expectedRequest:    &models.CheckoutRequest{},
+			mockError:          repository.ErrCartEmpty,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "out of stock",
+			expectedRequest:    &models.CheckoutRequest{},
+			mockError:          fmt.Errorf("%w: film 1", repository.ErrFilmUnavailable),
+			expectedStatusCode: http.StatusConflict,
+			expectedErrorCode:  "film_unavailable",
+		},
+		{
+			name:               "cart changed",
+			expectedRequest:    &models.CheckoutRequest{},
+			mockError:          repository.ErrCartChanged,
+			expectedStatusCode: http.StatusConflict,
+			expectedErrorCode:  "cart_changed",
+		},
+		{
+			name:               "used-up coupon",
+			body:               `{"coupon_code": "FIVEOFF"}`,
+			expectedRequest:    &models.CheckoutRequest{CouponCode: "FIVEOFF"},
+			mockError:          repository.ErrCouponExhausted,
+			expectedStatusCode: http.StatusConflict,
+			expectedErrorCode:  "coupon_exhausted",
+		},
+		{
+			name:               "spent gift card",
+			body:               `{"gift_card_code": "7KQF-M2XD-9HRT-WB4C"}`,
+			expectedRequest:    &models.CheckoutRequest{GiftCardCode: "7KQF-M2XD-9HRT-WB4C"},
+			mockError:          repository.ErrGiftCardEmpty,
+			expectedStatusCode: http.StatusConflict,
+			expectedErrorCode:  "gift_card_empty",
+		},
+		{
+			name:               "unknown gift card",
+			body:               `{"gift_card_code": "7KQF-M2XD-9HRT-WB4C"}`,
+			expectedRequest:    &models.CheckoutRequest{GiftCardCode: "7KQF-M2XD-9HRT-WB4C"},
+			mockError:          repository.ErrGiftCardNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "invalid coupon code", body: `{"coupon_code": "NO CODE"}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCartService)
+			handler := handlers.NewCartHandler(mockService)
+			if tt.expectedRequest != nil {
+				call := mockService.On("Checkout", mock.Anything, 600, *tt.expectedRequest)
+				if tt.mockError != nil {
+					call.Return(nil, tt.mockError)
+				} else {
+					call.Return(&models.Checkout{ID: 1, CustomerID: 600}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/customers/600/checkout", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "600")
+			w := httptest.NewRecorder()
+			handler.Checkout(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedErrorCode != "" {
+				var errorResponse models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+				assert.Equal(t, tt.expectedErrorCode, errorResponse.Code)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
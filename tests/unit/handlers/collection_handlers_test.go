@@ -0,0 +1,138 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockCollectionService struct {
+	mock.Mock
+}
+
+func (m *MockCollectionService) CreateCollection(
+	ctx context.Context,
+	collectionReq models.CollectionRequest,
+) (*models.Collection, error) {
+	args := m.Called(ctx, collectionReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) ListCollections(ctx context.Context) ([]models.Collection, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) GetCollection(ctx context.Context, collectionID int) (*models.Collection, error) {
+	args := m.Called(ctx, collectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) UpdateCollection(
+	ctx context.Context,
+	collectionID int,
+	collectionReq models.CollectionRequest,
+) (*models.Collection, error) {
+	args := m.Called(ctx, collectionID, collectionReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) DeleteCollection(ctx context.Context, collectionID int) error {
+	return m.Called(ctx, collectionID).Error(0)
+}
+
+func TestCollectionHandler_GetCollection(t *testing.T) {
+	mockService := new(MockCollectionService)
+	handler := handlers.NewCollectionHandler(mockService)
+	mockService.On("GetCollection", mock.Anything, 1).Return(&models.Collection{
+		ID: 1, Name: "Alien Franchise", FilmCount: 1,
+		Films: []models.CollectionFilm{{FilmID: 8, Title: "ALIEN CENTER", Position: 1}},
+	}, nil)
+	mockService.On("GetCollection", mock.Anything, 2).Return(nil, repository.ErrCollectionNotFound)
+
+	for id, expectedStatusCode := range map[string]int{"1": http.StatusOK, "2": http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodGet, "/collections/"+id, nil)
+		req.SetPathValue("id", id)
+		w := httptest.NewRecorder()
+		handler.GetCollection(w, req)
+
+		assert.Equal(t, expectedStatusCode, w.Code)
+	}
+}
+
+func TestCollectionHandler_CreateCollection(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		expectedRequest    *models.CollectionRequest
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "created",
+			body:               `{"name": "Alien Franchise", "film_ids": [8, 3]}`,
+			expectedRequest:    &models.CollectionRequest{Name: "Alien Franchise", FilmIDs: []int{8, 3}},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "name taken",
+			body:               `{"name": "Alien Franchise"}`,
+			expectedRequest:    &models.CollectionRequest{Name: "Alien Franchise"},
+			mockError:          repository.ErrCollectionNameTaken,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "unknown film",
+			body:               `{"name": "Alien Franchise", "film_ids": [99999]}`,
+			expectedRequest:    &models.CollectionRequest{Name: "Alien Franchise", FilmIDs: []int{99999}},
+			mockError:          repository.ErrInvalidReference,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "missing name", body: `{"film_ids": [8]}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "repeated film", body: `{"name": "Alien", "film_ids": [8, 8]}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid film ID", body: `{"name": "Alien", "film_ids": [0]}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCollectionService)
+			handler := handlers.NewCollectionHandler(mockService)
+			if tt.expectedRequest != nil {
+				call := mockService.On("CreateCollection", mock.Anything, *tt.expectedRequest)
+				if tt.mockError != nil {
+					call.Return(nil, tt.mockError)
+				} else {
+					call.Return(&models.Collection{ID: 1, Name: tt.expectedRequest.Name}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/collections", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			handler.CreateCollection(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockCouponService struct {
+	mock.Mock
+}
+
+func (m *MockCouponService) CreateCoupon(ctx context.Context, couponReq models.CouponRequest) (*models.Coupon, error) {
+	args := m.Called(ctx, couponReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Coupon), args.Error(1)
+}
+
+func (m *MockCouponService) ListCoupons(ctx context.Context) ([]models.Coupon, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Coupon), args.Error(1)
+}
+
+func (m *MockCouponService) ValidateCoupon(
+	ctx context.Context,
+	code string,
+	customerID int,
+	amount float64,
+) (*models.CouponQuote, error) {
+	args := m.Called(ctx, code, customerID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CouponQuote), args.Error(1)
+}
+
+func (m *MockCouponService) RedeemCoupon(
+	ctx context.Context,
+	code string,
+	customerID int,
+	amount float64,
+) (*models.CouponRedemption, error) {
+	args := m.Called(ctx, code, customerID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CouponRedemption), args.Error(1)
+}
+
+func TestCouponHandler_CreateCoupon(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectMock         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "percent off",
+			body:               `{"code": "summer20", "percent_off": 20, "max_per_customer": 1}`,
+			expectMock:         true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "amount off",
+			body:               `{"code": "FIVEOFF", "amount_off": 5}`,
+			expectMock:         true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "code taken",
+			body:               `{"code": "FIVEOFF", "amount_off": 5}`,
+			mockError:          repository.ErrCouponCodeTaken,
+			expectMock:         true,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{name: "no discount", body: `{"code": "NOTHING"}`, expectedStatusCode: http.StatusBadRequest},
+		{
+			name:               "both discounts",
+			body:               `{"code": "BOTH", "percent_off": 10, "amount_off": 5}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "over 100 percent", body: `{"code": "FREE", "percent_off": 150}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "code with spaces", body: `{"code": "TWO WORDS", "amount_off": 5}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCouponService)
+			handler := handlers.NewCouponHandler(mockService)
+			if tt.expectMock {
+				call := mockService.On("CreateCoupon", mock.Anything, mock.AnythingOfType("models.CouponRequest"))
+				if tt.mockError != nil {
+					call.Return(nil, tt.mockError)
+				} else {
+					call.Return(&models.Coupon{ID: 1, Code: "SUMMER20"}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/coupons", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			handler.CreateCoupon(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCouponHandler_RedeemCoupon(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectMock         bool
+		expectedStatusCode int
+		expectedErrorCode  string
+	}{
+		{
+			name:               "redeemed",
+			body:               `{"amount": 9.98}`,
+			expectMock:         true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "unknown code",
+			body:               `{"amount": 9.98}`,
+			mockError:          repository.ErrCouponNotFound,
+			expectMock:         true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "expired",
+			body:               `{"amount": 9.98}`,
+			mockError:          repository.ErrCouponExpired,
+			expectMock:         true,
+			expectedStatusCode: http.StatusConflict,
+			expectedErrorCode:  "coupon_expired",
+		},
+		{
+			name:               "used up",
+			body:               `{"amount": 9.98}`,
+			mockError:          repository.ErrCouponExhausted,
+			expectMock:         true,
+			expectedStatusCode: http.StatusConflict,
+			expectedErrorCode:  "coupon_exhausted",
+		},
+		{
+			name:               "database error",
+			body:               `{"amount": 9.98}`,
+			mockError:          errors.New("boom"),
+			expectMock:         true,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "missing amount", body: `{}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "negative amount", body: `{"amount": -1}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCouponService)
+			handler := handlers.NewCouponHandler(mockService)
+			if tt.expectMock {
+				call := mockService.On("RedeemCoupon", mock.Anything, "summer20", 600, 9.98)
+				if tt.mockError != nil {
+					call.Return(nil, tt.mockError)
+				} else {
+					call.Return(&models.CouponRedemption{ID: 1, Code: "SUMMER20", CustomerID: 600}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/customers/600/coupons/summer20/redeem",
+				bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "600")
+			req.SetPathValue("code", "summer20")
+			w := httptest.NewRecorder()
+			handler.RedeemCoupon(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedErrorCode != "" {
+				var errorResponse models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+				assert.Equal(t, tt.expectedErrorCode, errorResponse.Code)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockCustomerExportService struct {
+	mock.Mock
+}
+
+func (m *MockCustomerExportService) RequestExport(
+	ctx context.Context,
+	customerID int,
+	format string,
+) (*models.CustomerExport, error) {
+	args := m.Called(ctx, customerID, format)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerExport), args.Error(1)
+}
+
+func (m *MockCustomerExportService) GetExport(
+	ctx context.Context,
+	customerID, exportID int,
+) (*models.CustomerExport, error) {
+	args := m.Called(ctx, customerID, exportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerExport), args.Error(1)
+}
+
+func (m *MockCustomerExportService) GetExportFile(
+	ctx context.Context,
+	export *models.CustomerExport,
+) ([]byte, error) {
+	args := m.Called(ctx, export)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockCustomerExportService) HandleExportJob(ctx context.Context, payload json.RawMessage) error {
+	return m.Called(ctx, payload).Error(0)
+}
+
+func TestCustomerExportHandler_GetExportPending(t *testing.T) {
+	mockService := new(MockCustomerExportService)
+	handler := handlers.NewCustomerExportHandler(mockService)
+	mockService.On("RequestExport", mock.Anything, 600, "json").Return(&models.CustomerExport{
+		ID: 3, CustomerID: 600, Format: "json", Status: models.CustomerExportStatusPending,
+		StatusURL: "/api/v1/customers/600/exports/3",
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/600/export", nil)
+	req.SetPathValue("id", "600")
+	w := httptest.NewRecorder()
+	handler.GetExport(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/api/v1/customers/600/exports/3", w.Header().Get("Location"))
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomerExportHandler_GetExportReady(t *testing.T) {
+	mockService := new(MockCustomerExportService)
+	handler := handlers.NewCustomerExportHandler(mockService)
+	export := &models.CustomerExport{
+		ID: 3, CustomerID: 600, Format: "zip", Status: models.CustomerExportStatusReady,
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	mockService.On("RequestExport", mock.Anything, 600, "zip").Return(export, nil)
+	mockService.On("GetExportFile", mock.Anything, export).Return([]byte("PK"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/600/export?format=zip", nil)
+	req.SetPathValue("id", "600")
+	w := httptest.NewRecorder()
+	handler.GetExport(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="customer-600-export-2024-05-01.zip"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "PK", w.Body.String())
+}
+
+func TestCustomerExportHandler_GetExportUnknownFormat(t *testing.T) {
+	mockService := new(MockCustomerExportService)
+	handler := handlers.NewCustomerExportHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/600/export?format=xml", nil)
+	req.SetPathValue("id", "600")
+	w := httptest.NewRecorder()
+	handler.GetExport(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "RequestExport", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCustomerExportHandler_GetExportStatus(t *testing.T) {
+	tests := []struct {
+		name               string
+		mockExport         *models.CustomerExport
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "ready",
+			mockExport:         &models.CustomerExport{ID: 3, CustomerID: 600, Status: models.CustomerExportStatusReady},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "not found", mockError: repository.ErrCustomerExportNotFound, expectedStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCustomerExportService)
+			handler := handlers.NewCustomerExportHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("GetExport", mock.Anything, 600, 3).Return(nil, tt.mockError)
+			} else {
+				mockService.On("GetExport", mock.Anything, 600, 3).Return(tt.mockExport, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/customers/600/exports/3", nil)
+			req.SetPathValue("id", "600")
+			req.SetPathValue("exportID", "3")
+			w := httptest.NewRecorder()
+			handler.GetExportStatus(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockCustomerService struct {
+	mock.Mock
+}
+
+func (m *MockCustomerService) Register(
+	ctx context.Context,
+	registerReq models.CustomerRegisterRequest,
+) (*models.Customer, error) {
+	args := m.Called(ctx, registerReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) Login(
+	ctx context.Context,
+	loginReq models.CustomerLoginRequest,
+) (*models.TokenResponse, error) {
+	args := m.Called(ctx, loginReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TokenResponse), args.Error(1)
+}
+
+func (m *MockCustomerService) EraseData(ctx context.Context, customerID int) (*models.CustomerErasure, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerErasure), args.Error(1)
+}
+
+func (m *MockCustomerService) MergeCustomers(
+	ctx context.Context,
+	sourceID, targetID int,
+) (*models.CustomerMerge, error) {
+	args := m.Called(ctx, sourceID, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerMerge), args.Error(1)
+}
+
+func TestCustomerHandler_Register(t *testing.T) {
+	validBody := `{"first_name":"Jane","last_name":"Doe","email":"jane@example.com",` +
+		`"address_id":3,"store_id":1,"password":"s3cret-pass"}`
+
+	tests := []struct {
+		name               string
+		body               string
+		mockResponse       *models.Customer
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "successful registration",
+			body:               validBody,
+			mockResponse:       &models.Customer{CustomerID: 600, Email: "jane@example.com", Active: true},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "email taken",
+			body:               validBody,
+			mockError:          repository.ErrEmailTaken,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "invalid email",
+			body:               `{"first_name":"Jane","last_name":"Doe","email":"nope","password":"s3cret-pass"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCustomerService := new(MockCustomerService)
+			handler := handlers.NewCustomerHandler(mockCustomerService)
+			if tt.mockResponse != nil || tt.mockError != nil {
+				mockCustomerService.On("Register", mock.Anything, mock.Anything).Return(tt.mockResponse, tt.mockError)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/customers/register", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.Register(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockCustomerService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCustomerHandler_EraseData(t *testing.T) {
+	tests := []struct {
+		name               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "erased", expectedStatusCode: http.StatusOK},
+		{name: "customer not found", mockError: repository.ErrCustomerNotFound, expectedStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCustomerService)
+			handler := handlers.NewCustomerHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("EraseData", mock.Anything, 600).Return(nil, tt.mockError)
+			} else {
+				mockService.On("EraseData", mock.Anything, 600).
+					Return(&models.CustomerErasure{CustomerID: 600, CommentsAnonymized: 2, CredentialsDeleted: true}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/customers/600/data", nil)
+			req.SetPathValue("id", "600")
+			w := httptest.NewRecorder()
+			handler.EraseData(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.mockError == nil {
+				assert.Contains(t, w.Body.String(), `"comments_anonymized":2`)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCustomerHandler_MergeCustomers(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "merged", query: "?into=601", expectedStatusCode: http.StatusOK},
+		{name: "customer not found", query: "?into=601", mockError: repository.ErrCustomerNotFound,
+			expectedStatusCode: http.StatusNotFound},
+		{name: "same customer", query: "?into=601", mockError: service.ErrMergeSameCustomer,
+			expectedStatusCode: http.StatusBadRequest},
+		{name: "missing into", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCustomerService)
+			handler := handlers.NewCustomerHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("MergeCustomers", mock.Anything, 600, 601).Return(nil, tt.mockError)
+			} else {
+				mockService.On("MergeCustomers", mock.Anything, 600, 601).
+					Return(&models.CustomerMerge{SourceID: 600, TargetID: 601, CommentsMoved: 2, RentalsMoved: 5}, nil).
+					Maybe()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/customers/600/merge"+tt.query, nil)
+			req.SetPathValue("id", "600")
+			w := httptest.NewRecorder()
+			handler.MergeCustomers(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"rentals_moved":5`)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockCustomerListService struct {
+	mock.Mock
+}
+
+func (m *MockCustomerListService) CreateList(
+	ctx context.Context,
+	customerID int,
+	listReq models.CustomerListRequest,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(ctx, customerID, listReq))
+}
+
+func (m *MockCustomerListService) ListCustomerLists(
+	ctx context.Context,
+	customerID int,
+) ([]models.CustomerList, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CustomerList), args.Error(1)
+}
+
+func (m *MockCustomerListService) GetCustomerList(
+	ctx context.Context,
+	customerID, listID int,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(ctx, customerID, listID))
+}
+
+func (m *MockCustomerListService) GetSharedList(ctx context.Context, listID int) (*models.CustomerList, error) {
+	return m.list(m.Called(ctx, listID))
+}
+
+func (m *MockCustomerListService) UpdateList(
+	ctx context.Context,
+	customerID, listID int,
+	listReq models.CustomerListRequest,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(ctx, customerID, listID, listReq))
+}
+
+func (m *MockCustomerListService) DeleteList(ctx context.Context, customerID, listID int) error {
+	return m.Called(ctx, customerID, listID).Error(0)
+}
+
+func (m *MockCustomerListService) AddListEntry(
+	ctx context.Context,
+	customerID, listID, filmID int,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(ctx, customerID, listID, filmID))
+}
+
+func (m *MockCustomerListService) RemoveListEntry(
+	ctx context.Context,
+	customerID, listID, filmID int,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(ctx, customerID, listID, filmID))
+}
+
+func (m *MockCustomerListService) ReorderListEntries(
+	ctx context.Context,
+	customerID, listID int,
+	filmIDs []int,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(ctx, customerID, listID, filmIDs))
+}
+
+func (m *MockCustomerListService) list(args mock.Arguments) (*models.CustomerList, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerList), args.Error(1)
+}
+
+func TestCustomerListHandler_CreateList(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		expectedStatusCode int
+	}{
+		{name: "created", body: `{"name": "Best 80s Horror", "visibility": "public"}`, expectedStatusCode: 201},
+		{name: "missing name", body: `{"visibility": "public"}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "unknown visibility", body: `{"name": "x", "visibility": "friends"}`, expectedStatusCode: 400},
+		{name: "invalid body", body: `{`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCustomerListService)
+			handler := handlers.NewCustomerListHandler(mockService)
+			mockService.On("CreateList", mock.Anything, 600, mock.Anything).
+				Return(&models.CustomerList{ID: 1, CustomerID: 600, Visibility: "public"}, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/customers/600/lists", strings.NewReader(tt.body))
+			req.SetPathValue("id", "600")
+			w := httptest.NewRecorder()
+			handler.CreateList(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestCustomerListHandler_AddListEntry(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "added", body: `{"film_id": 8}`, expectedStatusCode: http.StatusCreated},
+		{name: "already on list", body: `{"film_id": 8}`, mockError: repository.ErrListEntryExists, expectedStatusCode: 409},
+		{name: "unknown film", body: `{"film_id": 8}`, mockError: repository.ErrFilmNotFound, expectedStatusCode: 404},
+		{name: "another customer's list", body: `{"film_id": 8}`, mockError: repository.ErrListNotFound,
+			expectedStatusCode: http.StatusNotFound},
+		{name: "missing film", body: `{}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCustomerListService)
+			handler := handlers.NewCustomerListHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("AddListEntry", mock.Anything, 600, 7, 8).Return(nil, tt.mockError)
+			} else {
+				mockService.On("AddListEntry", mock.Anything, 600, 7, 8).Return(&models.CustomerList{ID: 7}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/customers/600/lists/7/entries", strings.NewReader(tt.body))
+			req.SetPathValue("id", "600")
+			req.SetPathValue("listID", "7")
+			w := httptest.NewRecorder()
+			handler.AddListEntry(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestCustomerListHandler_ReorderListEntries(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "reordered", body: `{"film_ids": [3, 8]}`, expectedStatusCode: http.StatusOK},
+		{name: "films differ from the list", body: `{"film_ids": [3, 8]}`, mockError: repository.ErrListOrderMismatch,
+			expectedStatusCode: http.StatusBadRequest},
+		{name: "duplicate film", body: `{"film_ids": [3, 3]}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "empty order", body: `{"film_ids": []}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCustomerListService)
+			handler := handlers.NewCustomerListHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("ReorderListEntries", mock.Anything, 600, 7, []int{3, 8}).Return(nil, tt.mockError)
+			} else {
+				mockService.On("ReorderListEntries", mock.Anything, 600, 7, []int{3, 8}).
+					Return(&models.CustomerList{ID: 7}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/customers/600/lists/7/entries", strings.NewReader(tt.body))
+			req.SetPathValue("id", "600")
+			req.SetPathValue("listID", "7")
+			w := httptest.NewRecorder()
+			handler.ReorderListEntries(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestCustomerListHandler_GetSharedListHidesPrivateLists(t *testing.T) {
+	mockService := new(MockCustomerListService)
+	handler := handlers.NewCustomerListHandler(mockService)
+	mockService.On("GetSharedList", mock.Anything, 7).Return(nil, repository.ErrListNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/lists/7", nil)
+	req.SetPathValue("id", "7")
+	w := httptest.NewRecorder()
+	handler.GetSharedList(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,122 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockDashboardService struct {
+	mock.Mock
+}
+
+func (m *MockDashboardService) GetDashboard(ctx context.Context) (*models.Dashboard, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Dashboard), args.Error(1)
+}
+
+func (m *MockDashboardService) GetCategoryStats(ctx context.Context, categoryID int) (*models.CategoryStats, error) {
+	args := m.Called(ctx, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategoryStats), args.Error(1)
+}
+
+func TestDashboardHandler_GetDashboard(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := handlers.NewDashboardHandler(mockService)
+	mockService.On("GetDashboard", mock.Anything).Return(&models.Dashboard{
+		FilmsCount: 1000, RentalsToday: 42, PendingComments: 7, RevenueThisMonth: 1234.5, ErrorRate: 0.02,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/dashboard", nil)
+	w := httptest.NewRecorder()
+	handler.GetDashboard(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"films_count":1000`)
+	assert.Contains(t, w.Body.String(), `"rentals_today":42`)
+	assert.Contains(t, w.Body.String(), `"error_rate":0.02`)
+	mockService.AssertExpectations(t)
+}
+
+func TestDashboardHandler_GetDashboardFails(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := handlers.NewDashboardHandler(mockService)
+	mockService.On("GetDashboard", mock.Anything).Return(nil, errors.New("boom"))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/dashboard", nil)
+	w := httptest.NewRecorder()
+	handler.GetDashboard(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestDashboardHandler_GetCategoryStats(t *testing.T) {
+	tests := []struct {
+		name           string
+		categoryID     string
+		setupMock      func(*MockDashboardService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "found",
+			categoryID: "8",
+			setupMock: func(m *MockDashboardService) {
+				m.On("GetCategoryStats", mock.Anything, 8).Return(&models.CategoryStats{
+					CategoryID: 8, Name: "Family", FilmsCount: 69, RentalsCount: 1096, Revenue: 3782.26,
+					FilmsByRating: map[string]int{"G": 20, "PG": 49},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"films_by_rating":{"G":20,"PG":49}`,
+		},
+		{
+			name:       "unknown category",
+			categoryID: "99",
+			setupMock: func(m *MockDashboardService) {
+				m.On("GetCategoryStats", mock.Anything, 99).Return(nil, repository.ErrCategoryNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "category not found",
+		},
+		{
+			name:           "invalid ID",
+			categoryID:     "family",
+			setupMock:      func(*MockDashboardService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid category ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockDashboardService)
+			tt.setupMock(mockService)
+			handler := handlers.NewDashboardHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/categories/"+tt.categoryID+"/stats", nil)
+			req.SetPathValue("id", tt.categoryID)
+			w := httptest.NewRecorder()
+			handler.GetCategoryStats(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
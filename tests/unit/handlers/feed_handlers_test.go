@@ -0,0 +1,98 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/feeds"
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockFeedService struct {
+	mock.Mock
+}
+
+func (m *MockFeedService) FilmsFeed(ctx context.Context) (*feeds.Feed, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*feeds.Feed), args.Error(1)
+}
+
+func (m *MockFeedService) FilmCommentsFeed(ctx context.Context, filmID int) (*feeds.Feed, error) {
+	args := m.Called(ctx, filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*feeds.Feed), args.Error(1)
+}
+
+func TestFeedHandler_GetFilmsFeed(t *testing.T) {
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mockService := new(MockFeedService)
+	handler := handlers.NewFeedHandler(mockService)
+	mockService.On("FilmsFeed", mock.Anything).Return(&feeds.Feed{
+		ID:      "http://localhost:8080/feeds/films.atom",
+		Title:   "Mockbuster: New films",
+		Updated: updated,
+		Entries: []feeds.Entry{{ID: "http://localhost:8080/api/v1/films/1", Title: "Academy Dinosaur"}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/feeds/films.atom", nil)
+	w := httptest.NewRecorder()
+	handler.GetFilmsFeed(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", w.Header().Get("Last-Modified"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "<?xml"))
+	assert.Contains(t, w.Body.String(), "<title>Academy Dinosaur</title>")
+
+	etag := w.Header().Get("ETag")
+	req = httptest.NewRequest(http.MethodGet, "/feeds/films.atom", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.GetFilmsFeed(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/feeds/films.atom", nil)
+	req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 12:00:00 GMT")
+	w = httptest.NewRecorder()
+	handler.GetFilmsFeed(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestFeedHandler_GetFilmCommentsFeed(t *testing.T) {
+	mockService := new(MockFeedService)
+	handler := handlers.NewFeedHandler(mockService)
+	mockService.On("FilmCommentsFeed", mock.Anything, 1).Return(&feeds.Feed{Title: "Mockbuster: Comments"}, nil)
+	mockService.On("FilmCommentsFeed", mock.Anything, 999).Return(nil, repository.ErrFilmNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/feeds/films/1/comments.atom", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	handler.GetFilmCommentsFeed(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>Mockbuster: Comments</title>")
+
+	req = httptest.NewRequest(http.MethodGet, "/feeds/films/999/comments.atom", nil)
+	req.SetPathValue("id", "999")
+	w = httptest.NewRecorder()
+	handler.GetFilmCommentsFeed(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,120 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+// fakeFilmExportRepo streams films until failAfter have been sent, then
+// returns err.
+type fakeFilmExportRepo struct {
+	films       []models.Film
+	err         error
+	failAfter   int
+	storeID     int
+	afterFilmID int
+}
+
+func (r *fakeFilmExportRepo) StreamFilms(
+	_ context.Context,
+	storeID, afterFilmID int,
+	fn func(models.Film) error,
+) error {
+	r.storeID = storeID
+	r.afterFilmID = afterFilmID
+	for i, film := range r.films {
+		if r.err != nil && i == r.failAfter {
+			return r.err
+		}
+		if err := fn(film); err != nil {
+			return err
+		}
+	}
+	return r.err
+}
+
+func exportFilms(t *testing.T, repo *fakeFilmExportRepo, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := handlers.NewFilmExportHandler(service.NewFilmExportService(repo))
+	req := httptest.NewRequest(http.MethodGet, "/exports/films.ndjson"+query, nil)
+	rr := httptest.NewRecorder()
+	handler.ExportFilms(rr, req)
+	return rr
+}
+
+func catalog(n int) []models.Film {
+	films := make([]models.Film, 0, n)
+	for id := 1; id <= n; id++ {
+		films = append(films, models.Film{FilmID: id, Title: "Film", Rating: "PG"})
+	}
+	return films
+}
+
+func TestFilmExportHandler_ExportFilms(t *testing.T) {
+	repo := &fakeFilmExportRepo{films: catalog(150)}
+
+	rr := exportFilms(t, repo, "?store_id=2&after_film_id=7")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	assert.True(t, rr.Flushed)
+	assert.Equal(t, 2, repo.storeID)
+	assert.Equal(t, 7, repo.afterFilmID)
+
+	var ids []int
+	scanner := bufio.NewScanner(rr.Body)
+	for scanner.Scan() {
+		var film models.Film
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &film))
+		ids = append(ids, film.FilmID)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, ids, 150)
+	assert.Equal(t, 1, ids[0])
+	assert.Equal(t, 150, ids[149])
+}
+
+func TestFilmExportHandler_ExportFilmsEmpty(t *testing.T) {
+	rr := exportFilms(t, &fakeFilmExportRepo{}, "")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	assert.Empty(t, rr.Body.String())
+}
+
+func TestFilmExportHandler_ExportFilmsInvalidRequest(t *testing.T) {
+	for _, query := range []string{"?store_id=abc", "?after_film_id=-1"} {
+		t.Run(query, func(t *testing.T) {
+			rr := exportFilms(t, &fakeFilmExportRepo{films: catalog(1)}, query)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
+
+func TestFilmExportHandler_ExportFilmsFailsBeforeFirstFilm(t *testing.T) {
+	rr := exportFilms(t, &fakeFilmExportRepo{err: errors.New("connection refused")}, "")
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+}
+
+func TestFilmExportHandler_ExportFilmsFailsPartway(t *testing.T) {
+	repo := &fakeFilmExportRepo{films: catalog(5), err: errors.New("connection reset"), failAfter: 3}
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		exportFilms(t, repo, "")
+	})
+}
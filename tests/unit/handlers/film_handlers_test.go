@@ -3,32 +3,43 @@ package handlers_test
 import (
 	"bytes"
 	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
-	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rxbenefits/go-hw/internal/cache"
 	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/middleware"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
 	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
 )
 
 type MockFilmService struct {
 	mock.Mock
 }
 
-func (m *MockFilmService) GetFilms(ctx context.Context, filters models.FilmFilters) (*models.FilmListResponse, error) {
+func (m *MockFilmService) GetFilms(
+	ctx context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
 	args := m.Called(ctx, filters)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.FilmListResponse), args.Error(1)
+	return args.Get(0).(*pagination.Paginated[models.Film]), args.Error(1)
 }
 
 func (m *MockFilmService) GetFilmByID(ctx context.Context, filmID int) (*models.Film, error) {
@@ -44,6 +55,25 @@ func (m *MockFilmService) GetCategories(ctx context.Context) ([]models.Category,
 	return args.Get(0).([]models.Category), args.Error(1)
 }
 
+func (m *MockFilmService) GetAdminFilms(
+	ctx context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.Film]), args.Error(1)
+}
+
+func (m *MockFilmService) GetAdminFilmByID(ctx context.Context, filmID int) (*models.Film, error) {
+	args := m.Called(ctx, filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Film), args.Error(1)
+}
+
 type MockCommentService struct {
 	mock.Mock
 }
@@ -60,16 +90,60 @@ func (m *MockCommentService) AddComment(
 	return args.Get(0).(*models.Comment), args.Error(1)
 }
 
-func (m *MockCommentService) GetCommentsByFilmID(ctx context.Context, filmID int) ([]models.Comment, error) {
+func (m *MockCommentService) SetCommentsLocked(
+	ctx context.Context,
+	filmID int,
+	locked bool,
+) (*models.CommentLockStatus, error) {
+	args := m.Called(ctx, filmID, locked)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentLockStatus), args.Error(1)
+}
+
+func (m *MockCommentService) SetShadowBanned(
+	ctx context.Context,
+	customerID int,
+	banned bool,
+) (*models.ShadowBanStatus, error) {
+	args := m.Called(ctx, customerID, banned)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShadowBanStatus), args.Error(1)
+}
+
+func (m *MockCommentService) GetCommentsByFilmID(
+	ctx context.Context,
+	filmID int,
+	filters models.CommentFilters,
+) (*pagination.Paginated[models.Comment], error) {
+	args := m.Called(ctx, filmID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.Comment]), args.Error(1)
+}
+
+func (m *MockCommentService) GetCommentSummary(ctx context.Context, filmID int) (*models.CommentSummary, error) {
 	args := m.Called(ctx, filmID)
-	return args.Get(0).([]models.Comment), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentSummary), args.Error(1)
+}
+
+func (m *MockCommentService) RefreshCommentSummaries(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
 }
 
 func TestFilmHandler_GetFilms(t *testing.T) {
 	tests := []struct {
 		name               string
 		queryParams        string
-		mockResponse       *models.FilmListResponse
+		mockResponse       *pagination.Paginated[models.Film]
 		mockError          error
 		expectedStatusCode int
 		expectedResponse   interface{}
@@ -77,8 +151,8 @@ func TestFilmHandler_GetFilms(t *testing.T) {
 		{
 			name:        "successful retrieval",
 			queryParams: "?title=test&page=1&limit=10",
-			mockResponse: &models.FilmListResponse{
-				Films: []models.Film{
+			mockResponse: &pagination.Paginated[models.Film]{
+				Items: []models.Film{
 					{FilmID: 1, Title: "Test Film", Rating: "PG"},
 				},
 				Total: 1,
@@ -86,8 +160,8 @@ func TestFilmHandler_GetFilms(t *testing.T) {
 				Limit: 10,
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: &models.FilmListResponse{
-				Films: []models.Film{
+			expectedResponse: &pagination.Paginated[models.Film]{
+				Items: []models.Film{
 					{FilmID: 1, Title: "Test Film", Rating: "PG"},
 				},
 				Total: 1,
@@ -111,7 +185,7 @@ func TestFilmHandler_GetFilms(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockFilmService := new(MockFilmService)
 			mockCommentService := new(MockCommentService)
-			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService)
+			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService, pagination.DefaultLimits)
 
 			// Setup mock expectations
 			mockFilmService.On("GetFilms", mock.Anything, mock.AnythingOfType("models.FilmFilters")).
@@ -142,6 +216,70 @@ func TestFilmHandler_GetFilms(t *testing.T) {
 	}
 }
 
+func TestFilmHandler_GetFilmsDefaultLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		queryParams string
+	}{
+		{"no limit", ""},
+		{"empty limit", "?limit="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFilmService := new(MockFilmService)
+			handler := handlers.NewFilmHandler(mockFilmService, new(MockCommentService),
+				pagination.Limits{DefaultLimit: 25, MaxLimit: 50})
+			mockFilmService.On("GetFilms", mock.Anything, models.FilmFilters{Params: pagination.Params{Page: 1, Limit: 25}}).
+				Return(&pagination.Paginated[models.Film]{Page: 1, Limit: 25}, nil)
+
+			w := httptest.NewRecorder()
+			handler.GetFilms(w, httptest.NewRequest(http.MethodGet, "/films"+tt.queryParams, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockFilmService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilmHandler_GetFilmsBindsQuery(t *testing.T) {
+	mockFilmService := new(MockFilmService)
+	handler := handlers.NewFilmHandler(mockFilmService, new(MockCommentService), pagination.DefaultLimits)
+	expected := models.FilmFilters{
+		Title:                "alien",
+		Ratings:              []string{"PG", "R"},
+		Categories:           []string{"Horror"},
+		IncludeSubcategories: true,
+		CountMode:            models.CountNone,
+		Params:               pagination.Params{Page: 2, Limit: 5},
+	}
+	mockFilmService.On("GetFilms", mock.Anything, expected).
+		Return(&pagination.Paginated[models.Film]{Page: 2, Limit: 5}, nil)
+
+	w := httptest.NewRecorder()
+	handler.GetFilms(w, httptest.NewRequest(http.MethodGet,
+		"/films?title=alien&rating=PG,%20R,&category=Horror&include_subcategories=true&count=false&page=2&limit=5", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockFilmService.AssertExpectations(t)
+}
+
+func TestFilmHandler_GetFilmsInvalidQuery(t *testing.T) {
+	for _, query := range []string{"?limit=abc", "?page=1.5"} {
+		t.Run(query, func(t *testing.T) {
+			mockFilmService := new(MockFilmService)
+			handler := handlers.NewFilmHandler(mockFilmService, new(MockCommentService), pagination.DefaultLimits)
+
+			w := httptest.NewRecorder()
+			handler.GetFilms(w, httptest.NewRequest(http.MethodGet, "/films"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "Invalid query parameter")
+			mockFilmService.AssertNotCalled(t, "GetFilms", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestFilmHandler_GetFilmByID(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -191,7 +329,7 @@ func TestFilmHandler_GetFilmByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockFilmService := new(MockFilmService)
 			mockCommentService := new(MockCommentService)
-			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService)
+			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService, pagination.DefaultLimits)
 
 			// Setup mock expectations only for valid film IDs
 			if tt.filmID != "invalid" {
@@ -204,7 +342,7 @@ func TestFilmHandler_GetFilmByID(t *testing.T) {
 
 			// Create request with mux vars
 			req := httptest.NewRequest(http.MethodGet, "/films/"+tt.filmID, nil)
-			req = mux.SetURLVars(req, map[string]string{"id": tt.filmID})
+			req.SetPathValue("id", tt.filmID)
 			w := httptest.NewRecorder()
 
 			// Execute handler
@@ -273,13 +411,39 @@ func TestFilmHandler_AddComment(t *testing.T) {
 				Details: "film not found",
 			},
 		},
+		{
+			name:   "comments locked",
+			filmID: "1",
+			requestBody: models.CommentRequest{
+				CustomerName: "John Doe",
+				Comment:      "Great movie!",
+			},
+			mockError:          repository.ErrCommentsLocked,
+			expectedStatusCode: http.StatusLocked,
+			expectedResponse: &models.ErrorResponse{
+				Error:   "Comments are locked",
+				Details: "comments are locked for this film",
+				Code:    "comments_locked",
+			},
+		},
+		{
+			name:               "guest comment without a name",
+			filmID:             "1",
+			requestBody:        models.CommentRequest{Comment: "Great movie!"},
+			mockError:          service.ErrCustomerNameRequired,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse: &models.ErrorResponse{
+				Error:   "Customer name is required for guest comments",
+				Details: "customer name is required for guest comments",
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockFilmService := new(MockFilmService)
 			mockCommentService := new(MockCommentService)
-			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService)
+			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService, pagination.DefaultLimits)
 
 			// Setup mock expectations
 			filmID := 1
@@ -293,7 +457,7 @@ func TestFilmHandler_AddComment(t *testing.T) {
 			requestBody, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest(http.MethodPost, "/films/"+tt.filmID+"/comments", bytes.NewBuffer(requestBody))
 			req.Header.Set("Content-Type", "application/json")
-			req = mux.SetURLVars(req, map[string]string{"id": tt.filmID})
+			req.SetPathValue("id", tt.filmID)
 			w := httptest.NewRecorder()
 
 			// Execute handler
@@ -366,7 +530,7 @@ func TestFilmHandler_GetCategories(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockFilmService := new(MockFilmService)
 			mockCommentService := new(MockCommentService)
-			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService)
+			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService, pagination.DefaultLimits)
 
 			// Setup mock expectations
 			mockFilmService.On("GetCategories", mock.Anything).Return(tt.mockResponse, tt.mockError)
@@ -396,11 +560,66 @@ func TestFilmHandler_GetCategories(t *testing.T) {
 	}
 }
 
+// commentForm builds a multipart comment body with an attachment of data.
+func commentForm(t *testing.T, data []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	require.NoError(t, form.WriteField("customer_name", "John Doe"))
+	require.NoError(t, form.WriteField("comment", "Look at this"))
+	require.NoError(t, form.WriteField("parent_id", "7"))
+	part, err := form.CreateFormFile("attachment", "still.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+	return &body, form.FormDataContentType()
+}
+
+func TestFilmHandler_AddCommentWithAttachment(t *testing.T) {
+	mockCommentService := new(MockCommentService)
+	handler := handlers.NewFilmHandler(new(MockFilmService), mockCommentService, pagination.DefaultLimits)
+	parentID := 7
+	mockCommentService.On("AddComment", mock.Anything, 1, models.CommentRequest{
+		CustomerName: "John Doe",
+		Comment:      "Look at this",
+		ParentID:     &parentID,
+		Attachment:   &models.CommentUpload{Data: []byte("image bytes")},
+	}).Return(&models.Comment{ID: 1, FilmID: 1, ThumbnailURL: "/media/abc-thumb.jpg"}, nil)
+
+	body, contentType := commentForm(t, []byte("image bytes"))
+	req := httptest.NewRequest(http.MethodPost, "/films/1/comments", body)
+	req.Header.Set("Content-Type", contentType)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	handler.AddComment(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"thumbnail_url":"/media/abc-thumb.jpg"`)
+	mockCommentService.AssertExpectations(t)
+}
+
+func TestFilmHandler_AddCommentAttachmentTooLarge(t *testing.T) {
+	mockCommentService := new(MockCommentService)
+	handler := handlers.NewFilmHandler(new(MockFilmService), mockCommentService, pagination.DefaultLimits,
+		handlers.WithMaxAttachmentBytes(1024))
+
+	body, contentType := commentForm(t, bytes.Repeat([]byte("x"), 200<<10))
+	req := httptest.NewRequest(http.MethodPost, "/films/1/comments", body)
+	req.Header.Set("Content-Type", contentType)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	handler.AddComment(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockCommentService.AssertNotCalled(t, "AddComment", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestFilmHandler_GetComments(t *testing.T) {
 	tests := []struct {
 		name               string
 		filmID             string
-		mockResponse       []models.Comment
+		mockResponse       *pagination.Paginated[models.Comment]
 		mockError          error
 		expectedStatusCode int
 		expectedResponse   interface{}
@@ -408,14 +627,20 @@ func TestFilmHandler_GetComments(t *testing.T) {
 		{
 			name:   "successful retrieval",
 			filmID: "1",
-			mockResponse: []models.Comment{
-				{ID: 1, FilmID: 1, CustomerName: "John Doe", Comment: "Great movie!"},
-				{ID: 2, FilmID: 1, CustomerName: "Jane Smith", Comment: "Loved it!"},
+			mockResponse: &pagination.Paginated[models.Comment]{
+				Items: []models.Comment{
+					{ID: 1, FilmID: 1, CustomerName: "John Doe", Comment: "Great movie!"},
+					{ID: 2, FilmID: 1, CustomerName: "Jane Smith", Comment: "Loved it!"},
+				},
+				Total: 2, Page: 1, Limit: 50,
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse: []models.Comment{
-				{ID: 1, FilmID: 1, CustomerName: "John Doe", Comment: "Great movie!"},
-				{ID: 2, FilmID: 1, CustomerName: "Jane Smith", Comment: "Loved it!"},
+			expectedResponse: &pagination.Paginated[models.Comment]{
+				Items: []models.Comment{
+					{ID: 1, FilmID: 1, CustomerName: "John Doe", Comment: "Great movie!"},
+					{ID: 2, FilmID: 1, CustomerName: "Jane Smith", Comment: "Loved it!"},
+				},
+				Total: 2, Page: 1, Limit: 50,
 			},
 		},
 		{
@@ -443,7 +668,7 @@ func TestFilmHandler_GetComments(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockFilmService := new(MockFilmService)
 			mockCommentService := new(MockCommentService)
-			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService)
+			handler := handlers.NewFilmHandler(mockFilmService, mockCommentService, pagination.DefaultLimits)
 
 			// Setup mock expectations only for valid film IDs
 			if tt.filmID != "invalid" {
@@ -451,13 +676,14 @@ func TestFilmHandler_GetComments(t *testing.T) {
 				if tt.filmID == "999" {
 					filmID = 999
 				}
-				mockCommentService.On("GetCommentsByFilmID", mock.Anything, filmID).
+				mockCommentService.On("GetCommentsByFilmID", mock.Anything, filmID,
+					models.CommentFilters{Limit: models.CommentLimits.DefaultLimit}).
 					Return(tt.mockResponse, tt.mockError)
 			}
 
 			// Create request with mux vars
 			req := httptest.NewRequest(http.MethodGet, "/films/"+tt.filmID+"/comments", nil)
-			req = mux.SetURLVars(req, map[string]string{"id": tt.filmID})
+			req.SetPathValue("id", tt.filmID)
 			w := httptest.NewRecorder()
 
 			// Execute handler
@@ -480,3 +706,497 @@ func TestFilmHandler_GetComments(t *testing.T) {
 		})
 	}
 }
+
+type recordingPublisher struct {
+	events []cache.Event
+}
+
+func (p *recordingPublisher) Publish(event cache.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestAdminHandler_PurgeCache(t *testing.T) {
+	publisher := &recordingPublisher{}
+	handler := handlers.NewAdminHandler(publisher, middleware.NewMaintenanceMode(false))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cache/purge", nil)
+	w := httptest.NewRecorder()
+
+	handler.PurgeCache(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, publisher.events, 1)
+	assert.True(t, publisher.events[0].All)
+}
+
+func TestAdminHandler_SetMaintenance(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		expectedStatus  int
+		expectedEnabled bool
+	}{
+		{name: "enable", body: `{"enabled": true}`, expectedStatus: http.StatusOK, expectedEnabled: true},
+		{name: "disable", body: `{"enabled": false}`, expectedStatus: http.StatusOK, expectedEnabled: false},
+		{name: "missing field", body: `{}`, expectedStatus: http.StatusBadRequest, expectedEnabled: false},
+		{name: "invalid body", body: `not json`, expectedStatus: http.StatusBadRequest, expectedEnabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode := middleware.NewMaintenanceMode(false)
+			handler := handlers.NewAdminHandler(&recordingPublisher{}, mode)
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/maintenance", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.SetMaintenance(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedEnabled, mode.Enabled())
+		})
+	}
+}
+
+type recordingReporter struct {
+	errors []error
+}
+
+func (r *recordingReporter) CaptureError(_ *http.Request, _ string, err error) {
+	r.errors = append(r.errors, err)
+}
+
+func (r *recordingReporter) CapturePanic(*http.Request, string, any) {}
+
+func (r *recordingReporter) Flush(time.Duration) {}
+
+func TestFilmHandler_ReportsServerErrors(t *testing.T) {
+	mockFilmService := new(MockFilmService)
+	mockCommentService := new(MockCommentService)
+	handler := handlers.NewFilmHandler(mockFilmService, mockCommentService, pagination.DefaultLimits)
+	reporter := &recordingReporter{}
+
+	serviceErr := errors.New("database error")
+	mockFilmService.On("GetCategories", mock.Anything).Return([]models.Category(nil), serviceErr)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories", nil)
+	w := httptest.NewRecorder()
+
+	middleware.ReportErrors(reporter)(http.HandlerFunc(handler.GetCategories)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, []error{serviceErr}, reporter.errors)
+}
+
+func TestFilmHandler_DatabaseUnavailable(t *testing.T) {
+	mockFilmService := new(MockFilmService)
+	mockCommentService := new(MockCommentService)
+	handler := handlers.NewFilmHandler(mockFilmService, mockCommentService, pagination.DefaultLimits)
+
+	mockFilmService.On("GetCategories", mock.Anything).
+		Return([]models.Category(nil), fmt.Errorf("error querying categories: %w", driver.ErrBadConn))
+
+	req := httptest.NewRequest(http.MethodGet, "/categories", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetCategories(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestFilmHandler_GetFilmByIDHead(t *testing.T) {
+	lastUpdate := time.Date(2013, 5, 26, 14, 50, 58, 0, time.UTC)
+	mockFilmService := new(MockFilmService)
+	handler := handlers.NewFilmHandler(mockFilmService, new(MockCommentService), pagination.DefaultLimits)
+	mockFilmService.On("GetFilmByID", mock.Anything, 1).
+		Return(&models.Film{FilmID: 1, Title: "Test Film", LastUpdate: lastUpdate}, nil)
+
+	getReq := httptest.NewRequest(http.MethodGet, "/films/1", nil)
+	getReq.SetPathValue("id", "1")
+	get := httptest.NewRecorder()
+	handler.GetFilmByID(get, getReq)
+	headReq := httptest.NewRequest(http.MethodHead, "/films/1", nil)
+	headReq.SetPathValue("id", "1")
+	head := httptest.NewRecorder()
+	handler.GetFilmByID(head, headReq)
+
+	assert.Equal(t, http.StatusOK, head.Code)
+	assert.Empty(t, head.Body.Bytes())
+	assert.NotEmpty(t, head.Header().Get("ETag"))
+	assert.Equal(t, get.Header().Get("ETag"), head.Header().Get("ETag"))
+	assert.Equal(t, strconv.Itoa(get.Body.Len()), head.Header().Get("Content-Length"))
+	assert.Equal(t, "Sun, 26 May 2013 14:50:58 GMT", head.Header().Get("Last-Modified"))
+}
+
+func TestFilmHandler_ConditionalRequests(t *testing.T) {
+	lastUpdate := time.Date(2013, 5, 26, 14, 50, 58, 0, time.UTC)
+	films := &pagination.Paginated[models.Film]{
+		Items: []models.Film{
+			{FilmID: 1, LastUpdate: lastUpdate.Add(-time.Hour)},
+			{FilmID: 2, LastUpdate: lastUpdate},
+		},
+		Total: 2, Page: 1, Limit: 10,
+	}
+
+	serve := func(header http.Header) *httptest.ResponseRecorder {
+		mockFilmService := new(MockFilmService)
+		mockFilmService.On("GetFilms", mock.Anything, mock.Anything).Return(films, nil)
+		handler := handlers.NewFilmHandler(mockFilmService, new(MockCommentService), pagination.DefaultLimits)
+		req := httptest.NewRequest(http.MethodGet, "/films", nil)
+		req.Header = header
+		w := httptest.NewRecorder()
+		handler.GetFilms(w, req)
+		return w
+	}
+
+	first := serve(http.Header{})
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.Equal(t, "Sun, 26 May 2013 14:50:58 GMT", first.Header().Get("Last-Modified"))
+
+	tests := []struct {
+		name           string
+		header         http.Header
+		expectedStatus int
+	}{
+		{"matching etag", http.Header{"If-None-Match": {etag}}, http.StatusNotModified},
+		{"weak matching etag in list", http.Header{"If-None-Match": {`"other", W/` + etag}}, http.StatusNotModified},
+		{"stale etag", http.Header{"If-None-Match": {`"other"`}}, http.StatusOK},
+		{"not modified since", http.Header{"If-Modified-Since": {"Sun, 26 May 2013 14:50:58 GMT"}}, http.StatusNotModified},
+		{"modified since", http.Header{"If-Modified-Since": {"Sun, 26 May 2013 14:00:00 GMT"}}, http.StatusOK},
+		{
+			"etag takes precedence",
+			http.Header{"If-None-Match": {`"other"`}, "If-Modified-Since": {"Sun, 26 May 2013 14:50:58 GMT"}},
+			http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.header)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			if tt.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestFilmHandler_GetCommentsConditional(t *testing.T) {
+	newest := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	comments := []models.Comment{
+		{ID: 1, FilmID: 1, CreatedAt: newest.Add(-time.Hour)},
+		{ID: 2, FilmID: 1, CreatedAt: newest},
+	}
+
+	tests := []struct {
+		name            string
+		comments        []models.Comment
+		ifModifiedSince string
+		expectedStatus  int
+	}{
+		{name: "no validator", comments: comments, expectedStatus: http.StatusOK},
+		{name: "unchanged", comments: comments, ifModifiedSince: "Fri, 01 Mar 2024 09:30:00 GMT",
+			expectedStatus: http.StatusNotModified},
+		{name: "new comment", comments: comments, ifModifiedSince: "Fri, 01 Mar 2024 09:00:00 GMT",
+			expectedStatus: http.StatusOK},
+		{name: "no comments", comments: []models.Comment{}, ifModifiedSince: "Fri, 01 Mar 2024 09:30:00 GMT",
+			expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentService := new(MockCommentService)
+			mockCommentService.On("GetCommentsByFilmID", mock.Anything, 1, mock.Anything).
+				Return(pagination.New(tt.comments, len(tt.comments), models.CommentLimits.First()), nil)
+			handler := handlers.NewFilmHandler(new(MockFilmService), mockCommentService, pagination.DefaultLimits)
+
+			req := httptest.NewRequest(http.MethodGet, "/films/1/comments", nil)
+			req.SetPathValue("id", "1")
+			if tt.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+			w := httptest.NewRecorder()
+			handler.GetComments(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if len(tt.comments) > 0 {
+				assert.Equal(t, "Fri, 01 Mar 2024 09:30:00 GMT", w.Header().Get("Last-Modified"))
+			} else {
+				assert.Empty(t, w.Header().Get("Last-Modified"))
+			}
+		})
+	}
+}
+
+func TestFilmHandler_GetCommentsPrivateWithToken(t *testing.T) {
+	tests := []struct {
+		name                 string
+		authorization        string
+		expectedCacheControl string
+	}{
+		{name: "anonymous"},
+		{name: "with a token", authorization: "Bearer token", expectedCacheControl: "private, no-cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentService := new(MockCommentService)
+			mockCommentService.On("GetCommentsByFilmID", mock.Anything, 1, mock.Anything).
+				Return(pagination.New([]models.Comment{}, 0, models.CommentLimits.First()), nil)
+			handler := handlers.NewFilmHandler(new(MockFilmService), mockCommentService, pagination.DefaultLimits)
+
+			req := httptest.NewRequest(http.MethodGet, "/films/1/comments", nil)
+			req.SetPathValue("id", "1")
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.GetComments(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "Authorization", w.Header().Get("Vary"))
+			assert.Equal(t, tt.expectedCacheControl, w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestFilmHandler_GetCommentSummary(t *testing.T) {
+	refreshed := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+	summary := &models.CommentSummary{
+		FilmID: 1, CommentCount: 4, TopKeywords: []string{"dinosaur"}, RefreshedAt: refreshed,
+		RatingCorrelation: models.CommentRatingCorrelation{Rating: "PG", AverageComments: 2, RelativeActivity: 2},
+	}
+
+	tests := []struct {
+		name           string
+		filmID         string
+		mockResponse   *models.CommentSummary
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "summary", filmID: "1", mockResponse: summary, expectedStatus: http.StatusOK},
+		{name: "not computed yet", filmID: "1", mockError: repository.ErrCommentSummaryNotFound,
+			expectedStatus: http.StatusNotFound},
+		{name: "film not found", filmID: "1", mockError: repository.ErrFilmNotFound,
+			expectedStatus: http.StatusNotFound},
+		{name: "invalid film ID", filmID: "abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentService := new(MockCommentService)
+			if tt.mockResponse != nil || tt.mockError != nil {
+				mockCommentService.On("GetCommentSummary", mock.Anything, 1).Return(tt.mockResponse, tt.mockError)
+			}
+			handler := handlers.NewFilmHandler(new(MockFilmService), mockCommentService, pagination.DefaultLimits)
+
+			req := httptest.NewRequest(http.MethodGet, "/films/"+tt.filmID+"/comments/summary", nil)
+			req.SetPathValue("id", tt.filmID)
+			w := httptest.NewRecorder()
+			handler.GetCommentSummary(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var body models.CommentSummary
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+				assert.Equal(t, *summary, body)
+				assert.Equal(t, "Fri, 01 Mar 2024 03:00:00 GMT", w.Header().Get("Last-Modified"))
+			}
+			mockCommentService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilmHandler_LockComments(t *testing.T) {
+	lockedAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockResponse   *models.CommentLockStatus
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "lock",
+			body:           `{"locked": true}`,
+			mockResponse:   &models.CommentLockStatus{FilmID: 1, Locked: true, LockedAt: &lockedAt},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unlock",
+			body:           `{"locked": false}`,
+			mockResponse:   &models.CommentLockStatus{FilmID: 1},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "missing locked", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "film not found",
+			body:           `{"locked": true}`,
+			mockError:      repository.ErrFilmNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentService := new(MockCommentService)
+			if tt.mockResponse != nil || tt.mockError != nil {
+				mockCommentService.On("SetCommentsLocked", mock.Anything, 1, mock.AnythingOfType("bool")).
+					Return(tt.mockResponse, tt.mockError)
+			}
+			handler := handlers.NewFilmHandler(new(MockFilmService), mockCommentService, pagination.DefaultLimits)
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/films/1/comments:lock", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+			handler.LockComments(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.mockResponse != nil {
+				var status models.CommentLockStatus
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+				assert.Equal(t, tt.mockResponse.Locked, status.Locked)
+			}
+			mockCommentService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilmHandler_ShadowBanCustomer(t *testing.T) {
+	bannedAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		customerID     string
+		body           string
+		mockResponse   *models.ShadowBanStatus
+		mockError      error
+		expectedStatus int
+	}{
+		{
+			name:           "ban",
+			customerID:     "600",
+			body:           `{"banned": true}`,
+			mockResponse:   &models.ShadowBanStatus{CustomerID: 600, Banned: true, BannedAt: &bannedAt},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "lift",
+			customerID:     "600",
+			body:           `{"banned": false}`,
+			mockResponse:   &models.ShadowBanStatus{CustomerID: 600},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "missing banned", customerID: "600", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid customer ID", customerID: "abc", body: `{"banned": true}`,
+			expectedStatus: http.StatusBadRequest},
+		{
+			name:           "customer not found",
+			customerID:     "600",
+			body:           `{"banned": true}`,
+			mockError:      repository.ErrCustomerNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentService := new(MockCommentService)
+			if tt.mockResponse != nil || tt.mockError != nil {
+				mockCommentService.On("SetShadowBanned", mock.Anything, 600, mock.AnythingOfType("bool")).
+					Return(tt.mockResponse, tt.mockError)
+			}
+			handler := handlers.NewFilmHandler(new(MockFilmService), mockCommentService, pagination.DefaultLimits)
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/customers/"+tt.customerID+"/shadow-ban",
+				bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", tt.customerID)
+			w := httptest.NewRecorder()
+			handler.ShadowBanCustomer(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.mockResponse != nil {
+				var status models.ShadowBanStatus
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+				assert.Equal(t, tt.mockResponse.Banned, status.Banned)
+			}
+			mockCommentService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilmHandler_GetFilmsSuggestsTitles(t *testing.T) {
+	tests := []struct {
+		name                string
+		query               string
+		items               []models.Film
+		expectedSuggestions bool
+	}{
+		{name: "title search without results", query: "?title=acadmy", expectedSuggestions: true},
+		{name: "title search with results", query: "?title=academy",
+			items: []models.Film{{FilmID: 1, Title: "ACADEMY DINOSAUR"}}},
+		{name: "page past the end", query: "?title=acadmy&page=3"},
+		{name: "no title", query: "?rating=NC-17"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFilmService := new(MockFilmService)
+			mockSearchService := new(MockSearchService)
+			handler := handlers.NewFilmHandler(mockFilmService, new(MockCommentService), pagination.DefaultLimits,
+				handlers.WithTitleSuggestions(mockSearchService))
+			mockFilmService.On("GetFilms", mock.Anything, mock.Anything).
+				Return(&pagination.Paginated[models.Film]{Items: tt.items, Total: len(tt.items), Page: 1}, nil)
+			mockSearchService.On("SuggestTitles", mock.Anything, "acadmy").
+				Return([]string{"ACADEMY DINOSAUR"}).Maybe()
+
+			w := httptest.NewRecorder()
+			handler.GetFilms(w, httptest.NewRequest(http.MethodGet, "/films"+tt.query, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			if tt.expectedSuggestions {
+				assert.Contains(t, w.Body.String(), `"suggestions":["ACADEMY DINOSAUR"]`)
+				mockSearchService.AssertExpectations(t)
+			} else {
+				assert.NotContains(t, w.Body.String(), "suggestions")
+				mockSearchService.AssertNotCalled(t, "SuggestTitles", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestFilmHandler_GetAdminFilmsBindsStatus(t *testing.T) {
+	mockFilmService := new(MockFilmService)
+	handler := handlers.NewFilmHandler(mockFilmService, new(MockCommentService), pagination.DefaultLimits)
+	expected := models.FilmFilters{
+		Statuses: []string{models.FilmDraft, models.FilmArchived},
+		Params:   pagination.Params{Page: 1, Limit: pagination.DefaultLimits.DefaultLimit},
+	}
+	mockFilmService.On("GetAdminFilms", mock.Anything, expected).
+		Return(&pagination.Paginated[models.Film]{Page: 1, Limit: expected.Limit}, nil)
+
+	w := httptest.NewRecorder()
+	handler.GetAdminFilms(w, httptest.NewRequest(http.MethodGet, "/admin/films?status=draft,archived", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockFilmService.AssertExpectations(t)
+}
+
+func TestFilmHandler_GetAdminFilm(t *testing.T) {
+	mockFilmService := new(MockFilmService)
+	handler := handlers.NewFilmHandler(mockFilmService, new(MockCommentService), pagination.DefaultLimits)
+	mockFilmService.On("GetAdminFilmByID", mock.Anything, 2).
+		Return(&models.Film{FilmID: 2, Title: "Unreleased Film", Status: models.FilmDraft}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/films/2", nil)
+	req.SetPathValue("id", "2")
+	w := httptest.NewRecorder()
+	handler.GetAdminFilm(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"draft"`)
+	mockFilmService.AssertExpectations(t)
+}
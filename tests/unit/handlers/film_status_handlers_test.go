@@ -0,0 +1,189 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockFilmStatusService struct {
+	mock.Mock
+}
+
+func (m *MockFilmStatusService) SetFilmStatus(
+	ctx context.Context,
+	filmID int,
+	status string,
+) (*models.FilmStatus, error) {
+	args := m.Called(ctx, filmID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FilmStatus), args.Error(1)
+}
+
+func (m *MockFilmStatusService) ScheduleFilmPublish(
+	ctx context.Context,
+	filmID int,
+	publishAt *time.Time,
+) (*models.FilmStatus, error) {
+	args := m.Called(ctx, filmID, publishAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FilmStatus), args.Error(1)
+}
+
+func (m *MockFilmStatusService) PublishScheduledFilms(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockFilmStatusService) ArchiveFilm(ctx context.Context, filmID int) (*models.FilmStatus, error) {
+	args := m.Called(ctx, filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FilmStatus), args.Error(1)
+}
+
+func (m *MockFilmStatusService) RestoreFilm(ctx context.Context, filmID int) (*models.FilmStatus, error) {
+	args := m.Called(ctx, filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FilmStatus), args.Error(1)
+}
+
+func TestFilmStatusHandler_SetFilmStatus(t *testing.T) {
+	tests := []struct {
+		name               string
+		filmID             string
+		body               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "published", filmID: "1", body: `{"status": "published"}`, expectedStatusCode: http.StatusOK},
+		{
+			name:               "film not found",
+			filmID:             "1",
+			body:               `{"status": "draft"}`,
+			mockError:          repository.ErrFilmNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "unknown status", filmID: "1", body: `{"status": "hidden"}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "missing status", filmID: "1", body: `{}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid body", filmID: "1", body: `{`, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid film ID", filmID: "abc", body: `{"status": "draft"}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockFilmStatusService)
+			handler := handlers.NewFilmStatusHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("SetFilmStatus", mock.Anything, 1, mock.Anything).Return(nil, tt.mockError)
+			} else {
+				mockService.On("SetFilmStatus", mock.Anything, 1, models.FilmPublished).
+					Return(&models.FilmStatus{FilmID: 1, Status: models.FilmPublished}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/films/"+tt.filmID+"/status", strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.filmID)
+			w := httptest.NewRecorder()
+			handler.SetFilmStatus(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"status":"published"`)
+			}
+		})
+	}
+}
+
+func TestFilmStatusHandler_ScheduleFilmPublish(t *testing.T) {
+	publishAt := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name               string
+		body               string
+		publishAt          *time.Time
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "scheduled", body: `{"publish_at": "2030-01-02T15:00:00Z"}`, publishAt: &publishAt,
+			expectedStatusCode: http.StatusOK},
+		{name: "cancelled", body: `{"publish_at": null}`, expectedStatusCode: http.StatusOK},
+		{name: "not a draft", body: `{"publish_at": "2030-01-02T15:00:00Z"}`, publishAt: &publishAt,
+			mockError: repository.ErrFilmNotDraft, expectedStatusCode: http.StatusConflict},
+		{name: "in the past", body: `{"publish_at": "2030-01-02T15:00:00Z"}`, publishAt: &publishAt,
+			mockError: service.ErrPublishAtPast, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid time", body: `{"publish_at": "tomorrow"}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockFilmStatusService)
+			handler := handlers.NewFilmStatusHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("ScheduleFilmPublish", mock.Anything, 1, tt.publishAt).Return(nil, tt.mockError)
+			} else {
+				mockService.On("ScheduleFilmPublish", mock.Anything, 1, tt.publishAt).
+					Return(&models.FilmStatus{FilmID: 1, Status: models.FilmDraft, PublishAt: tt.publishAt}, nil).
+					Maybe()
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/films/1/schedule", strings.NewReader(tt.body))
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+			handler.ScheduleFilmPublish(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestFilmStatusHandler_RestoreFilm(t *testing.T) {
+	tests := []struct {
+		name               string
+		filmID             string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "restored", filmID: "1", expectedStatusCode: http.StatusOK},
+		{name: "not archived", filmID: "1", mockError: repository.ErrFilmNotArchived,
+			expectedStatusCode: http.StatusConflict},
+		{name: "film not found", filmID: "1", mockError: repository.ErrFilmNotFound,
+			expectedStatusCode: http.StatusNotFound},
+		{name: "invalid film ID", filmID: "abc", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockFilmStatusService)
+			handler := handlers.NewFilmStatusHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("RestoreFilm", mock.Anything, 1).Return(nil, tt.mockError)
+			} else {
+				mockService.On("RestoreFilm", mock.Anything, 1).
+					Return(&models.FilmStatus{FilmID: 1, Status: models.FilmPublished}, nil).Maybe()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/films/"+tt.filmID+"/restore", nil)
+			req.SetPathValue("id", tt.filmID)
+			w := httptest.NewRecorder()
+			handler.RestoreFilm(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
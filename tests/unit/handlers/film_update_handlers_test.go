@@ -0,0 +1,169 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockFilmUpdateService struct {
+	mock.Mock
+}
+
+func (m *MockFilmUpdateService) UpdateFilm(
+	ctx context.Context,
+	filmID int,
+	version time.Time,
+	update models.FilmUpdateRequest,
+) (*models.Film, error) {
+	args := m.Called(ctx, filmID, version, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Film), args.Error(1)
+}
+
+func (m *MockFilmUpdateService) BulkUpdatePrice(
+	ctx context.Context,
+	filters models.FilmFilters,
+	change models.BulkPriceRequest,
+) (*models.BulkPriceResult, error) {
+	args := m.Called(ctx, filters, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BulkPriceResult), args.Error(1)
+}
+
+// adminFilmETag returns the ETag GET /admin/films/{id} sends for film.
+func adminFilmETag(t *testing.T, film *models.Film) string {
+	t.Helper()
+	mockFilmService := new(MockFilmService)
+	mockFilmService.On("GetAdminFilmByID", mock.Anything, film.FilmID).Return(film, nil)
+	handler := handlers.NewFilmHandler(mockFilmService, new(MockCommentService), pagination.DefaultLimits)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/films/1", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	handler.GetAdminFilm(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	return etag
+}
+
+func TestFilmUpdateHandler_UpdateFilm(t *testing.T) {
+	version := time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.UTC)
+	etag := adminFilmETag(t, &models.Film{FilmID: 1, Title: "ACADEMY DINOSAUR", LastUpdate: version})
+	title := "ACADEMY DINOSAUR II"
+
+	tests := []struct {
+		name               string
+		ifMatch            string
+		body               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "updated", ifMatch: etag, body: `{"title": "ACADEMY DINOSAUR II"}`, expectedStatusCode: http.StatusOK},
+		{name: "changed since fetched", ifMatch: etag, body: `{"title": "ACADEMY DINOSAUR II"}`,
+			mockError: repository.ErrFilmModified, expectedStatusCode: http.StatusPreconditionFailed},
+		{name: "film not found", ifMatch: etag, body: `{"title": "ACADEMY DINOSAUR II"}`,
+			mockError: repository.ErrFilmNotFound, expectedStatusCode: http.StatusNotFound},
+		{name: "missing If-Match", body: `{"title": "ACADEMY DINOSAUR II"}`,
+			expectedStatusCode: http.StatusPreconditionRequired},
+		{name: "weakened by compression", ifMatch: "W/" + etag, body: `{"title": "ACADEMY DINOSAUR II"}`,
+			expectedStatusCode: http.StatusOK},
+		{name: "not a film ETag", ifMatch: `"5d41402abc4b2a76"`, body: `{"title": "ACADEMY DINOSAUR II"}`,
+			expectedStatusCode: http.StatusPreconditionFailed},
+		{name: "empty title", ifMatch: etag, body: `{"title": ""}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "unknown rating", ifMatch: etag, body: `{"rating": "X"}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid body", ifMatch: etag, body: `{`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockFilmUpdateService)
+			handler := handlers.NewFilmUpdateHandler(mockService)
+			update := models.FilmUpdateRequest{Title: &title}
+			if tt.mockError != nil {
+				mockService.On("UpdateFilm", mock.Anything, 1, mock.Anything, update).Return(nil, tt.mockError)
+			} else {
+				mockService.On("UpdateFilm", mock.Anything, 1, mock.MatchedBy(version.Equal), update).
+					Return(&models.Film{FilmID: 1, Title: title, LastUpdate: version.Add(time.Minute)}, nil).
+					Maybe()
+			}
+
+			req := httptest.NewRequest(http.MethodPatch, "/admin/films/1", strings.NewReader(tt.body))
+			req.SetPathValue("id", "1")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			handler.UpdateFilm(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.NotEmpty(t, w.Header().Get("ETag"))
+				assert.NotEqual(t, etag, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestFilmUpdateHandler_BulkUpdatePrice(t *testing.T) {
+	percent := -10.0
+	tests := []struct {
+		name               string
+		query              string
+		body               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "dry run", query: "?rating=PG,G&category=Horror", body: `{"percent": -10, "dry_run": true}`,
+			expectedStatusCode: http.StatusOK},
+		{name: "no change", query: "?rating=PG,G&category=Horror", body: `{"dry_run": true}`,
+			mockError: service.ErrBulkPriceChange, expectedStatusCode: http.StatusBadRequest},
+		{name: "rate too high", body: `{"rental_rate": 100}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "percent too low", body: `{"percent": -100}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockFilmUpdateService)
+			handler := handlers.NewFilmUpdateHandler(mockService)
+			filters := models.FilmFilters{Ratings: []string{"PG", "G"}, Categories: []string{"Horror"}}
+			if tt.mockError != nil {
+				mockService.On("BulkUpdatePrice", mock.Anything, filters, mock.Anything).Return(nil, tt.mockError)
+			} else {
+				mockService.On("BulkUpdatePrice", mock.Anything, filters,
+					models.BulkPriceRequest{Percent: &percent, DryRun: true}).
+					Return(&models.BulkPriceResult{FilmsMatched: 12, FilmsChanged: 11, DryRun: true}, nil).
+					Maybe()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/films/bulk-price"+tt.query, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.BulkUpdatePrice(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.JSONEq(t, `{"films_matched": 12, "films_changed": 11, "dry_run": true}`, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockGuestService struct {
+	mock.Mock
+}
+
+func (m *MockGuestService) StartSession(ctx context.Context) (*models.GuestSession, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GuestSession), args.Error(1)
+}
+
+func (m *MockGuestService) GetWatchlist(ctx context.Context) ([]models.GuestWatchlistEntry, error) {
+	args := m.Called(ctx)
+	entries, _ := args.Get(0).([]models.GuestWatchlistEntry)
+	return entries, args.Error(1)
+}
+
+func (m *MockGuestService) AddToWatchlist(ctx context.Context, filmID int) error {
+	return m.Called(ctx, filmID).Error(0)
+}
+
+func (m *MockGuestService) RemoveFromWatchlist(ctx context.Context, filmID int) error {
+	return m.Called(ctx, filmID).Error(0)
+}
+
+func (m *MockGuestService) RecordGuestComment(ctx context.Context, guestID, commentID int) error {
+	return m.Called(ctx, guestID, commentID).Error(0)
+}
+
+func (m *MockGuestService) MergeGuest(ctx context.Context, guestID, customerID int) (*models.GuestMerge, error) {
+	args := m.Called(ctx, guestID, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GuestMerge), args.Error(1)
+}
+
+func TestGuestHandler_StartSession(t *testing.T) {
+	mockService := new(MockGuestService)
+	handler := handlers.NewGuestHandler(mockService)
+	expiresAt := time.Date(2026, 11, 15, 0, 0, 0, 0, time.UTC)
+	mockService.On("StartSession", mock.Anything).
+		Return(&models.GuestSession{GuestID: 42, Token: "guest-token", ExpiresAt: expiresAt}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/guest-sessions", nil)
+	w := httptest.NewRecorder()
+	handler.StartSession(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var session models.GuestSession
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+	assert.Equal(t, "guest-token", session.Token)
+}
+
+func TestGuestHandler_AddToWatchlist(t *testing.T) {
+	tests := []struct {
+		name               string
+		filmID             string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "added", filmID: "8", expectedStatusCode: http.StatusOK},
+		{name: "film not found", filmID: "8", mockError: repository.ErrFilmNotFound,
+			expectedStatusCode: http.StatusNotFound},
+		{name: "already registered", filmID: "8", mockError: repository.ErrGuestSessionMerged,
+			expectedStatusCode: http.StatusConflict},
+		{name: "not a guest", filmID: "8", mockError: service.ErrGuestTokenRequired,
+			expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid film ID", filmID: "abc", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockGuestService)
+			handler := handlers.NewGuestHandler(mockService)
+			mockService.On("AddToWatchlist", mock.Anything, 8).Return(tt.mockError).Maybe()
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/guest/watchlist/"+tt.filmID, nil)
+			req.SetPathValue("filmID", tt.filmID)
+			w := httptest.NewRecorder()
+			handler.AddToWatchlist(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+type MockHomeService struct {
+	mock.Mock
+}
+
+func (m *MockHomeService) GetHome(ctx context.Context, customerID int) (*models.HomeFeed, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HomeFeed), args.Error(1)
+}
+
+func TestHomeHandler_GetHome(t *testing.T) {
+	tests := []struct {
+		name               string
+		customerID         string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "feed", customerID: "600", expectedStatusCode: http.StatusOK},
+		{name: "request ended", customerID: "600", mockError: context.DeadlineExceeded,
+			expectedStatusCode: http.StatusServiceUnavailable},
+		{name: "invalid customer ID", customerID: "abc", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockHomeService)
+			handler := handlers.NewHomeHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("GetHome", mock.Anything, 600).Return(nil, tt.mockError)
+			} else {
+				mockService.On("GetHome", mock.Anything, 600).Return(&models.HomeFeed{
+					CustomerID:         600,
+					OpenRentals:        []models.RentalEvent{},
+					Recommended:        []models.HomeFilm{{FilmID: 3, Title: "ADAPTATION HOLES"}},
+					FavoriteCategories: []string{},
+					NewInFavorites:     []models.HomeFilm{},
+					Incomplete:         []string{models.HomeSectionNewInFavorites},
+				}, nil).Maybe()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/customers/"+tt.customerID+"/home", nil)
+			req.SetPathValue("id", tt.customerID)
+			w := httptest.NewRecorder()
+			handler.GetHome(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"incomplete":["new_in_favorites"]`)
+				assert.Contains(t, w.Body.String(), `"open_rentals":[]`)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockInventoryService struct {
+	mock.Mock
+}
+
+func (m *MockInventoryService) AddInventory(
+	ctx context.Context,
+	filmID int,
+	inventoryReq models.InventoryRequest,
+) ([]models.InventoryCopy, error) {
+	args := m.Called(ctx, filmID, inventoryReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InventoryCopy), args.Error(1)
+}
+
+func (m *MockInventoryService) GetInventoryCopy(ctx context.Context, inventoryID int) (*models.InventoryCopy, error) {
+	args := m.Called(ctx, inventoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InventoryCopy), args.Error(1)
+}
+
+func (m *MockInventoryService) ListFilmInventory(
+	ctx context.Context,
+	filmID int,
+	filters models.InventoryFilters,
+) ([]models.InventoryCopy, error) {
+	args := m.Called(ctx, filmID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InventoryCopy), args.Error(1)
+}
+
+func (m *MockInventoryService) RetireInventory(ctx context.Context, inventoryID int) (*models.InventoryCopy, error) {
+	args := m.Called(ctx, inventoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InventoryCopy), args.Error(1)
+}
+
+func (m *MockInventoryService) TransferInventory(
+	ctx context.Context,
+	inventoryID int,
+	transferReq models.InventoryTransferRequest,
+) (*models.InventoryTransfer, error) {
+	args := m.Called(ctx, inventoryID, transferReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InventoryTransfer), args.Error(1)
+}
+
+func (m *MockInventoryService) ListInventoryTransfers(
+	ctx context.Context,
+	inventoryID int,
+) ([]models.InventoryTransfer, error) {
+	args := m.Called(ctx, inventoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InventoryTransfer), args.Error(1)
+}
+
+func TestInventoryHandler_AddInventory(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectMock         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "copies added",
+			body:               `{"store_id": 2, "copies": 2}`,
+			expectMock:         true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "film not found",
+			body:               `{"store_id": 2, "copies": 2}`,
+			mockError:          repository.ErrFilmNotFound,
+			expectMock:         true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "unknown store",
+			body:               `{"store_id": 2, "copies": 2}`,
+			mockError:          repository.ErrInvalidReference,
+			expectMock:         true,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "missing store", body: `{"copies": 2}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "too many copies", body: `{"store_id": 2, "copies": 500}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockInventoryService)
+			handler := handlers.NewInventoryHandler(mockService)
+			if tt.expectMock {
+				call := mockService.On("AddInventory", mock.Anything, 1, models.InventoryRequest{StoreID: 2, Copies: 2})
+				if tt.mockError != nil {
+					call.Return(nil, tt.mockError)
+				} else {
+					call.Return([]models.InventoryCopy{{InventoryID: 4582}, {InventoryID: 4583}}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/films/1/inventory", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+			handler.AddInventory(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryHandler_RetireInventory(t *testing.T) {
+	tests := []struct {
+		name               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "retired", expectedStatusCode: http.StatusOK},
+		{name: "not found", mockError: repository.ErrInventoryNotFound, expectedStatusCode: http.StatusNotFound},
+		{name: "rented out", mockError: repository.ErrInventoryRented, expectedStatusCode: http.StatusConflict},
+		{name: "database error", mockError: errors.New("boom"), expectedStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockInventoryService)
+			handler := handlers.NewInventoryHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("RetireInventory", mock.Anything, 7).Return(nil, tt.mockError)
+			} else {
+				mockService.On("RetireInventory", mock.Anything, 7).
+					Return(&models.InventoryCopy{InventoryID: 7, Status: models.CopyStatusRetired}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/inventory/7", nil)
+			req.SetPathValue("id", "7")
+			w := httptest.NewRecorder()
+			handler.RetireInventory(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryHandler_ListFilmInventory(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedFilters    *models.InventoryFilters
+		expectedStatusCode int
+	}{
+		{name: "all copies", expectedFilters: &models.InventoryFilters{}, expectedStatusCode: http.StatusOK},
+		{
+			name:               "lost copies at a store",
+			query:              "?store_id=2&status=lost",
+			expectedFilters:    &models.InventoryFilters{StoreID: 2, Status: models.CopyStatusLost},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "unknown status", query: "?status=stolen", expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid store", query: "?store_id=main", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockInventoryService)
+			handler := handlers.NewInventoryHandler(mockService)
+			if tt.expectedFilters != nil {
+				mockService.On("ListFilmInventory", mock.Anything, 1, *tt.expectedFilters).
+					Return([]models.InventoryCopy{}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/films/1/inventory"+tt.query, nil)
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+			handler.ListFilmInventory(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryHandler_TransferInventory(t *testing.T) {
+	transferReq := models.InventoryTransferRequest{ToStoreID: 2, Reason: "rebalancing"}
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectMock         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "transferred",
+			body:               `{"to_store_id": 2, "reason": "rebalancing"}`,
+			expectMock:         true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "rented out",
+			body:               `{"to_store_id": 2, "reason": "rebalancing"}`,
+			mockError:          repository.ErrInventoryRented,
+			expectMock:         true,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "retired",
+			body:               `{"to_store_id": 2, "reason": "rebalancing"}`,
+			mockError:          repository.ErrInventoryRetired,
+			expectMock:         true,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "already at store",
+			body:               `{"to_store_id": 2, "reason": "rebalancing"}`,
+			mockError:          repository.ErrInventoryAtStore,
+			expectMock:         true,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "unknown store",
+			body:               `{"to_store_id": 2, "reason": "rebalancing"}`,
+			mockError:          repository.ErrInvalidReference,
+			expectMock:         true,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "copy not found",
+			body:               `{"to_store_id": 2, "reason": "rebalancing"}`,
+			mockError:          repository.ErrInventoryNotFound,
+			expectMock:         true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "missing store", body: `{"reason": "rebalancing"}`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockInventoryService)
+			handler := handlers.NewInventoryHandler(mockService)
+			if tt.expectMock {
+				call := mockService.On("TransferInventory", mock.Anything, 7, transferReq)
+				if tt.mockError != nil {
+					call.Return(nil, tt.mockError)
+				} else {
+					call.Return(&models.InventoryTransfer{ID: 1, InventoryID: 7, FromStoreID: 1, ToStoreID: 2}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/inventory/7/transfer", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "7")
+			w := httptest.NewRecorder()
+			handler.TransferInventory(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockLoyaltyService struct {
+	mock.Mock
+}
+
+func (m *MockLoyaltyService) GetBalance(ctx context.Context, customerID int) (*models.LoyaltyBalance, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoyaltyBalance), args.Error(1)
+}
+
+func (m *MockLoyaltyService) GetHistory(
+	ctx context.Context,
+	customerID int,
+	filters models.LoyaltyHistoryFilters,
+) (*pagination.Paginated[models.LoyaltyEntry], error) {
+	args := m.Called(ctx, customerID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.LoyaltyEntry]), args.Error(1)
+}
+
+func (m *MockLoyaltyService) RedeemPoints(
+	ctx context.Context,
+	customerID int,
+	redeemReq models.RedeemPointsRequest,
+) (*models.LoyaltyEntry, error) {
+	args := m.Called(ctx, customerID, redeemReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoyaltyEntry), args.Error(1)
+}
+
+func (m *MockLoyaltyService) AwardCommentPoints(ctx context.Context, customerID, commentID int) error {
+	return m.Called(ctx, customerID, commentID).Error(0)
+}
+
+func TestLoyaltyHandler_RedeemPoints(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		expectService      bool
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "redeemed", body: `{"points": 500}`, expectService: true, expectedStatusCode: http.StatusCreated},
+		{
+			name:               "not enough points",
+			body:               `{"points": 500}`,
+			expectService:      true,
+			mockError:          repository.ErrInsufficientPoints,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "too few points",
+			body:               `{"points": 500}`,
+			expectService:      true,
+			mockError:          service.ErrRedemptionTooSmall,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "zero points", body: `{"points": 0}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid body", body: `points`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockLoyaltyService)
+			handler := handlers.NewLoyaltyHandler(mockService)
+			if tt.expectService {
+				if tt.mockError != nil {
+					mockService.On("RedeemPoints", mock.Anything, 600, models.RedeemPointsRequest{Points: 500}).
+						Return(nil, tt.mockError)
+				} else {
+					mockService.On("RedeemPoints", mock.Anything, 600, models.RedeemPointsRequest{Points: 500}).
+						Return(&models.LoyaltyEntry{ID: 1, Points: -500, Credit: 5}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/customers/600/loyalty/redeem", strings.NewReader(tt.body))
+			req.SetPathValue("id", "600")
+			w := httptest.NewRecorder()
+			handler.RedeemPoints(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockPaymentService struct {
+	mock.Mock
+}
+
+func (m *MockPaymentService) StartPayment(ctx context.Context, customerID, checkoutID int) (*models.Payment, error) {
+	args := m.Called(ctx, customerID, checkoutID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	return m.Called(ctx, payload, header).Error(0)
+}
+
+func (m *MockPaymentService) RefundPayment(
+	ctx context.Context,
+	paymentID int,
+	idempotencyKey string,
+	refundReq models.RefundRequest,
+) (*models.Refund, error) {
+	args := m.Called(ctx, paymentID, idempotencyKey, refundReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Refund), args.Error(1)
+}
+
+func TestPaymentHandler_RefundPayment(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		idempotencyKey     string
+		expectedRequest    *models.RefundRequest
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "full refund without a body",
+			idempotencyKey:     "key-1",
+			expectedRequest:    &models.RefundRequest{},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "partial refund",
+			body:               `{"amount": 2.5, "reason": "scratched disc"}`,
+			idempotencyKey:     "key-1",
+			expectedRequest:    &models.RefundRequest{Amount: 2.5, Reason: "scratched disc"},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "payment not found",
+			idempotencyKey:     "key-1",
+			expectedRequest:    &models.RefundRequest{},
+			mockError:          repository.ErrPaymentNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "refund too large",
+			body:               `{"amount": 100}`,
+			idempotencyKey:     "key-1",
+			expectedRequest:    &models.RefundRequest{Amount: 100},
+			mockError:          repository.ErrRefundExceedsPayment,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "key reused for another amount",
+			body:               `{"amount": 1}`,
+			idempotencyKey:     "key-1",
+			expectedRequest:    &models.RefundRequest{Amount: 1},
+			mockError:          repository.ErrIdempotencyKeyReused,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:               "provider declined",
+			idempotencyKey:     "key-1",
+			expectedRequest:    &models.RefundRequest{},
+			mockError:          fmt.Errorf("%w: declined", service.ErrRefundFailed),
+			expectedStatusCode: http.StatusBadGateway,
+		},
+		{
+			name:               "database error",
+			idempotencyKey:     "key-1",
+			expectedRequest:    &models.RefundRequest{},
+			mockError:          errors.New("boom"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "missing idempotency key", expectedStatusCode: http.StatusBadRequest},
+		{
+			name:               "negative amount",
+			body:               `{"amount": -1}`,
+			idempotencyKey:     "key-1",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPaymentService)
+			handler := handlers.NewPaymentHandler(mockService)
+			if tt.expectedRequest != nil {
+				if tt.mockError != nil {
+					mockService.On("RefundPayment", mock.Anything, 1, tt.idempotencyKey, *tt.expectedRequest).
+						Return(nil, tt.mockError)
+				} else {
+					mockService.On("RefundPayment", mock.Anything, 1, tt.idempotencyKey, *tt.expectedRequest).
+						Return(&models.Refund{ID: 4, PaymentID: 1, Status: models.PaymentStatusSucceeded}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/payments/1/refund", strings.NewReader(tt.body))
+			if tt.idempotencyKey != "" {
+				req.Header.Set("Idempotency-Key", tt.idempotencyKey)
+			}
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+			handler.RefundPayment(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
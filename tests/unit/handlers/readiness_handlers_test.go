@@ -0,0 +1,77 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+type MockReadinessService struct {
+	mock.Mock
+}
+
+func (m *MockReadinessService) CheckReadiness(ctx context.Context) (*models.Readiness, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Readiness), args.Error(1)
+}
+
+func TestReadinessHandler_Ready(t *testing.T) {
+	tests := []struct {
+		name      string
+		readiness *models.Readiness
+		err       error
+		status    int
+		body      string
+	}{
+		{
+			name:      "ready",
+			readiness: &models.Readiness{Ready: true, SchemaVersion: 30, ExpectedVersion: 30},
+			status:    http.StatusOK,
+			body:      `"ready":true`,
+		},
+		{
+			name: "schema mismatch",
+			readiness: &models.Readiness{
+				SchemaVersion: 31, ExpectedVersion: 30, Reason: "database schema is newer than this build",
+			},
+			status: http.StatusServiceUnavailable,
+			body:   `"reason":"database schema is newer than this build"`,
+		},
+		{
+			name:   "database down",
+			err:    errors.New("connection refused"),
+			status: http.StatusServiceUnavailable,
+			body:   `"reason":"database unavailable"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReadinessService)
+			if tt.err != nil {
+				mockService.On("CheckReadiness", mock.Anything).Return(nil, tt.err)
+			} else {
+				mockService.On("CheckReadiness", mock.Anything).Return(tt.readiness, nil)
+			}
+			handler := handlers.NewReadinessHandler(mockService)
+
+			w := httptest.NewRecorder()
+			handler.Ready(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			assert.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+}
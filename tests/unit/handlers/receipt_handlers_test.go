@@ -0,0 +1,140 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockReceiptService struct {
+	mock.Mock
+}
+
+func (m *MockReceiptService) GetReceipt(ctx context.Context, rentalID int) (*models.Receipt, error) {
+	args := m.Called(ctx, rentalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Receipt), args.Error(1)
+}
+
+func (m *MockReceiptService) GetCustomerInvoices(
+	ctx context.Context,
+	customerID int,
+	filters models.InvoiceFilters,
+) (*pagination.Paginated[models.Invoice], error) {
+	args := m.Called(ctx, customerID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.Invoice]), args.Error(1)
+}
+
+func TestReceiptHandler_GetReceipt(t *testing.T) {
+	tests := []struct {
+		name                string
+		query               string
+		accept              string
+		mockError           error
+		expectedStatusCode  int
+		expectedContentType string
+	}{
+		{
+			name:                "html by default",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/html; charset=utf-8",
+		},
+		{
+			name:                "json by query",
+			query:               "?format=json",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "json by accept header",
+			accept:              "application/json",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		{name: "rental not found", mockError: repository.ErrRentalNotFound, expectedStatusCode: http.StatusNotFound},
+		{name: "database error", mockError: errors.New("boom"), expectedStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReceiptService)
+			handler := handlers.NewReceiptHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("GetReceipt", mock.Anything, 42).Return(nil, tt.mockError)
+			} else {
+				mockService.On("GetReceipt", mock.Anything, 42).
+					Return(&models.Receipt{RentalID: 42, Payments: []models.ReceiptPayment{}}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/rentals/42/receipt"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			req.SetPathValue("id", "42")
+			w := httptest.NewRecorder()
+			handler.GetReceipt(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestReceiptHandler_GetCustomerInvoices(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedFilters    *models.InvoiceFilters
+		expectedStatusCode int
+	}{
+		{
+			name:               "defaults",
+			expectedFilters:    &models.InvoiceFilters{Page: 1, Limit: 20},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "explicit page",
+			query:              "?page=3&limit=5",
+			expectedFilters:    &models.InvoiceFilters{Page: 3, Limit: 5},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "page zero", query: "?page=0", expectedStatusCode: http.StatusBadRequest},
+		{name: "limit too large", query: "?limit=500", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReceiptService)
+			handler := handlers.NewReceiptHandler(mockService)
+			if tt.expectedFilters != nil {
+				mockService.On("GetCustomerInvoices", mock.Anything, 600, *tt.expectedFilters).
+					Return(&pagination.Paginated[models.Invoice]{Items: []models.Invoice{}}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/customers/600/invoices"+tt.query, nil)
+			req.SetPathValue("id", "600")
+			w := httptest.NewRecorder()
+			handler.GetCustomerInvoices(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockRentalService struct {
+	mock.Mock
+}
+
+func (m *MockRentalService) GetTrendingFilms(ctx context.Context) ([]models.TrendingFilm, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TrendingFilm), args.Error(1)
+}
+
+func (m *MockRentalService) GetFilmRentals(
+	ctx context.Context,
+	filmID int,
+	filters models.RentalHistoryFilters,
+) (*pagination.Paginated[models.RentalEvent], error) {
+	args := m.Called(ctx, filmID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.RentalEvent]), args.Error(1)
+}
+
+func (m *MockRentalService) GetCustomerRentals(
+	ctx context.Context,
+	customerID int,
+	filters models.RentalHistoryFilters,
+) (*pagination.Paginated[models.RentalEvent], error) {
+	args := m.Called(ctx, customerID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.RentalEvent]), args.Error(1)
+}
+
+func (m *MockRentalService) GetOverdueReport(
+	ctx context.Context,
+	filters models.OverdueReportFilters,
+) (*models.OverdueReport, error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OverdueReport), args.Error(1)
+}
+
+func (m *MockRentalService) ReturnRental(ctx context.Context, rentalID int) (*models.RentalEvent, error) {
+	args := m.Called(ctx, rentalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RentalEvent), args.Error(1)
+}
+
+func (m *MockRentalService) MarkOverdueRentals(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockRentalService) SendDueReminders(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockRentalService) RefreshTrendingFilms(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestRentalHandler_GetFilmRentals(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedFilters    *models.RentalHistoryFilters
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "defaults",
+			expectedFilters:    &models.RentalHistoryFilters{Params: pagination.Params{Page: 1, Limit: 20}},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "date range covers the whole to date",
+			query: "?from=2005-05-24&to=2005-05-31&page=2&limit=5",
+			expectedFilters: &models.RentalHistoryFilters{
+				From:   timePtr(time.Date(2005, 5, 24, 0, 0, 0, 0, time.UTC)),
+				To:     timePtr(time.Date(2005, 6, 1, 0, 0, 0, 0, time.UTC)),
+				Params: pagination.Params{Page: 2, Limit: 5},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "timestamps are taken as given",
+			query: "?from=2005-05-24T10:00:00Z&to=2005-05-24T12:00:00Z",
+			expectedFilters: &models.RentalHistoryFilters{
+				From:   timePtr(time.Date(2005, 5, 24, 10, 0, 0, 0, time.UTC)),
+				To:     timePtr(time.Date(2005, 5, 24, 12, 0, 0, 0, time.UTC)),
+				Params: pagination.Params{Page: 1, Limit: 20},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "film not found",
+			expectedFilters:    &models.RentalHistoryFilters{Params: pagination.Params{Page: 1, Limit: 20}},
+			mockError:          repository.ErrFilmNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "database error",
+			expectedFilters:    &models.RentalHistoryFilters{Params: pagination.Params{Page: 1, Limit: 20}},
+			mockError:          errors.New("boom"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "invalid from", query: "?from=yesterday", expectedStatusCode: http.StatusBadRequest},
+		{name: "reversed range", query: "?from=2005-06-01&to=2005-05-01", expectedStatusCode: http.StatusBadRequest},
+		{name: "page zero", query: "?page=0", expectedStatusCode: http.StatusBadRequest},
+		{name: "limit too large", query: "?limit=1000", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRentalService)
+			handler := handlers.NewRentalHandler(mockService)
+			if tt.expectedFilters != nil {
+				if tt.mockError != nil {
+					mockService.On("GetFilmRentals", mock.Anything, 1, *tt.expectedFilters).Return(nil, tt.mockError)
+				} else {
+					mockService.On("GetFilmRentals", mock.Anything, 1, *tt.expectedFilters).
+						Return(&pagination.Paginated[models.RentalEvent]{Items: []models.RentalEvent{}, Page: 1, Limit: 20}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/films/1/rentals"+tt.query, nil)
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+			handler.GetFilmRentals(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRentalHandler_GetCustomerRentals(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedFilters    *models.RentalHistoryFilters
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "all rentals",
+			expectedFilters:    &models.RentalHistoryFilters{Params: pagination.Params{Page: 1, Limit: 20}},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "overdue rentals",
+			query: "?status=overdue",
+			expectedFilters: &models.RentalHistoryFilters{
+				Status: models.RentalStatusOverdue, Params: pagination.Params{Page: 1, Limit: 20},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "customer not found",
+			expectedFilters:    &models.RentalHistoryFilters{Params: pagination.Params{Page: 1, Limit: 20}},
+			mockError:          repository.ErrCustomerNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "unknown status", query: "?status=lost", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRentalService)
+			handler := handlers.NewRentalHandler(mockService)
+			if tt.expectedFilters != nil {
+				if tt.mockError != nil {
+					mockService.On("GetCustomerRentals", mock.Anything, 600, *tt.expectedFilters).Return(nil, tt.mockError)
+				} else {
+					mockService.On("GetCustomerRentals", mock.Anything, 600, *tt.expectedFilters).
+						Return(&pagination.Paginated[models.RentalEvent]{Items: []models.RentalEvent{}, Page: 1, Limit: 20}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/customers/600/rentals"+tt.query, nil)
+			req.SetPathValue("id", "600")
+			w := httptest.NewRecorder()
+			handler.GetCustomerRentals(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRentalHandler_GetOverdueReport(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedFilters    *models.OverdueReportFilters
+		expectedStatusCode int
+	}{
+		{
+			name:               "highest fees first by default",
+			expectedFilters:    &models.OverdueReportFilters{Sort: models.OverdueSortFees, Order: "desc"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "titles sort ascending",
+			query:              "?sort=title",
+			expectedFilters:    &models.OverdueReportFilters{Sort: models.OverdueSortTitle, Order: "asc"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "explicit order",
+			query:              "?sort=days&order=asc",
+			expectedFilters:    &models.OverdueReportFilters{Sort: models.OverdueSortDays, Order: "asc"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "unknown sort", query: "?sort=customer", expectedStatusCode: http.StatusBadRequest},
+		{name: "unknown order", query: "?order=up", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRentalService)
+			handler := handlers.NewRentalHandler(mockService)
+			if tt.expectedFilters != nil {
+				mockService.On("GetOverdueReport", mock.Anything, *tt.expectedFilters).
+					Return(&models.OverdueReport{Films: []models.OverdueFilm{}}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/rentals/overdue"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.GetOverdueReport(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRentalHandler_GetOverdueReportCSV(t *testing.T) {
+	mockService := new(MockRentalService)
+	handler := handlers.NewRentalHandler(mockService)
+	mockService.On("GetOverdueReport", mock.Anything, mock.Anything).Return(&models.OverdueReport{
+		GeneratedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Films: []models.OverdueFilm{
+			{FilmID: 1, Title: "Alien, Director's Cut", OverdueRentals: 2, TotalDaysOverdue: 5, MaxDaysOverdue: 3,
+				AccruedFees: 5},
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/rentals/overdue", nil)
+	req.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+	handler.GetOverdueReport(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="overdue-rentals-2024-05-01.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "film_id,title,overdue_rentals,total_days_overdue,max_days_overdue,accrued_fees\n"+
+		`1,"Alien, Director's Cut",2,5,3,5.00`+"\n", w.Body.String())
+}
+
+func TestRentalHandler_ReturnRental(t *testing.T) {
+	tests := []struct {
+		name               string
+		rentalID           string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "returned", rentalID: "5", expectedStatusCode: http.StatusOK},
+		{name: "not found", rentalID: "5", mockError: repository.ErrRentalNotFound, expectedStatusCode: http.StatusNotFound},
+		{
+			name:               "already returned",
+			rentalID:           "5",
+			mockError:          repository.ErrRentalReturned,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{name: "invalid ID", rentalID: "abc", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRentalService)
+			handler := handlers.NewRentalHandler(mockService)
+			if tt.rentalID == "5" {
+				if tt.mockError != nil {
+					mockService.On("ReturnRental", mock.Anything, 5).Return(nil, tt.mockError)
+				} else {
+					mockService.On("ReturnRental", mock.Anything, 5).
+						Return(&models.RentalEvent{RentalID: 5, Status: models.RentalStatusReturned}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/rentals/"+tt.rentalID+"/return", nil)
+			req.SetPathValue("id", tt.rentalID)
+			w := httptest.NewRecorder()
+			handler.ReturnRental(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
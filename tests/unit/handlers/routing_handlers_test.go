@@ -0,0 +1,82 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/router"
+)
+
+// newRoutingRouter builds a router with a few routes, including some in a
+// group, and the JSON fallback handlers.
+func newRoutingRouter() *router.Mux {
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+	r := router.New(
+		router.WithNotFound(http.HandlerFunc(handlers.NotFound)),
+		router.WithMethodNotAllowed(http.HandlerFunc(handlers.MethodNotAllowed)),
+	)
+
+	api := r.Group("/api/v1")
+	api.HandleFunc("GET /films/{id}/comments", ok)
+	api.HandleFunc("POST /films/{id}/comments", ok)
+	api.HandleFunc("GET /films", ok)
+	return r
+}
+
+func decodeError(t *testing.T, rr *httptest.ResponseRecorder) models.ErrorResponse {
+	t.Helper()
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	var response models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response
+}
+
+func TestNotFoundHandler(t *testing.T) {
+	rr := httptest.NewRecorder()
+	newRoutingRouter().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/nope", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	response := decodeError(t, rr)
+	assert.Equal(t, "Not found", response.Error)
+	assert.Contains(t, response.Details, "/api/v1/nope")
+}
+
+func TestMethodNotAllowedHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		allow  string
+	}{
+		{"single method", http.MethodDelete, "/api/v1/films", "GET, HEAD"},
+		{"several methods", http.MethodPut, "/api/v1/films/1/comments", "GET, HEAD, POST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			newRoutingRouter().ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+			assert.Equal(t, tt.allow, rr.Header().Get("Allow"))
+			response := decodeError(t, rr)
+			assert.Equal(t, "Method not allowed", response.Error)
+			assert.Contains(t, response.Details, tt.method)
+		})
+	}
+}
+
+func TestRoutingHandlers_MatchedRouteUnaffected(t *testing.T) {
+	rr := httptest.NewRecorder()
+	newRoutingRouter().ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/films/1/comments", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Allow"))
+}
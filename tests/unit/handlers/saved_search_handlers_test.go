@@ -0,0 +1,113 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockSavedSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchService) CreateSavedSearch(
+	ctx context.Context,
+	customerID int,
+	searchReq models.SavedSearchRequest,
+) (*models.SavedSearch, error) {
+	args := m.Called(ctx, customerID, searchReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchService) ListSavedSearches(ctx context.Context, customerID int) ([]models.SavedSearch, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchService) DeleteSavedSearch(ctx context.Context, customerID, searchID int) error {
+	return m.Called(ctx, customerID, searchID).Error(0)
+}
+
+func (m *MockSavedSearchService) NotifyNewMatches(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func TestSavedSearchHandler_CreateSavedSearch(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "saved", body: `{"name": "Scary", "filters": {"categories": ["Horror"]}, "notify": true}`,
+			expectedStatusCode: http.StatusCreated},
+		{name: "too many", body: `{"name": "Scary", "filters": {"categories": ["Horror"]}, "notify": true}`,
+			mockError: repository.ErrSavedSearchLimit, expectedStatusCode: http.StatusConflict},
+		{name: "missing name", body: `{"filters": {"categories": ["Horror"]}}`,
+			expectedStatusCode: http.StatusBadRequest},
+		{name: "unknown rating", body: `{"name": "Adults", "filters": {"ratings": ["X"]}}`,
+			expectedStatusCode: http.StatusBadRequest},
+		{name: "blank category", body: `{"name": "Blank", "filters": {"categories": [" "]}}`,
+			expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSavedSearchService)
+			handler := handlers.NewSavedSearchHandler(mockService)
+			searchReq := models.SavedSearchRequest{
+				Name: "Scary", Filters: models.SavedSearchFilters{Categories: []string{"Horror"}}, Notify: true,
+			}
+			if tt.mockError != nil {
+				mockService.On("CreateSavedSearch", mock.Anything, 600, searchReq).Return(nil, tt.mockError)
+			} else {
+				mockService.On("CreateSavedSearch", mock.Anything, 600, searchReq).
+					Return(&models.SavedSearch{ID: 1, CustomerID: 600, Name: "Scary", Filters: searchReq.Filters,
+						Notify: true}, nil).
+					Maybe()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/customers/600/saved-searches", strings.NewReader(tt.body))
+			req.SetPathValue("id", "600")
+			w := httptest.NewRecorder()
+			handler.CreateSavedSearch(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestSavedSearchHandler_DeleteSavedSearch(t *testing.T) {
+	mockService := new(MockSavedSearchService)
+	handler := handlers.NewSavedSearchHandler(mockService)
+	mockService.On("DeleteSavedSearch", mock.Anything, 600, 1).Return(nil)
+	mockService.On("DeleteSavedSearch", mock.Anything, 600, 2).Return(repository.ErrSavedSearchNotFound)
+
+	for searchID, expected := range map[string]int{
+		"1": http.StatusOK, "2": http.StatusNotFound, "abc": http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodDelete, "/customers/600/saved-searches/"+searchID, nil)
+		req.SetPathValue("id", "600")
+		req.SetPathValue("searchID", searchID)
+		w := httptest.NewRecorder()
+		handler.DeleteSavedSearch(w, req)
+
+		assert.Equal(t, expected, w.Code, searchID)
+	}
+}
@@ -0,0 +1,67 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+type MockSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSearchService) SuggestTitles(ctx context.Context, title string) []string {
+	suggestions, _ := m.Called(ctx, title).Get(0).([]string)
+	return suggestions
+}
+
+func (m *MockSearchService) ListMisses(
+	ctx context.Context,
+	params pagination.Params,
+) (*pagination.Paginated[models.SearchMiss], error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.SearchMiss]), args.Error(1)
+}
+
+func TestSearchHandler_ListZeroResults(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		params             pagination.Params
+		expectedStatusCode int
+	}{
+		{name: "default page", params: pagination.Params{Page: 1, Limit: 20}, expectedStatusCode: http.StatusOK},
+		{name: "second page", query: "?page=2&limit=50", params: pagination.Params{Page: 2, Limit: 50},
+			expectedStatusCode: http.StatusOK},
+		{name: "limit too large", query: "?limit=500", expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid page", query: "?page=0", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSearchService)
+			handler := handlers.NewSearchHandler(mockService)
+			misses := pagination.New([]models.SearchMiss{{Term: "acadmy", Searches: 3}}, 1, tt.params)
+			mockService.On("ListMisses", mock.Anything, tt.params).Return(misses, nil).Maybe()
+
+			w := httptest.NewRecorder()
+			handler.ListZeroResults(w, httptest.NewRequest(http.MethodGet, "/admin/search/zero-results"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"term":"acadmy"`)
+			}
+		})
+	}
+}
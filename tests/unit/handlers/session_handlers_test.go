@@ -0,0 +1,195 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/middleware"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockSessionService struct {
+	mock.Mock
+}
+
+func (m *MockSessionService) StartSession(
+	ctx context.Context,
+	role string,
+	subjectID int,
+	twoFactor bool,
+) (*models.TokenResponse, error) {
+	args := m.Called(ctx, role, subjectID, twoFactor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TokenResponse), args.Error(1)
+}
+
+func (m *MockSessionService) Refresh(ctx context.Context, refreshToken string) (*models.TokenResponse, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TokenResponse), args.Error(1)
+}
+
+func (m *MockSessionService) Logout(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockSessionService) ListCustomerSessions(ctx context.Context, customerID int) ([]models.AuthSession, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuthSession), args.Error(1)
+}
+
+func (m *MockSessionService) RevokeCustomerSession(ctx context.Context, customerID int, sessionID int64) error {
+	return m.Called(ctx, customerID, sessionID).Error(0)
+}
+
+func TestSessionHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "refreshed", body: `{"refresh_token": "mbr_old"}`, expectedStatusCode: http.StatusOK},
+		{name: "invalid refresh token", body: `{"refresh_token": "mbr_old"}`,
+			mockError: service.ErrInvalidRefreshToken, expectedStatusCode: http.StatusUnauthorized},
+		{name: "missing refresh token", body: `{}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid body", body: `nope`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSessionService)
+			handler := handlers.NewSessionHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("Refresh", mock.Anything, "mbr_old").Return(nil, tt.mockError)
+			} else {
+				refreshExpiresAt := time.Now().Add(time.Hour)
+				mockService.On("Refresh", mock.Anything, "mbr_old").Return(&models.TokenResponse{
+					Token: "access", ExpiresAt: time.Now().Add(time.Minute),
+					RefreshToken: "mbr_new", RefreshExpiresAt: &refreshExpiresAt,
+				}, nil).Maybe()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.Refresh(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"refresh_token":"mbr_new"`)
+			}
+		})
+	}
+}
+
+func TestSessionHandler_RefreshRecordsTrustedClientAddr(t *testing.T) {
+	trustedProxies, err := middleware.ParseNetworks([]string{"192.168.0.0/16"})
+	require.NoError(t, err)
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expectedIP string
+	}{
+		{name: "forwarded by a trusted proxy", remoteAddr: "192.168.1.1:5000", expectedIP: "203.0.113.7"},
+		{name: "forged by the client", remoteAddr: "198.51.100.1:5000", expectedIP: "198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSessionService)
+			handler := middleware.TrustedClient(trustedProxies)(
+				http.HandlerFunc(handlers.NewSessionHandler(mockService).Refresh))
+			var client models.SessionClient
+			mockService.On("Refresh", mock.Anything, "mbr_old").
+				Run(func(args mock.Arguments) {
+					client = auth.SessionClientFromContext(args.Get(0).(context.Context))
+				}).
+				Return(nil, service.ErrInvalidRefreshToken)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(`{"refresh_token": "mbr_old"}`))
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expectedIP, client.IPAddress)
+		})
+	}
+}
+
+func TestSessionHandler_Logout(t *testing.T) {
+	mockService := new(MockSessionService)
+	handler := handlers.NewSessionHandler(mockService)
+	mockService.On("Logout", mock.Anything).Return(service.ErrSessionTokenRequired)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	w := httptest.NewRecorder()
+	handler.Logout(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSessionHandler_ListCustomerSessions(t *testing.T) {
+	mockService := new(MockSessionService)
+	handler := handlers.NewSessionHandler(mockService)
+	mockService.On("ListCustomerSessions", mock.Anything, 600).Return([]models.AuthSession{
+		{ID: 9, Role: "customer", SubjectID: 600, UserAgent: "Firefox", IPAddress: "203.0.113.7", Current: true},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/customers/600/sessions", nil)
+	req.SetPathValue("id", "600")
+	w := httptest.NewRecorder()
+	handler.ListCustomerSessions(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"user_agent":"Firefox"`)
+	assert.Contains(t, w.Body.String(), `"current":true`)
+}
+
+func TestSessionHandler_RevokeCustomerSession(t *testing.T) {
+	tests := []struct {
+		name               string
+		sessionID          string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "revoked", sessionID: "9", expectedStatusCode: http.StatusOK},
+		{name: "session not found", sessionID: "9", mockError: repository.ErrSessionNotFound,
+			expectedStatusCode: http.StatusNotFound},
+		{name: "invalid session ID", sessionID: "abc", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSessionService)
+			handler := handlers.NewSessionHandler(mockService)
+			mockService.On("RevokeCustomerSession", mock.Anything, 600, int64(9)).Return(tt.mockError).Maybe()
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/customers/600/sessions/"+tt.sessionID, nil)
+			req.SetPathValue("id", "600")
+			req.SetPathValue("sessionID", tt.sessionID)
+			w := httptest.NewRecorder()
+			handler.RevokeCustomerSession(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockShortLinkService struct {
+	mock.Mock
+}
+
+func (m *MockShortLinkService) CreateShortLink(
+	ctx context.Context,
+	filmID int,
+	linkReq models.ShortLinkRequest,
+) (*models.ShortLink, error) {
+	args := m.Called(ctx, filmID, linkReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShortLink), args.Error(1)
+}
+
+func (m *MockShortLinkService) ListFilmShortLinks(ctx context.Context, filmID int) ([]models.ShortLink, error) {
+	args := m.Called(ctx, filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ShortLink), args.Error(1)
+}
+
+func (m *MockShortLinkService) FollowShortLink(ctx context.Context, code string) (string, error) {
+	args := m.Called(ctx, code)
+	return args.String(0), args.Error(1)
+}
+
+func TestShortLinkHandler_CreateShortLink(t *testing.T) {
+	campaign := "spring-sale"
+	tests := []struct {
+		name               string
+		body               string
+		expectedReq        *models.ShortLinkRequest
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "without a body",
+			expectedReq:        &models.ShortLinkRequest{},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "with a campaign",
+			body:               `{"campaign":"spring-sale"}`,
+			expectedReq:        &models.ShortLinkRequest{Campaign: &campaign},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "film not found",
+			expectedReq:        &models.ShortLinkRequest{},
+			mockError:          repository.ErrFilmNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "database error",
+			expectedReq:        &models.ShortLinkRequest{},
+			mockError:          errors.New("boom"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "empty campaign", body: `{"campaign":""}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockShortLinkService)
+			handler := handlers.NewShortLinkHandler(mockService)
+			if tt.expectedReq != nil {
+				if tt.mockError != nil {
+					mockService.On("CreateShortLink", mock.Anything, 1, *tt.expectedReq).Return(nil, tt.mockError)
+				} else {
+					mockService.On("CreateShortLink", mock.Anything, 1, *tt.expectedReq).
+						Return(&models.ShortLink{ID: 1, Code: "k3x9qm2a", Path: "/f/k3x9qm2a", FilmID: 1}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/films/1/shortlink", strings.NewReader(tt.body))
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+			handler.CreateShortLink(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShortLinkHandler_ListFilmShortLinks(t *testing.T) {
+	mockService := new(MockShortLinkService)
+	handler := handlers.NewShortLinkHandler(mockService)
+	mockService.On("ListFilmShortLinks", mock.Anything, 1).
+		Return([]models.ShortLink{{ID: 1, Code: "k3x9qm2a", Path: "/f/k3x9qm2a", FilmID: 1, Clicks: 12}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/films/1/shortlinks", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	handler.ListFilmShortLinks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var links []models.ShortLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &links))
+	assert.Equal(t, int64(12), links[0].Clicks)
+}
+
+func TestShortLinkHandler_FollowShortLink(t *testing.T) {
+	mockService := new(MockShortLinkService)
+	handler := handlers.NewShortLinkHandler(mockService)
+	mockService.On("FollowShortLink", mock.Anything, "k3x9qm2a").Return("/api/v1/films/42", nil)
+	mockService.On("FollowShortLink", mock.Anything, "missing1").Return("", repository.ErrShortLinkNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/f/k3x9qm2a", nil)
+	req.SetPathValue("code", "k3x9qm2a")
+	w := httptest.NewRecorder()
+	handler.FollowShortLink(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/api/v1/films/42", w.Header().Get("Location"))
+
+	req = httptest.NewRequest(http.MethodGet, "/f/missing1", nil)
+	req.SetPathValue("code", "missing1")
+	w = httptest.NewRecorder()
+	handler.FollowShortLink(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,93 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/service"
+	"github.com/rxbenefits/go-hw/internal/sitemaps"
+)
+
+type MockSitemapService struct {
+	mock.Mock
+}
+
+func (m *MockSitemapService) SitemapIndex(ctx context.Context) (*sitemaps.Index, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sitemaps.Index), args.Error(1)
+}
+
+func (m *MockSitemapService) FilmSitemap(ctx context.Context, page int) (*sitemaps.URLSet, error) {
+	args := m.Called(ctx, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sitemaps.URLSet), args.Error(1)
+}
+
+func TestSitemapHandler_GetSitemapIndex(t *testing.T) {
+	mockService := new(MockSitemapService)
+	handler := handlers.NewSitemapHandler(mockService)
+	index := sitemaps.NewIndex()
+	index.Sitemaps = append(index.Sitemaps, sitemaps.Sitemap{Loc: "http://localhost:8080/sitemaps/films-1.xml"})
+	mockService.On("SitemapIndex", mock.Anything).Return(index, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	w := httptest.NewRecorder()
+	handler.GetSitemapIndex(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), "<loc>http://localhost:8080/sitemaps/films-1.xml</loc>")
+
+	req = httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	handler.GetSitemapIndex(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestSitemapHandler_GetFilmSitemap(t *testing.T) {
+	mockService := new(MockSitemapService)
+	handler := handlers.NewSitemapHandler(mockService)
+	urlSet := sitemaps.NewURLSet()
+	urlSet.URLs = append(urlSet.URLs, sitemaps.URL{Loc: "http://localhost:8080/api/v1/films/1"})
+	mockService.On("FilmSitemap", mock.Anything, 1).Return(urlSet, nil)
+	mockService.On("FilmSitemap", mock.Anything, 9).Return(nil, service.ErrSitemapNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/sitemaps/films-1.xml", nil)
+	req.SetPathValue("file", "films-1.xml")
+	w := httptest.NewRecorder()
+	handler.GetFilmSitemap(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<loc>http://localhost:8080/api/v1/films/1</loc>")
+
+	req = httptest.NewRequest(http.MethodGet, "/sitemaps/films-9.xml", nil)
+	req.SetPathValue("file", "films-9.xml")
+	w = httptest.NewRecorder()
+	handler.GetFilmSitemap(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	for _, file := range []string{"films-x.xml", "films-0.xml", "actors-1.xml", "films-1.txt"} {
+		req = httptest.NewRequest(http.MethodGet, "/sitemaps/"+file, nil)
+		req.SetPathValue("file", file)
+		w = httptest.NewRecorder()
+		handler.GetFilmSitemap(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code, file)
+	}
+	mockService.AssertNumberOfCalls(t, "FilmSitemap", 2)
+}
@@ -0,0 +1,156 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockStaffService struct {
+	mock.Mock
+}
+
+func (m *MockStaffService) ListStaff(ctx context.Context) ([]models.Staff, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Staff), args.Error(1)
+}
+
+func (m *MockStaffService) CreateStaff(ctx context.Context, staffReq models.StaffRequest) (*models.Staff, error) {
+	args := m.Called(ctx, staffReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Staff), args.Error(1)
+}
+
+func (m *MockStaffService) DeactivateStaff(ctx context.Context, staffID int) (*models.Staff, error) {
+	args := m.Called(ctx, staffID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Staff), args.Error(1)
+}
+
+func (m *MockStaffService) Login(
+	ctx context.Context,
+	loginReq models.StaffLoginRequest,
+) (*models.TokenResponse, error) {
+	args := m.Called(ctx, loginReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TokenResponse), args.Error(1)
+}
+
+func TestStaffHandler_CreateStaff(t *testing.T) {
+	validBody := `{"first_name":"Jane","last_name":"Doe","address_id":3,"store_id":1,` +
+		`"username":"jane","password":"s3cret-pass"}`
+	shortPasswordBody := `{"first_name":"Jane","last_name":"Doe","address_id":3,"store_id":1,` +
+		`"username":"jane","password":"x"}`
+
+	tests := []struct {
+		name               string
+		body               string
+		mockResponse       *models.Staff
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "successful creation",
+			body:               validBody,
+			mockResponse:       &models.Staff{StaffID: 3, Username: "jane", Active: true},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "username taken",
+			body:               validBody,
+			mockError:          repository.ErrUsernameTaken,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "unknown store",
+			body:               validBody,
+			mockError:          repository.ErrInvalidReference,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "short password",
+			body:               shortPasswordBody,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStaffService := new(MockStaffService)
+			handler := handlers.NewStaffHandler(mockStaffService)
+			if tt.mockResponse != nil || tt.mockError != nil {
+				mockStaffService.On("CreateStaff", mock.Anything, mock.Anything).Return(tt.mockResponse, tt.mockError)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/staff", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.CreateStaff(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockStaffService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStaffHandler_Login(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		loginReq   models.StaffLoginRequest
+		serviceErr error
+	}{
+		{name: "wrong password", body: `{"username":"mike","password":"wrong"}`,
+			loginReq: models.StaffLoginRequest{Username: "mike", Password: "wrong"}, serviceErr: service.ErrInvalidCredentials},
+		{name: "missing two-factor code", body: `{"username":"mike","password":"right"}`,
+			loginReq: models.StaffLoginRequest{Username: "mike", Password: "right"}, serviceErr: service.ErrTwoFactorRequired},
+		{name: "wrong two-factor code", body: `{"username":"mike","password":"right","otp_code":"000000"}`,
+			loginReq:   models.StaffLoginRequest{Username: "mike", Password: "right", OTPCode: "000000"},
+			serviceErr: service.ErrInvalidTwoFactorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStaffService := new(MockStaffService)
+			handler := handlers.NewStaffHandler(mockStaffService)
+			mockStaffService.On("Login", mock.Anything, tt.loginReq).Return(nil, tt.serviceErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/staff/login", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.Login(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+}
+
+func TestStaffHandler_DeactivateStaffNotFound(t *testing.T) {
+	mockStaffService := new(MockStaffService)
+	handler := handlers.NewStaffHandler(mockStaffService)
+	mockStaffService.On("DeactivateStaff", mock.Anything, 99).Return(nil, repository.ErrStaffNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/staff/99/deactivate", nil)
+	req.SetPathValue("id", "99")
+	w := httptest.NewRecorder()
+
+	handler.DeactivateStaff(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,147 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) TagFilm(ctx context.Context, filmID int, tag string) (*models.FilmTags, error) {
+	args := m.Called(ctx, filmID, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FilmTags), args.Error(1)
+}
+
+func (m *MockTagService) UntagFilm(ctx context.Context, filmID int, tag string) (*models.FilmTags, error) {
+	args := m.Called(ctx, filmID, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FilmTags), args.Error(1)
+}
+
+func (m *MockTagService) ListTags(ctx context.Context) ([]models.TagCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TagCount), args.Error(1)
+}
+
+func TestTagHandler_TagFilm(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "tagged", body: `{"tag": "Cult Classic"}`, expectedStatusCode: http.StatusOK},
+		{
+			name:               "film not found",
+			body:               `{"tag": "noir"}`,
+			mockError:          repository.ErrFilmNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "blank tag",
+			body:               `{"tag": "   "}`,
+			mockError:          service.ErrInvalidTag,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "missing tag", body: `{}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "tag too long", body: `{"tag": "` + strings.Repeat("x", 51) + `"}`, expectedStatusCode: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockTagService)
+			handler := handlers.NewTagHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("TagFilm", mock.Anything, 1, mock.Anything).Return(nil, tt.mockError)
+			} else {
+				mockService.On("TagFilm", mock.Anything, 1, "Cult Classic").
+					Return(&models.FilmTags{FilmID: 1, Tags: []string{"cult classic"}}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/films/1/tags", strings.NewReader(tt.body))
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+			handler.TagFilm(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestTagHandler_UntagFilm(t *testing.T) {
+	tests := []struct {
+		name               string
+		mockError          error
+		expectedStatusCode int
+	}{
+		{name: "untagged", expectedStatusCode: http.StatusOK},
+		{name: "film not found", mockError: repository.ErrFilmNotFound, expectedStatusCode: http.StatusNotFound},
+		{name: "tag not on film", mockError: repository.ErrFilmTagNotFound, expectedStatusCode: http.StatusNotFound},
+		{name: "database error", mockError: errors.New("boom"), expectedStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockTagService)
+			handler := handlers.NewTagHandler(mockService)
+			if tt.mockError != nil {
+				mockService.On("UntagFilm", mock.Anything, 1, "noir").Return(nil, tt.mockError)
+			} else {
+				mockService.On("UntagFilm", mock.Anything, 1, "noir").Return(&models.FilmTags{FilmID: 1, Tags: []string{}}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/films/1/tags/noir", nil)
+			req.SetPathValue("id", "1")
+			req.SetPathValue("tag", "noir")
+			w := httptest.NewRecorder()
+			handler.UntagFilm(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagHandler_ListTags(t *testing.T) {
+	mockService := new(MockTagService)
+	handler := handlers.NewTagHandler(mockService)
+	mockService.On("ListTags", mock.Anything).Return([]models.TagCount{
+		{Tag: "cult classic", FilmCount: 12},
+		{Tag: "noir", FilmCount: 3},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	w := httptest.NewRecorder()
+	handler.ListTags(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var tags []models.TagCount
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+	assert.Equal(t, "cult classic", tags[0].Tag)
+	assert.Equal(t, 12, tags[0].FilmCount)
+}
@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockTwoFactorService struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorService) EnrollTwoFactor(ctx context.Context, staffID int) (*models.TwoFactorEnrollment, error) {
+	args := m.Called(ctx, staffID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TwoFactorEnrollment), args.Error(1)
+}
+
+func (m *MockTwoFactorService) ConfirmTwoFactor(ctx context.Context, staffID int, code string) error {
+	return m.Called(ctx, staffID, code).Error(0)
+}
+
+func (m *MockTwoFactorService) VerifyLogin(ctx context.Context, staffID int, code string) (bool, error) {
+	args := m.Called(ctx, staffID, code)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestTwoFactorHandler_Enroll(t *testing.T) {
+	mockService := new(MockTwoFactorService)
+	handler := handlers.NewTwoFactorHandler(mockService)
+	enrollment := &models.TwoFactorEnrollment{
+		Secret:          "ABCDEF",
+		ProvisioningURI: "otpauth://totp/Mockbuster:mike?issuer=Mockbuster&secret=ABCDEF",
+		BackupCodes:     []string{"abcde-12345"},
+	}
+	mockService.On("EnrollTwoFactor", mock.Anything, 1).Return(enrollment, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/staff/1/two-factor", nil)
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	handler.Enroll(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var got models.TwoFactorEnrollment
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, *enrollment, got)
+}
+
+func TestTwoFactorHandler_Confirm(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "enabled", body: `{"code":"123456"}`, expectedStatus: http.StatusOK},
+		{name: "missing code", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "wrong code", body: `{"code":"123456"}`, serviceErr: service.ErrInvalidTwoFactorCode,
+			expectedStatus: http.StatusBadRequest},
+		{name: "not enrolled", body: `{"code":"123456"}`, serviceErr: repository.ErrTwoFactorNotFound,
+			expectedStatus: http.StatusNotFound},
+		{name: "already enabled", body: `{"code":"123456"}`, serviceErr: repository.ErrTwoFactorEnabled,
+			expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockTwoFactorService)
+			handler := handlers.NewTwoFactorHandler(mockService)
+			mockService.On("ConfirmTwoFactor", mock.Anything, 1, "123456").Return(tt.serviceErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/staff/1/two-factor/confirm",
+				bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "1")
+			w := httptest.NewRecorder()
+			handler.Confirm(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
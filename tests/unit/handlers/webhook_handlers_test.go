@@ -0,0 +1,170 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/handlers"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) CreateSubscription(
+	ctx context.Context,
+	subscriptionReq models.WebhookSubscriptionRequest,
+) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, subscriptionReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteSubscription(ctx context.Context, subscriptionID int) error {
+	args := m.Called(ctx, subscriptionID)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) RotateSecret(ctx context.Context, subscriptionID int) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) ListDeliveries(
+	ctx context.Context,
+	subscriptionID int,
+	filters models.WebhookDeliveryFilters,
+) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, filters)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) ReplayDelivery(
+	ctx context.Context,
+	subscriptionID int,
+	deliveryID int64,
+) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) PurgeDeliveries(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestWebhookHandler_CreateSubscription(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		expectCreate       bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "valid subscription",
+			body:               `{"url":"https://example.com/hooks","events":["comment.created"]}`,
+			expectCreate:       true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			name:               "unknown event",
+			body:               `{"url":"https://example.com/hooks","events":["film.deleted"]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "not an http url",
+			body:               `{"url":"ftp://example.com/hooks","events":["comment.created"]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "no events",
+			body:               `{"url":"https://example.com/hooks","events":[]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWebhookService)
+			handler := handlers.NewWebhookHandler(mockService)
+			if tt.expectCreate {
+				mockService.On("CreateSubscription", mock.Anything, mock.Anything).
+					Return(&models.WebhookSubscription{ID: 1, Secret: "whsec_x"}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/webhooks", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			handler.CreateSubscription(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_ReplayDelivery(t *testing.T) {
+	tests := []struct {
+		name               string
+		mockResponse       *models.WebhookDelivery
+		mockError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "replayed",
+			mockResponse:       &models.WebhookDelivery{ID: 9, Status: models.WebhookDeliveryPending},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "not found",
+			mockError:          repository.ErrWebhookDeliveryNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "not failed",
+			mockError:          repository.ErrWebhookDeliveryNotFailed,
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWebhookService)
+			handler := handlers.NewWebhookHandler(mockService)
+			if tt.mockResponse != nil {
+				mockService.On("ReplayDelivery", mock.Anything, 3, int64(9)).Return(tt.mockResponse, nil)
+			} else {
+				mockService.On("ReplayDelivery", mock.Anything, 3, int64(9)).Return(nil, tt.mockError)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/webhooks/3/deliveries/9/replay", nil)
+			req.SetPathValue("id", "3")
+			req.SetPathValue("deliveryID", "9")
+			w := httptest.NewRecorder()
+			handler.ReplayDelivery(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
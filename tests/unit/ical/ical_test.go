@@ -0,0 +1,68 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/ical"
+)
+
+func TestMarshal(t *testing.T) {
+	rented := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("EDT", -4*60*60))
+	calendar := ical.Calendar{
+		Name: "Mockbuster rentals",
+		Events: []ical.Event{{
+			UID:         "rental-1@mockbuster",
+			Stamp:       rented,
+			Start:       rented.AddDate(0, 0, 3),
+			Summary:     "Return Alien, Director's Cut",
+			Description: "Rented 2024-05-01; store 1",
+			URL:         "http://localhost:8080/api/v1/films/1",
+		}},
+	}
+
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Mockbuster//Mockbuster Movie API//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Mockbuster rentals",
+		"BEGIN:VEVENT",
+		"UID:rental-1@mockbuster",
+		"DTSTAMP:20240501T140000Z",
+		"DTSTART:20240504T140000Z",
+		"DTEND:20240504T140000Z",
+		`SUMMARY:Return Alien\, Director's Cut`,
+		`DESCRIPTION:Rented 2024-05-01\; store 1`,
+		"URL:http://localhost:8080/api/v1/films/1",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), string(ical.Marshal(calendar)))
+}
+
+func TestMarshalFoldsLongLines(t *testing.T) {
+	title := strings.Repeat("é", 60)
+	body := string(ical.Marshal(ical.Calendar{Events: []ical.Event{{Summary: title}}}))
+
+	var summary []string
+	inSummary := false
+	for _, line := range strings.Split(body, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+		switch {
+		case strings.HasPrefix(line, "SUMMARY:"):
+			inSummary = true
+			summary = append(summary, line)
+		case inSummary && strings.HasPrefix(line, " "):
+			summary = append(summary, line[1:])
+		default:
+			inSummary = false
+		}
+	}
+	assert.Greater(t, len(summary), 1)
+	assert.Equal(t, "SUMMARY:"+title, strings.Join(summary, ""))
+}
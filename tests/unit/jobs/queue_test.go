@@ -0,0 +1,189 @@
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/jobs"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// memoryStore is an in-memory jobs.Store.
+type memoryStore struct {
+	mu     sync.Mutex
+	nextID int64
+	jobs   map[int64]*models.BackgroundJob
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{jobs: make(map[int64]*models.BackgroundJob)}
+}
+
+func (s *memoryStore) InsertJob(
+	_ context.Context,
+	kind string,
+	payload []byte,
+	maxAttempts int,
+) (*models.BackgroundJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	job := &models.BackgroundJob{
+		ID: s.nextID, Kind: kind, Payload: payload, Status: models.JobStatusPending,
+		MaxAttempts: maxAttempts, RunAt: time.Now(),
+	}
+	s.jobs[job.ID] = job
+	copied := *job
+	return &copied, nil
+}
+
+func (s *memoryStore) ClaimJob(_ context.Context, kinds []string, _ time.Duration) (*models.BackgroundJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := int64(1); id <= s.nextID; id++ {
+		job := s.jobs[id]
+		if job.Status == models.JobStatusPending && !job.RunAt.After(time.Now()) && slices.Contains(kinds, job.Kind) {
+			job.Status = models.JobStatusRunning
+			job.Attempts++
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, nil //nolint:nilnil // No job is due
+}
+
+func (s *memoryStore) CompleteJob(_ context.Context, jobID int64) error {
+	return s.update(jobID, models.JobStatusSucceeded, "", time.Time{})
+}
+
+func (s *memoryStore) RetryJobLater(_ context.Context, jobID int64, lastError string, runAt time.Time) error {
+	return s.update(jobID, models.JobStatusPending, lastError, runAt)
+}
+
+func (s *memoryStore) BuryJob(_ context.Context, jobID int64, lastError string) error {
+	return s.update(jobID, models.JobStatusDead, lastError, time.Time{})
+}
+
+func (s *memoryStore) update(jobID int64, status, lastError string, runAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.jobs[jobID]
+	job.Status = status
+	if lastError != "" {
+		job.LastError = &lastError
+	}
+	if !runAt.IsZero() {
+		job.RunAt = runAt
+	}
+	return nil
+}
+
+func (s *memoryStore) job(jobID int64) models.BackgroundJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.jobs[jobID]
+}
+
+var fastRetries = jobs.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func TestQueue_RunsJobs(t *testing.T) {
+	store := newMemoryStore()
+	queue := jobs.NewQueue(store, 2, 10*time.Millisecond, time.Second)
+
+	var total atomic.Int32
+	queue.Handle("add", func(_ context.Context, payload json.RawMessage) error {
+		var n int32
+		if err := json.Unmarshal(payload, &n); err != nil {
+			return err
+		}
+		total.Add(n)
+		return nil
+	}, fastRetries)
+	queue.Start()
+
+	for n := range 5 {
+		require.NoError(t, queue.Enqueue("add", n+1))
+	}
+
+	require.Eventually(t, func() bool { return total.Load() == 15 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, queue.Shutdown(context.Background()))
+	for id := int64(1); id <= 5; id++ {
+		assert.Equal(t, models.JobStatusSucceeded, store.job(id).Status)
+	}
+}
+
+func TestQueue_RetriesThenBuries(t *testing.T) {
+	tests := []struct {
+		name           string
+		failures       int32
+		expectedStatus string
+		expectedCalls  int32
+	}{
+		{name: "succeeds after retries", failures: 2, expectedStatus: models.JobStatusSucceeded, expectedCalls: 3},
+		{name: "dead after max attempts", failures: 10, expectedStatus: models.JobStatusDead, expectedCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore()
+			queue := jobs.NewQueue(store, 1, 5*time.Millisecond, time.Second)
+
+			var calls atomic.Int32
+			queue.Handle("flaky", func(context.Context, json.RawMessage) error {
+				if calls.Add(1) <= tt.failures {
+					return errors.New("boom")
+				}
+				return nil
+			}, fastRetries)
+			queue.Start()
+			require.NoError(t, queue.Enqueue("flaky", nil))
+
+			require.Eventually(t, func() bool {
+				return store.job(1).Status == tt.expectedStatus
+			}, time.Second, 5*time.Millisecond)
+			require.NoError(t, queue.Shutdown(context.Background()))
+
+			assert.Equal(t, tt.expectedCalls, calls.Load())
+			assert.Equal(t, tt.expectedCalls, int32(store.job(1).Attempts))
+		})
+	}
+}
+
+func TestQueue_PanicCountsAsFailure(t *testing.T) {
+	store := newMemoryStore()
+	queue := jobs.NewQueue(store, 1, 5*time.Millisecond, time.Second)
+	queue.Handle("panicking", func(context.Context, json.RawMessage) error { panic("boom") },
+		jobs.RetryPolicy{MaxAttempts: 1})
+	queue.Start()
+	require.NoError(t, queue.Enqueue("panicking", nil))
+
+	require.Eventually(t, func() bool {
+		return store.job(1).Status == models.JobStatusDead
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, queue.Shutdown(context.Background()))
+	assert.Equal(t, "panic: boom", *store.job(1).LastError)
+}
+
+func TestQueue_UnknownKind(t *testing.T) {
+	queue := jobs.NewQueue(newMemoryStore(), 1, time.Second, time.Second)
+
+	assert.ErrorIs(t, queue.Enqueue("missing", nil), jobs.ErrUnknownKind)
+}
+
+func TestQueue_Closed(t *testing.T) {
+	queue := jobs.NewQueue(newMemoryStore(), 1, time.Second, time.Second)
+	queue.Handle("test", func(context.Context, json.RawMessage) error { return nil }, fastRetries)
+	queue.Start()
+	require.NoError(t, queue.Shutdown(context.Background()))
+
+	assert.ErrorIs(t, queue.Enqueue("test", nil), jobs.ErrQueueClosed)
+}
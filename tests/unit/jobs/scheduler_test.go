@@ -0,0 +1,107 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/jobs"
+)
+
+// fakeLeaser grants leases according to grant and counts releases.
+type fakeLeaser struct {
+	grant    bool
+	err      error
+	released atomic.Int32
+}
+
+func (l *fakeLeaser) TryAcquire(context.Context, string, time.Duration) (func(), bool, error) {
+	if l.err != nil || !l.grant {
+		return nil, false, l.err
+	}
+	return func() { l.released.Add(1) }, true, nil
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	tests := []struct {
+		name         string
+		leaser       *fakeLeaser
+		run          func(context.Context) error
+		expectedRuns int32
+		expectedRel  int32
+	}{
+		{name: "runs when leased", leaser: &fakeLeaser{grant: true}, expectedRuns: 1, expectedRel: 1},
+		{name: "skips when held elsewhere", leaser: &fakeLeaser{grant: false}},
+		{name: "skips on lease error", leaser: &fakeLeaser{err: errors.New("db down")}},
+		{
+			name:         "releases after failure",
+			leaser:       &fakeLeaser{grant: true},
+			run:          func(context.Context) error { return errors.New("boom") },
+			expectedRuns: 1,
+			expectedRel:  1,
+		},
+		{
+			name:         "releases after panic",
+			leaser:       &fakeLeaser{grant: true},
+			run:          func(context.Context) error { panic("boom") },
+			expectedRuns: 1,
+			expectedRel:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs atomic.Int32
+			scheduler := jobs.NewScheduler(tt.leaser, time.Second)
+			scheduler.RunNow(context.Background(), jobs.ScheduledJob{
+				Name:     "test",
+				Interval: time.Minute,
+				Run: func(ctx context.Context) error {
+					runs.Add(1)
+					if tt.run != nil {
+						return tt.run(ctx)
+					}
+					return nil
+				},
+			})
+
+			assert.Equal(t, tt.expectedRuns, runs.Load())
+			assert.Equal(t, tt.expectedRel, tt.leaser.released.Load())
+		})
+	}
+}
+
+func TestScheduler_RunsOnIntervalUntilCancelled(t *testing.T) {
+	scheduler := jobs.NewScheduler(&fakeLeaser{grant: true}, time.Second)
+
+	var runs, disabledRuns atomic.Int32
+	scheduler.Register(jobs.ScheduledJob{
+		Name:     "ticking",
+		Interval: 10 * time.Millisecond,
+		Run: func(context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+	scheduler.Register(jobs.ScheduledJob{
+		Name:     "disabled",
+		Interval: 0,
+		Run: func(context.Context) error {
+			disabledRuns.Add(1)
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+	require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+	scheduler.Wait()
+
+	assert.Zero(t, disabledRuns.Load())
+}
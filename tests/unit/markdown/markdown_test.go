@@ -0,0 +1,60 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/markdown"
+)
+
+func TestRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "emphasis and code",
+			source:   "**Great** film, *truly*. Watch `director's cut`.",
+			expected: "<p><strong>Great</strong> film, <em>truly</em>. Watch <code>director&#39;s cut</code>.</p>\n",
+		},
+		{
+			name:     "lists and strikethrough",
+			source:   "- ~~boring~~\n- fun",
+			expected: "<ul>\n<li><del>boring</del></li>\n<li>fun</li>\n</ul>\n",
+		},
+		{
+			name:     "links open safely",
+			source:   "[trailer](https://example.com/trailer)",
+			expected: `<p><a href="https://example.com/trailer" rel="nofollow noopener" target="_blank">trailer</a></p>` + "\n",
+		},
+		{
+			name:     "script tags are dropped, leaving inert text",
+			source:   "Nice<script>alert(1)</script>",
+			expected: "<p>Nicealert(1)</p>\n",
+		},
+		{
+			name:     "javascript links are dropped",
+			source:   "[click](javascript:alert(1))",
+			expected: "<p>click</p>\n",
+		},
+		{
+			name:     "event handlers are dropped",
+			source:   `<img src=x onerror="alert(1)">`,
+			expected: "\n",
+		},
+		{
+			name:     "headings and images are reduced to text",
+			source:   "# Loud\n\n![poster](https://example.com/p.png)",
+			expected: "Loud\n<p></p>\n",
+		},
+	}
+
+	renderer := markdown.NewRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, renderer.Render(tt.source))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+// captureLogs redirects the default logger to a buffer for the test duration.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestAccessLog_LogsRequest(t *testing.T) {
+	logs := captureLogs(t)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	})
+	handler := middleware.RequestID(middleware.AccessLog(true)(next))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/films/1/comments", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "HTTP request", entry["msg"])
+	assert.Equal(t, http.MethodPost, entry["method"])
+	assert.Equal(t, "/api/v1/films/1/comments", entry["path"])
+	assert.InDelta(t, http.StatusCreated, entry["status"], 0)
+	assert.InDelta(t, 5, entry["bytes"], 0)
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Equal(t, "203.0.113.7", entry["client_ip"])
+	assert.Contains(t, entry, "latency")
+	assert.Equal(t, "req-123", w.Header().Get(middleware.RequestIDHeader))
+}
+
+func TestAccessLog_SkipsHealthChecks(t *testing.T) {
+	tests := []struct {
+		name             string
+		skipHealthChecks bool
+		expectLog        bool
+	}{
+		{name: "skipped when enabled", skipHealthChecks: true, expectLog: false},
+		{name: "logged when disabled", skipHealthChecks: false, expectLog: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLogs(t)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			handler := middleware.AccessLog(tt.skipHealthChecks)(next)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			assert.Equal(t, tt.expectLog, logs.Len() > 0)
+		})
+	}
+}
+
+func TestRequestID_GeneratesWhenMissing(t *testing.T) {
+	var seen string
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = middleware.RequestIDFromContext(r.Context())
+	})
+
+	w := httptest.NewRecorder()
+	middleware.RequestID(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.NotEmpty(t, seen)
+	assert.Equal(t, seen, w.Header().Get(middleware.RequestIDHeader))
+}
@@ -0,0 +1,43 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+func TestRequireAdminToken(t *testing.T) {
+	tests := []struct {
+		name               string
+		authorization      string
+		expectedStatusCode int
+	}{
+		{name: "valid token", authorization: "Bearer secret", expectedStatusCode: http.StatusNoContent},
+		{name: "wrong token", authorization: "Bearer nope", expectedStatusCode: http.StatusUnauthorized},
+		{name: "missing token", expectedStatusCode: http.StatusUnauthorized},
+		{name: "missing bearer scheme", authorization: "secret", expectedStatusCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+			handler := middleware.RequireAdminToken("secret")(next)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/purge", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
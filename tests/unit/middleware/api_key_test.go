@@ -0,0 +1,139 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/metrics"
+	"github.com/rxbenefits/go-hw/internal/middleware"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+// fakeAPIKeyVerifier accepts the keys in keys and enforces quota per key.
+type fakeAPIKeyVerifier struct {
+	keys  map[string]*models.APIKey
+	quota int64
+	used  map[int]int64
+}
+
+func (v *fakeAPIKeyVerifier) Authenticate(_ context.Context, rawKey string) (*models.APIKey, error) {
+	key, ok := v.keys[rawKey]
+	if !ok {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+func (v *fakeAPIKeyVerifier) RecordRequest(_ context.Context, key *models.APIKey) (*models.APIKeyUsage, bool, error) {
+	resetsAt := time.Now().Add(time.Hour)
+	if v.quota > 0 && v.used[key.ID] >= v.quota {
+		return &models.APIKeyUsage{Used: v.used[key.ID], ResetsAt: resetsAt}, false, nil
+	}
+	v.used[key.ID]++
+	return &models.APIKeyUsage{Used: v.used[key.ID], ResetsAt: resetsAt}, true, nil
+}
+
+func newFakeAPIKeyVerifier(quota int64) *fakeAPIKeyVerifier {
+	return &fakeAPIKeyVerifier{
+		keys: map[string]*models.APIKey{
+			"mbk_free":     {ID: 1, Tier: models.APIKeyTierFree},
+			"mbk_internal": {ID: 2, Tier: models.APIKeyTierInternal},
+		},
+		quota: quota,
+		used:  map[int]int64{},
+	}
+}
+
+func serveWithAPIKey(handler http.Handler, rawKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+	if rawKey != "" {
+		req.Header.Set(auth.APIKeyHeader, rawKey)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		rawKey         string
+		expectedStatus int
+		expectedKeyID  int
+	}{
+		{name: "anonymous", expectedStatus: http.StatusOK},
+		{name: "valid key", rawKey: "mbk_free", expectedStatus: http.StatusOK, expectedKeyID: 1},
+		{name: "unknown key", rawKey: "mbk_nope", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keyID int
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if key, ok := auth.APIKeyFromContext(r.Context()); ok {
+					keyID = key.ID
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+			handler := middleware.APIKeyAuth(newFakeAPIKeyVerifier(0), middleware.NewRateLimiter())(next)
+
+			w := serveWithAPIKey(handler, tt.rawKey)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedKeyID, keyID)
+		})
+	}
+}
+
+func TestAPIKeyAuth_RateLimit(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	verifier := newFakeAPIKeyVerifier(0)
+	handler := middleware.APIKeyAuth(verifier, middleware.NewRateLimiter())(next)
+	limits, _ := models.TierLimits(models.APIKeyTierFree)
+	rejected := metrics.RateLimitRejections.WithLabelValues(models.APIKeyTierFree, "rate")
+	before := testutil.ToFloat64(rejected)
+
+	for range limits.RequestsPerMinute {
+		require.Equal(t, http.StatusOK, serveWithAPIKey(handler, "mbk_free").Code)
+	}
+	w := serveWithAPIKey(handler, "mbk_free")
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, strconv.Itoa(limits.RequestsPerMinute), w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, int64(limits.RequestsPerMinute), verifier.used[1], "rejected requests use no quota")
+	assert.InDelta(t, before+1, testutil.ToFloat64(rejected), 0)
+
+	// Tiers without a rate limit are unaffected.
+	for range limits.RequestsPerMinute + 1 {
+		require.Equal(t, http.StatusOK, serveWithAPIKey(handler, "mbk_internal").Code)
+	}
+}
+
+func TestAPIKeyAuth_MonthlyQuota(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := middleware.APIKeyAuth(newFakeAPIKeyVerifier(2), middleware.NewRateLimiter())(next)
+	rejected := metrics.RateLimitRejections.WithLabelValues(models.APIKeyTierFree, "quota")
+	before := testutil.ToFloat64(rejected)
+
+	require.Equal(t, http.StatusOK, serveWithAPIKey(handler, "mbk_free").Code)
+	require.Equal(t, http.StatusOK, serveWithAPIKey(handler, "mbk_free").Code)
+	w := serveWithAPIKey(handler, "mbk_free")
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "Monthly quota exceeded")
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour.Seconds(), retryAfter, 5)
+	assert.InDelta(t, before+1, testutil.ToFloat64(rejected), 0)
+}
@@ -0,0 +1,104 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+const catalogPolicy = "public, max-age=60"
+
+func TestCacheControl(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		handler  http.HandlerFunc
+		expected string
+	}{
+		{
+			name:   "successful read",
+			method: http.MethodGet,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"items":[]}`))
+			},
+			expected: catalogPolicy,
+		},
+		{
+			name:     "head",
+			method:   http.MethodHead,
+			handler:  func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) },
+			expected: catalogPolicy,
+		},
+		{
+			name:     "not modified",
+			method:   http.MethodGet,
+			handler:  func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotModified) },
+			expected: catalogPolicy,
+		},
+		{
+			name:     "empty response",
+			method:   http.MethodGet,
+			handler:  func(http.ResponseWriter, *http.Request) {},
+			expected: catalogPolicy,
+		},
+		{
+			name:     "error",
+			method:   http.MethodGet,
+			handler:  func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			expected: "no-store",
+		},
+		{
+			name:     "write",
+			method:   http.MethodPost,
+			handler:  func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusCreated) },
+			expected: "no-store",
+		},
+		{
+			name:   "handler's own policy",
+			method: http.MethodGet,
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Cache-Control", "private, max-age=300")
+				w.WriteHeader(http.StatusOK)
+			},
+			expected: "private, max-age=300",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			middleware.CacheControl(catalogPolicy)(tt.handler).ServeHTTP(w, httptest.NewRequest(tt.method, "/api/v1/films", nil))
+
+			assert.Equal(t, tt.expected, w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestCacheControl_EmptyPolicy(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	middleware.CacheControl("")(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func TestCacheControl_ThroughTimeout(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Add("Vary", middleware.StoreIDHeader)
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	})
+	handler := middleware.Timeout(time.Second)(middleware.CacheControl(catalogPolicy)(next))
+
+	w := httptest.NewRecorder()
+	w.Header().Add("Vary", "Accept-Encoding")
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+
+	assert.Equal(t, catalogPolicy, w.Header().Get("Cache-Control"))
+	assert.Equal(t, []string{"Accept-Encoding", middleware.StoreIDHeader}, w.Header().Values("Vary"))
+}
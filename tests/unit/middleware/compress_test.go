@@ -0,0 +1,225 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+var largeBody = strings.Repeat(`{"title":"ACADEMY DINOSAUR","rating":"PG"}`, 100)
+
+func compressionOptions() middleware.CompressionOptions {
+	return middleware.CompressionOptions{
+		Encodings:   []string{"br", "gzip"},
+		GzipLevel:   5,
+		BrotliLevel: 4,
+		MinSize:     1024,
+		SkipTypes:   []string{"image/", "application/zip"},
+	}
+}
+
+// serveCompressed runs handler behind Compress for a request with the given
+// Accept-Encoding.
+func serveCompressed(
+	t *testing.T,
+	opts middleware.CompressionOptions,
+	acceptEncoding string,
+	handler http.HandlerFunc,
+) *httptest.ResponseRecorder {
+	t.Helper()
+	require.NoError(t, opts.Validate())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rr := httptest.NewRecorder()
+	middleware.Compress(opts)(handler).ServeHTTP(rr, req)
+	return rr
+}
+
+func writeBody(contentType, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Header().Set("ETag", `"abc"`)
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+// decode reads rr's body in its Content-Encoding.
+func decode(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	var reader io.Reader = rr.Body
+	switch rr.Header().Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(rr.Body)
+		require.NoError(t, err)
+		reader = gz
+	case "br":
+		reader = brotli.NewReader(rr.Body)
+	}
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestCompress_Negotiation(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		expected       string
+	}{
+		{name: "no header", acceptEncoding: "", expected: ""},
+		{name: "gzip only", acceptEncoding: "gzip", expected: "gzip"},
+		{name: "ties go to the server's order", acceptEncoding: "gzip, deflate, br", expected: "br"},
+		{name: "quality values", acceptEncoding: "br;q=0.5, gzip;q=0.8", expected: "gzip"},
+		{name: "wildcard", acceptEncoding: "*", expected: "br"},
+		{name: "wildcard with exclusion", acceptEncoding: "*, br;q=0", expected: "gzip"},
+		{name: "all refused", acceptEncoding: "br;q=0, gzip;q=0", expected: ""},
+		{name: "identity preferred", acceptEncoding: "gzip;q=0.5, identity", expected: ""},
+		{name: "unsupported only", acceptEncoding: "deflate, zstd", expected: ""},
+		{name: "malformed quality", acceptEncoding: "br;q=high, gzip", expected: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveCompressed(t, compressionOptions(), tt.acceptEncoding, writeBody("application/json", largeBody))
+
+			assert.Equal(t, tt.expected, rr.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+			assert.Equal(t, largeBody, decode(t, rr))
+		})
+	}
+}
+
+func TestCompress_CompressedHeaders(t *testing.T) {
+	rr := serveCompressed(t, compressionOptions(), "gzip", writeBody("application/json", largeBody))
+
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Empty(t, rr.Header().Get("Content-Length"))
+	assert.Equal(t, `W/"abc"`, rr.Header().Get("ETag"))
+	assert.Less(t, rr.Body.Len(), len(largeBody))
+}
+
+func TestCompress_ConfiguredEncodings(t *testing.T) {
+	opts := compressionOptions()
+	opts.Encodings = []string{"gzip"}
+
+	rr := serveCompressed(t, opts, "br, gzip", writeBody("application/json", largeBody))
+
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+}
+
+func TestCompress_SentAsIs(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{name: "below minimum size", handler: writeBody("application/json", `{"ok":true}`)},
+		{name: "skipped subtype", handler: writeBody("image/png", largeBody)},
+		{name: "skipped type", handler: writeBody("application/zip; name=export", largeBody)},
+		{
+			name: "already encoded",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Encoding", "gzip")
+				_, _ = io.WriteString(w, largeBody)
+			},
+		},
+		{
+			name: "no body",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := httptest.NewRecorder()
+			tt.handler(expected, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			rr := serveCompressed(t, compressionOptions(), "br, gzip", tt.handler)
+
+			assert.Equal(t, expected.Header().Get("Content-Encoding"), rr.Header().Get("Content-Encoding"))
+			assert.Equal(t, expected.Header().Get("Content-Length"), rr.Header().Get("Content-Length"))
+			assert.Equal(t, expected.Header().Get("ETag"), rr.Header().Get("ETag"))
+			assert.Equal(t, expected.Code, rr.Code)
+			assert.Equal(t, expected.Body.String(), rr.Body.String())
+		})
+	}
+}
+
+func TestCompress_NotModifiedWeakensETag(t *testing.T) {
+	rr := serveCompressed(t, compressionOptions(), "gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		w.WriteHeader(http.StatusNotModified)
+	})
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, `W/"abc"`, rr.Header().Get("ETag"))
+}
+
+func TestCompress_FlushStreamsCompressed(t *testing.T) {
+	rr := serveCompressed(t, compressionOptions(), "gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for range 3 {
+			_, _ = io.WriteString(w, "{\"film_id\":1}\n")
+			require.NoError(t, http.NewResponseController(w).Flush())
+		}
+	})
+
+	assert.True(t, rr.Flushed)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, strings.Repeat("{\"film_id\":1}\n", 3), decode(t, rr))
+}
+
+func TestCompress_SniffsContentType(t *testing.T) {
+	rr := serveCompressed(t, compressionOptions(), "br", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "<html><body>"+largeBody+"</body></html>")
+	})
+
+	assert.Equal(t, "br", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+}
+
+func TestCompress_Disabled(t *testing.T) {
+	opts := compressionOptions()
+	opts.Encodings = nil
+
+	rr := serveCompressed(t, opts, "br, gzip", writeBody("application/json", largeBody))
+
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Empty(t, rr.Header().Get("Vary"))
+	assert.Equal(t, largeBody, rr.Body.String())
+}
+
+func TestCompressionOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*middleware.CompressionOptions)
+	}{
+		{name: "unknown encoding", modify: func(o *middleware.CompressionOptions) { o.Encodings = []string{"zstd"} }},
+		{name: "gzip level", modify: func(o *middleware.CompressionOptions) { o.GzipLevel = 10 }},
+		{name: "brotli level", modify: func(o *middleware.CompressionOptions) { o.BrotliLevel = 12 }},
+		{name: "minimum size", modify: func(o *middleware.CompressionOptions) { o.MinSize = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := compressionOptions()
+			tt.modify(&opts)
+			assert.Error(t, opts.Validate())
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+func TestAllowNetworks(t *testing.T) {
+	allowed, err := middleware.ParseNetworks([]string{"10.0.0.0/8", "203.0.113.7"})
+	require.NoError(t, err)
+	trustedProxies, err := middleware.ParseNetworks([]string{"192.168.0.0/16"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		remoteAddr         string
+		forwardedFor       string
+		expectedStatusCode int
+	}{
+		{name: "allowed range", remoteAddr: "10.1.2.3:5000", expectedStatusCode: http.StatusNoContent},
+		{name: "allowed address", remoteAddr: "203.0.113.7:5000", expectedStatusCode: http.StatusNoContent},
+		{name: "outside the allowlist", remoteAddr: "198.51.100.1:5000", expectedStatusCode: http.StatusForbidden},
+		{name: "forwarded by a trusted proxy", remoteAddr: "192.168.1.1:5000", forwardedFor: "10.1.2.3",
+			expectedStatusCode: http.StatusNoContent},
+		{name: "forged entry before the proxy's", remoteAddr: "192.168.1.1:5000",
+			forwardedFor: "10.1.2.3, 198.51.100.1", expectedStatusCode: http.StatusForbidden},
+		{name: "forwarded by an untrusted peer", remoteAddr: "198.51.100.1:5000", forwardedFor: "10.1.2.3",
+			expectedStatusCode: http.StatusForbidden},
+		{name: "IPv4-mapped address", remoteAddr: "[::ffff:10.1.2.3]:5000", expectedStatusCode: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+			handler := middleware.AllowNetworks(allowed, trustedProxies)(next)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/dashboard", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestAllowNetworksWithoutAllowlist(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := middleware.AllowNetworks(nil, nil)(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/dashboard", nil)
+	req.RemoteAddr = "198.51.100.1:5000"
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestClientAddr(t *testing.T) {
+	trustedProxies, err := middleware.ParseNetworks([]string{"192.168.0.0/16"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		trustProxies bool
+		remoteAddr   string
+		forwardedFor string
+		expectedAddr string
+	}{
+		{name: "forwarded by a trusted proxy", trustProxies: true, remoteAddr: "192.168.1.1:5000",
+			forwardedFor: "203.0.113.7", expectedAddr: "203.0.113.7"},
+		{name: "forwarded by an untrusted peer", trustProxies: true, remoteAddr: "198.51.100.1:5000",
+			forwardedFor: "203.0.113.7", expectedAddr: "198.51.100.1"},
+		{name: "without TrustedClient", remoteAddr: "192.168.1.1:5000",
+			forwardedFor: "203.0.113.7", expectedAddr: "192.168.1.1"},
+		{name: "unparseable peer", remoteAddr: "pipe", expectedAddr: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var clientAddr string
+			var handler http.Handler = http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				clientAddr = middleware.ClientAddr(r)
+			})
+			if tt.trustProxies {
+				handler = middleware.TrustedClient(trustedProxies)(handler)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expectedAddr, clientAddr)
+		})
+	}
+}
+
+func TestParseNetworksRejectsInvalidRange(t *testing.T) {
+	_, err := middleware.ParseNetworks([]string{"10.0.0.0/33"})
+
+	assert.Error(t, err)
+}
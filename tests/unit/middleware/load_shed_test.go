@@ -0,0 +1,90 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+// blockingHandler holds each request until release is closed, signalling
+// started once it begins serving.
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestLimitConcurrency_ShedsWhenFull(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	handler := middleware.LimitConcurrency(1, 10*time.Millisecond)(blockingHandler(started, release))
+
+	done := make(chan int)
+	go func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+		done <- w.Code
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "Server overloaded")
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+}
+
+func TestLimitConcurrency_QueuesBriefly(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	handler := middleware.LimitConcurrency(1, time.Second)(blockingHandler(started, release))
+
+	done := make(chan int, 2)
+	serve := func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+		done <- w.Code
+	}
+	go serve()
+	<-started
+	go serve()
+
+	// The second request waits for the first to finish instead of being shed.
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+	assert.Equal(t, http.StatusOK, <-done)
+}
+
+func TestLimitConcurrency_HealthChecksBypassLimit(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+	blocking := blockingHandler(started, release)
+	handler := middleware.LimitConcurrency(1, 10*time.Millisecond)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/healthz" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			blocking.ServeHTTP(w, r)
+		}))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+	<-started
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
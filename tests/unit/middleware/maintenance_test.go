@@ -0,0 +1,55 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        bool
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "disabled allows writes", method: http.MethodPost, path: "/api/v1/films/1/comments",
+			expectedStatus: http.StatusOK},
+		{name: "enabled allows reads", enabled: true, method: http.MethodGet, path: "/api/v1/films",
+			expectedStatus: http.StatusOK},
+		{name: "enabled rejects writes", enabled: true, method: http.MethodPost, path: "/api/v1/films/1/comments",
+			expectedStatus: http.StatusServiceUnavailable},
+		{name: "enabled allows exempt writes", enabled: true, method: http.MethodPut, path: "/api/v1/admin/maintenance",
+			expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode := middleware.NewMaintenanceMode(tt.enabled)
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			handler := middleware.ReadOnly(mode, "/api/v1/admin")(next)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestMaintenanceMode_Toggle(t *testing.T) {
+	mode := middleware.NewMaintenanceMode(false)
+
+	mode.SetEnabled(true)
+	assert.True(t, mode.Enabled())
+
+	mode.SetEnabled(false)
+	assert.False(t, mode.Enabled())
+}
@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/metrics"
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+func TestCountResponses(t *testing.T) {
+	before2xx := testutil.ToFloat64(metrics.HTTPResponses.WithLabelValues("2xx"))
+	before5xx := testutil.ToFloat64(metrics.HTTPResponses.WithLabelValues("5xx"))
+
+	ok := middleware.CountResponses(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	failing := middleware.CountResponses(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	ok.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	failing.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.InDelta(t, before2xx+1, testutil.ToFloat64(metrics.HTTPResponses.WithLabelValues("2xx")), 0)
+	assert.InDelta(t, before5xx+1, testutil.ToFloat64(metrics.HTTPResponses.WithLabelValues("5xx")), 0)
+	assert.Greater(t, metrics.HTTPErrorRate(), 0.0)
+}
+
+func TestStatusClass(t *testing.T) {
+	assert.Equal(t, "2xx", metrics.StatusClass(http.StatusNoContent))
+	assert.Equal(t, "4xx", metrics.StatusClass(http.StatusNotFound))
+	assert.Equal(t, "5xx", metrics.StatusClass(http.StatusServiceUnavailable))
+	assert.Equal(t, "5xx", metrics.StatusClass(0))
+}
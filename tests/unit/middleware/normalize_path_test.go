@@ -0,0 +1,53 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+	"github.com/rxbenefits/go-hw/internal/router"
+)
+
+func TestNormalizePath(t *testing.T) {
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path + " " + r.PathValue("id")))
+	}
+	r := router.New()
+	api := r.Group("/api/v1")
+	api.HandleFunc("GET /films", echo)
+	api.HandleFunc("POST /films/{id}/comments", echo)
+	r.HandleFunc("/swagger/", echo)
+	handler := middleware.NormalizePath(r)(r)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"clean path", http.MethodGet, "/api/v1/films", http.StatusOK, "GET /api/v1/films "},
+		{"trailing slash", http.MethodGet, "/api/v1/films/", http.StatusOK, "GET /api/v1/films "},
+		{"repeated slashes", http.MethodGet, "/api//v1/films", http.StatusOK, "GET /api/v1/films "},
+		{"write keeps method", http.MethodPost, "/api/v1/films/7/comments/", http.StatusOK,
+			"POST /api/v1/films/7/comments 7"},
+		{"prefix route keeps slash", http.MethodGet, "/swagger/", http.StatusOK, "GET /swagger/ "},
+		{"unknown path", http.MethodGet, "/api/v1/nope/", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader("{}")))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
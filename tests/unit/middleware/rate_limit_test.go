@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	limiter := middleware.NewRateLimiter(middleware.WithRateLimiterClock(func() time.Time { return now }))
+
+	allowed, remaining, resetsAt := limiter.Allow("guest:1", 2)
+	assert.True(t, allowed)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, now.Add(time.Minute), resetsAt)
+
+	allowed, _, _ = limiter.Allow("guest:1", 2)
+	assert.True(t, allowed)
+	allowed, remaining, _ = limiter.Allow("guest:1", 2)
+	assert.False(t, allowed)
+	assert.Equal(t, 0, remaining)
+
+	now = now.Add(time.Minute)
+	allowed, _, _ = limiter.Allow("guest:1", 2)
+	assert.True(t, allowed)
+}
+
+func TestRateLimiter_DropsEndedWindows(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	limiter := middleware.NewRateLimiter(middleware.WithRateLimiterClock(func() time.Time { return now }))
+
+	for i := range 100 {
+		limiter.Allow("guest:"+strconv.Itoa(i), 10)
+	}
+	assert.Equal(t, 100, limiter.Len())
+
+	now = now.Add(time.Minute)
+	limiter.Allow("guest:new", 10)
+
+	assert.Equal(t, 1, limiter.Len())
+}
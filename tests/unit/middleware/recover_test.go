@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+	"github.com/rxbenefits/go-hw/internal/models"
+)
+
+type recordingReporter struct {
+	errors []error
+	panics []any
+}
+
+func (r *recordingReporter) CaptureError(_ *http.Request, _ string, err error) {
+	r.errors = append(r.errors, err)
+}
+
+func (r *recordingReporter) CapturePanic(_ *http.Request, _ string, recovered any) {
+	r.panics = append(r.panics, recovered)
+}
+
+func (r *recordingReporter) Flush(time.Duration) {}
+
+func TestReportErrors_RecoversPanics(t *testing.T) {
+	reporter := &recordingReporter{}
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	handler := middleware.ReportErrors(reporter)(next)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var response models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Internal server error", response.Error)
+	assert.Equal(t, []any{"boom"}, reporter.panics)
+}
+
+func TestReportErrors_CapturesRecordedServerErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		expectCapture bool
+	}{
+		{name: "server error", status: http.StatusInternalServerError, expectCapture: true},
+		{name: "client error", status: http.StatusNotFound, expectCapture: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := &recordingReporter{}
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if recorder, ok := w.(interface{ RecordError(error) }); ok {
+					recorder.RecordError(errors.New("database error"))
+				}
+				w.WriteHeader(tt.status)
+			})
+			handler := middleware.ReportErrors(reporter)(next)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.expectCapture, len(reporter.errors) == 1)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+func TestSignedURLOr(t *testing.T) {
+	signer := auth.NewURLSigner("secret", time.Hour, "")
+	signed, _, err := signer.Sign("/api/v1/rentals/42/receipt")
+	require.NoError(t, err)
+	customerTokens := auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour)
+	token, _, err := customerTokens.Issue(600)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		target         string
+		token          string
+		expectedStatus int
+	}{
+		{name: "signed URL", target: signed, expectedStatus: http.StatusOK},
+		{name: "bearer token", target: "/api/v1/rentals/42/receipt", token: token, expectedStatus: http.StatusOK},
+		{name: "neither", target: "/api/v1/rentals/42/receipt", expectedStatus: http.StatusUnauthorized},
+		{name: "bad signature", target: "/api/v1/rentals/42/receipt?expires=99999999999&signature=forged",
+			token: token, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			middleware.SignedURLOr(signer, middleware.RequireToken(customerTokens))(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestSignedURLOrWithoutSigner(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/rentals/42/receipt?expires=1&signature=x", nil)
+	w := httptest.NewRecorder()
+	customerTokens := auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour)
+	middleware.SignedURLOr(nil, middleware.RequireToken(customerTokens))(next).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
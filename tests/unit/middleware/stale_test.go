@@ -0,0 +1,116 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+func TestServeStale(t *testing.T) {
+	status := http.StatusOK
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(`{"total":1}`))
+		} else {
+			_, _ = w.Write([]byte(`{"error":"unavailable"}`))
+		}
+	})
+	handler := middleware.ServeStale(cache.NewMemoryCache(time.Hour))(next)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films?page=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(middleware.StaleHeader))
+
+	status = http.StatusServiceUnavailable
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films?page=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get(middleware.StaleHeader))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"total":1}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films?page=2", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Empty(t, w.Header().Get(middleware.StaleHeader))
+}
+
+func TestServeStale_ExpiredCopy(t *testing.T) {
+	status := http.StatusOK
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	})
+	handler := middleware.ServeStale(cache.NewMemoryCache(time.Nanosecond))(next)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+	time.Sleep(time.Millisecond)
+	status = http.StatusServiceUnavailable
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Empty(t, w.Header().Get(middleware.StaleHeader))
+}
+
+func TestServeStale_IgnoresWrites(t *testing.T) {
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	handler := middleware.ServeStale(cache.NewMemoryCache(time.Hour))(next)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/films/1/comments", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestServeStale_SkipsCallerCredentials(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		header string
+		value  string
+	}{
+		{name: "bearer token", target: "/api/v1/feed", header: "Authorization", value: "Bearer token"},
+		{name: "API key", target: "/api/v1/usage", header: "X-API-Key", value: "mb_key"},
+		{name: "signed URL", target: "/api/v1/customers/1/export?expires=1700000000&signature=abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := http.StatusOK
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(status)
+			})
+			handler := middleware.ServeStale(cache.NewMemoryCache(time.Hour))(next)
+			request := func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+				if tt.header != "" {
+					req.Header.Set(tt.header, tt.value)
+				}
+				return req
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), request())
+			status = http.StatusServiceUnavailable
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, request())
+
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			assert.Empty(t, w.Header().Get(middleware.StaleHeader))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+func TestTimeout_PassesThroughFastResponses(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Test", "yes")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+
+	w := httptest.NewRecorder()
+	middleware.Timeout(time.Second)(next).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/films", nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "yes", w.Header().Get("X-Test"))
+	assert.JSONEq(t, `{"id":1}`, w.Body.String())
+}
+
+func TestTimeout_StuckHandler(t *testing.T) {
+	release := make(chan struct{})
+	writeErr := make(chan error, 1)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		<-release
+		_, err := w.Write([]byte("late"))
+		writeErr <- err
+	})
+
+	w := httptest.NewRecorder()
+	middleware.Timeout(10*time.Millisecond)(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "Request timed out")
+
+	close(release)
+	require.ErrorIs(t, <-writeErr, http.ErrHandlerTimeout)
+	assert.NotContains(t, w.Body.String(), "late")
+}
+
+func TestTimeout_PropagatesPanics(t *testing.T) {
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(errors.New("boom"))
+	})
+	handler := middleware.Timeout(time.Second)(next)
+
+	assert.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/films", nil))
+	})
+}
+
+func TestTimeout_RequestedTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxTimeout time.Duration
+		header     string
+		value      string
+		expected   time.Duration
+	}{
+		{
+			name: "shorter than the server's", maxTimeout: time.Minute,
+			header: middleware.RequestTimeoutHeader, value: "2s", expected: 2 * time.Second,
+		},
+		{
+			name: "grpc-timeout", maxTimeout: time.Minute,
+			header: middleware.GRPCTimeoutHeader, value: "1500m", expected: 1500 * time.Millisecond,
+		},
+		{
+			name: "capped at the server's", maxTimeout: 3 * time.Second,
+			header: middleware.GRPCTimeoutHeader, value: "10M", expected: 3 * time.Second,
+		},
+		{
+			name: "server limit disabled", maxTimeout: 0,
+			header: middleware.RequestTimeoutHeader, value: "250ms", expected: 250 * time.Millisecond,
+		},
+		{name: "no header", maxTimeout: time.Minute, expected: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var remaining time.Duration
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				deadline, ok := r.Context().Deadline()
+				require.True(t, ok)
+				remaining = time.Until(deadline)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			middleware.Timeout(tt.maxTimeout)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.InDelta(t, tt.expected, remaining, float64(100*time.Millisecond))
+		})
+	}
+}
+
+func TestTimeout_RequestedTimeoutExpires(t *testing.T) {
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+	req.Header.Set(middleware.GRPCTimeoutHeader, "10m")
+	w := httptest.NewRecorder()
+	middleware.Timeout(time.Minute)(next).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), "10ms")
+}
+
+func TestTimeout_InvalidRequestedTimeout(t *testing.T) {
+	tests := []struct{ header, value string }{
+		{middleware.RequestTimeoutHeader, "soon"},
+		{middleware.RequestTimeoutHeader, "-1s"},
+		{middleware.GRPCTimeoutHeader, "0S"},
+		{middleware.GRPCTimeoutHeader, "123456789S"},
+		{middleware.GRPCTimeoutHeader, "10x"},
+		{middleware.GRPCTimeoutHeader, "1.5S"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header+" "+tt.value, func(t *testing.T) {
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				t.Error("handler should not run")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+			req.Header.Set(tt.header, tt.value)
+			w := httptest.NewRecorder()
+			middleware.Timeout(time.Minute)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/middleware"
+)
+
+func TestRequireToken(t *testing.T) {
+	issuer := auth.NewTokenIssuer(auth.RoleStaff, "secret", time.Hour)
+	token, _, err := issuer.Issue(5)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "valid token", authorization: "Bearer " + token, expectedStatus: http.StatusOK},
+		{name: "missing token", expectedStatus: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer nope", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subject int
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims, ok := auth.ClaimsFromContext(r.Context())
+				require.True(t, ok)
+				subject = claims.Subject
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/staff", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			middleware.RequireToken(issuer)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, 5, subject)
+			}
+		})
+	}
+}
+
+func TestRequireTokenAcceptsAnyIssuer(t *testing.T) {
+	customers := auth.NewTokenIssuer(auth.RoleCustomer, "customer-secret", time.Hour)
+	staff := auth.NewTokenIssuer(auth.RoleStaff, "staff-secret", time.Hour)
+	staffToken, _, err := staff.Issue(5)
+	require.NoError(t, err)
+
+	var role string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := auth.ClaimsFromContext(r.Context())
+		role = claims.Role
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/customers/600/rentals", nil)
+	req.Header.Set("Authorization", "Bearer "+staffToken)
+	w := httptest.NewRecorder()
+	middleware.RequireToken(customers, staff)(next).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, auth.RoleStaff, role)
+}
+
+type stubRevocationList struct {
+	revoked map[int64]bool
+	err     error
+}
+
+func (l stubRevocationList) IsSessionRevoked(_ context.Context, sessionID int64) (bool, error) {
+	return l.revoked[sessionID], l.err
+}
+
+func TestRequireTokenRejectsRevokedSessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		sessionID      int64
+		lookupErr      error
+		expectedStatus int
+	}{
+		{name: "live session", sessionID: 1, expectedStatus: http.StatusOK},
+		{name: "revoked session", sessionID: 2, expectedStatus: http.StatusUnauthorized},
+		{name: "lookup failed", sessionID: 1, lookupErr: errors.New("boom"),
+			expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revocations := stubRevocationList{revoked: map[int64]bool{2: true}, err: tt.lookupErr}
+			issuer := auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour, auth.WithRevocationList(revocations))
+			token, _, err := issuer.IssueForSession(600, tt.sessionID, false)
+			require.NoError(t, err)
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/feed", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			middleware.RequireToken(issuer)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequireQueryToken(t *testing.T) {
+	secret := "customer-secret"
+	calendars := auth.NewTokenIssuer(auth.RoleCalendar, secret, time.Hour)
+	calendarToken, _, err := calendars.Issue(600)
+	require.NoError(t, err)
+	customerToken, _, err := auth.NewTokenIssuer(auth.RoleCustomer, secret, time.Hour).Issue(600)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "calendar token", query: "?token=" + calendarToken, expectedStatus: http.StatusOK},
+		{name: "missing token", expectedStatus: http.StatusUnauthorized},
+		{name: "customer login token", query: "?token=" + customerToken, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims, ok := auth.ClaimsFromContext(r.Context())
+				require.True(t, ok)
+				assert.Equal(t, 600, claims.Subject)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/customers/600/rentals.ics"+tt.query, nil)
+			w := httptest.NewRecorder()
+			middleware.RequireQueryToken("token", calendars)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestOptionalToken(t *testing.T) {
+	issuer := auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour)
+	token, _, err := issuer.Issue(600)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+		expectClaims   bool
+	}{
+		{name: "anonymous", expectedStatus: http.StatusOK},
+		{name: "valid token", authorization: "Bearer " + token, expectedStatus: http.StatusOK, expectClaims: true},
+		{name: "invalid token", authorization: "Bearer nope", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hasClaims bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, hasClaims = auth.ClaimsFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/films/1/comments", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			middleware.OptionalToken(issuer)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectClaims, hasClaims)
+		})
+	}
+}
+
+func TestRequireSubject(t *testing.T) {
+	tests := []struct {
+		name           string
+		claims         *auth.Claims
+		customerID     string
+		expectedStatus int
+	}{
+		{name: "own resource", claims: &auth.Claims{Subject: 600}, customerID: "600", expectedStatus: http.StatusOK},
+		{name: "other customer", claims: &auth.Claims{Subject: 600}, customerID: "601",
+			expectedStatus: http.StatusForbidden},
+		{name: "no claims", customerID: "600", expectedStatus: http.StatusForbidden},
+		{name: "exempt role", claims: &auth.Claims{Subject: 5, Role: auth.RoleStaff}, customerID: "601",
+			expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/customers/"+tt.customerID+"/notification-preferences", nil)
+			req.SetPathValue("id", tt.customerID)
+			if tt.claims != nil {
+				req = req.WithContext(auth.WithClaims(req.Context(), tt.claims))
+			}
+			w := httptest.NewRecorder()
+			middleware.RequireSubject("id", auth.RoleStaff)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestGuestRateLimit(t *testing.T) {
+	limit := middleware.GuestRateLimit(middleware.NewRateLimiter(), 2)
+	handler := limit(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(claims *auth.Claims) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/films/1/comments", nil)
+		if claims != nil {
+			req = req.WithContext(auth.WithClaims(req.Context(), claims))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	guest := &auth.Claims{Subject: 42, Role: auth.RoleGuest}
+
+	assert.Equal(t, http.StatusOK, serve(guest).Code)
+	assert.Equal(t, http.StatusOK, serve(guest).Code)
+	limited := serve(guest)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.NotEmpty(t, limited.Header().Get("Retry-After"))
+
+	// Other guests, customers, and anonymous requests are not counted.
+	assert.Equal(t, http.StatusOK, serve(&auth.Claims{Subject: 43, Role: auth.RoleGuest}).Code)
+	for range 3 {
+		assert.Equal(t, http.StatusOK, serve(&auth.Claims{Subject: 42, Role: auth.RoleCustomer}).Code)
+		assert.Equal(t, http.StatusOK, serve(nil).Code)
+	}
+}
+
+func TestRequireTwoFactor(t *testing.T) {
+	tests := []struct {
+		name           string
+		claims         *auth.Claims
+		expectedStatus int
+	}{
+		{name: "staff with two-factor", claims: &auth.Claims{Subject: 1, Role: auth.RoleStaff, TwoFactor: true},
+			expectedStatus: http.StatusOK},
+		{name: "staff without two-factor", claims: &auth.Claims{Subject: 1, Role: auth.RoleStaff},
+			expectedStatus: http.StatusForbidden},
+		{name: "other role", claims: &auth.Claims{Subject: 600, Role: auth.RoleCustomer},
+			expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/staff", nil)
+			req = req.WithContext(auth.WithClaims(req.Context(), tt.claims))
+			w := httptest.NewRecorder()
+			middleware.RequireTwoFactor(auth.RoleStaff)(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package notifications_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/jobs"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+)
+
+type recordingSender struct {
+	mu       sync.Mutex
+	messages []notifications.Message
+}
+
+func (s *recordingSender) Send(_ context.Context, msg notifications.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// inlineQueue runs each enqueued job immediately, after a JSON round trip
+// like the persistent queue's.
+type inlineQueue struct {
+	kinds   []string
+	handler jobs.Handler
+}
+
+func (q *inlineQueue) Enqueue(kind string, payload any) error {
+	q.kinds = append(q.kinds, kind)
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return q.handler(context.Background(), encoded)
+}
+
+func newInlineNotifier(sender notifications.Sender, opts ...notifications.NotifierOption) (
+	*notifications.Notifier,
+	*inlineQueue,
+) {
+	queue := &inlineQueue{}
+	notifier := notifications.NewNotifier(sender, queue, opts...)
+	queue.handler = notifier.HandleEmailJob
+	return notifier, queue
+}
+
+func TestNotifier_SendsThroughQueue(t *testing.T) {
+	sender := &recordingSender{}
+	notifier, queue := newInlineNotifier(sender)
+
+	require.NoError(t, notifier.NotifyCommentReply(notifications.CommentReply{
+		RecipientEmail: "jane@example.com",
+		RecipientName:  "Jane",
+		FilmTitle:      "Academy Dinosaur",
+		ReplierName:    "Bob",
+		Reply:          "Agreed!",
+	}))
+	require.NoError(t, notifier.NotifyRentalDue(notifications.RentalDue{
+		RecipientEmail: "jane@example.com",
+		RecipientName:  "Jane",
+		FilmTitle:      "Academy Dinosaur",
+		DueAt:          time.Date(2026, time.March, 3, 18, 0, 0, 0, time.UTC),
+	}))
+
+	assert.Equal(t, []string{notifications.EmailJobKind, notifications.EmailJobKind}, queue.kinds)
+
+	require.Len(t, sender.messages, 2)
+	assert.Equal(t, "jane@example.com", sender.messages[0].To)
+	assert.Equal(t, "New reply to your comment on Academy Dinosaur", sender.messages[0].Subject)
+	assert.Contains(t, sender.messages[0].Body, "Agreed!")
+	assert.Equal(t, "Academy Dinosaur is due back Tue Mar 3", sender.messages[1].Subject)
+}
+
+func TestNotifier_NotifyCommentMention(t *testing.T) {
+	sender := &recordingSender{}
+	notifier, queue := newInlineNotifier(sender)
+
+	require.NoError(t, notifier.NotifyCommentMention(notifications.CommentMention{
+		RecipientEmail: "jane@example.com",
+		RecipientName:  "Jane",
+		FilmTitle:      "Academy Dinosaur",
+		MentionerName:  "Bob",
+		Comment:        "@600 you would love this",
+	}))
+
+	assert.Equal(t, []string{notifications.EmailJobKind}, queue.kinds)
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, "You were mentioned in a comment on Academy Dinosaur", sender.messages[0].Subject)
+	assert.Contains(t, sender.messages[0].Body, "Bob mentioned you")
+}
+
+type optOutChecker struct {
+	event string
+}
+
+func (c optOutChecker) Allowed(_ context.Context, _ int, event, _ string) (bool, error) {
+	return event != c.event, nil
+}
+
+func TestNotifier_RespectsPreferences(t *testing.T) {
+	sender := &recordingSender{}
+	notifier, _ := newInlineNotifier(sender,
+		notifications.WithPreferences(optOutChecker{event: notifications.EventRentalDue}))
+
+	require.NoError(t, notifier.NotifyCommentReply(notifications.CommentReply{
+		RecipientID: 600, RecipientEmail: "jane@example.com", FilmTitle: "Academy Dinosaur",
+	}))
+	require.NoError(t, notifier.NotifyRentalDue(notifications.RentalDue{
+		RecipientID: 600, RecipientEmail: "jane@example.com", FilmTitle: "Academy Dinosaur",
+	}))
+
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, "New reply to your comment on Academy Dinosaur", sender.messages[0].Subject)
+}
+
+func TestSendGridSender(t *testing.T) {
+	var received map[string]any
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := notifications.NewSendGridSender("key", "from@example.com",
+		notifications.WithSendGridURL(server.URL))
+	err := sender.Send(context.Background(), notifications.Message{
+		To: "jane@example.com", Subject: "Hello", Body: "Hi",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer key", authorization)
+	assert.Equal(t, "Hello", received["subject"])
+}
+
+func TestSendGridSender_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	sender := notifications.NewSendGridSender("key", "from@example.com",
+		notifications.WithSendGridURL(server.URL))
+	err := sender.Send(context.Background(), notifications.Message{To: "jane@example.com"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+}
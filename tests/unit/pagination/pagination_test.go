@@ -0,0 +1,72 @@
+package pagination_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+)
+
+func TestLimits_Validate(t *testing.T) {
+	require.NoError(t, pagination.DefaultLimits.Validate())
+	require.NoError(t, pagination.Limits{DefaultLimit: 5, MaxLimit: 5}.Validate())
+	require.Error(t, pagination.Limits{DefaultLimit: 0, MaxLimit: 100}.Validate())
+	require.Error(t, pagination.Limits{DefaultLimit: 50, MaxLimit: 20}.Validate())
+}
+
+func TestLimits_Normalize(t *testing.T) {
+	limits := pagination.Limits{DefaultLimit: 20, MaxLimit: 100}
+
+	params := pagination.Params{}
+	limits.Normalize(&params)
+	assert.Equal(t, limits.First(), params)
+
+	params = pagination.Params{Page: 3, Limit: 5}
+	limits.Normalize(&params)
+	assert.Equal(t, pagination.Params{Page: 3, Limit: 5}, params)
+}
+
+func TestLimits_Check(t *testing.T) {
+	limits := pagination.Limits{DefaultLimit: 20, MaxLimit: 100}
+
+	require.NoError(t, limits.Check(pagination.Params{Page: 1, Limit: 100}))
+
+	for _, params := range []pagination.Params{{Page: 0, Limit: 10}, {Page: 1, Limit: 0}, {Page: 1, Limit: 101}} {
+		err := limits.Check(params)
+		require.Error(t, err)
+		assert.Equal(t, apperrors.Invalid, apperrors.KindOf(err))
+	}
+}
+
+func TestParams_Offset(t *testing.T) {
+	assert.Equal(t, 0, pagination.Params{Page: 1, Limit: 20}.Offset())
+	assert.Equal(t, 40, pagination.Params{Page: 3, Limit: 20}.Offset())
+}
+
+func TestNew(t *testing.T) {
+	page := pagination.New[string](nil, 0, pagination.Params{Page: 2, Limit: 10})
+
+	assert.Equal(t, &pagination.Paginated[string]{Items: []string{}, Page: 2, Limit: 10}, page)
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := pagination.Cursor{Time: time.Date(2024, 5, 1, 12, 0, 0, 123456000, time.UTC), ID: 42}
+
+	decoded, err := pagination.DecodeCursor(cursor.Encode())
+
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, encoded := range []string{"", "!!!", "bm9kb3Q", "YWJjLjQy", "MTIzLng"} {
+		_, err := pagination.DecodeCursor(encoded)
+
+		require.ErrorIs(t, err, pagination.ErrInvalidCursor, encoded)
+		assert.Equal(t, apperrors.Invalid, apperrors.KindOf(err))
+	}
+}
@@ -0,0 +1,187 @@
+package payments_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/payments"
+)
+
+const (
+	testWebhookSecret = "whsec_test"
+	testEvent         = `{"id": "evt_1", "type": "payment_intent.succeeded", "data": {"object": {"id": "pi_123"}}}`
+)
+
+// signature returns the v1 signature of payload signed under secret at t.
+func signature(secret string, t time.Time, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(t.Unix(), 10) + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sign returns a Stripe-Signature header value for payload signed at t.
+func sign(secret string, t time.Time, payload string) string {
+	return "t=" + strconv.FormatInt(t.Unix(), 10) + ",v1=" + signature(secret, t, payload)
+}
+
+func TestStripeProvider_CreateIntent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/payment_intents", r.URL.Path)
+		assert.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		assert.Equal(t, "checkout-7", r.Header.Get("Idempotency-Key"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "998", r.PostForm.Get("amount"))
+		assert.Equal(t, "usd", r.PostForm.Get("currency"))
+		assert.Equal(t, "7", r.PostForm.Get("metadata[checkout_id]"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "pi_123", "client_secret": "pi_123_secret_abc", ` +
+			`"status": "requires_payment_method"}`))
+	}))
+	defer server.Close()
+	provider := payments.NewStripeProvider("sk_test", testWebhookSecret, payments.WithStripeURL(server.URL))
+
+	intent, err := provider.CreateIntent(context.Background(), payments.IntentRequest{
+		Amount:         998,
+		Currency:       "usd",
+		IdempotencyKey: "checkout-7",
+		Metadata:       map[string]string{"checkout_id": "7"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, &payments.Intent{
+		ID: "pi_123", ClientSecret: "pi_123_secret_abc", Status: "requires_payment_method",
+	}, intent)
+}
+
+func TestStripeProvider_CreateIntentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"error": {"message": "card declined"}}`))
+	}))
+	defer server.Close()
+	provider := payments.NewStripeProvider("sk_test", testWebhookSecret, payments.WithStripeURL(server.URL))
+
+	_, err := provider.CreateIntent(context.Background(), payments.IntentRequest{Amount: 998, Currency: "usd"})
+
+	require.ErrorContains(t, err, "status 402")
+	require.ErrorIs(t, err, payments.ErrDeclined)
+}
+
+func TestStripeProvider_CreateRefundOutcomeUnknown(t *testing.T) {
+	for _, status := range []int{http.StatusConflict, http.StatusTooManyRequests, http.StatusBadGateway} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+			provider := payments.NewStripeProvider("sk_test", testWebhookSecret, payments.WithStripeURL(server.URL))
+
+			_, err := provider.CreateRefund(context.Background(), payments.RefundRequest{IntentID: "pi_123", Amount: 250})
+
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, payments.ErrDeclined)
+		})
+	}
+}
+
+func TestStripeProvider_CreateRefund(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/refunds", r.URL.Path)
+		assert.Equal(t, "refund-3", r.Header.Get("Idempotency-Key"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "pi_123", r.PostForm.Get("payment_intent"))
+		assert.Equal(t, "250", r.PostForm.Get("amount"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "re_123", "status": "succeeded"}`))
+	}))
+	defer server.Close()
+	provider := payments.NewStripeProvider("sk_test", testWebhookSecret, payments.WithStripeURL(server.URL))
+
+	refund, err := provider.CreateRefund(context.Background(), payments.RefundRequest{
+		IntentID:       "pi_123",
+		Amount:         250,
+		IdempotencyKey: "refund-3",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, &payments.Refund{ID: "re_123", Status: "succeeded"}, refund)
+}
+
+func TestStripeProvider_ParseWebhook(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		signature string
+		payload   string
+		wantErr   bool
+	}{
+		{name: "valid signature", signature: sign(testWebhookSecret, now, testEvent), payload: testEvent},
+		{
+			name:      "one of several signatures matches",
+			signature: sign("whsec_old", now, testEvent) + ",v1=" + signature(testWebhookSecret, now, testEvent),
+			payload:   testEvent,
+		},
+		{name: "wrong secret", signature: sign("whsec_other", now, testEvent), payload: testEvent, wantErr: true},
+		{
+			name:      "tampered payload",
+			signature: sign(testWebhookSecret, now, testEvent),
+			payload:   `{"id": "evt_1", "type": "payment_intent.succeeded", "data": {"object": {"id": "pi_999"}}}`,
+			wantErr:   true,
+		},
+		{
+			name:      "stale signature",
+			signature: sign(testWebhookSecret, now.Add(-10*time.Minute), testEvent),
+			payload:   testEvent,
+			wantErr:   true,
+		},
+		{name: "missing signature", payload: testEvent, wantErr: true},
+	}
+
+	provider := payments.NewStripeProvider("sk_test", testWebhookSecret,
+		payments.WithStripeClock(func() time.Time { return now }))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.signature != "" {
+				header.Set("Stripe-Signature", tt.signature)
+			}
+
+			event, err := provider.ParseWebhook([]byte(tt.payload), header)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, payments.ErrInvalidWebhook)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &payments.Event{ID: "evt_1", Type: payments.EventPaymentSucceeded, IntentID: "pi_123"}, event)
+		})
+	}
+}
+
+func TestFakeProvider(t *testing.T) {
+	provider := payments.FakeProvider{}
+
+	intent, err := provider.CreateIntent(context.Background(), payments.IntentRequest{Amount: 998, Currency: "usd"})
+	require.NoError(t, err)
+	assert.Regexp(t, `^pi_fake_[0-9a-f]{24}$`, intent.ID)
+
+	event, err := provider.ParseWebhook([]byte(testEvent), http.Header{})
+	require.NoError(t, err)
+	assert.Equal(t, "pi_123", event.IntentID)
+
+	_, err = provider.ParseWebhook([]byte(`{"type": "payment_intent.succeeded"}`), http.Header{})
+	require.ErrorIs(t, err, payments.ErrInvalidWebhook)
+}
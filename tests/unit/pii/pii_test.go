@@ -0,0 +1,108 @@
+package pii_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/pii"
+)
+
+func newKey(t *testing.T, fill byte) *pii.LocalKey {
+	t.Helper()
+	key, err := pii.NewLocalKey(base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(fill), 32))))
+	require.NoError(t, err)
+	return key
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c := pii.NewCipher(newKey(t, 'k'))
+
+	stored, err := c.Encrypt("Jane Smith")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored, pii.Prefix))
+	assert.NotContains(t, stored, "Jane")
+
+	plaintext, err := c.Decrypt(stored)
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Smith", plaintext)
+}
+
+func TestCipher_EncryptUsesFreshKeys(t *testing.T) {
+	c := pii.NewCipher(newKey(t, 'k'))
+
+	first, err := c.Encrypt("Jane Smith")
+	require.NoError(t, err)
+	second, err := c.Encrypt("Jane Smith")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestCipher_EmptyValuesStayEmpty(t *testing.T) {
+	stored, err := pii.NewCipher(newKey(t, 'k')).Encrypt("")
+
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestCipher_PlaintextIsReadAsIs(t *testing.T) {
+	plaintext, err := pii.NewCipher(newKey(t, 'k')).Decrypt("Jane Smith")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Smith", plaintext)
+}
+
+func TestCipher_Disabled(t *testing.T) {
+	encrypted, err := pii.NewCipher(newKey(t, 'k')).Encrypt("Jane Smith")
+	require.NoError(t, err)
+
+	c := pii.NewCipher(nil)
+	assert.False(t, c.Enabled())
+
+	stored, err := c.Encrypt("Jane Smith")
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Smith", stored)
+
+	_, err = c.Decrypt(encrypted)
+	assert.ErrorIs(t, err, pii.ErrKeyRequired)
+}
+
+func TestCipher_DisabledRejectsPrefix(t *testing.T) {
+	_, err := pii.NewCipher(nil).Encrypt(pii.Prefix + "x:y:z")
+	require.ErrorIs(t, err, pii.ErrReservedPrefix)
+
+	// With a key, such a value is encrypted like any other.
+	c := pii.NewCipher(newKey(t, 'k'))
+	stored, err := c.Encrypt(pii.Prefix + "x:y:z")
+	require.NoError(t, err)
+	plaintext, err := c.Decrypt(stored)
+	require.NoError(t, err)
+	assert.Equal(t, pii.Prefix+"x:y:z", plaintext)
+}
+
+func TestCipher_WrongKey(t *testing.T) {
+	stored, err := pii.NewCipher(newKey(t, 'k')).Encrypt("Jane Smith")
+	require.NoError(t, err)
+
+	_, err = pii.NewCipher(newKey(t, 'x')).Decrypt(stored)
+
+	assert.ErrorIs(t, err, pii.ErrUnknownKey)
+}
+
+func TestCipher_Malformed(t *testing.T) {
+	_, err := pii.NewCipher(newKey(t, 'k')).Decrypt(pii.Prefix + "garbage")
+
+	assert.ErrorIs(t, err, pii.ErrMalformed)
+}
+
+func TestNewLocalKey_Invalid(t *testing.T) {
+	_, err := pii.NewLocalKey("not base64!")
+	assert.Error(t, err)
+
+	_, err = pii.NewLocalKey(base64.StdEncoding.EncodeToString([]byte("too short")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package pricing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pricing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestQuote(t *testing.T) {
+	// 2024-05-01 is a Wednesday and 2024-05-04 a Saturday.
+	weekday := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	saturday := time.Date(2024, 5, 4, 12, 0, 0, 0, time.UTC)
+	film := models.FilmPricing{FilmID: 1, RentalRate: 4.99, CategoryIDs: []int{5, 8}}
+
+	tests := []struct {
+		name            string
+		storeID         int
+		at              time.Time
+		rules           []models.PricingRule
+		expectedRate    float64
+		expectedApplied []int
+		expectedBundles []int
+		expectedPerFilm float64
+	}{
+		{
+			name:         "no rules",
+			at:           weekday,
+			expectedRate: 4.99,
+		},
+		{
+			name: "category discount",
+			at:   weekday,
+			rules: []models.PricingRule{
+				{ID: 1, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(20), CategoryID: intPtr(5)},
+				{ID: 2, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(50), CategoryID: intPtr(6)},
+			},
+			expectedRate:    3.99,
+			expectedApplied: []int{1},
+		},
+		{
+			name: "weekend deal skipped on a weekday",
+			at:   weekday,
+			rules: []models.PricingRule{
+				{ID: 1, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(50), WeekendsOnly: true},
+			},
+			expectedRate: 4.99,
+		},
+		{
+			name: "discounts stack in rule order",
+			at:   saturday,
+			rules: []models.PricingRule{
+				{ID: 1, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(50), WeekendsOnly: true},
+				{ID: 2, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(10)},
+			},
+			expectedRate:    2.24,
+			expectedApplied: []int{1, 2},
+		},
+		{
+			name: "discounts never go below free",
+			at:   weekday,
+			rules: []models.PricingRule{
+				{ID: 1, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(100)},
+				{ID: 2, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(100)},
+			},
+			expectedApplied: []int{1, 2},
+		},
+		{
+			name:    "store rules apply only at their store",
+			storeID: 2,
+			at:      weekday,
+			rules: []models.PricingRule{
+				{ID: 1, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(10), StoreID: intPtr(1)},
+				{ID: 2, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(20), StoreID: intPtr(2)},
+			},
+			expectedRate:    3.99,
+			expectedApplied: []int{2},
+		},
+		{
+			name: "store rules need a store",
+			at:   weekday,
+			rules: []models.PricingRule{
+				{ID: 1, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(10), StoreID: intPtr(1)},
+			},
+			expectedRate: 4.99,
+		},
+		{
+			name: "rules outside their window are skipped",
+			at:   weekday,
+			rules: []models.PricingRule{
+				{ID: 1, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(10), EndsAt: timePtr(weekday)},
+				{ID: 2, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(10), StartsAt: timePtr(saturday)},
+				{ID: 3, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(20), StartsAt: timePtr(weekday)},
+			},
+			expectedRate:    3.99,
+			expectedApplied: []int{3},
+		},
+		{
+			name: "bundles are offered at the discounted rate",
+			at:   weekday,
+			rules: []models.PricingRule{
+				{ID: 1, Kind: models.PricingRuleBundle, BundleQuantity: intPtr(3), BundlePaid: intPtr(2)},
+				{ID: 2, Kind: models.PricingRulePercentOff, PercentOff: floatPtr(20)},
+			},
+			expectedRate:    3.99,
+			expectedApplied: []int{2},
+			expectedBundles: []int{1},
+			expectedPerFilm: 2.66,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price := pricing.Quote(film, tt.storeID, tt.at, tt.rules)
+
+			assert.Equal(t, 4.99, price.BaseRate)
+			assert.InDelta(t, tt.expectedRate, price.EffectiveRate, 0.001)
+			assert.Equal(t, tt.at, price.PricedAt)
+
+			applied := []int{}
+			for _, discount := range price.AppliedRules {
+				applied = append(applied, discount.RuleID)
+			}
+			assert.ElementsMatch(t, tt.expectedApplied, applied)
+
+			bundles := []int{}
+			for _, offer := range price.BundleOffers {
+				bundles = append(bundles, offer.RuleID)
+				assert.InDelta(t, tt.expectedPerFilm, offer.RatePerFilm, 0.001)
+			}
+			assert.ElementsMatch(t, tt.expectedBundles, bundles)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package ratings_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/ratings"
+)
+
+func TestParse(t *testing.T) {
+	systems, err := ratings.Parse([]string{"UK:PG-13=12A", "UK:R=15", " DE : PG-13 = FSK 12 ", "UK:R=15"})
+	require.NoError(t, err)
+
+	assert.Equal(t, ratings.Systems{
+		"UK": {"PG-13": "12A", "R": "15"},
+		"DE": {"PG-13": "FSK 12"},
+	}, systems)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []string
+		expected string
+	}{
+		{name: "no system", entries: []string{"PG-13=12A"}, expected: "want SYSTEM:RATING=LOCAL"},
+		{name: "no local rating", entries: []string{"UK:PG-13="}, expected: "want SYSTEM:RATING=LOCAL"},
+		{name: "unknown rating", entries: []string{"UK:PG13=12A"}, expected: `unknown rating "PG13"`},
+		{name: "conflict", entries: []string{"UK:R=15", "UK:R=18"}, expected: "conflicting rating mappings for UK R"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ratings.Parse(tt.entries)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestSystems_Localize(t *testing.T) {
+	systems := ratings.Systems{
+		"UK": {"PG-13": "12A", "R": "15"},
+		"DE": {"PG-13": "FSK 12"},
+	}
+
+	assert.Equal(t, map[string]string{"UK": "12A", "DE": "FSK 12"}, systems.Localize("PG-13"))
+	assert.Equal(t, map[string]string{"UK": "15"}, systems.Localize("R"))
+	assert.Nil(t, systems.Localize("G"))
+}
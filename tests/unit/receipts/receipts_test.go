@@ -0,0 +1,48 @@
+package receipts_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/receipts"
+)
+
+func TestRenderHTML(t *testing.T) {
+	rentedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	receipt := &models.Receipt{
+		RentalID:     42,
+		CustomerName: "Mary <Smith>",
+		StoreAddress: "47 MySakila Drive, Lethbridge",
+		FilmTitle:    "Academy Dinosaur",
+		RentalDate:   rentedAt,
+		DueAt:        rentedAt.AddDate(0, 0, 3),
+		Payments: []models.ReceiptPayment{
+			{PaymentID: 1, Amount: 4.99, PaidAt: rentedAt},
+			{PaymentID: 2, Amount: -1.5, PaidAt: rentedAt.AddDate(0, 0, 1)},
+		},
+		AmountPaid: 3.49,
+		IssuedAt:   rentedAt.AddDate(0, 0, 2),
+	}
+
+	var out strings.Builder
+	require.NoError(t, receipts.RenderHTML(&out, receipt))
+
+	html := out.String()
+	assert.Contains(t, html, "<title>Receipt for rental #42</title>")
+	assert.Contains(t, html, "Mary &lt;Smith&gt;")
+	assert.Contains(t, html, "Not yet returned")
+	assert.Contains(t, html, `#2 (refund)</td><td class="amount">-$1.50</td>`)
+	assert.Contains(t, html, `Total paid</td><td class="amount">$3.49</td>`)
+}
+
+func TestRenderHTMLWithoutPayments(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, receipts.RenderHTML(&out, &models.Receipt{RentalID: 1}))
+
+	assert.Contains(t, out.String(), "No payments")
+}
@@ -0,0 +1,98 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+type MockFilmRepository struct {
+	mock.Mock
+}
+
+func (m *MockFilmRepository) GetFilms(
+	_ context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
+	args := m.Called(filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.Film]), args.Error(1)
+}
+
+func (m *MockFilmRepository) GetFilmByID(_ context.Context, filmID int) (*models.Film, error) {
+	args := m.Called(filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Film), args.Error(1)
+}
+
+func (m *MockFilmRepository) GetCategories(_ context.Context) ([]models.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+
+func TestCachedFilmRepository_GetFilmByID(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	filmCache := cache.NewMemoryCache(time.Minute)
+	repo := repository.NewCachedFilmRepository(mockRepo, filmCache)
+
+	film := &models.Film{FilmID: 1, Title: "Academy Dinosaur"}
+	mockRepo.On("GetFilmByID", 1).Return(film, nil).Once()
+
+	// The second call is served from the cache.
+	for range 2 {
+		result, err := repo.GetFilmByID(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, film, result)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCachedFilmRepository_DoesNotCacheNotFound(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	repo := repository.NewCachedFilmRepository(mockRepo, cache.NewMemoryCache(time.Minute))
+
+	mockRepo.On("GetFilmByID", 999).Return(nil, repository.ErrFilmNotFound).Twice()
+
+	for range 2 {
+		_, err := repo.GetFilmByID(context.Background(), 999)
+		require.ErrorIs(t, err, repository.ErrFilmNotFound)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCachedFilmRepository_EvictedOnInvalidation(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	filmCache := cache.NewMemoryCache(time.Minute)
+	bus := cache.NewBus()
+	bus.Subscribe(cache.Evict(filmCache))
+	repo := repository.NewCachedFilmRepository(mockRepo, filmCache)
+
+	filters := models.FilmFilters{Params: pagination.Params{Page: 1, Limit: 10}}
+	listing := &pagination.Paginated[models.Film]{Items: []models.Film{{FilmID: 1}}, Total: 1, Page: 1, Limit: 10}
+	mockRepo.On("GetFilms", filters).Return(listing, nil).Twice()
+
+	_, err := repo.GetFilms(context.Background(), filters)
+	require.NoError(t, err)
+
+	bus.Publish(cache.FilmChanged(1))
+
+	_, err = repo.GetFilms(context.Background(), filters)
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
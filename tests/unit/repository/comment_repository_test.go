@@ -0,0 +1,279 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/pii"
+	"github.com/rxbenefits/go-hw/internal/repository"
+)
+
+func TestCommentRepository_SetCommentsLocked(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	lockedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery("INSERT INTO film_comment_locks").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"locked_at"}).AddRow(lockedAt))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	status, err := repo.SetCommentsLocked(context.Background(), 1, true)
+
+	require.NoError(t, err)
+	assert.True(t, status.Locked)
+	assert.Equal(t, lockedAt, *status.LockedAt)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_SetCommentsUnlocked(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectExec("DELETE FROM film_comment_locks").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	status, err := repo.SetCommentsLocked(context.Background(), 1, false)
+
+	require.NoError(t, err)
+	assert.False(t, status.Locked)
+	assert.Nil(t, status.LockedAt)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_SetCommentsLockedUnknownFilm(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(999).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	_, err = repo.SetCommentsLocked(context.Background(), 999, true)
+
+	assert.ErrorIs(t, err, repository.ErrFilmNotFound)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_GetCommentsByFilmIDUnpublishedFilm(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM film WHERE film_id = \$1 AND status = 'published'\)`).
+		WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	_, err = repo.GetCommentsByFilmID(context.Background(), 7, 0, 10, nil)
+
+	assert.ErrorIs(t, err, repository.ErrFilmNotFound)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// commentRows returns rows of the columns scanned for a comment, one per ID
+// given, each posted a minute before the last.
+func commentRows(posted time.Time, ids ...int) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{
+		"id", "film_id", "customer_name", "comment", "created_at", "customer_id", "parent_id", "name", "renter",
+		"mentions", "attachment_key", "thumbnail_key",
+	})
+	for i, id := range ids {
+		rows.AddRow(id, 1, "Guest", "Nice", posted.Add(-time.Duration(i)*time.Minute), nil, nil, nil, false, "{}",
+			"", "")
+	}
+	return rows
+}
+
+func TestCommentRepository_GetCommentsByFilmIDFirstPage(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	posted := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery("SELECT COUNT").WithArgs(1, 0).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	sqlMock.ExpectQuery("customer_shadow_bans .*\\) ORDER BY fc.created_at DESC, fc.id DESC LIMIT \\$3$").
+		WithArgs(1, 0, 3).
+		WillReturnRows(commentRows(posted, 9, 8, 7))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	page, err := repo.GetCommentsByFilmID(context.Background(), 1, 0, 2, nil)
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, 5, page.Total)
+	assert.Zero(t, page.Page)
+	cursor, err := pagination.DecodeCursor(page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, pagination.Cursor{Time: posted.Add(-time.Minute), ID: 8}, cursor)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_GetCommentsByFilmIDAfterCursor(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	after := pagination.Cursor{Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ID: 8}
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery("SELECT COUNT").WithArgs(1, 0).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	sqlMock.ExpectQuery("\\(fc.created_at, fc.id\\) < \\(\\$3, \\$4\\) ORDER BY .* LIMIT \\$5$").
+		WithArgs(1, 0, after.Time, 8, 3).
+		WillReturnRows(commentRows(after.Time.Add(-time.Minute), 7))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	page, err := repo.GetCommentsByFilmID(context.Background(), 1, 0, 2, &after)
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Zero(t, page.Page)
+	assert.Empty(t, page.NextCursor)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_GetCommentsByFilmIDMentions(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	posted := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{
+		"id", "film_id", "customer_name", "comment", "created_at", "customer_id", "parent_id", "name", "renter",
+		"mentions", "attachment_key", "thumbnail_key",
+	}).AddRow(9, 1, "Guest", "@600 @601 look", posted, nil, nil, nil, false, "{600,601}", "", "")
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery("SELECT COUNT").WithArgs(1, 0).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	sqlMock.ExpectQuery("FROM comment_mentions cm").WithArgs(1, 0, 3).WillReturnRows(rows)
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	page, err := repo.GetCommentsByFilmID(context.Background(), 1, 0, 2, nil)
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, []int{600, 601}, page.Items[0].Mentions)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_GetCommentsByFilmIDShowsViewerTheirShadowBannedComments(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	posted := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	visible := "WHERE fc.film_id = \\$1 AND \\(fc.customer_id = \\$2 OR NOT EXISTS \\(\\s*" +
+		"SELECT 1 FROM customer_shadow_bans b WHERE b.customer_id = fc.customer_id\\)\\)"
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM film_comments fc "+visible).WithArgs(1, 42).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	sqlMock.ExpectQuery(visible).WithArgs(1, 42, 3).WillReturnRows(commentRows(posted, 9))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	page, err := repo.GetCommentsByFilmID(context.Background(), 1, 42, 2, nil)
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, 1, page.Total)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_SetShadowBanned(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	bannedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(42).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery("INSERT INTO customer_shadow_bans").WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"banned_at"}).AddRow(bannedAt))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	status, err := repo.SetShadowBanned(context.Background(), 42, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, status.CustomerID)
+	assert.True(t, status.Banned)
+	assert.Equal(t, bannedAt, *status.BannedAt)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_SetShadowBanLifted(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(42).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectExec("DELETE FROM customer_shadow_bans").WithArgs(42).WillReturnResult(sqlmock.NewResult(0, 1))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	status, err := repo.SetShadowBanned(context.Background(), 42, false)
+
+	require.NoError(t, err)
+	assert.False(t, status.Banned)
+	assert.Nil(t, status.BannedAt)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_SetShadowBannedUnknownCustomer(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(999).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	_, err = repo.SetShadowBanned(context.Background(), 999, true)
+
+	assert.ErrorIs(t, err, repository.ErrCustomerNotFound)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_GetMentionTargets(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("WHERE c.customer_id = ANY\\(\\$1\\) AND c.activebool").WithArgs("{600,601}").
+		WillReturnRows(sqlmock.NewRows([]string{"customer_id", "first_name", "email"}).
+			AddRow(600, "Jane", "jane@example.com"))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	targets, err := repo.GetMentionTargets(context.Background(), []int{600, 601})
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.CommentAuthor{{CustomerID: 600, FirstName: "Jane", Email: "jane@example.com"}}, targets)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_GetCommentSummary(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	refreshed := time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery("FROM film_comment_summaries").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{
+		"film_id", "rating", "comment_count", "latest_comment_at", "top_keywords", "rating_average_comments",
+		"refreshed_at",
+	}).AddRow(1, "PG", 6, refreshed, "{dinosaur,epic}", "4.00", refreshed))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	summary, err := repo.GetCommentSummary(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, summary.CommentCount)
+	assert.Equal(t, []string{"dinosaur", "epic"}, summary.TopKeywords)
+	assert.Equal(t, models.CommentRatingCorrelation{Rating: "PG", AverageComments: 4, RelativeActivity: 1.5},
+		summary.RatingCorrelation)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_GetCommentSummaryNotComputed(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	sqlMock.ExpectQuery("FROM film_comment_summaries").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"film_id"}))
+	repo := repository.NewCommentRepository(db, pii.NewCipher(nil))
+
+	_, err = repo.GetCommentSummary(context.Background(), 1)
+
+	assert.ErrorIs(t, err, repository.ErrCommentSummaryNotFound)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -1,10 +1,22 @@
 package repository_test
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/lib/pq"
+
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
 	"github.com/rxbenefits/go-hw/internal/repository"
 )
 
@@ -15,3 +27,184 @@ func TestNewFilmRepository(t *testing.T) {
 func TestNewCommentRepository(t *testing.T) {
 	assert.NotNil(t, repository.NewCommentRepository)
 }
+
+func TestIsUnavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "bad connection", err: fmt.Errorf("error querying films: %w", driver.ErrBadConn), expected: true},
+		{name: "connection exception", err: &pq.Error{Code: "08006"}, expected: true},
+		{name: "admin shutdown", err: &pq.Error{Code: "57P01"}, expected: true},
+		{name: "syntax error", err: &pq.Error{Code: "42601"}, expected: false},
+		{name: "not found", err: repository.ErrFilmNotFound, expected: false},
+		{name: "other", err: errors.New("boom"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, repository.IsUnavailable(tt.err))
+		})
+	}
+}
+
+func TestFilmRepository_GetCategories(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("SELECT category_id, name, parent_id FROM category").
+		WillReturnRows(sqlmock.NewRows([]string{"category_id", "name", "parent_id"}).
+			AddRow(1, "Action", nil).AddRow(3, "Children", 8).AddRow(8, "Family", nil))
+	repo := repository.NewFilmRepository(db, pagination.Limits{DefaultLimit: 10, MaxLimit: 100})
+
+	categories, err := repo.GetCategories(context.Background())
+
+	require.NoError(t, err)
+	family := 8
+	assert.Equal(t, []models.Category{
+		{CategoryID: 1, Name: "Action"},
+		{CategoryID: 3, Name: "Children", ParentID: &family},
+		{CategoryID: 8, Name: "Family"},
+	}, categories)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestFilmRepository_GetFilmsIncludeSubcategories(t *testing.T) {
+	tests := []struct {
+		name                 string
+		includeSubcategories bool
+		expectedClause       string
+	}{
+		{name: "exact category", includeSubcategories: false, expectedClause: `JOIN category c ON fc.category_id`},
+		{name: "with subcategories", includeSubcategories: true, expectedClause: `WITH RECURSIVE matched`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			sqlMock.ExpectQuery(tt.expectedClause).
+				WithArgs("family", models.FilmPublished, sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"film_id"}))
+			repo := repository.NewFilmRepository(db, pagination.Limits{DefaultLimit: 10, MaxLimit: 100})
+
+			_, err = repo.GetFilms(context.Background(), models.FilmFilters{
+				Categories:           []string{"Family"},
+				IncludeSubcategories: tt.includeSubcategories,
+				CountMode:            models.CountNone,
+				Params:               pagination.Params{Page: 1, Limit: 10},
+			})
+
+			require.NoError(t, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilmRepository_GetFilmByIDNotFound(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("FROM film").WithArgs(999).WillReturnError(sql.ErrNoRows)
+	repo := repository.NewFilmRepository(db, pagination.Limits{DefaultLimit: 10, MaxLimit: 100})
+
+	_, err = repo.GetFilmByID(context.Background(), 999)
+
+	assert.ErrorIs(t, err, repository.ErrFilmNotFound)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestFilmRepository_GetFilmByIDHonorsDeadline(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectQuery("FROM film").WithArgs(1).WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"film_id"}).AddRow(1))
+	repo := repository.NewFilmRepository(db, pagination.Limits{DefaultLimit: 10, MaxLimit: 100})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = repo.GetFilmByID(ctx, 1)
+
+	require.ErrorIs(t, err, sqlmock.ErrCancelled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestFilmRepository_UpdateFilmNotApplied(t *testing.T) {
+	tests := []struct {
+		name        string
+		filmExists  bool
+		expectedErr error
+	}{
+		{name: "changed since fetched", filmExists: true, expectedErr: repository.ErrFilmModified},
+		{name: "film missing", filmExists: false, expectedErr: repository.ErrFilmNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			version := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+			title := "ACADEMY DINOSAUR II"
+			sqlMock.ExpectExec("UPDATE film SET").
+				WithArgs(1, version, &title, nil, nil, nil, nil, nil, nil, nil).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			sqlMock.ExpectQuery("SELECT EXISTS").WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tt.filmExists))
+			repo := repository.NewFilmRepository(db, pagination.Limits{DefaultLimit: 10, MaxLimit: 100})
+
+			_, err = repo.UpdateFilm(context.Background(), 1, version, models.FilmUpdateRequest{Title: &title})
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilmRepository_BulkUpdateRentalRate(t *testing.T) {
+	tests := []struct {
+		name            string
+		dryRun          bool
+		expectedFilmIDs []int
+	}{
+		{name: "applied", dryRun: false, expectedFilmIDs: []int{4, 9}},
+		{name: "dry run", dryRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			percent := 10.0
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectQuery(`SELECT COUNT\(\*\) FROM \(SELECT 1 FROM film f .* FOR UPDATE\)`).
+				WithArgs("PG", models.FilmDraft, models.FilmPublished).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			sqlMock.ExpectQuery(`UPDATE film f SET rental_rate = LEAST\(ROUND\(f.rental_rate \* \(1 \+ \$4`).
+				WithArgs("PG", models.FilmDraft, models.FilmPublished, percent).
+				WillReturnRows(sqlmock.NewRows([]string{"film_id"}).AddRow(4).AddRow(9))
+			if tt.dryRun {
+				sqlMock.ExpectRollback()
+			} else {
+				sqlMock.ExpectCommit()
+			}
+			repo := repository.NewFilmRepository(db, pagination.Limits{DefaultLimit: 10, MaxLimit: 100})
+
+			result, filmIDs, err := repo.BulkUpdateRentalRate(
+				context.Background(),
+				models.FilmFilters{Ratings: []string{"PG"}, Statuses: []string{models.FilmDraft, models.FilmPublished}},
+				models.BulkPriceRequest{Percent: &percent, DryRun: tt.dryRun})
+
+			require.NoError(t, err)
+			assert.Equal(t, &models.BulkPriceResult{FilmsMatched: 3, FilmsChanged: 2, DryRun: tt.dryRun}, result)
+			assert.Equal(t, tt.expectedFilmIDs, filmIDs)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
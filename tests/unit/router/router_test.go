@@ -0,0 +1,160 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rxbenefits/go-hw/internal/router"
+)
+
+// echo writes the request's method, path, and id path value.
+func echo(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(r.Method + " " + r.URL.Path + " " + r.PathValue("id")))
+}
+
+// tag returns middleware that appends name to the X-Trace header, so tests
+// can see which middleware ran and in what order.
+func tag(name string) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Trace", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func serve(handler http.Handler, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestMux_Routes(t *testing.T) {
+	r := router.New()
+	api := r.Group("/api/v1")
+	api.HandleFunc("GET", echo)
+	api.HandleFunc("GET /films/{id}", echo)
+	api.HandleFunc("GET /films/trending", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("trending"))
+	})
+	api.Group("/stores/{storeID}").HandleFunc("GET /films/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.PathValue("storeID") + "/" + r.PathValue("id")))
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		expected string
+	}{
+		{name: "group prefix itself", method: http.MethodGet, path: "/api/v1", expected: "GET /api/v1 "},
+		{name: "path value", method: http.MethodGet, path: "/api/v1/films/7", expected: "GET /api/v1/films/7 7"},
+		{name: "literal beats wildcard", method: http.MethodGet, path: "/api/v1/films/trending", expected: "trending"},
+		{name: "nested group", method: http.MethodGet, path: "/api/v1/stores/2/films/7", expected: "2/7"},
+		{name: "HEAD on GET route", method: http.MethodHead, path: "/api/v1/films/7", expected: "HEAD /api/v1/films/7 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, tt.method, tt.path)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expected, w.Body.String())
+		})
+	}
+}
+
+func TestMux_MiddlewareOrder(t *testing.T) {
+	r := router.New()
+	r.Use(tag("root"))
+	api := r.Group("/api/v1", tag("group"))
+	api.HandleFunc("GET /films", echo, tag("route-1"), tag("route-2"))
+	// Middleware added after a route still wraps it.
+	api.Use(tag("late"))
+	r.HandleFunc("GET /other", echo)
+
+	w := serve(r, http.MethodGet, "/api/v1/films")
+	assert.Equal(t, []string{"root", "group", "late", "route-1", "route-2"}, w.Header().Values("X-Trace"))
+
+	w = serve(r, http.MethodGet, "/other")
+	assert.Equal(t, []string{"root"}, w.Header().Values("X-Trace"))
+}
+
+func TestMux_Misses(t *testing.T) {
+	fallback := func(status int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte("custom"))
+		})
+	}
+	r := router.New(
+		router.WithNotFound(fallback(http.StatusNotFound)),
+		router.WithMethodNotAllowed(fallback(http.StatusMethodNotAllowed)),
+	)
+	r.Use(tag("root"))
+	r.HandleFunc("GET /films", echo)
+	r.HandleFunc("POST /films", echo)
+
+	w := serve(r, http.MethodGet, "/nope")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "custom", w.Body.String())
+	assert.Empty(t, w.Header().Values("X-Trace"))
+
+	w = serve(r, http.MethodDelete, "/films")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD, POST", w.Header().Get("Allow"))
+	assert.Equal(t, "custom", w.Body.String())
+}
+
+func TestMux_DefaultMisses(t *testing.T) {
+	r := router.New()
+	r.HandleFunc("GET /films", echo)
+
+	w := serve(r, http.MethodGet, "/nope")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(r, http.MethodPost, "/films")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+}
+
+func TestMux_Match(t *testing.T) {
+	r := router.New()
+	r.HandleFunc("GET /films", echo)
+	r.HandleFunc("/swagger/", echo)
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "/films", expected: true},
+		{path: "/swagger/", expected: true},
+		{path: "/swagger/index.html", expected: true},
+		{path: "/films/", expected: false},
+		{path: "//films", expected: false},
+		{path: "/nope", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.URL.Path = tt.path
+			assert.Equal(t, tt.expected, r.Match(req))
+		})
+	}
+}
+
+func TestMux_ConflictingRoutesPanic(t *testing.T) {
+	r := router.New()
+	r.HandleFunc("GET /films/{id}", echo)
+
+	assert.Panics(t, func() {
+		r.Group("/films").HandleFunc("GET /{filmID}", echo)
+	})
+	assert.NotPanics(t, func() {
+		r.HandleFunc("GET /films/new", echo)
+	})
+}
@@ -0,0 +1,155 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockActivityRepository struct {
+	mock.Mock
+}
+
+func (m *MockActivityRepository) Follow(_ context.Context, followerID, followedID int) error {
+	return m.Called(followerID, followedID).Error(0)
+}
+
+func (m *MockActivityRepository) Unfollow(_ context.Context, followerID, followedID int) error {
+	return m.Called(followerID, followedID).Error(0)
+}
+
+func (m *MockActivityRepository) ListFollowing(_ context.Context, customerID int) ([]models.Follow, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
+
+func (m *MockActivityRepository) RecordActivity(_ context.Context, activity models.Activity) error {
+	return m.Called(activity).Error(0)
+}
+
+func (m *MockActivityRepository) GetFeed(
+	_ context.Context,
+	customerID int,
+	filters models.ActivityFeedFilters,
+) (*pagination.Paginated[models.Activity], error) {
+	args := m.Called(customerID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.Activity]), args.Error(1)
+}
+
+func TestActivityService_FollowRejectsSelf(t *testing.T) {
+	mockRepo := new(MockActivityRepository)
+	activityService := service.NewActivityService(mockRepo)
+	mockRepo.On("Follow", 600, 601).Return(nil)
+
+	require.NoError(t, activityService.Follow(context.Background(), 600, 601))
+	require.ErrorIs(t, activityService.Follow(context.Background(), 600, 600), service.ErrSelfFollow)
+	mockRepo.AssertNumberOfCalls(t, "Follow", 1)
+}
+
+func TestActivityService_GetFeedIsTheTokensCustomers(t *testing.T) {
+	mockRepo := new(MockActivityRepository)
+	activityService := service.NewActivityService(mockRepo)
+	filters := models.ActivityFeedFilters{Page: 1, Limit: 20}
+	mockRepo.On("GetFeed", 600, filters).Return(&pagination.Paginated[models.Activity]{Items: []models.Activity{}}, nil)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: 600, Role: auth.RoleCustomer})
+	_, err := activityService.GetFeed(ctx, filters)
+	require.NoError(t, err)
+
+	staff := auth.WithClaims(context.Background(), &auth.Claims{Subject: 1, Role: auth.RoleStaff})
+	_, err = activityService.GetFeed(staff, filters)
+	require.ErrorIs(t, err, service.ErrFeedRequiresCustomer)
+	_, err = activityService.GetFeed(context.Background(), filters)
+	require.ErrorIs(t, err, service.ErrFeedRequiresCustomer)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCommentService_AddCommentRecordsCustomerActivity(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockFilmRepo := new(MockFilmRepository)
+	mockActivityRepo := new(MockActivityRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo,
+		service.WithCommentActivity(service.NewActivityService(mockActivityRepo)))
+
+	mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+	mockCommentRepo.On("IsShadowBanned", 600).Return(false, nil)
+	mockCommentRepo.On("AddComment", 1, mock.Anything).
+		Return(&models.Comment{ID: 11, FilmID: 1, CustomerID: intPtr(600), Comment: "Great film"}, nil).Once()
+	mockCommentRepo.On("AddComment", 1, mock.Anything).
+		Return(&models.Comment{ID: 12, FilmID: 1, CustomerName: "Bob", Comment: "Great film"}, nil).Once()
+	mockActivityRepo.On("RecordActivity", models.Activity{
+		CustomerID: 600, Kind: models.ActivityCommentPosted, FilmID: intPtr(1), CommentID: intPtr(11),
+	}).Return(nil)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: 600, Role: auth.RoleCustomer})
+	_, err := commentService.AddComment(ctx, 1, models.CommentRequest{Comment: "Great film"})
+	require.NoError(t, err)
+	_, err = commentService.AddComment(context.Background(), 1,
+		models.CommentRequest{CustomerName: "Bob", Comment: "Great film"})
+	require.NoError(t, err)
+
+	// Anonymous comments belong to no one's feed.
+	mockActivityRepo.AssertExpectations(t)
+	mockActivityRepo.AssertNumberOfCalls(t, "RecordActivity", 1)
+}
+
+func TestCustomerListService_RecordsPublicListActivity(t *testing.T) {
+	mockRepo := new(MockCustomerListRepository)
+	mockActivityRepo := new(MockActivityRepository)
+	listService := service.NewCustomerListService(mockRepo,
+		service.WithListActivity(service.NewActivityService(mockActivityRepo)))
+	mockRepo.On("AddListEntry", 600, 7, 8).
+		Return(&models.CustomerList{ID: 7, CustomerID: 600, Visibility: models.ListVisibilityPublic}, nil)
+	mockRepo.On("AddListEntry", 600, 9, 8).
+		Return(&models.CustomerList{ID: 9, CustomerID: 600, Visibility: models.ListVisibilityPrivate}, nil)
+	mockActivityRepo.On("RecordActivity", models.Activity{
+		CustomerID: 600, Kind: models.ActivityListEntryAdded, FilmID: intPtr(8), ListID: intPtr(7),
+	}).Return(nil)
+
+	_, err := listService.AddListEntry(context.Background(), 600, 7, 8)
+	require.NoError(t, err)
+	_, err = listService.AddListEntry(context.Background(), 600, 9, 8)
+	require.NoError(t, err)
+
+	mockActivityRepo.AssertExpectations(t)
+	mockActivityRepo.AssertNumberOfCalls(t, "RecordActivity", 1)
+}
+
+func TestCustomerListService_UpdateListPublishesWhenMadePublic(t *testing.T) {
+	mockRepo := new(MockCustomerListRepository)
+	mockActivityRepo := new(MockActivityRepository)
+	listService := service.NewCustomerListService(mockRepo,
+		service.WithListActivity(service.NewActivityService(mockActivityRepo)))
+	listReq := models.CustomerListRequest{Name: "Best 80s Horror", Visibility: models.ListVisibilityPublic}
+	mockRepo.On("GetList", 7).
+		Return(&models.CustomerList{ID: 7, CustomerID: 600, Visibility: models.ListVisibilityPrivate}, nil).Once()
+	mockRepo.On("GetList", 7).
+		Return(&models.CustomerList{ID: 7, CustomerID: 600, Visibility: models.ListVisibilityPublic}, nil).Once()
+	mockRepo.On("UpdateList", 600, 7, listReq).
+		Return(&models.CustomerList{ID: 7, CustomerID: 600, Visibility: models.ListVisibilityPublic}, nil)
+	mockActivityRepo.On("RecordActivity", models.Activity{
+		CustomerID: 600, Kind: models.ActivityListPublished, ListID: intPtr(7),
+	}).Return(nil)
+
+	_, err := listService.UpdateList(context.Background(), 600, 7, listReq)
+	require.NoError(t, err)
+	// Saving a list that is already public publishes nothing.
+	_, err = listService.UpdateList(context.Background(), 600, 7, listReq)
+	require.NoError(t, err)
+
+	assert.Len(t, mockActivityRepo.Calls, 1)
+}
@@ -0,0 +1,142 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(
+	_ context.Context,
+	keyReq models.APIKeyRequest,
+	keyPrefix, keyHash string,
+) (*models.APIKey, error) {
+	args := m.Called(keyReq, keyPrefix, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) ListAPIKeys(_ context.Context) ([]models.APIKey, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(_ context.Context, keyID int) (*models.APIKey, error) {
+	args := m.Called(keyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetActiveAPIKeyByHash(_ context.Context, keyHash string) (*models.APIKey, error) {
+	args := m.Called(keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) IncrementUsage(
+	_ context.Context,
+	keyID int,
+	period time.Time,
+	quota int64,
+) (int64, bool, error) {
+	args := m.Called(keyID, period, quota)
+	return args.Get(0).(int64), args.Bool(1), args.Error(2)
+}
+
+func (m *MockAPIKeyRepository) GetUsage(_ context.Context, keyID int, period time.Time) (int64, error) {
+	args := m.Called(keyID, period)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestAPIKeyService_CreateKeyStoresOnlyTheHash(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	keyService := service.NewAPIKeyService(mockRepo)
+	keyReq := models.APIKeyRequest{Name: "Partner Co", Tier: models.APIKeyTierPartner}
+
+	var storedPrefix, storedHash string
+	mockRepo.On("CreateAPIKey", keyReq, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			storedPrefix, storedHash = args.String(1), args.String(2)
+		}).
+		Return(&models.APIKey{ID: 1, Name: keyReq.Name, Tier: keyReq.Tier}, nil)
+
+	key, err := keyService.CreateKey(context.Background(), keyReq)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key.Key, storedPrefix))
+	assert.Equal(t, auth.HashAPIKey(key.Key), storedHash)
+}
+
+func TestAPIKeyService_AuthenticateUnknownKey(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	keyService := service.NewAPIKeyService(mockRepo)
+	mockRepo.On("GetActiveAPIKeyByHash", auth.HashAPIKey("mbk_unknown")).Return(nil, repository.ErrAPIKeyNotFound)
+
+	_, err := keyService.Authenticate(context.Background(), "mbk_unknown")
+
+	require.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+}
+
+func TestAPIKeyService_RecordRequest(t *testing.T) {
+	tests := []struct {
+		name              string
+		tier              string
+		expectedQuota     int64
+		used              int64
+		counted           bool
+		expectedRemaining *int64
+	}{
+		{name: "within quota", tier: models.APIKeyTierFree, expectedQuota: 10_000, used: 42, counted: true,
+			expectedRemaining: int64Ptr(9_958)},
+		{name: "quota exhausted", tier: models.APIKeyTierFree, expectedQuota: 10_000, used: 10_000,
+			expectedRemaining: int64Ptr(0)},
+		{name: "unlimited tier", tier: models.APIKeyTierInternal, used: 7, counted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockAPIKeyRepository)
+			keyService := service.NewAPIKeyService(mockRepo)
+			key := &models.APIKey{ID: 3, Tier: tt.tier}
+			mockRepo.On("IncrementUsage", 3, mock.MatchedBy(func(period time.Time) bool {
+				return period.Day() == 1 && period.Hour() == 0 && period.Location() == time.UTC
+			}), tt.expectedQuota).Return(tt.used, tt.counted, nil)
+
+			usage, counted, err := keyService.RecordRequest(context.Background(), key)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.counted, counted)
+			assert.Equal(t, tt.used, usage.Used)
+			assert.Equal(t, tt.expectedRemaining, usage.Remaining)
+			assert.Equal(t, 1, usage.ResetsAt.Day())
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
@@ -0,0 +1,57 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) ListStaffActivity(
+	_ context.Context,
+	staffID int,
+	filters models.AuditFilters,
+) (*pagination.Paginated[models.AuditEntry], error) {
+	args := m.Called(staffID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.AuditEntry]), args.Error(1)
+}
+
+func TestAuditService_GetStaffActivityNormalizesPage(t *testing.T) {
+	auditRepo := new(MockAuditRepository)
+	auditService := service.NewAuditService(auditRepo)
+	params := pagination.Params{Page: 1, Limit: 20}
+	filters := models.AuditFilters{Actions: []string{models.AuditActionCustomerErased}, Params: params}
+	entries := []models.AuditEntry{{ID: 7, Action: models.AuditActionCustomerErased, TargetID: 600}}
+	auditRepo.On("ListStaffActivity", 1, filters).Return(pagination.New(entries, 1, params), nil)
+
+	activity, err := auditService.GetStaffActivity(context.Background(), 1,
+		models.AuditFilters{Actions: []string{models.AuditActionCustomerErased}})
+
+	require.NoError(t, err)
+	assert.Equal(t, entries, activity.Items)
+	assert.Equal(t, 1, activity.Total)
+}
+
+func TestAuditService_GetStaffActivityStaffNotFound(t *testing.T) {
+	auditRepo := new(MockAuditRepository)
+	auditService := service.NewAuditService(auditRepo)
+	auditRepo.On("ListStaffActivity", 99, mock.Anything).Return(nil, repository.ErrStaffNotFound)
+
+	_, err := auditService.GetStaffActivity(context.Background(), 99, models.AuditFilters{})
+
+	assert.ErrorIs(t, err, repository.ErrStaffNotFound)
+}
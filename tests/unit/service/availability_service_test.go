@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockAvailabilityRepository struct {
+	mock.Mock
+}
+
+func (m *MockAvailabilityRepository) CreateAlert(
+	_ context.Context,
+	customerID, filmID int,
+) (*models.AvailabilityAlert, bool, error) {
+	args := m.Called(customerID, filmID)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*models.AvailabilityAlert), args.Bool(1), args.Error(2)
+}
+
+func (m *MockAvailabilityRepository) ClaimAlerts(_ context.Context, filmID int) ([]models.AvailabilityNotice, error) {
+	args := m.Called(filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AvailabilityNotice), args.Error(1)
+}
+
+type recordingAvailabilityNotifier struct {
+	notices []notifications.FilmAvailable
+	err     error
+}
+
+func (n *recordingAvailabilityNotifier) NotifyFilmAvailable(available notifications.FilmAvailable) error {
+	n.notices = append(n.notices, available)
+	return n.err
+}
+
+func TestAvailabilityService_NotifyMe(t *testing.T) {
+	mockRepo := new(MockAvailabilityRepository)
+	availabilityService := service.NewAvailabilityService(mockRepo, &recordingAvailabilityNotifier{})
+	alert := &models.AvailabilityAlert{ID: 1, FilmID: 8, CustomerID: 600}
+	mockRepo.On("CreateAlert", 600, 8).Return(alert, true, nil)
+	mockRepo.On("CreateAlert", 600, 999).Return(nil, false, repository.ErrFilmNotFound)
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: 600, Role: auth.RoleCustomer})
+
+	result, created, err := availabilityService.NotifyMe(ctx, 8)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, alert, result)
+
+	_, _, err = availabilityService.NotifyMe(ctx, 999)
+	require.ErrorIs(t, err, repository.ErrFilmNotFound)
+
+	staff := auth.WithClaims(context.Background(), &auth.Claims{Subject: 1, Role: auth.RoleStaff})
+	_, _, err = availabilityService.NotifyMe(staff, 8)
+	require.ErrorIs(t, err, service.ErrAlertRequiresCustomer)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAvailabilityService_NotifyAvailable(t *testing.T) {
+	mockRepo := new(MockAvailabilityRepository)
+	notifier := &recordingAvailabilityNotifier{err: errors.New("queue full")}
+	availabilityService := service.NewAvailabilityService(mockRepo, notifier)
+	mockRepo.On("ClaimAlerts", 8).Return([]models.AvailabilityNotice{
+		{AlertID: 1, CustomerID: 600, CustomerEmail: "a@example.com", CustomerName: "Ann", FilmID: 8, FilmTitle: "Alien"},
+		{AlertID: 2, CustomerID: 601, CustomerEmail: "b@example.com", CustomerName: "Bob", FilmID: 8, FilmTitle: "Alien"},
+	}, nil)
+
+	// A failed send is logged and the remaining notices still go out.
+	err := availabilityService.NotifyAvailable(context.Background(), 8)
+
+	require.NoError(t, err)
+	require.Len(t, notifier.notices, 2)
+	assert.Equal(t, notifications.FilmAvailable{
+		RecipientID:    600,
+		RecipientEmail: "a@example.com",
+		RecipientName:  "Ann",
+		FilmTitle:      "Alien",
+	}, notifier.notices[0])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAvailabilityService_NotifyAvailableClaimError(t *testing.T) {
+	mockRepo := new(MockAvailabilityRepository)
+	notifier := &recordingAvailabilityNotifier{}
+	availabilityService := service.NewAvailabilityService(mockRepo, notifier)
+	mockRepo.On("ClaimAlerts", 8).Return(nil, errors.New("database down"))
+
+	err := availabilityService.NotifyAvailable(context.Background(), 8)
+
+	require.EqualError(t, err, "database down")
+	assert.Empty(t, notifier.notices)
+}
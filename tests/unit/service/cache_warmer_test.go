@@ -0,0 +1,46 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+func TestWarmFilmCache(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits)
+
+	fullPage := &pagination.Paginated[models.Film]{Items: make([]models.Film, 2), Total: 3, Page: 1, Limit: 2}
+	lastPage := &pagination.Paginated[models.Film]{Items: make([]models.Film, 1), Total: 3, Page: 2, Limit: 2}
+
+	mockRepo.On("GetCategories").Return([]models.Category{{CategoryID: 1, Name: "Action"}}, nil)
+	mockRepo.On("GetFilms", models.FilmFilters{Params: pagination.Params{Page: 1, Limit: 2}}).Return(fullPage, nil)
+	mockRepo.On("GetFilms", models.FilmFilters{Params: pagination.Params{Page: 2, Limit: 2}}).Return(lastPage, nil)
+
+	// Warming stops after the short second page even though five were requested.
+	err := service.WarmFilmCache(context.Background(), filmService, 5, 2)
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "GetFilms", 2)
+}
+
+func TestWarmFilmCache_CategoriesError(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits)
+
+	mockRepo.On("GetCategories").Return([]models.Category(nil), errors.New("database error"))
+
+	err := service.WarmFilmCache(context.Background(), filmService, 3, 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error warming categories")
+
+	mockRepo.AssertNotCalled(t, "GetFilms")
+}
@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+func TestCalendarService_IssueCalendarURL(t *testing.T) {
+	tokens := auth.NewTokenIssuer(auth.RoleCalendar, "secret", time.Hour)
+	calendarService := service.NewCalendarService(new(MockRentalRepository), tokens, "https://mockbuster.example")
+
+	subscription, err := calendarService.IssueCalendarURL(context.Background(), 600)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(subscription.URL,
+		"https://mockbuster.example/api/v1/customers/600/rentals.ics?token="))
+	parsed, err := url.Parse(subscription.URL)
+	require.NoError(t, err)
+	claims, err := tokens.Verify(parsed.Query().Get("token"))
+	require.NoError(t, err)
+	assert.Equal(t, 600, claims.Subject)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), subscription.ExpiresAt, time.Minute)
+}
+
+func TestCalendarService_RentalCalendar(t *testing.T) {
+	mockRepo := new(MockRentalRepository)
+	calendarService := service.NewCalendarService(mockRepo,
+		auth.NewTokenIssuer(auth.RoleCalendar, "secret", time.Hour), "https://mockbuster.example")
+	rented := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	mockRepo.On("GetCustomerRentals", 600, models.RentalHistoryFilters{
+		Status: models.RentalStatusOverdue, Params: pagination.Params{Page: 1, Limit: 100},
+	}).Return(&pagination.Paginated[models.RentalEvent]{Items: []models.RentalEvent{
+		{RentalID: 2, FilmID: 8, FilmTitle: "Airport Pollock", StoreID: 1, RentalDate: rented.AddDate(0, 0, -10),
+			DueAt: rented.AddDate(0, 0, -4), Status: models.RentalStatusOverdue},
+	}}, nil)
+	mockRepo.On("GetCustomerRentals", 600, models.RentalHistoryFilters{
+		Status: models.RentalStatusOpen, Params: pagination.Params{Page: 1, Limit: 100},
+	}).Return(&pagination.Paginated[models.RentalEvent]{Items: []models.RentalEvent{
+		{RentalID: 7, FilmID: 1, FilmTitle: "Academy Dinosaur", StoreID: 2, RentalDate: rented,
+			DueAt: rented.AddDate(0, 0, 6), Status: models.RentalStatusOpen},
+	}}, nil)
+
+	calendar, err := calendarService.RentalCalendar(context.Background(), 600)
+
+	require.NoError(t, err)
+	require.Len(t, calendar.Events, 2)
+	assert.Equal(t, "Overdue: return Airport Pollock", calendar.Events[0].Summary)
+	open := calendar.Events[1]
+	assert.Equal(t, "rental-7@mockbuster", open.UID)
+	assert.Equal(t, "Return Academy Dinosaur", open.Summary)
+	assert.Equal(t, rented.AddDate(0, 0, 6), open.Start)
+	assert.Equal(t, rented, open.Stamp)
+	assert.Equal(t, "Rented 2024-05-01 from store 2. Rental 7.", open.Description)
+	assert.Equal(t, "https://mockbuster.example/api/v1/films/1", open.URL)
+}
+
+func TestCalendarService_RentalCalendarUnknownCustomer(t *testing.T) {
+	mockRepo := new(MockRentalRepository)
+	calendarService := service.NewCalendarService(mockRepo,
+		auth.NewTokenIssuer(auth.RoleCalendar, "secret", time.Hour), "https://mockbuster.example")
+	mockRepo.On("GetCustomerRentals", 999, models.RentalHistoryFilters{
+		Status: models.RentalStatusOverdue, Params: pagination.Params{Page: 1, Limit: 100},
+	}).Return(nil, repository.ErrCustomerNotFound)
+
+	_, err := calendarService.RentalCalendar(context.Background(), 999)
+
+	require.ErrorIs(t, err, repository.ErrCustomerNotFound)
+}
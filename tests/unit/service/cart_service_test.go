@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/giftcards"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockCartRepository struct {
+	mock.Mock
+}
+
+func (m *MockCartRepository) GetCart(_ context.Context, customerID int) (*models.Cart, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Cart), args.Error(1)
+}
+
+func (m *MockCartRepository) AddCartItem(_ context.Context, customerID, filmID int) error {
+	return m.Called(customerID, filmID).Error(0)
+}
+
+func (m *MockCartRepository) RemoveCartItem(_ context.Context, customerID, filmID int) error {
+	return m.Called(customerID, filmID).Error(0)
+}
+
+func (m *MockCartRepository) Checkout(
+	_ context.Context,
+	customerID int,
+	lines []models.CheckoutLine,
+	couponCode, giftCardHash string,
+	rentalPoints int,
+) (*models.Checkout, error) {
+	args := m.Called(customerID, lines, couponCode, giftCardHash, rentalPoints)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Checkout), args.Error(1)
+}
+
+func (m *MockCartRepository) GetCheckout(_ context.Context, checkoutID int) (*models.Checkout, error) {
+	args := m.Called(checkoutID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Checkout), args.Error(1)
+}
+
+// cartWithTwoFilms returns a fresh cart at store 1 holding a comedy and a
+// drama.
+func cartWithTwoFilms() *models.Cart {
+	return &models.Cart{
+		CustomerID: 600,
+		StoreID:    1,
+		Items: []models.CartItem{
+			{FilmID: 1, BaseRate: 4.99, CategoryIDs: []int{5}},
+			{FilmID: 2, BaseRate: 2.99, CategoryIDs: []int{7}},
+		},
+	}
+}
+
+func TestCartService_GetCartAppliesPricingRules(t *testing.T) {
+	mockCartRepo := new(MockCartRepository)
+	mockPricingRepo := new(MockPricingRepository)
+	cartService := service.NewCartService(mockCartRepo, mockPricingRepo)
+	comedy, percent := 5, 20.0
+	mockCartRepo.On("GetCart", 600).Return(cartWithTwoFilms(), nil)
+	mockPricingRepo.On("ListPricingRules").Return([]models.PricingRule{
+		{ID: 1, Kind: models.PricingRulePercentOff, PercentOff: &percent, CategoryID: &comedy},
+	}, nil)
+
+	cart, err := cartService.GetCart(context.Background(), 600)
+
+	require.NoError(t, err)
+	assert.InDelta(t, 3.99, cart.Items[0].Rate, 0.001)
+	assert.InDelta(t, 2.99, cart.Items[1].Rate, 0.001)
+	assert.InDelta(t, 6.98, cart.Subtotal, 0.001)
+}
+
+func TestCartService_CheckoutRentsCartAtCartRates(t *testing.T) {
+	mockCartRepo := new(MockCartRepository)
+	mockPricingRepo := new(MockPricingRepository)
+	cartService := service.NewCartService(mockCartRepo, mockPricingRepo, service.WithRentalPoints(10))
+	mockCartRepo.On("GetCart", 600).Return(cartWithTwoFilms(), nil)
+	mockPricingRepo.On("ListPricingRules").Return([]models.PricingRule{}, nil)
+	lines := []models.CheckoutLine{{FilmID: 1, Rate: 4.99}, {FilmID: 2, Rate: 2.99}}
+	mockCartRepo.On("Checkout", 600, lines, "FIVEOFF", "", 10).Return(&models.Checkout{ID: 1, Total: 2.98}, nil)
+
+	checkout, err := cartService.Checkout(context.Background(), 600, models.CheckoutRequest{CouponCode: "fiveoff"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, checkout.ID)
+	mockCartRepo.AssertExpectations(t)
+}
+
+func TestCartService_CheckoutLooksUpGiftCardByHash(t *testing.T) {
+	mockCartRepo := new(MockCartRepository)
+	mockPricingRepo := new(MockPricingRepository)
+	cartService := service.NewCartService(mockCartRepo, mockPricingRepo)
+	mockCartRepo.On("GetCart", 600).Return(cartWithTwoFilms(), nil)
+	mockPricingRepo.On("ListPricingRules").Return([]models.PricingRule{}, nil)
+	mockCartRepo.On("Checkout", 600, mock.Anything, "", giftcards.Hash("7KQF-M2XD-9HRT-WB4C"), 0).
+		Return(&models.Checkout{ID: 1, GiftCardApplied: 7.98}, nil)
+
+	checkout, err := cartService.Checkout(context.Background(), 600,
+		models.CheckoutRequest{GiftCardCode: "7kqf m2xd 9hrt wb4c"})
+
+	require.NoError(t, err)
+	assert.InDelta(t, 7.98, checkout.GiftCardApplied, 0.001)
+	mockCartRepo.AssertExpectations(t)
+}
+
+func TestCartService_CheckoutEmptyCart(t *testing.T) {
+	mockCartRepo := new(MockCartRepository)
+	mockPricingRepo := new(MockPricingRepository)
+	cartService := service.NewCartService(mockCartRepo, mockPricingRepo)
+	mockCartRepo.On("GetCart", 600).Return(&models.Cart{CustomerID: 600, Items: []models.CartItem{}}, nil)
+
+	_, err := cartService.Checkout(context.Background(), 600, models.CheckoutRequest{})
+
+	require.ErrorIs(t, err, repository.ErrCartEmpty)
+	mockCartRepo.AssertNotCalled(t, "Checkout", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything)
+}
@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockCollectionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCollectionRepository) CreateCollection(
+	_ context.Context,
+	collectionReq models.CollectionRequest,
+) (*models.Collection, error) {
+	args := m.Called(collectionReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionRepository) ListCollections(_ context.Context) ([]models.Collection, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionRepository) GetCollection(_ context.Context, collectionID int) (*models.Collection, error) {
+	args := m.Called(collectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionRepository) UpdateCollection(
+	_ context.Context,
+	collectionID int,
+	collectionReq models.CollectionRequest,
+) (*models.Collection, []int, error) {
+	args := m.Called(collectionID, collectionReq)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.Collection), args.Get(1).([]int), args.Error(2)
+}
+
+func (m *MockCollectionRepository) DeleteCollection(_ context.Context, collectionID int) ([]int, error) {
+	args := m.Called(collectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+type recordingInvalidations struct {
+	events []cache.Event
+}
+
+func (p *recordingInvalidations) Publish(event cache.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestCollectionService_CreateCollectionEvictsItsFilms(t *testing.T) {
+	mockRepo := new(MockCollectionRepository)
+	invalidations := &recordingInvalidations{}
+	collectionService := service.NewCollectionService(mockRepo, invalidations)
+	collectionReq := models.CollectionRequest{Name: "Alien Franchise", FilmIDs: []int{8, 3}}
+	mockRepo.On("CreateCollection", collectionReq).Return(&models.Collection{ID: 1, FilmCount: 2}, nil)
+
+	collection, err := collectionService.CreateCollection(context.Background(), collectionReq)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, collection.ID)
+	assert.Equal(t, []cache.Event{cache.FilmsChanged(8, 3)}, invalidations.events)
+}
+
+func TestCollectionService_UpdateCollectionEvictsOldAndNewFilms(t *testing.T) {
+	mockRepo := new(MockCollectionRepository)
+	invalidations := &recordingInvalidations{}
+	collectionService := service.NewCollectionService(mockRepo, invalidations)
+	collectionReq := models.CollectionRequest{Name: "Alien Franchise", FilmIDs: []int{3}}
+	mockRepo.On("UpdateCollection", 1, collectionReq).Return(&models.Collection{ID: 1, FilmCount: 1}, []int{8, 3}, nil)
+
+	_, err := collectionService.UpdateCollection(context.Background(), 1, collectionReq)
+
+	require.NoError(t, err)
+	assert.Equal(t, []cache.Event{cache.FilmsChanged(8, 3, 3)}, invalidations.events)
+}
+
+func TestCollectionService_DeleteCollection(t *testing.T) {
+	mockRepo := new(MockCollectionRepository)
+	invalidations := &recordingInvalidations{}
+	collectionService := service.NewCollectionService(mockRepo, invalidations)
+	mockRepo.On("DeleteCollection", 1).Return([]int{}, nil)
+	mockRepo.On("DeleteCollection", 2).Return(nil, repository.ErrCollectionNotFound)
+
+	require.NoError(t, collectionService.DeleteCollection(context.Background(), 1))
+	require.ErrorIs(t, collectionService.DeleteCollection(context.Background(), 2), repository.ErrCollectionNotFound)
+	assert.Empty(t, invalidations.events, "an empty collection changes no films")
+}
@@ -1,23 +1,41 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"image"
+	"image/png"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/attachments"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/metrics"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/pagination"
 	"github.com/rxbenefits/go-hw/internal/repository"
 	"github.com/rxbenefits/go-hw/internal/service"
+	"github.com/rxbenefits/go-hw/internal/webhooks"
 )
 
 type MockCommentRepository struct {
 	mock.Mock
 }
 
-func (m *MockCommentRepository) AddComment(filmID int, commentReq models.CommentRequest) (*models.Comment, error) {
+func (m *MockCommentRepository) AddComment(
+	_ context.Context,
+	filmID int,
+	commentReq models.CommentRequest,
+) (*models.Comment, error) {
 	args := m.Called(filmID, commentReq)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -25,9 +43,79 @@ func (m *MockCommentRepository) AddComment(filmID int, commentReq models.Comment
 	return args.Get(0).(*models.Comment), args.Error(1)
 }
 
-func (m *MockCommentRepository) GetCommentsByFilmID(filmID int) ([]models.Comment, error) {
+func (m *MockCommentRepository) SetCommentsLocked(
+	_ context.Context,
+	filmID int,
+	locked bool,
+) (*models.CommentLockStatus, error) {
+	args := m.Called(filmID, locked)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentLockStatus), args.Error(1)
+}
+
+func (m *MockCommentRepository) SetShadowBanned(
+	_ context.Context,
+	customerID int,
+	banned bool,
+) (*models.ShadowBanStatus, error) {
+	args := m.Called(customerID, banned)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShadowBanStatus), args.Error(1)
+}
+
+func (m *MockCommentRepository) IsShadowBanned(_ context.Context, customerID int) (bool, error) {
+	args := m.Called(customerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetCommentsByFilmID(
+	_ context.Context,
+	filmID int,
+	viewerID int,
+	limit int,
+	after *pagination.Cursor,
+) (*pagination.Paginated[models.Comment], error) {
+	args := m.Called(filmID, viewerID, limit, after)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.Comment]), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetCommentAuthor(_ context.Context, commentID int) (*models.CommentAuthor, error) {
+	args := m.Called(commentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentAuthor), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetMentionTargets(
+	_ context.Context,
+	customerIDs []int,
+) ([]models.CommentAuthor, error) {
+	args := m.Called(customerIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CommentAuthor), args.Error(1)
+}
+
+func (m *MockCommentRepository) RefreshCommentSummaries(_ context.Context, stopwords []string, keywordLimit int) error {
+	args := m.Called(stopwords, keywordLimit)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetCommentSummary(_ context.Context, filmID int) (*models.CommentSummary, error) {
 	args := m.Called(filmID)
-	return args.Get(0).([]models.Comment), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentSummary), args.Error(1)
 }
 
 func TestCommentService_AddComment(t *testing.T) {
@@ -61,6 +149,7 @@ func TestCommentService_AddComment(t *testing.T) {
 				FilmID:       1,
 				CustomerName: "John Doe",
 				Comment:      "Great movie!",
+				CommentHTML:  "<p>Great movie!</p>\n",
 			},
 		},
 		{
@@ -109,6 +198,15 @@ func TestCommentService_AddComment(t *testing.T) {
 			},
 			expectedError: "customer name too long",
 		},
+		{
+			name:   "customer name looks encrypted",
+			filmID: 1,
+			commentReq: models.CommentRequest{
+				CustomerName: "pii:v1:x:y:z",
+				Comment:      "Great movie!",
+			},
+			expectedError: "customer name must not start with",
+		},
 	}
 
 	for _, tt := range tests {
@@ -120,9 +218,10 @@ func TestCommentService_AddComment(t *testing.T) {
 			// Setup film existence check if filmID is valid
 			if tt.filmID > 0 && tt.expectedError != "customer name is required" &&
 				tt.expectedError != "comment text is required" &&
-				tt.expectedError != "customer name too long" {
+				tt.expectedError != "customer name too long" &&
+				tt.expectedError != "customer name must not start with" {
 				if tt.filmExists {
-					mockFilmRepo.On("GetFilmByID", tt.filmID).Return(&models.Film{FilmID: tt.filmID}, tt.filmError)
+					mockFilmRepo.On("GetFilmByID", tt.filmID).Return(&models.Film{FilmID: tt.filmID, Status: models.FilmPublished}, tt.filmError)
 					if tt.filmError == nil {
 						mockCommentRepo.On("AddComment", tt.filmID, tt.commentReq).Return(tt.mockResponse, tt.mockError)
 					}
@@ -136,6 +235,7 @@ func TestCommentService_AddComment(t *testing.T) {
 			if tt.expectedError != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.NotEqual(t, apperrors.Internal, apperrors.KindOf(err))
 				assert.Nil(t, result)
 			} else {
 				require.NoError(t, err)
@@ -148,6 +248,281 @@ func TestCommentService_AddComment(t *testing.T) {
 	}
 }
 
+func TestCommentService_AddCommentAttributesCustomer(t *testing.T) {
+	tests := []struct {
+		name         string
+		customerName string
+	}{
+		{name: "without a name"},
+		{name: "free-text name is dropped", customerName: "Jane"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentRepo := new(MockCommentRepository)
+			mockFilmRepo := new(MockFilmRepository)
+			commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo)
+
+			customerID := 600
+			commentReq := models.CommentRequest{CustomerName: tt.customerName, Comment: "Loved it!"}
+			linkedReq := models.CommentRequest{Comment: "Loved it!", CustomerID: &customerID}
+
+			mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+			mockCommentRepo.On("IsShadowBanned", customerID).Return(false, nil)
+			mockCommentRepo.On("AddComment", 1, linkedReq).Return(&models.Comment{
+				ID: 1, FilmID: 1, CustomerID: &customerID, DisplayName: "Mary S.", Verified: true,
+			}, nil)
+
+			ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: customerID, Role: auth.RoleCustomer})
+			result, err := commentService.AddComment(ctx, 1, commentReq)
+
+			require.NoError(t, err)
+			assert.True(t, result.Verified)
+			assert.Equal(t, "Mary S.", result.DisplayName)
+			mockCommentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+type recordingReplyNotifier struct {
+	replies []notifications.CommentReply
+}
+
+func (n *recordingReplyNotifier) NotifyCommentReply(reply notifications.CommentReply) error {
+	n.replies = append(n.replies, reply)
+	return nil
+}
+
+func TestCommentService_AddReplyNotifiesAuthor(t *testing.T) {
+	tests := []struct {
+		name         string
+		replierID    *int
+		expectNotify bool
+	}{
+		{name: "reply from someone else", expectNotify: true},
+		{name: "reply to own comment", replierID: intPtr(600), expectNotify: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentRepo := new(MockCommentRepository)
+			mockFilmRepo := new(MockFilmRepository)
+			notifier := &recordingReplyNotifier{}
+			commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo,
+				service.WithReplyNotifier(notifier))
+
+			parentID := 10
+			commentReq := models.CommentRequest{CustomerName: "Bob", Comment: "Agreed!", ParentID: &parentID}
+			mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished, Title: "Academy Dinosaur"}, nil)
+			mockCommentRepo.On("AddComment", 1, mock.Anything).Return(&models.Comment{
+				ID: 11, FilmID: 1, CustomerName: "Bob", Comment: "Agreed!", ParentID: &parentID, CustomerID: tt.replierID,
+			}, nil)
+			mockCommentRepo.On("GetCommentAuthor", parentID).
+				Return(&models.CommentAuthor{CustomerID: 600, FirstName: "Jane", Email: "jane@example.com"}, nil)
+
+			_, err := commentService.AddComment(context.Background(), 1, commentReq)
+
+			require.NoError(t, err)
+			if tt.expectNotify {
+				require.Len(t, notifier.replies, 1)
+				assert.Equal(t, "jane@example.com", notifier.replies[0].RecipientEmail)
+				assert.Equal(t, "Academy Dinosaur", notifier.replies[0].FilmTitle)
+			} else {
+				assert.Empty(t, notifier.replies)
+			}
+		})
+	}
+}
+
+type recordingMentionNotifier struct {
+	mentions []notifications.CommentMention
+}
+
+func (n *recordingMentionNotifier) NotifyCommentMention(mention notifications.CommentMention) error {
+	n.mentions = append(n.mentions, mention)
+	return nil
+}
+
+func TestCommentService_AddCommentNotifiesMentions(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockFilmRepo := new(MockFilmRepository)
+	replies := &recordingReplyNotifier{}
+	mentions := &recordingMentionNotifier{}
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo,
+		service.WithReplyNotifier(replies), service.WithMentionNotifier(mentions))
+
+	// 600 wrote the parent comment and is told of the reply instead, 500 is
+	// the author, and bob@700 is an email address rather than a mention.
+	parentID := 10
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: 500, Role: auth.RoleCustomer})
+	text := "@600 @601 agreed, and @601 too. Ask bob@700 or @500"
+	mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished, Title: "Academy Dinosaur"}, nil)
+	mockCommentRepo.On("GetMentionTargets", []int{600, 601, 500}).Return([]models.CommentAuthor{
+		{CustomerID: 500, FirstName: "Bob", Email: "bob@example.com"},
+		{CustomerID: 600, FirstName: "Jane", Email: "jane@example.com"},
+		{CustomerID: 601, FirstName: "Joe", Email: "joe@example.com"},
+	}, nil)
+	mockCommentRepo.On("IsShadowBanned", 500).Return(false, nil)
+	mockCommentRepo.On("AddComment", 1, mock.MatchedBy(func(req models.CommentRequest) bool {
+		return assert.ObjectsAreEqual([]int{600, 601, 500}, req.MentionIDs)
+	})).Return(&models.Comment{
+		ID: 11, FilmID: 1, Comment: text, ParentID: &parentID, CustomerID: intPtr(500), DisplayName: "Bob S.",
+		Mentions: []int{600, 601, 500},
+	}, nil)
+	mockCommentRepo.On("GetCommentAuthor", parentID).
+		Return(&models.CommentAuthor{CustomerID: 600, FirstName: "Jane", Email: "jane@example.com"}, nil)
+
+	_, err := commentService.AddComment(ctx, 1, models.CommentRequest{Comment: text, ParentID: &parentID})
+
+	require.NoError(t, err)
+	require.Len(t, replies.replies, 1)
+	require.Len(t, mentions.mentions, 1)
+	assert.Equal(t, 601, mentions.mentions[0].RecipientID)
+	assert.Equal(t, "Bob S.", mentions.mentions[0].MentionerName)
+	assert.Equal(t, "Academy Dinosaur", mentions.mentions[0].FilmTitle)
+}
+
+func TestCommentService_AddCommentRejectsBadMentions(t *testing.T) {
+	tooMany := ""
+	for id := 1; id <= 11; id++ {
+		tooMany += fmt.Sprintf("@%d ", id)
+	}
+
+	tests := []struct {
+		name          string
+		comment       string
+		targets       []models.CommentAuthor
+		expectedError error
+	}{
+		{
+			name:          "unknown customer",
+			comment:       "@600 and @999",
+			targets:       []models.CommentAuthor{{CustomerID: 600}},
+			expectedError: service.ErrUnknownMention,
+		},
+		{name: "too many customers", comment: tooMany, expectedError: service.ErrTooManyMentions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentRepo := new(MockCommentRepository)
+			mockFilmRepo := new(MockFilmRepository)
+			commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo)
+			mockCommentRepo.On("GetMentionTargets", mock.Anything).Return(tt.targets, nil)
+
+			_, err := commentService.AddComment(context.Background(), 1,
+				models.CommentRequest{CustomerName: "Bob", Comment: tt.comment})
+
+			require.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, apperrors.Invalid, apperrors.KindOf(err))
+			mockCommentRepo.AssertNotCalled(t, "AddComment", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+type recordingEventPublisher struct {
+	events []string
+	data   []any
+}
+
+func (p *recordingEventPublisher) Publish(event string, data any) error {
+	p.events = append(p.events, event)
+	p.data = append(p.data, data)
+	return nil
+}
+
+func TestCommentService_AddCommentPublishesEvent(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockFilmRepo := new(MockFilmRepository)
+	publisher := &recordingEventPublisher{}
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo,
+		service.WithEventPublisher(publisher))
+
+	comment := &models.Comment{ID: 11, FilmID: 1, CustomerName: "Bob", Comment: "Great film"}
+	mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+	mockCommentRepo.On("AddComment", 1, mock.Anything).Return(comment, nil)
+
+	_, err := commentService.AddComment(context.Background(), 1,
+		models.CommentRequest{CustomerName: "Bob", Comment: "Great film"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{webhooks.EventCommentCreated}, publisher.events)
+	assert.Equal(t, []any{comment}, publisher.data)
+}
+
+func TestCommentService_AddCommentCountsPerFilm(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockFilmRepo := new(MockFilmRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo)
+	added := metrics.CommentsAdded.WithLabelValues("42")
+	before := testutil.ToFloat64(added)
+
+	mockFilmRepo.On("GetFilmByID", 42).Return(&models.Film{FilmID: 42, Status: models.FilmPublished}, nil)
+	mockCommentRepo.On("AddComment", 42, mock.Anything).
+		Return(&models.Comment{ID: 12, FilmID: 42, Comment: "Great film"}, nil)
+
+	_, err := commentService.AddComment(context.Background(), 42,
+		models.CommentRequest{CustomerName: "Bob", Comment: "Great film"})
+
+	require.NoError(t, err)
+	assert.InDelta(t, before+1, testutil.ToFloat64(added), 0)
+}
+
+func TestCommentService_AddCommentAwardsCustomerPoints(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockFilmRepo := new(MockFilmRepository)
+	mockLoyaltyRepo := new(MockLoyaltyRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo,
+		service.WithCommentPoints(service.NewLoyaltyService(mockLoyaltyRepo, 5, 100)))
+
+	mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+	mockCommentRepo.On("IsShadowBanned", 600).Return(false, nil)
+	mockCommentRepo.On("AddComment", 1, mock.Anything).
+		Return(&models.Comment{ID: 11, FilmID: 1, CustomerID: intPtr(600), Comment: "Great film"}, nil).Once()
+	mockCommentRepo.On("AddComment", 1, mock.Anything).
+		Return(&models.Comment{ID: 12, FilmID: 1, CustomerName: "Bob", Comment: "Great film"}, nil).Once()
+	mockLoyaltyRepo.On("AwardPoints", 600, 5, models.LoyaltyReasonComment, 11).Return(nil)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: 600, Role: auth.RoleCustomer})
+	_, err := commentService.AddComment(ctx, 1, models.CommentRequest{Comment: "Great film"})
+	require.NoError(t, err)
+	_, err = commentService.AddComment(context.Background(), 1,
+		models.CommentRequest{CustomerName: "Bob", Comment: "Great film"})
+	require.NoError(t, err)
+
+	// Only the logged-in customer's comment earns points.
+	mockLoyaltyRepo.AssertExpectations(t)
+	mockLoyaltyRepo.AssertNumberOfCalls(t, "AwardPoints", 1)
+}
+
+func TestCommentService_AddCommentRecordsGuest(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockFilmRepo := new(MockFilmRepository)
+	mockGuestRepo := new(MockGuestRepository)
+	guestService := service.NewGuestService(mockGuestRepo, auth.NewTokenIssuer(auth.RoleGuest, "secret", time.Hour))
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo, service.WithGuestComments(guestService))
+
+	// Guests still comment under a free-text name until they register.
+	commentReq := models.CommentRequest{CustomerName: "Bob", Comment: "Great film"}
+	mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+	mockCommentRepo.On("AddComment", 1, commentReq).
+		Return(&models.Comment{ID: 11, FilmID: 1, CustomerName: "Bob", Comment: "Great film"}, nil)
+	mockGuestRepo.On("RecordComment", 42, 11).Return(nil)
+
+	result, err := commentService.AddComment(guestContext(42), 1, commentReq)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.CustomerID)
+	mockGuestRepo.AssertExpectations(t)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// firstCommentPage is the page of comments selected by empty filters.
+var firstCommentPage = pagination.Params{Limit: models.CommentLimits.DefaultLimit}
+
 func TestCommentService_GetCommentsByFilmID(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -168,8 +543,9 @@ func TestCommentService_GetCommentsByFilmID(t *testing.T) {
 				{ID: 2, FilmID: 1, CustomerName: "Jane Smith", Comment: "Loved it!"},
 			},
 			expectedResult: []models.Comment{
-				{ID: 1, FilmID: 1, CustomerName: "John Doe", Comment: "Great movie!"},
-				{ID: 2, FilmID: 1, CustomerName: "Jane Smith", Comment: "Loved it!"},
+				{ID: 1, FilmID: 1, CustomerName: "John Doe", Comment: "Great movie!",
+					CommentHTML: "<p>Great movie!</p>\n"},
+				{ID: 2, FilmID: 1, CustomerName: "Jane Smith", Comment: "Loved it!", CommentHTML: "<p>Loved it!</p>\n"},
 			},
 		},
 		{
@@ -193,16 +569,17 @@ func TestCommentService_GetCommentsByFilmID(t *testing.T) {
 
 			if tt.filmID > 0 {
 				if tt.filmExists {
-					mockFilmRepo.On("GetFilmByID", tt.filmID).Return(&models.Film{FilmID: tt.filmID}, tt.filmError)
+					mockFilmRepo.On("GetFilmByID", tt.filmID).Return(&models.Film{FilmID: tt.filmID, Status: models.FilmPublished}, tt.filmError)
 					if tt.filmError == nil {
-						mockCommentRepo.On("GetCommentsByFilmID", tt.filmID).Return(tt.mockResponse, tt.mockError)
+						mockCommentRepo.On("GetCommentsByFilmID", tt.filmID, 0, firstCommentPage.Limit, (*pagination.Cursor)(nil)).
+							Return(pagination.New(tt.mockResponse, len(tt.mockResponse), firstCommentPage), tt.mockError)
 					}
 				} else {
 					mockFilmRepo.On("GetFilmByID", tt.filmID).Return(nil, tt.filmError)
 				}
 			}
 
-			result, err := commentService.GetCommentsByFilmID(context.Background(), tt.filmID)
+			result, err := commentService.GetCommentsByFilmID(context.Background(), tt.filmID, models.CommentFilters{})
 
 			if tt.expectedError != "" {
 				require.Error(t, err)
@@ -210,7 +587,7 @@ func TestCommentService_GetCommentsByFilmID(t *testing.T) {
 				assert.Nil(t, result)
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedResult, result)
+				assert.Equal(t, tt.expectedResult, result.Items)
 			}
 
 			mockFilmRepo.AssertExpectations(t)
@@ -218,3 +595,290 @@ func TestCommentService_GetCommentsByFilmID(t *testing.T) {
 		})
 	}
 }
+
+func TestCommentService_GetCommentsByFilmIDAfterCursor(t *testing.T) {
+	mockFilmRepo := new(MockFilmRepository)
+	mockCommentRepo := new(MockCommentRepository)
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo)
+	after := pagination.Cursor{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ID: 7}
+	params := pagination.Params{Limit: 2}
+	mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+	mockCommentRepo.On("GetCommentsByFilmID", 1, 0, 2, &after).
+		Return(pagination.New([]models.Comment{{ID: 6, FilmID: 1, Comment: "Older"}}, 3, params), nil)
+
+	result, err := commentService.GetCommentsByFilmID(context.Background(), 1,
+		models.CommentFilters{After: after.Encode(), Limit: 2})
+
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, 6, result.Items[0].ID)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentService_GetCommentsByFilmIDInvalidPage(t *testing.T) {
+	cursor := pagination.Cursor{Time: time.Now(), ID: 1}.Encode()
+	tests := []struct {
+		name    string
+		filters models.CommentFilters
+	}{
+		{"limit too large", models.CommentFilters{Limit: 101}},
+		{"malformed cursor", models.CommentFilters{After: "not a cursor"}},
+		{"numbered page", models.CommentFilters{Page: 2, Limit: 10}},
+		{"cursor with page", models.CommentFilters{After: cursor, Page: 2, Limit: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentService := service.NewCommentService(new(MockCommentRepository), new(MockFilmRepository))
+
+			_, err := commentService.GetCommentsByFilmID(context.Background(), 1, tt.filters)
+
+			require.Error(t, err)
+			assert.Equal(t, apperrors.Invalid, apperrors.KindOf(err))
+		})
+	}
+}
+
+func TestCommentService_AddCommentLocked(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockFilmRepo := new(MockFilmRepository)
+	publisher := &recordingEventPublisher{}
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo,
+		service.WithEventPublisher(publisher))
+
+	commentReq := models.CommentRequest{CustomerName: "Bob", Comment: "Great film"}
+	mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+	mockCommentRepo.On("AddComment", 1, commentReq).Return(nil, repository.ErrCommentsLocked)
+
+	_, err := commentService.AddComment(context.Background(), 1, commentReq)
+
+	require.ErrorIs(t, err, repository.ErrCommentsLocked)
+	assert.Empty(t, publisher.events)
+}
+
+func TestCommentService_AddCommentShadowBanned(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockFilmRepo := new(MockFilmRepository)
+	mockLoyaltyRepo := new(MockLoyaltyRepository)
+	publisher := &recordingEventPublisher{}
+	replies := &recordingReplyNotifier{}
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo,
+		service.WithEventPublisher(publisher), service.WithReplyNotifier(replies),
+		service.WithCommentPoints(service.NewLoyaltyService(mockLoyaltyRepo, 5, 100)))
+
+	parentID := 10
+	mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+	mockCommentRepo.On("IsShadowBanned", 600).Return(true, nil)
+	mockCommentRepo.On("AddComment", 1, mock.Anything).Return(&models.Comment{
+		ID: 11, FilmID: 1, CustomerID: intPtr(600), Comment: "Terrible", ParentID: &parentID,
+	}, nil)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: 600, Role: auth.RoleCustomer})
+	result, err := commentService.AddComment(ctx, 1, models.CommentRequest{Comment: "Terrible", ParentID: &parentID})
+
+	// The comment looks posted to its author, but nobody else hears of it.
+	require.NoError(t, err)
+	assert.Equal(t, 11, result.ID)
+	assert.Empty(t, publisher.events)
+	assert.Empty(t, replies.replies)
+	mockCommentRepo.AssertNotCalled(t, "GetCommentAuthor", parentID)
+	mockLoyaltyRepo.AssertNotCalled(t, "AwardPoints", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_GetCommentsByFilmIDShowsCustomerTheirOwn(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   *auth.Claims
+		viewerID int
+	}{
+		{name: "customer", claims: &auth.Claims{Subject: 600, Role: auth.RoleCustomer}, viewerID: 600},
+		{name: "guest", claims: &auth.Claims{Subject: 42, Role: auth.RoleGuest}},
+		{name: "anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFilmRepo := new(MockFilmRepository)
+			mockCommentRepo := new(MockCommentRepository)
+			commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo)
+			mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+			mockCommentRepo.On("GetCommentsByFilmID", 1, tt.viewerID, 20, (*pagination.Cursor)(nil)).
+				Return(pagination.New([]models.Comment{}, 0, pagination.Params{Limit: 20}), nil)
+
+			ctx := context.Background()
+			if tt.claims != nil {
+				ctx = auth.WithClaims(ctx, tt.claims)
+			}
+			_, err := commentService.GetCommentsByFilmID(ctx, 1, models.CommentFilters{Limit: 20})
+
+			require.NoError(t, err)
+			mockCommentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCommentService_SetShadowBanned(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	commentService := service.NewCommentService(mockCommentRepo, new(MockFilmRepository))
+	status := &models.ShadowBanStatus{CustomerID: 600, Banned: true}
+	mockCommentRepo.On("SetShadowBanned", 600, true).Return(status, nil)
+	mockCommentRepo.On("SetShadowBanned", 999, true).Return(nil, repository.ErrCustomerNotFound)
+
+	result, err := commentService.SetShadowBanned(context.Background(), 600, true)
+	require.NoError(t, err)
+	assert.Equal(t, status, result)
+
+	_, err = commentService.SetShadowBanned(context.Background(), 999, true)
+	require.ErrorIs(t, err, repository.ErrCustomerNotFound)
+}
+
+func TestCommentService_GetCommentSummaryCached(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	summaryCache := cache.NewMemoryCache(time.Hour)
+	commentService := service.NewCommentService(mockCommentRepo, new(MockFilmRepository),
+		service.WithCommentSummaryCache(summaryCache))
+	summary := &models.CommentSummary{FilmID: 1, CommentCount: 3}
+	mockCommentRepo.On("GetCommentSummary", 1).Return(summary, nil).Once()
+
+	first, err := commentService.GetCommentSummary(context.Background(), 1)
+	require.NoError(t, err)
+	second, err := commentService.GetCommentSummary(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	mockCommentRepo.AssertNumberOfCalls(t, "GetCommentSummary", 1)
+}
+
+func TestCommentService_RefreshCommentSummariesEvictsCache(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	summaryCache := cache.NewMemoryCache(time.Hour)
+	commentService := service.NewCommentService(mockCommentRepo, new(MockFilmRepository),
+		service.WithCommentSummaryCache(summaryCache))
+	mockCommentRepo.On("GetCommentSummary", 1).Return(&models.CommentSummary{FilmID: 1}, nil).Twice()
+	mockCommentRepo.On("RefreshCommentSummaries", mock.Anything, 10).Return(nil)
+
+	_, err := commentService.GetCommentSummary(context.Background(), 1)
+	require.NoError(t, err)
+	require.NoError(t, commentService.RefreshCommentSummaries(context.Background()))
+	_, err = commentService.GetCommentSummary(context.Background(), 1)
+	require.NoError(t, err)
+
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestCommentService_GetCommentSummaryNotComputed(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	commentService := service.NewCommentService(mockCommentRepo, new(MockFilmRepository))
+	mockCommentRepo.On("GetCommentSummary", 1).Return(nil, repository.ErrCommentSummaryNotFound)
+
+	_, err := commentService.GetCommentSummary(context.Background(), 1)
+
+	assert.ErrorIs(t, err, repository.ErrCommentSummaryNotFound)
+}
+
+type memoryAttachmentStore struct {
+	files map[string][]byte
+}
+
+func (s *memoryAttachmentStore) Put(_ context.Context, key string, data []byte) error {
+	s.files[key] = data
+	return nil
+}
+
+func (s *memoryAttachmentStore) Delete(_ context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memoryAttachmentStore) URL(key string) string {
+	return "/media/" + key
+}
+
+type stubScanner struct {
+	err error
+}
+
+func (s stubScanner) Scan(context.Context, []byte) error {
+	return s.err
+}
+
+// pngAttachment returns a small PNG to attach to comments.
+func pngAttachment(t *testing.T) *models.CommentUpload {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8))))
+	return &models.CommentUpload{Data: buf.Bytes()}
+}
+
+func TestCommentService_AddCommentStoresAttachment(t *testing.T) {
+	mockCommentRepo := new(MockCommentRepository)
+	mockFilmRepo := new(MockFilmRepository)
+	store := &memoryAttachmentStore{files: map[string][]byte{}}
+	commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo,
+		service.WithCommentAttachments(store, stubScanner{}, 1<<20))
+	attachment := pngAttachment(t)
+	var stored models.CommentRequest
+	added := &models.Comment{ID: 1, FilmID: 1}
+	mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+	mockCommentRepo.On("AddComment", 1, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(models.CommentRequest)
+		added.AttachmentKey, added.ThumbnailKey = stored.AttachmentKey, stored.ThumbnailKey
+	}).Return(added, nil)
+
+	comment, err := commentService.AddComment(context.Background(), 1,
+		models.CommentRequest{CustomerName: "Bob", Comment: "Still", Attachment: attachment})
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{32}\.png$`, stored.AttachmentKey)
+	assert.Equal(t, attachment.Data, store.files[stored.AttachmentKey])
+	assert.Contains(t, store.files, stored.ThumbnailKey)
+	assert.Equal(t, "/media/"+stored.AttachmentKey, comment.AttachmentURL)
+	assert.Equal(t, "/media/"+stored.ThumbnailKey, comment.ThumbnailURL)
+}
+
+func TestCommentService_AddCommentRejectsAttachment(t *testing.T) {
+	store := &memoryAttachmentStore{files: map[string][]byte{}}
+	tests := []struct {
+		name          string
+		opts          []service.CommentServiceOption
+		attachment    *models.CommentUpload
+		expectedError error
+	}{
+		{
+			name:          "attachments disabled",
+			attachment:    pngAttachment(t),
+			expectedError: service.ErrAttachmentsDisabled,
+		},
+		{
+			name:          "not an image",
+			opts:          []service.CommentServiceOption{service.WithCommentAttachments(store, nil, 1<<20)},
+			attachment:    &models.CommentUpload{Data: []byte("%PDF-1.4")},
+			expectedError: attachments.ErrUnsupportedType,
+		},
+		{
+			name: "infected",
+			opts: []service.CommentServiceOption{
+				service.WithCommentAttachments(store, stubScanner{err: attachments.ErrInfected}, 1<<20),
+			},
+			attachment:    pngAttachment(t),
+			expectedError: attachments.ErrInfected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommentRepo := new(MockCommentRepository)
+			mockFilmRepo := new(MockFilmRepository)
+			commentService := service.NewCommentService(mockCommentRepo, mockFilmRepo, tt.opts...)
+			mockFilmRepo.On("GetFilmByID", 1).Return(&models.Film{FilmID: 1, Status: models.FilmPublished}, nil)
+
+			_, err := commentService.AddComment(context.Background(), 1,
+				models.CommentRequest{CustomerName: "Bob", Comment: "Still", Attachment: tt.attachment})
+
+			require.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, apperrors.Invalid, apperrors.KindOf(err))
+			assert.Empty(t, store.files)
+			mockCommentRepo.AssertNotCalled(t, "AddComment", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockCouponRepository struct {
+	mock.Mock
+}
+
+func (m *MockCouponRepository) CreateCoupon(_ context.Context, couponReq models.CouponRequest) (*models.Coupon, error) {
+	args := m.Called(couponReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) ListCoupons(_ context.Context) ([]models.Coupon, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepository) ValidateCoupon(
+	_ context.Context,
+	code string,
+	customerID int,
+	amount float64,
+) (*models.CouponQuote, error) {
+	args := m.Called(code, customerID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CouponQuote), args.Error(1)
+}
+
+func (m *MockCouponRepository) RedeemCoupon(
+	_ context.Context,
+	code string,
+	customerID int,
+	amount float64,
+) (*models.CouponRedemption, error) {
+	args := m.Called(code, customerID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CouponRedemption), args.Error(1)
+}
+
+func TestCouponService_CodesAreCaseInsensitive(t *testing.T) {
+	mockRepo := new(MockCouponRepository)
+	couponService := service.NewCouponService(mockRepo)
+	amountOff := 5.0
+	mockRepo.On("CreateCoupon", models.CouponRequest{Code: "FIVEOFF", AmountOff: &amountOff}).
+		Return(&models.Coupon{ID: 1, Code: "FIVEOFF"}, nil)
+	mockRepo.On("ValidateCoupon", "FIVEOFF", 600, 9.98).
+		Return(&models.CouponQuote{Code: "FIVEOFF", Amount: 9.98, Discount: 5, Total: 4.98}, nil)
+	mockRepo.On("RedeemCoupon", "FIVEOFF", 600, 9.98).
+		Return(&models.CouponRedemption{ID: 1, Code: "FIVEOFF", CustomerID: 600}, nil)
+
+	ctx := context.Background()
+
+	_, err := couponService.CreateCoupon(ctx, models.CouponRequest{Code: "FiveOff", AmountOff: &amountOff})
+	require.NoError(t, err)
+	_, err = couponService.ValidateCoupon(ctx, "fiveoff", 600, 9.98)
+	require.NoError(t, err)
+	_, err = couponService.RedeemCoupon(ctx, "fiveOFF", 600, 9.98)
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
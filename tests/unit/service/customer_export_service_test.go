@@ -0,0 +1,229 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockCustomerExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomerExportRepository) CreateExport(
+	_ context.Context,
+	customerID int,
+	format string,
+) (*models.CustomerExport, error) {
+	args := m.Called(customerID, format)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerExport), args.Error(1)
+}
+
+func (m *MockCustomerExportRepository) GetLatestExport(
+	_ context.Context,
+	customerID int,
+	format string,
+	since time.Time,
+) (*models.CustomerExport, error) {
+	args := m.Called(customerID, format, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerExport), args.Error(1)
+}
+
+func (m *MockCustomerExportRepository) GetExport(
+	_ context.Context,
+	customerID, exportID int,
+) (*models.CustomerExport, error) {
+	args := m.Called(customerID, exportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerExport), args.Error(1)
+}
+
+func (m *MockCustomerExportRepository) GetExportFile(_ context.Context, exportID int) ([]byte, error) {
+	args := m.Called(exportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockCustomerExportRepository) CompleteExport(_ context.Context, exportID int, data []byte) error {
+	return m.Called(exportID, data).Error(0)
+}
+
+func (m *MockCustomerExportRepository) GetCustomerData(
+	_ context.Context,
+	customerID int,
+) (*models.CustomerData, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerData), args.Error(1)
+}
+
+// recordingQueue keeps enqueued jobs' payloads, encoded as the persistent
+// queue would.
+type recordingQueue struct {
+	kinds    []string
+	payloads []json.RawMessage
+}
+
+func (q *recordingQueue) Enqueue(kind string, payload any) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	q.kinds = append(q.kinds, kind)
+	q.payloads = append(q.payloads, encoded)
+	return nil
+}
+
+func testCustomerData() *models.CustomerData {
+	return &models.CustomerData{
+		Profile: models.CustomerProfile{
+			Customer: models.Customer{CustomerID: 600, FirstName: "Mary", LastName: "Smith"},
+			City:     "Sasebo",
+		},
+		Comments: []models.CustomerDataComment{{ID: 1, FilmID: 8, Comment: "Loved it"}},
+		Rentals:  []models.RentalEvent{{RentalID: 76, FilmID: 8, CustomerID: 600}},
+	}
+}
+
+func TestCustomerExportService_RequestExportQueuesJob(t *testing.T) {
+	mockRepo := new(MockCustomerExportRepository)
+	queue := &recordingQueue{}
+	exportService := service.NewCustomerExportService(mockRepo, queue, 24*time.Hour)
+	mockRepo.On("GetLatestExport", 600, "json", mock.Anything).Return(nil, repository.ErrCustomerExportNotFound)
+	mockRepo.On("CreateExport", 600, "json").Return(&models.CustomerExport{
+		ID: 3, CustomerID: 600, Format: "json", Status: models.CustomerExportStatusPending,
+	}, nil)
+
+	export, err := exportService.RequestExport(context.Background(), 600, "json")
+
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/customers/600/exports/3", export.StatusURL)
+	assert.Equal(t, "/api/v1/customers/600/export?format=json", export.DownloadURL)
+	assert.Equal(t, []string{service.CustomerExportJobKind}, queue.kinds)
+	assert.JSONEq(t, `{"export_id": 3, "customer_id": 600, "format": "json"}`, string(queue.payloads[0]))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomerExportService_RequestExportReusesRecentExport(t *testing.T) {
+	mockRepo := new(MockCustomerExportRepository)
+	queue := &recordingQueue{}
+	exportService := service.NewCustomerExportService(mockRepo, queue, 24*time.Hour)
+	mockRepo.On("GetLatestExport", 600, "zip", mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) > 23*time.Hour && time.Since(since) < 25*time.Hour
+	})).Return(&models.CustomerExport{
+		ID: 3, CustomerID: 600, Format: "zip", Status: models.CustomerExportStatusReady,
+	}, nil)
+
+	export, err := exportService.RequestExport(context.Background(), 600, "zip")
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, export.ID)
+	assert.Empty(t, queue.kinds)
+	mockRepo.AssertNotCalled(t, "CreateExport", mock.Anything, mock.Anything)
+}
+
+func TestCustomerExportService_SignsReadyExportDownloads(t *testing.T) {
+	mockRepo := new(MockCustomerExportRepository)
+	signer := auth.NewURLSigner("secret", time.Hour, "https://api.example.com")
+	exportService := service.NewCustomerExportService(mockRepo, &recordingQueue{}, time.Hour,
+		service.WithSignedExportURLs(signer))
+	mockRepo.On("GetExport", 600, 3).Return(&models.CustomerExport{
+		ID: 3, CustomerID: 600, Format: "zip", Status: models.CustomerExportStatusReady,
+	}, nil).Once()
+	mockRepo.On("GetExport", 600, 4).Return(&models.CustomerExport{
+		ID: 4, CustomerID: 600, Format: "zip", Status: models.CustomerExportStatusPending,
+	}, nil).Once()
+
+	ready, err := exportService.GetExport(context.Background(), 600, 3)
+	require.NoError(t, err)
+	pending, err := exportService.GetExport(context.Background(), 600, 4)
+	require.NoError(t, err)
+
+	signed, err := url.Parse(ready.SignedDownloadURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/customers/600/export", signed.Path)
+	assert.Equal(t, "zip", signed.Query().Get("format"))
+	assert.NoError(t, signer.Verify(signed))
+	assert.Empty(t, pending.SignedDownloadURL)
+}
+
+func TestCustomerExportService_HandleExportJobJSON(t *testing.T) {
+	mockRepo := new(MockCustomerExportRepository)
+	exportService := service.NewCustomerExportService(mockRepo, &recordingQueue{}, time.Hour)
+	mockRepo.On("GetCustomerData", 600).Return(testCustomerData(), nil)
+	var file []byte
+	mockRepo.On("CompleteExport", 3, mock.Anything).Run(func(args mock.Arguments) {
+		file = args.Get(1).([]byte)
+	}).Return(nil)
+
+	err := exportService.HandleExportJob(context.Background(),
+		json.RawMessage(`{"export_id": 3, "customer_id": 600, "format": "json"}`))
+
+	require.NoError(t, err)
+	var data models.CustomerData
+	require.NoError(t, json.Unmarshal(file, &data))
+	assert.Equal(t, "Mary", data.Profile.FirstName)
+	assert.Equal(t, "Sasebo", data.Profile.City)
+	assert.Len(t, data.Comments, 1)
+	assert.Len(t, data.Rentals, 1)
+	assert.False(t, data.ExportedAt.IsZero())
+}
+
+func TestCustomerExportService_HandleExportJobZIP(t *testing.T) {
+	mockRepo := new(MockCustomerExportRepository)
+	exportService := service.NewCustomerExportService(mockRepo, &recordingQueue{}, time.Hour)
+	mockRepo.On("GetCustomerData", 600).Return(testCustomerData(), nil)
+	var file []byte
+	mockRepo.On("CompleteExport", 3, mock.Anything).Run(func(args mock.Arguments) {
+		file = args.Get(1).([]byte)
+	}).Return(nil)
+
+	err := exportService.HandleExportJob(context.Background(),
+		json.RawMessage(`{"export_id": 3, "customer_id": 600, "format": "zip"}`))
+
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"profile.json", "comments.json", "rentals.json"}, names)
+}
+
+func TestCustomerExportService_HandleExportJobSkipsReplacedExport(t *testing.T) {
+	mockRepo := new(MockCustomerExportRepository)
+	exportService := service.NewCustomerExportService(mockRepo, &recordingQueue{}, time.Hour)
+	mockRepo.On("GetCustomerData", 600).Return(testCustomerData(), nil)
+	mockRepo.On("CompleteExport", 3, mock.Anything).Return(repository.ErrCustomerExportNotFound)
+
+	err := exportService.HandleExportJob(context.Background(),
+		json.RawMessage(`{"export_id": 3, "customer_id": 600, "format": "json"}`))
+
+	require.NoError(t, err)
+}
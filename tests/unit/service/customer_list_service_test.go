@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockCustomerListRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomerListRepository) CreateList(
+	_ context.Context,
+	customerID int,
+	listReq models.CustomerListRequest,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(customerID, listReq))
+}
+
+func (m *MockCustomerListRepository) ListCustomerLists(
+	_ context.Context,
+	customerID int,
+) ([]models.CustomerList, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CustomerList), args.Error(1)
+}
+
+func (m *MockCustomerListRepository) GetList(_ context.Context, listID int) (*models.CustomerList, error) {
+	return m.list(m.Called(listID))
+}
+
+func (m *MockCustomerListRepository) UpdateList(
+	_ context.Context,
+	customerID, listID int,
+	listReq models.CustomerListRequest,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(customerID, listID, listReq))
+}
+
+func (m *MockCustomerListRepository) DeleteList(_ context.Context, customerID, listID int) error {
+	return m.Called(customerID, listID).Error(0)
+}
+
+func (m *MockCustomerListRepository) AddListEntry(
+	_ context.Context,
+	customerID, listID, filmID int,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(customerID, listID, filmID))
+}
+
+func (m *MockCustomerListRepository) RemoveListEntry(
+	_ context.Context,
+	customerID, listID, filmID int,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(customerID, listID, filmID))
+}
+
+func (m *MockCustomerListRepository) ReorderListEntries(
+	_ context.Context,
+	customerID, listID int,
+	filmIDs []int,
+) (*models.CustomerList, error) {
+	return m.list(m.Called(customerID, listID, filmIDs))
+}
+
+func (m *MockCustomerListRepository) list(args mock.Arguments) (*models.CustomerList, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerList), args.Error(1)
+}
+
+func TestCustomerListService_CreateListIsPrivateByDefault(t *testing.T) {
+	mockRepo := new(MockCustomerListRepository)
+	listService := service.NewCustomerListService(mockRepo)
+	mockRepo.On("CreateList", 600, models.CustomerListRequest{Name: "Best 80s Horror", Visibility: "private"}).
+		Return(&models.CustomerList{ID: 1, CustomerID: 600, Visibility: "private"}, nil)
+	mockRepo.On("CreateList", 600, models.CustomerListRequest{Name: "Comfort Films", Visibility: "public"}).
+		Return(&models.CustomerList{ID: 2, CustomerID: 600, Visibility: "public"}, nil)
+
+	_, err := listService.CreateList(context.Background(), 600, models.CustomerListRequest{Name: "Best 80s Horror"})
+	require.NoError(t, err)
+	_, err = listService.CreateList(context.Background(), 600,
+		models.CustomerListRequest{Name: "Comfort Films", Visibility: "public"})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomerListService_GetSharedList(t *testing.T) {
+	tests := []struct {
+		name          string
+		visibility    string
+		claims        *auth.Claims
+		expectedError error
+	}{
+		{name: "public list, anonymous", visibility: models.ListVisibilityPublic},
+		{
+			name:          "private list, anonymous",
+			visibility:    models.ListVisibilityPrivate,
+			expectedError: repository.ErrListNotFound,
+		},
+		{
+			name:       "private list, owner",
+			visibility: models.ListVisibilityPrivate,
+			claims:     &auth.Claims{Subject: 600, Role: auth.RoleCustomer},
+		},
+		{
+			name:          "private list, another customer",
+			visibility:    models.ListVisibilityPrivate,
+			claims:        &auth.Claims{Subject: 601, Role: auth.RoleCustomer},
+			expectedError: repository.ErrListNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCustomerListRepository)
+			listService := service.NewCustomerListService(mockRepo)
+			mockRepo.On("GetList", 7).Return(&models.CustomerList{ID: 7, CustomerID: 600, Visibility: tt.visibility}, nil)
+
+			ctx := context.Background()
+			if tt.claims != nil {
+				ctx = auth.WithClaims(ctx, tt.claims)
+			}
+			list, err := listService.GetSharedList(ctx, 7)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 7, list.ID)
+		})
+	}
+}
+
+func TestCustomerListService_GetCustomerListHidesOtherCustomersLists(t *testing.T) {
+	mockRepo := new(MockCustomerListRepository)
+	listService := service.NewCustomerListService(mockRepo)
+	mockRepo.On("GetList", 7).Return(&models.CustomerList{ID: 7, CustomerID: 600, Visibility: "public"}, nil)
+
+	_, err := listService.GetCustomerList(context.Background(), 600, 7)
+	require.NoError(t, err)
+	_, err = listService.GetCustomerList(context.Background(), 601, 7)
+	require.ErrorIs(t, err, repository.ErrListNotFound)
+}
@@ -0,0 +1,219 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockCustomerRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomerRepository) CreateCustomer(
+	_ context.Context,
+	registerReq models.CustomerRegisterRequest,
+	passwordHash string,
+) (*models.Customer, error) {
+	args := m.Called(registerReq, passwordHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *MockCustomerRepository) GetCustomerCredentials(
+	_ context.Context,
+	email string,
+) (*models.Customer, string, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*models.Customer), args.String(1), args.Error(2)
+}
+
+func (m *MockCustomerRepository) EraseCustomerData(
+	_ context.Context,
+	customerID int,
+	entry models.AuditEntry,
+) (*models.CustomerErasure, error) {
+	args := m.Called(customerID, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerErasure), args.Error(1)
+}
+
+func TestCustomerService_Register(t *testing.T) {
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo,
+		auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour))
+	registerReq := models.CustomerRegisterRequest{Email: "jane@example.com", Password: "s3cret-pass"}
+
+	var storedHash string
+	mockRepo.On("CreateCustomer", registerReq, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { storedHash = args.String(1) }).
+		Return(&models.Customer{CustomerID: 600, Email: "jane@example.com", Active: true}, nil)
+
+	customer, err := customerService.Register(context.Background(), registerReq)
+
+	require.NoError(t, err)
+	assert.Equal(t, 600, customer.CustomerID)
+	assert.True(t, auth.CheckPassword(storedHash, registerReq.Password))
+}
+
+func TestCustomerService_RegisterEmailTaken(t *testing.T) {
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo,
+		auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour))
+	mockRepo.On("CreateCustomer", mock.Anything, mock.Anything).Return(nil, repository.ErrEmailTaken)
+
+	_, err := customerService.Register(context.Background(),
+		models.CustomerRegisterRequest{Email: "jane@example.com", Password: "s3cret-pass"})
+
+	require.ErrorIs(t, err, repository.ErrEmailTaken)
+}
+
+func (m *MockCustomerRepository) MergeCustomers(
+	_ context.Context,
+	sourceID, targetID int,
+	entry models.AuditEntry,
+) (*models.CustomerMerge, error) {
+	args := m.Called(sourceID, targetID, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerMerge), args.Error(1)
+}
+
+func TestCustomerService_RegisterMergesGuest(t *testing.T) {
+	listID := 7
+	tests := []struct {
+		name          string
+		mergeErr      error
+		expectedMerge *models.GuestMerge
+	}{
+		{name: "merged", expectedMerge: &models.GuestMerge{GuestID: 42, CommentsLinked: 2, WatchlistFilms: 3,
+			ListID: &listID}},
+		{name: "already merged", mergeErr: repository.ErrGuestSessionMerged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCustomerRepository)
+			mockGuestRepo := new(MockGuestRepository)
+			guestService := service.NewGuestService(mockGuestRepo,
+				auth.NewTokenIssuer(auth.RoleGuest, "secret", time.Hour))
+			customerService := service.NewCustomerService(mockRepo,
+				auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour), service.WithGuestMerge(guestService))
+			mockRepo.On("CreateCustomer", mock.Anything, mock.Anything).
+				Return(&models.Customer{CustomerID: 600, Email: "jane@example.com", Active: true}, nil)
+			if tt.mergeErr != nil {
+				mockGuestRepo.On("MergeSession", 42, 600).Return(nil, tt.mergeErr)
+			} else {
+				mockGuestRepo.On("MergeSession", 42, 600).Return(tt.expectedMerge, nil)
+			}
+
+			// A failed merge still registers the customer.
+			customer, err := customerService.Register(guestContext(42),
+				models.CustomerRegisterRequest{Email: "jane@example.com", Password: "s3cret-pass"})
+
+			require.NoError(t, err)
+			assert.Equal(t, 600, customer.CustomerID)
+			assert.Equal(t, tt.expectedMerge, customer.GuestMerge)
+			mockGuestRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCustomerService_Login(t *testing.T) {
+	hash, err := auth.HashPassword("s3cret-pass")
+	require.NoError(t, err)
+	tokens := auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour)
+
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo, tokens)
+	mockRepo.On("GetCustomerCredentials", "jane@example.com").
+		Return(&models.Customer{CustomerID: 600, Active: true}, hash, nil)
+	mockRepo.On("GetCustomerCredentials", "nobody@example.com").
+		Return(nil, "", repository.ErrCustomerNotFound)
+
+	token, err := customerService.Login(context.Background(),
+		models.CustomerLoginRequest{Email: "jane@example.com", Password: "s3cret-pass"})
+	require.NoError(t, err)
+	claims, err := tokens.Verify(token.Token)
+	require.NoError(t, err)
+	assert.Equal(t, 600, claims.Subject)
+
+	_, err = customerService.Login(context.Background(),
+		models.CustomerLoginRequest{Email: "jane@example.com", Password: "wrong"})
+	require.ErrorIs(t, err, service.ErrInvalidCredentials)
+
+	_, err = customerService.Login(context.Background(),
+		models.CustomerLoginRequest{Email: "nobody@example.com", Password: "s3cret-pass"})
+	require.ErrorIs(t, err, service.ErrInvalidCredentials)
+}
+
+func TestCustomerService_EraseDataAuditsCaller(t *testing.T) {
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo,
+		auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour))
+	staffID := 3
+	mockRepo.On("EraseCustomerData", 600, models.AuditEntry{ActorRole: auth.RoleStaff, ActorID: &staffID}).
+		Return(&models.CustomerErasure{CustomerID: 600, CommentsAnonymized: 2, CredentialsDeleted: true}, nil)
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: 3, Role: auth.RoleStaff})
+
+	erasure, err := customerService.EraseData(ctx, 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, erasure.CommentsAnonymized)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomerService_EraseDataWithoutCaller(t *testing.T) {
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo,
+		auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour))
+	mockRepo.On("EraseCustomerData", 999, models.AuditEntry{ActorRole: models.AuditActorSystem}).
+		Return(nil, repository.ErrCustomerNotFound)
+
+	_, err := customerService.EraseData(context.Background(), 999)
+
+	require.ErrorIs(t, err, repository.ErrCustomerNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomerService_MergeCustomers(t *testing.T) {
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo,
+		auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour))
+	mockRepo.On("MergeCustomers", 600, 601, models.AuditEntry{ActorRole: models.AuditActorSystem}).
+		Return(&models.CustomerMerge{SourceID: 600, TargetID: 601, RentalsMoved: 5}, nil)
+
+	merge, err := customerService.MergeCustomers(context.Background(), 600, 601)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, merge.RentalsMoved)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomerService_MergeCustomersIntoSelf(t *testing.T) {
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo,
+		auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour))
+
+	_, err := customerService.MergeCustomers(context.Background(), 600, 600)
+
+	require.ErrorIs(t, err, service.ErrMergeSameCustomer)
+	mockRepo.AssertNotCalled(t, "MergeCustomers", mock.Anything, mock.Anything, mock.Anything)
+}
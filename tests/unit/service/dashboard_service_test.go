@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockDashboardRepository struct {
+	mock.Mock
+}
+
+func (m *MockDashboardRepository) CountFilms(_ context.Context) (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDashboardRepository) CountRentalsToday(_ context.Context) (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDashboardRepository) CountPendingComments(_ context.Context) (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDashboardRepository) GetRevenueThisMonth(_ context.Context) (float64, error) {
+	args := m.Called()
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockDashboardRepository) GetCategoryStats(_ context.Context, categoryID int) (*models.CategoryStats, error) {
+	args := m.Called(categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategoryStats), args.Error(1)
+}
+
+func TestDashboardService_GetDashboard(t *testing.T) {
+	mockRepo := new(MockDashboardRepository)
+	mockRepo.On("CountFilms").Return(1000, nil)
+	mockRepo.On("CountRentalsToday").Return(42, nil)
+	mockRepo.On("CountPendingComments").Return(7, nil)
+	mockRepo.On("GetRevenueThisMonth").Return(1234.5, nil)
+	svc := service.NewDashboardService(mockRepo, func() float64 { return 0.02 })
+
+	dashboard, err := svc.GetDashboard(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1000, dashboard.FilmsCount)
+	assert.Equal(t, 42, dashboard.RentalsToday)
+	assert.Equal(t, 7, dashboard.PendingComments)
+	assert.InDelta(t, 1234.5, dashboard.RevenueThisMonth, 0.001)
+	assert.InDelta(t, 0.02, dashboard.ErrorRate, 0.0001)
+	assert.False(t, dashboard.GeneratedAt.IsZero())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDashboardService_GetDashboardQueryFails(t *testing.T) {
+	mockRepo := new(MockDashboardRepository)
+	mockRepo.On("CountFilms").Return(1000, nil)
+	mockRepo.On("CountRentalsToday").Return(0, errors.New("boom"))
+	mockRepo.On("CountPendingComments").Return(7, nil)
+	mockRepo.On("GetRevenueThisMonth").Return(1234.5, nil)
+	svc := service.NewDashboardService(mockRepo, func() float64 { return 0 })
+
+	dashboard, err := svc.GetDashboard(context.Background())
+
+	require.Error(t, err)
+	assert.Nil(t, dashboard)
+}
+
+func TestDashboardService_GetCategoryStatsCached(t *testing.T) {
+	mockRepo := new(MockDashboardRepository)
+	mockRepo.On("GetCategoryStats", 8).
+		Return(&models.CategoryStats{CategoryID: 8, Name: "Family", FilmsCount: 69}, nil).Once()
+	statsCache := cache.NewMemoryCache(time.Minute)
+	svc := service.NewDashboardService(mockRepo, func() float64 { return 0 },
+		service.WithCategoryStatsCache(statsCache))
+
+	first, err := svc.GetCategoryStats(context.Background(), 8)
+	require.NoError(t, err)
+	second, err := svc.GetCategoryStats(context.Background(), 8)
+	require.NoError(t, err)
+
+	assert.Equal(t, 69, first.FilmsCount)
+	assert.False(t, first.GeneratedAt.IsZero())
+	assert.Same(t, first, second)
+	mockRepo.AssertExpectations(t)
+
+	// A purge makes the next request aggregate again.
+	cache.Evict(statsCache)(cache.PurgeAll())
+	mockRepo.On("GetCategoryStats", 8).
+		Return(&models.CategoryStats{CategoryID: 8, Name: "Family", FilmsCount: 70}, nil).Once()
+
+	third, err := svc.GetCategoryStats(context.Background(), 8)
+	require.NoError(t, err)
+	assert.Equal(t, 70, third.FilmsCount)
+}
+
+func TestDashboardService_GetCategoryStatsNotFound(t *testing.T) {
+	mockRepo := new(MockDashboardRepository)
+	mockRepo.On("GetCategoryStats", 99).Return(nil, repository.ErrCategoryNotFound)
+	statsCache := cache.NewMemoryCache(time.Minute)
+	svc := service.NewDashboardService(mockRepo, func() float64 { return 0 },
+		service.WithCategoryStatsCache(statsCache))
+
+	stats, err := svc.GetCategoryStats(context.Background(), 99)
+
+	require.ErrorIs(t, err, repository.ErrCategoryNotFound)
+	assert.Nil(t, stats)
+	_, cached := statsCache.Get("category_stats:99")
+	assert.False(t, cached)
+}
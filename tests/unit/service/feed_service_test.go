@@ -0,0 +1,99 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/feeds"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockFilmFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockFilmFeedRepository) GetNewestFilms(_ context.Context, limit int) ([]models.Film, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Film), args.Error(1)
+}
+
+func TestFeedService_FilmsFeed(t *testing.T) {
+	mockFeedRepo := new(MockFilmFeedRepository)
+	feedService := service.NewFeedService(new(MockFilmRepository), mockFeedRepo,
+		service.NewCommentService(new(MockCommentRepository), new(MockFilmRepository)), "https://mockbuster.example")
+	older := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	description := "A fanciful documentary"
+	year := 2006
+	mockFeedRepo.On("GetNewestFilms", 50).Return([]models.Film{
+		{FilmID: 2, Title: "Ace Goldfinger", LastUpdate: older},
+		{FilmID: 1, Title: "Academy Dinosaur", ReleaseYear: &year, Description: &description, LastUpdate: newer,
+			Categories: []string{"Documentary"}},
+	}, nil)
+
+	feed, err := feedService.FilmsFeed(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://mockbuster.example/feeds/films.atom", feed.ID)
+	assert.Equal(t, newer, feed.Updated)
+	require.Len(t, feed.Entries, 2)
+	filmURL := "https://mockbuster.example/api/v1/films/1"
+	assert.Equal(t, feeds.Entry{
+		ID:         filmURL,
+		Title:      "Academy Dinosaur (2006)",
+		Updated:    newer,
+		Links:      []feeds.Link{{Rel: "alternate", Type: "application/json", Href: filmURL}},
+		Categories: []feeds.Category{{Term: "Documentary"}},
+		Summary:    &feeds.Text{Body: description},
+	}, feed.Entries[1])
+}
+
+func TestFeedService_FilmCommentsFeed(t *testing.T) {
+	mockFilmRepo := new(MockFilmRepository)
+	mockCommentRepo := new(MockCommentRepository)
+	feedService := service.NewFeedService(mockFilmRepo, new(MockFilmFeedRepository),
+		service.NewCommentService(mockCommentRepo, mockFilmRepo), "https://mockbuster.example")
+	posted := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mockFilmRepo.On("GetFilmByID", 1).
+		Return(&models.Film{FilmID: 1, Title: "Academy Dinosaur", Status: models.FilmPublished}, nil)
+	page := pagination.Params{Limit: 50}
+	mockCommentRepo.On("GetCommentsByFilmID", 1, 0, 50, (*pagination.Cursor)(nil)).Return(pagination.New([]models.Comment{
+		{ID: 9, FilmID: 1, Comment: "**Great**", DisplayName: "Mary S.", CreatedAt: posted},
+		{ID: 4, FilmID: 1, Comment: "Fine", DisplayName: "Guest", CreatedAt: posted.Add(-time.Hour)},
+	}, 2, page), nil)
+
+	feed, err := feedService.FilmCommentsFeed(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Mockbuster: Comments on Academy Dinosaur", feed.Title)
+	assert.Equal(t, posted, feed.Updated)
+	require.Len(t, feed.Entries, 2)
+	entry := feed.Entries[0]
+	assert.Equal(t, "https://mockbuster.example/api/v1/films/1/comments#comment-9", entry.ID)
+	assert.Equal(t, "Mary S. on Academy Dinosaur", entry.Title)
+	assert.Equal(t, &feeds.Person{Name: "Mary S."}, entry.Author)
+	assert.Equal(t, "html", entry.Content.Type)
+	assert.Contains(t, entry.Content.Body, "<strong>Great</strong>")
+}
+
+func TestFeedService_FilmCommentsFeedUnknownFilm(t *testing.T) {
+	mockFilmRepo := new(MockFilmRepository)
+	feedService := service.NewFeedService(mockFilmRepo, new(MockFilmFeedRepository),
+		service.NewCommentService(new(MockCommentRepository), mockFilmRepo), "https://mockbuster.example")
+	mockFilmRepo.On("GetFilmByID", 999).Return(nil, repository.ErrFilmNotFound)
+
+	_, err := feedService.FilmCommentsFeed(context.Background(), 999)
+
+	require.ErrorIs(t, err, repository.ErrFilmNotFound)
+}
@@ -5,11 +5,17 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/binding"
+	"github.com/rxbenefits/go-hw/internal/metrics"
 	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/ratings"
 	"github.com/rxbenefits/go-hw/internal/repository"
 	"github.com/rxbenefits/go-hw/internal/service"
 )
@@ -18,15 +24,18 @@ type MockFilmRepository struct {
 	mock.Mock
 }
 
-func (m *MockFilmRepository) GetFilms(filters models.FilmFilters) (*models.FilmListResponse, error) {
+func (m *MockFilmRepository) GetFilms(
+	_ context.Context,
+	filters models.FilmFilters,
+) (*pagination.Paginated[models.Film], error) {
 	args := m.Called(filters)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.FilmListResponse), args.Error(1)
+	return args.Get(0).(*pagination.Paginated[models.Film]), args.Error(1)
 }
 
-func (m *MockFilmRepository) GetFilmByID(filmID int) (*models.Film, error) {
+func (m *MockFilmRepository) GetFilmByID(_ context.Context, filmID int) (*models.Film, error) {
 	args := m.Called(filmID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -34,7 +43,7 @@ func (m *MockFilmRepository) GetFilmByID(filmID int) (*models.Film, error) {
 	return args.Get(0).(*models.Film), args.Error(1)
 }
 
-func (m *MockFilmRepository) GetCategories() ([]models.Category, error) {
+func (m *MockFilmRepository) GetCategories(_ context.Context) ([]models.Category, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Category), args.Error(1)
 }
@@ -43,29 +52,28 @@ func TestFilmService_GetFilms(t *testing.T) {
 	tests := []struct {
 		name           string
 		filters        models.FilmFilters
-		mockResponse   *models.FilmListResponse
+		mockResponse   *pagination.Paginated[models.Film]
 		mockError      error
-		expectedResult *models.FilmListResponse
+		expectedResult *pagination.Paginated[models.Film]
 		expectedError  string
 	}{
 		{
 			name: "successful retrieval with valid filters",
 			filters: models.FilmFilters{
-				Title:  "Test",
-				Rating: "PG",
-				Page:   1,
-				Limit:  10,
+				Title:   "Test",
+				Ratings: []string{"PG"},
+				Params:  pagination.Params{Page: 1, Limit: 10},
 			},
-			mockResponse: &models.FilmListResponse{
-				Films: []models.Film{
+			mockResponse: &pagination.Paginated[models.Film]{
+				Items: []models.Film{
 					{FilmID: 1, Title: "Test Film", Rating: "PG"},
 				},
 				Total: 1,
 				Page:  1,
 				Limit: 10,
 			},
-			expectedResult: &models.FilmListResponse{
-				Films: []models.Film{
+			expectedResult: &pagination.Paginated[models.Film]{
+				Items: []models.Film{
 					{FilmID: 1, Title: "Test Film", Rating: "PG"},
 				},
 				Total: 1,
@@ -73,28 +81,65 @@ func TestFilmService_GetFilms(t *testing.T) {
 				Limit: 10,
 			},
 		},
+		{
+			name: "multiple ratings and categories",
+			filters: models.FilmFilters{
+				Ratings:    []string{"PG", "PG-13"},
+				Categories: []string{"Action", "Comedy"},
+				Params:     pagination.Params{Page: 1, Limit: 10},
+			},
+			mockResponse: &pagination.Paginated[models.Film]{
+				Items: []models.Film{
+					{FilmID: 1, Title: "Test Film", Rating: "PG-13"},
+				},
+				Total: 1,
+				Page:  1,
+				Limit: 10,
+			},
+			expectedResult: &pagination.Paginated[models.Film]{
+				Items: []models.Film{
+					{FilmID: 1, Title: "Test Film", Rating: "PG-13"},
+				},
+				Total: 1,
+				Page:  1,
+				Limit: 10,
+			},
+		},
 		{
 			name: "invalid rating filter",
 			filters: models.FilmFilters{
-				Rating: "INVALID",
-				Page:   1,
-				Limit:  10,
+				Ratings: []string{"INVALID"},
+				Params:  pagination.Params{Page: 1, Limit: 10},
 			},
-			expectedError: "invalid rating provided",
+			expectedError: `rating[0] must be one of G, PG, PG-13, R, NC-17, not "INVALID"`,
+		},
+		{
+			name: "one invalid rating among several",
+			filters: models.FilmFilters{
+				Ratings: []string{"PG", "XXX"},
+				Params:  pagination.Params{Page: 1, Limit: 10},
+			},
+			expectedError: `rating[1] must be one of G, PG, PG-13, R, NC-17, not "XXX"`,
+		},
+		{
+			name: "invalid count mode",
+			filters: models.FilmFilters{
+				CountMode: "sometimes",
+				Params:    pagination.Params{Page: 1, Limit: 10},
+			},
+			expectedError: `count must be one of exact, estimate, none, not "sometimes"`,
 		},
 		{
 			name: "invalid page number",
 			filters: models.FilmFilters{
-				Page:  0,
-				Limit: 10,
+				Params: pagination.Params{Page: 0, Limit: 10},
 			},
-			expectedError: "page must be greater than 0",
+			expectedError: "page must be at least 1",
 		},
 		{
 			name: "invalid limit",
 			filters: models.FilmFilters{
-				Page:  1,
-				Limit: 101,
+				Params: pagination.Params{Page: 1, Limit: 101},
 			},
 			expectedError: "limit must be between 1 and 100",
 		},
@@ -103,7 +148,7 @@ func TestFilmService_GetFilms(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockFilmRepository)
-			filmService := service.NewFilmService(mockRepo)
+			filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits)
 
 			if tt.mockResponse != nil {
 				// Normalize filters for the mock expectation
@@ -133,6 +178,25 @@ func TestFilmService_GetFilms(t *testing.T) {
 	}
 }
 
+func TestFilmService_GetFilmsConfiguredPagination(t *testing.T) {
+	pageSizes := pagination.Limits{DefaultLimit: 25, MaxLimit: 50}
+	mockRepo := new(MockFilmRepository)
+	filmService := service.NewFilmService(mockRepo, pageSizes)
+
+	_, err := filmService.GetFilms(context.Background(),
+		models.FilmFilters{Params: pagination.Params{Page: 1, Limit: 51}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "limit must be between 1 and 50")
+
+	filters := models.FilmFilters{Params: pagination.Params{Page: 1, Limit: 50}}
+	response := &pagination.Paginated[models.Film]{Page: 1, Limit: 50}
+	mockRepo.On("GetFilms", filters).Return(response, nil)
+	result, err := filmService.GetFilms(context.Background(), filters)
+	require.NoError(t, err)
+	assert.Equal(t, response, result)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestFilmService_GetFilmByID(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -149,12 +213,24 @@ func TestFilmService_GetFilmByID(t *testing.T) {
 				FilmID: 1,
 				Title:  "Test Film",
 				Rating: "PG",
+				Status: models.FilmPublished,
 			},
 			expectedResult: &models.Film{
 				FilmID: 1,
 				Title:  "Test Film",
 				Rating: "PG",
+				Status: models.FilmPublished,
+			},
+		},
+		{
+			name:   "draft film",
+			filmID: 2,
+			mockResponse: &models.Film{
+				FilmID: 2,
+				Title:  "Unreleased Film",
+				Status: models.FilmDraft,
 			},
+			expectedError: "film not found",
 		},
 		{
 			name:          "film not found",
@@ -172,7 +248,7 @@ func TestFilmService_GetFilmByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockFilmRepository)
-			filmService := service.NewFilmService(mockRepo)
+			filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits)
 
 			if tt.filmID > 0 {
 				mockRepo.On("GetFilmByID", tt.filmID).Return(tt.mockResponse, tt.mockError)
@@ -183,6 +259,7 @@ func TestFilmService_GetFilmByID(t *testing.T) {
 			if tt.expectedError != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.NotEqual(t, apperrors.Internal, apperrors.KindOf(err))
 				assert.Nil(t, result)
 			} else {
 				require.NoError(t, err)
@@ -223,7 +300,7 @@ func TestFilmService_GetCategories(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockFilmRepository)
-			filmService := service.NewFilmService(mockRepo)
+			filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits)
 
 			mockRepo.On("GetCategories").Return(tt.mockResponse, tt.mockError)
 
@@ -241,3 +318,135 @@ func TestFilmService_GetCategories(t *testing.T) {
 		})
 	}
 }
+
+func TestFilmService_GetFilmsNormalizesTags(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits)
+	expected := models.FilmFilters{Tags: []string{"cult classic", "noir"}, Params: pagination.Params{Page: 1, Limit: 10}}
+	mockRepo.On("GetFilms", expected).
+		Return(&pagination.Paginated[models.Film]{Items: []models.Film{}, Page: 1, Limit: 10}, nil)
+
+	_, err := filmService.GetFilms(context.Background(),
+		models.FilmFilters{Tags: []string{"Cult  Classic", "NOIR"}, Params: pagination.Params{Page: 1, Limit: 10}})
+	require.NoError(t, err)
+
+	_, err = filmService.GetFilms(context.Background(),
+		models.FilmFilters{Tags: []string{"\t"}, Params: pagination.Params{Page: 1, Limit: 10}})
+	require.EqualError(t, err, "validation failed: tags[0] must not be empty")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFilmService_SearchMetrics(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits)
+	found := metrics.FilmSearches.WithLabelValues("found")
+	empty := metrics.FilmSearches.WithLabelValues("empty")
+	beforeFound, beforeEmpty := testutil.ToFloat64(found), testutil.ToFloat64(empty)
+
+	page := func(params pagination.Params, items ...models.Film) *pagination.Paginated[models.Film] {
+		return pagination.New(items, len(items), params)
+	}
+	first := pagination.Params{Page: 1, Limit: 10}
+	second := pagination.Params{Page: 2, Limit: 10}
+	mockRepo.On("GetFilms", models.FilmFilters{Title: "dinosaur", Params: first}).
+		Return(page(first, models.Film{FilmID: 1}), nil)
+	mockRepo.On("GetFilms", models.FilmFilters{Title: "zzz", Params: first}).Return(page(first), nil)
+	mockRepo.On("GetFilms", models.FilmFilters{Title: "dinosaur", Params: second}).Return(page(second), nil)
+	mockRepo.On("GetFilms", models.FilmFilters{Params: first}).Return(page(first), nil)
+
+	for _, filters := range []models.FilmFilters{
+		{Title: "dinosaur", Params: first},
+		{Title: "zzz", Params: first},
+		{Title: "dinosaur", Params: second}, // Paging through results is not a new search.
+		{Params: first},                     // Nor is browsing the unfiltered catalog.
+	} {
+		_, err := filmService.GetFilms(context.Background(), filters)
+		require.NoError(t, err)
+	}
+
+	assert.InDelta(t, beforeFound+1, testutil.ToFloat64(found), 0)
+	assert.InDelta(t, beforeEmpty+1, testutil.ToFloat64(empty), 0)
+}
+
+func TestFilmService_GetFilmByIDCountsNotFound(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits)
+	mockRepo.On("GetFilmByID", 999).Return(nil, repository.ErrFilmNotFound)
+	before := testutil.ToFloat64(metrics.FilmLookupsNotFound)
+
+	_, err := filmService.GetFilmByID(context.Background(), 999)
+
+	require.ErrorIs(t, err, repository.ErrFilmNotFound)
+	assert.InDelta(t, before+1, testutil.ToFloat64(metrics.FilmLookupsNotFound), 0)
+}
+
+func TestFilmService_LocalRatings(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	systems := ratings.Systems{"UK": {"PG-13": "12A"}}
+	filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits, service.WithRatingSystems(systems))
+
+	// The repository may return films shared through the film cache.
+	cached := &models.Film{FilmID: 1, Rating: "PG-13", Status: models.FilmPublished}
+	params := pagination.Params{Page: 1, Limit: 10}
+	page := pagination.New([]models.Film{{FilmID: 1, Rating: "PG-13"}, {FilmID: 2, Rating: "G"}}, 2, params)
+	mockRepo.On("GetFilmByID", 1).Return(cached, nil)
+	mockRepo.On("GetFilms", models.FilmFilters{Params: params}).Return(page, nil)
+
+	film, err := filmService.GetFilmByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"UK": "12A"}, film.LocalRatings)
+	assert.Nil(t, cached.LocalRatings)
+
+	films, err := filmService.GetFilms(context.Background(), models.FilmFilters{Params: params})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"UK": "12A"}, films.Items[0].LocalRatings)
+	assert.Nil(t, films.Items[1].LocalRatings)
+	assert.Nil(t, page.Items[0].LocalRatings)
+}
+
+func TestFilmService_AdminFilmsSeeEveryStatus(t *testing.T) {
+	mockRepo := new(MockFilmRepository)
+	filmService := service.NewFilmService(mockRepo, pagination.DefaultLimits)
+	params := pagination.Params{Page: 1, Limit: 10}
+	draft := &models.Film{FilmID: 2, Title: "Unreleased Film", Status: models.FilmDraft}
+	mockRepo.On("GetFilmByID", 2).Return(draft, nil)
+	mockRepo.On("GetFilms", models.FilmFilters{Params: params}).
+		Return(pagination.New([]models.Film{}, 0, params), nil)
+	mockRepo.On("GetFilms", models.FilmFilters{
+		Statuses: []string{models.FilmDraft, models.FilmPublished}, Params: params,
+	}).Return(pagination.New([]models.Film{*draft}, 1, params), nil)
+	mockRepo.On("GetFilms", models.FilmFilters{Statuses: models.FilmStatuses, IncludeArchived: true, Params: params}).
+		Return(pagination.New([]models.Film{*draft}, 1, params), nil)
+	mockRepo.On("GetFilms", models.FilmFilters{Statuses: []string{models.FilmDraft}, Params: params}).
+		Return(pagination.New([]models.Film{*draft}, 1, params), nil)
+
+	film, err := filmService.GetAdminFilmByID(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, draft, film)
+
+	// The public listing ignores any statuses asked for.
+	films, err := filmService.GetFilms(context.Background(),
+		models.FilmFilters{Statuses: []string{models.FilmDraft}, Params: params})
+	require.NoError(t, err)
+	assert.Empty(t, films.Items)
+
+	// Archived films are left out unless asked for.
+	films, err = filmService.GetAdminFilms(context.Background(), models.FilmFilters{Params: params})
+	require.NoError(t, err)
+	assert.Len(t, films.Items, 1)
+
+	films, err = filmService.GetAdminFilms(context.Background(),
+		models.FilmFilters{IncludeArchived: true, Params: params})
+	require.NoError(t, err)
+	assert.Len(t, films.Items, 1)
+
+	films, err = filmService.GetAdminFilms(context.Background(),
+		models.FilmFilters{Statuses: []string{models.FilmDraft}, Params: params})
+	require.NoError(t, err)
+	assert.Len(t, films.Items, 1)
+	mockRepo.AssertExpectations(t)
+
+	_, err = filmService.GetAdminFilms(context.Background(),
+		models.FilmFilters{Statuses: []string{"hidden"}, Params: params})
+	require.ErrorIs(t, err, binding.ErrValidation)
+}
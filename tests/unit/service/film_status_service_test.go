@@ -0,0 +1,186 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+	"github.com/rxbenefits/go-hw/internal/webhooks"
+)
+
+type MockFilmStatusRepository struct {
+	mock.Mock
+}
+
+func (m *MockFilmStatusRepository) SetFilmStatus(
+	_ context.Context,
+	filmID int,
+	status string,
+) (*models.FilmStatus, string, error) {
+	args := m.Called(filmID, status)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*models.FilmStatus), args.String(1), args.Error(2)
+}
+
+func (m *MockFilmStatusRepository) ScheduleFilmPublish(
+	_ context.Context,
+	filmID int,
+	publishAt *time.Time,
+) (*models.FilmStatus, error) {
+	args := m.Called(filmID, publishAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FilmStatus), args.Error(1)
+}
+
+func (m *MockFilmStatusRepository) PublishScheduledFilms(_ context.Context) ([]models.FilmStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FilmStatus), args.Error(1)
+}
+
+func (m *MockFilmStatusRepository) RestoreFilm(_ context.Context, filmID int) (*models.FilmStatus, error) {
+	args := m.Called(filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FilmStatus), args.Error(1)
+}
+
+func TestFilmStatusService_SetFilmStatusEvictsFilm(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         string
+		previous       string
+		expectedEvents []string
+	}{
+		{name: "publish draft", status: models.FilmPublished, previous: models.FilmDraft,
+			expectedEvents: []string{webhooks.EventFilmPublished}},
+		{name: "publish again", status: models.FilmPublished, previous: models.FilmPublished},
+		{name: "unpublish", status: models.FilmDraft, previous: models.FilmPublished},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFilmStatusRepository)
+			invalidations := &recordingInvalidations{}
+			publisher := &recordingEventPublisher{}
+			statusService := service.NewFilmStatusService(mockRepo, invalidations,
+				service.WithFilmEventPublisher(publisher))
+			updated := &models.FilmStatus{FilmID: 1, Status: tt.status, LastUpdate: time.Now()}
+			mockRepo.On("SetFilmStatus", 1, tt.status).Return(updated, tt.previous, nil)
+
+			filmStatus, err := statusService.SetFilmStatus(context.Background(), 1, tt.status)
+
+			require.NoError(t, err)
+			assert.Equal(t, updated, filmStatus)
+			assert.Equal(t, []cache.Event{cache.FilmChanged(1)}, invalidations.events)
+			assert.Equal(t, tt.expectedEvents, publisher.events)
+		})
+	}
+}
+
+func TestFilmStatusService_SetFilmStatusFails(t *testing.T) {
+	mockRepo := new(MockFilmStatusRepository)
+	invalidations := &recordingInvalidations{}
+	statusService := service.NewFilmStatusService(mockRepo, invalidations)
+	mockRepo.On("SetFilmStatus", 999, models.FilmDraft).Return(nil, "", repository.ErrFilmNotFound)
+
+	_, err := statusService.SetFilmStatus(context.Background(), 1, "hidden")
+	require.ErrorIs(t, err, service.ErrInvalidFilmStatus)
+	mockRepo.AssertNotCalled(t, "SetFilmStatus", mock.Anything, mock.Anything)
+
+	_, err = statusService.SetFilmStatus(context.Background(), 999, models.FilmDraft)
+	require.ErrorIs(t, err, repository.ErrFilmNotFound)
+	assert.Empty(t, invalidations.events)
+}
+
+func TestFilmStatusService_ScheduleFilmPublish(t *testing.T) {
+	mockRepo := new(MockFilmStatusRepository)
+	invalidations := &recordingInvalidations{}
+	statusService := service.NewFilmStatusService(mockRepo, invalidations)
+	publishAt := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Minute)
+	scheduled := &models.FilmStatus{FilmID: 1, Status: models.FilmDraft, PublishAt: &publishAt}
+	mockRepo.On("ScheduleFilmPublish", 1, &publishAt).Return(scheduled, nil)
+	mockRepo.On("ScheduleFilmPublish", 2, (*time.Time)(nil)).Return(nil, repository.ErrFilmNotDraft)
+
+	filmStatus, err := statusService.ScheduleFilmPublish(context.Background(), 1, &publishAt)
+	require.NoError(t, err)
+	assert.Equal(t, scheduled, filmStatus)
+	assert.Equal(t, []cache.Event{cache.FilmChanged(1)}, invalidations.events)
+
+	_, err = statusService.ScheduleFilmPublish(context.Background(), 1, &past)
+	require.ErrorIs(t, err, service.ErrPublishAtPast)
+
+	_, err = statusService.ScheduleFilmPublish(context.Background(), 2, nil)
+	require.ErrorIs(t, err, repository.ErrFilmNotDraft)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFilmStatusService_PublishScheduledFilms(t *testing.T) {
+	mockRepo := new(MockFilmStatusRepository)
+	invalidations := &recordingInvalidations{}
+	publisher := &recordingEventPublisher{}
+	statusService := service.NewFilmStatusService(mockRepo, invalidations,
+		service.WithFilmEventPublisher(publisher))
+	published := []models.FilmStatus{
+		{FilmID: 1, Status: models.FilmPublished},
+		{FilmID: 2, Status: models.FilmPublished},
+	}
+	mockRepo.On("PublishScheduledFilms").Return(published, nil).Once()
+
+	require.NoError(t, statusService.PublishScheduledFilms(context.Background()))
+
+	assert.Equal(t, []cache.Event{cache.FilmsChanged(1, 2)}, invalidations.events)
+	assert.Equal(t, []string{webhooks.EventFilmPublished, webhooks.EventFilmPublished}, publisher.events)
+	assert.Equal(t, []any{published[0], published[1]}, publisher.data)
+
+	// Nothing due publishes nothing.
+	mockRepo.On("PublishScheduledFilms").Return([]models.FilmStatus{}, nil).Once()
+	require.NoError(t, statusService.PublishScheduledFilms(context.Background()))
+	assert.Len(t, invalidations.events, 1)
+
+	mockRepo.On("PublishScheduledFilms").Return(nil, errors.New("boom")).Once()
+	require.Error(t, statusService.PublishScheduledFilms(context.Background()))
+}
+
+func TestFilmStatusService_ArchiveAndRestore(t *testing.T) {
+	mockRepo := new(MockFilmStatusRepository)
+	invalidations := &recordingInvalidations{}
+	publisher := &recordingEventPublisher{}
+	statusService := service.NewFilmStatusService(mockRepo, invalidations, service.WithFilmEventPublisher(publisher))
+	archived := &models.FilmStatus{FilmID: 1, Status: models.FilmArchived, LastUpdate: time.Now()}
+	restored := &models.FilmStatus{FilmID: 1, Status: models.FilmPublished, LastUpdate: time.Now()}
+	mockRepo.On("SetFilmStatus", 1, models.FilmArchived).Return(archived, models.FilmPublished, nil)
+	mockRepo.On("RestoreFilm", 1).Return(restored, nil).Once()
+	mockRepo.On("RestoreFilm", 1).Return(nil, repository.ErrFilmNotArchived)
+
+	filmStatus, err := statusService.ArchiveFilm(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, archived, filmStatus)
+	assert.Empty(t, publisher.events)
+
+	filmStatus, err = statusService.RestoreFilm(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, restored, filmStatus)
+	assert.Equal(t, []string{webhooks.EventFilmPublished}, publisher.events)
+	assert.Equal(t, []cache.Event{cache.FilmChanged(1), cache.FilmChanged(1)}, invalidations.events)
+
+	_, err = statusService.RestoreFilm(context.Background(), 1)
+	assert.ErrorIs(t, err, repository.ErrFilmNotArchived)
+}
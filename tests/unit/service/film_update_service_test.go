@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockFilmUpdateRepository struct {
+	mock.Mock
+}
+
+func (m *MockFilmUpdateRepository) UpdateFilm(
+	_ context.Context,
+	filmID int,
+	version time.Time,
+	update models.FilmUpdateRequest,
+) (*models.Film, error) {
+	args := m.Called(filmID, version, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Film), args.Error(1)
+}
+
+func (m *MockFilmUpdateRepository) BulkUpdateRentalRate(
+	_ context.Context,
+	filters models.FilmFilters,
+	change models.BulkPriceRequest,
+) (*models.BulkPriceResult, []int, error) {
+	args := m.Called(filters, change)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	filmIDs, _ := args.Get(1).([]int)
+	return args.Get(0).(*models.BulkPriceResult), filmIDs, args.Error(2)
+}
+
+func TestFilmUpdateService_UpdateFilmEvictsFilm(t *testing.T) {
+	mockRepo := new(MockFilmUpdateRepository)
+	invalidations := &recordingInvalidations{}
+	updateService := service.NewFilmUpdateService(mockRepo, invalidations)
+	version := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rate := 3.99
+	update := models.FilmUpdateRequest{RentalRate: &rate}
+	updated := &models.Film{FilmID: 1, Title: "ACADEMY DINOSAUR", RentalRate: rate, LastUpdate: time.Now()}
+	mockRepo.On("UpdateFilm", 1, version, update).Return(updated, nil)
+
+	film, err := updateService.UpdateFilm(context.Background(), 1, version, update)
+
+	require.NoError(t, err)
+	assert.Equal(t, updated, film)
+	assert.Equal(t, []cache.Event{cache.FilmChanged(1)}, invalidations.events)
+}
+
+func TestFilmUpdateService_UpdateFilmErrors(t *testing.T) {
+	title := "ACADEMY DINOSAUR II"
+	tests := []struct {
+		name        string
+		update      models.FilmUpdateRequest
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "nothing to change", update: models.FilmUpdateRequest{}, expectedErr: service.ErrEmptyFilmUpdate},
+		{name: "changed since fetched", update: models.FilmUpdateRequest{Title: &title},
+			repoErr: repository.ErrFilmModified, expectedErr: repository.ErrFilmModified},
+		{name: "film missing", update: models.FilmUpdateRequest{Title: &title},
+			repoErr: repository.ErrFilmNotFound, expectedErr: repository.ErrFilmNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFilmUpdateRepository)
+			invalidations := &recordingInvalidations{}
+			updateService := service.NewFilmUpdateService(mockRepo, invalidations)
+			if tt.repoErr != nil {
+				mockRepo.On("UpdateFilm", 1, mock.Anything, tt.update).Return(nil, tt.repoErr)
+			}
+
+			_, err := updateService.UpdateFilm(context.Background(), 1, time.Now(), tt.update)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Empty(t, invalidations.events)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilmUpdateService_BulkUpdatePrice(t *testing.T) {
+	percent := 10.0
+	tests := []struct {
+		name           string
+		change         models.BulkPriceRequest
+		filmIDs        []int
+		expectedEvents []cache.Event
+	}{
+		{name: "applied", change: models.BulkPriceRequest{Percent: &percent}, filmIDs: []int{1, 2},
+			expectedEvents: []cache.Event{cache.FilmsChanged(1, 2)}},
+		{name: "dry run", change: models.BulkPriceRequest{Percent: &percent, DryRun: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFilmUpdateRepository)
+			invalidations := &recordingInvalidations{}
+			updateService := service.NewFilmUpdateService(mockRepo, invalidations)
+			// Films match as GET /admin/films lists them, whatever the paging.
+			expectedFilters := models.FilmFilters{
+				Ratings:  []string{"PG"},
+				Statuses: []string{models.FilmDraft, models.FilmPublished},
+				Params:   pagination.Params{Page: 1, Limit: 1},
+			}
+			result := &models.BulkPriceResult{FilmsMatched: 3, FilmsChanged: 2, DryRun: tt.change.DryRun}
+			mockRepo.On("BulkUpdateRentalRate", expectedFilters, tt.change).Return(result, tt.filmIDs, nil)
+
+			got, err := updateService.BulkUpdatePrice(context.Background(),
+				models.FilmFilters{Ratings: []string{"PG"}, Params: pagination.Params{Page: 4}}, tt.change)
+
+			require.NoError(t, err)
+			assert.Equal(t, result, got)
+			assert.Equal(t, tt.expectedEvents, invalidations.events)
+		})
+	}
+}
+
+func TestFilmUpdateService_BulkUpdatePriceInvalid(t *testing.T) {
+	rate, percent := 2.99, 10.0
+	tests := []struct {
+		name    string
+		filters models.FilmFilters
+		change  models.BulkPriceRequest
+	}{
+		{name: "no change", change: models.BulkPriceRequest{}},
+		{name: "both changes", change: models.BulkPriceRequest{RentalRate: &rate, Percent: &percent}},
+		{name: "unknown rating", filters: models.FilmFilters{Ratings: []string{"X"}},
+			change: models.BulkPriceRequest{RentalRate: &rate}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockFilmUpdateRepository)
+			updateService := service.NewFilmUpdateService(mockRepo, &recordingInvalidations{})
+
+			_, err := updateService.BulkUpdatePrice(context.Background(), tt.filters, tt.change)
+
+			assert.Equal(t, apperrors.Invalid, apperrors.KindOf(err))
+			mockRepo.AssertNotCalled(t, "BulkUpdateRentalRate", mock.Anything, mock.Anything)
+		})
+	}
+}
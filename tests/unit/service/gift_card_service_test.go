@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/giftcards"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/payments"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockGiftCardRepository struct {
+	mock.Mock
+}
+
+func (m *MockGiftCardRepository) CreateGiftCard(
+	_ context.Context,
+	card models.GiftCard,
+	codeHash string,
+) (*models.GiftCard, error) {
+	args := m.Called(card, codeHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GiftCard), args.Error(1)
+}
+
+func (m *MockGiftCardRepository) SetGiftCardPayment(
+	_ context.Context,
+	giftCardID int,
+	providerPaymentID, clientSecret string,
+) (*models.GiftCard, error) {
+	args := m.Called(giftCardID, providerPaymentID, clientSecret)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GiftCard), args.Error(1)
+}
+
+func (m *MockGiftCardRepository) GetGiftCardByCode(_ context.Context, codeHash string) (*models.GiftCard, error) {
+	args := m.Called(codeHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GiftCard), args.Error(1)
+}
+
+func (m *MockGiftCardRepository) GetProviderGiftCard(
+	_ context.Context,
+	provider, providerPaymentID string,
+) (*models.GiftCard, error) {
+	args := m.Called(provider, providerPaymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GiftCard), args.Error(1)
+}
+
+func (m *MockGiftCardRepository) ActivateGiftCard(_ context.Context, giftCardID int) (*models.GiftCard, error) {
+	args := m.Called(giftCardID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GiftCard), args.Error(1)
+}
+
+func (m *MockGiftCardRepository) FailGiftCard(_ context.Context, giftCardID int) error {
+	return m.Called(giftCardID).Error(0)
+}
+
+// failingIntentProvider is a fake provider that cannot start payments.
+type failingIntentProvider struct {
+	payments.FakeProvider
+}
+
+func (failingIntentProvider) CreateIntent(_ context.Context, _ payments.IntentRequest) (*payments.Intent, error) {
+	return nil, errors.New("provider unavailable")
+}
+
+func TestGiftCardService_PurchaseGiftCard(t *testing.T) {
+	mockRepo := new(MockGiftCardRepository)
+	giftCardService := service.NewGiftCardService(mockRepo, payments.FakeProvider{}, "usd")
+	var storedHash, storedLastFour string
+	mockRepo.On("CreateGiftCard", mock.MatchedBy(func(card models.GiftCard) bool {
+		return card.PurchaserID == 600 && card.Amount == 25 && card.Currency == "usd" && card.Provider == "fake"
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		storedLastFour = args.Get(0).(models.GiftCard).LastFour
+		storedHash = args.String(1)
+	}).Return(&models.GiftCard{ID: 3, Amount: 25, Status: models.GiftCardStatusPending}, nil)
+	mockRepo.On("SetGiftCardPayment", 3, mock.Anything, mock.Anything).
+		Return(&models.GiftCard{ID: 3, Amount: 25, Status: models.GiftCardStatusPending, ClientSecret: "secret"}, nil)
+
+	card, err := giftCardService.PurchaseGiftCard(context.Background(), 600,
+		models.GiftCardPurchaseRequest{Amount: 25})
+
+	require.NoError(t, err)
+	assert.Equal(t, storedHash, giftcards.Hash(card.Code), "only the code's hash is stored")
+	assert.Equal(t, giftcards.LastFour(card.Code), storedLastFour)
+	assert.Equal(t, "secret", card.ClientSecret)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGiftCardService_PurchaseGiftCardProviderFailure(t *testing.T) {
+	mockRepo := new(MockGiftCardRepository)
+	giftCardService := service.NewGiftCardService(mockRepo, failingIntentProvider{}, "usd")
+	mockRepo.On("CreateGiftCard", mock.Anything, mock.Anything).
+		Return(&models.GiftCard{ID: 3, Amount: 25, Status: models.GiftCardStatusPending}, nil)
+	mockRepo.On("FailGiftCard", 3).Return(nil)
+
+	_, err := giftCardService.PurchaseGiftCard(context.Background(), 600, models.GiftCardPurchaseRequest{Amount: 25})
+
+	require.Error(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "SetGiftCardPayment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGiftCardService_CheckBalance(t *testing.T) {
+	mockRepo := new(MockGiftCardRepository)
+	giftCardService := service.NewGiftCardService(mockRepo, payments.FakeProvider{}, "usd")
+	mockRepo.On("GetGiftCardByCode", giftcards.Hash("7KQF-M2XD-9HRT-WB4C")).Return(&models.GiftCard{
+		ID: 3, LastFour: "WB4C", PurchaserID: 600, Amount: 25, Balance: 17.02, Currency: "usd",
+		Status: models.GiftCardStatusActive,
+	}, nil)
+	mockRepo.On("GetGiftCardByCode", mock.Anything).Return(nil, repository.ErrGiftCardNotFound)
+
+	balance, err := giftCardService.CheckBalance(context.Background(), "7kqf-m2xd-9hrt-wb4c")
+	require.NoError(t, err)
+	assert.Equal(t, &models.GiftCardBalance{
+		LastFour: "WB4C", Balance: 17.02, Currency: "usd", Status: models.GiftCardStatusActive,
+	}, balance)
+
+	_, err = giftCardService.CheckBalance(context.Background(), "AAAA-AAAA-AAAA-AAAA")
+	require.ErrorIs(t, err, repository.ErrGiftCardNotFound)
+}
+
+func TestPaymentService_HandleWebhookActivatesGiftCard(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockGiftCardRepo := new(MockGiftCardRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, new(MockCartRepository), payments.FakeProvider{},
+		"usd", service.WithGiftCardPayments(mockGiftCardRepo))
+	mockPaymentRepo.On("GetProviderPayment", "fake", "pi_gift").Return(nil, repository.ErrPaymentNotFound)
+	mockGiftCardRepo.On("GetProviderGiftCard", "fake", "pi_gift").Return(&models.GiftCard{ID: 3, Amount: 25}, nil)
+	mockGiftCardRepo.On("ActivateGiftCard", 3).
+		Return(&models.GiftCard{ID: 3, Balance: 25, Status: models.GiftCardStatusActive}, nil)
+
+	err := paymentService.HandleWebhook(context.Background(),
+		[]byte(`{"id": "evt_1", "type": "payment_intent.succeeded", "data": {"object": {"id": "pi_gift"}}}`),
+		http.Header{})
+
+	require.NoError(t, err)
+	mockGiftCardRepo.AssertExpectations(t)
+}
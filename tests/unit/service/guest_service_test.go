@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockGuestRepository struct {
+	mock.Mock
+}
+
+func (m *MockGuestRepository) CreateSession(_ context.Context) (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockGuestRepository) RecordComment(_ context.Context, guestID, commentID int) error {
+	return m.Called(guestID, commentID).Error(0)
+}
+
+func (m *MockGuestRepository) ListWatchlist(_ context.Context, guestID int) ([]models.GuestWatchlistEntry, error) {
+	args := m.Called(guestID)
+	entries, _ := args.Get(0).([]models.GuestWatchlistEntry)
+	return entries, args.Error(1)
+}
+
+func (m *MockGuestRepository) AddToWatchlist(_ context.Context, guestID, filmID int) error {
+	return m.Called(guestID, filmID).Error(0)
+}
+
+func (m *MockGuestRepository) RemoveFromWatchlist(_ context.Context, guestID, filmID int) error {
+	return m.Called(guestID, filmID).Error(0)
+}
+
+func (m *MockGuestRepository) MergeSession(_ context.Context, guestID, customerID int) (*models.GuestMerge, error) {
+	args := m.Called(guestID, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GuestMerge), args.Error(1)
+}
+
+// guestContext returns a context carrying the claims of guestID's token.
+func guestContext(guestID int) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{Subject: guestID, Role: auth.RoleGuest})
+}
+
+func TestGuestService_StartSession(t *testing.T) {
+	mockRepo := new(MockGuestRepository)
+	tokens := auth.NewTokenIssuer(auth.RoleGuest, "secret", time.Hour)
+	guestService := service.NewGuestService(mockRepo, tokens)
+	mockRepo.On("CreateSession").Return(42, nil)
+
+	session, err := guestService.StartSession(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, session.GuestID)
+	claims, err := tokens.Verify(session.Token)
+	require.NoError(t, err)
+	assert.Equal(t, 42, claims.Subject)
+	assert.Equal(t, auth.RoleGuest, claims.Role)
+}
+
+func TestGuestService_WatchlistRequiresGuestToken(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "anonymous", ctx: context.Background()},
+		{name: "customer token", ctx: auth.WithClaims(context.Background(),
+			&auth.Claims{Subject: 600, Role: auth.RoleCustomer})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockGuestRepository)
+			guestService := service.NewGuestService(mockRepo, auth.NewTokenIssuer(auth.RoleGuest, "secret", time.Hour))
+
+			_, err := guestService.GetWatchlist(tt.ctx)
+			require.ErrorIs(t, err, service.ErrGuestTokenRequired)
+			err = guestService.AddToWatchlist(tt.ctx, 1)
+			require.ErrorIs(t, err, service.ErrGuestTokenRequired)
+
+			mockRepo.AssertNotCalled(t, "AddToWatchlist", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGuestService_AddToWatchlist(t *testing.T) {
+	mockRepo := new(MockGuestRepository)
+	guestService := service.NewGuestService(mockRepo, auth.NewTokenIssuer(auth.RoleGuest, "secret", time.Hour))
+	mockRepo.On("AddToWatchlist", 42, 1).Return(nil)
+	mockRepo.On("AddToWatchlist", 42, 999).Return(repository.ErrFilmNotFound)
+
+	require.NoError(t, guestService.AddToWatchlist(guestContext(42), 1))
+	require.ErrorIs(t, guestService.AddToWatchlist(guestContext(42), 999), repository.ErrFilmNotFound)
+	mockRepo.AssertExpectations(t)
+}
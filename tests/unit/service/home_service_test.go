@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockHomeRepository struct {
+	mock.Mock
+}
+
+func (m *MockHomeRepository) GetRecommendedFilms(_ context.Context, customerID, limit int) ([]models.HomeFilm, error) {
+	args := m.Called(customerID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HomeFilm), args.Error(1)
+}
+
+func (m *MockHomeRepository) GetFavoriteCategories(_ context.Context, customerID, limit int) ([]string, error) {
+	args := m.Called(customerID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockHomeRepository) GetNewFilmsInCategories(
+	_ context.Context,
+	customerID int,
+	categories []string,
+	limit int,
+) ([]models.HomeFilm, error) {
+	args := m.Called(customerID, categories, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HomeFilm), args.Error(1)
+}
+
+// mockOpenRentals sets up the rental history pages the open rentals section
+// reads for customerID.
+func mockOpenRentals(rentalRepo *MockRentalRepository, customerID int, overdue, open []models.RentalEvent) {
+	for status, rentals := range map[string][]models.RentalEvent{
+		models.RentalStatusOverdue: overdue, models.RentalStatusOpen: open,
+	} {
+		params := pagination.Params{Page: 1, Limit: 10}
+		rentalRepo.On("GetCustomerRentals", customerID, models.RentalHistoryFilters{Status: status, Params: params}).
+			Return(pagination.New(rentals, len(rentals), params), nil)
+	}
+}
+
+func TestHomeService_GetHome(t *testing.T) {
+	homeRepo := new(MockHomeRepository)
+	rentalRepo := new(MockRentalRepository)
+	rentalService := service.NewRentalService(rentalRepo, &recordingDueNotifier{}, time.Hour, time.Hour, 1.00)
+	homeService := service.NewHomeService(homeRepo, rentalService, time.Second)
+	overdue := []models.RentalEvent{{RentalID: 1, FilmID: 8, Status: models.RentalStatusOverdue}}
+	open := []models.RentalEvent{{RentalID: 2, FilmID: 9, Status: models.RentalStatusOpen}}
+	mockOpenRentals(rentalRepo, 600, overdue, open)
+	recommended := []models.HomeFilm{{FilmID: 3, Title: "ADAPTATION HOLES"}}
+	homeRepo.On("GetRecommendedFilms", 600, 10).Return(recommended, nil)
+	homeRepo.On("GetFavoriteCategories", 600, 3).Return([]string{"Horror", "Sci-Fi"}, nil)
+	newFilms := []models.HomeFilm{{FilmID: 1001, Title: "ALIEN RETURNS"}}
+	homeRepo.On("GetNewFilmsInCategories", 600, []string{"Horror", "Sci-Fi"}, 10).Return(newFilms, nil)
+
+	feed, err := homeService.GetHome(context.Background(), 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, 600, feed.CustomerID)
+	assert.Equal(t, append(overdue, open...), feed.OpenRentals)
+	assert.Equal(t, recommended, feed.Recommended)
+	assert.Equal(t, []string{"Horror", "Sci-Fi"}, feed.FavoriteCategories)
+	assert.Equal(t, newFilms, feed.NewInFavorites)
+	assert.Empty(t, feed.Incomplete)
+}
+
+func TestHomeService_GetHomeRecommendsTrendingWithoutRentals(t *testing.T) {
+	homeRepo := new(MockHomeRepository)
+	rentalRepo := new(MockRentalRepository)
+	rentalService := service.NewRentalService(rentalRepo, &recordingDueNotifier{}, time.Hour, time.Hour, 1.00)
+	homeService := service.NewHomeService(homeRepo, rentalService, time.Second)
+	mockOpenRentals(rentalRepo, 601, nil, nil)
+	homeRepo.On("GetRecommendedFilms", 601, 10).Return([]models.HomeFilm{}, nil)
+	rentalRepo.On("GetTrendingFilms").Return([]models.TrendingFilm{{Rank: 1, FilmID: 8, Title: "ALIEN CENTER"}}, nil)
+	homeRepo.On("GetFavoriteCategories", 601, 3).Return([]string{}, nil)
+
+	feed, err := homeService.GetHome(context.Background(), 601)
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.HomeFilm{{FilmID: 8, Title: "ALIEN CENTER"}}, feed.Recommended)
+	assert.Empty(t, feed.NewInFavorites)
+	assert.Empty(t, feed.Incomplete)
+	homeRepo.AssertNotCalled(t, "GetNewFilmsInCategories", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHomeService_GetHomeLeavesOutLateAndFailedSections(t *testing.T) {
+	homeRepo := new(MockHomeRepository)
+	rentalRepo := new(MockRentalRepository)
+	rentalService := service.NewRentalService(rentalRepo, &recordingDueNotifier{}, time.Hour, time.Hour, 1.00)
+	homeService := service.NewHomeService(homeRepo, rentalService, 50*time.Millisecond)
+	open := []models.RentalEvent{{RentalID: 2, FilmID: 9, Status: models.RentalStatusOpen}}
+	mockOpenRentals(rentalRepo, 600, nil, open)
+	homeRepo.On("GetRecommendedFilms", 600, 10).After(time.Second).Return([]models.HomeFilm{{FilmID: 3}}, nil)
+	homeRepo.On("GetFavoriteCategories", 600, 3).Return(nil, errors.New("database error"))
+
+	start := time.Now()
+	feed, err := homeService.GetHome(context.Background(), 600)
+
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, open, feed.OpenRentals)
+	assert.Empty(t, feed.Recommended)
+	assert.Empty(t, feed.NewInFavorites)
+	assert.ElementsMatch(t, []string{models.HomeSectionRecommended, models.HomeSectionNewInFavorites},
+		feed.Incomplete)
+}
+
+func TestHomeService_GetHomeFailsWhenRequestEnds(t *testing.T) {
+	homeRepo := new(MockHomeRepository)
+	rentalRepo := new(MockRentalRepository)
+	rentalService := service.NewRentalService(rentalRepo, &recordingDueNotifier{}, time.Hour, time.Hour, 1.00)
+	homeService := service.NewHomeService(homeRepo, rentalService, time.Second)
+	mockOpenRentals(rentalRepo, 600, nil, nil)
+	homeRepo.On("GetRecommendedFilms", 600, 10).Return([]models.HomeFilm{}, nil).Maybe()
+	rentalRepo.On("GetTrendingFilms").Return([]models.TrendingFilm{}, nil).Maybe()
+	homeRepo.On("GetFavoriteCategories", 600, 3).Return([]string{}, nil).Maybe()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := homeService.GetHome(ctx, 600)
+
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+type MockInventoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockInventoryRepository) AddInventory(
+	_ context.Context,
+	filmID, storeID, copies int,
+) ([]models.InventoryCopy, error) {
+	args := m.Called(filmID, storeID, copies)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InventoryCopy), args.Error(1)
+}
+
+func (m *MockInventoryRepository) GetInventoryCopy(_ context.Context, inventoryID int) (*models.InventoryCopy, error) {
+	args := m.Called(inventoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InventoryCopy), args.Error(1)
+}
+
+func (m *MockInventoryRepository) ListFilmInventory(
+	_ context.Context,
+	filmID int,
+	filters models.InventoryFilters,
+) ([]models.InventoryCopy, error) {
+	args := m.Called(filmID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InventoryCopy), args.Error(1)
+}
+
+func (m *MockInventoryRepository) RetireInventory(_ context.Context, inventoryID int) (*models.InventoryCopy, error) {
+	args := m.Called(inventoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InventoryCopy), args.Error(1)
+}
+
+func (m *MockInventoryRepository) TransferInventory(
+	_ context.Context,
+	inventoryID int,
+	transferReq models.InventoryTransferRequest,
+) (*models.InventoryTransfer, error) {
+	args := m.Called(inventoryID, transferReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InventoryTransfer), args.Error(1)
+}
+
+func (m *MockInventoryRepository) ListInventoryTransfers(
+	_ context.Context,
+	inventoryID int,
+) ([]models.InventoryTransfer, error) {
+	args := m.Called(inventoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InventoryTransfer), args.Error(1)
+}
+
+func TestInventoryService_AddInventory(t *testing.T) {
+	tests := []struct {
+		name           string
+		copies         int
+		expectedCopies int
+	}{
+		{name: "one copy by default", expectedCopies: 1},
+		{name: "several copies", copies: 3, expectedCopies: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockInventoryRepository)
+			inventoryService := service.NewInventoryService(mockRepo)
+			mockRepo.On("AddInventory", 1, 2, tt.expectedCopies).
+				Return([]models.InventoryCopy{{InventoryID: 4582, FilmID: 1, StoreID: 2}}, nil)
+
+			added, err := inventoryService.AddInventory(context.Background(), 1,
+				models.InventoryRequest{StoreID: 2, Copies: tt.copies})
+
+			require.NoError(t, err)
+			assert.Len(t, added, 1)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInventoryService_ListFilmInventoryScopedToStore(t *testing.T) {
+	mockRepo := new(MockInventoryRepository)
+	inventoryService := service.NewInventoryService(mockRepo)
+	mockRepo.On("ListFilmInventory", 1, models.InventoryFilters{StoreID: 2, Status: models.CopyStatusLost}).
+		Return([]models.InventoryCopy{}, nil)
+
+	_, err := inventoryService.ListFilmInventory(tenant.WithStoreID(context.Background(), 2), 1,
+		models.InventoryFilters{StoreID: 1, Status: models.CopyStatusLost})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestInventoryService_RetireInventoryRented(t *testing.T) {
+	mockRepo := new(MockInventoryRepository)
+	inventoryService := service.NewInventoryService(mockRepo)
+	mockRepo.On("RetireInventory", 7).Return(nil, repository.ErrInventoryRented)
+
+	_, err := inventoryService.RetireInventory(context.Background(), 7)
+
+	require.ErrorIs(t, err, repository.ErrInventoryRented)
+}
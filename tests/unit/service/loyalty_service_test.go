@@ -0,0 +1,118 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockLoyaltyRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoyaltyRepository) AwardPoints(
+	_ context.Context,
+	customerID, points int,
+	reason string,
+	referenceID int,
+) error {
+	return m.Called(customerID, points, reason, referenceID).Error(0)
+}
+
+func (m *MockLoyaltyRepository) GetBalance(_ context.Context, customerID int) (*models.LoyaltyBalance, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoyaltyBalance), args.Error(1)
+}
+
+func (m *MockLoyaltyRepository) GetHistory(
+	_ context.Context,
+	customerID int,
+	filters models.LoyaltyHistoryFilters,
+) (*pagination.Paginated[models.LoyaltyEntry], error) {
+	args := m.Called(customerID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.LoyaltyEntry]), args.Error(1)
+}
+
+func (m *MockLoyaltyRepository) RedeemPoints(
+	_ context.Context,
+	customerID, points int,
+	credit float64,
+) (*models.LoyaltyEntry, error) {
+	args := m.Called(customerID, points, credit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoyaltyEntry), args.Error(1)
+}
+
+func TestLoyaltyService_RedeemPoints(t *testing.T) {
+	tests := []struct {
+		name            string
+		points          int
+		pointsPerDollar int
+		expectedCredit  float64
+		mockError       error
+		expectedError   error
+	}{
+		{name: "whole dollars", points: 500, pointsPerDollar: 100, expectedCredit: 5},
+		{name: "credit rounds down to the cent", points: 1234, pointsPerDollar: 300, expectedCredit: 4.11},
+		{name: "too few points", points: 2, pointsPerDollar: 300, expectedError: service.ErrRedemptionTooSmall},
+		{name: "redemption disabled", points: 500, expectedError: service.ErrRedemptionTooSmall},
+		{
+			name:            "not enough points",
+			points:          500,
+			pointsPerDollar: 100,
+			expectedCredit:  5,
+			mockError:       repository.ErrInsufficientPoints,
+			expectedError:   repository.ErrInsufficientPoints,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockLoyaltyRepository)
+			loyaltyService := service.NewLoyaltyService(mockRepo, 5, tt.pointsPerDollar)
+			if tt.expectedCredit != 0 {
+				if tt.mockError != nil {
+					mockRepo.On("RedeemPoints", 600, tt.points, tt.expectedCredit).Return(nil, tt.mockError)
+				} else {
+					mockRepo.On("RedeemPoints", 600, tt.points, tt.expectedCredit).
+						Return(&models.LoyaltyEntry{Points: -tt.points, Credit: tt.expectedCredit}, nil)
+				}
+			}
+
+			entry, err := loyaltyService.RedeemPoints(context.Background(), 600,
+				models.RedeemPointsRequest{Points: tt.points})
+
+			mockRepo.AssertExpectations(t)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expectedCredit, entry.Credit, 0.001)
+		})
+	}
+}
+
+func TestLoyaltyService_AwardCommentPointsDisabled(t *testing.T) {
+	mockRepo := new(MockLoyaltyRepository)
+	loyaltyService := service.NewLoyaltyService(mockRepo, 0, 100)
+
+	require.NoError(t, loyaltyService.AwardCommentPoints(context.Background(), 600, 11))
+	mockRepo.AssertNotCalled(t, "AwardPoints", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
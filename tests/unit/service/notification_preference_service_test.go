@@ -0,0 +1,118 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockNotificationPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepository) GetPreferences(
+	_ context.Context,
+	customerID int,
+) (models.NotificationPreferences, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(models.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) SetPreferences(
+	_ context.Context,
+	customerID int,
+	prefs models.NotificationPreferences,
+) error {
+	args := m.Called(customerID, prefs)
+	return args.Error(0)
+}
+
+func TestNotificationPreferenceService_GetPreferencesFillsDefaults(t *testing.T) {
+	mockRepo := new(MockNotificationPreferenceRepository)
+	prefService := service.NewNotificationPreferenceService(mockRepo)
+	mockRepo.On("GetPreferences", 600).Return(models.NotificationPreferences{
+		notifications.EventRentalDue: {notifications.ChannelEmail: false},
+	}, nil)
+
+	prefs, err := prefService.GetPreferences(context.Background(), 600)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.NotificationPreferences{
+		notifications.EventCommentReply:     {notifications.ChannelEmail: true, notifications.ChannelSMS: false},
+		notifications.EventRentalDue:        {notifications.ChannelEmail: false, notifications.ChannelSMS: false},
+		notifications.EventFilmAvailable:    {notifications.ChannelEmail: true, notifications.ChannelSMS: false},
+		notifications.EventSavedSearchMatch: {notifications.ChannelEmail: true, notifications.ChannelSMS: false},
+		notifications.EventCommentMention:   {notifications.ChannelEmail: true, notifications.ChannelSMS: false},
+	}, prefs.Preferences)
+}
+
+func TestNotificationPreferenceService_UpdatePreferences(t *testing.T) {
+	tests := []struct {
+		name          string
+		prefs         models.NotificationPreferences
+		expectedError error
+	}{
+		{
+			name:  "valid update",
+			prefs: models.NotificationPreferences{notifications.EventRentalDue: {notifications.ChannelSMS: true}},
+		},
+		{
+			name:          "unknown event",
+			prefs:         models.NotificationPreferences{"newsletter": {notifications.ChannelEmail: true}},
+			expectedError: service.ErrInvalidPreference,
+		},
+		{
+			name:          "unknown channel",
+			prefs:         models.NotificationPreferences{notifications.EventRentalDue: {"pigeon": true}},
+			expectedError: service.ErrInvalidPreference,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockNotificationPreferenceRepository)
+			prefService := service.NewNotificationPreferenceService(mockRepo)
+			if tt.expectedError == nil {
+				mockRepo.On("SetPreferences", 600, tt.prefs).Return(nil)
+				mockRepo.On("GetPreferences", 600).Return(tt.prefs, nil)
+			}
+
+			_, err := prefService.UpdatePreferences(context.Background(), 600, tt.prefs)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationPreferenceService_Allowed(t *testing.T) {
+	mockRepo := new(MockNotificationPreferenceRepository)
+	prefService := service.NewNotificationPreferenceService(mockRepo)
+	mockRepo.On("GetPreferences", 600).Return(models.NotificationPreferences{
+		notifications.EventRentalDue: {notifications.ChannelEmail: false},
+	}, nil)
+
+	allowed, err := prefService.Allowed(context.Background(), 600, notifications.EventRentalDue,
+		notifications.ChannelEmail)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, err = prefService.Allowed(context.Background(), 600, notifications.EventCommentReply,
+		notifications.ChannelEmail)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
@@ -0,0 +1,332 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/payments"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) CreatePayment(_ context.Context, payment models.Payment) (*models.Payment, error) {
+	args := m.Called(payment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetPayment(_ context.Context, paymentID int) (*models.Payment, error) {
+	args := m.Called(paymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetCheckoutPayment(_ context.Context, checkoutID int) (*models.Payment, error) {
+	args := m.Called(checkoutID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetProviderPayment(
+	_ context.Context,
+	provider, providerPaymentID string,
+) (*models.Payment, error) {
+	args := m.Called(provider, providerPaymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) CompletePayment(
+	_ context.Context,
+	paymentID int,
+	rentalPayments []models.RentalPayment,
+) (*models.Payment, error) {
+	args := m.Called(paymentID, rentalPayments)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) FailPayment(_ context.Context, paymentID int) error {
+	return m.Called(paymentID).Error(0)
+}
+
+func (m *MockPaymentRepository) CreateRefund(
+	_ context.Context,
+	paymentID int,
+	idempotencyKey string,
+	refundReq models.RefundRequest,
+) (*models.Refund, error) {
+	args := m.Called(paymentID, idempotencyKey, refundReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Refund), args.Error(1)
+}
+
+func (m *MockPaymentRepository) CompleteRefund(
+	_ context.Context,
+	refundID int,
+	providerRefundID string,
+	rentalRefunds []models.RentalPayment,
+) (*models.Refund, error) {
+	args := m.Called(refundID, providerRefundID, rentalRefunds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Refund), args.Error(1)
+}
+
+func (m *MockPaymentRepository) FailRefund(_ context.Context, refundID int) error {
+	return m.Called(refundID).Error(0)
+}
+
+// decliningProvider is a fake provider whose refunds always fail.
+type decliningProvider struct {
+	payments.FakeProvider
+}
+
+func (decliningProvider) CreateRefund(_ context.Context, _ payments.RefundRequest) (*payments.Refund, error) {
+	return nil, fmt.Errorf("%w: charge already disputed", payments.ErrDeclined)
+}
+
+// unreachableProvider is a fake provider whose refunds never get an answer.
+type unreachableProvider struct {
+	payments.FakeProvider
+}
+
+func (unreachableProvider) CreateRefund(_ context.Context, _ payments.RefundRequest) (*payments.Refund, error) {
+	return nil, errors.New("status 503: service unavailable")
+}
+
+// discountedCheckout returns checkout 7 of customer 600: three rentals
+// totalling 9.97, less a 3.00 coupon.
+func discountedCheckout() *models.Checkout {
+	return &models.Checkout{
+		ID:         7,
+		CustomerID: 600,
+		Rentals: []models.CheckoutRental{
+			{RentalID: 1, Rate: 4.99},
+			{RentalID: 2, Rate: 2.99},
+			{RentalID: 3, Rate: 1.99},
+		},
+		Subtotal: 9.97,
+		Discount: 3,
+		Total:    6.97,
+	}
+}
+
+func TestPaymentService_StartPayment(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockCartRepo := new(MockCartRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, payments.FakeProvider{}, "usd")
+	mockCartRepo.On("GetCheckout", 7).Return(discountedCheckout(), nil)
+	mockPaymentRepo.On("GetCheckoutPayment", 7).Return(nil, repository.ErrPaymentNotFound)
+	mockPaymentRepo.On("CreatePayment", mock.MatchedBy(func(payment models.Payment) bool {
+		return payment.CheckoutID == 7 && payment.Provider == "fake" && payment.Amount == 6.97 &&
+			payment.Currency == "usd" && payment.ProviderPaymentID != "" && payment.ClientSecret != ""
+	})).Return(&models.Payment{ID: 1, CheckoutID: 7, Status: models.PaymentStatusPending}, nil)
+
+	payment, err := paymentService.StartPayment(context.Background(), 600, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, payment.ID)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentService_StartPaymentRejections(t *testing.T) {
+	tests := []struct {
+		name          string
+		customerID    int
+		total         float64
+		existing      *models.Payment
+		expectedError error
+	}{
+		{name: "another customer's checkout", customerID: 601, total: 6.97, expectedError: repository.ErrCheckoutNotFound},
+		{name: "nothing to pay", customerID: 600, expectedError: service.ErrNothingToPay},
+		{
+			name:          "already paid",
+			customerID:    600,
+			total:         6.97,
+			existing:      &models.Payment{ID: 1, Status: models.PaymentStatusSucceeded},
+			expectedError: service.ErrCheckoutPaid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPaymentRepo := new(MockPaymentRepository)
+			mockCartRepo := new(MockCartRepository)
+			paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, payments.FakeProvider{}, "usd")
+			checkout := discountedCheckout()
+			checkout.Total = tt.total
+			mockCartRepo.On("GetCheckout", 7).Return(checkout, nil)
+			if tt.existing != nil {
+				mockPaymentRepo.On("GetCheckoutPayment", 7).Return(tt.existing, nil)
+			} else {
+				mockPaymentRepo.On("GetCheckoutPayment", 7).Return(nil, repository.ErrPaymentNotFound).Maybe()
+			}
+
+			_, err := paymentService.StartPayment(context.Background(), tt.customerID, 7)
+
+			require.ErrorIs(t, err, tt.expectedError)
+			mockPaymentRepo.AssertNotCalled(t, "CreatePayment", mock.Anything)
+		})
+	}
+}
+
+func TestPaymentService_StartPaymentReturnsPendingPayment(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockCartRepo := new(MockCartRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, payments.FakeProvider{}, "usd")
+	pending := &models.Payment{ID: 1, CheckoutID: 7, Status: models.PaymentStatusPending, ClientSecret: "secret"}
+	mockCartRepo.On("GetCheckout", 7).Return(discountedCheckout(), nil)
+	mockPaymentRepo.On("GetCheckoutPayment", 7).Return(pending, nil)
+
+	payment, err := paymentService.StartPayment(context.Background(), 600, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, pending, payment)
+	mockPaymentRepo.AssertNotCalled(t, "CreatePayment", mock.Anything)
+}
+
+func TestPaymentService_HandleWebhookSplitsTotalAcrossRentals(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockCartRepo := new(MockCartRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, payments.FakeProvider{}, "usd")
+	mockPaymentRepo.On("GetProviderPayment", "fake", "pi_123").Return(&models.Payment{ID: 1, CheckoutID: 7}, nil)
+	mockCartRepo.On("GetCheckout", 7).Return(discountedCheckout(), nil)
+	// 6.97 split in proportion to 4.99, 2.99, and 1.99; the last rental
+	// takes the rounding remainder.
+	mockPaymentRepo.On("CompletePayment", 1, []models.RentalPayment{
+		{RentalID: 1, Amount: 3.48},
+		{RentalID: 2, Amount: 2.09},
+		{RentalID: 3, Amount: 1.40},
+	}).Return(&models.Payment{ID: 1, Status: models.PaymentStatusSucceeded}, nil)
+
+	err := paymentService.HandleWebhook(context.Background(),
+		[]byte(`{"id": "evt_1", "type": "payment_intent.succeeded", "data": {"object": {"id": "pi_123"}}}`),
+		http.Header{})
+
+	require.NoError(t, err)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentService_HandleWebhookIgnoresUnknownPayments(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockCartRepo := new(MockCartRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, payments.FakeProvider{}, "usd")
+	mockPaymentRepo.On("GetProviderPayment", "fake", "pi_999").Return(nil, repository.ErrPaymentNotFound)
+
+	err := paymentService.HandleWebhook(context.Background(),
+		[]byte(`{"id": "evt_1", "type": "payment_intent.payment_failed", "data": {"object": {"id": "pi_999"}}}`),
+		http.Header{})
+
+	require.NoError(t, err)
+	mockPaymentRepo.AssertNotCalled(t, "FailPayment", mock.Anything)
+}
+
+func TestPaymentService_RefundPayment(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockCartRepo := new(MockCartRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, payments.FakeProvider{}, "usd")
+	refundReq := models.RefundRequest{Amount: 2, Reason: "scratched disc"}
+	mockPaymentRepo.On("GetPayment", 1).
+		Return(&models.Payment{ID: 1, CheckoutID: 7, ProviderPaymentID: "pi_123"}, nil)
+	mockPaymentRepo.On("CreateRefund", 1, "key-1", refundReq).
+		Return(&models.Refund{ID: 4, PaymentID: 1, Amount: 2, Status: models.PaymentStatusPending}, nil)
+	mockCartRepo.On("GetCheckout", 7).Return(discountedCheckout(), nil)
+	mockPaymentRepo.On("CompleteRefund", 4, mock.AnythingOfType("string"), []models.RentalPayment{
+		{RentalID: 1, Amount: 1.00},
+		{RentalID: 2, Amount: 0.59},
+		{RentalID: 3, Amount: 0.41},
+	}).Return(&models.Refund{ID: 4, Amount: 2, Status: models.PaymentStatusSucceeded}, nil)
+
+	refund, err := paymentService.RefundPayment(context.Background(), 1, "key-1", refundReq)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusSucceeded, refund.Status)
+	mockPaymentRepo.AssertExpectations(t)
+}
+
+func TestPaymentService_RefundPaymentReplaysFinishedRefund(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockCartRepo := new(MockCartRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, decliningProvider{}, "usd")
+	succeeded := &models.Refund{ID: 4, PaymentID: 1, Amount: 2, Status: models.PaymentStatusSucceeded}
+	mockPaymentRepo.On("GetPayment", 1).Return(&models.Payment{ID: 1, CheckoutID: 7}, nil)
+	mockPaymentRepo.On("CreateRefund", 1, "key-1", models.RefundRequest{}).Return(succeeded, nil)
+
+	refund, err := paymentService.RefundPayment(context.Background(), 1, "key-1", models.RefundRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, succeeded, refund)
+	mockPaymentRepo.AssertNotCalled(t, "CompleteRefund", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPaymentService_RefundPaymentProviderFailure(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockCartRepo := new(MockCartRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, decliningProvider{}, "usd")
+	mockPaymentRepo.On("GetPayment", 1).Return(&models.Payment{ID: 1, CheckoutID: 7}, nil)
+	mockPaymentRepo.On("CreateRefund", 1, "key-1", models.RefundRequest{}).
+		Return(&models.Refund{ID: 4, PaymentID: 1, Amount: 6.97, Status: models.PaymentStatusPending}, nil)
+	mockPaymentRepo.On("FailRefund", 4).Return(nil)
+
+	_, err := paymentService.RefundPayment(context.Background(), 1, "key-1", models.RefundRequest{})
+
+	require.ErrorIs(t, err, service.ErrRefundFailed)
+	mockPaymentRepo.AssertExpectations(t)
+	mockPaymentRepo.AssertNotCalled(t, "CompleteRefund", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPaymentService_RefundPaymentOutcomeUnknown(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockCartRepo := new(MockCartRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, unreachableProvider{}, "usd")
+	mockPaymentRepo.On("GetPayment", 1).Return(&models.Payment{ID: 1, CheckoutID: 7}, nil)
+	mockPaymentRepo.On("CreateRefund", 1, "key-1", models.RefundRequest{}).
+		Return(&models.Refund{ID: 4, PaymentID: 1, Amount: 6.97, Status: models.PaymentStatusPending}, nil)
+
+	_, err := paymentService.RefundPayment(context.Background(), 1, "key-1", models.RefundRequest{})
+
+	// The provider may have made the refund, so it stays pending for a retry.
+	require.ErrorIs(t, err, service.ErrRefundPending)
+	mockPaymentRepo.AssertNotCalled(t, "FailRefund", mock.Anything)
+	mockPaymentRepo.AssertNotCalled(t, "CompleteRefund", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPaymentService_RefundPaymentReplaysFailedRefund(t *testing.T) {
+	mockPaymentRepo := new(MockPaymentRepository)
+	mockCartRepo := new(MockCartRepository)
+	paymentService := service.NewPaymentService(mockPaymentRepo, mockCartRepo, payments.FakeProvider{}, "usd")
+	failed := &models.Refund{ID: 4, PaymentID: 1, Amount: 2, Status: models.PaymentStatusFailed}
+	mockPaymentRepo.On("GetPayment", 1).Return(&models.Payment{ID: 1, CheckoutID: 7}, nil)
+	mockPaymentRepo.On("CreateRefund", 1, "key-1", models.RefundRequest{}).Return(failed, nil)
+
+	refund, err := paymentService.RefundPayment(context.Background(), 1, "key-1", models.RefundRequest{})
+
+	require.ErrorIs(t, err, service.ErrRefundFailed)
+	assert.Nil(t, refund)
+}
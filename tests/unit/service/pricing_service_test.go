@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+type MockPricingRepository struct {
+	mock.Mock
+}
+
+func (m *MockPricingRepository) CreatePricingRule(
+	_ context.Context,
+	ruleReq models.PricingRuleRequest,
+) (*models.PricingRule, error) {
+	args := m.Called(ruleReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PricingRule), args.Error(1)
+}
+
+func (m *MockPricingRepository) ListPricingRules(_ context.Context) ([]models.PricingRule, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PricingRule), args.Error(1)
+}
+
+func (m *MockPricingRepository) DeletePricingRule(_ context.Context, ruleID int) error {
+	return m.Called(ruleID).Error(0)
+}
+
+func (m *MockPricingRepository) GetFilmPricing(_ context.Context, filmID int) (*models.FilmPricing, error) {
+	args := m.Called(filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FilmPricing), args.Error(1)
+}
+
+func (m *MockPricingRepository) GetCustomerStoreID(_ context.Context, customerID int) (int, error) {
+	args := m.Called(customerID)
+	return args.Int(0), args.Error(1)
+}
+
+func TestPricingService_CreateRuleRejectsContradictions(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	quantity, paid, percent := 2, 2, 10.0
+
+	tests := []struct {
+		name    string
+		ruleReq models.PricingRuleRequest
+	}{
+		{
+			name: "bundle paying for every film",
+			ruleReq: models.PricingRuleRequest{
+				Name: "Two for two", Kind: models.PricingRuleBundle, BundleQuantity: &quantity, BundlePaid: &paid,
+			},
+		},
+		{
+			name: "ends before it starts",
+			ruleReq: models.PricingRuleRequest{
+				Name: "Backwards", Kind: models.PricingRulePercentOff, PercentOff: &percent,
+				StartsAt: &start, EndsAt: &start,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPricingRepository)
+			pricingService := service.NewPricingService(mockRepo)
+
+			_, err := pricingService.CreateRule(context.Background(), tt.ruleReq)
+
+			require.ErrorIs(t, err, service.ErrInvalidPricingRule)
+			mockRepo.AssertNotCalled(t, "CreatePricingRule", mock.Anything)
+		})
+	}
+}
+
+func TestPricingService_GetFilmPrice(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	storeOne, storeTwo, percent := 1, 2, 20.0
+	rules := []models.PricingRule{
+		{ID: 1, Name: "Store one", Kind: models.PricingRulePercentOff, PercentOff: &percent, StoreID: &storeOne},
+		{ID: 2, Name: "Store two", Kind: models.PricingRulePercentOff, PercentOff: &percent, StoreID: &storeTwo},
+	}
+	customerID := 600
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		customerID   *int
+		expectedRule int
+	}{
+		{name: "scoped store", ctx: tenant.WithStoreID(context.Background(), 1), expectedRule: 1},
+		{
+			name:         "customer's store wins over the scoped store",
+			ctx:          tenant.WithStoreID(context.Background(), 1),
+			customerID:   &customerID,
+			expectedRule: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPricingRepository)
+			pricingService := service.NewPricingService(mockRepo)
+			mockRepo.On("GetFilmPricing", 1).Return(&models.FilmPricing{FilmID: 1, RentalRate: 5}, nil)
+			mockRepo.On("GetCustomerStoreID", customerID).Return(storeTwo, nil).Maybe()
+			mockRepo.On("ListPricingRules").Return(rules, nil)
+
+			price, err := pricingService.GetFilmPrice(tt.ctx, 1, tt.customerID, at)
+
+			require.NoError(t, err)
+			require.Len(t, price.AppliedRules, 1)
+			assert.Equal(t, tt.expectedRule, price.AppliedRules[0].RuleID)
+			assert.InDelta(t, 4.0, price.EffectiveRate, 0.001)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPricingService_GetFilmPriceUnknownCustomer(t *testing.T) {
+	mockRepo := new(MockPricingRepository)
+	pricingService := service.NewPricingService(mockRepo)
+	mockRepo.On("GetFilmPricing", 1).Return(&models.FilmPricing{FilmID: 1, RentalRate: 5}, nil)
+	mockRepo.On("GetCustomerStoreID", 999).Return(0, repository.ErrCustomerNotFound)
+	customerID := 999
+
+	_, err := pricingService.GetFilmPrice(context.Background(), 1, &customerID, time.Now())
+
+	require.ErrorIs(t, err, repository.ErrCustomerNotFound)
+	mockRepo.AssertNotCalled(t, "ListPricingRules")
+}
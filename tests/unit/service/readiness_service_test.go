@@ -0,0 +1,61 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockSchemaRepository struct {
+	mock.Mock
+}
+
+func (m *MockSchemaRepository) GetSchemaVersion(_ context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestReadinessService_CheckReadiness(t *testing.T) {
+	tests := []struct {
+		name          string
+		schemaVersion int64
+		ready         bool
+		reason        string
+	}{
+		{name: "matching schema", schemaVersion: 30, ready: true},
+		{name: "newer schema", schemaVersion: 31, reason: "database schema is newer than this build"},
+		{name: "older schema", schemaVersion: 29, reason: "database schema is behind this build"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockSchemaRepository)
+			mockRepo.On("GetSchemaVersion").Return(tt.schemaVersion, nil)
+			svc := service.NewReadinessService(mockRepo, 30)
+
+			readiness, err := svc.CheckReadiness(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.ready, readiness.Ready)
+			assert.Equal(t, tt.reason, readiness.Reason)
+			assert.Equal(t, tt.schemaVersion, readiness.SchemaVersion)
+			assert.Equal(t, int64(30), readiness.ExpectedVersion)
+		})
+	}
+}
+
+func TestReadinessService_CheckReadinessFails(t *testing.T) {
+	mockRepo := new(MockSchemaRepository)
+	mockRepo.On("GetSchemaVersion").Return(int64(0), errors.New("connection refused"))
+	svc := service.NewReadinessService(mockRepo, 30)
+
+	_, err := svc.CheckReadiness(context.Background())
+
+	assert.Error(t, err)
+}
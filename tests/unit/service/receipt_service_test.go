@@ -0,0 +1,119 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockReceiptRepository struct {
+	mock.Mock
+}
+
+func (m *MockReceiptRepository) GetRentalReceipt(_ context.Context, rentalID int) (*models.Receipt, error) {
+	args := m.Called(rentalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Receipt), args.Error(1)
+}
+
+func (m *MockReceiptRepository) GetCustomerInvoices(
+	_ context.Context,
+	customerID int,
+	filters models.InvoiceFilters,
+) (*pagination.Paginated[models.Invoice], error) {
+	args := m.Called(customerID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.Invoice]), args.Error(1)
+}
+
+func TestReceiptService_GetReceipt(t *testing.T) {
+	tests := []struct {
+		name          string
+		claims        *auth.Claims
+		expectedError error
+	}{
+		{name: "own rental", claims: &auth.Claims{Subject: 600, Role: auth.RoleCustomer}},
+		{name: "staff", claims: &auth.Claims{Subject: 1, Role: auth.RoleStaff}},
+		{
+			name:          "another customer's rental",
+			claims:        &auth.Claims{Subject: 601, Role: auth.RoleCustomer},
+			expectedError: repository.ErrRentalNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockReceiptRepository)
+			receiptService := service.NewReceiptService(mockRepo)
+			mockRepo.On("GetRentalReceipt", 42).Return(&models.Receipt{
+				RentalID:   42,
+				CustomerID: 600,
+				Payments: []models.ReceiptPayment{
+					{PaymentID: 1, Amount: 4.99},
+					{PaymentID: 2, Amount: 0.1},
+					{PaymentID: 3, Amount: -1.5},
+				},
+			}, nil)
+
+			receipt, err := receiptService.GetReceipt(auth.WithClaims(context.Background(), tt.claims), 42)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, 3.59, receipt.AmountPaid, 0.0001)
+			assert.False(t, receipt.IssuedAt.IsZero())
+		})
+	}
+}
+
+func TestReceiptService_GetCustomerInvoices(t *testing.T) {
+	mockRepo := new(MockReceiptRepository)
+	receiptService := service.NewReceiptService(mockRepo)
+	filters := models.InvoiceFilters{Page: 1, Limit: 20}
+	mockRepo.On("GetCustomerInvoices", 600, filters).Return(&pagination.Paginated[models.Invoice]{
+		Items: []models.Invoice{{RentalID: 42}, {RentalID: 7}},
+		Total: 2,
+	}, nil)
+
+	invoices, err := receiptService.GetCustomerInvoices(context.Background(), 600, filters)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/rentals/42/receipt", invoices.Items[0].ReceiptURL)
+	assert.Equal(t, "/api/v1/rentals/7/receipt", invoices.Items[1].ReceiptURL)
+}
+
+func TestReceiptService_GetCustomerInvoicesSignsReceiptURLs(t *testing.T) {
+	mockRepo := new(MockReceiptRepository)
+	signer := auth.NewURLSigner("secret", time.Hour, "https://api.example.com")
+	receiptService := service.NewReceiptService(mockRepo, service.WithSignedReceiptURLs(signer))
+	filters := models.InvoiceFilters{Page: 1, Limit: 20}
+	mockRepo.On("GetCustomerInvoices", 600, filters).Return(&pagination.Paginated[models.Invoice]{
+		Items: []models.Invoice{{RentalID: 42}},
+		Total: 1,
+	}, nil)
+
+	invoices, err := receiptService.GetCustomerInvoices(context.Background(), 600, filters)
+
+	require.NoError(t, err)
+	signed, err := url.Parse(invoices.Items[0].SignedReceiptURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/rentals/42/receipt", signed.Path)
+	assert.NoError(t, signer.Verify(signed))
+}
@@ -0,0 +1,243 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+type MockRentalRepository struct {
+	mock.Mock
+}
+
+func (m *MockRentalRepository) MarkOverdueRentals(_ context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRentalRepository) ClaimDueReminders(
+	_ context.Context,
+	window time.Duration,
+) ([]models.RentalDueReminder, error) {
+	args := m.Called(window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RentalDueReminder), args.Error(1)
+}
+
+func (m *MockRentalRepository) RefreshTrendingFilms(_ context.Context, since time.Time, limit int) error {
+	args := m.Called(since, limit)
+	return args.Error(0)
+}
+
+func (m *MockRentalRepository) GetTrendingFilms(_ context.Context) ([]models.TrendingFilm, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TrendingFilm), args.Error(1)
+}
+
+func (m *MockRentalRepository) GetFilmRentals(
+	_ context.Context,
+	filmID int,
+	filters models.RentalHistoryFilters,
+) (*pagination.Paginated[models.RentalEvent], error) {
+	args := m.Called(filmID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.RentalEvent]), args.Error(1)
+}
+
+func (m *MockRentalRepository) GetCustomerRentals(
+	_ context.Context,
+	customerID int,
+	filters models.RentalHistoryFilters,
+) (*pagination.Paginated[models.RentalEvent], error) {
+	args := m.Called(customerID, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.RentalEvent]), args.Error(1)
+}
+
+func (m *MockRentalRepository) GetOverdueFilms(
+	_ context.Context,
+	filters models.OverdueReportFilters,
+) ([]models.OverdueFilm, error) {
+	args := m.Called(filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OverdueFilm), args.Error(1)
+}
+
+func (m *MockRentalRepository) ReturnRental(_ context.Context, rentalID int) (*models.RentalEvent, error) {
+	args := m.Called(rentalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RentalEvent), args.Error(1)
+}
+
+type recordingDueNotifier struct {
+	reminders []notifications.RentalDue
+	err       error
+}
+
+func (n *recordingDueNotifier) NotifyRentalDue(due notifications.RentalDue) error {
+	n.reminders = append(n.reminders, due)
+	return n.err
+}
+
+func TestRentalService_SendDueReminders(t *testing.T) {
+	dueAt := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	mockRepo := new(MockRentalRepository)
+	notifier := &recordingDueNotifier{err: errors.New("queue full")}
+	rentalService := service.NewRentalService(mockRepo, notifier, 24*time.Hour, time.Hour, 1.00)
+	mockRepo.On("ClaimDueReminders", 24*time.Hour).Return([]models.RentalDueReminder{
+		{RentalID: 1, CustomerID: 600, CustomerEmail: "a@example.com", CustomerName: "Ann", FilmTitle: "Alien", DueAt: dueAt},
+		{RentalID: 2, CustomerID: 601, CustomerEmail: "b@example.com", CustomerName: "Bob", FilmTitle: "Brazil", DueAt: dueAt},
+	}, nil)
+
+	// A failed send is logged and the remaining reminders still go out.
+	err := rentalService.SendDueReminders(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, notifier.reminders, 2)
+	assert.Equal(t, notifications.RentalDue{
+		RecipientID:    600,
+		RecipientEmail: "a@example.com",
+		RecipientName:  "Ann",
+		FilmTitle:      "Alien",
+		DueAt:          dueAt,
+	}, notifier.reminders[0])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRentalService_SendDueRemindersClaimError(t *testing.T) {
+	mockRepo := new(MockRentalRepository)
+	notifier := &recordingDueNotifier{}
+	rentalService := service.NewRentalService(mockRepo, notifier, time.Hour, time.Hour, 1.00)
+	mockRepo.On("ClaimDueReminders", time.Hour).Return(nil, errors.New("db down"))
+
+	err := rentalService.SendDueReminders(context.Background())
+
+	require.Error(t, err)
+	assert.Empty(t, notifier.reminders)
+}
+
+func TestRentalService_MarkOverdueRentals(t *testing.T) {
+	mockRepo := new(MockRentalRepository)
+	rentalService := service.NewRentalService(mockRepo, &recordingDueNotifier{}, time.Hour, time.Hour, 1.00)
+	mockRepo.On("MarkOverdueRentals").Return(int64(3), nil)
+
+	require.NoError(t, rentalService.MarkOverdueRentals(context.Background()))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRentalService_RefreshTrendingFilms(t *testing.T) {
+	mockRepo := new(MockRentalRepository)
+	rentalService := service.NewRentalService(mockRepo, &recordingDueNotifier{}, time.Hour, 7*24*time.Hour, 1.00)
+	mockRepo.On("RefreshTrendingFilms", mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since).Round(time.Hour) == 7*24*time.Hour
+	}), 10).Return(nil)
+
+	require.NoError(t, rentalService.RefreshTrendingFilms(context.Background()))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRentalService_GetFilmRentalsScopedToStore(t *testing.T) {
+	mockRepo := new(MockRentalRepository)
+	rentalService := service.NewRentalService(mockRepo, &recordingDueNotifier{}, time.Hour, time.Hour, 1.00)
+	filters := models.RentalHistoryFilters{Params: pagination.Params{Page: 1, Limit: 20}}
+	scoped := filters
+	scoped.StoreID = 2
+	history := &pagination.Paginated[models.RentalEvent]{
+		Items: []models.RentalEvent{{RentalID: 1}}, Total: 1, Page: 1, Limit: 20,
+	}
+	mockRepo.On("GetFilmRentals", 1, scoped).Return(history, nil)
+
+	result, err := rentalService.GetFilmRentals(tenant.WithStoreID(context.Background(), 2), 1, filters)
+
+	require.NoError(t, err)
+	assert.Equal(t, history, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRentalService_GetOverdueReport(t *testing.T) {
+	mockRepo := new(MockRentalRepository)
+	rentalService := service.NewRentalService(mockRepo, &recordingDueNotifier{}, time.Hour, time.Hour, 1.50)
+	mockRepo.On("GetOverdueFilms", models.OverdueReportFilters{
+		Sort: models.OverdueSortFees, Order: "desc", StoreID: 2, LateFeePerDay: 1.50,
+	}).Return([]models.OverdueFilm{
+		{FilmID: 1, Title: "Alien", OverdueRentals: 2, TotalDaysOverdue: 5, MaxDaysOverdue: 3, AccruedFees: 7.50},
+		{FilmID: 2, Title: "Brazil", OverdueRentals: 1, TotalDaysOverdue: 1, MaxDaysOverdue: 1, AccruedFees: 1.50},
+	}, nil)
+
+	report, err := rentalService.GetOverdueReport(
+		tenant.WithStoreID(context.Background(), 2),
+		models.OverdueReportFilters{Sort: models.OverdueSortFees, Order: "desc"},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.OverdueRentals)
+	assert.InDelta(t, 9.00, report.AccruedFees, 0)
+	assert.InDelta(t, 1.50, report.LateFeePerDay, 0)
+	assert.Len(t, report.Films, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+type recordingAvailabilityDispatcher struct {
+	filmIDs []int
+	err     error
+}
+
+func (d *recordingAvailabilityDispatcher) NotifyAvailable(_ context.Context, filmID int) error {
+	d.filmIDs = append(d.filmIDs, filmID)
+	return d.err
+}
+
+func TestRentalService_ReturnRental(t *testing.T) {
+	mockRepo := new(MockRentalRepository)
+	dispatcher := &recordingAvailabilityDispatcher{err: errors.New("queue full")}
+	rentalService := service.NewRentalService(mockRepo, &recordingDueNotifier{}, time.Hour, time.Hour, 1.00,
+		service.WithAvailabilityDispatcher(dispatcher))
+	returned := &models.RentalEvent{RentalID: 5, FilmID: 8, Status: models.RentalStatusReturned}
+	mockRepo.On("ReturnRental", 5).Return(returned, nil)
+
+	// The return stands even when the waiting customers cannot be notified.
+	rental, err := rentalService.ReturnRental(context.Background(), 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, returned, rental)
+	assert.Equal(t, []int{8}, dispatcher.filmIDs)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRentalService_ReturnRentalAlreadyReturned(t *testing.T) {
+	mockRepo := new(MockRentalRepository)
+	dispatcher := &recordingAvailabilityDispatcher{}
+	rentalService := service.NewRentalService(mockRepo, &recordingDueNotifier{}, time.Hour, time.Hour, 1.00,
+		service.WithAvailabilityDispatcher(dispatcher))
+	mockRepo.On("ReturnRental", 5).Return(nil, repository.ErrRentalReturned)
+
+	_, err := rentalService.ReturnRental(context.Background(), 5)
+
+	require.ErrorIs(t, err, repository.ErrRentalReturned)
+	assert.Empty(t, dispatcher.filmIDs)
+}
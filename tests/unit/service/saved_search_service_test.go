@@ -0,0 +1,145 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/notifications"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockSavedSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchRepository) CreateSavedSearch(
+	_ context.Context,
+	customerID int,
+	searchReq models.SavedSearchRequest,
+) (*models.SavedSearch, error) {
+	args := m.Called(customerID, searchReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) ListSavedSearches(_ context.Context, customerID int) ([]models.SavedSearch, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) DeleteSavedSearch(_ context.Context, customerID, searchID int) error {
+	return m.Called(customerID, searchID).Error(0)
+}
+
+func (m *MockSavedSearchRepository) ListAlertingSearches(
+	_ context.Context,
+) ([]models.SavedSearchAlert, time.Time, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, time.Time{}, args.Error(2)
+	}
+	return args.Get(0).([]models.SavedSearchAlert), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockSavedSearchRepository) MarkSearchChecked(_ context.Context, searchID int, checkedAt time.Time) error {
+	return m.Called(searchID, checkedAt).Error(0)
+}
+
+type MockFilmPublishedRepository struct {
+	mock.Mock
+}
+
+func (m *MockFilmPublishedRepository) ListPublishedBetween(
+	_ context.Context,
+	filters models.FilmFilters,
+	after, until time.Time,
+	limit int,
+) ([]models.Film, error) {
+	args := m.Called(filters, after, until, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Film), args.Error(1)
+}
+
+type recordingSavedSearchNotifier struct {
+	matches []notifications.SavedSearchMatch
+}
+
+func (n *recordingSavedSearchNotifier) NotifySavedSearchMatch(match notifications.SavedSearchMatch) error {
+	n.matches = append(n.matches, match)
+	return nil
+}
+
+func TestSavedSearchService_CreateSavedSearchNormalizesTags(t *testing.T) {
+	mockRepo := new(MockSavedSearchRepository)
+	searchService := service.NewSavedSearchService(mockRepo, new(MockFilmPublishedRepository),
+		&recordingSavedSearchNotifier{})
+	saved := models.SavedSearchRequest{Name: "Noir", Filters: models.SavedSearchFilters{Tags: []string{"film noir"}},
+		Notify: true}
+	search := &models.SavedSearch{ID: 1, CustomerID: 600, Name: "Noir", Filters: saved.Filters, Notify: true}
+	mockRepo.On("CreateSavedSearch", 600, saved).Return(search, nil)
+	mockRepo.On("CreateSavedSearch", 601, mock.Anything).Return(nil, repository.ErrSavedSearchLimit)
+
+	result, err := searchService.CreateSavedSearch(context.Background(), 600, models.SavedSearchRequest{
+		Name: "Noir", Filters: models.SavedSearchFilters{Tags: []string{"  Film Noir "}}, Notify: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, search, result)
+
+	_, err = searchService.CreateSavedSearch(context.Background(), 601, saved)
+	require.ErrorIs(t, err, repository.ErrSavedSearchLimit)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSavedSearchService_NotifyNewMatches(t *testing.T) {
+	mockRepo := new(MockSavedSearchRepository)
+	mockFilms := new(MockFilmPublishedRepository)
+	notifier := &recordingSavedSearchNotifier{}
+	searchService := service.NewSavedSearchService(mockRepo, mockFilms, notifier)
+	lastChecked := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	checkedAt := lastChecked.Add(time.Hour)
+	horror := models.SavedSearchFilters{Categories: []string{"Horror"}}
+	comedy := models.SavedSearchFilters{Categories: []string{"Comedy"}}
+	broken := models.SavedSearchFilters{Title: "alien"}
+	mockRepo.On("ListAlertingSearches").Return([]models.SavedSearchAlert{
+		{SavedSearch: models.SavedSearch{ID: 1, CustomerID: 600, Name: "Scary", Filters: horror},
+			CustomerEmail: "a@example.com", CustomerName: "Ann", LastCheckedAt: lastChecked},
+		{SavedSearch: models.SavedSearch{ID: 2, CustomerID: 601, Name: "Funny", Filters: comedy},
+			CustomerEmail: "b@example.com", CustomerName: "Bob", LastCheckedAt: lastChecked},
+		{SavedSearch: models.SavedSearch{ID: 3, CustomerID: 602, Name: "Aliens", Filters: broken},
+			CustomerEmail: "c@example.com", CustomerName: "Cat", LastCheckedAt: lastChecked},
+	}, checkedAt, nil)
+	mockFilms.On("ListPublishedBetween", horror.FilmFilters(), lastChecked, checkedAt, mock.Anything).
+		Return([]models.Film{{FilmID: 8, Title: "ALIEN CENTER"}, {FilmID: 9, Title: "ALONE TRIP"}}, nil)
+	mockFilms.On("ListPublishedBetween", comedy.FilmFilters(), lastChecked, checkedAt, mock.Anything).
+		Return([]models.Film{}, nil)
+	mockFilms.On("ListPublishedBetween", broken.FilmFilters(), lastChecked, checkedAt, mock.Anything).
+		Return(nil, errors.New("database error"))
+	mockRepo.On("MarkSearchChecked", 1, checkedAt).Return(nil)
+	mockRepo.On("MarkSearchChecked", 2, checkedAt).Return(nil)
+
+	err := searchService.NotifyNewMatches(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []notifications.SavedSearchMatch{{
+		RecipientID: 600, RecipientEmail: "a@example.com", RecipientName: "Ann", SearchName: "Scary",
+		FilmTitles: []string{"ALIEN CENTER", "ALONE TRIP"},
+	}}, notifier.matches)
+	// A search that could not be checked is left for the next run.
+	mockRepo.AssertNotCalled(t, "MarkSearchChecked", 3, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
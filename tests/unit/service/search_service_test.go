@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/pagination"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) RecordMiss(_ context.Context, term string) error {
+	return m.Called(term).Error(0)
+}
+
+func (m *MockSearchRepository) ListMisses(
+	_ context.Context,
+	params pagination.Params,
+) (*pagination.Paginated[models.SearchMiss], error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pagination.Paginated[models.SearchMiss]), args.Error(1)
+}
+
+func (m *MockSearchRepository) SuggestTitles(_ context.Context, term string, limit int) ([]string, error) {
+	args := m.Called(term, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestSearchService_SuggestTitles(t *testing.T) {
+	tests := []struct {
+		name        string
+		title       string
+		term        string
+		recordErr   error
+		suggestErr  error
+		expected    []string
+		expectCalls bool
+	}{
+		{name: "suggested", title: "  Acadmy Dinosaur ", term: "acadmy dinosaur",
+			expected: []string{"ACADEMY DINOSAUR"}, expectCalls: true},
+		{name: "suggested despite failed record", title: "acadmy", term: "acadmy",
+			recordErr: errors.New("boom"), expected: []string{"ACADEMY DINOSAUR"}, expectCalls: true},
+		{name: "no pg_trgm", title: "acadmy", term: "acadmy", suggestErr: errors.New("no function similarity"),
+			expectCalls: true},
+		{name: "long term cut short", title: strings.Repeat("a", 150), term: strings.Repeat("a", 100),
+			expected: []string{"ACADEMY DINOSAUR"}, expectCalls: true},
+		{name: "blank", title: "   "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockSearchRepository)
+			searchService := service.NewSearchService(mockRepo)
+			if tt.expectCalls {
+				mockRepo.On("RecordMiss", tt.term).Return(tt.recordErr)
+				if tt.suggestErr != nil {
+					mockRepo.On("SuggestTitles", tt.term, 5).Return(nil, tt.suggestErr)
+				} else {
+					mockRepo.On("SuggestTitles", tt.term, 5).Return(tt.expected, nil)
+				}
+			}
+
+			suggestions := searchService.SuggestTitles(context.Background(), tt.title)
+
+			assert.Equal(t, tt.expected, suggestions)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
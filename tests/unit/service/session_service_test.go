@@ -0,0 +1,218 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) CreateSession(
+	_ context.Context,
+	session models.AuthSession,
+	tokenHash string,
+) (*models.AuthSession, error) {
+	args := m.Called(session, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthSession), args.Error(1)
+}
+
+func (m *MockSessionRepository) RotateRefreshToken(
+	_ context.Context,
+	tokenHash, newHash string,
+	client models.SessionClient,
+) (*models.AuthSession, error) {
+	args := m.Called(tokenHash, newHash, client)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthSession), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeSession(_ context.Context, sessionID int64) error {
+	return m.Called(sessionID).Error(0)
+}
+
+func (m *MockSessionRepository) ListActiveSessions(
+	_ context.Context,
+	role string,
+	subjectID int,
+) ([]models.AuthSession, error) {
+	args := m.Called(role, subjectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuthSession), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeSubjectSession(
+	_ context.Context,
+	role string,
+	subjectID int,
+	sessionID int64,
+) error {
+	return m.Called(role, subjectID, sessionID).Error(0)
+}
+
+func (m *MockSessionRepository) IsSessionRevoked(_ context.Context, sessionID int64) (bool, error) {
+	args := m.Called(sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestSessionService_StartSession(t *testing.T) {
+	sessionRepo := new(MockSessionRepository)
+	customerTokens := auth.NewTokenIssuer(auth.RoleCustomer, "secret", time.Hour)
+	sessionService := service.NewSessionService(sessionRepo, 30*24*time.Hour, customerTokens)
+	session := &models.AuthSession{ID: 9, Role: auth.RoleCustomer, SubjectID: 600,
+		ExpiresAt: time.Now().Add(30 * 24 * time.Hour)}
+	var tokenHash string
+	client := models.SessionClient{UserAgent: "Firefox", IPAddress: "203.0.113.7"}
+	var created models.AuthSession
+	sessionRepo.On("CreateSession", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			created = args.Get(0).(models.AuthSession)
+			tokenHash = args.String(1)
+		}).
+		Return(session, nil)
+
+	ctx := auth.WithSessionClient(context.Background(), client)
+	tokens, err := sessionService.StartSession(ctx, auth.RoleCustomer, 600, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, auth.HashRefreshToken(tokens.RefreshToken), tokenHash)
+	assert.Equal(t, auth.RoleCustomer, created.Role)
+	assert.Equal(t, 600, created.SubjectID)
+	assert.Equal(t, "Firefox", created.UserAgent)
+	assert.Equal(t, "203.0.113.7", created.IPAddress)
+	assert.Equal(t, &session.ExpiresAt, tokens.RefreshExpiresAt)
+	claims, err := customerTokens.Verify(tokens.Token)
+	require.NoError(t, err)
+	assert.Equal(t, 600, claims.Subject)
+	assert.Equal(t, int64(9), claims.SessionID)
+	assert.False(t, claims.TwoFactor)
+}
+
+func TestSessionService_StartSessionWithTwoFactor(t *testing.T) {
+	sessionRepo := new(MockSessionRepository)
+	staffTokens := auth.NewTokenIssuer(auth.RoleStaff, "secret", time.Hour)
+	sessionService := service.NewSessionService(sessionRepo, 30*24*time.Hour, staffTokens)
+	sessionRepo.On("CreateSession", mock.MatchedBy(func(s models.AuthSession) bool { return s.TwoFactor }),
+		mock.Anything).
+		Return(&models.AuthSession{ID: 4, Role: auth.RoleStaff, SubjectID: 1, TwoFactor: true,
+			ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	tokens, err := sessionService.StartSession(context.Background(), auth.RoleStaff, 1, true)
+
+	require.NoError(t, err)
+	claims, err := staffTokens.Verify(tokens.Token)
+	require.NoError(t, err)
+	assert.True(t, claims.TwoFactor)
+}
+
+func TestSessionService_Refresh(t *testing.T) {
+	tests := []struct {
+		name        string
+		rotateErr   error
+		expectedErr error
+	}{
+		{name: "rotated"},
+		{name: "unknown token", rotateErr: repository.ErrSessionNotFound, expectedErr: service.ErrInvalidRefreshToken},
+		{name: "reused token", rotateErr: repository.ErrRefreshTokenReused, expectedErr: service.ErrInvalidRefreshToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := new(MockSessionRepository)
+			staffTokens := auth.NewTokenIssuer(auth.RoleStaff, "secret", time.Hour)
+			sessionService := service.NewSessionService(sessionRepo, 30*24*time.Hour, staffTokens)
+			if tt.rotateErr != nil {
+				sessionRepo.On("RotateRefreshToken", auth.HashRefreshToken("mbr_old"), mock.Anything, mock.Anything).
+					Return(nil, tt.rotateErr)
+			} else {
+				sessionRepo.On("RotateRefreshToken", auth.HashRefreshToken("mbr_old"), mock.Anything, mock.Anything).
+					Return(&models.AuthSession{ID: 3, Role: auth.RoleStaff, SubjectID: 1,
+						ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			}
+
+			tokens, err := sessionService.Refresh(context.Background(), "mbr_old")
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, "mbr_old", tokens.RefreshToken)
+			sessionRepo.AssertCalled(t, "RotateRefreshToken", auth.HashRefreshToken("mbr_old"),
+				auth.HashRefreshToken(tokens.RefreshToken), models.SessionClient{})
+		})
+	}
+}
+
+func TestSessionService_AccessTokenNeverOutlivesSession(t *testing.T) {
+	sessionRepo := new(MockSessionRepository)
+	staffTokens := auth.NewTokenIssuer(auth.RoleStaff, "secret", 8*time.Hour)
+	sessionService := service.NewSessionService(sessionRepo, 30*24*time.Hour, staffTokens)
+	sessionEnd := time.Now().Add(time.Hour)
+	sessionRepo.On("RotateRefreshToken", mock.Anything, mock.Anything, mock.Anything).
+		Return(&models.AuthSession{ID: 3, Role: auth.RoleStaff, SubjectID: 1, ExpiresAt: sessionEnd}, nil)
+
+	tokens, err := sessionService.Refresh(context.Background(), "mbr_old")
+
+	require.NoError(t, err)
+	assert.Equal(t, sessionEnd, tokens.ExpiresAt)
+}
+
+func TestSessionService_Logout(t *testing.T) {
+	sessionRepo := new(MockSessionRepository)
+	sessionService := service.NewSessionService(sessionRepo, time.Hour)
+	sessionRepo.On("RevokeSession", int64(9)).Return(nil)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: 600, Role: auth.RoleCustomer, SessionID: 9})
+	require.NoError(t, sessionService.Logout(ctx))
+	sessionRepo.AssertExpectations(t)
+
+	sessionless := auth.WithClaims(context.Background(), &auth.Claims{Subject: 600, Role: auth.RoleCustomer})
+	assert.ErrorIs(t, sessionService.Logout(sessionless), service.ErrSessionTokenRequired)
+}
+
+func TestSessionService_ListCustomerSessionsMarksCurrent(t *testing.T) {
+	sessionRepo := new(MockSessionRepository)
+	sessionService := service.NewSessionService(sessionRepo, time.Hour)
+	sessionRepo.On("ListActiveSessions", auth.RoleCustomer, 600).Return([]models.AuthSession{
+		{ID: 9, Role: auth.RoleCustomer, SubjectID: 600, UserAgent: "Firefox"},
+		{ID: 4, Role: auth.RoleCustomer, SubjectID: 600, UserAgent: "Safari"},
+	}, nil)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Subject: 600, Role: auth.RoleCustomer, SessionID: 4})
+	sessions, err := sessionService.ListCustomerSessions(ctx, 600)
+
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+}
+
+func TestSessionService_RevokeCustomerSession(t *testing.T) {
+	sessionRepo := new(MockSessionRepository)
+	sessionService := service.NewSessionService(sessionRepo, time.Hour)
+	sessionRepo.On("RevokeSubjectSession", auth.RoleCustomer, 600, int64(9)).Return(nil)
+	sessionRepo.On("RevokeSubjectSession", auth.RoleCustomer, 600, int64(10)).Return(repository.ErrSessionNotFound)
+
+	require.NoError(t, sessionService.RevokeCustomerSession(context.Background(), 600, 9))
+	assert.ErrorIs(t, sessionService.RevokeCustomerSession(context.Background(), 600, 10),
+		repository.ErrSessionNotFound)
+}
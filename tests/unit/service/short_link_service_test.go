@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockShortLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockShortLinkRepository) CreateShortLink(
+	_ context.Context,
+	code string,
+	filmID int,
+	campaign *string,
+) (*models.ShortLink, error) {
+	args := m.Called(code, filmID, campaign)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShortLink), args.Error(1)
+}
+
+func (m *MockShortLinkRepository) ListFilmShortLinks(_ context.Context, filmID int) ([]models.ShortLink, error) {
+	args := m.Called(filmID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ShortLink), args.Error(1)
+}
+
+func (m *MockShortLinkRepository) RecordClick(_ context.Context, code string) (int, error) {
+	args := m.Called(code)
+	return args.Int(0), args.Error(1)
+}
+
+func TestShortLinkService_CreateShortLinkRetriesTakenCode(t *testing.T) {
+	mockRepo := new(MockShortLinkRepository)
+	shortLinkService := service.NewShortLinkService(mockRepo, "/api/v1/films/{id}")
+	campaign := "spring-sale"
+	mockRepo.On("CreateShortLink", mock.Anything, 1, &campaign).Return(nil, repository.ErrShortLinkCodeTaken).Once()
+	mockRepo.On("CreateShortLink", mock.Anything, 1, &campaign).
+		Return(&models.ShortLink{ID: 7, Code: "k3x9qm2a", FilmID: 1, Campaign: &campaign}, nil).Once()
+
+	link, err := shortLinkService.CreateShortLink(context.Background(), 1, models.ShortLinkRequest{Campaign: &campaign})
+
+	require.NoError(t, err)
+	assert.Equal(t, "/f/k3x9qm2a", link.Path)
+	for _, call := range mockRepo.Calls {
+		assert.Regexp(t, `^[0-9a-z]{8}$`, call.Arguments.String(0))
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestShortLinkService_CreateShortLinkGivesUpOnTakenCodes(t *testing.T) {
+	mockRepo := new(MockShortLinkRepository)
+	shortLinkService := service.NewShortLinkService(mockRepo, "/api/v1/films/{id}")
+	mockRepo.On("CreateShortLink", mock.Anything, 1, (*string)(nil)).Return(nil, repository.ErrShortLinkCodeTaken)
+
+	_, err := shortLinkService.CreateShortLink(context.Background(), 1, models.ShortLinkRequest{})
+
+	require.ErrorIs(t, err, repository.ErrShortLinkCodeTaken)
+	mockRepo.AssertNumberOfCalls(t, "CreateShortLink", 3)
+}
+
+func TestShortLinkService_CreateShortLinkUnknownFilm(t *testing.T) {
+	mockRepo := new(MockShortLinkRepository)
+	shortLinkService := service.NewShortLinkService(mockRepo, "/api/v1/films/{id}")
+	mockRepo.On("CreateShortLink", mock.Anything, 999, (*string)(nil)).Return(nil, repository.ErrFilmNotFound)
+
+	_, err := shortLinkService.CreateShortLink(context.Background(), 999, models.ShortLinkRequest{})
+
+	require.ErrorIs(t, err, repository.ErrFilmNotFound)
+	mockRepo.AssertNumberOfCalls(t, "CreateShortLink", 1)
+}
+
+func TestShortLinkService_FollowShortLink(t *testing.T) {
+	mockRepo := new(MockShortLinkRepository)
+	shortLinkService := service.NewShortLinkService(mockRepo, "https://mockbuster.example/films/{id}?utm_source=short")
+	mockRepo.On("RecordClick", "k3x9qm2a").Return(42, nil)
+	mockRepo.On("RecordClick", "missing1").Return(0, repository.ErrShortLinkNotFound)
+
+	target, err := shortLinkService.FollowShortLink(context.Background(), " K3X9QM2A ")
+	require.NoError(t, err)
+	assert.Equal(t, "https://mockbuster.example/films/42?utm_source=short", target)
+
+	_, err = shortLinkService.FollowShortLink(context.Background(), "missing1")
+	require.ErrorIs(t, err, repository.ErrShortLinkNotFound)
+}
+
+func TestShortLinkService_ListFilmShortLinksSetsPaths(t *testing.T) {
+	mockRepo := new(MockShortLinkRepository)
+	shortLinkService := service.NewShortLinkService(mockRepo, "/api/v1/films/{id}")
+	mockRepo.On("ListFilmShortLinks", 1).Return([]models.ShortLink{{Code: "aaaa1111", Clicks: 3}}, nil)
+
+	links, err := shortLinkService.ListFilmShortLinks(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.ShortLink{{Code: "aaaa1111", Path: "/f/aaaa1111", Clicks: 3}}, links)
+}
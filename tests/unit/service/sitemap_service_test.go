@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/service"
+	"github.com/rxbenefits/go-hw/internal/sitemaps"
+)
+
+type MockFilmSitemapRepository struct {
+	mock.Mock
+}
+
+func (m *MockFilmSitemapRepository) ListFilmUpdates(_ context.Context) ([]models.FilmUpdate, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FilmUpdate), args.Error(1)
+}
+
+// filmUpdates returns count films with IDs from 1, the film with ID newest
+// changed latest.
+func filmUpdates(count, newest int) []models.FilmUpdate {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	films := make([]models.FilmUpdate, count)
+	for i := range films {
+		films[i] = models.FilmUpdate{FilmID: i + 1, LastUpdate: base}
+	}
+	films[newest-1].LastUpdate = base.AddDate(0, 1, 0)
+	return films
+}
+
+func newSitemapService(repo *MockFilmSitemapRepository) service.SitemapService {
+	return service.NewSitemapService(repo, cache.NewMemoryCache(time.Hour), "https://mockbuster.example",
+		"https://mockbuster.example/films/{id}")
+}
+
+func TestSitemapService_SitemapIndexPaginates(t *testing.T) {
+	mockRepo := new(MockFilmSitemapRepository)
+	sitemapService := newSitemapService(mockRepo)
+	mockRepo.On("ListFilmUpdates").Return(filmUpdates(2500, 1200), nil).Once()
+
+	index, err := sitemapService.SitemapIndex(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []sitemaps.Sitemap{
+		{Loc: "https://mockbuster.example/sitemaps/films-1.xml", LastMod: "2024-01-01T00:00:00Z"},
+		{Loc: "https://mockbuster.example/sitemaps/films-2.xml", LastMod: "2024-02-01T00:00:00Z"},
+		{Loc: "https://mockbuster.example/sitemaps/films-3.xml", LastMod: "2024-01-01T00:00:00Z"},
+	}, index.Sitemaps)
+
+	// Pages come from the cached film list.
+	urlSet, err := sitemapService.FilmSitemap(context.Background(), 3)
+	require.NoError(t, err)
+	require.Len(t, urlSet.URLs, 500)
+	assert.Equal(t, sitemaps.URL{Loc: "https://mockbuster.example/films/2001", LastMod: "2024-01-01T00:00:00Z"},
+		urlSet.URLs[0])
+
+	_, err = sitemapService.FilmSitemap(context.Background(), 4)
+	require.ErrorIs(t, err, service.ErrSitemapNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSitemapService_SmallCatalogHasOneSitemap(t *testing.T) {
+	mockRepo := new(MockFilmSitemapRepository)
+	sitemapService := newSitemapService(mockRepo)
+	mockRepo.On("ListFilmUpdates").Return(filmUpdates(1000, 1), nil)
+
+	index, err := sitemapService.SitemapIndex(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, index.Sitemaps, 1)
+
+	urlSet, err := sitemapService.FilmSitemap(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Len(t, urlSet.URLs, 1000)
+
+	_, err = sitemapService.FilmSitemap(context.Background(), 2)
+	require.ErrorIs(t, err, service.ErrSitemapNotFound)
+}
+
+func TestSitemapService_EmptyCatalog(t *testing.T) {
+	mockRepo := new(MockFilmSitemapRepository)
+	sitemapService := newSitemapService(mockRepo)
+	mockRepo.On("ListFilmUpdates").Return([]models.FilmUpdate{}, nil)
+
+	index, err := sitemapService.SitemapIndex(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []sitemaps.Sitemap{{Loc: "https://mockbuster.example/sitemaps/films-1.xml"}}, index.Sitemaps)
+
+	urlSet, err := sitemapService.FilmSitemap(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Empty(t, urlSet.URLs)
+}
@@ -0,0 +1,234 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/apperrors"
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+	"github.com/rxbenefits/go-hw/internal/tenant"
+)
+
+type MockStaffRepository struct {
+	mock.Mock
+}
+
+func (m *MockStaffRepository) ListStaff(_ context.Context, storeID int) ([]models.Staff, error) {
+	args := m.Called(storeID)
+	return args.Get(0).([]models.Staff), args.Error(1)
+}
+
+func (m *MockStaffRepository) CreateStaff(
+	_ context.Context,
+	staffReq models.StaffRequest,
+	passwordHash string,
+) (*models.Staff, error) {
+	args := m.Called(staffReq, passwordHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Staff), args.Error(1)
+}
+
+func (m *MockStaffRepository) DeactivateStaff(_ context.Context, staffID int) (*models.Staff, error) {
+	args := m.Called(staffID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Staff), args.Error(1)
+}
+
+func (m *MockStaffRepository) GetStaffCredentials(_ context.Context, username string) (*models.Staff, string, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*models.Staff), args.String(1), args.Error(2)
+}
+
+func (m *MockStaffRepository) UpdateStaffPassword(_ context.Context, staffID int, passwordHash string) error {
+	args := m.Called(staffID, passwordHash)
+	return args.Error(0)
+}
+
+func newStaffTokens() *auth.TokenIssuer {
+	return auth.NewTokenIssuer(auth.RoleStaff, "secret", time.Hour)
+}
+
+func TestStaffService_ListStaffScopedToStore(t *testing.T) {
+	mockRepo := new(MockStaffRepository)
+	staffService := service.NewStaffService(mockRepo, newStaffTokens())
+	mockRepo.On("ListStaff", 2).Return([]models.Staff{{StaffID: 2, StoreID: 2}}, nil)
+
+	staff, err := staffService.ListStaff(tenant.WithStoreID(context.Background(), 2))
+
+	require.NoError(t, err)
+	assert.Len(t, staff, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStaffService_CreateStaffHashesPassword(t *testing.T) {
+	mockRepo := new(MockStaffRepository)
+	staffService := service.NewStaffService(mockRepo, newStaffTokens())
+	staffReq := models.StaffRequest{Username: "jane", Password: "s3cret-pass"}
+
+	var storedHash string
+	mockRepo.On("CreateStaff", staffReq, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { storedHash = args.String(1) }).
+		Return(&models.Staff{StaffID: 3, Username: "jane", Active: true}, nil)
+
+	member, err := staffService.CreateStaff(context.Background(), staffReq)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, member.StaffID)
+	assert.NotEqual(t, staffReq.Password, storedHash)
+	assert.True(t, auth.CheckPassword(storedHash, staffReq.Password))
+}
+
+func TestStaffService_DeactivateStaffInvalidID(t *testing.T) {
+	mockRepo := new(MockStaffRepository)
+	staffService := service.NewStaffService(mockRepo, newStaffTokens())
+
+	member, err := staffService.DeactivateStaff(context.Background(), 0)
+
+	require.ErrorIs(t, err, service.ErrInvalidStaffID)
+	assert.Equal(t, apperrors.Invalid, apperrors.KindOf(err))
+	assert.Nil(t, member)
+	mockRepo.AssertNotCalled(t, "DeactivateStaff", mock.Anything)
+}
+
+func TestStaffService_Login(t *testing.T) {
+	hash, err := auth.HashPassword("s3cret-pass")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		password      string
+		member        *models.Staff
+		repoError     error
+		expectedError error
+	}{
+		{
+			name:     "valid credentials",
+			password: "s3cret-pass",
+			member:   &models.Staff{StaffID: 1, Active: true},
+		},
+		{
+			name:          "wrong password",
+			password:      "wrong",
+			member:        &models.Staff{StaffID: 1, Active: true},
+			expectedError: service.ErrInvalidCredentials,
+		},
+		{
+			name:          "inactive staff",
+			password:      "s3cret-pass",
+			member:        &models.Staff{StaffID: 1, Active: false},
+			expectedError: service.ErrInvalidCredentials,
+		},
+		{
+			name:          "unknown username",
+			password:      "s3cret-pass",
+			repoError:     repository.ErrStaffNotFound,
+			expectedError: service.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockStaffRepository)
+			tokens := newStaffTokens()
+			staffService := service.NewStaffService(mockRepo, tokens)
+			if tt.member != nil {
+				mockRepo.On("GetStaffCredentials", "mike").Return(tt.member, hash, nil)
+			} else {
+				mockRepo.On("GetStaffCredentials", "mike").Return(nil, "", tt.repoError)
+			}
+
+			token, loginErr := staffService.Login(context.Background(),
+				models.StaffLoginRequest{Username: "mike", Password: tt.password})
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, loginErr, tt.expectedError)
+				assert.Nil(t, token)
+				return
+			}
+			require.NoError(t, loginErr)
+			claims, verifyErr := tokens.Verify(token.Token)
+			require.NoError(t, verifyErr)
+			assert.Equal(t, tt.member.StaffID, claims.Subject)
+		})
+	}
+}
+
+func TestStaffService_LoginRehashesLegacyPassword(t *testing.T) {
+	mockRepo := new(MockStaffRepository)
+	staffService := service.NewStaffService(mockRepo, newStaffTokens())
+	// SHA-1 of "12345", as stored for the sample dvdrental staff.
+	mockRepo.On("GetStaffCredentials", "mike").
+		Return(&models.Staff{StaffID: 1, Active: true}, "8cb2237d0679ca88db6464eac60da96345513964", nil)
+	var storedHash string
+	mockRepo.On("UpdateStaffPassword", 1, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { storedHash = args.String(1) }).
+		Return(nil)
+
+	_, err := staffService.Login(context.Background(), models.StaffLoginRequest{Username: "mike", Password: "12345"})
+
+	require.NoError(t, err)
+	assert.False(t, auth.NeedsRehash(storedHash))
+	assert.True(t, auth.CheckPassword(storedHash, "12345"))
+}
+
+type stubTwoFactorVerifier struct {
+	enabled bool
+	err     error
+}
+
+func (v stubTwoFactorVerifier) VerifyLogin(_ context.Context, _ int, _ string) (bool, error) {
+	return v.enabled, v.err
+}
+
+func TestStaffService_LoginWithTwoFactor(t *testing.T) {
+	hash, err := auth.HashPassword("s3cret-pass")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		verifier      stubTwoFactorVerifier
+		expectedError error
+	}{
+		{name: "not enrolled"},
+		{name: "code accepted", verifier: stubTwoFactorVerifier{enabled: true}},
+		{name: "code rejected", verifier: stubTwoFactorVerifier{enabled: true, err: service.ErrInvalidTwoFactorCode},
+			expectedError: service.ErrInvalidTwoFactorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockStaffRepository)
+			tokens := newStaffTokens()
+			staffService := service.NewStaffService(mockRepo, tokens, service.WithStaffTwoFactor(tt.verifier))
+			mockRepo.On("GetStaffCredentials", "mike").Return(&models.Staff{StaffID: 1, Active: true}, hash, nil)
+
+			token, loginErr := staffService.Login(context.Background(),
+				models.StaffLoginRequest{Username: "mike", Password: "s3cret-pass", OTPCode: "123456"})
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, loginErr, tt.expectedError)
+				assert.Nil(t, token)
+				return
+			}
+			require.NoError(t, loginErr)
+			claims, verifyErr := tokens.Verify(token.Token)
+			require.NoError(t, verifyErr)
+			assert.Equal(t, tt.verifier.enabled, claims.TwoFactor)
+		})
+	}
+}
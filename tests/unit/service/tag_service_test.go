@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/cache"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) TagFilm(_ context.Context, filmID int, tag string) ([]string, error) {
+	args := m.Called(filmID, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTagRepository) UntagFilm(_ context.Context, filmID int, tag string) ([]string, error) {
+	args := m.Called(filmID, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTagRepository) ListTags(_ context.Context) ([]models.TagCount, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TagCount), args.Error(1)
+}
+
+func TestTagService_TagFilmNormalizesAndEvictsFilm(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	invalidations := &recordingInvalidations{}
+	tagService := service.NewTagService(mockRepo, invalidations)
+	mockRepo.On("TagFilm", 1, "cult classic").Return([]string{"cult classic", "sci-fi"}, nil)
+
+	filmTags, err := tagService.TagFilm(context.Background(), 1, "  Cult   CLASSIC ")
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.FilmTags{FilmID: 1, Tags: []string{"cult classic", "sci-fi"}}, filmTags)
+	assert.Equal(t, []cache.Event{cache.FilmChanged(1)}, invalidations.events)
+}
+
+func TestTagService_TagFilmRejectsBlankTag(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	invalidations := &recordingInvalidations{}
+	tagService := service.NewTagService(mockRepo, invalidations)
+
+	_, err := tagService.TagFilm(context.Background(), 1, " \t ")
+
+	require.ErrorIs(t, err, service.ErrInvalidTag)
+	mockRepo.AssertNotCalled(t, "TagFilm", mock.Anything, mock.Anything)
+	assert.Empty(t, invalidations.events)
+}
+
+func TestTagService_UntagFilm(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	invalidations := &recordingInvalidations{}
+	tagService := service.NewTagService(mockRepo, invalidations)
+	mockRepo.On("UntagFilm", 1, "noir").Return([]string{}, nil)
+	mockRepo.On("UntagFilm", 2, "noir").Return(nil, repository.ErrFilmTagNotFound)
+
+	filmTags, err := tagService.UntagFilm(context.Background(), 1, "Noir")
+	require.NoError(t, err)
+	assert.Empty(t, filmTags.Tags)
+
+	_, err = tagService.UntagFilm(context.Background(), 2, "noir")
+	require.ErrorIs(t, err, repository.ErrFilmTagNotFound)
+	assert.Equal(t, []cache.Event{cache.FilmChanged(1)}, invalidations.events)
+}
@@ -0,0 +1,179 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/auth"
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/service"
+)
+
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) BeginEnrollment(
+	_ context.Context,
+	staffID int,
+	secret string,
+	codeHashes []string,
+) (string, error) {
+	args := m.Called(staffID, secret, codeHashes)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) GetTwoFactor(_ context.Context, staffID int) (*models.StaffTwoFactor, error) {
+	args := m.Called(staffID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StaffTwoFactor), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) UseTOTPStep(_ context.Context, staffID int, step int64) (bool, error) {
+	args := m.Called(staffID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) UseBackupCode(_ context.Context, staffID int, codeHash string) (bool, error) {
+	args := m.Called(staffID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+// currentTOTP returns the code for secret now and its time step.
+func currentTOTP(t *testing.T, secret string) (string, int64) {
+	t.Helper()
+	step := auth.TOTPStep(time.Now())
+	code, err := auth.TOTPCode(secret, step)
+	require.NoError(t, err)
+	return code, step
+}
+
+func TestTwoFactorService_EnrollTwoFactor(t *testing.T) {
+	mockRepo := new(MockTwoFactorRepository)
+	twoFactorService := service.NewTwoFactorService(mockRepo, "Mockbuster")
+	var storedSecret string
+	var storedHashes []string
+	mockRepo.On("BeginEnrollment", 1, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			storedSecret = args.String(1)
+			storedHashes = args.Get(2).([]string)
+		}).
+		Return("mike", nil)
+
+	enrollment, err := twoFactorService.EnrollTwoFactor(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, storedSecret, enrollment.Secret)
+	assert.Equal(t, auth.TOTPProvisioningURI("Mockbuster", "mike", storedSecret), enrollment.ProvisioningURI)
+	require.Len(t, storedHashes, len(enrollment.BackupCodes))
+	assert.Equal(t, auth.HashBackupCode(enrollment.BackupCodes[0]), storedHashes[0])
+}
+
+func TestTwoFactorService_EnrollTwoFactorAlreadyEnabled(t *testing.T) {
+	mockRepo := new(MockTwoFactorRepository)
+	twoFactorService := service.NewTwoFactorService(mockRepo, "Mockbuster")
+	mockRepo.On("BeginEnrollment", 1, mock.Anything, mock.Anything).Return("", repository.ErrTwoFactorEnabled)
+
+	enrollment, err := twoFactorService.EnrollTwoFactor(context.Background(), 1)
+
+	require.ErrorIs(t, err, repository.ErrTwoFactorEnabled)
+	assert.Nil(t, enrollment)
+}
+
+func TestTwoFactorService_ConfirmTwoFactor(t *testing.T) {
+	secret, err := auth.NewTOTPSecret()
+	require.NoError(t, err)
+	code, step := currentTOTP(t, secret)
+
+	tests := []struct {
+		name          string
+		factor        *models.StaffTwoFactor
+		code          string
+		expectedError error
+	}{
+		{name: "valid code", factor: &models.StaffTwoFactor{StaffID: 1, Secret: secret}, code: code},
+		{name: "wrong code", factor: &models.StaffTwoFactor{StaffID: 1, Secret: secret}, code: "000000x",
+			expectedError: service.ErrInvalidTwoFactorCode},
+		{name: "already enabled", factor: &models.StaffTwoFactor{StaffID: 1, Secret: secret, Enabled: true},
+			code: code, expectedError: repository.ErrTwoFactorEnabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTwoFactorRepository)
+			twoFactorService := service.NewTwoFactorService(mockRepo, "Mockbuster")
+			mockRepo.On("GetTwoFactor", 1).Return(tt.factor, nil)
+			mockRepo.On("UseTOTPStep", 1, step).Return(true, nil)
+
+			confirmErr := twoFactorService.ConfirmTwoFactor(context.Background(), 1, tt.code)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, confirmErr, tt.expectedError)
+				mockRepo.AssertNotCalled(t, "UseTOTPStep", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, confirmErr)
+			mockRepo.AssertCalled(t, "UseTOTPStep", 1, step)
+		})
+	}
+}
+
+func TestTwoFactorService_VerifyLogin(t *testing.T) {
+	secret, err := auth.NewTOTPSecret()
+	require.NoError(t, err)
+	code, step := currentTOTP(t, secret)
+	enabled := &models.StaffTwoFactor{StaffID: 1, Secret: secret, Enabled: true}
+
+	tests := []struct {
+		name          string
+		factor        *models.StaffTwoFactor
+		repoError     error
+		code          string
+		stepUnused    bool
+		backupUsed    bool
+		expectEnabled bool
+		expectedError error
+	}{
+		{name: "not enrolled", repoError: repository.ErrTwoFactorNotFound},
+		{name: "pending enrollment", factor: &models.StaffTwoFactor{StaffID: 1, Secret: secret}},
+		{name: "missing code", factor: enabled, expectEnabled: true,
+			expectedError: service.ErrTwoFactorRequired},
+		{name: "valid code", factor: enabled, code: code, stepUnused: true, expectEnabled: true},
+		{name: "replayed code", factor: enabled, code: code, expectEnabled: true,
+			expectedError: service.ErrInvalidTwoFactorCode},
+		{name: "backup code", factor: enabled, code: "abcde-12345", backupUsed: true, expectEnabled: true},
+		{name: "unknown backup code", factor: enabled, code: "abcde-12345", expectEnabled: true,
+			expectedError: service.ErrInvalidTwoFactorCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTwoFactorRepository)
+			twoFactorService := service.NewTwoFactorService(mockRepo, "Mockbuster")
+			if tt.factor != nil {
+				mockRepo.On("GetTwoFactor", 1).Return(tt.factor, nil)
+			} else {
+				mockRepo.On("GetTwoFactor", 1).Return(nil, tt.repoError)
+			}
+			mockRepo.On("UseTOTPStep", 1, step).Return(tt.stepUnused, nil)
+			mockRepo.On("UseBackupCode", 1, auth.HashBackupCode("abcde-12345")).Return(tt.backupUsed, nil)
+
+			isEnabled, verifyErr := twoFactorService.VerifyLogin(context.Background(), 1, tt.code)
+
+			assert.Equal(t, tt.expectEnabled, isEnabled)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, verifyErr, tt.expectedError)
+				return
+			}
+			require.NoError(t, verifyErr)
+		})
+	}
+}
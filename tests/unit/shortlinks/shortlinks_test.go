@@ -0,0 +1,34 @@
+package shortlinks_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/shortlinks"
+)
+
+func TestNewCode(t *testing.T) {
+	code, err := shortlinks.NewCode()
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^[0-9a-hjkmnp-tv-z]{8}$`, code)
+	other, err := shortlinks.NewCode()
+	require.NoError(t, err)
+	assert.NotEqual(t, code, other)
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "k3x9qm2a", shortlinks.Normalize(" K3X9qm2A "))
+}
+
+func TestTarget(t *testing.T) {
+	assert.Equal(t, "/api/v1/films/42", shortlinks.Target("/api/v1/films/{id}", 42))
+	assert.Equal(t, "https://mockbuster.example/films/42?utm_source=short",
+		shortlinks.Target("https://mockbuster.example/films/{id}?utm_source=short", 42))
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/f/k3x9qm2a", shortlinks.Path("k3x9qm2a"))
+}
@@ -0,0 +1,44 @@
+package sitemaps_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/sitemaps"
+)
+
+func TestMarshalURLSet(t *testing.T) {
+	urlSet := sitemaps.NewURLSet()
+	urlSet.URLs = append(urlSet.URLs,
+		sitemaps.URL{Loc: "http://localhost:8080/api/v1/films/1?a=1&b=2", LastMod: "2024-05-01T12:00:00Z"},
+		sitemaps.URL{Loc: "http://localhost:8080/api/v1/films/2"})
+
+	body, err := sitemaps.Marshal(urlSet)
+
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+
+		`<url><loc>http://localhost:8080/api/v1/films/1?a=1&amp;b=2</loc><lastmod>2024-05-01T12:00:00Z</lastmod></url>`+
+		`<url><loc>http://localhost:8080/api/v1/films/2</loc></url></urlset>`, string(body))
+}
+
+func TestMarshalIndex(t *testing.T) {
+	index := sitemaps.NewIndex()
+	index.Sitemaps = append(index.Sitemaps, sitemaps.Sitemap{Loc: "http://localhost:8080/sitemaps/films-1.xml"})
+
+	body, err := sitemaps.Marshal(index)
+
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+
+		`<sitemap><loc>http://localhost:8080/sitemaps/films-1.xml</loc></sitemap></sitemapindex>`, string(body))
+}
+
+func TestLastMod(t *testing.T) {
+	assert.Equal(t, "", sitemaps.LastMod(time.Time{}))
+	assert.Equal(t, "2024-05-01T16:00:00Z",
+		sitemaps.LastMod(time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("EDT", -4*60*60))))
+}
@@ -3,6 +3,7 @@ package util_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -18,6 +19,60 @@ func TestInitConfig(t *testing.T) {
 	assert.Equal(t, "postgres", config.DBUser)
 	assert.Equal(t, "postgres", config.DBPassword)
 	assert.Equal(t, "dvdrental", config.DBName)
+	assert.Equal(t, 500*time.Millisecond, config.DBSlowQueryThreshold)
+	assert.Equal(t, 10, config.DefaultPageSize)
+	assert.Equal(t, 100, config.MaxPageSize)
+	assert.True(t, config.CacheEnabled)
+	assert.Equal(t, 5*time.Minute, config.CacheTTL)
+	assert.Equal(t, 10000, config.CacheMaxEntries)
+	assert.Zero(t, config.CacheWarmPages)
+	assert.True(t, config.StaleFallbackEnabled)
+	assert.Equal(t, 24*time.Hour, config.StaleMaxAge)
+	assert.Equal(t, 1000, config.StaleMaxEntries)
+	assert.Equal(t, "public, max-age=60", config.CacheControlCatalog)
+	assert.Equal(t, "no-cache", config.CacheControlComments)
+	assert.Equal(t, "no-store", config.CacheControlAdmin)
+	assert.Empty(t, config.AdminAPIToken)
+	assert.Empty(t, config.StaffAuthSecret)
+	assert.Equal(t, 8*time.Hour, config.StaffTokenTTL)
+	assert.Empty(t, config.CustomerAuthSecret)
+	assert.Equal(t, 24*time.Hour, config.CustomerTokenTTL)
+	assert.Equal(t, "log", config.EmailBackend)
+	assert.Equal(t, 2, config.JobWorkers)
+	assert.Equal(t, 2*time.Second, config.JobPollInterval)
+	assert.True(t, config.SchedulerEnabled)
+	assert.Equal(t, 15*time.Minute, config.JobMarkOverdueInterval)
+	assert.Equal(t, time.Hour, config.JobDueRemindersInterval)
+	assert.Equal(t, 10*time.Minute, config.JobRefreshTrendingInterval)
+	assert.Equal(t, 24*time.Hour, config.JobPurgeWebhookDeliveriesInterval)
+	assert.Equal(t, 24*time.Hour, config.RentalReminderWindow)
+	assert.Equal(t, 30*24*time.Hour, config.TrendingWindow)
+	assert.InDelta(t, 1.00, config.LateFeePerDay, 0)
+	assert.Equal(t, "fake", config.PaymentProvider)
+	assert.Equal(t, "usd", config.PaymentCurrency)
+	assert.Equal(t, 10, config.LoyaltyPointsPerRental)
+	assert.Equal(t, 5, config.LoyaltyPointsPerComment)
+	assert.Equal(t, 100, config.LoyaltyPointsPerDollar)
+	assert.Equal(t, 10*time.Second, config.WebhookTimeout)
+	assert.Equal(t, 24*time.Hour, config.WebhookSecretGrace)
+	assert.Equal(t, 30*24*time.Hour, config.WebhookDeliveryRetention)
+	assert.Equal(t, "9090", config.GRPCPort)
+	assert.Empty(t, config.GRPCAPIToken)
+	assert.False(t, config.MaintenanceMode)
+	assert.Equal(t, "info", config.LogLevel)
+	assert.Equal(t, "text", config.LogFormat)
+	assert.Empty(t, config.SentryDSN)
+	assert.Equal(t, "development", config.SentryEnvironment)
+	assert.True(t, config.AccessLogSkipHealthChecks)
+	assert.Equal(t, 200, config.MaxInFlightRequests)
+	assert.Equal(t, 250*time.Millisecond, config.LoadShedQueueTimeout)
+	assert.Equal(t, 10*time.Second, config.HandlerTimeout)
+	assert.True(t, config.CompressionEnabled)
+	assert.Equal(t, []string{"br", "gzip"}, config.CompressionEncodings)
+	assert.Equal(t, 5, config.CompressionGzipLevel)
+	assert.Equal(t, 4, config.CompressionBrotliLevel)
+	assert.Equal(t, 1024, config.CompressionMinSize)
+	assert.Contains(t, config.CompressionSkipTypes, "image/")
 }
 
 func TestInitConfig_WithEnvironmentVariables(t *testing.T) {
@@ -58,3 +113,20 @@ func TestGetEnv(t *testing.T) {
 	value = util.GetEnv("NON_EXISTENT_VAR", "default-value")
 	assert.Equal(t, "default-value", value)
 }
+
+func TestGetEnvTypedHelpers(t *testing.T) {
+	t.Setenv("TEST_BOOL", "false")
+	t.Setenv("TEST_INT", "42")
+	t.Setenv("TEST_DURATION", "30s")
+	t.Setenv("TEST_BAD", "not-a-value")
+	t.Setenv("TEST_LIST", " gzip, ,br ")
+
+	assert.False(t, util.GetEnvBool("TEST_BOOL", true))
+	assert.True(t, util.GetEnvBool("TEST_BAD", true))
+	assert.Equal(t, 42, util.GetEnvInt("TEST_INT", 1))
+	assert.Equal(t, 1, util.GetEnvInt("TEST_BAD", 1))
+	assert.Equal(t, 30*time.Second, util.GetEnvDuration("TEST_DURATION", time.Minute))
+	assert.Equal(t, time.Minute, util.GetEnvDuration("TEST_BAD", time.Minute))
+	assert.Equal(t, []string{"gzip", "br"}, util.GetEnvList("TEST_LIST", nil))
+	assert.Equal(t, []string{"a"}, util.GetEnvList("TEST_UNSET_LIST", []string{"a"}))
+}
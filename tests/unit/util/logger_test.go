@@ -0,0 +1,60 @@
+package util_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/util"
+)
+
+func TestNewLogHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		level         string
+		format        string
+		expectedError string
+	}{
+		{name: "text info", level: "info", format: "text"},
+		{name: "json debug", level: "debug", format: "json"},
+		{name: "case insensitive", level: "WARN", format: "JSON"},
+		{name: "invalid level", level: "loud", format: "text", expectedError: "invalid log level"},
+		{name: "invalid format", level: "info", format: "xml", expectedError: "invalid log format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := util.NewLogHandler(&bytes.Buffer{}, tt.level, tt.format)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, handler)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, handler)
+			}
+		})
+	}
+}
+
+func TestNewLogHandler_FiltersBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := util.NewLogHandler(&buf, "warn", "json")
+	require.NoError(t, err)
+
+	assert.False(t, handler.Enabled(context.Background(), slog.LevelInfo))
+
+	logger := slog.New(handler)
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "shown", entry["msg"])
+}
@@ -0,0 +1,207 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rxbenefits/go-hw/internal/models"
+	"github.com/rxbenefits/go-hw/internal/repository"
+	"github.com/rxbenefits/go-hw/internal/webhooks"
+)
+
+type attempt struct {
+	status         string
+	responseStatus *int
+	lastError      *string
+}
+
+// fakeStore holds one delivery target and records attempts.
+type fakeStore struct {
+	target   *models.WebhookDeliveryTarget
+	created  []int64
+	attempts []attempt
+}
+
+func (s *fakeStore) CreateDeliveries(context.Context, string, []byte) ([]int64, error) {
+	return s.created, nil
+}
+
+func (s *fakeStore) GetDeliveryTarget(context.Context, int64) (*models.WebhookDeliveryTarget, error) {
+	if s.target == nil {
+		return nil, repository.ErrWebhookDeliveryNotFound
+	}
+	return s.target, nil
+}
+
+func (s *fakeStore) RecordDeliveryAttempt(
+	_ context.Context,
+	_ int64,
+	status string,
+	responseStatus *int,
+	lastError *string,
+) error {
+	s.attempts = append(s.attempts, attempt{status: status, responseStatus: responseStatus, lastError: lastError})
+	return nil
+}
+
+type recordingQueue struct {
+	kinds    []string
+	payloads []json.RawMessage
+}
+
+func (q *recordingQueue) Enqueue(kind string, payload any) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	q.kinds = append(q.kinds, kind)
+	q.payloads = append(q.payloads, encoded)
+	return nil
+}
+
+func newTarget(url string) *models.WebhookDeliveryTarget {
+	return &models.WebhookDeliveryTarget{
+		Delivery: models.WebhookDelivery{
+			ID:      42,
+			Event:   webhooks.EventCommentCreated,
+			Payload: json.RawMessage(`{"id":7}`),
+			Status:  models.WebhookDeliveryPending,
+		},
+		URL:    url,
+		Active: true,
+		Secret: "new-secret",
+	}
+}
+
+func TestDispatcher_PublishFansOutToDeliveries(t *testing.T) {
+	store := &fakeStore{created: []int64{1, 2}}
+	queue := &recordingQueue{}
+	dispatcher := webhooks.NewDispatcher(store, queue)
+
+	require.NoError(t, dispatcher.Publish(webhooks.EventCommentCreated, map[string]int{"id": 7}))
+	require.Equal(t, []string{webhooks.FanoutJobKind}, queue.kinds)
+
+	require.NoError(t, dispatcher.HandleFanoutJob(context.Background(), queue.payloads[0]))
+	assert.Equal(t, []string{webhooks.FanoutJobKind, webhooks.DeliverJobKind, webhooks.DeliverJobKind}, queue.kinds)
+	assert.JSONEq(t, `{"delivery_id":2}`, string(queue.payloads[2]))
+}
+
+func TestDispatcher_DeliversSignedRequest(t *testing.T) {
+	var headers http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	previous := "old-secret"
+	store := &fakeStore{target: newTarget(server.URL)}
+	store.target.PreviousSecret = &previous
+	dispatcher := webhooks.NewDispatcher(store, &recordingQueue{})
+
+	err := dispatcher.HandleDeliveryJob(context.Background(), json.RawMessage(`{"delivery_id":42}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, webhooks.EventCommentCreated, headers.Get(webhooks.EventHeader))
+	assert.Equal(t, "42", headers.Get(webhooks.DeliveryHeader))
+	timestamp, err := strconv.ParseInt(headers.Get(webhooks.TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t,
+		webhooks.Sign("new-secret", timestamp, body)+","+webhooks.Sign("old-secret", timestamp, body),
+		headers.Get(webhooks.SignatureHeader))
+	assert.JSONEq(t, `{"id":7}`, string(decodeData(t, body)))
+
+	require.Len(t, store.attempts, 1)
+	assert.Equal(t, models.WebhookDeliverySucceeded, store.attempts[0].status)
+	assert.Equal(t, http.StatusNoContent, *store.attempts[0].responseStatus)
+}
+
+func TestDispatcher_RecordsFailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	store := &fakeStore{target: newTarget(server.URL)}
+	dispatcher := webhooks.NewDispatcher(store, &recordingQueue{},
+		webhooks.WithHTTPClient(&http.Client{Timeout: time.Second}))
+
+	err := dispatcher.HandleDeliveryJob(context.Background(), json.RawMessage(`{"delivery_id":42}`))
+
+	require.Error(t, err)
+	require.Len(t, store.attempts, 1)
+	assert.Equal(t, models.WebhookDeliveryFailed, store.attempts[0].status)
+	assert.Equal(t, http.StatusBadGateway, *store.attempts[0].responseStatus)
+	assert.Contains(t, *store.attempts[0].lastError, "nope")
+}
+
+func TestDispatcher_SkipsWithoutRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		target           *models.WebhookDeliveryTarget
+		expectedAttempts int
+	}{
+		{name: "deleted delivery", target: nil, expectedAttempts: 0},
+		{
+			name: "already succeeded",
+			target: func() *models.WebhookDeliveryTarget {
+				target := newTarget("http://unused.invalid")
+				target.Delivery.Status = models.WebhookDeliverySucceeded
+				return target
+			}(),
+			expectedAttempts: 0,
+		},
+		{
+			name: "inactive subscription",
+			target: func() *models.WebhookDeliveryTarget {
+				target := newTarget("http://unused.invalid")
+				target.Active = false
+				return target
+			}(),
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{target: tt.target}
+			dispatcher := webhooks.NewDispatcher(store, &recordingQueue{})
+
+			err := dispatcher.HandleDeliveryJob(context.Background(), json.RawMessage(`{"delivery_id":42}`))
+
+			require.NoError(t, err)
+			assert.Len(t, store.attempts, tt.expectedAttempts)
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := webhooks.NewSecret()
+	require.NoError(t, err)
+	second, err := webhooks.NewSecret()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "whsec_"))
+	assert.NotEqual(t, first, second)
+}
+
+func decodeData(t *testing.T, body []byte) json.RawMessage {
+	t.Helper()
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &envelope))
+	return envelope.Data
+}